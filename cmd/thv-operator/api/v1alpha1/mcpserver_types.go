@@ -9,6 +9,10 @@ import (
 const (
 	// ConditionImageValidated indicates whether this image is fine to be used
 	ConditionImageValidated = "ImageValidated"
+
+	// ConditionNetworkPolicyReady indicates whether the NetworkPolicy enforcing the network
+	// permissions has been applied
+	ConditionNetworkPolicyReady = "NetworkPolicyReady"
 )
 
 const (
@@ -22,6 +26,20 @@ const (
 	ConditionReasonImageValidationSkipped = "ImageValidationSkipped"
 )
 
+const (
+	// ConditionReasonNetworkPolicyApplied indicates the NetworkPolicy enforces all network permissions
+	ConditionReasonNetworkPolicyApplied = "NetworkPolicyApplied"
+	// ConditionReasonNetworkPolicyHostNamesNotEnforced indicates the NetworkPolicy was applied, but the
+	// host name rules of the network permissions require an egress proxy
+	ConditionReasonNetworkPolicyHostNamesNotEnforced = "NetworkPolicyHostNamesNotEnforced"
+	// ConditionReasonNetworkPolicyHostNamesAdvisory indicates the NetworkPolicy was applied, but the
+	// host name rules of the network permissions are only advisory, as the egress proxy shares the
+	// network of the MCP server
+	ConditionReasonNetworkPolicyHostNamesAdvisory = "NetworkPolicyHostNamesAdvisory"
+	// ConditionReasonNetworkPolicyFailed indicates the NetworkPolicy could not be applied
+	ConditionReasonNetworkPolicyFailed = "NetworkPolicyFailed"
+)
+
 // MCPServerSpec defines the desired state of MCPServer
 type MCPServerSpec struct {
 	// Image is the container image for the MCP server
@@ -81,6 +99,10 @@ type MCPServerSpec struct {
 	// +optional
 	PermissionProfile *PermissionProfileRef `json:"permissionProfile,omitempty"`
 
+	// NetworkPolicy configures the enforcement of the network permissions of the permission profile
+	// +optional
+	NetworkPolicy *NetworkPolicyConfig `json:"networkPolicy,omitempty"`
+
 	// PodTemplateSpec defines the pod template to use for the MCP server
 	// This allows for customizing the pod configuration beyond what is provided by the other fields.
 	// Note that to modify the specific container the MCP server runs in, you must specify
@@ -104,6 +126,10 @@ type MCPServerSpec struct {
 	// +optional
 	Audit *AuditConfig `json:"audit,omitempty"`
 
+	// Usage defines usage accounting and quota configuration for the MCP server
+	// +optional
+	Usage *UsageConfig `json:"usage,omitempty"`
+
 	// ToolsFilter is the filter on tools applied to the MCP server
 	// Deprecated: Use ToolConfigRef instead
 	// +optional
@@ -281,6 +307,26 @@ type PermissionProfileRef struct {
 	Key string `json:"key,omitempty"`
 }
 
+// NetworkPolicyConfig defines how the network permissions of an MCP server are enforced
+type NetworkPolicyConfig struct {
+	// Enabled generates a NetworkPolicy from the network permissions of the permission profile.
+	// All traffic of the MCP server which is not allowed by the profile is denied, except for DNS
+	// lookups and traffic from pods in the same namespace.
+	// +kubebuilder:default=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// EgressProxy adds an egress proxy sidecar to the MCP server, which applies the host name rules
+	// of the network permissions that a NetworkPolicy cannot express. The sidecar shares the network
+	// of the MCP server, so the NetworkPolicy allows the MCP server to reach all addresses on the
+	// ports of the host name rules, and the host name rules are only advisory: an MCP server which
+	// ignores HTTP_PROXY and HTTPS_PROXY bypasses them. Without it, connections to hosts which are
+	// only allowed by name are denied by the NetworkPolicy.
+	// +kubebuilder:default=false
+	// +optional
+	EgressProxy bool `json:"egressProxy,omitempty"`
+}
+
 // PermissionProfileSpec defines the permissions for an MCP server
 type PermissionProfileSpec struct {
 	// Read is a list of paths that the MCP server can read from
@@ -502,6 +548,32 @@ type AuditConfig struct {
 	Enabled bool `json:"enabled,omitempty"`
 }
 
+// UsageConfig defines usage accounting and quota configuration for the MCP server.
+// Usage is kept in a ConfigMap in the MCPServer's namespace.
+type UsageConfig struct {
+	// Enabled controls whether the tool calls, bytes and time of each client identity are accounted
+	// +kubebuilder:default=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// PrincipalClaim is the JWT claim usage is accounted by, such as "sub" or a team claim
+	// +kubebuilder:default=sub
+	// +optional
+	PrincipalClaim string `json:"principalClaim,omitempty"`
+
+	// DailyToolCallQuota is the maximum number of tool calls per client identity per day
+	// Zero means unlimited
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DailyToolCallQuota int64 `json:"dailyToolCallQuota,omitempty"`
+
+	// MonthlyToolCallQuota is the maximum number of tool calls per client identity per month
+	// Zero means unlimited
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MonthlyToolCallQuota int64 `json:"monthlyToolCallQuota,omitempty"`
+}
+
 // TelemetryConfig defines observability configuration for the MCP server
 type TelemetryConfig struct {
 	// OpenTelemetry defines OpenTelemetry configuration
@@ -546,6 +618,10 @@ type OpenTelemetryConfig struct {
 	// Tracing defines OpenTelemetry tracing configuration
 	// +optional
 	Tracing *OpenTelemetryTracingConfig `json:"tracing,omitempty"`
+
+	// Logs defines OpenTelemetry logs configuration
+	// +optional
+	Logs *OpenTelemetryLogsConfig `json:"logs,omitempty"`
 }
 
 // PrometheusConfig defines Prometheus-specific configuration
@@ -575,6 +651,20 @@ type OpenTelemetryMetricsConfig struct {
 	// +kubebuilder:default=false
 	// +optional
 	Enabled bool `json:"enabled,omitempty"`
+
+	// Exemplars controls whether metrics record exemplars linking measurements,
+	// such as a slow request duration bucket, to sampled traces
+	// +kubebuilder:default=false
+	// +optional
+	Exemplars bool `json:"exemplars,omitempty"`
+}
+
+// OpenTelemetryLogsConfig defines OpenTelemetry logs configuration
+type OpenTelemetryLogsConfig struct {
+	// Enabled controls whether proxy logs and audit events are sent via OTLP
+	// +kubebuilder:default=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
 }
 
 // MCPServerStatus defines the observed state of MCPServer
@@ -455,6 +455,11 @@ func (in *MCPServerSpec) DeepCopyInto(out *MCPServerSpec) {
 		*out = new(PermissionProfileRef)
 		**out = **in
 	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicyConfig)
+		**out = **in
+	}
 	if in.PodTemplateSpec != nil {
 		in, out := &in.PodTemplateSpec, &out.PodTemplateSpec
 		*out = new(corev1.PodTemplateSpec)
@@ -480,6 +485,11 @@ func (in *MCPServerSpec) DeepCopyInto(out *MCPServerSpec) {
 		*out = new(AuditConfig)
 		**out = **in
 	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(UsageConfig)
+		**out = **in
+	}
 	if in.ToolsFilter != nil {
 		in, out := &in.ToolsFilter, &out.ToolsFilter
 		*out = make([]string, len(*in))
@@ -680,6 +690,21 @@ func (in *NetworkPermissions) DeepCopy() *NetworkPermissions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyConfig) DeepCopyInto(out *NetworkPolicyConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyConfig.
+func (in *NetworkPolicyConfig) DeepCopy() *NetworkPolicyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OIDCConfigRef) DeepCopyInto(out *OIDCConfigRef) {
 	*out = *in
@@ -728,6 +753,11 @@ func (in *OpenTelemetryConfig) DeepCopyInto(out *OpenTelemetryConfig) {
 		*out = new(OpenTelemetryTracingConfig)
 		**out = **in
 	}
+	if in.Logs != nil {
+		in, out := &in.Logs, &out.Logs
+		*out = new(OpenTelemetryLogsConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenTelemetryConfig.
@@ -740,6 +770,21 @@ func (in *OpenTelemetryConfig) DeepCopy() *OpenTelemetryConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenTelemetryLogsConfig) DeepCopyInto(out *OpenTelemetryLogsConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenTelemetryLogsConfig.
+func (in *OpenTelemetryLogsConfig) DeepCopy() *OpenTelemetryLogsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenTelemetryLogsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenTelemetryMetricsConfig) DeepCopyInto(out *OpenTelemetryMetricsConfig) {
 	*out = *in
@@ -1145,6 +1190,21 @@ func (in *ToolOverride) DeepCopy() *ToolOverride {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UsageConfig) DeepCopyInto(out *UsageConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UsageConfig.
+func (in *UsageConfig) DeepCopy() *UsageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(UsageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Volume) DeepCopyInto(out *Volume) {
 	*out = *in
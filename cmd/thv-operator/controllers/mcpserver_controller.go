@@ -17,6 +17,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -77,7 +78,7 @@ var defaultRBACRules = []rbacv1.PolicyRule{
 	{
 		APIGroups: []string{""},
 		Resources: []string{"configmaps"},
-		Verbs:     []string{"get", "list", "watch"},
+		Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
 	},
 }
 
@@ -154,6 +155,7 @@ func (r *MCPServerReconciler) detectPlatform(ctx context.Context) (kubernetes.Pl
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete;apply
 // +kubebuilder:rbac:groups="",resources=pods/attach,verbs=create;get
 // +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=create;delete;get;list;patch;update;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -267,6 +269,16 @@ func (r *MCPServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		}
 	}
 
+	// Ensure the NetworkPolicy enforcing the network permissions is up to date. Its condition
+	// is persisted with the status update below.
+	if err := r.ensureNetworkPolicy(ctx, mcpServer); err != nil {
+		ctxLogger.Error(err, "Failed to ensure NetworkPolicy")
+		if statusErr := r.Status().Update(ctx, mcpServer); statusErr != nil {
+			ctxLogger.Error(statusErr, "Failed to update MCPServer status after NetworkPolicy error")
+		}
+		return ctrl.Result{}, err
+	}
+
 	// Update the MCPServer status with the pod status
 	if err := r.updateMCPServerStatus(ctx, mcpServer); err != nil {
 		ctxLogger.Error(err, "Failed to update MCPServer status")
@@ -2076,6 +2088,18 @@ func (*MCPServerReconciler) generateOpenTelemetryArgs(m *mcpv1alpha1.MCPServer)
 		} else {
 			args = append(args, "--otel-metrics-enabled=false")
 		}
+		if otel.Metrics.Exemplars {
+			args = append(args, "--otel-exemplars-enabled=true")
+		}
+	}
+
+	// Handle logs configuration
+	if otel.Logs != nil {
+		if otel.Logs.Enabled {
+			args = append(args, "--otel-logs-enabled=true")
+		} else {
+			args = append(args, "--otel-logs-enabled=false")
+		}
 	}
 
 	return args
@@ -2192,6 +2216,7 @@ func (r *MCPServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&mcpv1alpha1.MCPServer{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
+		Owns(&networkingv1.NetworkPolicy{}).
 		Complete(r)
 }
 
@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+	"github.com/stacklok/toolhive/pkg/container/kubernetes"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+// ensureNetworkPolicy creates, updates or deletes the NetworkPolicy which enforces the network
+// permissions of the MCP server, and reports the result in the NetworkPolicyReady condition.
+// The condition is set on the MCPServer in memory and persisted with the next status update.
+func (r *MCPServerReconciler) ensureNetworkPolicy(ctx context.Context, m *mcpv1alpha1.MCPServer) error {
+	if m.Spec.NetworkPolicy == nil || !m.Spec.NetworkPolicy.Enabled {
+		meta.RemoveStatusCondition(&m.Status.Conditions, mcpv1alpha1.ConditionNetworkPolicyReady)
+		return r.deleteNetworkPolicy(ctx, m)
+	}
+
+	profile, err := r.resolvePermissionProfile(ctx, m)
+	if err != nil {
+		setNetworkPolicyCondition(m, metav1.ConditionFalse, mcpv1alpha1.ConditionReasonNetworkPolicyFailed, err.Error())
+		return err
+	}
+
+	egressProxy := m.Spec.NetworkPolicy.EgressProxy
+	desired, err := kubernetes.NetworkPolicyForWorkload(m.Name, m.Namespace, profile.Network, egressProxy)
+	if err != nil {
+		setNetworkPolicyCondition(m, metav1.ConditionFalse, mcpv1alpha1.ConditionReasonNetworkPolicyFailed, err.Error())
+		return fmt.Errorf("failed to generate NetworkPolicy: %w", err)
+	}
+	desired.Labels = labelsForMCPServer(m.Name)
+
+	if err := r.applyNetworkPolicy(ctx, m, desired); err != nil {
+		setNetworkPolicyCondition(m, metav1.ConditionFalse, mcpv1alpha1.ConditionReasonNetworkPolicyFailed, err.Error())
+		return err
+	}
+
+	if hostNames := kubernetes.HostNameRules(profile.Network); len(hostNames) > 0 {
+		if !egressProxy {
+			setNetworkPolicyCondition(m, metav1.ConditionTrue, mcpv1alpha1.ConditionReasonNetworkPolicyHostNamesNotEnforced,
+				fmt.Sprintf("NetworkPolicy %s applied, but the host name rules %s require an egress proxy; "+
+					"connections to these hosts are denied", desired.Name, strings.Join(hostNames, ", ")))
+			return nil
+		}
+		// The egress proxy sidecar shares the network of the MCP server, so the MCP server can
+		// reach every address the proxy can reach by not using the proxy
+		setNetworkPolicyCondition(m, metav1.ConditionTrue, mcpv1alpha1.ConditionReasonNetworkPolicyHostNamesAdvisory,
+			fmt.Sprintf("NetworkPolicy %s applied, but the host name rules %s are only applied by the egress proxy; "+
+				"the MCP server can bypass them by not using the proxy", desired.Name, strings.Join(hostNames, ", ")))
+		return nil
+	}
+	setNetworkPolicyCondition(m, metav1.ConditionTrue, mcpv1alpha1.ConditionReasonNetworkPolicyApplied,
+		fmt.Sprintf("NetworkPolicy %s enforces the network permissions", desired.Name))
+	return nil
+}
+
+// applyNetworkPolicy creates the NetworkPolicy, or updates it if its spec changed
+func (r *MCPServerReconciler) applyNetworkPolicy(
+	ctx context.Context,
+	m *mcpv1alpha1.MCPServer,
+	desired *networkingv1.NetworkPolicy,
+) error {
+	ctxLogger := log.FromContext(ctx)
+	if err := controllerutil.SetControllerReference(m, desired, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference on NetworkPolicy: %w", err)
+	}
+
+	current := &networkingv1.NetworkPolicy{}
+	err := r.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, current)
+	if errors.IsNotFound(err) {
+		ctxLogger.Info("Creating NetworkPolicy", "NetworkPolicy.Name", desired.Name)
+		if err := r.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed to create NetworkPolicy: %w", err)
+		}
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get NetworkPolicy: %w", err)
+	}
+
+	if reflect.DeepEqual(current.Spec, desired.Spec) && reflect.DeepEqual(current.Labels, desired.Labels) {
+		return nil
+	}
+	ctxLogger.Info("Updating NetworkPolicy", "NetworkPolicy.Name", desired.Name)
+	current.Spec = desired.Spec
+	current.Labels = desired.Labels
+	if err := r.Update(ctx, current); err != nil {
+		return fmt.Errorf("failed to update NetworkPolicy: %w", err)
+	}
+	return nil
+}
+
+// deleteNetworkPolicy deletes the NetworkPolicy of the MCP server, if any
+func (r *MCPServerReconciler) deleteNetworkPolicy(ctx context.Context, m *mcpv1alpha1.MCPServer) error {
+	policy := &networkingv1.NetworkPolicy{}
+	err := r.Get(ctx, types.NamespacedName{Name: kubernetes.NetworkPolicyName(m.Name), Namespace: m.Namespace}, policy)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get NetworkPolicy: %w", err)
+	}
+
+	// Only delete policies created for the MCP server
+	if !metav1.IsControlledBy(policy, m) {
+		return nil
+	}
+	log.FromContext(ctx).Info("Deleting NetworkPolicy", "NetworkPolicy.Name", policy.Name)
+	if err := r.Delete(ctx, policy); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete NetworkPolicy: %w", err)
+	}
+	return nil
+}
+
+// resolvePermissionProfile returns the permission profile the MCP server runs with. As in the
+// proxy runner, servers without a permission profile run with the builtin network profile.
+func (r *MCPServerReconciler) resolvePermissionProfile(
+	ctx context.Context,
+	m *mcpv1alpha1.MCPServer,
+) (*permissions.Profile, error) {
+	ref := m.Spec.PermissionProfile
+	if ref == nil {
+		return permissions.BuiltinNetworkProfile(), nil
+	}
+
+	switch ref.Type {
+	case mcpv1alpha1.PermissionProfileTypeBuiltin:
+		switch ref.Name {
+		case permissions.ProfileNone:
+			return permissions.BuiltinNoneProfile(), nil
+		case permissions.ProfileNetwork:
+			return permissions.BuiltinNetworkProfile(), nil
+		default:
+			return nil, fmt.Errorf("unknown builtin permission profile %q", ref.Name)
+		}
+	case mcpv1alpha1.PermissionProfileTypeConfigMap:
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: m.Namespace}, configMap); err != nil {
+			return nil, fmt.Errorf("failed to get permission profile ConfigMap %s: %w", ref.Name, err)
+		}
+		data, ok := configMap.Data[ref.Key]
+		if !ok {
+			return nil, fmt.Errorf("permission profile ConfigMap %s has no key %q", ref.Name, ref.Key)
+		}
+		var profile permissions.Profile
+		if err := json.Unmarshal([]byte(data), &profile); err != nil {
+			return nil, fmt.Errorf("failed to parse permission profile in ConfigMap %s: %w", ref.Name, err)
+		}
+		return &profile, nil
+	default:
+		return nil, fmt.Errorf("unknown permission profile type %q", ref.Type)
+	}
+}
+
+// setNetworkPolicyCondition sets the NetworkPolicyReady status condition
+func setNetworkPolicyCondition(m *mcpv1alpha1.MCPServer, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&m.Status.Conditions, metav1.Condition{
+		Type:    mcpv1alpha1.ConditionNetworkPolicyReady,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	mcpv1alpha1 "github.com/stacklok/toolhive/cmd/thv-operator/api/v1alpha1"
+)
+
+func newNetworkPolicyTestReconciler(objects ...client.Object) (*MCPServerReconciler, client.Client) {
+	testScheme := createTestScheme()
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(testScheme).
+		WithObjects(objects...).
+		Build()
+	return &MCPServerReconciler{Client: fakeClient, Scheme: testScheme}, fakeClient
+}
+
+func getNetworkPolicy(t *testing.T, c client.Client, name string) (*networkingv1.NetworkPolicy, error) {
+	t.Helper()
+	policy := &networkingv1.NetworkPolicy{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "default"}, policy)
+	return policy, err
+}
+
+func TestEnsureNetworkPolicy_BuiltinProfile(t *testing.T) {
+	t.Parallel()
+
+	mcpServer := createTestMCPServer("fetch", "default")
+	mcpServer.Spec.PermissionProfile = &mcpv1alpha1.PermissionProfileRef{
+		Type: mcpv1alpha1.PermissionProfileTypeBuiltin,
+		Name: "none",
+	}
+	mcpServer.Spec.NetworkPolicy = &mcpv1alpha1.NetworkPolicyConfig{Enabled: true}
+	reconciler, fakeClient := newNetworkPolicyTestReconciler(mcpServer)
+
+	require.NoError(t, reconciler.ensureNetworkPolicy(context.TODO(), mcpServer))
+
+	policy, err := getNetworkPolicy(t, fakeClient, "fetch-network-policy")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "fetch"}, policy.Spec.PodSelector.MatchLabels)
+	assert.True(t, metav1.IsControlledBy(policy, mcpServer))
+	// The none profile only allows DNS lookups
+	require.Len(t, policy.Spec.Egress, 1)
+	assert.Empty(t, policy.Spec.Egress[0].To)
+
+	condition := meta.FindStatusCondition(mcpServer.Status.Conditions, mcpv1alpha1.ConditionNetworkPolicyReady)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, mcpv1alpha1.ConditionReasonNetworkPolicyApplied, condition.Reason)
+
+	// Switching to the network profile updates the policy
+	mcpServer.Spec.PermissionProfile.Name = "network"
+	require.NoError(t, reconciler.ensureNetworkPolicy(context.TODO(), mcpServer))
+	policy, err = getNetworkPolicy(t, fakeClient, "fetch-network-policy")
+	require.NoError(t, err)
+	require.Len(t, policy.Spec.Egress, 2)
+
+	// Disabling the policy deletes it and removes the condition
+	mcpServer.Spec.NetworkPolicy.Enabled = false
+	require.NoError(t, reconciler.ensureNetworkPolicy(context.TODO(), mcpServer))
+	_, err = getNetworkPolicy(t, fakeClient, "fetch-network-policy")
+	assert.True(t, errors.IsNotFound(err))
+	assert.Nil(t, meta.FindStatusCondition(mcpServer.Status.Conditions, mcpv1alpha1.ConditionNetworkPolicyReady))
+}
+
+func TestEnsureNetworkPolicy_ConfigMapProfile(t *testing.T) {
+	t.Parallel()
+
+	profileConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "profiles", Namespace: "default"},
+		Data: map[string]string{
+			"github.json": `{"network": {"outbound": {"allow_host": ["api.github.com"], "allow_port": [443]}}}`,
+		},
+	}
+
+	tests := []struct {
+		name           string
+		egressProxy    bool
+		expectedReason string
+		expectedEgress int
+	}{
+		{
+			name:           "host names are not enforced without egress proxy",
+			egressProxy:    false,
+			expectedReason: mcpv1alpha1.ConditionReasonNetworkPolicyHostNamesNotEnforced,
+			expectedEgress: 1,
+		},
+		{
+			name:           "host names are advisory with egress proxy",
+			egressProxy:    true,
+			expectedReason: mcpv1alpha1.ConditionReasonNetworkPolicyHostNamesAdvisory,
+			expectedEgress: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mcpServer := createTestMCPServer("github", "default")
+			mcpServer.Spec.PermissionProfile = &mcpv1alpha1.PermissionProfileRef{
+				Type: mcpv1alpha1.PermissionProfileTypeConfigMap,
+				Name: "profiles",
+				Key:  "github.json",
+			}
+			mcpServer.Spec.NetworkPolicy = &mcpv1alpha1.NetworkPolicyConfig{Enabled: true, EgressProxy: tt.egressProxy}
+			reconciler, fakeClient := newNetworkPolicyTestReconciler(mcpServer, profileConfigMap.DeepCopy())
+
+			require.NoError(t, reconciler.ensureNetworkPolicy(context.TODO(), mcpServer))
+
+			policy, err := getNetworkPolicy(t, fakeClient, "github-network-policy")
+			require.NoError(t, err)
+			assert.Len(t, policy.Spec.Egress, tt.expectedEgress)
+
+			condition := meta.FindStatusCondition(mcpServer.Status.Conditions, mcpv1alpha1.ConditionNetworkPolicyReady)
+			require.NotNil(t, condition)
+			assert.Equal(t, tt.expectedReason, condition.Reason)
+			assert.Contains(t, condition.Message, "api.github.com")
+		})
+	}
+}
+
+func TestEnsureNetworkPolicy_MissingProfile(t *testing.T) {
+	t.Parallel()
+
+	mcpServer := createTestMCPServer("fetch", "default")
+	mcpServer.Spec.PermissionProfile = &mcpv1alpha1.PermissionProfileRef{
+		Type: mcpv1alpha1.PermissionProfileTypeConfigMap,
+		Name: "missing",
+		Key:  "profile.json",
+	}
+	mcpServer.Spec.NetworkPolicy = &mcpv1alpha1.NetworkPolicyConfig{Enabled: true}
+	reconciler, fakeClient := newNetworkPolicyTestReconciler(mcpServer)
+
+	assert.Error(t, reconciler.ensureNetworkPolicy(context.TODO(), mcpServer))
+
+	_, err := getNetworkPolicy(t, fakeClient, "fetch-network-policy")
+	assert.True(t, errors.IsNotFound(err))
+	condition := meta.FindStatusCondition(mcpServer.Status.Conditions, mcpv1alpha1.ConditionNetworkPolicyReady)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, mcpv1alpha1.ConditionReasonNetworkPolicyFailed, condition.Reason)
+}
@@ -93,6 +93,26 @@ func TestTelemetryArgs(t *testing.T) {
 				"--enable-prometheus-metrics-path",
 			},
 		},
+		{
+			name: "OpenTelemetry logs and metric exemplars enabled",
+			telemetryConfig: &mcpv1alpha1.TelemetryConfig{
+				OpenTelemetry: &mcpv1alpha1.OpenTelemetryConfig{
+					Metrics: &mcpv1alpha1.OpenTelemetryMetricsConfig{
+						Enabled:   true,
+						Exemplars: true,
+					},
+					Logs: &mcpv1alpha1.OpenTelemetryLogsConfig{
+						Enabled: true,
+					},
+				},
+			},
+			prometheusEnabled: false,
+			expectedArgs: []string{
+				"--otel-metrics-enabled=true",
+				"--otel-exemplars-enabled=true",
+				"--otel-logs-enabled=true",
+			},
+		},
 		{
 			name: "Prometheus only",
 			telemetryConfig: &mcpv1alpha1.TelemetryConfig{
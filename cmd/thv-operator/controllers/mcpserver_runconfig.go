@@ -25,6 +25,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/operator/accessors"
 	"github.com/stacklok/toolhive/pkg/runner"
 	transporttypes "github.com/stacklok/toolhive/pkg/transport/types"
+	"github.com/stacklok/toolhive/pkg/usage"
 )
 
 // defaultProxyHost is the default host for proxy binding
@@ -269,6 +270,8 @@ func (r *MCPServerReconciler) createRunConfigFromMCPServer(m *mcpv1alpha1.MCPSer
 		runner.WithVolumes(volumes),
 		// Secrets are NOT included in runconfig for ConfigMap mode - handled via k8s pod patch
 		runner.WithK8sPodPatch(k8sPodPatch),
+		// The egress proxy sidecar enforces the host name rules which a NetworkPolicy cannot express
+		runner.WithNetworkIsolation(m.Spec.NetworkPolicy != nil && m.Spec.NetworkPolicy.EgressProxy),
 	}
 
 	// Add tools override if present
@@ -312,6 +315,9 @@ func (r *MCPServerReconciler) createRunConfigFromMCPServer(m *mcpv1alpha1.MCPSer
 	// Add audit configuration if specified
 	addAuditConfigOptions(&options, m.Spec.Audit)
 
+	// Add usage accounting configuration if specified
+	addUsageConfigOptions(&options, m.Spec.Usage)
+
 	// Check for Vault Agent Injection and add env-file-dir if needed
 	vaultDetected := false
 
@@ -580,6 +586,8 @@ func addTelemetryConfigOptions(
 	var otelEnablePrometheusMetricsPath bool
 	var otelTracingEnabled bool
 	var otelMetricsEnabled bool
+	var otelLogsEnabled bool
+	var otelExemplarsEnabled bool
 	var otelServiceName string
 	var otelSamplingRate = 0.05 // Default sampling rate
 	var otelHeaders []string
@@ -616,6 +624,12 @@ func addTelemetryConfigOptions(
 		// Handle metrics configuration
 		if otel.Metrics != nil {
 			otelMetricsEnabled = otel.Metrics.Enabled
+			otelExemplarsEnabled = otel.Metrics.Exemplars
+		}
+
+		// Handle logs configuration
+		if otel.Logs != nil {
+			otelLogsEnabled = otel.Logs.Enabled
 		}
 	}
 
@@ -635,6 +649,8 @@ func addTelemetryConfigOptions(
 		otelHeaders,
 		otelInsecure,
 		otelEnvironmentVariables,
+		otelLogsEnabled,
+		otelExemplarsEnabled,
 	))
 }
 
@@ -774,3 +790,19 @@ func addAuditConfigOptions(
 	// Add audit config to options with default config (no custom config path for now)
 	*options = append(*options, runner.WithAuditEnabled(auditConfig.Enabled, ""))
 }
+
+// addUsageConfigOptions adds usage accounting configuration options to the builder options
+func addUsageConfigOptions(
+	options *[]runner.RunConfigBuilderOption,
+	usageConfig *mcpv1alpha1.UsageConfig,
+) {
+	if usageConfig == nil || !usageConfig.Enabled {
+		return
+	}
+
+	config := usage.NewConfigWithQuotas(usageConfig.DailyToolCallQuota, usageConfig.MonthlyToolCallQuota)
+	if usageConfig.PrincipalClaim != "" {
+		config.PrincipalClaim = usageConfig.PrincipalClaim
+	}
+	*options = append(*options, runner.WithUsageConfig(config))
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/authz"
 	"github.com/stacklok/toolhive/pkg/runner"
 	transporttypes "github.com/stacklok/toolhive/pkg/transport/types"
+	"github.com/stacklok/toolhive/pkg/usage"
 )
 
 const (
@@ -79,6 +80,25 @@ func TestCreateRunConfigFromMCPServer(t *testing.T) {
 				assert.Equal(t, 8080, config.Port)
 			},
 		},
+		{
+			name: "with egress proxy",
+			mcpServer: &mcpv1alpha1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "egress-server",
+					Namespace: "test-ns",
+				},
+				Spec: mcpv1alpha1.MCPServerSpec{
+					Image:         testImage,
+					Transport:     stdioTransport,
+					Port:          8080,
+					NetworkPolicy: &mcpv1alpha1.NetworkPolicyConfig{Enabled: true, EgressProxy: true},
+				},
+			},
+			//nolint:thelper // We want to see the error at the specific line
+			expected: func(t *testing.T, config *runner.RunConfig) {
+				assert.True(t, config.IsolateNetwork)
+			},
+		},
 		{
 			name: "with environment variables",
 			mcpServer: &mcpv1alpha1.MCPServer{
@@ -312,6 +332,10 @@ func TestCreateRunConfigFromMCPServer(t *testing.T) {
 								SamplingRate: "0.25",
 							},
 							Metrics: &mcpv1alpha1.OpenTelemetryMetricsConfig{
+								Enabled:   true,
+								Exemplars: true,
+							},
+							Logs: &mcpv1alpha1.OpenTelemetryLogsConfig{
 								Enabled: true,
 							},
 						},
@@ -334,6 +358,8 @@ func TestCreateRunConfigFromMCPServer(t *testing.T) {
 				assert.True(t, config.TelemetryConfig.Insecure)
 				assert.True(t, config.TelemetryConfig.TracingEnabled)
 				assert.True(t, config.TelemetryConfig.MetricsEnabled)
+				assert.True(t, config.TelemetryConfig.ExemplarsEnabled)
+				assert.True(t, config.TelemetryConfig.LogsEnabled)
 				assert.Equal(t, 0.25, config.TelemetryConfig.SamplingRate)
 				assert.Equal(t, map[string]string{"Authorization": "Bearer token123", "X-API-Key": "abc"}, config.TelemetryConfig.Headers)
 
@@ -600,6 +626,53 @@ func TestCreateRunConfigFromMCPServer(t *testing.T) {
 				assert.Nil(t, config.AuditConfig)
 			},
 		},
+		{
+			name: "with usage accounting and quotas",
+			mcpServer: &mcpv1alpha1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "usage-server",
+					Namespace: "test-ns",
+				},
+				Spec: mcpv1alpha1.MCPServerSpec{
+					Image:     testImage,
+					Transport: stdioTransport,
+					Port:      8080,
+					Usage: &mcpv1alpha1.UsageConfig{
+						Enabled:              true,
+						PrincipalClaim:       "team",
+						MonthlyToolCallQuota: 1000,
+					},
+				},
+			},
+			//nolint:thelper // We want to see the error at the specific line
+			expected: func(t *testing.T, config *runner.RunConfig) {
+				assert.Equal(t, "usage-server", config.Name)
+				require.NotNil(t, config.UsageConfig)
+				assert.Equal(t, "team", config.UsageConfig.PrincipalClaim)
+				assert.Equal(t, []usage.Quota{{Period: usage.PeriodMonthly, MaxToolCalls: 1000}}, config.UsageConfig.Quotas)
+			},
+		},
+		{
+			name: "with usage accounting disabled",
+			mcpServer: &mcpv1alpha1.MCPServer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "usage-disabled-server",
+					Namespace: "test-ns",
+				},
+				Spec: mcpv1alpha1.MCPServerSpec{
+					Image:     testImage,
+					Transport: stdioTransport,
+					Port:      8080,
+					Usage: &mcpv1alpha1.UsageConfig{
+						DailyToolCallQuota: 10,
+					},
+				},
+			},
+			//nolint:thelper // We want to see the error at the specific line
+			expected: func(t *testing.T, config *runner.RunConfig) {
+				assert.Nil(t, config.UsageConfig)
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -77,6 +77,8 @@ func runWithFlagsBasedConfig(
 			runFlags.runOtelHeaders,
 			runFlags.runOtelInsecure,
 			[]string{}, // CLI flags don't include telemetry environment variables, pass empty slice
+			runFlags.runOtelLogsEnabled,
+			runFlags.runOtelExemplarsEnabled,
 		),
 	}
 
@@ -80,6 +80,8 @@ type proxyRunFlags struct {
 	runOtelHeaders              []string
 	runOtelTracingEnabled       bool
 	runOtelMetricsEnabled       bool
+	runOtelLogsEnabled          bool
+	runOtelExemplarsEnabled     bool
 	runOtelInsecure             bool
 	runOtelTracingSamplingRate  float64
 	enablePrometheusMetricsPath bool
@@ -214,6 +216,10 @@ func addRunFlags(runCmd *cobra.Command, runFlags *proxyRunFlags) {
 		"Enable distributed tracing (when OTLP endpoint is configured)")
 	runCmd.Flags().BoolVar(&runFlags.runOtelMetricsEnabled, "otel-metrics-enabled", false,
 		"Enable OTLP metrics export (when OTLP endpoint is configured)")
+	runCmd.Flags().BoolVar(&runFlags.runOtelLogsEnabled, "otel-logs-enabled", false,
+		"Enable OTLP export of logs and audit events (when OTLP endpoint is configured)")
+	runCmd.Flags().BoolVar(&runFlags.runOtelExemplarsEnabled, "otel-exemplars-enabled", false,
+		"Attach exemplars linking metrics to sampled traces")
 	runCmd.Flags().Float64Var(&runFlags.runOtelTracingSamplingRate, "otel-tracing-sampling-rate", 0.0,
 		"OpenTelemetry trace sampling rate (0.0-1.0)")
 	runCmd.Flags().BoolVar(&runFlags.enablePrometheusMetricsPath, "enable-prometheus-metrics-path", false,
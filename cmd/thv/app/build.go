@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/container/images"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/runner"
+	"github.com/stacklok/toolhive/pkg/runner/pin"
 )
 
 var buildCmd = &cobra.Command{
@@ -22,19 +24,51 @@ ToolHive supports building containers from protocol schemes:
 	$ thv build npx://package-name
 	$ thv build go://package-name
 	$ thv build go://./local-path
+	$ thv build pipx://package-name
+	$ thv build cargo://crate-name
+	$ thv build dotnet://Tool.Name
+	$ thv build git://github.com/org/repo@ref#subdir
 
 Automatically generates a container that can run the specified package
-using either uvx (Python with uv package manager), npx (Node.js),
-or go (Golang). For Go, you can also specify local paths starting
-with './' or '../' to build local Go projects.
+using uvx (Python with uv package manager), npx (Node.js), go (Golang),
+pipx (Python), cargo (Rust) or dotnet (.NET tools). For Go, you can also
+specify local paths starting with './' or '../' to build local Go projects.
+
+Git sources are cloned at the given branch, tag or commit, and the
+subdirectory after '#' is built according to the package.json,
+pyproject.toml, go.mod or Cargo.toml it contains.
+
+Packages are pinned to an exact version and content hash (npm integrity,
+PyPI sha256, Go module sum, crates.io checksum or NuGet sha512, and the
+commit for git sources). Images are labeled and tagged with their pin, and
+an existing image with the same pin is reused instead of being rebuilt.
+
+Use --lock to record the pin in a lockfile (toolhive.lock unless --lock-file
+is set). Pass the lockfile with --lock-file to 'thv build' and 'thv run' to
+build the locked versions and fail if a package index reports a different
+hash, so teams get identical builds. Commit the lockfile next to your
+configuration to share it.
 
 The container will be built and tagged locally, ready to be used with 'thv run'
 or other container tools. The built image name will be displayed upon successful completion.
 
+Use --platform to build the image for other platforms than the host, such as
+linux/arm64 and linux/amd64, which requires emulation with QEMU when the
+platforms differ from the host. The images of the other platforms are tagged
+with the platform as suffix, for example my-server:1.0-linux-arm64. With
+--oci-layout, the images of all platforms are written as a single
+multi-platform image to an OCI layout tarball; with --push, they are pushed
+as a multi-platform image to the registry of the --tag name.
+
 Examples:
 	$ thv build uvx://mcp-server-git
 	$ thv build --tag my-custom-name:latest npx://@modelcontextprotocol/server-filesystem
-	$ thv build go://./my-local-server`,
+	$ thv build go://./my-local-server
+	$ thv build --lock npx://@modelcontextprotocol/server-filesystem
+	$ thv build --lock-file toolhive.lock npx://@modelcontextprotocol/server-filesystem
+	$ thv build --platform linux/amd64,linux/arm64 --oci-layout fetch.tar uvx://mcp-server-fetch
+	$ thv build --platform linux/amd64 --platform linux/arm64 \
+		--tag ghcr.io/my-org/fetch:0.6.2 --push uvx://mcp-server-fetch`,
 	Args: cobra.ExactArgs(1),
 	RunE: buildCmdFunc,
 }
@@ -43,9 +77,14 @@ var buildFlags BuildFlags
 
 // BuildFlags holds the configuration for building MCP server containers
 type BuildFlags struct {
-	Tag    string
-	Output string
-	DryRun bool
+	Tag       string
+	Output    string
+	DryRun    bool
+	Lock      bool
+	LockFile  string
+	Platforms []string
+	OCILayout string
+	Push      bool
 }
 
 func init() {
@@ -58,6 +97,15 @@ func AddBuildFlags(cmd *cobra.Command, config *BuildFlags) {
 	cmd.Flags().StringVarP(&config.Tag, "tag", "t", "", "Name and optionally a tag in the 'name:tag' format for the built image")
 	cmd.Flags().StringVarP(&config.Output, "output", "o", "", "Write the Dockerfile to the specified file instead of building")
 	cmd.Flags().BoolVar(&config.DryRun, "dry-run", false, "Generate Dockerfile without building (stdout output unless -o is set)")
+	cmd.Flags().BoolVar(&config.Lock, "lock", false, "Resolve the package again and record its pin in the lockfile")
+	cmd.Flags().StringVar(&config.LockFile, "lock-file", "",
+		fmt.Sprintf("Path of the lockfile that pins packages (%s with --lock when not set)", pin.DefaultLockFile))
+	cmd.Flags().StringSliceVar(&config.Platforms, "platform", nil,
+		"Target platform in the os/arch[/variant] format, can be repeated to build a multi-platform image")
+	cmd.Flags().StringVar(&config.OCILayout, "oci-layout", "",
+		"Write the image, with all its platforms, to the specified OCI layout tarball")
+	cmd.Flags().BoolVar(&config.Push, "push", false,
+		"Push the image, with all its platforms, to the registry of the --tag name")
 }
 
 func buildCmdFunc(cmd *cobra.Command, args []string) error {
@@ -66,15 +114,48 @@ func buildCmdFunc(cmd *cobra.Command, args []string) error {
 
 	// Validate that this is a protocol scheme
 	if !runner.IsImageProtocolScheme(protocolScheme) {
-		return fmt.Errorf("invalid protocol scheme: %s. Supported schemes are: uvx://, npx://, go://", protocolScheme)
+		return fmt.Errorf("invalid protocol scheme: %s. Supported schemes are: "+
+			"uvx://, npx://, go://, pipx://, cargo://, dotnet://, git://", protocolScheme)
+	}
+
+	multiPlatform := len(buildFlags.Platforms) > 0 || buildFlags.OCILayout != "" || buildFlags.Push
+	if multiPlatform && (buildFlags.DryRun || buildFlags.Output != "") {
+		return fmt.Errorf("--platform, --oci-layout and --push cannot be used with --dry-run or --output")
+	}
+	if buildFlags.Push && buildFlags.Tag == "" {
+		return fmt.Errorf("--push requires --tag with the name of the image in the registry")
 	}
 
 	// Create image manager (even for dry-run, we pass it but it won't be used)
-	imageManager := images.NewImageManager(ctx)
+	var imageManager images.ImageManager = images.NewImageManager(ctx)
+	var platformManager *images.PlatformImageManager
+	if multiPlatform {
+		platforms, err := images.ParsePlatforms(buildFlags.Platforms)
+		if err != nil {
+			return err
+		}
+		platformManager, err = images.NewPlatformImageManager(imageManager, platforms)
+		if err != nil {
+			return err
+		}
+		imageManager = platformManager
+	}
+
+	// The lockfile is only used when it is given, or written when the lock is updated
+	lockFile := buildFlags.LockFile
+	if lockFile == "" && buildFlags.Lock {
+		lockFile = pin.DefaultLockFile
+	}
+	opts := runner.BuildOptions{
+		ImageName:  buildFlags.Tag,
+		LockFile:   lockFile,
+		UpdateLock: buildFlags.Lock,
+	}
 
 	// If dry-run or output is specified, just generate the Dockerfile
 	if buildFlags.DryRun || buildFlags.Output != "" {
-		dockerfileContent, err := runner.BuildFromProtocolSchemeWithName(ctx, imageManager, protocolScheme, "", buildFlags.Tag, true)
+		opts.DryRun = true
+		dockerfileContent, err := runner.BuildFromProtocolScheme(ctx, imageManager, protocolScheme, opts)
 		if err != nil {
 			return fmt.Errorf("failed to generate Dockerfile for %s: %v", protocolScheme, err)
 		}
@@ -96,14 +177,35 @@ func buildCmdFunc(cmd *cobra.Command, args []string) error {
 	logger.Infof("Building container for protocol scheme: %s", protocolScheme)
 
 	// Build the image using the new protocol handler with custom name
-	imageName, err := runner.BuildFromProtocolSchemeWithName(ctx, imageManager, protocolScheme, "", buildFlags.Tag, false)
+	imageName, err := runner.BuildFromProtocolScheme(ctx, imageManager, protocolScheme, opts)
 	if err != nil {
 		return fmt.Errorf("failed to build container for %s: %v", protocolScheme, err)
 	}
 
 	logger.Infof("Successfully built container image: %s", imageName)
 	fmt.Printf("Container built successfully: %s\n", imageName)
+
+	if platformManager != nil {
+		return exportPlatformImages(ctx, platformManager, imageName)
+	}
 	fmt.Printf("You can now run it with: thv run %s\n", imageName)
 
 	return nil
 }
+
+// exportPlatformImages writes the images of all platforms to an OCI layout tarball, or pushes them to a registry
+func exportPlatformImages(ctx context.Context, platformManager *images.PlatformImageManager, imageName string) error {
+	if buildFlags.OCILayout != "" {
+		if err := platformManager.WriteOCILayout(ctx, imageName, buildFlags.OCILayout); err != nil {
+			return err
+		}
+		fmt.Printf("OCI layout written to: %s\n", buildFlags.OCILayout)
+	}
+	if buildFlags.Push {
+		if err := platformManager.Push(ctx, imageName); err != nil {
+			return err
+		}
+		fmt.Printf("Image pushed to: %s\n", imageName)
+	}
+	return nil
+}
@@ -51,8 +51,11 @@ func NewRootCmd(enableUpdates bool) *cobra.Command {
 	rootCmd.AddCommand(newExportCmd())
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(logsCommand())
+	rootCmd.AddCommand(newUsageCmd())
 	rootCmd.AddCommand(newSecretCommand())
 	rootCmd.AddCommand(inspectorCommand())
+	rootCmd.AddCommand(newInspectCommand())
+	rootCmd.AddCommand(newImageCommand())
 	rootCmd.AddCommand(newMCPCommand())
 	rootCmd.AddCommand(groupCmd)
 
@@ -44,7 +44,7 @@ func IsOIDCEnabled(cmd *cobra.Command) bool {
 
 // SetSecretsProvider sets the secrets provider type in the configuration.
 // It validates the input, tests the provider functionality, and updates the configuration.
-// Choices are `encrypted`, `1password`, and `none`.
+// Choices are `encrypted`, `1password`, `vault`, `none`, and installed secrets provider plugins.
 func SetSecretsProvider(provider secrets.ProviderType) error {
 	// Validate input
 	if provider == "" {
@@ -58,11 +58,15 @@ func SetSecretsProvider(provider secrets.ProviderType) error {
 	case secrets.OnePasswordType:
 	case secrets.NoneType:
 	case secrets.EnvironmentType:
+	case secrets.VaultType:
 		// Valid provider type
 	default:
-		return fmt.Errorf("invalid secrets provider type: %s (valid types: %s, %s, %s, %s)",
-			provider, string(secrets.EncryptedType), string(secrets.OnePasswordType),
-			string(secrets.NoneType), string(secrets.EnvironmentType))
+		if !secrets.IsPluginProviderType(provider) {
+			return fmt.Errorf("invalid secrets provider type: %s (valid types: %s, %s, %s, %s, %s, or a plugin installed as %s<name>)",
+				provider, string(secrets.EncryptedType), string(secrets.OnePasswordType),
+				string(secrets.NoneType), string(secrets.EnvironmentType), string(secrets.VaultType),
+				secrets.PluginExecutablePrefix)
+		}
 	}
 
 	// Validate that the provider can be created and works correctly
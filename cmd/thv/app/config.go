@@ -9,6 +9,7 @@ import (
 
 	"github.com/stacklok/toolhive/pkg/certs"
 	"github.com/stacklok/toolhive/pkg/config"
+	"github.com/stacklok/toolhive/pkg/container/verifier"
 )
 
 var configCmd = &cobra.Command{
@@ -71,6 +72,55 @@ var unsetRegistryCmd = &cobra.Command{
 	RunE:  unsetRegistryCmdFunc,
 }
 
+var setVerificationPolicyCmd = &cobra.Command{
+	Use:   "set-verification-policy <path>",
+	Short: "Set the image verification policy",
+	Long: `Set the path of the image verification policy file, which adds trusted signers and
+requirements for images to the provenance information of the registry.
+
+Each rule of the policy applies to the images whose repository matches its pattern, where
+* matches any characters except / and ** matches any characters. The first matching rule
+applies. Images must be signed by one of the trusted identities (keyless signatures) or
+public keys (cosign keys) of the rule, and have the attestations it requires.
+
+With require_verified, 'thv run' refuses images that cannot be verified, even with
+--image-verification=warn. Images without a rule or provenance information in the registry
+are refused, since there are no trusted signers to verify them against; add a rule for **
+to trust signers for all images. Images built from protocol schemes such as uvx:// are built
+locally, and not verified.
+
+Example policy:
+  require_verified: true
+  images:
+    - image: ghcr.io/stacklok/dockyard/**
+      identities:
+        - issuer: https://token.actions.githubusercontent.com
+          subject_regexp: ^https://github.com/stacklok/dockyard/
+      attestations: [https://slsa.dev/provenance/v1]
+      slsa_level: 2
+    - image: registry.example.com/mcp/*
+      public_keys: [cosign.pub]
+
+Example:
+  thv config set-verification-policy /path/to/verification-policy.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: setVerificationPolicyCmdFunc,
+}
+
+var getVerificationPolicyCmd = &cobra.Command{
+	Use:   "get-verification-policy",
+	Short: "Get the currently configured image verification policy",
+	Long:  "Display the path of the image verification policy file that is currently configured.",
+	RunE:  getVerificationPolicyCmdFunc,
+}
+
+var unsetVerificationPolicyCmd = &cobra.Command{
+	Use:   "unset-verification-policy",
+	Short: "Remove the configured image verification policy",
+	Long:  "Remove the image verification policy, reverting to verifying images against the registry only.",
+	RunE:  unsetVerificationPolicyCmdFunc,
+}
+
 var (
 	allowPrivateRegistryIp bool
 )
@@ -93,6 +143,9 @@ func init() {
 	)
 	configCmd.AddCommand(getRegistryCmd)
 	configCmd.AddCommand(unsetRegistryCmd)
+	configCmd.AddCommand(setVerificationPolicyCmd)
+	configCmd.AddCommand(getVerificationPolicyCmd)
+	configCmd.AddCommand(unsetVerificationPolicyCmd)
 
 	// Add OTEL parent command to config
 	configCmd.AddCommand(OtelCmd)
@@ -239,3 +292,66 @@ func unsetRegistryCmdFunc(_ *cobra.Command, _ []string) error {
 	fmt.Println("Will use built-in registry.")
 	return nil
 }
+
+func setVerificationPolicyCmdFunc(_ *cobra.Command, args []string) error {
+	policyPath, err := filepath.Abs(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid verification policy path: %w", err)
+	}
+
+	// Validate the policy before it is used by 'thv run'
+	policy, err := verifier.LoadPolicy(policyPath)
+	if err != nil {
+		return err
+	}
+
+	err = config.UpdateConfig(func(c *config.Config) {
+		c.VerificationPolicyPath = policyPath
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update configuration: %w", err)
+	}
+
+	fmt.Printf("Successfully set verification policy: %s (%d rules)\n", policyPath, len(policy.Images))
+	if policy.RequireVerified {
+		fmt.Println("Images that cannot be verified will be refused.")
+	}
+	return nil
+}
+
+func getVerificationPolicyCmdFunc(_ *cobra.Command, _ []string) error {
+	cfg := config.NewDefaultProvider().GetConfig()
+
+	if cfg.VerificationPolicyPath == "" {
+		fmt.Println("No verification policy is currently configured.")
+		return nil
+	}
+
+	fmt.Printf("Current verification policy: %s\n", cfg.VerificationPolicyPath)
+
+	// Check if the policy is still valid
+	if _, err := verifier.LoadPolicy(cfg.VerificationPolicyPath); err != nil {
+		fmt.Printf("Warning: The configured verification policy cannot be loaded: %v\n", err)
+	}
+
+	return nil
+}
+
+func unsetVerificationPolicyCmdFunc(_ *cobra.Command, _ []string) error {
+	cfg := config.NewDefaultProvider().GetConfig()
+
+	if cfg.VerificationPolicyPath == "" {
+		fmt.Println("No verification policy is currently configured.")
+		return nil
+	}
+
+	err := config.UpdateConfig(func(c *config.Config) {
+		c.VerificationPolicyPath = ""
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update configuration: %w", err)
+	}
+
+	fmt.Println("Successfully removed verification policy configuration.")
+	return nil
+}
@@ -0,0 +1,220 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+
+	"github.com/stacklok/toolhive/pkg/container/images"
+	"github.com/stacklok/toolhive/pkg/runner"
+)
+
+var (
+	imageListFormat     string
+	imagePruneUnused    bool
+	imagePruneOlderThan string
+)
+
+func newImageCommand() *cobra.Command {
+	imageCmd := &cobra.Command{
+		Use:   "image",
+		Short: "Manage the images built or pulled by ToolHive",
+		Long: `Manage the local images that ToolHive built from protocol schemes or pulled from registries.
+
+Images built by ToolHive carry the toolhive-image label, and the images that
+ToolHive pulls are recorded in its state directory. Other local images are
+never listed or removed. The images of saved workloads are never pruned.
+
+ToolHive can prune images automatically after it builds or pulls an image,
+with a prune policy in its configuration:
+
+	image_prune:
+	  enabled: true
+	  unused: true          # prune all unused images, not only the untagged ones
+	  max_age_days: 30      # only prune images built or pulled more than 30 days ago`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the images built or pulled by ToolHive",
+		Long: `List the local images that ToolHive built or pulled, newest first.
+The IN USE column shows whether a saved workload uses the image.`,
+		Args: cobra.NoArgs,
+		RunE: imageListCmdFunc,
+	}
+	listCmd.Flags().StringVar(&imageListFormat, "format", FormatText, "Output format (json or text)")
+
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove images built or pulled by ToolHive",
+		Long: `Remove the images that ToolHive built or pulled and which no saved workload uses.
+
+By default, only the untagged images are removed, which were replaced by newer
+builds or pulls of the same image. Use --unused to remove all the unused images,
+and --older-than to only remove the images built or pulled before a duration
+such as 72h or 30d.
+
+Examples:
+	$ thv image prune
+	$ thv image prune --unused --older-than 30d`,
+		Args: cobra.NoArgs,
+		RunE: imagePruneCmdFunc,
+	}
+	pruneCmd.Flags().BoolVar(&imagePruneUnused, "unused", false,
+		"Remove all the images that no saved workload uses, not only the untagged images")
+	pruneCmd.Flags().StringVar(&imagePruneOlderThan, "older-than", "",
+		"Only remove the images built or pulled longer ago than this duration (for example 72h or 30d)")
+
+	imageCmd.AddCommand(listCmd)
+	imageCmd.AddCommand(pruneCmd)
+	return imageCmd
+}
+
+func imageListCmdFunc(cmd *cobra.Command, _ []string) error {
+	if imageListFormat != FormatJSON && imageListFormat != FormatText {
+		return fmt.Errorf("invalid format %q (valid formats: %s, %s)", imageListFormat, FormatJSON, FormatText)
+	}
+	ctx := cmd.Context()
+
+	lister, err := newImageLister(cmd)
+	if err != nil {
+		return err
+	}
+	managed, err := images.ListManagedImages(ctx, lister)
+	if err != nil {
+		return err
+	}
+	referenced, err := runner.ReferencedImages(ctx)
+	if err != nil {
+		return err
+	}
+
+	if imageListFormat == FormatJSON {
+		type image struct {
+			images.ManagedImage
+			InUse bool `json:"in_use"`
+		}
+		output := make([]image, 0, len(managed))
+		for _, m := range managed {
+			output = append(output, image{ManagedImage: m, InUse: m.IsReferenced(referenced)})
+		}
+		jsonData, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %v", err)
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	if len(managed) == 0 {
+		fmt.Println("No images found")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "IMAGE\tID\tORIGIN\tSINCE\tSIZE\tIN USE")
+	for _, m := range managed {
+		inUse := "no"
+		if m.IsReferenced(referenced) {
+			inUse = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			imageDisplayName(m.Image),
+			shortImageID(m.ID),
+			m.Origin,
+			m.Since.Format(time.RFC3339),
+			units.HumanSize(float64(m.Size)),
+			inUse,
+		)
+	}
+	return w.Flush()
+}
+
+func imagePruneCmdFunc(cmd *cobra.Command, _ []string) error {
+	olderThan, err := parseImageAge(imagePruneOlderThan)
+	if err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+
+	lister, err := newImageLister(cmd)
+	if err != nil {
+		return err
+	}
+	referenced, err := runner.ReferencedImages(ctx)
+	if err != nil {
+		return fmt.Errorf("refusing to prune images: %w", err)
+	}
+
+	removed, err := images.Prune(ctx, lister, images.PruneOptions{
+		Unused:     imagePruneUnused,
+		OlderThan:  olderThan,
+		Referenced: referenced,
+	})
+	if err != nil {
+		return err
+	}
+	if len(removed) == 0 {
+		fmt.Println("No images to remove")
+		return nil
+	}
+	var size int64
+	for _, image := range removed {
+		fmt.Printf("Removed %s (%s)\n", imageDisplayName(image.Image), shortImageID(image.ID))
+		size += image.Size
+	}
+	fmt.Printf("Removed %d images, reclaimed %s\n", len(removed), units.HumanSize(float64(size)))
+	return nil
+}
+
+// newImageLister returns the image manager of the container runtime, if it can list images
+func newImageLister(cmd *cobra.Command) (images.ImageLister, error) {
+	lister, ok := images.NewImageManager(cmd.Context()).(images.ImageLister)
+	if !ok {
+		return nil, fmt.Errorf("the container runtime does not support listing images")
+	}
+	return lister, nil
+}
+
+// parseImageAge parses a duration, which may also be a number of days such as 30d
+func parseImageAge(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return age, nil
+}
+
+// imageDisplayName returns the tags of an image, or <none> if it is untagged
+func imageDisplayName(image images.Image) string {
+	if len(image.Tags) == 0 {
+		return "<none>"
+	}
+	return strings.Join(image.Tags, ",")
+}
+
+// shortImageID returns the first 12 characters of the hex digest of an image ID
+func shortImageID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
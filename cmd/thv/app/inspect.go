@@ -0,0 +1,147 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacklok/toolhive/pkg/config"
+	"github.com/stacklok/toolhive/pkg/container/sbom"
+)
+
+var (
+	inspectSBOMFormat          string
+	inspectSBOMVulnerabilities bool
+	inspectSBOMDatabase        string
+)
+
+func newInspectCommand() *cobra.Command {
+	inspectCmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Inspect the images of workloads",
+		Long:  "Inspect the images of workloads, such as the packages installed in them.",
+	}
+
+	sbomCmd := &cobra.Command{
+		Use:   "sbom [workload-name]",
+		Short: "Show the SBOM of the image of a workload",
+		Long: `Show the software bill of materials (SBOM) of the image of a workload.
+
+When 'thv run' starts a workload, it catalogs the packages installed in its image,
+whether the image was pulled or built from a protocol scheme, and stores the SBOM
+next to the state of the workload. The SBOM is a CycloneDX document which lists
+Debian and Alpine packages, Python distributions, npm packages, .NET packages and
+the modules of Go executables, identified by their package URL.
+
+Use --vulnerabilities to list the vulnerabilities of the packages found in an
+offline vulnerability database. The database is a directory or zip archive of
+OSV advisories, such as the ecosystem exports of osv.dev.
+
+A vulnerability policy in the ToolHive configuration makes 'thv run' refuse to
+start images with vulnerabilities in the database:
+
+	vulnerability_policy:
+	  database: /var/lib/osv/all.zip
+	  severity: critical    # lowest severity that counts, defaults to critical
+	  max_findings: 0       # number of vulnerabilities tolerated
+	  ignore: [CVE-2024-1234]
+
+Examples:
+	$ thv inspect sbom fetch
+	$ thv inspect sbom fetch --format text
+	$ thv inspect sbom fetch --vulnerabilities --database ./osv`,
+		Args: cobra.ExactArgs(1),
+		RunE: inspectSBOMCmdFunc,
+	}
+	sbomCmd.Flags().StringVar(&inspectSBOMFormat, "format", FormatJSON, "Output format (json or text)")
+	sbomCmd.Flags().BoolVar(&inspectSBOMVulnerabilities, "vulnerabilities", false,
+		"List the vulnerabilities of the packages instead of the SBOM")
+	sbomCmd.Flags().StringVar(&inspectSBOMDatabase, "database", "",
+		"Path of the OSV vulnerability database (defaults to the database of the vulnerability policy)")
+
+	inspectCmd.AddCommand(sbomCmd)
+	return inspectCmd
+}
+
+func inspectSBOMCmdFunc(cmd *cobra.Command, args []string) error {
+	if inspectSBOMFormat != FormatJSON && inspectSBOMFormat != FormatText {
+		return fmt.Errorf("invalid format %q (valid formats: %s, %s)", inspectSBOMFormat, FormatJSON, FormatText)
+	}
+
+	doc, err := sbom.Load(cmd.Context(), args[0])
+	if err != nil {
+		return err
+	}
+
+	if inspectSBOMVulnerabilities {
+		return printVulnerabilities(doc)
+	}
+	if inspectSBOMFormat == FormatJSON {
+		return doc.Write(os.Stdout)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION\tPURL\tLOCATION")
+	for _, component := range doc.Components {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", component.Name, component.Version, component.PURL, component.Location())
+	}
+	return w.Flush()
+}
+
+// printVulnerabilities prints the vulnerabilities of the packages of an SBOM
+func printVulnerabilities(doc *sbom.Document) error {
+	database := inspectSBOMDatabase
+	if database == "" {
+		database = config.NewDefaultProvider().GetConfig().VulnerabilityPolicy.Database
+	}
+	if database == "" {
+		return fmt.Errorf("no vulnerability database configured; use --database or set vulnerability_policy.database")
+	}
+
+	db, err := sbom.LoadDatabase(database)
+	if err != nil {
+		return err
+	}
+	findings := db.Match(doc)
+
+	if inspectSBOMFormat == FormatJSON {
+		type vulnerability struct {
+			ID       string   `json:"id"`
+			Aliases  []string `json:"aliases,omitempty"`
+			Severity string   `json:"severity"`
+			Summary  string   `json:"summary,omitempty"`
+			Package  string   `json:"package"`
+		}
+		vulnerabilities := make([]vulnerability, 0, len(findings))
+		for _, f := range findings {
+			vulnerabilities = append(vulnerabilities, vulnerability{
+				ID:       f.Advisory.ID,
+				Aliases:  f.Advisory.Aliases,
+				Severity: f.Severity.String(),
+				Summary:  f.Advisory.Summary,
+				Package:  f.Component.PURL,
+			})
+		}
+		jsonData, err := json.MarshalIndent(vulnerabilities, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	if len(findings) == 0 {
+		fmt.Println("No vulnerabilities found.")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tSEVERITY\tPACKAGE\tVERSION\tSUMMARY")
+	for _, f := range findings {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			f.Advisory.ID, f.Severity, f.Component.Name, f.Component.Version, f.Advisory.Summary)
+	}
+	return w.Flush()
+}
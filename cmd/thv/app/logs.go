@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -13,14 +14,21 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/stacklok/toolhive/pkg/config"
 	rt "github.com/stacklok/toolhive/pkg/container/runtime"
 	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/logs"
 	"github.com/stacklok/toolhive/pkg/workloads"
 )
 
 var (
 	followFlag bool
 	proxyFlag  bool
+	logsLevel  string
+	logsSince  string
+	logsUntil  string
+	logsGrep   string
+	logsFormat string
 )
 
 func logsCommand() *cobra.Command {
@@ -30,7 +38,13 @@ func logsCommand() *cobra.Command {
 		Long: `Output the logs of an MCP server managed by ToolHive, or manage log files.
 
 By default, this command shows the logs from the MCP server container.
-Use --proxy to view the logs from the ToolHive proxy process instead.`,
+Use --proxy to view the logs from the ToolHive proxy process instead. For MCP
+servers with network isolation, the proxy logs include the outbound connections
+which were denied by the network permissions of the permission profile.
+
+Container and proxy logs are collected into rotated files and parsed into
+structured records. Use --level, --since, --until and --grep to filter them,
+and --format json to print one JSON record per line.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Check if the argument is "prune"
@@ -44,6 +58,14 @@ Use --proxy to view the logs from the ToolHive proxy process instead.`,
 
 	logsCommand.Flags().BoolVarP(&followFlag, "follow", "f", false, "Follow log output (only for workload logs)")
 	logsCommand.Flags().BoolVarP(&proxyFlag, "proxy", "p", false, "Show proxy logs instead of container logs")
+	logsCommand.Flags().StringVar(&logsLevel, "level", "",
+		"Only show records at or above this level (debug, info, notice, warning, error, critical, alert, emergency)")
+	logsCommand.Flags().StringVar(&logsSince, "since", "",
+		"Only show records newer than a relative duration (e.g. 10m) or an RFC 3339 timestamp")
+	logsCommand.Flags().StringVar(&logsUntil, "until", "",
+		"Only show records older than a relative duration (e.g. 5m) or an RFC 3339 timestamp")
+	logsCommand.Flags().StringVar(&logsGrep, "grep", "", "Only show records matching this regular expression")
+	logsCommand.Flags().StringVar(&logsFormat, "format", FormatText, "Output format (json or text)")
 
 	err := viper.BindPFlag("follow", logsCommand.Flags().Lookup("follow"))
 	if err != nil {
@@ -78,6 +100,19 @@ func logsCmdFunc(cmd *cobra.Command, args []string) error {
 	follow := viper.GetBool("follow")
 	proxy := viper.GetBool("proxy")
 
+	if logsFormat != FormatText && logsFormat != FormatJSON {
+		return fmt.Errorf("invalid format %q, must be one of: %s, %s", logsFormat, FormatText, FormatJSON)
+	}
+
+	filterOpts := logs.FilterOptions{Level: logsLevel, Since: logsSince, Until: logsUntil, Grep: logsGrep}
+	if !filterOpts.IsZero() || logsFormat == FormatJSON {
+		source := logs.SourceContainer
+		if proxy {
+			source = logs.SourceProxy
+		}
+		return structuredLogsCmdFunc(ctx, workloadName, source, filterOpts, follow)
+	}
+
 	if proxy {
 		return getProxyLogs(workloadName, follow)
 	}
@@ -100,47 +135,116 @@ func logsCmdFunc(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// structuredLogsCmdFunc prints the parsed and filtered log records of a workload.
+// Container logs fall back to the runtime output when no collected logs exist,
+// e.g. for workloads started before log collection was available.
+func structuredLogsCmdFunc(
+	ctx context.Context, workloadName string, source logs.Source, filterOpts logs.FilterOptions, follow bool,
+) error {
+	filter, err := logs.NewFilter(filterOpts, time.Now())
+	if err != nil {
+		return err
+	}
+
+	store, err := logs.NewDefaultStore(config.NewDefaultProvider().GetConfig().LogRetention)
+	if err != nil {
+		return err
+	}
+
+	printRecord := func(record logs.Record) error {
+		if logsFormat == FormatJSON {
+			return json.NewEncoder(os.Stdout).Encode(record)
+		}
+		_, err := fmt.Println(record.String())
+		return err
+	}
+
+	if source == logs.SourceContainer && len(store.Files(workloadName, source)) == 0 {
+		manager, err := workloads.NewManager(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create workload manager: %v", err)
+		}
+		output, err := manager.GetLogs(ctx, workloadName, false)
+		if err != nil {
+			if errors.Is(err, rt.ErrWorkloadNotFound) {
+				logger.Infof("workload %s not found", workloadName)
+				return nil
+			}
+			return fmt.Errorf("failed to get logs for workload %s: %v", workloadName, err)
+		}
+		for _, line := range strings.Split(output, "\n") {
+			if record, ok := logs.ParseLine(source, line, time.Now()); ok && filter.Match(record) {
+				if err := printRecord(record); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	records, err := store.Read(workloadName, source, filter)
+	if err != nil {
+		return fmt.Errorf("failed to read logs for workload %s: %v", workloadName, err)
+	}
+	for _, record := range records {
+		if err := printRecord(record); err != nil {
+			return err
+		}
+	}
+
+	if !follow {
+		return nil
+	}
+	// Only the lower bound of the time window applies to new records
+	filter.Until = time.Time{}
+	return store.Follow(ctx, workloadName, source, filter, printRecord)
+}
+
 func logsPruneCmdFunc(cmd *cobra.Command) error {
 	ctx := cmd.Context()
 
-	logsDir, err := getLogsDirectory()
+	store, err := getLogStore()
 	if err != nil {
 		return err
 	}
+	if store == nil {
+		return nil
+	}
 
 	managedNames, err := getManagedContainerNames(ctx)
 	if err != nil {
 		return err
 	}
 
-	logFiles, err := getLogFiles(logsDir)
+	logWorkloads, err := store.Workloads()
 	if err != nil {
 		return err
 	}
 
-	if len(logFiles) == 0 {
+	if len(logWorkloads) == 0 {
 		logger.Info("No log files found")
 		return nil
 	}
 
-	prunedFiles, errs := pruneOrphanedLogFiles(logFiles, managedNames)
+	prunedFiles, errs := pruneOrphanedLogFiles(store, logWorkloads, managedNames)
 	reportPruneResults(prunedFiles, errs)
 
 	return nil
 }
 
-func getLogsDirectory() (string, error) {
-	logsDir, err := xdg.DataFile("toolhive/logs")
+// getLogStore returns the workload log store, or nil if no logs have been written yet.
+func getLogStore() (*logs.Store, error) {
+	store, err := logs.NewDefaultStore(config.NewDefaultProvider().GetConfig().LogRetention)
 	if err != nil {
-		return "", fmt.Errorf("failed to get logs directory path: %v", err)
+		return nil, err
 	}
 
-	if _, err := os.Stat(logsDir); os.IsNotExist(err) {
+	if _, err := os.Stat(store.Dir()); os.IsNotExist(err) {
 		logger.Info("No logs directory found, nothing to prune")
-		return "", nil
+		return nil, nil
 	}
 
-	return logsDir, nil
+	return store, nil
 }
 
 func getManagedContainerNames(ctx context.Context) (map[string]bool, error) {
@@ -165,27 +269,20 @@ func getManagedContainerNames(ctx context.Context) (map[string]bool, error) {
 	return managedNames, nil
 }
 
-func getLogFiles(logsDir string) ([]string, error) {
-	if logsDir == "" {
-		return nil, nil
-	}
-
-	logFiles, err := filepath.Glob(filepath.Join(logsDir, "*.log"))
-	if err != nil {
-		return nil, fmt.Errorf("failed to list log files: %v", err)
-	}
-
-	return logFiles, nil
-}
-
-func pruneOrphanedLogFiles(logFiles []string, managedNames map[string]bool) ([]string, []string) {
+// pruneOrphanedLogFiles removes the proxy and container log files, including rotated
+// ones, of every workload which is no longer managed by ToolHive.
+func pruneOrphanedLogFiles(store *logs.Store, logWorkloads []string, managedNames map[string]bool) ([]string, []string) {
 	var prunedFiles []string
 	var errs []string
 
-	for _, logFile := range logFiles {
-		baseName := strings.TrimSuffix(filepath.Base(logFile), ".log")
+	for _, name := range logWorkloads {
+		if managedNames[name] {
+			continue
+		}
 
-		if !managedNames[baseName] {
+		files := store.Files(name, logs.SourceProxy)
+		files = append(files, store.Files(name, logs.SourceContainer)...)
+		for _, logFile := range files {
 			if err := os.Remove(logFile); err != nil {
 				errs = append(errs, fmt.Sprintf("failed to remove %s: %v", logFile, err))
 				logger.Warnf("Failed to remove log file %s: %v", logFile, err)
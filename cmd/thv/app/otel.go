@@ -93,6 +93,59 @@ var unsetOtelTracingEnabledCmd = &cobra.Command{
 	RunE:  unsetOtelTracingEnabledCmdFunc,
 }
 
+var setOtelLogsEnabledCmd = &cobra.Command{
+	Use:   "set-logs-enabled <enabled>",
+	Short: "Set the OpenTelemetry logs export to enabled",
+	Long: `Set the OpenTelemetry logs flag to enable to export ToolHive logs and audit events to an OTel collector.
+
+Log records emitted while handling a traced request carry its trace and span IDs.
+
+	thv config otel set-logs-enabled true`,
+	Args: cobra.ExactArgs(1),
+	RunE: setOtelLogsEnabledCmdFunc,
+}
+
+var getOtelLogsEnabledCmd = &cobra.Command{
+	Use:   "get-logs-enabled",
+	Short: "Get the currently configured OpenTelemetry logs export flag",
+	Long:  "Display the OpenTelemetry logs export flag that is currently configured.",
+	RunE:  getOtelLogsEnabledCmdFunc,
+}
+
+var unsetOtelLogsEnabledCmd = &cobra.Command{
+	Use:   "unset-logs-enabled",
+	Short: "Remove the configured OpenTelemetry logs export flag",
+	Long:  "Remove the OpenTelemetry logs export flag configuration.",
+	RunE:  unsetOtelLogsEnabledCmdFunc,
+}
+
+var setOtelExemplarsEnabledCmd = &cobra.Command{
+	Use:   "set-exemplars-enabled <enabled>",
+	Short: "Set the OpenTelemetry metric exemplars to enabled",
+	Long: `Set the OpenTelemetry exemplars flag to attach exemplars to metrics.
+
+Exemplars link a measurement, such as a slow request duration bucket, to the sampled trace which produced it.
+In the Prometheus /metrics endpoint, exemplars are only served in the OpenMetrics format.
+
+	thv config otel set-exemplars-enabled true`,
+	Args: cobra.ExactArgs(1),
+	RunE: setOtelExemplarsEnabledCmdFunc,
+}
+
+var getOtelExemplarsEnabledCmd = &cobra.Command{
+	Use:   "get-exemplars-enabled",
+	Short: "Get the currently configured OpenTelemetry metric exemplars flag",
+	Long:  "Display the OpenTelemetry metric exemplars flag that is currently configured.",
+	RunE:  getOtelExemplarsEnabledCmdFunc,
+}
+
+var unsetOtelExemplarsEnabledCmd = &cobra.Command{
+	Use:   "unset-exemplars-enabled",
+	Short: "Remove the configured OpenTelemetry metric exemplars flag",
+	Long:  "Remove the OpenTelemetry metric exemplars flag configuration.",
+	RunE:  unsetOtelExemplarsEnabledCmdFunc,
+}
+
 var setOtelSamplingRateCmd = &cobra.Command{
 	Use:   "set-sampling-rate <rate>",
 	Short: "Set the OpenTelemetry sampling rate",
@@ -209,6 +262,12 @@ func init() {
 	OtelCmd.AddCommand(setOtelTracingEnabledCmd)
 	OtelCmd.AddCommand(getOtelTracingEnabledCmd)
 	OtelCmd.AddCommand(unsetOtelTracingEnabledCmd)
+	OtelCmd.AddCommand(setOtelLogsEnabledCmd)
+	OtelCmd.AddCommand(getOtelLogsEnabledCmd)
+	OtelCmd.AddCommand(unsetOtelLogsEnabledCmd)
+	OtelCmd.AddCommand(setOtelExemplarsEnabledCmd)
+	OtelCmd.AddCommand(getOtelExemplarsEnabledCmd)
+	OtelCmd.AddCommand(unsetOtelExemplarsEnabledCmd)
 	OtelCmd.AddCommand(setOtelSamplingRateCmd)
 	OtelCmd.AddCommand(getOtelSamplingRateCmd)
 	OtelCmd.AddCommand(unsetOtelSamplingRateCmd)
@@ -482,6 +541,100 @@ func unsetOtelTracingEnabledCmdFunc(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
+func setOtelLogsEnabledCmdFunc(_ *cobra.Command, args []string) error {
+	enabled, err := strconv.ParseBool(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid boolean value for logs enabled flag: %w", err)
+	}
+
+	// Update the configuration
+	err = config.UpdateConfig(func(c *config.Config) {
+		c.OTEL.LogsEnabled = enabled
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update configuration: %w", err)
+	}
+
+	fmt.Printf("Successfully set OpenTelemetry logs enabled: %t\n", enabled)
+	return nil
+}
+
+func getOtelLogsEnabledCmdFunc(_ *cobra.Command, _ []string) error {
+	configProvider := config.NewDefaultProvider()
+	cfg := configProvider.GetConfig()
+
+	fmt.Printf("Current OpenTelemetry logs enabled: %t\n", cfg.OTEL.LogsEnabled)
+	return nil
+}
+
+func unsetOtelLogsEnabledCmdFunc(_ *cobra.Command, _ []string) error {
+	configProvider := config.NewDefaultProvider()
+	cfg := configProvider.GetConfig()
+
+	if !cfg.OTEL.LogsEnabled {
+		fmt.Println("OpenTelemetry logs enabled is already disabled.")
+		return nil
+	}
+
+	// Update the configuration
+	err := config.UpdateConfig(func(c *config.Config) {
+		c.OTEL.LogsEnabled = false
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update configuration: %w", err)
+	}
+
+	fmt.Println("Successfully disabled OpenTelemetry logs enabled configuration.")
+	return nil
+}
+
+func setOtelExemplarsEnabledCmdFunc(_ *cobra.Command, args []string) error {
+	enabled, err := strconv.ParseBool(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid boolean value for exemplars enabled flag: %w", err)
+	}
+
+	// Update the configuration
+	err = config.UpdateConfig(func(c *config.Config) {
+		c.OTEL.ExemplarsEnabled = enabled
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update configuration: %w", err)
+	}
+
+	fmt.Printf("Successfully set OpenTelemetry exemplars enabled: %t\n", enabled)
+	return nil
+}
+
+func getOtelExemplarsEnabledCmdFunc(_ *cobra.Command, _ []string) error {
+	configProvider := config.NewDefaultProvider()
+	cfg := configProvider.GetConfig()
+
+	fmt.Printf("Current OpenTelemetry exemplars enabled: %t\n", cfg.OTEL.ExemplarsEnabled)
+	return nil
+}
+
+func unsetOtelExemplarsEnabledCmdFunc(_ *cobra.Command, _ []string) error {
+	configProvider := config.NewDefaultProvider()
+	cfg := configProvider.GetConfig()
+
+	if !cfg.OTEL.ExemplarsEnabled {
+		fmt.Println("OpenTelemetry exemplars enabled is already disabled.")
+		return nil
+	}
+
+	// Update the configuration
+	err := config.UpdateConfig(func(c *config.Config) {
+		c.OTEL.ExemplarsEnabled = false
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update configuration: %w", err)
+	}
+
+	fmt.Println("Successfully disabled OpenTelemetry exemplars enabled configuration.")
+	return nil
+}
+
 func setOtelInsecureCmdFunc(_ *cobra.Command, args []string) error {
 	enabled, err := strconv.ParseBool(args[0])
 	if err != nil {
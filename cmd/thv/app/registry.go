@@ -235,6 +235,22 @@ func printTextServerInfo(name string, server registry.ServerMetadata) {
 						}
 						fmt.Printf("    Allow Port: %s\n", strings.Join(ports, ", "))
 					}
+
+					if len(outbound.AllowCIDR) > 0 {
+						fmt.Printf("    Allow CIDR: %s\n", strings.Join(outbound.AllowCIDR, ", "))
+					}
+
+					if len(outbound.AllowHostPort) > 0 {
+						fmt.Printf("    Allow Host Port: %s\n", strings.Join(outbound.AllowHostPort, ", "))
+					}
+
+					if len(outbound.DenyHost) > 0 {
+						fmt.Printf("    Deny Host: %s\n", strings.Join(outbound.DenyHost, ", "))
+					}
+
+					if len(outbound.DenyCIDR) > 0 {
+						fmt.Printf("    Deny CIDR: %s\n", strings.Join(outbound.DenyCIDR, ", "))
+					}
 				}
 			}
 		}
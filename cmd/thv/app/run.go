@@ -14,6 +14,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/stacklok/toolhive/pkg/container"
 	"github.com/stacklok/toolhive/pkg/container/runtime"
@@ -22,9 +23,13 @@ import (
 	"github.com/stacklok/toolhive/pkg/networking"
 	"github.com/stacklok/toolhive/pkg/process"
 	"github.com/stacklok/toolhive/pkg/runner"
+	"github.com/stacklok/toolhive/pkg/telemetry"
 	"github.com/stacklok/toolhive/pkg/workloads"
 )
 
+// tracerName is the name of the tracer of CLI operations
+const tracerName = "github.com/stacklok/toolhive/cmd/thv/app"
+
 var runCmd = &cobra.Command{
 	Use:   "run [flags] SERVER_OR_IMAGE_OR_PROTOCOL [-- ARGS...]",
 	Short: "Run an MCP server",
@@ -51,11 +56,17 @@ ToolHive supports five ways to run an MCP server:
 	   $ thv run npx://package-name [-- args...]
 	   $ thv run go://package-name [-- args...]
 	   $ thv run go://./local-path [-- args...]
+	   $ thv run pipx://package-name [-- args...]
+	   $ thv run cargo://crate-name [-- args...]
+	   $ thv run dotnet://Tool.Name [-- args...]
+	   $ thv run git://github.com/org/repo@ref#subdir [-- args...]
 
    Automatically generates a container that runs the specified package
-   using either uvx (Python with uv package manager), npx (Node.js),
-   or go (Golang). For Go, you can also specify local paths starting
-   with './' or '../' to build and run local Go projects.
+   using uvx (Python with uv package manager), npx (Node.js), go (Golang),
+   pipx (Python), cargo (Rust) or dotnet (.NET tools). For Go, you can also
+   specify local paths starting with './' or '../' to build and run local
+   Go projects. Git repositories are built according to the package.json,
+   pyproject.toml, go.mod or Cargo.toml found in the repository.
 
 4. From an exported configuration:
 
@@ -178,14 +189,53 @@ func runCmdFunc(cmd *cobra.Command, args []string) error {
 
 // runSingleServer handles the core logic for running a single MCP server
 func runSingleServer(ctx context.Context, runFlags *RunFlags, serverOrImage string, cmdArgs []string, debugMode bool, cmd *cobra.Command, groupName string) error { //nolint:lll
+	// Trace the preparation of the workload, such as image retrieval, when tracing is configured.
+	// The workload manager traces the startup of the workload itself, in this or a detached process.
+	tracingProvider, err := telemetry.NewTracingProvider(ctx, setupTelemetryConfiguration(cmd, runFlags))
+	if err != nil {
+		return fmt.Errorf("failed to create tracing provider: %w", err)
+	}
+	ctx, span := telemetry.StartSpan(ctx, tracerName, "thv.run", attribute.String("mcp.server", serverOrImage))
+
+	workloadManager, runnerConfig, err := prepareSingleServer(ctx, runFlags, serverOrImage, cmdArgs, debugMode, cmd, groupName)
+	span.SetAttributes(attribute.String("workload.name", runFlags.Name))
+	if err == nil && runnerConfig.LearnPermissions {
+		logger.Infof("Learning permissions: the suggested permission profile will be written to %s when %s stops",
+			runnerConfig.LearnPermissionsOutput, runnerConfig.BaseName)
+	}
+	if err == nil && !runFlags.Foreground {
+		err = workloadManager.RunWorkloadDetached(ctx, runnerConfig)
+	}
+
+	telemetry.EndSpan(span, err)
+	if shutdownErr := tracingProvider.Shutdown(ctx); shutdownErr != nil {
+		logger.Warnf("Warning: Failed to shutdown tracing provider: %v", shutdownErr)
+	}
+
+	if err != nil || !runFlags.Foreground {
+		return err
+	}
+	return runForeground(ctx, workloadManager, runnerConfig)
+}
+
+// prepareSingleServer builds and saves the run configuration of a single MCP server
+func prepareSingleServer(
+	ctx context.Context,
+	runFlags *RunFlags,
+	serverOrImage string,
+	cmdArgs []string,
+	debugMode bool,
+	cmd *cobra.Command,
+	groupName string,
+) (workloads.Manager, *runner.RunConfig, error) {
 	// Create container runtime
 	rt, err := container.NewFactory().Create(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to create container runtime: %v", err)
+		return nil, nil, fmt.Errorf("failed to create container runtime: %v", err)
 	}
 	workloadManager, err := workloads.NewManagerFromRuntime(rt)
 	if err != nil {
-		return fmt.Errorf("failed to create workload manager: %v", err)
+		return nil, nil, fmt.Errorf("failed to create workload manager: %v", err)
 	}
 
 	if runFlags.Name == "" {
@@ -194,33 +244,29 @@ func runSingleServer(ctx context.Context, runFlags *RunFlags, serverOrImage stri
 	}
 	exists, err := workloadManager.DoesWorkloadExist(ctx, runFlags.Name)
 	if err != nil {
-		return fmt.Errorf("failed to check if workload exists: %v", err)
+		return nil, nil, fmt.Errorf("failed to check if workload exists: %v", err)
 	}
 	if exists {
-		return fmt.Errorf("workload with name '%s' already exists", runFlags.Name)
+		return nil, nil, fmt.Errorf("workload with name '%s' already exists", runFlags.Name)
 	}
 	err = validateGroup(ctx, workloadManager, serverOrImage)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	// Build the run configuration
 	runnerConfig, err := BuildRunnerConfig(ctx, runFlags, serverOrImage, cmdArgs, debugMode, cmd, groupName)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	// Always save the run config to disk before starting (both foreground and detached modes)
 	// NOTE: Save before secrets processing to avoid storing secrets in the state store
 	if err := runnerConfig.SaveState(ctx); err != nil {
-		return fmt.Errorf("failed to save run configuration: %v", err)
-	}
-
-	if runFlags.Foreground {
-		return runForeground(ctx, workloadManager, runnerConfig)
+		return nil, nil, fmt.Errorf("failed to save run configuration: %v", err)
 	}
 
-	return workloadManager.RunWorkloadDetached(ctx, runnerConfig)
+	return workloadManager, runnerConfig, nil
 }
 
 // deriveRemoteName extracts a name from a remote URL
@@ -248,7 +294,7 @@ func deriveRemoteName(remoteURL string) (string, error) {
 // getworkloadDefaultName generates a default workload name based on the serverOrImage input
 // This function reuses the existing system's naming logic to ensure consistency
 func getworkloadDefaultName(serverOrImage string) string {
-	// If it's a protocol scheme (uvx://, npx://, go://)
+	// If it's a protocol scheme (uvx://, npx://, go://, ...)
 	if runner.IsImageProtocolScheme(serverOrImage) {
 		// Extract package name from protocol scheme using the existing parseProtocolScheme logic
 		_, packageName, err := runner.ParseProtocolScheme(serverOrImage)
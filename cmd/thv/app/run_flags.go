@@ -16,6 +16,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/environment"
 	"github.com/stacklok/toolhive/pkg/ignore"
 	"github.com/stacklok/toolhive/pkg/networking"
+	"github.com/stacklok/toolhive/pkg/permissions"
 	"github.com/stacklok/toolhive/pkg/process"
 	"github.com/stacklok/toolhive/pkg/registry"
 	"github.com/stacklok/toolhive/pkg/runner"
@@ -23,6 +24,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/telemetry"
 	"github.com/stacklok/toolhive/pkg/transport"
 	"github.com/stacklok/toolhive/pkg/transport/types"
+	"github.com/stacklok/toolhive/pkg/usage"
 )
 
 const (
@@ -47,6 +49,12 @@ type RunFlags struct {
 	Volumes           []string
 	Secrets           []string
 
+	// Resource limits
+	CPUs      string
+	Memory    string
+	PidsLimit int64
+	Ulimits   []string
+
 	// Remote MCP server support
 	RemoteURL string
 
@@ -56,10 +64,19 @@ type RunFlags struct {
 	EnableAudit bool
 	K8sPodPatch string
 
+	// Usage accounting and quotas
+	EnableUsage       bool
+	UsageConfig       string
+	UsageDailyQuota   int64
+	UsageMonthlyQuota int64
+
 	// Image verification
 	CACertPath  string
 	VerifyImage string
 
+	// LockFile pins the packages of protocol schemes
+	LockFile string
+
 	// OIDC configuration
 	ThvCABundle        string
 	JWKSAuthTokenFile  string
@@ -73,6 +90,8 @@ type RunFlags struct {
 	OtelServiceName                 string
 	OtelTracingEnabled              bool
 	OtelMetricsEnabled              bool
+	OtelLogsEnabled                 bool
+	OtelExemplarsEnabled            bool
 	OtelSamplingRate                float64
 	OtelHeaders                     []string
 	OtelInsecure                    bool
@@ -82,6 +101,13 @@ type RunFlags struct {
 	// Network isolation
 	IsolateNetwork bool
 
+	// Auxiliary service containers
+	Services string
+
+	// Permission learning
+	LearnPermissions       bool
+	LearnPermissionsOutput string
+
 	// Proxy headers
 	TrustProxyHeaders bool
 
@@ -152,14 +178,30 @@ func AddRunFlags(cmd *cobra.Command, config *RunFlags) {
 		&config.Secrets,
 		"secret",
 		[]string{},
-		"Specify a secret to be fetched from the secrets manager and set as an environment variable (format: NAME,target=TARGET)",
+		"Specify a secret to be fetched from the secrets manager and set as an environment variable "+
+			"(format: NAME,target=TARGET), or mounted as a read-only file (format: NAME,target=file:/PATH[,mode=0400])",
 	)
+	cmd.Flags().StringVar(&config.CPUs, "cpus", "", "Number of CPUs the MCP server may use (e.g. 0.5 or 2)")
+	cmd.Flags().StringVar(&config.Memory, "memory", "", "Memory limit of the MCP server (e.g. 512m or 1Gi)")
+	cmd.Flags().Int64Var(&config.PidsLimit, "pids-limit", 0, "Maximum number of processes in the MCP server container")
+	cmd.Flags().StringArrayVar(&config.Ulimits, "ulimit", []string{},
+		"Resource limit of the processes in the MCP server container (format: name=soft[:hard], e.g. nofile=1024:2048)")
 	cmd.Flags().StringVar(&config.AuthzConfig, "authz-config", "", "Path to the authorization configuration file")
 	cmd.Flags().StringVar(&config.AuditConfig, "audit-config", "", "Path to the audit configuration file")
 	cmd.Flags().BoolVar(&config.EnableAudit, "enable-audit", false, "Enable audit logging with default configuration")
+	cmd.Flags().BoolVar(&config.EnableUsage, "enable-usage", false,
+		"Enable usage accounting of tool calls, bytes and time per client identity")
+	cmd.Flags().StringVar(&config.UsageConfig, "usage-config", "",
+		"Path to the usage accounting and quota configuration file (implies --enable-usage)")
+	cmd.Flags().Int64Var(&config.UsageDailyQuota, "usage-daily-quota", 0,
+		"Maximum number of tool calls per client identity per day, 0 for unlimited (implies --enable-usage)")
+	cmd.Flags().Int64Var(&config.UsageMonthlyQuota, "usage-monthly-quota", 0,
+		"Maximum number of tool calls per client identity per month, 0 for unlimited (implies --enable-usage)")
 	cmd.Flags().StringVar(&config.K8sPodPatch, "k8s-pod-patch", "",
 		"JSON string to patch the Kubernetes pod template (only applicable when using Kubernetes runtime)")
 	cmd.Flags().StringVar(&config.CACertPath, "ca-cert", "", "Path to a custom CA certificate file to use for container builds")
+	cmd.Flags().StringVar(&config.LockFile, "lock-file", "",
+		"Path of a lockfile written by 'thv build --lock' whose pins are used to build protocol schemes")
 	cmd.Flags().StringVar(&config.VerifyImage, "image-verification", retriever.VerifyImageWarn,
 		fmt.Sprintf("Set image verification mode (%s, %s, %s)",
 			retriever.VerifyImageWarn, retriever.VerifyImageEnabled, retriever.VerifyImageDisabled))
@@ -186,6 +228,10 @@ func AddRunFlags(cmd *cobra.Command, config *RunFlags) {
 		"Enable distributed tracing (when OTLP endpoint is configured)")
 	cmd.Flags().BoolVar(&config.OtelMetricsEnabled, "otel-metrics-enabled", true,
 		"Enable OTLP metrics export (when OTLP endpoint is configured)")
+	cmd.Flags().BoolVar(&config.OtelLogsEnabled, "otel-logs-enabled", false,
+		"Enable OTLP export of logs and audit events (when OTLP endpoint is configured)")
+	cmd.Flags().BoolVar(&config.OtelExemplarsEnabled, "otel-exemplars-enabled", false,
+		"Attach exemplars linking metrics to sampled traces")
 	cmd.Flags().Float64Var(&config.OtelSamplingRate, "otel-sampling-rate", 0.1, "OpenTelemetry trace sampling rate (0.0-1.0)")
 	cmd.Flags().StringArrayVar(&config.OtelHeaders, "otel-headers", nil,
 		"OpenTelemetry OTLP headers in key=value format (e.g., x-honeycomb-team=your-api-key)")
@@ -198,6 +244,14 @@ func AddRunFlags(cmd *cobra.Command, config *RunFlags) {
 
 	cmd.Flags().BoolVar(&config.IsolateNetwork, "isolate-network", false,
 		"Isolate the container network from the host (default: false)")
+	cmd.Flags().StringVar(&config.Services, "services", "",
+		"Path to a YAML or JSON file declaring service containers, such as a database, "+
+			"which start before the MCP server and are removed with it")
+	cmd.Flags().BoolVar(&config.LearnPermissions, "learn-permissions", false,
+		"Allow and record all outbound connections and mounted file accesses, "+
+			"and write a suggested permission profile when the server stops (implies --isolate-network)")
+	cmd.Flags().StringVar(&config.LearnPermissionsOutput, "learn-permissions-output", "",
+		"Path to write the suggested permission profile to (default: the ToolHive state directory)")
 	cmd.Flags().BoolVar(&config.TrustProxyHeaders, "trust-proxy-headers", false,
 		"Trust X-Forwarded-* headers from reverse proxies (X-Forwarded-Proto, X-Forwarded-Host, X-Forwarded-Port, X-Forwarded-Prefix)")
 	cmd.Flags().StringArrayVarP(&config.Labels, "label", "l", []string{}, "Set labels on the container (format: key=value)")
@@ -281,8 +335,18 @@ func BuildRunnerConfig(
 	}
 
 	// Build the runner config
-	return buildRunnerConfig(ctx, runFlags, cmdArgs, debugMode, validatedHost, rt, imageURL, serverMetadata,
+	runConfig, err := buildRunnerConfig(ctx, runFlags, cmdArgs, debugMode, validatedHost, rt, imageURL, serverMetadata,
 		envVars, envVarValidator, oidcConfig, telemetryConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// The service containers run next to the MCP server, so their images are subject to the same policies
+	verifyOpts := retriever.Options{VerifyImage: runFlags.VerifyImage}
+	if err := retriever.VerifyServiceImages(ctx, runConfig.Services, verifyOpts); err != nil {
+		return nil, fmt.Errorf("failed to verify the images of the services: %w", err)
+	}
+	return runConfig, nil
 }
 
 // setupOIDCConfiguration sets up OIDC configuration and validates URLs
@@ -313,9 +377,13 @@ func setupTelemetryConfiguration(cmd *cobra.Command, runFlags *RunFlags) *teleme
 		runFlags.OtelSamplingRate, runFlags.OtelEnvironmentVariables, runFlags.OtelInsecure,
 		runFlags.OtelEnablePrometheusMetricsPath)
 
+	finalOtelLogsEnabled, finalOtelExemplarsEnabled := getTelemetrySignalsFromFlags(cmd, config,
+		runFlags.OtelLogsEnabled, runFlags.OtelExemplarsEnabled)
+
 	return createTelemetryConfig(finalOtelEndpoint, finalOtelEnablePrometheusMetricsPath,
 		runFlags.OtelServiceName, runFlags.OtelTracingEnabled, runFlags.OtelMetricsEnabled, finalOtelSamplingRate,
-		runFlags.OtelHeaders, finalOtelInsecure, finalOtelEnvironmentVariables)
+		runFlags.OtelHeaders, finalOtelInsecure, finalOtelEnvironmentVariables,
+		finalOtelLogsEnabled, finalOtelExemplarsEnabled)
 }
 
 // setupRuntimeAndValidation creates container runtime and selects environment variable validator
@@ -349,8 +417,13 @@ func handleImageRetrieval(
 ) {
 
 	// Try to get server from registry (container or remote) or direct URL
-	imageURL, serverMetadata, err := retriever.GetMCPServer(
-		ctx, serverOrImage, runFlags.CACertPath, runFlags.VerifyImage, groupName)
+	imageURL, serverMetadata, err := retriever.GetMCPServer(ctx, serverOrImage, retriever.Options{
+		CACertPath:   runFlags.CACertPath,
+		VerifyImage:  runFlags.VerifyImage,
+		GroupName:    groupName,
+		LockFile:     runFlags.LockFile,
+		WorkloadName: runFlags.Name,
+	})
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to find or create the MCP server %s: %v", serverOrImage, err)
 	}
@@ -433,6 +506,8 @@ func buildRunnerConfig(
 		runner.WithAuditConfigPath(runFlags.AuditConfig),
 		runner.WithPermissionProfileNameOrPath(runFlags.PermissionProfile),
 		runner.WithNetworkIsolation(runFlags.IsolateNetwork),
+		runner.WithServicesFile(runFlags.Services),
+		runner.WithPermissionLearning(runFlags.LearnPermissions, runFlags.LearnPermissionsOutput),
 		runner.WithTrustProxyHeaders(runFlags.TrustProxyHeaders),
 		runner.WithK8sPodPatch(runFlags.K8sPodPatch),
 		runner.WithProxyMode(types.ProxyMode(runFlags.ProxyMode)),
@@ -446,6 +521,12 @@ func buildRunnerConfig(
 		}),
 	}
 
+	resourceLimits, err := buildResourceLimits(runFlags)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, runner.WithResourceLimits(resourceLimits))
+
 	var toolsOverride map[string]runner.ToolOverride
 	if runFlags.ToolsOverride != "" {
 		loadedToolsOverride, err := cli.LoadToolsOverride(runFlags.ToolsOverride)
@@ -466,6 +547,54 @@ func buildRunnerConfig(
 	return runner.NewRunConfigBuilder(ctx, imageMetadata, envVars, envVarValidator, opts...)
 }
 
+// buildResourceLimits builds the resource limits from the flags, returning nil when no limits are set
+func buildResourceLimits(runFlags *RunFlags) (*permissions.ResourceLimits, error) {
+	limits := &permissions.ResourceLimits{
+		CPUs:      runFlags.CPUs,
+		Memory:    runFlags.Memory,
+		PidsLimit: runFlags.PidsLimit,
+	}
+	for _, value := range runFlags.Ulimits {
+		ulimit, err := permissions.ParseUlimit(value)
+		if err != nil {
+			return nil, err
+		}
+		limits.Ulimits = append(limits.Ulimits, ulimit)
+	}
+
+	if limits.IsEmpty() {
+		return nil, nil
+	}
+	if err := limits.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid resource limits: %w", err)
+	}
+	return limits, nil
+}
+
+// buildUsageConfig builds the usage accounting configuration from the flags,
+// returning nil when usage accounting is not enabled
+func buildUsageConfig(runFlags *RunFlags) (*usage.Config, error) {
+	if runFlags.UsageDailyQuota < 0 || runFlags.UsageMonthlyQuota < 0 {
+		return nil, fmt.Errorf("usage quotas cannot be negative")
+	}
+
+	if runFlags.UsageConfig != "" {
+		usageConfig, err := usage.LoadFromFile(runFlags.UsageConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load usage configuration: %w", err)
+		}
+		quotas := usage.NewConfigWithQuotas(runFlags.UsageDailyQuota, runFlags.UsageMonthlyQuota).Quotas
+		usageConfig.Quotas = append(usageConfig.Quotas, quotas...)
+		return usageConfig, nil
+	}
+
+	if runFlags.EnableUsage || runFlags.UsageDailyQuota > 0 || runFlags.UsageMonthlyQuota > 0 {
+		return usage.NewConfigWithQuotas(runFlags.UsageDailyQuota, runFlags.UsageMonthlyQuota), nil
+	}
+
+	return nil, nil
+}
+
 // configureMiddlewareAndOptions configures middleware and additional runner options
 func configureMiddlewareAndOptions(
 	runFlags *RunFlags,
@@ -484,6 +613,12 @@ func configureMiddlewareAndOptions(
 		return nil, fmt.Errorf("invalid token exchange configuration: %w", err)
 	}
 
+	usageConfig, err := buildUsageConfig(runFlags)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, runner.WithUsageConfig(usageConfig))
+
 	// Use computed serverName and transportType for correct telemetry labels
 	opts = append(opts, runner.WithToolsOverride(toolsOverride))
 	opts = append(
@@ -497,6 +632,7 @@ func configureMiddlewareAndOptions(
 			runFlags.AuthzConfig,
 			runFlags.EnableAudit,
 			runFlags.AuditConfig,
+			usageConfig,
 			serverName,
 			transportType,
 		),
@@ -520,6 +656,7 @@ func configureMiddlewareAndOptions(
 	// Get OIDC and telemetry values for legacy configuration
 	oidcIssuer, oidcAudience, oidcJwksURL, oidcIntrospectionURL, oidcClientID, oidcClientSecret := extractOIDCValues(oidcConfig)
 	finalOtelEndpoint, finalOtelSamplingRate, finalOtelEnvironmentVariables := extractTelemetryValues(telemetryConfig)
+	finalOtelLogsEnabled, finalOtelExemplarsEnabled := extractTelemetrySignals(telemetryConfig)
 
 	// Set additional configurations that are still needed in old format for other parts of the system
 	opts = append(opts,
@@ -529,6 +666,7 @@ func configureMiddlewareAndOptions(
 		runner.WithTelemetryConfig(finalOtelEndpoint, runFlags.OtelEnablePrometheusMetricsPath,
 			runFlags.OtelTracingEnabled, runFlags.OtelMetricsEnabled, runFlags.OtelServiceName,
 			finalOtelSamplingRate, runFlags.OtelHeaders, runFlags.OtelInsecure, finalOtelEnvironmentVariables,
+			finalOtelLogsEnabled, finalOtelExemplarsEnabled,
 		),
 		runner.WithToolsFilter(runFlags.ToolsFilter))
 
@@ -588,6 +726,14 @@ func extractTelemetryValues(config *telemetry.Config) (string, float64, []string
 	return config.Endpoint, config.SamplingRate, config.EnvironmentVariables
 }
 
+// extractTelemetrySignals extracts the logs and exemplars flags from telemetry config
+func extractTelemetrySignals(config *telemetry.Config) (bool, bool) {
+	if config == nil {
+		return false, false
+	}
+	return config.LogsEnabled, config.ExemplarsEnabled
+}
+
 // getRemoteAuthFromRemoteServerMetadata creates RemoteAuthConfig from RemoteServerMetadata,
 // giving CLI flags priority. For OAuthParams: if CLI provides any, they REPLACE metadata entirely.
 func getRemoteAuthFromRemoteServerMetadata(
@@ -710,6 +856,23 @@ func getTelemetryFromFlags(cmd *cobra.Command, config *cfg.Config, otelEndpoint
 		finalOtelInsecure, finalOtelEnablePrometheusMetricsPath
 }
 
+// getTelemetrySignalsFromFlags extracts the logs and exemplars flags, using config values
+// as fallbacks when the flags are not explicitly set
+func getTelemetrySignalsFromFlags(cmd *cobra.Command, config *cfg.Config, otelLogsEnabled bool,
+	otelExemplarsEnabled bool) (bool, bool) {
+	finalOtelLogsEnabled := otelLogsEnabled
+	if !cmd.Flags().Changed("otel-logs-enabled") {
+		finalOtelLogsEnabled = config.OTEL.LogsEnabled
+	}
+
+	finalOtelExemplarsEnabled := otelExemplarsEnabled
+	if !cmd.Flags().Changed("otel-exemplars-enabled") {
+		finalOtelExemplarsEnabled = config.OTEL.ExemplarsEnabled
+	}
+
+	return finalOtelLogsEnabled, finalOtelExemplarsEnabled
+}
+
 // createOIDCConfig creates an OIDC configuration if any OIDC parameters are provided
 func createOIDCConfig(oidcIssuer, oidcAudience, oidcJwksURL, oidcIntrospectionURL,
 	oidcClientID, oidcClientSecret, resourceURL string, allowPrivateIP bool) *auth.TokenValidatorConfig {
@@ -732,7 +895,7 @@ func createOIDCConfig(oidcIssuer, oidcAudience, oidcJwksURL, oidcIntrospectionUR
 // createTelemetryConfig creates a telemetry configuration if any telemetry parameters are provided
 func createTelemetryConfig(otelEndpoint string, otelEnablePrometheusMetricsPath bool,
 	otelServiceName string, otelTracingEnabled bool, otelMetricsEnabled bool, otelSamplingRate float64, otelHeaders []string,
-	otelInsecure bool, otelEnvironmentVariables []string) *telemetry.Config {
+	otelInsecure bool, otelEnvironmentVariables []string, otelLogsEnabled bool, otelExemplarsEnabled bool) *telemetry.Config {
 	if otelEndpoint == "" && !otelEnablePrometheusMetricsPath {
 		return nil
 	}
@@ -771,6 +934,8 @@ func createTelemetryConfig(otelEndpoint string, otelEnablePrometheusMetricsPath
 		ServiceVersion:              telemetry.DefaultConfig().ServiceVersion,
 		TracingEnabled:              otelTracingEnabled,
 		MetricsEnabled:              otelMetricsEnabled,
+		LogsEnabled:                 otelLogsEnabled,
+		ExemplarsEnabled:            otelExemplarsEnabled,
 		SamplingRate:                otelSamplingRate,
 		Headers:                     headers,
 		Insecure:                    otelInsecure,
@@ -12,6 +12,7 @@ import (
 
 	"github.com/stacklok/toolhive/pkg/config"
 	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/usage"
 )
 
 // createTestConfigProvider creates a config provider for testing with the provided configuration.
@@ -354,3 +355,73 @@ func TestBuildRunnerConfig_TelemetryProcessing_Integration(t *testing.T) {
 	assert.Equal(t, false, finalInsecure, "Insecure setting should use runFlags value when not set via CLI")
 	assert.Equal(t, false, finalEnablePrometheusMetricsPath, "Enable Prometheus metrics path should use runFlags value when not set via CLI")
 }
+
+func TestBuildUsageConfig(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "usage.json")
+	require.NoError(t, os.WriteFile(configPath,
+		[]byte(`{"principal_claim":"team","quotas":[{"period":"daily","max_bytes":1024}]}`), 0600))
+
+	tests := []struct {
+		name          string
+		flags         RunFlags
+		expected      *usage.Config
+		errorContains string
+	}{
+		{
+			name:     "disabled by default",
+			flags:    RunFlags{},
+			expected: nil,
+		},
+		{
+			name:     "enabled without quotas",
+			flags:    RunFlags{EnableUsage: true},
+			expected: &usage.Config{PrincipalClaim: usage.DefaultPrincipalClaim},
+		},
+		{
+			name:  "quota flags imply accounting",
+			flags: RunFlags{UsageDailyQuota: 50},
+			expected: &usage.Config{
+				PrincipalClaim: usage.DefaultPrincipalClaim,
+				Quotas:         []usage.Quota{{Period: usage.PeriodDaily, MaxToolCalls: 50}},
+			},
+		},
+		{
+			name:  "quota flags are added to the config file",
+			flags: RunFlags{UsageConfig: configPath, UsageMonthlyQuota: 1000},
+			expected: &usage.Config{
+				PrincipalClaim: "team",
+				Quotas: []usage.Quota{
+					{Period: usage.PeriodDaily, MaxBytes: 1024},
+					{Period: usage.PeriodMonthly, MaxToolCalls: 1000},
+				},
+			},
+		},
+		{
+			name:          "negative quota",
+			flags:         RunFlags{UsageDailyQuota: -1},
+			errorContains: "usage quotas cannot be negative",
+		},
+		{
+			name:          "missing config file",
+			flags:         RunFlags{UsageConfig: filepath.Join(t.TempDir(), "missing.json")},
+			errorContains: "failed to load usage configuration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			usageConfig, err := buildUsageConfig(&tt.flags)
+			if tt.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, usageConfig)
+		})
+	}
+}
@@ -21,9 +21,23 @@ var runtimeCmd = &cobra.Command{
 var runtimeCheckCmd = &cobra.Command{
 	Use:   "check",
 	Short: "Ping the container runtime",
-	Long:  "Ensure the container runtime is responsive.",
-	Args:  cobra.NoArgs, // no args allowed
-	RunE:  runtimeCheckCmdFunc,
+	Long: `Ensure the container runtime is responsive.
+
+The check also reports whether the runtime is rootless or remaps the users of
+containers with a user namespace, and how the write mounts of permission
+profiles are made writable in that setup:
+
+  - rootless Podman runs workloads with write mounts with --userns=keep-id
+  - rootless Docker and containerd run workloads whose user is root, in the
+    profile or the image, as your user on the host; for other users, ToolHive
+    grants their subordinate UID from /etc/subuid access to the mounted
+    directories with ACLs
+  - Docker with userns-remap grants the remapped UID of the user access to the
+    mounted directories with ACLs
+  - write mounts are only refused if the UID on the host is unknown, such as
+    for users given by name, or the directories do not support ACLs`,
+	Args: cobra.NoArgs, // no args allowed
+	RunE: runtimeCheckCmdFunc,
 }
 
 var runtimeCheckTimeout int
@@ -60,9 +74,29 @@ func runtimeCheckCmdFunc(cmd *cobra.Command, _ []string) error {
 	}
 
 	fmt.Println("Container runtime is responsive")
+
+	// Diagnose how write mounts are handled with rootless runtimes and user namespaces
+	if detector, ok := rt.(runtime.UserNamespaceDetector); ok {
+		userNamespaceCtx, cancelUserNamespace := context.WithTimeout(ctx, time.Duration(runtimeCheckTimeout)*time.Second)
+		defer cancelUserNamespace()
+		info, err := detector.UserNamespace(userNamespaceCtx)
+		if err != nil {
+			return fmt.Errorf("failed to detect the user namespace of the runtime: %w", err)
+		}
+		printUserNamespaceInfo(info)
+	}
 	return nil
 }
 
+// printUserNamespaceInfo prints the user namespace setup of the runtime and how write mounts are handled
+func printUserNamespaceInfo(info runtime.UserNamespaceInfo) {
+	fmt.Printf("User namespace: %s\n", info.Mode)
+	fmt.Printf("Write mounts: %s\n", info.WriteMounts)
+	for _, warning := range info.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+}
+
 func createWithTimeout(ctx context.Context) (runtime.Runtime, error) {
 	done := make(chan struct {
 		rt  runtime.Runtime
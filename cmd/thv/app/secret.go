@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 	"syscall"
 
@@ -12,6 +13,7 @@ import (
 	"golang.org/x/term"
 
 	"github.com/stacklok/toolhive/pkg/config"
+	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/secrets"
 )
 
@@ -32,6 +34,11 @@ Run "thv secret setup" first to configure a secrets provider before using any se
 		newSecretGetCommand(),
 		newSecretDeleteCommand(),
 		newSecretListCommand(),
+		newSecretUsageCommand(),
+		newSecretExportCommand(),
+		newSecretImportCommand(),
+		newSecretRotateKeyCommand(),
+		newSecretMigrateCommand(),
 		newSecretResetKeyringCommand(),
 		newSecretProviderCommand(),
 	)
@@ -53,7 +60,9 @@ making it suitable for scripted deployments and automation.
 Valid secrets providers:
   - encrypted: Full read-write secrets provider using AES-256-GCM encryption
   - 1password: Read-only secrets provider (requires OP_SERVICE_ACCOUNT_TOKEN)
-  - none: Disables secrets functionality`,
+  - vault: HashiCorp Vault or OpenBao KV v2 secrets provider (requires VAULT_ADDR and credentials)
+  - none: Disables secrets functionality
+  - <name>: Secrets provider plugin, implemented by an executable named thv-secrets-<name> on the PATH`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(_ *cobra.Command, args []string) error {
 			provider := args[0]
@@ -75,17 +84,22 @@ configuration and ensures the selected provider initializes properly.
 Available providers:
   - %s: Stores secrets in an encrypted file using AES-256-GCM using the OS keyring
   - %s: Read-only access to 1Password secrets (requires OP_SERVICE_ACCOUNT_TOKEN environment variable)
+  - %s: Secrets in HashiCorp Vault or OpenBao (requires VAULT_ADDR and token, AppRole or Kubernetes credentials)
   - %s: Disables secrets functionality
+  - <name>: Secrets provider plugins installed as thv-secrets-<name> executables on the PATH
 
 Run this command before using any other secrets functionality.`,
-			string(secrets.EncryptedType), string(secrets.OnePasswordType), string(secrets.NoneType)), //nolint:gofmt,gci
+			string(secrets.EncryptedType), string(secrets.OnePasswordType), string(secrets.VaultType),
+			string(secrets.NoneType)), //nolint:gofmt,gci
 		Args: cobra.NoArgs,
 		RunE: runSecretsSetup,
 	}
 }
 
 func newSecretSetCommand() *cobra.Command {
-	return &cobra.Command{
+	var restartWorkloads bool
+
+	cmd := &cobra.Command{
 		Use:   "set <name>",
 		Short: "Set a secret",
 		Long: `Create or update a secret with the specified name.
@@ -110,7 +124,10 @@ Example:
 	Enter secret value (input will be hidden): _
 
 The command stores the secret securely using your configured secrets provider.
-Note that some providers (like 1Password) are read-only and do not support setting secrets.`,
+Note that some providers (like 1Password) are read-only and do not support setting secrets.
+
+Running workloads which use the secret keep its previous value until they are restarted.
+Use --restart-workloads to restart them one at a time after the secret is set.`,
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			name := args[0]
@@ -180,8 +197,21 @@ Note that some providers (like 1Password) are read-only and do not support setti
 				return
 			}
 			fmt.Printf("Secret %s set successfully\n", name)
+
+			if err := restartSecretConsumers(ctx, name, restartWorkloads); err != nil {
+				if restartWorkloads {
+					fmt.Fprintf(os.Stderr, "Failed to restart workloads using secret %s: %v\n", name, err)
+				} else {
+					logger.Debugf("Failed to find workloads using secret %s: %v", name, err)
+				}
+			}
 		},
 	}
+
+	cmd.Flags().BoolVar(&restartWorkloads, "restart-workloads", false,
+		"Restart the running workloads which use the secret, one at a time")
+
+	return cmd
 }
 
 func newSecretGetCommand() *cobra.Command {
@@ -330,9 +360,11 @@ encrypts and decrypts secrets when using the 'encrypted' secrets provider.
 
 Use this command if:
   - You've forgotten your keyring password
-  - You want to change your encryption password
   - Your keyring has become corrupted
 
+To change your encryption password without losing your secrets, use
+"thv secret rotate-key" instead.
+
 Warning: Resetting the keyring password makes any existing encrypted secrets
 inaccessible unless you remember the previous password. You will need to set up
 your secrets again after resetting.
@@ -381,13 +413,22 @@ ToolHive Secrets Setup
 Please select a secrets provider:
   %s - Store secrets in an encrypted file (full read/write)
   %s - Use 1Password for secrets (read-only, requires service account)
+  %s - Use HashiCorp Vault or OpenBao for secrets (KV v2 and dynamic secrets)
   %s - Disable secrets functionality
-`, string(secrets.EncryptedType), string(secrets.OnePasswordType), string(secrets.NoneType))
+`, string(secrets.EncryptedType), string(secrets.OnePasswordType), string(secrets.VaultType), string(secrets.NoneType))
+
+	plugins := secrets.ListPlugins()
+	for _, plugin := range plugins {
+		fmt.Printf("  %s - Use the %s%s secrets provider plugin\n", plugin, secrets.PluginExecutablePrefix, plugin)
+	}
+	choices := append([]string{
+		string(secrets.EncryptedType), string(secrets.OnePasswordType), string(secrets.VaultType),
+		string(secrets.NoneType),
+	}, plugins...)
 
 	var providerType secrets.ProviderType
 	for {
-		fmt.Printf("\nEnter provider (%s/%s/%s): ",
-			string(secrets.EncryptedType), string(secrets.OnePasswordType), string(secrets.NoneType))
+		fmt.Printf("\nEnter provider (%s): ", strings.Join(choices, "/"))
 		input, err := reader.ReadString('\n')
 		if err != nil {
 			return fmt.Errorf("failed to read input: %w", err)
@@ -399,12 +440,16 @@ Please select a secrets provider:
 			providerType = secrets.EncryptedType
 		case string(secrets.OnePasswordType):
 			providerType = secrets.OnePasswordType
+		case string(secrets.VaultType):
+			providerType = secrets.VaultType
 		case string(secrets.NoneType):
 			providerType = secrets.NoneType
 		default:
-			fmt.Printf("Invalid provider. Please enter '%s', '%s', or '%s'.\n",
-				string(secrets.EncryptedType), string(secrets.OnePasswordType), string(secrets.NoneType))
-			continue
+			if !slices.Contains(plugins, input) {
+				fmt.Printf("Invalid provider. Please enter one of: %s.\n", strings.Join(choices, ", "))
+				continue
+			}
+			providerType = secrets.ProviderType(input)
 		}
 		break
 	}
@@ -426,6 +471,23 @@ To use 1Password as your secrets provider, you need to:
 3. Set the OP_SERVICE_ACCOUNT_TOKEN environment variable
 
 For more information, visit: https://developer.1password.com/docs/service-accounts/`)
+	case secrets.VaultType:
+		fmt.Println(`Setting up Vault secrets provider...
+
+To use HashiCorp Vault or OpenBao as your secrets provider, set VAULT_ADDR and one of:
+- Token auth: VAULT_TOKEN
+- AppRole auth: TOOLHIVE_VAULT_ROLE_ID and TOOLHIVE_VAULT_SECRET_ID
+- Kubernetes auth: TOOLHIVE_VAULT_ROLE (uses the pod's service account token)
+
+Optional settings:
+- VAULT_NAMESPACE and VAULT_CACERT
+- TOOLHIVE_VAULT_AUTH_MOUNT: mount path of the AppRole or Kubernetes auth method
+- TOOLHIVE_VAULT_KV_MOUNT: mount path of the KV v2 engine (default: secret)
+- TOOLHIVE_VAULT_PATH_PREFIX: path of ToolHive secrets in the KV v2 engine (default: toolhive)
+
+Secrets are stored in the "value" field of <kv mount>/<path prefix>/<name>.
+Use <name>#<field> to read another field, and vault:<path>#<field> to read any
+Vault path, such as dynamic database credentials, whose leases are renewed.`)
 	case secrets.NoneType:
 		fmt.Println(`Setting up none secrets provider...
 Secrets functionality will be disabled.
@@ -434,6 +496,8 @@ No secrets will be stored or retrieved.`)
 		fmt.Println(`Setting up environment variable secrets provider...
 Secrets will be read from environment variables with the TOOLHIVE_SECRET_ prefix.
 This provider is read-only and suitable for CI/CD and containerized environments.`)
+	default:
+		fmt.Printf("Setting up the %s%s secrets provider plugin...\n", secrets.PluginExecutablePrefix, providerType)
 	}
 
 	// SetSecretsProvider will handle validation and configuration
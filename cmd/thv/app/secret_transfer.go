@@ -0,0 +1,283 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/stacklok/toolhive/pkg/config"
+	"github.com/stacklok/toolhive/pkg/secrets"
+)
+
+var (
+	secretExportPassphraseFile string
+	secretExportForce          bool
+	secretImportPassphraseFile string
+	secretImportOverwrite      bool
+	secretRotatePasswordFile   string
+	secretMigrateOverwrite     bool
+)
+
+func newSecretExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <file>",
+		Short: "Export secrets to a passphrase-protected bundle",
+		Long: `Export all the secrets of the configured secrets provider to a bundle file.
+
+The bundle is encrypted with AES-256-GCM using a key derived from a passphrase, rather
+than the password in the OS keyring, so it can be imported on another machine with
+"thv secret import". The passphrase is prompted for, or read from --passphrase-file.
+
+The secrets provider must support listing and reading secrets.`,
+		Args: cobra.ExactArgs(1),
+		RunE: secretExportCmdFunc,
+	}
+
+	cmd.Flags().StringVar(&secretExportPassphraseFile, "passphrase-file", "",
+		"Read the passphrase of the bundle from a file instead of prompting for it")
+	cmd.Flags().BoolVar(&secretExportForce, "force", false, "Overwrite the bundle file if it already exists")
+
+	return cmd
+}
+
+func newSecretImportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import secrets from a passphrase-protected bundle",
+		Long: `Import the secrets of a bundle created by "thv secret export" into the configured secrets provider.
+
+Secrets which already exist in the provider are skipped, unless --overwrite is set.
+The passphrase is prompted for, or read from --passphrase-file.`,
+		Args: cobra.ExactArgs(1),
+		RunE: secretImportCmdFunc,
+	}
+
+	cmd.Flags().StringVar(&secretImportPassphraseFile, "passphrase-file", "",
+		"Read the passphrase of the bundle from a file instead of prompting for it")
+	cmd.Flags().BoolVar(&secretImportOverwrite, "overwrite", false, "Overwrite secrets which already exist")
+
+	return cmd
+}
+
+func newSecretRotateKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate-key",
+		Short: "Re-encrypt secrets with a new password",
+		Long: `Re-encrypt the secrets of the encrypted secrets provider with a new password.
+
+The secrets are decrypted with the password stored in the OS keyring, encrypted with a key
+derived from the new password, and the new password replaces the previous one in the keyring.
+The new password is prompted for, or read from --password-file.
+
+This command only works with the 'encrypted' secrets provider.`,
+		Args: cobra.NoArgs,
+		RunE: secretRotateKeyCmdFunc,
+	}
+
+	cmd.Flags().StringVar(&secretRotatePasswordFile, "password-file", "",
+		"Read the new password from a file instead of prompting for it")
+
+	return cmd
+}
+
+func newSecretMigrateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate <provider>",
+		Short: "Migrate secrets to another secrets provider",
+		Long: `Copy all the secrets of the configured secrets provider to another secrets provider,
+and configure ToolHive to use it.
+
+The secrets are not removed from the previous provider. Secrets which already exist in the
+new provider are skipped, unless --overwrite is set. The configured provider must support
+listing and reading secrets, and the new provider must support writing them.`,
+		Args: cobra.ExactArgs(1),
+		RunE: secretMigrateCmdFunc,
+	}
+
+	cmd.Flags().BoolVar(&secretMigrateOverwrite, "overwrite", false,
+		"Overwrite secrets which already exist in the new provider")
+
+	return cmd
+}
+
+func secretExportCmdFunc(cmd *cobra.Command, args []string) error {
+	path := filepath.Clean(args[0])
+	if !secretExportForce {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("file %s already exists, use --force to overwrite it", path)
+		}
+	}
+
+	manager, err := getSecretsManager()
+	if err != nil {
+		return err
+	}
+	values, err := secrets.ExportSecrets(cmd.Context(), manager)
+	if err != nil {
+		return fmt.Errorf("failed to export secrets: %w", err)
+	}
+
+	passphrase, err := readPassphrase(secretExportPassphraseFile, "Enter a passphrase for the bundle", true)
+	if err != nil {
+		return err
+	}
+	bundle, err := secrets.EncryptBundle(values, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt secrets: %w", err)
+	}
+	if err := os.WriteFile(path, bundle, 0600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	fmt.Printf("Exported %d secrets to %s\n", len(values), path)
+	return nil
+}
+
+func secretImportCmdFunc(cmd *cobra.Command, args []string) error {
+	bundle, err := os.ReadFile(filepath.Clean(args[0]))
+	if err != nil {
+		return fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	passphrase, err := readPassphrase(secretImportPassphraseFile, "Enter the passphrase of the bundle", false)
+	if err != nil {
+		return err
+	}
+	values, err := secrets.DecryptBundle(bundle, passphrase)
+	if err != nil {
+		return err
+	}
+
+	manager, err := getSecretsManager()
+	if err != nil {
+		return err
+	}
+	result, err := secrets.ImportSecrets(cmd.Context(), manager, values, secretImportOverwrite)
+	if result != nil {
+		printTransferResult(result)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to import secrets: %w", err)
+	}
+	return nil
+}
+
+func secretRotateKeyCmdFunc(_ *cobra.Command, _ []string) error {
+	cfg := config.NewDefaultProvider().GetConfig()
+	providerType, err := cfg.Secrets.GetProviderType()
+	if err != nil {
+		return err
+	}
+	if providerType != secrets.EncryptedType {
+		return fmt.Errorf("the %s secrets provider does not use an encryption key, only the %s provider does",
+			providerType, secrets.EncryptedType)
+	}
+
+	password, err := readPassphrase(secretRotatePasswordFile, "Enter the new password", true)
+	if err != nil {
+		return err
+	}
+	if err := secrets.RotateSecretsPassword(password); err != nil {
+		return fmt.Errorf("failed to rotate key: %w", err)
+	}
+
+	fmt.Println("Secrets re-encrypted with the new password")
+	return nil
+}
+
+func secretMigrateCmdFunc(cmd *cobra.Command, args []string) error {
+	target := secrets.ProviderType(args[0])
+
+	cfg := config.NewDefaultProvider().GetConfig()
+	current, err := cfg.Secrets.GetProviderType()
+	if err != nil {
+		return err
+	}
+	if current == target {
+		return fmt.Errorf("the %s secrets provider is already configured", target)
+	}
+
+	source, err := getSecretsManager()
+	if err != nil {
+		return err
+	}
+	destination, err := secrets.CreateSecretProvider(target)
+	if err != nil {
+		if errors.Is(err, secrets.ErrUnknownManagerType) {
+			return fmt.Errorf("invalid secrets provider type: %s", target)
+		}
+		return fmt.Errorf("failed to create %s secrets provider: %w", target, err)
+	}
+
+	result, err := secrets.MigrateSecrets(cmd.Context(), source, destination, secretMigrateOverwrite)
+	if result != nil {
+		printTransferResult(result)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to migrate secrets from %s to %s: %w", current, target, err)
+	}
+
+	if err := SetSecretsProvider(target); err != nil {
+		return err
+	}
+	fmt.Printf("Secrets provider changed from %s to %s. The secrets remain in the %s provider until you remove them.\n",
+		current, target, current)
+	return nil
+}
+
+func printTransferResult(result *secrets.TransferResult) {
+	fmt.Printf("Imported %d secrets\n", len(result.Imported))
+	if len(result.Skipped) > 0 {
+		fmt.Printf("Skipped %d existing secrets (use --overwrite to replace them): %s\n",
+			len(result.Skipped), strings.Join(result.Skipped, ", "))
+	}
+}
+
+// readPassphrase reads a passphrase from a file, or prompts for it on the terminal if file is empty.
+// If confirm is set, the passphrase must be entered twice.
+func readPassphrase(file, prompt string, confirm bool) (string, error) {
+	if file != "" {
+		data, err := os.ReadFile(filepath.Clean(file))
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase file: %w", err)
+		}
+		passphrase := strings.TrimRight(string(data), "\r\n")
+		if passphrase == "" {
+			return "", errors.New("passphrase cannot be empty")
+		}
+		return passphrase, nil
+	}
+
+	if !term.IsTerminal(int(syscall.Stdin)) {
+		return "", errors.New("cannot prompt for a passphrase without a terminal, use a passphrase file instead")
+	}
+
+	fmt.Printf("%s (input will be hidden): ", prompt)
+	passphrase, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Println("")
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if len(passphrase) == 0 {
+		return "", errors.New("passphrase cannot be empty")
+	}
+
+	if confirm {
+		fmt.Print("Confirm (input will be hidden): ")
+		confirmation, err := term.ReadPassword(int(syscall.Stdin))
+		fmt.Println("")
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		if string(confirmation) != string(passphrase) {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return string(passphrase), nil
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/secrets"
+	"github.com/stacklok/toolhive/pkg/workloads"
+)
+
+var (
+	secretUsageFormat  string
+	secretUsageRestart bool
+)
+
+func newSecretUsageCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "usage <name>",
+		Short: "Show the workloads which use a secret",
+		Long: `Show the workloads which use a secret, based on the --secret parameters they were started with.
+
+Secrets are passed to workloads when they start, so running workloads keep using the value
+a secret had when they were started. For providers which version their secrets, such as vault,
+workloads started with a previous version of the secret are marked as stale.
+
+Use --restart to restart the running workloads which were started with a previous or unknown
+version of the secret, one at a time.`,
+		Args: cobra.ExactArgs(1),
+		RunE: secretUsageCmdFunc,
+	}
+
+	cmd.Flags().StringVar(&secretUsageFormat, "format", FormatText, "Output format (json or text)")
+	cmd.Flags().BoolVar(&secretUsageRestart, "restart", false,
+		"Restart the running workloads which were started with a previous or unknown version of the secret")
+
+	return cmd
+}
+
+func secretUsageCmdFunc(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	name := args[0]
+
+	if secretUsageFormat != FormatText && secretUsageFormat != FormatJSON {
+		return fmt.Errorf("invalid format %q, must be one of: %s, %s", secretUsageFormat, FormatText, FormatJSON)
+	}
+
+	manager, err := getSecretsManager()
+	if err != nil {
+		return fmt.Errorf("failed to create secrets manager: %w", err)
+	}
+	version, err := secrets.GetSecretVersion(ctx, manager, name)
+	if err != nil {
+		logger.Warnf("Failed to get the current version of secret %s: %v", name, err)
+	}
+
+	workloadManager, err := workloads.NewManager(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create workload manager: %w", err)
+	}
+	consumers, err := workloadManager.ListSecretConsumers(ctx, name, version)
+	if err != nil {
+		return fmt.Errorf("failed to list workloads using secret %s: %w", name, err)
+	}
+
+	if secretUsageRestart {
+		var names []string
+		for _, consumer := range consumers {
+			if consumer.IsRunning() && (consumer.Stale || consumer.Version == "") {
+				names = append(names, consumer.Workload)
+			}
+		}
+		if len(names) == 0 {
+			fmt.Printf("No running workloads use a previous version of secret %s\n", name)
+			return nil
+		}
+		return rollingRestartWorkloads(ctx, workloadManager, names)
+	}
+
+	if secretUsageFormat == FormatJSON {
+		if consumers == nil {
+			consumers = []workloads.SecretConsumer{}
+		}
+		jsonData, err := json.MarshalIndent(consumers, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	if len(consumers) == 0 {
+		fmt.Printf("No workloads use secret %s\n", name)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "WORKLOAD\tTARGET\tSTATUS\tVERSION\tSTALE")
+	for _, consumer := range consumers {
+		consumerVersion := consumer.Version
+		if consumerVersion == "" {
+			consumerVersion = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\n",
+			consumer.Workload, consumer.Target, consumer.Status, consumerVersion, consumer.Stale)
+	}
+	if err := w.Flush(); err != nil {
+		logger.Errorf("Warning: Failed to flush tabwriter: %v", err)
+	}
+	return nil
+}
+
+// restartSecretConsumers reports the running workloads which still use the previous value of a
+// secret that was just changed, and restarts them one at a time if restart is set.
+func restartSecretConsumers(ctx context.Context, name string, restart bool) error {
+	workloadManager, err := workloads.NewManager(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create workload manager: %w", err)
+	}
+	consumers, err := workloadManager.ListSecretConsumers(ctx, name, "")
+	if err != nil {
+		return fmt.Errorf("failed to list workloads using secret %s: %w", name, err)
+	}
+
+	var names []string
+	for _, consumer := range consumers {
+		if consumer.IsRunning() {
+			names = append(names, consumer.Workload)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	if !restart {
+		fmt.Printf("The following running workloads use the previous value of secret %s until they are restarted: %s\n",
+			name, strings.Join(names, ", "))
+		fmt.Printf("Run 'thv secret usage %s --restart' to restart them\n", name)
+		return nil
+	}
+	return rollingRestartWorkloads(ctx, workloadManager, names)
+}
+
+func rollingRestartWorkloads(ctx context.Context, workloadManager workloads.Manager, names []string) error {
+	group, err := workloadManager.RollingRestartWorkloads(ctx, names)
+	if err != nil {
+		return fmt.Errorf("failed to restart workloads: %w", err)
+	}
+	if err := group.Wait(); err != nil {
+		return err
+	}
+	fmt.Printf("Restarted workloads: %s\n", strings.Join(names, ", "))
+	return nil
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/usage"
+)
+
+var (
+	usageFormat    string
+	usagePrincipal string
+	usageSince     string
+	usageUntil     string
+	usageMonth     bool
+	usageTotal     bool
+)
+
+func newUsageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "usage [workload-name]",
+		Short: "Show MCP usage per client identity",
+		Long: `Show the tool calls, bytes and time accounted per client identity per MCP server per day.
+
+Usage is only accounted for MCP servers started with --enable-usage, --usage-config or a usage quota.
+Days are UTC dates in the YYYY-MM-DD format.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: usageCmdFunc,
+	}
+
+	cmd.Flags().StringVar(&usageFormat, "format", FormatText, "Output format (json or text)")
+	cmd.Flags().StringVar(&usagePrincipal, "principal", "", "Only show the usage of this client identity")
+	cmd.Flags().StringVar(&usageSince, "since", "", "Only show usage on or after this date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&usageUntil, "until", "", "Only show usage on or before this date (YYYY-MM-DD)")
+	cmd.Flags().BoolVar(&usageMonth, "month", false, "Only show usage of the current month")
+	cmd.Flags().BoolVar(&usageTotal, "total", false, "Show the total usage over the selected days instead of daily usage")
+	cmd.MarkFlagsMutuallyExclusive("month", "since")
+
+	return cmd
+}
+
+func usageCmdFunc(cmd *cobra.Command, args []string) error {
+	if usageFormat != FormatText && usageFormat != FormatJSON {
+		return fmt.Errorf("invalid format %q, must be one of: %s, %s", usageFormat, FormatText, FormatJSON)
+	}
+
+	filter := usage.Filter{
+		Principal: usagePrincipal,
+		Since:     usageSince,
+		Until:     usageUntil,
+	}
+	if len(args) == 1 {
+		filter.Workload = args[0]
+	}
+	if usageMonth {
+		now := time.Now().UTC()
+		filter.Since = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Format(usage.DateFormat)
+	}
+
+	store, err := usage.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to create usage store: %v", err)
+	}
+
+	records, err := usage.Query(cmd.Context(), store, filter)
+	if err != nil {
+		return fmt.Errorf("failed to read usage: %v", err)
+	}
+	if usageTotal {
+		records = usage.Summarize(records)
+	}
+
+	if usageFormat == FormatJSON {
+		jsonData, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %v", err)
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+
+	if len(records) == 0 {
+		fmt.Println("No usage found")
+		return nil
+	}
+	printUsageText(records)
+	return nil
+}
+
+// printUsageText prints usage records in text format
+func printUsageText(records []usage.Record) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	if usageTotal {
+		fmt.Fprintln(w, "WORKLOAD\tPRINCIPAL\tREQUESTS\tTOOL CALLS\tBYTES IN\tBYTES OUT\tTIME")
+	} else {
+		fmt.Fprintln(w, "DATE\tWORKLOAD\tPRINCIPAL\tREQUESTS\tTOOL CALLS\tBYTES IN\tBYTES OUT\tTIME")
+	}
+
+	for _, r := range records {
+		if !usageTotal {
+			fmt.Fprintf(w, "%s\t", r.Date)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%s\n",
+			r.Workload,
+			r.Principal,
+			r.Requests,
+			r.ToolCalls,
+			r.BytesIn,
+			r.BytesOut,
+			(time.Duration(r.DurationMs) * time.Millisecond).String(),
+		)
+	}
+
+	if err := w.Flush(); err != nil {
+		logger.Errorf("Warning: Failed to flush tabwriter: %v", err)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/stacklok/toolhive/cmd/thv/app"
 	"github.com/stacklok/toolhive/pkg/client"
 	"github.com/stacklok/toolhive/pkg/container"
+	"github.com/stacklok/toolhive/pkg/container/process"
 	"github.com/stacklok/toolhive/pkg/container/runtime"
 	"github.com/stacklok/toolhive/pkg/lockfile"
 	"github.com/stacklok/toolhive/pkg/logger"
@@ -19,6 +20,9 @@ import (
 )
 
 func main() {
+	// Run as the sandbox helper of the process runtime if requested; this does not return
+	process.HandleSandboxHelper()
+
 	// Initialize the logger
 	logger.Initialize()
 
@@ -225,8 +225,9 @@ func (b *ServerBuilder) setupDefaultRoutes(r *chi.Mux) {
 		"/api/v1beta/registry":  v1.RegistryRouter(),
 		"/api/v1beta/discovery": v1.DiscoveryRouter(),
 		"/api/v1beta/clients":   v1.ClientRouter(b.clientManager, b.workloadManager, b.groupManager),
-		"/api/v1beta/secrets":   v1.SecretsRouter(),
+		"/api/v1beta/secrets":   v1.SecretsRouter(b.workloadManager),
 		"/api/v1beta/groups":    v1.GroupsRouter(b.groupManager, b.workloadManager, b.clientManager),
+		"/api/v1beta/usage":     v1.UsageRouter(),
 	}
 
 	// Only mount docs router if enabled
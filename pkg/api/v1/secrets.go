@@ -12,6 +12,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/config"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/secrets"
+	"github.com/stacklok/toolhive/pkg/workloads"
 )
 
 const (
@@ -22,16 +23,20 @@ const (
 // SecretsRoutes defines the routes for the secrets API.
 type SecretsRoutes struct {
 	configProvider config.Provider
+	// workloadManager finds and restarts the workloads which use a secret
+	workloadManager workloads.Manager
 }
 
 // NewSecretsRoutes creates a new SecretsRoutes with the default config provider
-func NewSecretsRoutes() *SecretsRoutes {
+func NewSecretsRoutes(workloadManager workloads.Manager) *SecretsRoutes {
 	return &SecretsRoutes{
-		configProvider: config.NewDefaultProvider(),
+		configProvider:  config.NewDefaultProvider(),
+		workloadManager: workloadManager,
 	}
 }
 
-// NewSecretsRoutesWithProvider creates a new SecretsRoutes with a custom config provider
+// NewSecretsRoutesWithProvider creates a new SecretsRoutes with a custom config provider.
+// The routes have no workload manager, so updating a secret does not look up its consumers.
 func NewSecretsRoutesWithProvider(provider config.Provider) *SecretsRoutes {
 	return &SecretsRoutes{
 		configProvider: provider,
@@ -39,8 +44,8 @@ func NewSecretsRoutesWithProvider(provider config.Provider) *SecretsRoutes {
 }
 
 // SecretsRouter creates a new router for the secrets API.
-func SecretsRouter() http.Handler {
-	routes := NewSecretsRoutes()
+func SecretsRouter(workloadManager workloads.Manager) http.Handler {
+	routes := NewSecretsRoutes(workloadManager)
 	return secretsRouterWithRoutes(routes)
 }
 
@@ -57,6 +62,8 @@ func secretsRouterWithRoutes(routes *SecretsRoutes) http.Handler {
 			r.Get("/", routes.listSecrets)
 			r.Post("/", routes.createSecret)
 			r.Put("/{key}", routes.updateSecret)
+			r.Get("/{key}/usage", routes.getSecretUsage)
+			r.Post("/{key}/restart", routes.restartSecretConsumers)
 			r.Delete("/{key}", routes.deleteSecret)
 		})
 	})
@@ -95,14 +102,21 @@ func (s *SecretsRoutes) setupSecretsProvider(w http.ResponseWriter, r *http.Requ
 		providerType = secrets.OnePasswordType
 	case string(secrets.NoneType):
 		providerType = secrets.NoneType
+	case string(secrets.VaultType):
+		providerType = secrets.VaultType
 	case "":
 		http.Error(w, "Provider type cannot be empty", http.StatusBadRequest)
 		return
 	default:
-		http.Error(w, fmt.Sprintf("Invalid secrets provider type: %s (valid types: %s, %s, %s)",
-			req.ProviderType, string(secrets.EncryptedType), string(secrets.OnePasswordType), string(secrets.NoneType)),
-			http.StatusBadRequest)
-		return
+		if !secrets.IsPluginProviderType(secrets.ProviderType(req.ProviderType)) {
+			http.Error(w, fmt.Sprintf("Invalid secrets provider type: %s (valid types: %s, %s, %s, %s, "+
+				"or a plugin installed as %s<name>)",
+				req.ProviderType, string(secrets.EncryptedType), string(secrets.OnePasswordType), string(secrets.NoneType),
+				string(secrets.VaultType), secrets.PluginExecutablePrefix),
+				http.StatusBadRequest)
+			return
+		}
+		providerType = secrets.ProviderType(req.ProviderType)
 	}
 
 	// Check current secrets provider configuration for appropriate messaging
@@ -459,11 +473,68 @@ func (s *SecretsRoutes) updateSecret(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
 	resp := updateSecretResponse{
 		Key:     key,
 		Message: "Secret updated successfully",
 	}
+
+	// Running workloads keep the previous value until they are restarted. The consumers are
+	// only looked up when the routes have a workload manager.
+	if s.workloadManager != nil {
+		consumers, err := s.workloadManager.ListSecretConsumers(r.Context(), key, "")
+		if err != nil {
+			logger.Warnf("Failed to list workloads using secret %s: %v", key, err)
+		}
+		for _, consumer := range consumers {
+			if consumer.IsRunning() {
+				resp.Workloads = append(resp.Workloads, consumer.Workload)
+			}
+		}
+	}
+	if req.RestartWorkloads && len(resp.Workloads) > 0 {
+		if _, err := s.workloadManager.RollingRestartWorkloads(r.Context(), resp.Workloads); err != nil {
+			logger.Errorf("Failed to restart workloads using secret %s: %v", key, err)
+			http.Error(w, "Secret updated, but failed to restart workloads", http.StatusInternalServerError)
+			return
+		}
+		resp.Message = "Secret updated successfully, restarting workloads"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// getSecretUsage
+//
+//	@Summary		Get the workloads using a secret
+//	@Description	Get the workloads which were started with a secret, and whether they use a previous version of it
+//	@Tags			secrets
+//	@Produce		json
+//	@Param			key	path		string	true	"Secret key"
+//	@Success		200	{object}	secretUsageResponse
+//	@Failure		404	{string}	string	"Not Found - Provider not setup"
+//	@Failure		500	{string}	string	"Internal Server Error"
+//	@Router			/api/v1beta/secrets/default/keys/{key}/usage [get]
+func (s *SecretsRoutes) getSecretUsage(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	consumers, version, ok := s.listSecretConsumers(w, r, key)
+	if !ok {
+		return
+	}
+
+	if consumers == nil {
+		consumers = []workloads.SecretConsumer{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	resp := secretUsageResponse{
+		Key:       key,
+		Version:   version,
+		Workloads: consumers,
+	}
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		logger.Errorf("Failed to encode response: %v", err)
 		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
@@ -471,6 +542,89 @@ func (s *SecretsRoutes) updateSecret(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// restartSecretConsumers
+//
+//	@Summary		Restart the workloads using a previous version of a secret
+//	@Description	Restart the running workloads which were started with a previous or unknown version of a secret, one at a time
+//	@Tags			secrets
+//	@Produce		json
+//	@Param			key	path		string	true	"Secret key"
+//	@Success		202	{object}	restartSecretConsumersResponse
+//	@Failure		404	{string}	string	"Not Found - Provider not setup"
+//	@Failure		500	{string}	string	"Internal Server Error"
+//	@Router			/api/v1beta/secrets/default/keys/{key}/restart [post]
+func (s *SecretsRoutes) restartSecretConsumers(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	consumers, _, ok := s.listSecretConsumers(w, r, key)
+	if !ok {
+		return
+	}
+
+	resp := restartSecretConsumersResponse{Workloads: []string{}}
+	for _, consumer := range consumers {
+		if consumer.IsRunning() && (consumer.Stale || consumer.Version == "") {
+			resp.Workloads = append(resp.Workloads, consumer.Workload)
+		}
+	}
+
+	if len(resp.Workloads) > 0 {
+		if _, err := s.workloadManager.RollingRestartWorkloads(r.Context(), resp.Workloads); err != nil {
+			logger.Errorf("Failed to restart workloads using secret %s: %v", key, err)
+			http.Error(w, "Failed to restart workloads", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Errorf("Failed to encode response: %v", err)
+		return
+	}
+}
+
+// listSecretConsumers returns the workloads using a secret and the current version of the secret.
+// It writes an error response and returns false if they cannot be listed.
+func (s *SecretsRoutes) listSecretConsumers(
+	w http.ResponseWriter,
+	r *http.Request,
+	key string,
+) ([]workloads.SecretConsumer, string, bool) {
+	if key == "" {
+		http.Error(w, "Secret key is required", http.StatusBadRequest)
+		return nil, "", false
+	}
+	if s.workloadManager == nil {
+		logger.Errorf("Cannot list workloads using secret %s: no workload manager", key)
+		http.Error(w, "Workload manager not available", http.StatusInternalServerError)
+		return nil, "", false
+	}
+
+	provider, err := s.getSecretsManager()
+	if err != nil {
+		if errors.Is(err, secrets.ErrSecretsNotSetup) {
+			http.Error(w, "Secrets provider not setup", http.StatusNotFound)
+			return nil, "", false
+		}
+		logger.Errorf("Failed to get secrets manager: %v", err)
+		http.Error(w, "Failed to access secrets provider", http.StatusInternalServerError)
+		return nil, "", false
+	}
+
+	version, err := secrets.GetSecretVersion(r.Context(), provider, key)
+	if err != nil {
+		logger.Warnf("Failed to get the current version of secret %s: %v", key, err)
+	}
+
+	consumers, err := s.workloadManager.ListSecretConsumers(r.Context(), key, version)
+	if err != nil {
+		logger.Errorf("Failed to list workloads using secret %s: %v", key, err)
+		http.Error(w, "Failed to list workloads using secret", http.StatusInternalServerError)
+		return nil, "", false
+	}
+	return consumers, version, true
+}
+
 // deleteSecret
 //
 //	@Summary		Delete a secret
@@ -544,7 +698,7 @@ func (s *SecretsRoutes) getSecretsManager() (secrets.Provider, error) {
 //
 //	@Description	Request to setup a secrets provider
 type setupSecretsRequest struct {
-	// Type of the secrets provider (encrypted, 1password, none)
+	// Type of the secrets provider (encrypted, 1password, vault, none, or the name of a secrets provider plugin)
 	ProviderType string `json:"provider_type"`
 	// Password for encrypted provider (optional, can be set via environment variable)
 	// TODO Review environment variable for this
@@ -633,6 +787,8 @@ type createSecretResponse struct {
 type updateSecretRequest struct {
 	// New secret value
 	Value string `json:"value"`
+	// Restart the running workloads which use the secret, one at a time
+	RestartWorkloads bool `json:"restart_workloads,omitempty"`
 }
 
 // updateSecretResponse represents the response for updating a secret
@@ -643,4 +799,26 @@ type updateSecretResponse struct {
 	Key string `json:"key"`
 	// Success message
 	Message string `json:"message"`
+	// Running workloads which use the previous value of the secret until they are restarted
+	Workloads []string `json:"workloads,omitempty"`
+}
+
+// secretUsageResponse represents the response for getting the workloads using a secret
+//
+//	@Description	Response containing the workloads using a secret
+type secretUsageResponse struct {
+	// Secret key
+	Key string `json:"key"`
+	// Current version of the secret, if the provider versions its secrets
+	Version string `json:"version,omitempty"`
+	// Workloads which were started with the secret
+	Workloads []workloads.SecretConsumer `json:"workloads"`
+}
+
+// restartSecretConsumersResponse represents the response for restarting the workloads using a secret
+//
+//	@Description	Response containing the workloads being restarted
+type restartSecretConsumersResponse struct {
+	// Names of the workloads being restarted
+	Workloads []string `json:"workloads"`
 }
@@ -14,10 +14,15 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/stacklok/toolhive/pkg/config"
+	rt "github.com/stacklok/toolhive/pkg/container/runtime"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/secrets"
+	"github.com/stacklok/toolhive/pkg/workloads"
+	workloadsmocks "github.com/stacklok/toolhive/pkg/workloads/mocks"
 )
 
 func TestSecretsRouter(t *testing.T) {
@@ -107,7 +112,7 @@ func TestSetupSecretsProvider_InvalidRequests(t *testing.T) {
 				ProviderType: "invalid",
 			},
 			expectedCode: http.StatusBadRequest,
-			errorMessage: "Invalid secrets provider type: invalid (valid types: encrypted, 1password, none)",
+			errorMessage: "Invalid secrets provider type: invalid (valid types: encrypted, 1password, none, vault, or a plugin installed as thv-secrets-<name>)",
 		},
 		{
 			name:         "invalid json body",
@@ -579,6 +584,69 @@ func TestRouterIntegration(t *testing.T) {
 	})
 }
 
+func TestSecretUsage(t *testing.T) {
+	t.Parallel()
+	logger.Initialize()
+
+	configPath := filepath.Join(t.TempDir(), "toolhive", "config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0755))
+	configProvider := config.NewPathProvider(configPath)
+	require.NoError(t, configProvider.UpdateConfig(func(c *config.Config) {
+		c.Secrets.ProviderType = string(secrets.NoneType)
+		c.Secrets.SetupCompleted = true
+	}))
+
+	consumers := []workloads.SecretConsumer{
+		{Workload: "github", Target: "GITHUB_TOKEN", Status: rt.WorkloadStatusRunning},
+		{Workload: "github-readonly", Target: "GITHUB_TOKEN", Status: rt.WorkloadStatusStopped},
+	}
+
+	ctrl := gomock.NewController(t)
+	mockManager := workloadsmocks.NewMockManager(ctrl)
+	mockManager.EXPECT().ListSecretConsumers(gomock.Any(), "github-token", "").Return(consumers, nil).Times(2)
+	mockManager.EXPECT().RollingRestartWorkloads(gomock.Any(), []string{"github"}).Return(&errgroup.Group{}, nil)
+
+	routes := NewSecretsRoutesWithProvider(configProvider)
+	routes.workloadManager = mockManager
+	router := secretsRouterWithRoutes(routes)
+
+	req := httptest.NewRequest(http.MethodGet, "/default/keys/github-token/usage", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var usageResp secretUsageResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&usageResp))
+	assert.Equal(t, secretUsageResponse{Key: "github-token", Workloads: consumers}, usageResp)
+
+	// Only running workloads are restarted
+	req = httptest.NewRequest(http.MethodPost, "/default/keys/github-token/restart", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var restartResp restartSecretConsumersResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&restartResp))
+	assert.Equal(t, []string{"github"}, restartResp.Workloads)
+}
+
+func TestSecretUsage_WithoutWorkloadManager(t *testing.T) {
+	t.Parallel()
+	logger.Initialize()
+
+	configPath := filepath.Join(t.TempDir(), "toolhive", "config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0755))
+	configProvider := config.NewPathProvider(configPath)
+	router := secretsRouterWithRoutes(NewSecretsRoutesWithProvider(configProvider))
+
+	req := httptest.NewRequest(http.MethodGet, "/default/keys/github-token/usage", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "Workload manager not available")
+}
+
 // Test for default constant
 func TestConstants(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,96 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/usage"
+)
+
+// UsageRoutes defines the routes for usage accounting.
+type UsageRoutes struct {
+	// store overrides the default usage store, used in tests
+	store usage.Store
+}
+
+// UsageRouter creates a new router for usage accounting.
+func UsageRouter() http.Handler {
+	routes := UsageRoutes{}
+
+	r := chi.NewRouter()
+	r.Get("/", routes.listUsage)
+	return r
+}
+
+func (s *UsageRoutes) getStore() (usage.Store, error) {
+	if s.store != nil {
+		return s.store, nil
+	}
+	return usage.NewStore()
+}
+
+// listUsage
+//
+//	@Summary		List usage
+//	@Description	Get the tool calls, bytes and time accounted per client identity per workload per day
+//	@Tags			usage
+//	@Produce		json
+//	@Param			workload	query		string	false	"Only return the usage of this workload"
+//	@Param			principal	query		string	false	"Only return the usage of this client identity"
+//	@Param			since		query		string	false	"Only return usage on or after this UTC date (YYYY-MM-DD)"
+//	@Param			until		query		string	false	"Only return usage on or before this UTC date (YYYY-MM-DD)"
+//	@Param			total		query		bool	false	"Aggregate the usage over the selected days"
+//	@Success		200			{object}	usageListResponse
+//	@Failure		400			{string}	string	"Bad Request"
+//	@Failure		500			{string}	string	"Internal Server Error"
+//	@Router			/api/v1beta/usage [get]
+func (s *UsageRoutes) listUsage(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := usage.Filter{
+		Workload:  q.Get("workload"),
+		Principal: q.Get("principal"),
+		Since:     q.Get("since"),
+		Until:     q.Get("until"),
+	}
+
+	store, err := s.getStore()
+	if err != nil {
+		logger.Errorf("Failed to create usage store: %v", err)
+		http.Error(w, "Failed to list usage", http.StatusInternalServerError)
+		return
+	}
+
+	records, err := usage.Query(r.Context(), store, filter)
+	if err != nil {
+		var dateErr *usage.InvalidDateError
+		if errors.As(err, &dateErr) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logger.Errorf("Failed to list usage: %v", err)
+		http.Error(w, "Failed to list usage", http.StatusInternalServerError)
+		return
+	}
+	if q.Get("total") == "true" {
+		records = usage.Summarize(records)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(usageListResponse{Usage: records}); err != nil {
+		logger.Errorf("Failed to marshal usage: %v", err)
+		http.Error(w, "Failed to marshal usage", http.StatusInternalServerError)
+		return
+	}
+}
+
+// usageListResponse represents the response for listing usage
+//
+//	@Description	Response containing a list of usage records
+type usageListResponse struct {
+	// List of usage records
+	Usage []usage.Record `json:"usage"`
+}
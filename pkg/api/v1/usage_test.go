@@ -0,0 +1,94 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/usage"
+)
+
+func TestListUsage(t *testing.T) {
+	t.Parallel()
+
+	logger.Initialize()
+
+	store := usage.NewLocalStore(t.TempDir())
+	fetch := usage.NewLedger("fetch")
+	fetch.Add(time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC), "alice", usage.Counters{Requests: 2, ToolCalls: 2})
+	fetch.Add(time.Date(2025, 10, 2, 12, 0, 0, 0, time.UTC), "alice", usage.Counters{Requests: 1, ToolCalls: 1})
+	fetch.Add(time.Date(2025, 10, 2, 12, 0, 0, 0, time.UTC), "bob", usage.Counters{Requests: 1})
+	require.NoError(t, store.Save(context.Background(), fetch))
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expected       []usage.Record
+	}{
+		{
+			name:           "all usage",
+			expectedStatus: http.StatusOK,
+			expected: []usage.Record{
+				{Date: "2025-10-01", Workload: "fetch", Principal: "alice", Counters: usage.Counters{Requests: 2, ToolCalls: 2}},
+				{Date: "2025-10-02", Workload: "fetch", Principal: "alice", Counters: usage.Counters{Requests: 1, ToolCalls: 1}},
+				{Date: "2025-10-02", Workload: "fetch", Principal: "bob", Counters: usage.Counters{Requests: 1}},
+			},
+		},
+		{
+			name:           "filtered by principal and date",
+			query:          "principal=alice&since=2025-10-02",
+			expectedStatus: http.StatusOK,
+			expected: []usage.Record{
+				{Date: "2025-10-02", Workload: "fetch", Principal: "alice", Counters: usage.Counters{Requests: 1, ToolCalls: 1}},
+			},
+		},
+		{
+			name:           "total per principal",
+			query:          "workload=fetch&total=true",
+			expectedStatus: http.StatusOK,
+			expected: []usage.Record{
+				{Workload: "fetch", Principal: "alice", Counters: usage.Counters{Requests: 3, ToolCalls: 3}},
+				{Workload: "fetch", Principal: "bob", Counters: usage.Counters{Requests: 1}},
+			},
+		},
+		{
+			name:           "unknown workload",
+			query:          "workload=github",
+			expectedStatus: http.StatusOK,
+			expected:       []usage.Record{},
+		},
+		{
+			name:           "invalid date",
+			query:          "since=last-week",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			routes := &UsageRoutes{store: store}
+			req := httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			routes.listUsage(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response usageListResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expected, response.Usage)
+		})
+	}
+}
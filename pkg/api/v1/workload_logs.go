@@ -0,0 +1,176 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/config"
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/logs"
+)
+
+const (
+	logsFormatText = "text"
+	logsFormatJSON = "json"
+)
+
+// logsQuery holds the query parameters of the workload logs endpoint.
+type logsQuery struct {
+	filter logs.FilterOptions
+	source string
+	format string
+	follow bool
+}
+
+// parseLogsQuery reads the logs query parameters from the request.
+func parseLogsQuery(r *http.Request) logsQuery {
+	q := r.URL.Query()
+	return logsQuery{
+		filter: logs.FilterOptions{
+			Level: q.Get("level"),
+			Since: q.Get("since"),
+			Until: q.Get("until"),
+			Grep:  q.Get("grep"),
+		},
+		source: q.Get("source"),
+		format: q.Get("format"),
+		follow: q.Get("follow") == "true",
+	}
+}
+
+// structured reports whether the query asks for parsed log records rather than raw output.
+func (q logsQuery) structured() bool {
+	return !q.filter.IsZero() || q.source != "" || q.format != "" || q.follow
+}
+
+// getStructuredLogsForWorkload serves the parsed and filtered log records of a workload,
+// optionally streaming new records until the client disconnects.
+func (s *WorkloadRoutes) getStructuredLogsForWorkload(w http.ResponseWriter, r *http.Request, name string, query logsQuery) {
+	ctx := r.Context()
+
+	source := logs.SourceContainer
+	switch query.source {
+	case "", string(logs.SourceContainer):
+	case string(logs.SourceProxy):
+		source = logs.SourceProxy
+	default:
+		http.Error(w, fmt.Sprintf("Invalid source: %s", query.source), http.StatusBadRequest)
+		return
+	}
+
+	format := query.format
+	if format == "" {
+		format = logsFormatText
+	}
+	if format != logsFormatText && format != logsFormatJSON {
+		http.Error(w, fmt.Sprintf("Invalid format: %s", query.format), http.StatusBadRequest)
+		return
+	}
+
+	filter, err := logs.NewFilter(query.filter, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	store, err := s.getLogStore()
+	if err != nil {
+		logger.Errorf("Failed to open log store: %v", err)
+		http.Error(w, "Failed to get logs", http.StatusInternalServerError)
+		return
+	}
+
+	records, collected, err := s.readWorkloadLogs(r, store, name, source, filter)
+	if err != nil {
+		if errors.Is(err, runtime.ErrWorkloadNotFound) {
+			http.Error(w, "Workload not found", http.StatusNotFound)
+			return
+		}
+		logger.Errorf("Failed to get logs: %v", err)
+		http.Error(w, "Failed to get logs", http.StatusInternalServerError)
+		return
+	}
+
+	if format == logsFormatJSON {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	} else {
+		w.Header().Set("Content-Type", "text/plain")
+	}
+	encoder := json.NewEncoder(w)
+	writeRecord := func(record logs.Record) error {
+		if format == logsFormatJSON {
+			return encoder.Encode(record)
+		}
+		_, err := fmt.Fprintln(w, record.String())
+		return err
+	}
+
+	for _, record := range records {
+		if err := writeRecord(record); err != nil {
+			logger.Errorf("Failed to write logs response: %v", err)
+			return
+		}
+	}
+
+	// Only collected logs can be followed
+	if !query.follow || !collected {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return
+	}
+	flusher.Flush()
+
+	filter.Until = time.Time{}
+	err = store.Follow(ctx, name, source, filter, func(record logs.Record) error {
+		if err := writeRecord(record); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+	if err != nil {
+		logger.Debugf("Stopped streaming logs for %s: %v", name, err)
+	}
+}
+
+// readWorkloadLogs returns the matching records from the collected log files of a workload.
+// Container logs fall back to the runtime output when no collected logs exist, in which case
+// the returned collected flag is false.
+func (s *WorkloadRoutes) readWorkloadLogs(
+	r *http.Request, store *logs.Store, name string, source logs.Source, filter *logs.Filter,
+) ([]logs.Record, bool, error) {
+	if source == logs.SourceProxy || len(store.Files(name, source)) > 0 {
+		records, err := store.Read(name, source, filter)
+		return records, true, err
+	}
+
+	output, err := s.workloadManager.GetLogs(r.Context(), name, false)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var records []logs.Record
+	now := time.Now()
+	for _, line := range strings.Split(output, "\n") {
+		if record, ok := logs.ParseLine(source, line, now); ok && filter.Match(record) {
+			records = append(records, record)
+		}
+	}
+	return records, false, nil
+}
+
+// getLogStore returns the workload log store, with the log retention policy from the config.
+func (s *WorkloadRoutes) getLogStore() (*logs.Store, error) {
+	if s.logStore != nil {
+		return s.logStore, nil
+	}
+	return logs.NewDefaultStore(config.NewDefaultProvider().GetConfig().LogRetention)
+}
@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/logs"
+	workloadsmocks "github.com/stacklok/toolhive/pkg/workloads/mocks"
+)
+
+func TestGetLogsForWorkload(t *testing.T) {
+	t.Parallel()
+
+	logger.Initialize()
+
+	collected := strings.Join([]string{
+		`{"time":"2025-10-01T10:00:00Z","level":"info","source":"container","message":"started"}`,
+		`{"time":"2025-10-01T10:01:00Z","level":"error","source":"container","message":"upstream down"}`,
+	}, "\n") + "\n"
+
+	tests := []struct {
+		name           string
+		workloadName   string
+		query          string
+		collected      bool
+		setupMock      func(*workloadsmocks.MockManager)
+		expectedStatus int
+		expectedBody   []string
+		unexpected     []string
+	}{
+		{
+			name:         "raw runtime logs without query",
+			workloadName: "fetch",
+			setupMock: func(wm *workloadsmocks.MockManager) {
+				wm.EXPECT().GetLogs(gomock.Any(), "fetch", false).Return("raw output\n", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{"raw output"},
+		},
+		{
+			name:           "collected logs filtered by level",
+			workloadName:   "fetch",
+			query:          "level=error",
+			collected:      true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{"ERROR upstream down"},
+			unexpected:     []string{"started"},
+		},
+		{
+			name:           "collected logs as JSON",
+			workloadName:   "fetch",
+			query:          "format=json&grep=started",
+			collected:      true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{`"message":"started"`},
+			unexpected:     []string{"upstream down"},
+		},
+		{
+			name:         "runtime logs are parsed when nothing was collected",
+			workloadName: "fetch",
+			query:        "level=warning",
+			setupMock: func(wm *workloadsmocks.MockManager) {
+				wm.EXPECT().GetLogs(gomock.Any(), "fetch", false).
+					Return("INFO booting\nERROR crashed\n{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{}}\n", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{"ERROR crashed"},
+			unexpected:     []string{"booting", "jsonrpc"},
+		},
+		{
+			name:         "workload not found",
+			workloadName: "missing",
+			query:        "level=error",
+			setupMock: func(wm *workloadsmocks.MockManager) {
+				wm.EXPECT().GetLogs(gomock.Any(), "missing", false).Return("", runtime.ErrWorkloadNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   []string{"Workload not found"},
+		},
+		{
+			name:           "invalid level",
+			workloadName:   "fetch",
+			query:          "level=loud",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"invalid log level"},
+		},
+		{
+			name:           "invalid source",
+			workloadName:   "fetch",
+			query:          "source=kernel",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"Invalid source"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockWorkloadManager := workloadsmocks.NewMockManager(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(mockWorkloadManager)
+			}
+
+			store := logs.NewStore(t.TempDir(), logs.RetentionPolicy{})
+			if tt.collected {
+				path := store.Path(tt.workloadName, logs.SourceContainer)
+				w, err := store.Writer(tt.workloadName, logs.SourceContainer)
+				require.NoError(t, err)
+				require.NoError(t, w.Close())
+				require.NoError(t, os.WriteFile(path, []byte(collected), 0600))
+			}
+
+			routes := &WorkloadRoutes{
+				workloadManager: mockWorkloadManager,
+				logStore:        store,
+			}
+
+			req := httptest.NewRequest("GET", "/"+tt.workloadName+"/logs?"+tt.query, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("name", tt.workloadName)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			routes.getLogsForWorkload(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			for _, expected := range tt.expectedBody {
+				assert.Contains(t, w.Body.String(), expected)
+			}
+			for _, unexpected := range tt.unexpected {
+				assert.NotContains(t, w.Body.String(), unexpected)
+			}
+		})
+	}
+}
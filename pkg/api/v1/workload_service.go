@@ -136,14 +136,13 @@ func (s *WorkloadService) BuildFullRunConfig(ctx context.Context, req *createReq
 		imageCtx, cancel := context.WithTimeout(ctx, imageRetrievalTimeout)
 		defer cancel()
 
-		// Fetch or build the requested image
-		imageURL, serverMetadata, err = s.imageRetriever(
-			imageCtx,
-			req.Image,
-			"", // We do not let the user specify a CA cert path here.
-			retriever.VerifyImageWarn,
-			"", // TODO Add support for registry groups lookups for APi
-		)
+		// Fetch or build the requested image. We do not let the user specify a CA cert path or
+		// a lockfile here.
+		// TODO Add support for registry groups lookups for APi
+		imageURL, serverMetadata, err = s.imageRetriever(imageCtx, req.Image, retriever.Options{
+			VerifyImage:  retriever.VerifyImageWarn,
+			WorkloadName: req.Name,
+		})
 		if err != nil {
 			// Check if the error is due to context timeout
 			if imageCtx.Err() == context.DeadlineExceeded {
@@ -215,7 +214,7 @@ func (s *WorkloadService) BuildFullRunConfig(ctx context.Context, req *createReq
 			"", "", "", "", "", false),
 		runner.WithToolsFilter(req.ToolsFilter),
 		runner.WithToolsOverride(toolsOverride),
-		runner.WithTelemetryConfig("", false, false, false, "", 0.0, nil, false, nil),
+		runner.WithTelemetryConfig("", false, false, false, "", 0.0, nil, false, nil, false, false),
 	}
 
 	// Determine transport type
@@ -237,6 +236,7 @@ func (s *WorkloadService) BuildFullRunConfig(ctx context.Context, req *createReq
 			req.AuthzConfig,
 			false,
 			"",
+			nil, // usageConfig - not supported via API yet
 			req.Name,
 			transportType,
 		),
@@ -12,6 +12,7 @@ import (
 	thverrors "github.com/stacklok/toolhive/pkg/errors"
 	"github.com/stacklok/toolhive/pkg/groups"
 	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/logs"
 	"github.com/stacklok/toolhive/pkg/runner"
 	"github.com/stacklok/toolhive/pkg/runner/retriever"
 	"github.com/stacklok/toolhive/pkg/validation"
@@ -26,6 +27,8 @@ type WorkloadRoutes struct {
 	debugMode        bool
 	groupManager     groups.Manager
 	workloadService  *WorkloadService
+	// logStore overrides the default workload log store, used in tests
+	logStore *logs.Store
 }
 
 //	@title			ToolHive API
@@ -516,17 +519,34 @@ func (s *WorkloadRoutes) deleteWorkloadsBulk(w http.ResponseWriter, r *http.Requ
 //
 // @Summary      Get logs for a specific workload
 // @Description  Retrieve at most 100 lines of logs for a specific workload by name.
+// @Description  When any of level, since, until, grep, source or format is set, the collected
+// @Description  log records are parsed and filtered instead. With follow=true, new records are
+// @Description  streamed until the client disconnects.
 // @Tags         logs
 // @Produce      text/plain
-// @Param        name  path      string  true  "Workload name"
+// @Produce      application/x-ndjson
+// @Param        name    path      string  true   "Workload name"
+// @Param        level   query     string  false  "Minimum level (debug, info, notice, warning, error, critical, alert, emergency)"
+// @Param        since   query     string  false  "Relative duration (e.g. 10m) or RFC 3339 timestamp"
+// @Param        until   query     string  false  "Relative duration (e.g. 5m) or RFC 3339 timestamp"
+// @Param        grep    query     string  false  "Regular expression the records must match"
+// @Param        source  query     string  false  "Log source (container or proxy)"
+// @Param        format  query     string  false  "Output format (text or json)"
+// @Param        follow  query     bool    false  "Stream new records as they are collected"
 // @Success      200   {string}  string  "Logs for the specified workload"
+// @Failure      400   {string}  string  "Bad Request"
 // @Failure      404   {string}  string  "Not Found"
 // @Router       /api/v1beta/workloads/{name}/logs [get]
 func (s *WorkloadRoutes) getLogsForWorkload(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	name := chi.URLParam(r, "name")
 
-	logs, err := s.workloadManager.GetLogs(ctx, name, false)
+	if query := parseLogsQuery(r); query.structured() {
+		s.getStructuredLogsForWorkload(w, r, name, query)
+		return
+	}
+
+	output, err := s.workloadManager.GetLogs(ctx, name, false)
 	if err != nil {
 		if errors.Is(err, runtime.ErrWorkloadNotFound) {
 			http.Error(w, "Workload not found", http.StatusNotFound)
@@ -537,7 +557,7 @@ func (s *WorkloadRoutes) getLogsForWorkload(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	w.Header().Set("Content-Type", "text/plain")
-	_, err = w.Write([]byte(logs))
+	_, err = w.Write([]byte(output))
 	if err != nil {
 		logger.Errorf("Failed to write logs response: %v", err)
 		http.Error(w, "Failed to write logs response", http.StatusInternalServerError)
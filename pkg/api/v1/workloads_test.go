@@ -441,9 +441,11 @@ func makeMockRetriever(
 ) retriever.Retriever {
 	t.Helper()
 
-	return func(_ context.Context, serverOrImage string, _ string, verificationType string, _ string) (string, registry.ServerMetadata, error) {
+	return func(_ context.Context, serverOrImage string, opts retriever.Options) (string, registry.ServerMetadata, error) {
 		assert.Equal(t, expectedServerOrImage, serverOrImage)
-		assert.Equal(t, retriever.VerifyImageWarn, verificationType)
+		assert.Equal(t, retriever.VerifyImageWarn, opts.VerifyImage)
+		assert.Empty(t, opts.CACertPath)
+		assert.Empty(t, opts.LockFile)
 		return returnedImage, returnedServerMetadata, returnedError
 	}
 }
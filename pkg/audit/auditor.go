@@ -22,6 +22,7 @@ import (
 const LevelAudit = slog.Level(2)
 
 // NewAuditLogger creates a new structured audit logger that writes to the specified writer.
+// When OpenTelemetry logs export is configured, audit events are also exported as log records.
 func NewAuditLogger(w io.Writer) *slog.Logger {
 	if w == nil {
 		w = os.Stdout
@@ -31,7 +32,7 @@ func NewAuditLogger(w io.Writer) *slog.Logger {
 		Level: LevelAudit,
 	})
 
-	return slog.New(handler)
+	return slog.New(multiHandler{handler, newOTelHandler()})
 }
 
 // Auditor handles audit logging for HTTP requests.
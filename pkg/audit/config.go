@@ -149,6 +149,8 @@ func (c *Config) Validate() error {
 		// Fallback event types that can also be emitted by the middleware
 		EventTypeMCPRequest:  true,
 		EventTypeHTTPRequest: true,
+		// Events reported by the egress proxy of workloads with network isolation
+		EventTypeNetworkEgressDenied: true,
 	}
 
 	for _, eventType := range c.EventTypes {
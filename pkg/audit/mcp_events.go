@@ -35,6 +35,10 @@ const (
 	EventTypeMCPRequest = "mcp_request"
 	// EventTypeHTTPRequest represents a generic HTTP request (non-MCP)
 	EventTypeHTTPRequest = "http_request"
+
+	// EventTypeNetworkEgressDenied represents an outbound connection of an MCP server
+	// which was denied by its network permissions
+	EventTypeNetworkEgressDenied = "network_egress_denied"
 )
 
 // MCP target types for audit events
@@ -47,6 +51,8 @@ const (
 	TargetTypePrompt = "prompt"
 	// TargetTypeServer represents a server target
 	TargetTypeServer = "server"
+	// TargetTypeNetwork represents a network destination target
+	TargetTypeNetwork = "network"
 )
 
 // MCP-specific target field keys
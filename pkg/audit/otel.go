@@ -0,0 +1,222 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"go.opentelemetry.io/otel/log"
+	logglobal "go.opentelemetry.io/otel/log/global"
+)
+
+const (
+	// instrumentationName is the OpenTelemetry instrumentation scope of audit events
+	instrumentationName = "github.com/stacklok/toolhive/pkg/audit"
+	// severityText is the severity text of audit events exported via OpenTelemetry
+	severityText = "AUDIT"
+)
+
+// otelHandler is a slog.Handler which emits audit events as OpenTelemetry log records.
+// Events logged with a context holding a span carry its trace and span IDs.
+type otelHandler struct {
+	logger log.Logger
+	attrs  []log.KeyValue
+	group  string
+}
+
+// newOTelHandler creates a handler emitting to the global OpenTelemetry logger provider.
+// Until telemetry is configured with logs enabled, the handler is disabled.
+func newOTelHandler() *otelHandler {
+	return &otelHandler{logger: logglobal.GetLoggerProvider().Logger(instrumentationName)}
+}
+
+// Enabled reports whether the logger provider accepts records of the given level.
+func (h *otelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.logger.Enabled(ctx, log.EnabledParameters{Severity: convertLevel(level)})
+}
+
+// Handle converts the slog record to an OpenTelemetry log record and emits it.
+func (h *otelHandler) Handle(ctx context.Context, r slog.Record) error {
+	var record log.Record
+	record.SetTimestamp(r.Time)
+	record.SetSeverity(convertLevel(r.Level))
+	record.SetSeverityText(severityText)
+	record.SetEventName(r.Message)
+	record.SetBody(log.StringValue(r.Message))
+	record.AddAttributes(h.attrs...)
+	r.Attrs(func(attr slog.Attr) bool {
+		record.AddAttributes(h.convertAttr(attr))
+		return true
+	})
+	h.logger.Emit(ctx, record)
+	return nil
+}
+
+// WithAttrs returns a handler which adds attrs to every record.
+func (h *otelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]log.KeyValue{}, h.attrs...)
+	for _, attr := range attrs {
+		clone.attrs = append(clone.attrs, h.convertAttr(attr))
+	}
+	return &clone
+}
+
+// WithGroup returns a handler which qualifies the keys of subsequent attributes with name.
+func (h *otelHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.group = h.qualify(name)
+	return &clone
+}
+
+func (h *otelHandler) qualify(key string) string {
+	if h.group == "" {
+		return key
+	}
+	return h.group + "." + key
+}
+
+func (h *otelHandler) convertAttr(attr slog.Attr) log.KeyValue {
+	return log.KeyValue{Key: h.qualify(attr.Key), Value: convertValue(attr.Value)}
+}
+
+// convertValue converts a slog value, including groups and the maps used by audit
+// events, to an OpenTelemetry log value.
+func convertValue(v slog.Value) log.Value {
+	switch v.Kind() {
+	case slog.KindString:
+		return log.StringValue(v.String())
+	case slog.KindInt64:
+		return log.Int64Value(v.Int64())
+	case slog.KindUint64:
+		return log.Int64Value(int64(v.Uint64())) // #nosec G115 - audit values never exceed int64
+	case slog.KindFloat64:
+		return log.Float64Value(v.Float64())
+	case slog.KindBool:
+		return log.BoolValue(v.Bool())
+	case slog.KindDuration:
+		return log.Int64Value(int64(v.Duration()))
+	case slog.KindTime:
+		return log.StringValue(v.Time().Format("2006-01-02T15:04:05.999999999Z07:00"))
+	case slog.KindGroup:
+		group := v.Group()
+		kvs := make([]log.KeyValue, 0, len(group))
+		for _, attr := range group {
+			kvs = append(kvs, log.KeyValue{Key: attr.Key, Value: convertValue(attr.Value)})
+		}
+		return log.MapValue(kvs...)
+	case slog.KindLogValuer:
+		return convertValue(v.Resolve())
+	case slog.KindAny:
+		return convertAny(v.Any())
+	default:
+		return log.StringValue(v.String())
+	}
+}
+
+func convertAny(value any) log.Value {
+	switch val := value.(type) {
+	case nil:
+		return log.Value{}
+	case map[string]string:
+		kvs := make([]log.KeyValue, 0, len(val))
+		for _, key := range sortedKeys(val) {
+			kvs = append(kvs, log.String(key, val[key]))
+		}
+		return log.MapValue(kvs...)
+	case map[string]any:
+		kvs := make([]log.KeyValue, 0, len(val))
+		for _, key := range sortedKeys(val) {
+			kvs = append(kvs, log.KeyValue{Key: key, Value: convertValue(slog.AnyValue(val[key]))})
+		}
+		return log.MapValue(kvs...)
+	case *json.RawMessage:
+		if val == nil {
+			return log.Value{}
+		}
+		return log.StringValue(string(*val))
+	case json.RawMessage:
+		return log.StringValue(string(val))
+	case []byte:
+		return log.BytesValue(val)
+	case error:
+		return log.StringValue(val.Error())
+	case fmt.Stringer:
+		return log.StringValue(val.String())
+	default:
+		return log.StringValue(fmt.Sprint(val))
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// convertLevel maps slog levels, including LevelAudit, to OpenTelemetry severities.
+func convertLevel(level slog.Level) log.Severity {
+	switch {
+	case level >= slog.LevelError:
+		return log.SeverityError
+	case level >= slog.LevelWarn:
+		return log.SeverityWarn
+	case level >= LevelAudit:
+		return log.SeverityInfo2
+	case level >= slog.LevelInfo:
+		return log.SeverityInfo
+	default:
+		return log.SeverityDebug
+	}
+}
+
+// multiHandler is a slog.Handler which passes records to all of its handlers.
+type multiHandler []slog.Handler
+
+// Enabled reports whether any of the handlers is enabled for the level.
+func (m multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle passes the record to every enabled handler.
+func (m multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range m {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a handler which adds attrs to the records of every handler.
+func (m multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(multiHandler, 0, len(m))
+	for _, h := range m {
+		handlers = append(handlers, h.WithAttrs(attrs))
+	}
+	return handlers
+}
+
+// WithGroup returns a handler which qualifies the attributes of every handler with name.
+func (m multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make(multiHandler, 0, len(m))
+	for _, h := range m {
+		handlers = append(handlers, h.WithGroup(name))
+	}
+	return handlers
+}
@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/logtest"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestOTelHandler(t *testing.T) {
+	t.Parallel()
+
+	recorder := logtest.NewRecorder()
+	var buf bytes.Buffer
+	jsonHandler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: LevelAudit})
+	auditLogger := slog.New(multiHandler{
+		jsonHandler,
+		&otelHandler{logger: recorder.Logger(instrumentationName)},
+	})
+
+	tracerProvider := sdktrace.NewTracerProvider()
+	ctx, span := tracerProvider.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+
+	event := NewAuditEvent(EventTypeMCPToolCall, EventSource{Type: SourceTypeNetwork, Value: "10.0.0.1"},
+		OutcomeSuccess, map[string]string{SubjectKeyUser: "alice"}, "github")
+	event.WithTarget(map[string]string{TargetKeyName: "search"})
+	event.WithDataFromString(`{"request":{"query":"otel"}}`)
+	event.LogTo(ctx, auditLogger, LevelAudit)
+
+	// The event is still written to the audit log
+	assert.Contains(t, buf.String(), `"type":"mcp_tool_call"`)
+
+	records := recorder.Result()[logtest.Scope{Name: instrumentationName}]
+	require.Len(t, records, 1)
+	record := records[0]
+
+	assert.Equal(t, "audit_event", record.EventName)
+	assert.Equal(t, log.SeverityInfo2, record.Severity)
+	assert.Equal(t, severityText, record.SeverityText)
+	assert.Equal(t, span.SpanContext(), trace.SpanContextFromContext(record.Context))
+
+	attrs := make(map[string]log.Value, len(record.Attributes))
+	for _, kv := range record.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	assert.Equal(t, EventTypeMCPToolCall, attrs["type"].AsString())
+	assert.Equal(t, OutcomeSuccess, attrs["outcome"].AsString())
+	assert.Equal(t, []log.KeyValue{log.String(SubjectKeyUser, "alice")}, attrs["subjects"].AsMap())
+	assert.Equal(t, []log.KeyValue{log.String(TargetKeyName, "search")}, attrs["target"].AsMap())
+	assert.JSONEq(t, `{"request":{"query":"otel"}}`, attrs["data"].AsString())
+
+	source := attrs["source"].AsMap()
+	require.NotEmpty(t, source)
+	assert.Equal(t, log.String("type", SourceTypeNetwork), source[0])
+}
+
+func TestOTelHandlerDisabled(t *testing.T) {
+	t.Parallel()
+
+	recorder := logtest.NewRecorder(logtest.WithEnabledFunc(func(context.Context, log.EnabledParameters) bool {
+		return false
+	}))
+	var buf bytes.Buffer
+	auditLogger := slog.New(multiHandler{
+		slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: LevelAudit}),
+		&otelHandler{logger: recorder.Logger(instrumentationName)},
+	})
+
+	auditLogger.LogAttrs(context.Background(), LevelAudit, "audit_event", slog.String("type", "test"))
+
+	var logged map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &logged))
+	assert.Equal(t, "test", logged["type"])
+	assert.Empty(t, recorder.Result()[logtest.Scope{Name: instrumentationName}])
+}
@@ -13,9 +13,12 @@ import (
 	"github.com/adrg/xdg"
 	"gopkg.in/yaml.v3"
 
+	"github.com/stacklok/toolhive/pkg/container/images"
+	"github.com/stacklok/toolhive/pkg/container/sbom"
 	"github.com/stacklok/toolhive/pkg/env"
 	"github.com/stacklok/toolhive/pkg/lockfile"
 	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/logs"
 	"github.com/stacklok/toolhive/pkg/secrets"
 )
 
@@ -24,14 +27,18 @@ const lockTimeout = 1 * time.Second
 
 // Config represents the configuration of the application.
 type Config struct {
-	Secrets                Secrets             `yaml:"secrets"`
-	Clients                Clients             `yaml:"clients"`
-	RegistryUrl            string              `yaml:"registry_url"`
-	LocalRegistryPath      string              `yaml:"local_registry_path"`
-	AllowPrivateRegistryIp bool                `yaml:"allow_private_registry_ip"`
-	CACertificatePath      string              `yaml:"ca_certificate_path,omitempty"`
-	OTEL                   OpenTelemetryConfig `yaml:"otel,omitempty"`
-	DefaultGroupMigration  bool                `yaml:"default_group_migration,omitempty"`
+	Secrets                Secrets              `yaml:"secrets"`
+	Clients                Clients              `yaml:"clients"`
+	RegistryUrl            string               `yaml:"registry_url"`
+	LocalRegistryPath      string               `yaml:"local_registry_path"`
+	AllowPrivateRegistryIp bool                 `yaml:"allow_private_registry_ip"`
+	CACertificatePath      string               `yaml:"ca_certificate_path,omitempty"`
+	OTEL                   OpenTelemetryConfig  `yaml:"otel,omitempty"`
+	DefaultGroupMigration  bool                 `yaml:"default_group_migration,omitempty"`
+	LogRetention           logs.RetentionPolicy `yaml:"log_retention,omitempty"`
+	VulnerabilityPolicy    sbom.Policy          `yaml:"vulnerability_policy,omitempty"`
+	VerificationPolicyPath string               `yaml:"verification_policy_path,omitempty"`
+	ImagePrune             images.PrunePolicy   `yaml:"image_prune,omitempty"`
 }
 
 // Secrets contains the settings for secrets management.
@@ -49,9 +56,15 @@ func validateProviderType(provider string) (secrets.ProviderType, error) {
 		return secrets.OnePasswordType, nil
 	case string(secrets.NoneType):
 		return secrets.NoneType, nil
+	case string(secrets.VaultType):
+		return secrets.VaultType, nil
 	default:
-		return "", fmt.Errorf("invalid secrets provider type: %s (valid types: %s, %s, %s)",
-			provider, string(secrets.EncryptedType), string(secrets.OnePasswordType), string(secrets.NoneType))
+		if secrets.IsPluginProviderType(secrets.ProviderType(provider)) {
+			return secrets.ProviderType(provider), nil
+		}
+		return "", fmt.Errorf("invalid secrets provider type: %s (valid types: %s, %s, %s, %s, or a plugin installed as %s<name>)",
+			provider, string(secrets.EncryptedType), string(secrets.OnePasswordType), string(secrets.NoneType),
+			string(secrets.VaultType), secrets.PluginExecutablePrefix)
 	}
 }
 
@@ -305,6 +318,8 @@ type OpenTelemetryConfig struct {
 	EnvVars                     []string `yaml:"env-vars,omitempty"`
 	MetricsEnabled              bool     `yaml:"metrics-enabled,omitempty"`
 	TracingEnabled              bool     `yaml:"tracing-enabled,omitempty"`
+	LogsEnabled                 bool     `yaml:"logs-enabled,omitempty"`
+	ExemplarsEnabled            bool     `yaml:"exemplars-enabled,omitempty"`
 	Insecure                    bool     `yaml:"insecure,omitempty"`
 	EnablePrometheusMetricsPath bool     `yaml:"enable-prometheus-metrics-path,omitempty"`
 }
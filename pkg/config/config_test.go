@@ -294,6 +294,23 @@ func TestSecrets_GetProviderType_EnvironmentVariable(t *testing.T) {
 		assert.Equal(t, secrets.OnePasswordType, got, "Should fallback to config value when env var is unset")
 	})
 
+	t.Run("Vault provider via environment variable", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEnv := mocks.NewMockReader(ctrl)
+		s := &Secrets{
+			ProviderType:   string(secrets.EncryptedType),
+			SetupCompleted: true,
+		}
+
+		mockEnv.EXPECT().Getenv(secrets.ProviderEnvVar).Return(string(secrets.VaultType))
+		got, err := s.GetProviderTypeWithEnv(mockEnv)
+		require.NoError(t, err)
+		assert.Equal(t, secrets.VaultType, got, "Vault should be accepted as a provider type")
+	})
+
 	t.Run("None provider via environment variable", func(t *testing.T) {
 		t.Parallel()
 		ctrl := gomock.NewController(t)
@@ -0,0 +1,696 @@
+// Package containerd provides a containerd implementation of the container runtime.
+// Containers are managed through nerdctl, which takes care of image pulls, CNI
+// networking and port publishing, and stores container logs.
+package containerd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/stacklok/toolhive/pkg/container/containerd/nerdctl"
+	"github.com/stacklok/toolhive/pkg/container/docker"
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	lb "github.com/stacklok/toolhive/pkg/labels"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/networking"
+	"github.com/stacklok/toolhive/pkg/permissions"
+	"github.com/stacklok/toolhive/pkg/telemetry"
+)
+
+// RuntimeName is the name identifier for the containerd runtime
+const RuntimeName = "containerd"
+
+// tracerName is the name of the tracer of containerd runtime operations
+const tracerName = "github.com/stacklok/toolhive/pkg/container/containerd"
+
+const (
+	// stopTimeoutSeconds is how long a container may take to stop before it is killed
+	stopTimeoutSeconds = 30
+	// logTailLines is the number of lines returned by GetWorkloadLogs
+	logTailLines = "100"
+)
+
+// IsAvailable checks if containerd is available, that is if nerdctl is installed
+// and a containerd socket accepts connections
+func IsAvailable() bool {
+	_, err := nerdctl.New()
+	return err == nil
+}
+
+// nerdctlCLI is the subset of nerdctl.CLI used by the client, so that tests can substitute it
+type nerdctlCLI interface {
+	Run(ctx context.Context, args ...string) ([]byte, error)
+	Command(ctx context.Context, args ...string) *exec.Cmd
+}
+
+// Client implements the Runtime interface for containerd
+type Client struct {
+	cli nerdctlCLI
+}
+
+// NewClient creates a new containerd client
+func NewClient(_ context.Context) (*Client, error) {
+	cli, err := nerdctl.New()
+	if err != nil {
+		return nil, err // there is already enough context in the error.
+	}
+	return &Client{cli: cli}, nil
+}
+
+// containerInspect is the subset of the Docker-compatible output of nerdctl container inspect used by the client
+type containerInspect struct {
+	ID      string `json:"Id"`
+	Name    string `json:"Name"`
+	Created string `json:"Created"`
+	Image   string `json:"Image"`
+	State   struct {
+		Status   string `json:"Status"`
+		Running  bool   `json:"Running"`
+		ExitCode int    `json:"ExitCode"`
+	} `json:"State"`
+	Config struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"Config"`
+	NetworkSettings struct {
+		IPAddress string `json:"IPAddress"`
+		Ports     map[string][]struct {
+			HostIP   string `json:"HostIp"`
+			HostPort string `json:"HostPort"`
+		} `json:"Ports"`
+	} `json:"NetworkSettings"`
+}
+
+// DeployWorkload creates and starts a workload.
+// It configures the container based on the provided permission profile and transport type.
+// If options is nil, default options will be used.
+func (c *Client) DeployWorkload(
+	ctx context.Context,
+	image,
+	name string,
+	command []string,
+	envVars,
+	labels map[string]string,
+	permissionProfile *permissions.Profile,
+	transportType string,
+	options *runtime.DeployWorkloadOptions,
+	isolateNetwork bool,
+) (_ int, retErr error) {
+	ctx, span := telemetry.StartSpan(ctx, tracerName, "containerd.DeployWorkload",
+		attribute.String("workload.name", name),
+		attribute.String("workload.image", image),
+		attribute.Bool("workload.isolate_network", isolateNetwork),
+	)
+	defer func() {
+		telemetry.EndSpan(span, retErr)
+	}()
+
+	if options == nil {
+		options = runtime.NewDeployWorkloadOptions()
+	}
+
+	permissionConfig, err := docker.PermissionConfigFromProfile(permissionProfile, transportType, options.IgnoreConfig)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get permission config: %w", err)
+	}
+
+	// Make write mounts writable with rootless containerd
+	if len(permissionProfile.Write) > 0 {
+		mode, err := c.userNamespaceMode(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to detect the user namespace of containerd for write mounts: %w", err)
+		}
+		imageUser := func() (string, error) { return c.imageUser(ctx, image) }
+		setup := docker.NewUserNamespaceSetup(mode, "")
+		if err := docker.ApplyUserNamespace(permissionConfig, runtime.TypeContainerd, setup, imageUser); err != nil {
+			return 0, err
+		}
+	}
+
+	// Mount secrets with file targets
+	if len(options.SecretFiles) > 0 {
+		secretMounts, err := docker.MountSecretFiles(name, options.SecretFiles)
+		if err != nil {
+			return 0, err
+		}
+		permissionConfig.Mounts = append(permissionConfig.Mounts, secretMounts...)
+	}
+
+	// The egress proxy of the Docker runtime is not available, so network isolation
+	// is only possible for servers which need no network at all. Other servers are
+	// refused rather than started with unrestricted outbound access.
+	networkIsolation := false
+	if isolateNetwork {
+		if transportType != "stdio" || allowsOutbound(permissionProfile) {
+			return 0, fmt.Errorf("the containerd runtime cannot filter egress traffic, so it can only isolate "+
+				"the network of stdio servers without outbound network permissions; run %s without network isolation", name)
+		}
+		permissionConfig.NetworkMode = "none"
+		networkIsolation = true
+	}
+	lb.AddNetworkIsolationLabel(labels, networkIsolation)
+
+	var portBindings map[string][]runtime.PortBinding
+	hostPort := 0
+	if transportType != "stdio" {
+		portBindings, hostPort, err = publishPorts(labels, options.PortBindings)
+		if err != nil {
+			return 0, fmt.Errorf("failed to generate port bindings: %v", err)
+		}
+	}
+
+	// Environment variables and seccomp profiles are passed through files, so they
+	// don't show up in the process list
+	tmpDir, err := os.MkdirTemp("", "toolhive-containerd-")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Start the services before the MCP server, which may need them to start
+	var serviceHostArgs []string
+	if len(options.Services) > 0 {
+		if permissionConfig.NetworkMode == "none" {
+			return 0, fmt.Errorf("%s has no network with network isolation in the containerd runtime, "+
+				"so it cannot reach its services", name)
+		}
+		serviceHostArgs, err = c.startServices(ctx, tmpDir, name, options.Services)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create service containers: %w", err)
+		}
+	}
+
+	args, err := runArgs(tmpDir, name, envVars, labels, permissionConfig, portBindings, options.AttachStdio)
+	if err != nil {
+		return 0, err
+	}
+	args = append(args, serviceHostArgs...)
+	args = append(args, image)
+	args = append(args, command...)
+
+	// Replace any previous container of the workload
+	if _, err := c.cli.Run(ctx, "rm", "-f", name); err != nil && !errors.Is(err, nerdctl.ErrNotFound) {
+		return 0, fmt.Errorf("failed to remove existing container: %w", err)
+	}
+
+	if _, err := c.cli.Run(ctx, args...); err != nil {
+		return 0, fmt.Errorf("failed to create mcp container: %w", err)
+	}
+
+	return hostPort, nil
+}
+
+// allowsOutbound returns true if the permission profile allows any outbound connection
+func allowsOutbound(profile *permissions.Profile) bool {
+	if profile == nil || profile.Network == nil || profile.Network.Outbound == nil {
+		return false
+	}
+	outbound := profile.Network.Outbound
+	return outbound.InsecureAllowAll || len(outbound.AllowHost) > 0 || len(outbound.AllowPort) > 0 ||
+		len(outbound.AllowCIDR) > 0 || len(outbound.AllowHostPort) > 0
+}
+
+// publishPorts returns the port bindings of a workload and its host port. Like in the
+// Docker runtime, the first binding is remapped to a random host port unless the workload
+// is auxiliary.
+func publishPorts(
+	labels map[string]string,
+	portBindings map[string][]runtime.PortBinding,
+) (map[string][]runtime.PortBinding, int, error) {
+	keys := make([]string, 0, len(portBindings))
+	for key, bindings := range portBindings {
+		if len(bindings) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return portBindings, 0, nil
+	}
+	sort.Strings(keys)
+	first := portBindings[keys[0]]
+
+	if lb.IsAuxiliaryWorkload(labels) {
+		hostPort, err := strconv.Atoi(first[0].HostPort)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to convert host port %s to int: %v", first[0].HostPort, err)
+		}
+		return portBindings, hostPort, nil
+	}
+
+	hostPort := networking.FindAvailable()
+	if hostPort == 0 {
+		return nil, 0, fmt.Errorf("could not find an available port")
+	}
+	first[0].HostPort = strconv.Itoa(hostPort)
+	return portBindings, hostPort, nil
+}
+
+// runArgs returns the arguments of nerdctl run for a workload, without the image and command.
+// Files referenced by the arguments are written to dir.
+func runArgs(
+	dir string,
+	name string,
+	envVars, labels map[string]string,
+	config *runtime.PermissionConfig,
+	portBindings map[string][]runtime.PortBinding,
+	attachStdio bool,
+) ([]string, error) {
+	args := []string{"run", "--detach", "--name", name, "--restart", "unless-stopped"}
+	if attachStdio {
+		args = append(args, "--interactive")
+	}
+
+	for _, key := range sortedKeys(labels) {
+		args = append(args, "--label", key+"="+labels[key])
+	}
+
+	if len(envVars) > 0 {
+		var env bytes.Buffer
+		for _, key := range sortedKeys(envVars) {
+			if strings.ContainsAny(envVars[key], "\r\n") {
+				return nil, fmt.Errorf("environment variable %s contains a line break, which is not supported by containerd", key)
+			}
+			fmt.Fprintf(&env, "%s=%s\n", key, envVars[key])
+		}
+		envFile := dir + "/env"
+		if err := os.WriteFile(envFile, env.Bytes(), 0600); err != nil {
+			return nil, fmt.Errorf("failed to write environment file: %w", err)
+		}
+		args = append(args, "--env-file", envFile)
+	}
+
+	if config.NetworkMode != "" {
+		args = append(args, "--network", config.NetworkMode)
+	}
+	for _, capability := range config.CapDrop {
+		args = append(args, "--cap-drop", capability)
+	}
+	for _, capability := range config.CapAdd {
+		args = append(args, "--cap-add", capability)
+	}
+	for _, opt := range config.SecurityOpt {
+		switch {
+		case strings.HasPrefix(opt, "label"):
+			// SELinux labels are configured in containerd rather than per container
+			continue
+		case strings.HasPrefix(opt, "seccomp={"):
+			// nerdctl expects the path of a seccomp profile rather than its contents
+			profileFile := dir + "/seccomp.json"
+			if err := os.WriteFile(profileFile, []byte(strings.TrimPrefix(opt, "seccomp=")), 0600); err != nil {
+				return nil, fmt.Errorf("failed to write seccomp profile: %w", err)
+			}
+			opt = "seccomp=" + profileFile
+		}
+		args = append(args, "--security-opt", opt)
+	}
+	if config.Privileged {
+		args = append(args, "--privileged")
+	}
+	if config.ReadOnlyRootfs {
+		args = append(args, "--read-only")
+	}
+	if config.User != "" {
+		args = append(args, "--user", config.User)
+	}
+
+	resourceArgs, err := resourceLimitArgs(config.Resources)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, resourceArgs...)
+
+	for _, mount := range config.Mounts {
+		switch mount.Type {
+		case runtime.MountTypeTmpfs:
+			args = append(args, "--tmpfs", mount.Target)
+		default:
+			spec := fmt.Sprintf("type=bind,source=%s,target=%s", mount.Source, mount.Target)
+			if mount.ReadOnly {
+				spec += ",readonly"
+			}
+			args = append(args, "--mount", spec)
+		}
+	}
+
+	for _, port := range sortedKeys(portBindings) {
+		containerPort := strings.Split(port, "/")[0]
+		for _, binding := range portBindings[port] {
+			publish := binding.HostPort + ":" + containerPort + "/tcp"
+			if binding.HostIP != "" {
+				publish = binding.HostIP + ":" + publish
+			}
+			args = append(args, "--publish", publish)
+		}
+	}
+
+	return args, nil
+}
+
+// resourceLimitArgs returns the arguments of nerdctl run which apply resource limits
+func resourceLimitArgs(limits *permissions.ResourceLimits) ([]string, error) {
+	if limits == nil {
+		return nil, nil
+	}
+
+	var args []string
+	milliCPUs, err := limits.MilliCPUs()
+	if err != nil {
+		return nil, err
+	}
+	if milliCPUs > 0 {
+		args = append(args, "--cpus", strconv.FormatFloat(float64(milliCPUs)/1000, 'f', -1, 64))
+	}
+
+	memory, err := limits.MemoryBytes()
+	if err != nil {
+		return nil, err
+	}
+	if memory > 0 {
+		args = append(args, "--memory", strconv.FormatInt(memory, 10))
+	}
+
+	if limits.PidsLimit > 0 {
+		args = append(args, "--pids-limit", strconv.FormatInt(limits.PidsLimit, 10))
+	}
+	for _, ulimit := range limits.Ulimits {
+		args = append(args, "--ulimit", fmt.Sprintf("%s=%d:%d", ulimit.Name, ulimit.Soft, ulimit.Hard))
+	}
+	return args, nil
+}
+
+// sortedKeys returns the keys of a map in order, so that the arguments are deterministic
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ListWorkloads lists workloads
+func (c *Client) ListWorkloads(ctx context.Context) ([]runtime.ContainerInfo, error) {
+	output, err := c.cli.Run(ctx, "ps", "--all", "--quiet", "--no-trunc", "--filter", "label=toolhive=true")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	ids := strings.Fields(string(output))
+	if len(ids) == 0 {
+		return []runtime.ContainerInfo{}, nil
+	}
+
+	containers, err := c.inspect(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]runtime.ContainerInfo, 0, len(containers))
+	for _, ctr := range containers {
+		// Skip the helper containers of other runtimes
+		if ctr.Config.Labels[docker.ToolhiveAuxiliaryWorkloadLabel] == docker.LabelValueTrue {
+			continue
+		}
+		result = append(result, ctr.info())
+	}
+	return result, nil
+}
+
+// StopWorkload stops a workload
+// If the workload is already stopped or does not exist, it returns success
+func (c *Client) StopWorkload(ctx context.Context, workloadName string) error {
+	_, err := c.cli.Run(ctx, "stop", "--time", strconv.Itoa(stopTimeoutSeconds), workloadName)
+	if err != nil && !errors.Is(err, nerdctl.ErrNotFound) {
+		return fmt.Errorf("failed to stop workload %s: %w", workloadName, err)
+	}
+	c.stopServices(ctx, workloadName)
+	return nil
+}
+
+// RemoveWorkload removes the container of a workload and its secret files
+func (c *Client) RemoveWorkload(ctx context.Context, workloadName string) error {
+	_, err := c.cli.Run(ctx, "rm", "--force", workloadName)
+	if err != nil && !errors.Is(err, nerdctl.ErrNotFound) {
+		return fmt.Errorf("failed to remove workload %s: %w", workloadName, err)
+	}
+	if err := c.removeServices(ctx, workloadName); err != nil {
+		return err
+	}
+
+	if err := docker.RemoveSecretFiles(workloadName); err != nil {
+		logger.Warnf("Failed to remove secret files of %s: %v", workloadName, err)
+	}
+	return nil
+}
+
+// GetWorkloadLogs gets workload logs
+func (c *Client) GetWorkloadLogs(ctx context.Context, workloadName string, follow bool) (string, error) {
+	if _, err := c.inspectOne(ctx, workloadName); err != nil {
+		return "", err
+	}
+
+	if follow {
+		cmd := c.cli.Command(ctx, "logs", "--follow", workloadName)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil && ctx.Err() == nil {
+			return "", fmt.Errorf("failed to follow workload logs: %w", err)
+		}
+		return "", nil
+	}
+
+	// The output of the container is split across the streams of nerdctl
+	var buf bytes.Buffer
+	cmd := c.cli.Command(ctx, "logs", "--tail", logTailLines, workloadName)
+	cmd.Stdout = &buf
+	cmd.Stderr = &buf
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to get workload logs: %w: %s", err, strings.TrimSpace(buf.String()))
+	}
+	return buf.String(), nil
+}
+
+// StreamWorkloadLogs copies the output of the workload container to stdout and stderr,
+// starting at since, until ctx is done or the container exits.
+func (c *Client) StreamWorkloadLogs(
+	ctx context.Context, workloadName string, since time.Time, stdout, stderr io.Writer,
+) error {
+	if _, err := c.inspectOne(ctx, workloadName); err != nil {
+		return err
+	}
+
+	args := []string{"logs", "--follow"}
+	if !since.IsZero() {
+		args = append(args, "--since", strconv.FormatInt(since.Unix(), 10))
+	}
+	cmd := c.cli.Command(ctx, append(args, workloadName)...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to stream workload logs: %w", err)
+	}
+	return nil
+}
+
+// IsWorkloadRunning checks if a workload is running
+func (c *Client) IsWorkloadRunning(ctx context.Context, workloadName string) (bool, error) {
+	ctr, err := c.inspectOne(ctx, workloadName)
+	if err != nil {
+		return false, err
+	}
+	return ctr.State.Running, nil
+}
+
+// GetWorkloadInfo gets workload information
+func (c *Client) GetWorkloadInfo(ctx context.Context, workloadName string) (runtime.ContainerInfo, error) {
+	ctr, err := c.inspectOne(ctx, workloadName)
+	if err != nil {
+		return runtime.ContainerInfo{}, err
+	}
+	return ctr.info(), nil
+}
+
+// AttachToWorkload attaches to the standard streams of a workload, which must have been
+// deployed with AttachStdio. The standard error of the container is discarded.
+func (c *Client) AttachToWorkload(ctx context.Context, workloadName string) (io.WriteCloser, io.ReadCloser, error) {
+	running, err := c.IsWorkloadRunning(ctx, workloadName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !running {
+		return nil, nil, fmt.Errorf("workload %s is not running", workloadName)
+	}
+
+	// The pipes are created by hand rather than with StdinPipe and StdoutPipe, since
+	// those are closed by Wait, which runs independently of the readers of the output.
+	stdinReader, stdinWriter, err := os.Pipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		_ = stdinReader.Close()
+		_ = stdinWriter.Close()
+		return nil, nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+
+	cmd := c.cli.Command(ctx, "attach", workloadName)
+	cmd.Stdin = stdinReader
+	cmd.Stdout = stdoutWriter
+	cmd.Stderr = io.Discard
+
+	err = cmd.Start()
+	// nerdctl has its own copies of these ends
+	_ = stdinReader.Close()
+	_ = stdoutWriter.Close()
+	if err != nil {
+		_ = stdinWriter.Close()
+		_ = stdoutReader.Close()
+		return nil, nil, fmt.Errorf("failed to attach to workload %s: %w", workloadName, err)
+	}
+
+	go func() {
+		if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+			logger.Debugf("Attachment to workload %s ended: %v", workloadName, err)
+		}
+	}()
+
+	return stdinWriter, stdoutReader, nil
+}
+
+// IsRunning checks the health of containerd
+func (c *Client) IsRunning(ctx context.Context) error {
+	if _, err := c.cli.Run(ctx, "info"); err != nil {
+		return fmt.Errorf("failed to reach containerd: %w", err)
+	}
+	return nil
+}
+
+// UserNamespace returns the user namespace setup of containerd
+func (c *Client) UserNamespace(ctx context.Context) (runtime.UserNamespaceInfo, error) {
+	mode, err := c.userNamespaceMode(ctx)
+	if err != nil {
+		return runtime.UserNamespaceInfo{}, err
+	}
+	return docker.NewUserNamespaceInfo(runtime.TypeContainerd, docker.NewUserNamespaceSetup(mode, "")), nil
+}
+
+// userNamespaceMode detects whether containerd is rootless, from the Docker-compatible info of nerdctl
+func (c *Client) userNamespaceMode(ctx context.Context) (runtime.UserNamespace, error) {
+	output, err := c.cli.Run(ctx, "info", "--format", "{{json .SecurityOptions}}")
+	if err != nil {
+		return "", fmt.Errorf("failed to get containerd info: %w", err)
+	}
+	var securityOptions []string
+	if err := json.Unmarshal(bytes.TrimSpace(output), &securityOptions); err != nil {
+		return "", fmt.Errorf("failed to parse containerd info: %w", err)
+	}
+	return docker.UserNamespaceFromSecurityOptions(securityOptions), nil
+}
+
+// imageUser returns the user an image is configured to run as
+func (c *Client) imageUser(ctx context.Context, image string) (string, error) {
+	output, err := c.cli.Run(ctx, "image", "inspect", "--format", "{{.Config.User}}", image)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect image %s: %w", image, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// inspect returns the inspection of containers by name or ID
+func (c *Client) inspect(ctx context.Context, containers ...string) ([]containerInspect, error) {
+	output, err := c.cli.Run(ctx, append([]string{"container", "inspect"}, containers...)...)
+	if err != nil {
+		if errors.Is(err, nerdctl.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", runtime.ErrWorkloadNotFound, strings.Join(containers, ", "))
+		}
+		return nil, fmt.Errorf("failed to inspect containers: %w", err)
+	}
+
+	var result []containerInspect
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse container inspection: %w", err)
+	}
+	return result, nil
+}
+
+// inspectOne returns the inspection of a single container
+func (c *Client) inspectOne(ctx context.Context, workloadName string) (*containerInspect, error) {
+	containers, err := c.inspect(ctx, workloadName)
+	if err != nil {
+		return nil, err
+	}
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("%w: %s", runtime.ErrWorkloadNotFound, workloadName)
+	}
+	return &containers[0], nil
+}
+
+// info converts the inspection of a container to the runtime representation
+func (ctr *containerInspect) info() runtime.ContainerInfo {
+	ports := make([]runtime.PortMapping, 0)
+	for _, port := range sortedKeys(ctr.NetworkSettings.Ports) {
+		portValue, protocol, _ := strings.Cut(port, "/")
+		containerPort, err := strconv.Atoi(portValue)
+		if err != nil {
+			logger.Warnf("Warning: Failed to parse container port %s: %v", port, err)
+			continue
+		}
+		for _, binding := range ctr.NetworkSettings.Ports[port] {
+			hostPort, err := strconv.Atoi(binding.HostPort)
+			if err != nil {
+				logger.Warnf("Warning: Failed to parse host port %s: %v", binding.HostPort, err)
+			}
+			ports = append(ports, runtime.PortMapping{
+				ContainerPort: containerPort,
+				HostPort:      hostPort,
+				Protocol:      protocol,
+			})
+		}
+	}
+
+	created, err := time.Parse(time.RFC3339Nano, ctr.Created)
+	if err != nil {
+		created = time.Time{}
+	}
+
+	status := ctr.State.Status
+	if ctr.State.Status == "exited" {
+		status = fmt.Sprintf("Exited (%d)", ctr.State.ExitCode)
+	}
+
+	return runtime.ContainerInfo{
+		Name:    strings.TrimPrefix(ctr.Name, "/"),
+		Image:   ctr.Image,
+		Status:  status,
+		State:   toDomainStatus(ctr.State.Status),
+		Created: created,
+		Labels:  ctr.Config.Labels,
+		Ports:   ports,
+	}
+}
+
+// toDomainStatus converts the container status reported by nerdctl to a workload status
+func toDomainStatus(status string) runtime.WorkloadStatus {
+	switch status {
+	case "running":
+		return runtime.WorkloadStatusRunning
+	case "created", "restarting":
+		return runtime.WorkloadStatusStarting
+	case "paused", "pausing", "exited", "dead":
+		return runtime.WorkloadStatusStopped
+	case "removing":
+		return runtime.WorkloadStatusRemoving
+	}
+	return runtime.WorkloadStatusUnknown
+}
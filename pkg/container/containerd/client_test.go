@@ -0,0 +1,397 @@
+package containerd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/container/containerd/nerdctl"
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+// fakeCLI records nerdctl invocations and answers them from a table of outputs
+type fakeCLI struct {
+	mu      sync.Mutex
+	calls   [][]string
+	outputs map[string]string
+	errs    map[string]error
+	// files holds the contents of files referenced by the arguments when they were run
+	files map[string]string
+}
+
+func newFakeCLI() *fakeCLI {
+	return &fakeCLI{outputs: map[string]string{}, errs: map[string]error{}, files: map[string]string{}}
+}
+
+func (f *fakeCLI) Run(_ context.Context, args ...string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, args)
+	for i, arg := range args {
+		if i > 0 && (args[i-1] == "--env-file" || strings.HasPrefix(arg, "seccomp=/")) {
+			path := strings.TrimPrefix(arg, "seccomp=")
+			if data, err := os.ReadFile(path); err == nil {
+				f.files[path] = string(data)
+			}
+		}
+	}
+	key := strings.Join(args, " ")
+	if err, ok := f.errs[key]; ok {
+		return nil, err
+	}
+	return []byte(f.outputs[key]), nil
+}
+
+func (*fakeCLI) Command(ctx context.Context, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, "echo", args...)
+}
+
+func (f *fakeCLI) call(command string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, call := range f.calls {
+		if call[0] == command {
+			return call
+		}
+	}
+	return nil
+}
+
+func notFound(what string) error {
+	return fmt.Errorf("nerdctl: %w: no such container: %s", nerdctl.ErrNotFound, what)
+}
+
+const inspectRunning = `[{
+  "Id": "abc123",
+  "Name": "fetch",
+  "Created": "2025-01-02T03:04:05.123456Z",
+  "Image": "ghcr.io/example/fetch:latest",
+  "State": {"Status": "running", "Running": true, "ExitCode": 0},
+  "Config": {"Labels": {"toolhive": "true", "toolhive-name": "fetch"}},
+  "NetworkSettings": {"Ports": {"8080/tcp": [{"HostIp": "127.0.0.1", "HostPort": "43210"}]}}
+}]`
+
+func TestDeployWorkload(t *testing.T) {
+	t.Parallel()
+
+	cli := newFakeCLI()
+	client := &Client{cli: cli}
+
+	profile := permissions.BuiltinNoneProfile()
+	profile.Read = []permissions.MountDeclaration{"/etc/hosts:/data/hosts"}
+
+	labels := map[string]string{"toolhive": "true", "toolhive-name": "fetch"}
+	options := runtime.NewDeployWorkloadOptions()
+	options.PortBindings = map[string][]runtime.PortBinding{"8080/tcp": {{HostIP: "127.0.0.1", HostPort: "0"}}}
+
+	hostPort, err := client.DeployWorkload(context.Background(), "ghcr.io/example/fetch:latest", "fetch",
+		[]string{"--verbose"}, map[string]string{"MCP_PORT": "8080"}, labels, profile, "sse", options, false)
+	require.NoError(t, err)
+	assert.NotZero(t, hostPort)
+
+	remove := cli.call("rm")
+	assert.Equal(t, []string{"rm", "-f", "fetch"}, remove)
+
+	run := cli.call("run")
+	require.NotNil(t, run)
+	joined := strings.Join(run, " ")
+	assert.Contains(t, joined, "--name fetch")
+	assert.Contains(t, joined, "--label toolhive=true")
+	assert.Contains(t, joined, "--cap-drop ALL")
+	assert.Contains(t, joined, "--mount type=bind,source=/etc/hosts,target=/data/hosts,readonly")
+	assert.Contains(t, joined, fmt.Sprintf("--publish 127.0.0.1:%d:8080/tcp", hostPort))
+	assert.NotContains(t, joined, "label:disable")
+	assert.Equal(t, []string{"ghcr.io/example/fetch:latest", "--verbose"}, run[len(run)-2:])
+
+	// Environment variables are not passed on the command line
+	assert.NotContains(t, joined, "MCP_PORT")
+	for i, arg := range run {
+		if arg == "--env-file" {
+			assert.Equal(t, "MCP_PORT=8080\n", cli.files[run[i+1]])
+		}
+	}
+}
+
+func TestDeployWorkloadIsolatesStdioWithoutNetwork(t *testing.T) {
+	t.Parallel()
+
+	cli := newFakeCLI()
+	client := &Client{cli: cli}
+
+	labels := map[string]string{"toolhive": "true"}
+	hostPort, err := client.DeployWorkload(context.Background(), "example:latest", "stdio-server",
+		nil, nil, labels, permissions.BuiltinNoneProfile(), "stdio", &runtime.DeployWorkloadOptions{AttachStdio: true}, true)
+	require.NoError(t, err)
+	assert.Zero(t, hostPort)
+
+	joined := strings.Join(cli.call("run"), " ")
+	assert.Contains(t, joined, "--interactive")
+	assert.Contains(t, joined, "--network none")
+	assert.NotContains(t, joined, "--publish")
+	assert.Equal(t, "true", labels["toolhive-network-isolation"])
+}
+
+func TestDeployWorkloadRefusesUnsupportedIsolation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		transportType string
+		profile       *permissions.Profile
+	}{
+		{name: "outbound network permissions", transportType: "stdio", profile: permissions.BuiltinNetworkProfile()},
+		{name: "HTTP transport", transportType: "sse", profile: permissions.BuiltinNoneProfile()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cli := newFakeCLI()
+			client := &Client{cli: cli}
+			_, err := client.DeployWorkload(context.Background(), "example:latest", "fetch",
+				nil, nil, map[string]string{}, tt.profile, tt.transportType, runtime.NewDeployWorkloadOptions(), true)
+			require.ErrorContains(t, err, "cannot filter egress traffic")
+			assert.Nil(t, cli.call("run"), "the workload should not start")
+		})
+	}
+}
+
+func TestDeployWorkloadWithServices(t *testing.T) {
+	t.Parallel()
+
+	cli := newFakeCLI()
+	cli.outputs["ps --all --quiet --no-trunc --filter label=toolhive-service-of=pg"] = "old123\n"
+	cli.outputs["container inspect pg-svc-db"] = `[{"Name": "pg-svc-db", "NetworkSettings": {"IPAddress": "10.4.0.7"}}]`
+	client := &Client{cli: cli}
+
+	options := &runtime.DeployWorkloadOptions{AttachStdio: true, Services: []runtime.ServiceContainer{{
+		Name:        "db",
+		Image:       "postgres:16",
+		EnvVars:     map[string]string{"POSTGRES_PASSWORD": "test"},
+		HealthCheck: &runtime.HealthCheck{Command: []string{"pg_isready", "-U", "postgres"}},
+	}}}
+	_, err := client.DeployWorkload(context.Background(), "example:latest", "pg",
+		nil, nil, map[string]string{}, permissions.BuiltinNoneProfile(), "stdio", options, false)
+	require.NoError(t, err)
+
+	// The previous services are replaced, and the service is healthy before the MCP server starts
+	var commands []string
+	for _, call := range cli.calls {
+		commands = append(commands, strings.Join(call[:2], " "))
+	}
+	assert.Equal(t, []string{
+		"ps --all", "rm --force", "run --detach", "exec pg-svc-db", "container inspect", "rm -f", "run --detach",
+	}, commands)
+	assert.Equal(t, []string{"rm", "--force", "old123"}, cli.calls[1])
+	assert.Equal(t, []string{"exec", "pg-svc-db", "pg_isready", "-U", "postgres"}, cli.calls[3])
+
+	service := strings.Join(cli.calls[2], " ")
+	assert.Contains(t, service, "--name pg-svc-db")
+	assert.Contains(t, service, "--label toolhive-service-of=pg")
+	assert.True(t, strings.HasSuffix(service, " postgres:16"))
+
+	mcp := cli.calls[len(cli.calls)-1]
+	assert.Contains(t, strings.Join(mcp, " "), "--add-host db:10.4.0.7")
+	assert.Equal(t, "example:latest", mcp[len(mcp)-1])
+}
+
+func TestDeployWorkloadWithUnhealthyService(t *testing.T) {
+	t.Parallel()
+
+	cli := newFakeCLI()
+	cli.errs["exec broken-svc-db pg_isready"] = fmt.Errorf("exit status 2")
+	client := &Client{cli: cli}
+
+	options := &runtime.DeployWorkloadOptions{AttachStdio: true, Services: []runtime.ServiceContainer{{
+		Name:        "db",
+		Image:       "postgres:16",
+		HealthCheck: &runtime.HealthCheck{Command: []string{"pg_isready"}, Retries: 1},
+	}}}
+	_, err := client.DeployWorkload(context.Background(), "example:latest", "broken",
+		nil, nil, map[string]string{}, permissions.BuiltinNoneProfile(), "stdio", options, false)
+	require.ErrorContains(t, err, "service db is unhealthy")
+	assert.Equal(t, []string{"exec", "broken-svc-db", "pg_isready"}, cli.calls[len(cli.calls)-1],
+		"the MCP server should not start")
+}
+
+func TestDeployWorkloadWithServicesWithoutNetwork(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{cli: newFakeCLI()}
+	options := &runtime.DeployWorkloadOptions{
+		AttachStdio: true,
+		Services:    []runtime.ServiceContainer{{Name: "db", Image: "postgres:16"}},
+	}
+	_, err := client.DeployWorkload(context.Background(), "example:latest", "isolated",
+		nil, nil, map[string]string{}, permissions.BuiltinNoneProfile(), "stdio", options, true)
+	require.ErrorContains(t, err, "cannot reach its services")
+}
+
+func TestRemoveWorkloadRemovesServices(t *testing.T) {
+	t.Parallel()
+
+	cli := newFakeCLI()
+	cli.outputs["ps --all --quiet --no-trunc --filter label=toolhive-service-of=pg"] = "svc1\nsvc2\n"
+	client := &Client{cli: cli}
+
+	require.NoError(t, client.RemoveWorkload(context.Background(), "pg"))
+	assert.Equal(t, []string{"rm", "--force", "pg"}, cli.calls[0])
+	assert.Equal(t, []string{"rm", "--force", "svc1", "svc2"}, cli.calls[2])
+}
+
+func TestDeployWorkloadWriteMountsWithRootlessContainerd(t *testing.T) {
+	t.Parallel()
+
+	cli := newFakeCLI()
+	cli.outputs["info --format {{json .SecurityOptions}}"] = `["name=seccomp,profile=default","name=rootless"]` + "\n"
+	cli.outputs["image inspect --format {{.Config.User}} example:latest"] = "node\n"
+	client := &Client{cli: cli}
+
+	profile := permissions.BuiltinNoneProfile()
+	profile.Write = []permissions.MountDeclaration{"/tmp/project:/project"}
+
+	// The image runs as a user name, whose UID on the host is unknown
+	_, err := client.DeployWorkload(context.Background(), "example:latest", "writer",
+		nil, nil, map[string]string{}, profile, "stdio", &runtime.DeployWorkloadOptions{AttachStdio: true}, false)
+	require.Error(t, err)
+	assert.Nil(t, cli.call("run"))
+
+	// The root user of the container is the user running rootless containerd
+	cli.outputs["image inspect --format {{.Config.User}} example:latest"] = "\n"
+	_, err = client.DeployWorkload(context.Background(), "example:latest", "writer",
+		nil, nil, map[string]string{}, profile, "stdio", &runtime.DeployWorkloadOptions{AttachStdio: true}, false)
+	require.NoError(t, err)
+
+	joined := strings.Join(cli.call("run"), " ")
+	assert.Contains(t, joined, "--mount type=bind,source=/tmp/project,target=/project")
+}
+
+func TestUserNamespace(t *testing.T) {
+	t.Parallel()
+
+	cli := newFakeCLI()
+	cli.outputs["info --format {{json .SecurityOptions}}"] = "null\n"
+	client := &Client{cli: cli}
+
+	info, err := client.UserNamespace(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, runtime.UserNamespaceHost, info.Mode)
+	assert.Empty(t, info.Warnings)
+}
+
+func TestRunArgsSecurityOptions(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	config := &runtime.PermissionConfig{
+		SecurityOpt:    []string{"label:disable", `seccomp={"defaultAction":"SCMP_ACT_ERRNO"}`, "no-new-privileges"},
+		ReadOnlyRootfs: true,
+		User:           "1000:1000",
+		Resources:      &permissions.ResourceLimits{CPUs: "0.5", Memory: "256Mi", PidsLimit: 64},
+		Mounts:         []runtime.Mount{{Type: runtime.MountTypeTmpfs, Target: "/tmp"}},
+	}
+
+	args, err := runArgs(dir, "server", nil, nil, config, nil, false)
+	require.NoError(t, err)
+	joined := strings.Join(args, " ")
+
+	assert.Contains(t, joined, "--security-opt seccomp="+dir+"/seccomp.json")
+	assert.Contains(t, joined, "--security-opt no-new-privileges")
+	assert.NotContains(t, joined, "label:disable")
+	assert.Contains(t, joined, "--read-only")
+	assert.Contains(t, joined, "--user 1000:1000")
+	assert.Contains(t, joined, "--cpus 0.5")
+	assert.Contains(t, joined, "--memory 268435456")
+	assert.Contains(t, joined, "--pids-limit 64")
+	assert.Contains(t, joined, "--tmpfs /tmp")
+
+	data, err := os.ReadFile(dir + "/seccomp.json")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"defaultAction":"SCMP_ACT_ERRNO"}`, string(data))
+}
+
+func TestRunArgsRejectsMultilineEnv(t *testing.T) {
+	t.Parallel()
+
+	_, err := runArgs(t.TempDir(), "server", map[string]string{"KEY": "a\nb"}, nil, &runtime.PermissionConfig{}, nil, false)
+	require.Error(t, err)
+}
+
+func TestGetWorkloadInfo(t *testing.T) {
+	t.Parallel()
+
+	cli := newFakeCLI()
+	cli.outputs["container inspect fetch"] = inspectRunning
+	client := &Client{cli: cli}
+
+	info, err := client.GetWorkloadInfo(context.Background(), "fetch")
+	require.NoError(t, err)
+	assert.Equal(t, "fetch", info.Name)
+	assert.Equal(t, "ghcr.io/example/fetch:latest", info.Image)
+	assert.Equal(t, runtime.WorkloadStatusRunning, info.State)
+	assert.Equal(t, 2025, info.Created.Year())
+	assert.Equal(t, []runtime.PortMapping{{ContainerPort: 8080, HostPort: 43210, Protocol: "tcp"}}, info.Ports)
+
+	running, err := client.IsWorkloadRunning(context.Background(), "fetch")
+	require.NoError(t, err)
+	assert.True(t, running)
+}
+
+func TestWorkloadNotFound(t *testing.T) {
+	t.Parallel()
+
+	cli := newFakeCLI()
+	cli.errs["container inspect missing"] = notFound("missing")
+	cli.errs["stop --time 30 missing"] = notFound("missing")
+	cli.errs["rm --force missing"] = notFound("missing")
+	client := &Client{cli: cli}
+	ctx := context.Background()
+
+	_, err := client.GetWorkloadInfo(ctx, "missing")
+	require.ErrorIs(t, err, runtime.ErrWorkloadNotFound)
+
+	_, err = client.GetWorkloadLogs(ctx, "missing", false)
+	require.ErrorIs(t, err, runtime.ErrWorkloadNotFound)
+
+	require.NoError(t, client.StopWorkload(ctx, "missing"))
+	require.NoError(t, client.RemoveWorkload(ctx, "missing"))
+}
+
+func TestListWorkloads(t *testing.T) {
+	t.Parallel()
+
+	cli := newFakeCLI()
+	cli.outputs["ps --all --quiet --no-trunc --filter label=toolhive=true"] = "abc123\ndef456\n"
+	cli.outputs["container inspect abc123 def456"] = `[
+  {"Id": "abc123", "Name": "fetch", "State": {"Status": "exited", "ExitCode": 1},
+   "Config": {"Labels": {"toolhive": "true"}}},
+  {"Id": "def456", "Name": "fetch-dns", "State": {"Status": "running", "Running": true},
+   "Config": {"Labels": {"toolhive": "true", "toolhive-auxiliary-workload": "true"}}}
+]`
+	client := &Client{cli: cli}
+
+	workloads, err := client.ListWorkloads(context.Background())
+	require.NoError(t, err)
+	require.Len(t, workloads, 1)
+	assert.Equal(t, "fetch", workloads[0].Name)
+	assert.Equal(t, "Exited (1)", workloads[0].Status)
+	assert.Equal(t, runtime.WorkloadStatusStopped, workloads[0].State)
+}
+
+func TestListWorkloadsEmpty(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{cli: newFakeCLI()}
+
+	workloads, err := client.ListWorkloads(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, workloads)
+}
@@ -0,0 +1,148 @@
+// Package nerdctl runs nerdctl, the Docker-compatible CLI of containerd, against
+// the containerd socket. It is shared by the containerd runtime and image manager.
+package nerdctl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// Environment variable names
+const (
+	// ContainerdSocketEnv is the environment variable for a custom containerd socket path
+	ContainerdSocketEnv = "TOOLHIVE_CONTAINERD_SOCKET"
+	// NamespaceEnv is the environment variable for the containerd namespace of the workloads
+	NamespaceEnv = "TOOLHIVE_CONTAINERD_NAMESPACE"
+)
+
+const (
+	// ContainerdSocketPath is the default containerd socket path
+	ContainerdSocketPath = "/run/containerd/containerd.sock"
+	// ContainerdVarRunSocketPath is the containerd socket path on systems where /var/run is not a symlink
+	ContainerdVarRunSocketPath = "/var/run/containerd/containerd.sock"
+	// ContainerdXDGRuntimeSocketPath is the XDG runtime containerd socket path
+	ContainerdXDGRuntimeSocketPath = "containerd/containerd.sock"
+	// DefaultNamespace is the containerd namespace used by nerdctl by default
+	DefaultNamespace = "default"
+)
+
+// ErrNotFound is returned when nerdctl reports that a container or image does not exist
+var ErrNotFound = errors.New("not found")
+
+// CLI runs nerdctl commands against a containerd socket
+type CLI struct {
+	path      string
+	address   string
+	namespace string
+}
+
+// New finds nerdctl and the containerd socket, and verifies that the socket accepts connections
+func New() (*CLI, error) {
+	path, err := exec.LookPath("nerdctl")
+	if err != nil {
+		return nil, fmt.Errorf("nerdctl not found: %w", err)
+	}
+
+	address, err := FindSocket()
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := strings.TrimSpace(os.Getenv(NamespaceEnv))
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+
+	return &CLI{path: path, address: address, namespace: namespace}, nil
+}
+
+// FindSocket returns the path of the first containerd socket which accepts connections
+func FindSocket() (string, error) {
+	if customSocketPath := os.Getenv(ContainerdSocketEnv); customSocketPath != "" {
+		logger.Debugf("Using containerd socket from env: %s", customSocketPath)
+		if err := dialSocket(customSocketPath); err != nil {
+			return "", fmt.Errorf("invalid containerd socket path specified in %s: %w", ContainerdSocketEnv, err)
+		}
+		return customSocketPath, nil
+	}
+
+	candidates := []string{ContainerdSocketPath, ContainerdVarRunSocketPath}
+	if xdgRuntimeDir := os.Getenv("XDG_RUNTIME_DIR"); xdgRuntimeDir != "" {
+		candidates = append(candidates, filepath.Join(xdgRuntimeDir, ContainerdXDGRuntimeSocketPath))
+	}
+
+	var lastErr error
+	for _, candidate := range candidates {
+		if err := dialSocket(candidate); err != nil {
+			lastErr = err
+			continue
+		}
+		logger.Debugf("Found containerd socket at %s", candidate)
+		return candidate, nil
+	}
+	return "", fmt.Errorf("containerd socket not found: %w", lastErr)
+}
+
+// dialSocket checks that a unix socket exists and accepts connections from the current user
+func dialSocket(path string) error {
+	conn, err := net.DialTimeout("unix", path, 2*time.Second)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// Address returns the path of the containerd socket
+func (c *CLI) Address() string {
+	return c.address
+}
+
+// Namespace returns the containerd namespace of the commands
+func (c *CLI) Namespace() string {
+	return c.namespace
+}
+
+// Command returns an unstarted nerdctl command, for operations which stream their input or output
+func (c *CLI) Command(ctx context.Context, args ...string) *exec.Cmd {
+	fullArgs := append([]string{"--address", c.address, "--namespace", c.namespace}, args...)
+	// #nosec G204 -- the arguments are built by ToolHive
+	return exec.CommandContext(ctx, c.path, fullArgs...)
+}
+
+// Run runs a nerdctl command and returns its standard output. If the command fails,
+// the error includes its standard error, and wraps ErrNotFound if nerdctl reported
+// a missing container or image.
+func (c *CLI) Run(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := c.Command(ctx, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if isNotFound(message) {
+			return nil, fmt.Errorf("nerdctl %s: %w: %s", args[0], ErrNotFound, message)
+		}
+		return nil, fmt.Errorf("nerdctl %s failed: %w: %s", args[0], err, message)
+	}
+	return stdout.Bytes(), nil
+}
+
+// isNotFound returns true if the error output of nerdctl reports a missing object
+func isNotFound(message string) bool {
+	message = strings.ToLower(message)
+	return strings.Contains(message, "no such container") ||
+		strings.Contains(message, "no such image") ||
+		strings.Contains(message, "no such object") ||
+		strings.Contains(message, "not found")
+}
@@ -0,0 +1,148 @@
+package containerd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/container/containerd/nerdctl"
+	"github.com/stacklok/toolhive/pkg/container/docker"
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	lb "github.com/stacklok/toolhive/pkg/labels"
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// startServices replaces the service containers of a workload and starts them in the order they
+// are declared, waiting for each service with a health check to be healthy before starting the
+// next one. It returns the arguments of nerdctl run which let the workload reach the services
+// by their names, as nerdctl has no network aliases.
+func (c *Client) startServices(
+	ctx context.Context,
+	dir string,
+	workloadName string,
+	services []runtime.ServiceContainer,
+) ([]string, error) {
+	if err := c.removeServices(ctx, workloadName); err != nil {
+		return nil, err
+	}
+
+	var hostArgs []string
+	for _, service := range services {
+		logger.Infof("Starting service %s of %s with image %s...", service.Name, workloadName, service.Image)
+		containerName := runtime.ServiceContainerName(workloadName, service.Name)
+		labels := map[string]string{
+			lb.LabelToolHive:                      lb.LabelToolHiveValue,
+			lb.LabelName:                          containerName,
+			lb.LabelServiceOf:                     workloadName,
+			docker.ToolhiveAuxiliaryWorkloadLabel: docker.LabelValueTrue,
+		}
+
+		serviceDir := filepath.Join(dir, service.Name)
+		if err := os.Mkdir(serviceDir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		args, err := runArgs(serviceDir, containerName, service.EnvVars, labels, &runtime.PermissionConfig{}, nil, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid service %s: %w", service.Name, err)
+		}
+		args = append(args, service.Image)
+		args = append(args, service.Command...)
+		if _, err := c.cli.Run(ctx, args...); err != nil {
+			return nil, fmt.Errorf("failed to create service %s: %w", service.Name, err)
+		}
+
+		if service.HealthCheck != nil {
+			if err := c.waitForServiceHealthy(ctx, containerName, service); err != nil {
+				return nil, err
+			}
+			logger.Infof("Service %s of %s is healthy", service.Name, workloadName)
+		}
+
+		ctr, err := c.inspectOne(ctx, containerName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect service %s: %w", service.Name, err)
+		}
+		if ctr.NetworkSettings.IPAddress == "" {
+			return nil, fmt.Errorf("service %s has no IP address", service.Name)
+		}
+		hostArgs = append(hostArgs, "--add-host", service.Name+":"+ctr.NetworkSettings.IPAddress)
+	}
+	return hostArgs, nil
+}
+
+// waitForServiceHealthy runs the health check of a service until it succeeds, like the health
+// checks of Docker: failures during the start period don't count, and the service is unhealthy
+// after as many consecutive failures as the retries of the check.
+func (c *Client) waitForServiceHealthy(ctx context.Context, containerName string, service runtime.ServiceContainer) error {
+	check := service.HealthCheck
+	started := time.Now()
+	failures := 0
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, check.Timeout())
+		_, err := c.cli.Run(checkCtx, append([]string{"exec", containerName}, check.Command...)...)
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		if time.Since(started) >= check.StartPeriod() {
+			failures++
+		}
+		if failures >= check.RetryCount() {
+			return fmt.Errorf("service %s is unhealthy: %w", service.Name, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for service %s to be healthy: %w", service.Name, ctx.Err())
+		case <-time.After(check.Interval()):
+		}
+	}
+}
+
+// listServices returns the IDs of the service containers of a workload
+func (c *Client) listServices(ctx context.Context, workloadName string) ([]string, error) {
+	output, err := c.cli.Run(ctx, "ps", "--all", "--quiet", "--no-trunc",
+		"--filter", fmt.Sprintf("label=%s=%s", lb.LabelServiceOf, workloadName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the services of %s: %w", workloadName, err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// stopServices stops the service containers of a workload. Errors are logged, as the
+// workload itself is already stopped.
+func (c *Client) stopServices(ctx context.Context, workloadName string) {
+	ids, err := c.listServices(ctx, workloadName)
+	if err != nil {
+		logger.Debugf("Failed to stop services: %v", err)
+		return
+	}
+	if len(ids) == 0 {
+		return
+	}
+	args := append([]string{"stop", "--time", strconv.Itoa(stopTimeoutSeconds)}, ids...)
+	if _, err := c.cli.Run(ctx, args...); err != nil && !errors.Is(err, nerdctl.ErrNotFound) {
+		logger.Debugf("Failed to stop the services of %s: %v", workloadName, err)
+	}
+}
+
+// removeServices removes the service containers of a workload
+func (c *Client) removeServices(ctx context.Context, workloadName string) error {
+	ids, err := c.listServices(ctx, workloadName)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := c.cli.Run(ctx, append([]string{"rm", "--force"}, ids...)...); err != nil && !errors.Is(err, nerdctl.ErrNotFound) {
+		return fmt.Errorf("failed to remove the services of %s: %w", workloadName, err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+//go:build linux
+// +build linux
+
+package docker
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+const (
+	// aclAccessAttr and aclDefaultAttr are the extended attributes of the POSIX ACLs of a file,
+	// and of the ACLs inherited by the files created in a directory
+	aclAccessAttr  = "system.posix_acl_access"
+	aclDefaultAttr = "system.posix_acl_default"
+	// aclVersion is the version of the extended attribute format of POSIX ACLs
+	aclVersion = 2
+	// aclUndefinedID is the ID of the ACL entries which do not name a user or group
+	aclUndefinedID = 0xffffffff
+)
+
+// Tags of the entries of POSIX ACLs, in the order the kernel requires
+const (
+	aclUserObj  uint16 = 0x01
+	aclUser     uint16 = 0x02
+	aclGroupObj uint16 = 0x04
+	aclGroup    uint16 = 0x08
+	aclMask     uint16 = 0x10
+	aclOther    uint16 = 0x20
+)
+
+// aclEntry is an entry of a POSIX ACL
+type aclEntry struct {
+	Tag  uint16
+	Perm uint16
+	ID   uint32
+}
+
+// grantWriteAccess grants a user of the host read and write access to the file or directory
+// tree at path with POSIX ACLs. Directories also get default ACLs, so that the files the user
+// creates stay accessible to that user and to your user. Only path itself must succeed; the
+// files within it which cannot be changed, such as those of other users, are logged.
+func grantWriteAccess(path string, uid uint32) error {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(resolved, func(name string, entry fs.DirEntry, err error) error {
+		if err == nil && entry.Type()&fs.ModeSymlink == 0 {
+			err = grantEntryWriteAccess(name, entry, uid)
+		}
+		if err != nil {
+			if name == resolved {
+				return err
+			}
+			logger.Debugf("Failed to grant UID %d write access to %s: %v", uid, name, err)
+		}
+		return nil
+	})
+}
+
+// grantEntryWriteAccess grants a user write access to a single file or directory
+func grantEntryWriteAccess(name string, entry fs.DirEntry, uid uint32) error {
+	info, err := entry.Info()
+	if err != nil {
+		return err
+	}
+	mode := info.Mode()
+	perm := uint16(unix.S_IROTH | unix.S_IWOTH)
+	if mode.IsDir() || mode&0100 != 0 {
+		perm |= unix.S_IXOTH
+	}
+
+	if err := addACLUser(name, aclAccessAttr, mode, uid, perm); err != nil {
+		return err
+	}
+	if !mode.IsDir() {
+		return nil
+	}
+	if err := addACLUser(name, aclDefaultAttr, mode, uid, perm); err != nil {
+		return err
+	}
+	// #nosec G115 -- UIDs fit in 32 bits
+	return addACLUser(name, aclDefaultAttr, mode, uint32(os.Getuid()), perm)
+}
+
+// addACLUser adds the permissions of a user to an ACL of a file, keeping its other entries
+func addACLUser(name, attr string, mode fs.FileMode, uid uint32, perm uint16) error {
+	entries, err := readACL(name, attr, mode)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for i := range entries {
+		if entries[i].Tag == aclUser && entries[i].ID == uid {
+			entries[i].Perm |= perm
+			found = true
+		}
+	}
+	if !found {
+		entries = append(entries, aclEntry{Tag: aclUser, Perm: perm, ID: uid})
+	}
+
+	// The mask limits the permissions of the named users and groups, and of the owning group
+	var mask uint16
+	maskIndex := -1
+	for i, entry := range entries {
+		switch entry.Tag {
+		case aclUser, aclGroupObj, aclGroup:
+			mask |= entry.Perm
+		case aclMask:
+			maskIndex = i
+		}
+	}
+	if maskIndex >= 0 {
+		entries[maskIndex].Perm = mask
+	} else {
+		entries = append(entries, aclEntry{Tag: aclMask, Perm: mask, ID: aclUndefinedID})
+	}
+
+	return writeACL(name, attr, entries)
+}
+
+// readACL reads an ACL of a file. A missing access ACL, or default ACL, is derived from the mode.
+func readACL(name, attr string, mode fs.FileMode) ([]aclEntry, error) {
+	size, err := unix.Getxattr(name, attr, nil)
+	if errors.Is(err, unix.ENODATA) {
+		return []aclEntry{
+			{Tag: aclUserObj, Perm: uint16(mode.Perm()>>6) & 7, ID: aclUndefinedID},
+			{Tag: aclGroupObj, Perm: uint16(mode.Perm()>>3) & 7, ID: aclUndefinedID},
+			{Tag: aclOther, Perm: uint16(mode.Perm()) & 7, ID: aclUndefinedID},
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ACL: %w", err)
+	}
+	data := make([]byte, size)
+	if size, err = unix.Getxattr(name, attr, data); err != nil {
+		return nil, fmt.Errorf("failed to read ACL: %w", err)
+	}
+	return decodeACL(data[:size])
+}
+
+// writeACL writes an ACL of a file
+func writeACL(name, attr string, entries []aclEntry) error {
+	if err := unix.Setxattr(name, attr, encodeACL(entries), 0); err != nil {
+		return fmt.Errorf("failed to write ACL: %w", err)
+	}
+	return nil
+}
+
+// decodeACL decodes an ACL from its extended attribute
+func decodeACL(data []byte) ([]aclEntry, error) {
+	if len(data) < 4 || (len(data)-4)%8 != 0 || binary.LittleEndian.Uint32(data) != aclVersion {
+		return nil, fmt.Errorf("unsupported ACL format")
+	}
+	entries := make([]aclEntry, 0, (len(data)-4)/8)
+	for offset := 4; offset < len(data); offset += 8 {
+		entries = append(entries, aclEntry{
+			Tag:  binary.LittleEndian.Uint16(data[offset:]),
+			Perm: binary.LittleEndian.Uint16(data[offset+2:]),
+			ID:   binary.LittleEndian.Uint32(data[offset+4:]),
+		})
+	}
+	return entries, nil
+}
+
+// encodeACL encodes an ACL as its extended attribute, with the entries in the required order
+func encodeACL(entries []aclEntry) []byte {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Tag != entries[j].Tag {
+			return entries[i].Tag < entries[j].Tag
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	data := binary.LittleEndian.AppendUint32(nil, aclVersion)
+	for _, entry := range entries {
+		data = binary.LittleEndian.AppendUint16(data, entry.Tag)
+		data = binary.LittleEndian.AppendUint16(data, entry.Perm)
+		data = binary.LittleEndian.AppendUint32(data, entry.ID)
+	}
+	return data
+}
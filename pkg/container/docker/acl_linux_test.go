@@ -0,0 +1,86 @@
+//go:build linux
+// +build linux
+
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+)
+
+// aclOf returns an ACL of a file, or nil if it has none
+func aclOf(t *testing.T, name, attr string) []aclEntry {
+	t.Helper()
+	data := make([]byte, 1024)
+	size, err := unix.Getxattr(name, attr, data)
+	if err != nil {
+		return nil
+	}
+	entries, err := decodeACL(data[:size])
+	require.NoError(t, err)
+	return entries
+}
+
+// aclUserPerm returns the permissions of a named user in an ACL
+func aclUserPerm(entries []aclEntry, uid uint32) (uint16, bool) {
+	for _, entry := range entries {
+		if entry.Tag == aclUser && entry.ID == uid {
+			return entry.Perm, true
+		}
+	}
+	return 0, false
+}
+
+func TestApplyUserNamespaceGrantsWriteAccess(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if err := unix.Setxattr(dir, aclAccessAttr, encodeACL([]aclEntry{
+		{Tag: aclUserObj, Perm: 7, ID: aclUndefinedID},
+		{Tag: aclGroupObj, Perm: 5, ID: aclUndefinedID},
+		{Tag: aclOther, Perm: 0, ID: aclUndefinedID},
+	}), 0); err != nil {
+		t.Skipf("the file system does not support ACLs: %v", err)
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "src"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "src", "main.py"), []byte("print()\n"), 0600))
+	require.NoError(t, os.Symlink("/etc/passwd", filepath.Join(dir, "passwd")))
+
+	setup := UserNamespaceSetup{
+		Mode:   runtime.UserNamespaceRemapped,
+		UIDMap: []IDRange{{ContainerID: 0, HostID: 231072, Size: 65536}},
+	}
+	config := &runtime.PermissionConfig{
+		User: "1000",
+		Mounts: []runtime.Mount{
+			{Source: dir, Target: "/project", Type: runtime.MountTypeBind},
+			{Source: "/etc", Target: "/host-etc", ReadOnly: true, Type: runtime.MountTypeBind},
+		},
+	}
+	require.NoError(t, ApplyUserNamespace(config, runtime.TypeDocker, setup, nil))
+
+	hostUID := uint32(232072)
+	perm, ok := aclUserPerm(aclOf(t, dir, aclAccessAttr), hostUID)
+	require.True(t, ok)
+	assert.Equal(t, uint16(7), perm)
+	_, ok = aclUserPerm(aclOf(t, dir, aclDefaultAttr), hostUID)
+	assert.True(t, ok)
+	// #nosec G115 -- UIDs fit in 32 bits
+	_, ok = aclUserPerm(aclOf(t, dir, aclDefaultAttr), uint32(os.Getuid()))
+	assert.True(t, ok)
+
+	perm, ok = aclUserPerm(aclOf(t, filepath.Join(dir, "src", "main.py"), aclAccessAttr), hostUID)
+	require.True(t, ok)
+	assert.Equal(t, uint16(6), perm)
+
+	// Symlinks and read-only mounts are left alone
+	_, ok = aclUserPerm(aclOf(t, "/etc/passwd", aclAccessAttr), hostUID)
+	assert.False(t, ok)
+}
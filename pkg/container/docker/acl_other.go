@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package docker
+
+import "errors"
+
+// grantWriteAccess is not supported on this platform, whose runtimes do not remap users
+func grantWriteAccess(_ string, _ uint32) error {
+	return errors.New("ACLs are only supported on Linux")
+}
@@ -18,12 +18,15 @@ import (
 	"github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	dockerimage "github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/stacklok/toolhive/pkg/container/docker/sdk"
 	"github.com/stacklok/toolhive/pkg/container/images"
@@ -33,6 +36,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/networking"
 	"github.com/stacklok/toolhive/pkg/permissions"
+	"github.com/stacklok/toolhive/pkg/telemetry"
 )
 
 // DnsImage is the default DNS image used for network permissions
@@ -41,6 +45,9 @@ const DnsImage = "dockurr/dnsmasq:latest"
 // RuntimeName is the name identifier for the Docker runtime
 const RuntimeName = "docker"
 
+// tracerName is the name of the tracer of Docker runtime operations
+const tracerName = "github.com/stacklok/toolhive/pkg/container/docker"
+
 // IsAvailable checks if Docker is available by attempting to connect to the Docker daemon
 func IsAvailable() bool {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -72,6 +79,8 @@ type dockerAPI interface {
 	) (container.CreateResponse, error)
 	ContainerStart(ctx context.Context, containerID string, options container.StartOptions) error
 	ContainerRemove(ctx context.Context, containerID string, options container.RemoveOptions) error
+	Info(ctx context.Context) (system.Info, error)
+	ImageInspect(ctx context.Context, imageID string, inspectOpts ...client.ImageInspectOption) (dockerimage.InspectResponse, error)
 }
 
 // deployOps defines the internal operations used by DeployWorkload.
@@ -95,6 +104,14 @@ type deployOps interface {
 		endpointsConfig map[string]*network.EndpointSettings,
 		perm *permissions.NetworkPermissions,
 	) (string, error)
+	createServiceContainers(
+		ctx context.Context,
+		workloadName string,
+		networkName string,
+		services []runtime.ServiceContainer,
+		additionalDNS string,
+		isolateNetwork bool,
+	) error
 	createMcpContainer(
 		ctx context.Context,
 		name string,
@@ -181,17 +198,49 @@ func (c *Client) DeployWorkload(
 	transportType string,
 	options *runtime.DeployWorkloadOptions,
 	isolateNetwork bool,
-) (int, error) {
+) (_ int, retErr error) {
+	ctx, span := telemetry.StartSpan(ctx, tracerName, "docker.DeployWorkload",
+		attribute.String("workload.name", name),
+		attribute.String("workload.image", image),
+		attribute.Bool("workload.isolate_network", isolateNetwork),
+	)
+	defer func() {
+		telemetry.EndSpan(span, retErr)
+	}()
+
 	// Get permission config from profile
 	var ignoreConfig *ignore.Config
+	var services []runtime.ServiceContainer
 	if options != nil {
 		ignoreConfig = options.IgnoreConfig
+		services = options.Services
 	}
 	permissionConfig, err := c.getPermissionConfigFromProfile(permissionProfile, transportType, ignoreConfig)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get permission config: %w", err)
 	}
 
+	// Make write mounts writable with rootless runtimes and user namespace remapping
+	if len(permissionProfile.Write) > 0 {
+		setup, err := c.userNamespaceSetup(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to detect the user namespace of the runtime for write mounts: %w", err)
+		}
+		imageUser := func() (string, error) { return c.imageUser(ctx, image) }
+		if err := ApplyUserNamespace(permissionConfig, c.runtimeType, setup, imageUser); err != nil {
+			return 0, err
+		}
+	}
+
+	// Mount secrets with file targets
+	if options != nil && len(options.SecretFiles) > 0 {
+		secretMounts, err := MountSecretFiles(name, options.SecretFiles)
+		if err != nil {
+			return 0, err
+		}
+		permissionConfig.Mounts = append(permissionConfig.Mounts, secretMounts...)
+	}
+
 	// Determine if we should attach stdio
 	attachStdio := options == nil || options.AttachStdio
 
@@ -245,10 +294,29 @@ func (c *Client) DeployWorkload(
 		}
 
 		envVars = addEgressEnvVars(envVars, egressContainerName)
+	} else if len(services) > 0 {
+		// The MCP server reaches its services on a network of the workload
+		workloadNetworkLabels := map[string]string{}
+		lb.AddNetworkLabels(workloadNetworkLabels, networkName)
+		err := c.ops.createNetwork(ctx, networkName, workloadNetworkLabels, false)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create workload network: %v", err)
+		}
 	} else {
 		networkName = ""
 	}
 
+	// Start the services before the MCP server, which may need them to start
+	if len(services) > 0 {
+		err := c.ops.createServiceContainers(ctx, name, networkName, services, additionalDNS, isolateNetwork)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create service containers: %w", err)
+		}
+		if isolateNetwork {
+			envVars = addServicesNoProxy(envVars, services)
+		}
+	}
+
 	// only remap if is not an auxiliary tool
 	newPortBindings, hostPort, err := generatePortBindings(labels, options.PortBindings)
 	if err != nil {
@@ -382,6 +450,9 @@ func (c *Client) StopWorkload(ctx context.Context, workloadName string) error {
 		return NewContainerError(err, workloadName, fmt.Sprintf("failed to stop workload: %v", err))
 	}
 
+	// Stop the services of the workload, which do not depend on network isolation
+	c.stopServiceContainers(ctx, strings.TrimPrefix(info.Name, "/"), timeoutSeconds)
+
 	// If network isolation is not enabled, then there is nothing else to do.
 	// NOTE: This check treats all workloads created before the introduction of
 	// this label as having network isolation enabled. This is to ensure that they
@@ -437,12 +508,22 @@ func (c *Client) RemoveWorkload(ctx context.Context, workloadName string) error
 		return err // removeProxyContainers already wraps the error with context.
 	}
 
+	// Remove the services of the workload before its network
+	err = c.removeServiceContainers(ctx, workloadName, nil)
+	if err != nil {
+		return err // removeServiceContainers already wraps the error with context.
+	}
+
 	// Clear up any networks associated with this workload.
 	// This also deletes the external network if no other workloads are using it.
 	err = c.deleteNetworks(ctx, containerName)
 	if err != nil {
 		logger.Warnf("Failed to delete networks for container %s: %v", containerName, err)
 	}
+
+	if err := RemoveSecretFiles(containerName); err != nil {
+		logger.Warnf("Failed to remove secret files for container %s: %v", containerName, err)
+	}
 	return nil
 }
 
@@ -484,6 +565,38 @@ func (c *Client) GetWorkloadLogs(ctx context.Context, workloadName string, follo
 	return buf.String(), nil
 }
 
+// StreamWorkloadLogs streams the output of a workload to stdout and stderr
+// until ctx is done or the container exits.
+func (c *Client) StreamWorkloadLogs(
+	ctx context.Context, workloadName string, since time.Time, stdout, stderr io.Writer,
+) error {
+	workloadContainer, err := c.inspectContainerByName(ctx, workloadName)
+	if err != nil {
+		return err
+	}
+
+	options := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	}
+	if !since.IsZero() {
+		options.Since = strconv.FormatInt(since.Unix(), 10)
+	}
+
+	logs, err := c.client.ContainerLogs(ctx, workloadContainer.ID, options)
+	if err != nil {
+		return NewContainerError(err, workloadName, fmt.Sprintf("failed to stream workload logs: %v", err))
+	}
+	defer logs.Close()
+
+	// Containers are created without a TTY, so the stream is multiplexed
+	if _, err := stdcopy.StdCopy(stdout, stderr, logs); err != nil && !errors.Is(err, io.EOF) && ctx.Err() == nil {
+		return NewContainerError(err, workloadName, fmt.Sprintf("failed to stream workload logs: %v", err))
+	}
+	return nil
+}
+
 // IsWorkloadRunning checks if a workload is running
 func (c *Client) IsWorkloadRunning(ctx context.Context, workloadName string) (bool, error) {
 	// Inspect workload
@@ -763,12 +876,18 @@ func (c *Client) getPermissionConfigFromProfile(
 		CapAdd:      []string{},
 		SecurityOpt: []string{"label:disable"},
 		Privileged:  profile.Privileged,
+		Resources:   profile.Resources,
 	}
 
 	// Add mounts
 	c.addReadOnlyMounts(config, profile.Read, ignoreConfig)
 	c.addReadWriteMounts(config, profile.Write, ignoreConfig)
 
+	// Apply capabilities, seccomp and AppArmor profiles, the root filesystem and the user
+	if err := addSecuritySettings(config, profile.Security); err != nil {
+		return nil, fmt.Errorf("invalid security settings: %w", err)
+	}
+
 	// Validate transport type
 	switch transportType {
 	case "sse", "stdio", "inspector", "streamable-http":
@@ -780,6 +899,64 @@ func (c *Client) getPermissionConfigFromProfile(
 	return config, nil
 }
 
+// PermissionConfigFromProfile converts a permission profile to a container permission config.
+// It is shared with the other runtimes which accept Docker-style container settings.
+func PermissionConfigFromProfile(
+	profile *permissions.Profile,
+	transportType string,
+	ignoreConfig *ignore.Config,
+) (*runtime.PermissionConfig, error) {
+	return (&Client{}).getPermissionConfigFromProfile(profile, transportType, ignoreConfig)
+}
+
+// addSecuritySettings adds the security settings of a permission profile to the permission config
+func addSecuritySettings(config *runtime.PermissionConfig, settings *permissions.SecuritySettings) error {
+	if settings == nil {
+		return nil
+	}
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	// All capabilities are dropped, so the capabilities of the profile are an allowlist
+	config.CapAdd = append(config.CapAdd, settings.NormalizedCapabilities()...)
+
+	switch {
+	case settings.Seccomp == "", settings.Seccomp == permissions.SeccompProfileRuntimeDefault:
+		// The runtime applies its default profile
+	case settings.Seccomp == permissions.SeccompProfileUnconfined:
+		config.SecurityOpt = append(config.SecurityOpt, "seccomp=unconfined")
+	case strings.HasPrefix(settings.Seccomp, permissions.SeccompProfileLocalhostPrefix):
+		return fmt.Errorf("seccomp profile %s is only supported by the Kubernetes runtime", settings.Seccomp)
+	default:
+		// The API expects the contents of the profile rather than its path
+		profile, err := settings.SeccompProfileJSON()
+		if err != nil {
+			return err
+		}
+		config.SecurityOpt = append(config.SecurityOpt, "seccomp="+profile)
+	}
+
+	if settings.AppArmor != "" && settings.AppArmor != permissions.AppArmorProfileRuntimeDefault {
+		config.SecurityOpt = append(config.SecurityOpt, "apparmor="+settings.AppArmor)
+	}
+
+	if settings.NoNewPrivileges {
+		config.SecurityOpt = append(config.SecurityOpt, "no-new-privileges")
+	}
+
+	config.ReadOnlyRootfs = settings.ReadOnlyRootFilesystem
+	config.User = settings.User
+	for _, target := range settings.Tmpfs {
+		config.Mounts = append(config.Mounts, runtime.Mount{
+			Target: target,
+			Type:   runtime.MountTypeTmpfs,
+		})
+	}
+
+	return nil
+}
+
 // findExistingContainer finds a container with the exact name
 // Uses container runtime's name filter for efficiency but then verifies exact match to prevent partial matching
 func (c *Client) findExistingContainer(ctx context.Context, name string) (string, error) {
@@ -842,6 +1019,11 @@ func compareBasicConfig(existing *container.InspectResponse, desired *container.
 		return false
 	}
 
+	// Compare user, which defaults to the user of the image when not set
+	if desired.User != "" && existing.Config.User != desired.User {
+		return false
+	}
+
 	return true
 }
 
@@ -911,11 +1093,46 @@ func compareHostConfig(existing *container.InspectResponse, desired *container.H
 		return false
 	}
 
+	// Compare root filesystem mode
+	if existing.HostConfig.ReadonlyRootfs != desired.ReadonlyRootfs {
+		return false
+	}
+
 	// Compare restart policy
 	if existing.HostConfig.RestartPolicy.Name != desired.RestartPolicy.Name {
 		return false
 	}
 
+	// Compare the user namespace, which is only set for workloads with write mounts
+	if desired.UsernsMode != "" && existing.HostConfig.UsernsMode != desired.UsernsMode {
+		return false
+	}
+
+	return compareResources(existing.HostConfig.Resources, desired.Resources)
+}
+
+// compareResources compares resource limits. Pids limits and ulimits are only compared when
+// they are set, as Podman applies default values for them.
+func compareResources(existing, desired container.Resources) bool {
+	if existing.NanoCPUs != desired.NanoCPUs || existing.Memory != desired.Memory {
+		return false
+	}
+
+	if desired.PidsLimit != nil && (existing.PidsLimit == nil || *existing.PidsLimit != *desired.PidsLimit) {
+		return false
+	}
+
+	if len(desired.Ulimits) > 0 {
+		if len(existing.Ulimits) != len(desired.Ulimits) {
+			return false
+		}
+		for i, ulimit := range desired.Ulimits {
+			if existing.Ulimits[i] == nil || *existing.Ulimits[i] != *ulimit {
+				return false
+			}
+		}
+	}
+
 	return true
 }
 
@@ -947,7 +1164,8 @@ func compareMounts(existing *container.InspectResponse, desired *container.HostC
 	// Check if all desired mounts exist in the container with matching source and read-only flag
 	for _, desiredMount := range desired.Mounts {
 		existingMount, exists := existingMountsMap[desiredMount.Target]
-		if !exists || existingMount.Source != desiredMount.Source || existingMount.ReadOnly != desiredMount.ReadOnly {
+		if !exists || existingMount.Type != desiredMount.Type || existingMount.Source != desiredMount.Source ||
+			existingMount.ReadOnly != desiredMount.ReadOnly {
 			return false
 		}
 	}
@@ -1065,7 +1283,15 @@ func (c *Client) createNetwork(
 	name string,
 	labels map[string]string,
 	internal bool,
-) error {
+) (retErr error) {
+	ctx, span := telemetry.StartSpan(ctx, tracerName, "docker.CreateNetwork",
+		attribute.String("network.name", name),
+		attribute.Bool("network.internal", internal),
+	)
+	defer func() {
+		telemetry.EndSpan(span, retErr)
+	}()
+
 	// Check if the network already exists
 	// Use name filter for efficiency but verify exact match to avoid partial matching
 	networks, err := c.client.NetworkList(ctx, network.ListOptions{
@@ -1203,7 +1429,15 @@ func convertEnvVars(envVars map[string]string) []string {
 func convertMounts(mounts []runtime.Mount) []mount.Mount {
 	result := make([]mount.Mount, 0, len(mounts))
 	for _, m := range mounts {
-		// All mounts are now bind mounts (removed tmpfs support for overlays)
+		if m.Type == runtime.MountTypeTmpfs {
+			result = append(result, mount.Mount{
+				Type:   mount.TypeTmpfs,
+				Target: m.Target,
+			})
+			continue
+		}
+
+		// Overlays for ignored paths are bind mounts as well
 		result = append(result, mount.Mount{
 			Type:     mount.TypeBind,
 			Source:   m.Source,
@@ -1264,7 +1498,15 @@ func (c *Client) createContainer(
 	config *container.Config,
 	hostConfig *container.HostConfig,
 	endpointsConfig map[string]*network.EndpointSettings,
-) (string, error) {
+) (_ string, retErr error) {
+	ctx, span := telemetry.StartSpan(ctx, tracerName, "docker.CreateContainer",
+		attribute.String("container.name", containerName),
+		attribute.String("container.image", config.Image),
+	)
+	defer func() {
+		telemetry.EndSpan(span, retErr)
+	}()
+
 	existingID, err := c.findExistingContainer(ctx, containerName)
 	if err != nil {
 		return "", err
@@ -1388,16 +1630,19 @@ func (c *Client) createMcpContainer(ctx context.Context, name string, networkNam
 		AttachStderr: attachStdio,
 		OpenStdin:    attachStdio,
 		Tty:          false,
+		User:         permissionConfig.User,
 	}
 
 	// Create host configuration
 	hostConfig := &container.HostConfig{
-		Mounts:      convertMounts(permissionConfig.Mounts),
-		NetworkMode: container.NetworkMode(permissionConfig.NetworkMode),
-		CapAdd:      permissionConfig.CapAdd,
-		CapDrop:     permissionConfig.CapDrop,
-		SecurityOpt: permissionConfig.SecurityOpt,
-		Privileged:  permissionConfig.Privileged,
+		Mounts:         convertMounts(permissionConfig.Mounts),
+		NetworkMode:    container.NetworkMode(permissionConfig.NetworkMode),
+		CapAdd:         permissionConfig.CapAdd,
+		CapDrop:        permissionConfig.CapDrop,
+		SecurityOpt:    permissionConfig.SecurityOpt,
+		Privileged:     permissionConfig.Privileged,
+		ReadonlyRootfs: permissionConfig.ReadOnlyRootfs,
+		UsernsMode:     container.UsernsMode(permissionConfig.UsernsMode),
 		RestartPolicy: container.RestartPolicy{
 			Name: "unless-stopped",
 		},
@@ -1406,6 +1651,13 @@ func (c *Client) createMcpContainer(ctx context.Context, name string, networkNam
 		hostConfig.DNS = []string{additionalDNS}
 	}
 
+	// Apply resource limits
+	resources, err := convertResourceLimits(permissionConfig.Resources)
+	if err != nil {
+		return NewContainerError(err, "", err.Error())
+	}
+	hostConfig.Resources = resources
+
 	// Configure ports if options are provided
 	// Setup exposed ports
 	if err := setupExposedPorts(config, exposedPorts); err != nil {
@@ -1428,8 +1680,14 @@ func (c *Client) createMcpContainer(ctx context.Context, name string, networkNam
 		internalEndpointsConfig["toolhive-external"] = &network.EndpointSettings{
 			NetworkID: "toolhive-external",
 		}
+		// and to the network of the workload, if it has services
+		if networkName != "" {
+			internalEndpointsConfig[networkName] = &network.EndpointSettings{
+				NetworkID: networkName,
+			}
+		}
 	}
-	_, err := c.createContainer(ctx, name, config, hostConfig, internalEndpointsConfig)
+	_, err = c.createContainer(ctx, name, config, hostConfig, internalEndpointsConfig)
 	if err != nil {
 		return fmt.Errorf("failed to create container: %v", err)
 	}
@@ -1438,6 +1696,40 @@ func (c *Client) createMcpContainer(ctx context.Context, name string, networkNam
 
 }
 
+// convertResourceLimits converts resource limits to Docker resources
+func convertResourceLimits(limits *permissions.ResourceLimits) (container.Resources, error) {
+	resources := container.Resources{}
+	if limits == nil {
+		return resources, nil
+	}
+
+	milliCPUs, err := limits.MilliCPUs()
+	if err != nil {
+		return resources, err
+	}
+	resources.NanoCPUs = milliCPUs * 1000000
+
+	resources.Memory, err = limits.MemoryBytes()
+	if err != nil {
+		return resources, err
+	}
+
+	if limits.PidsLimit > 0 {
+		pidsLimit := limits.PidsLimit
+		resources.PidsLimit = &pidsLimit
+	}
+
+	for _, ulimit := range limits.Ulimits {
+		resources.Ulimits = append(resources.Ulimits, &container.Ulimit{
+			Name: ulimit.Name,
+			Soft: ulimit.Soft,
+			Hard: ulimit.Hard,
+		})
+	}
+
+	return resources, nil
+}
+
 // addEgressEnvVars adds environment variables for egress proxy configuration.
 func addEgressEnvVars(envVars map[string]string, egressContainerName string) map[string]string {
 	egressHost := fmt.Sprintf("http://%s:3128", egressContainerName)
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/permissions"
 )
 
 func TestSetupExposedPorts_SetsPorts(t *testing.T) {
@@ -91,6 +92,58 @@ func TestConvertMounts_BindMounts(t *testing.T) {
 	assert.Equal(t, false, out[1].ReadOnly)
 }
 
+func TestConvertMounts_TmpfsMounts(t *testing.T) {
+	t.Parallel()
+
+	out := convertMounts([]runtime.Mount{{Target: "/tmp", Type: runtime.MountTypeTmpfs}})
+
+	require.Len(t, out, 1)
+	assert.Equal(t, mount.Mount{Type: mount.TypeTmpfs, Target: "/tmp"}, out[0])
+}
+
+func TestAddSecuritySettings(t *testing.T) {
+	t.Parallel()
+
+	config := &runtime.PermissionConfig{
+		CapDrop:     []string{"ALL"},
+		CapAdd:      []string{},
+		SecurityOpt: []string{"label:disable"},
+	}
+	err := addSecuritySettings(config, &permissions.SecuritySettings{
+		Capabilities:           []string{"cap_net_bind_service"},
+		Seccomp:                permissions.SeccompProfileUnconfined,
+		AppArmor:               "toolhive-mcp",
+		ReadOnlyRootFilesystem: true,
+		Tmpfs:                  []string{"/tmp"},
+		NoNewPrivileges:        true,
+		User:                   "1000:1000",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ALL"}, config.CapDrop)
+	assert.Equal(t, []string{"NET_BIND_SERVICE"}, config.CapAdd)
+	assert.Equal(t, []string{"label:disable", "seccomp=unconfined", "apparmor=toolhive-mcp", "no-new-privileges"},
+		config.SecurityOpt)
+	assert.True(t, config.ReadOnlyRootfs)
+	assert.Equal(t, "1000:1000", config.User)
+	assert.Equal(t, []runtime.Mount{{Target: "/tmp", Type: runtime.MountTypeTmpfs}}, config.Mounts)
+
+	// The contents of the built-in seccomp profile are passed to the runtime
+	config = &runtime.PermissionConfig{}
+	require.NoError(t, addSecuritySettings(config, &permissions.SecuritySettings{
+		Seccomp:  permissions.SeccompProfileMCPDefault,
+		AppArmor: permissions.AppArmorProfileRuntimeDefault,
+	}))
+	require.Len(t, config.SecurityOpt, 1)
+	assert.Contains(t, config.SecurityOpt[0], `seccomp={"defaultAction":"SCMP_ACT_ERRNO"`)
+
+	// Profiles installed on Kubernetes nodes cannot be used
+	err = addSecuritySettings(&runtime.PermissionConfig{}, &permissions.SecuritySettings{Seccomp: "localhost/mcp.json"})
+	assert.Error(t, err)
+
+	require.NoError(t, addSecuritySettings(config, nil))
+}
+
 func TestCompareEnvVars_SubsetMatches(t *testing.T) {
 	t.Parallel()
 
@@ -145,6 +198,60 @@ func TestCompareHostConfig_EqualAndMismatch(t *testing.T) {
 
 	desired.Privileged = true
 	assert.False(t, compareHostConfig(existing, desired))
+
+	desired.Privileged = false
+	desired.ReadonlyRootfs = true
+	assert.False(t, compareHostConfig(existing, desired))
+}
+
+func TestConvertResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	resources, err := convertResourceLimits(nil)
+	require.NoError(t, err)
+	assert.Equal(t, container.Resources{}, resources)
+
+	resources, err = convertResourceLimits(&permissions.ResourceLimits{
+		CPUs:      "1.5",
+		Memory:    "256m",
+		PidsLimit: 100,
+		Ulimits:   []permissions.Ulimit{{Name: "nofile", Soft: 1024, Hard: 2048}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1500000000), resources.NanoCPUs)
+	assert.Equal(t, int64(256*1024*1024), resources.Memory)
+	require.NotNil(t, resources.PidsLimit)
+	assert.Equal(t, int64(100), *resources.PidsLimit)
+	assert.Equal(t, []*container.Ulimit{{Name: "nofile", Soft: 1024, Hard: 2048}}, resources.Ulimits)
+
+	_, err = convertResourceLimits(&permissions.ResourceLimits{CPUs: "none"})
+	assert.Error(t, err)
+}
+
+func TestCompareResources(t *testing.T) {
+	t.Parallel()
+
+	pidsLimit := int64(100)
+	otherPidsLimit := int64(200)
+	desired := container.Resources{NanoCPUs: 500000000, Memory: 1024}
+
+	// Defaults applied by the runtime to unset pids limits and ulimits are ignored
+	existing := desired
+	existing.PidsLimit = &otherPidsLimit
+	existing.Ulimits = []*container.Ulimit{{Name: "nofile", Soft: 1024, Hard: 1024}}
+	assert.True(t, compareResources(existing, desired))
+
+	desired.PidsLimit = &pidsLimit
+	assert.False(t, compareResources(existing, desired))
+	existing.PidsLimit = &pidsLimit
+	assert.True(t, compareResources(existing, desired))
+
+	desired.Ulimits = []*container.Ulimit{{Name: "nofile", Soft: 2048, Hard: 2048}}
+	assert.False(t, compareResources(existing, desired))
+
+	desired = existing
+	desired.Memory = 2048
+	assert.False(t, compareResources(existing, desired))
 }
 
 func TestComparePortConfig_EqualAndMismatch(t *testing.T) {
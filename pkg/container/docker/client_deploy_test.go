@@ -31,6 +31,12 @@ type fakeDeployOps struct {
 	egressCalled bool
 	egressID     string
 
+	servicesCalled      bool
+	servicesNetworkName string
+	services            []runtime.ServiceContainer
+	servicesDNS         string
+	servicesIsolate     bool
+
 	ingressCalled bool
 	ingressPort   int
 
@@ -53,6 +59,7 @@ type fakeDeployOps struct {
 	errCreateNetwork    error
 	errDNS              error
 	errEgress           error
+	errServices         error
 	errIngress          error
 	errMcp              error
 }
@@ -81,6 +88,22 @@ func (f *fakeDeployOps) createEgressSquidContainer(_ context.Context, _ string,
 	return f.egressID, f.errEgress
 }
 
+func (f *fakeDeployOps) createServiceContainers(
+	_ context.Context,
+	_ string,
+	networkName string,
+	services []runtime.ServiceContainer,
+	additionalDNS string,
+	isolateNetwork bool,
+) error {
+	f.servicesCalled = true
+	f.servicesNetworkName = networkName
+	f.services = services
+	f.servicesDNS = additionalDNS
+	f.servicesIsolate = isolateNetwork
+	return f.errServices
+}
+
 func (f *fakeDeployOps) createMcpContainer(
 	_ context.Context,
 	name string,
@@ -308,3 +331,73 @@ func TestDeployWorkload_UnsupportedTransport_PropagatesError(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unsupported transport type")
 }
+
+func TestDeployWorkload_Services_ShareWorkloadNetwork(t *testing.T) {
+	t.Parallel()
+
+	services := []runtime.ServiceContainer{{Name: "db", Image: "postgres:16"}}
+	deploy := func(fops *fakeDeployOps, isolateNetwork bool) error {
+		opts := runtime.NewDeployWorkloadOptions()
+		opts.AttachStdio = true
+		opts.Services = services
+		_, err := newClientWithOps(fops).DeployWorkload(
+			t.Context(),
+			"ghcr.io/example/mcp:latest",
+			"app",
+			nil,
+			nil,
+			map[string]string{},
+			&permissions.Profile{},
+			"stdio",
+			opts,
+			isolateNetwork,
+		)
+		return err
+	}
+
+	t.Run("without network isolation", func(t *testing.T) {
+		t.Parallel()
+
+		fops := &fakeDeployOps{}
+		require.NoError(t, deploy(fops, false))
+
+		// The workload network is created without isolating it from the internet
+		require.Len(t, fops.createNetworkCalls, 1)
+		assert.Equal(t, "toolhive-app-internal", fops.createNetworkCalls[0].name)
+		assert.False(t, fops.createNetworkCalls[0].internal)
+		assert.False(t, fops.dnsCalled)
+		assert.False(t, fops.egressCalled)
+
+		require.True(t, fops.servicesCalled)
+		assert.Equal(t, services, fops.services)
+		assert.Equal(t, "toolhive-app-internal", fops.servicesNetworkName)
+		assert.False(t, fops.servicesIsolate)
+		assert.Equal(t, "toolhive-app-internal", fops.mcpNetworkName)
+	})
+
+	t.Run("with network isolation", func(t *testing.T) {
+		t.Parallel()
+
+		fops := &fakeDeployOps{dnsIP: "172.18.0.10"}
+		require.NoError(t, deploy(fops, true))
+
+		require.Len(t, fops.createNetworkCalls, 1)
+		assert.True(t, fops.createNetworkCalls[0].internal)
+		require.True(t, fops.servicesCalled)
+		assert.Equal(t, "toolhive-app-internal", fops.servicesNetworkName)
+		assert.Equal(t, "172.18.0.10", fops.servicesDNS)
+		assert.True(t, fops.servicesIsolate)
+
+		// The MCP server reaches its services without the egress proxy
+		assert.Equal(t, "localhost,127.0.0.1,::1,db", fops.mcpEnvVars["NO_PROXY"])
+	})
+
+	t.Run("failing services", func(t *testing.T) {
+		t.Parallel()
+
+		fops := &fakeDeployOps{errServices: assert.AnError}
+		err := deploy(fops, false)
+		require.ErrorIs(t, err, assert.AnError)
+		assert.False(t, fops.mcpCalled, "the MCP server should not start without its services")
+	})
+}
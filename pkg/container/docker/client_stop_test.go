@@ -9,6 +9,8 @@ import (
 	"github.com/docker/go-connections/nat"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	lb "github.com/stacklok/toolhive/pkg/labels"
 )
 
 func TestStopWorkload_NotRunning_ReturnsNil(t *testing.T) {
@@ -69,7 +71,11 @@ func TestStopWorkload_Running_CallsContainerStop(t *testing.T) {
 	called := false
 	call := 0
 	api := &fakeDockerAPI{
-		listFunc: func(_ context.Context, _ container.ListOptions) ([]container.Summary, error) {
+		listFunc: func(_ context.Context, options container.ListOptions) ([]container.Summary, error) {
+			if options.Filters.ExactMatch("label", lb.LabelServiceOf+"=app") {
+				// The workload has no services
+				return nil, nil
+			}
 			call++
 			if call == 1 {
 				return []container.Summary{}, nil
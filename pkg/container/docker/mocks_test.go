@@ -5,7 +5,10 @@ import (
 	"fmt"
 
 	"github.com/docker/docker/api/types/container"
+	dockerimage "github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/client"
 	v1 "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
@@ -20,6 +23,9 @@ type fakeDockerAPI struct {
 	createFunc func(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *v1.Platform, containerName string) (container.CreateResponse, error)
 	startFunc  func(ctx context.Context, containerID string, options container.StartOptions) error
 	removeFunc func(ctx context.Context, containerID string, options container.RemoveOptions) error
+	infoFunc   func(ctx context.Context) (system.Info, error)
+
+	imageInspectFunc func(ctx context.Context, imageID string) (dockerimage.InspectResponse, error)
 }
 
 func (f *fakeDockerAPI) ContainerList(ctx context.Context, options container.ListOptions) ([]container.Summary, error) {
@@ -64,6 +70,22 @@ func (f *fakeDockerAPI) ContainerRemove(ctx context.Context, containerID string,
 	return nil
 }
 
+func (f *fakeDockerAPI) Info(ctx context.Context) (system.Info, error) {
+	if f.infoFunc != nil {
+		return f.infoFunc(ctx)
+	}
+	return system.Info{}, nil
+}
+
+func (f *fakeDockerAPI) ImageInspect(
+	ctx context.Context, imageID string, _ ...client.ImageInspectOption,
+) (dockerimage.InspectResponse, error) {
+	if f.imageInspectFunc != nil {
+		return f.imageInspectFunc(ctx, imageID)
+	}
+	return dockerimage.InspectResponse{}, nil
+}
+
 // fakeImageManager provides a minimal test double for ImageManager
 type fakeImageManager struct {
 	pulledImages    map[string]struct{}
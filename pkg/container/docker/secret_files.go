@@ -0,0 +1,108 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+)
+
+// errNoSecretFilesTmpfs is returned when there is no tmpfs to write the secret files of workloads to
+var errNoSecretFilesTmpfs = errors.New("no tmpfs available for secret files, secrets are never written to disk")
+
+// secretFilesBaseDir returns the directory in which the secret files of workloads are written.
+// It is always on a tmpfs, so that secrets are never written to disk: there is no fallback to
+// the temporary directory, which is usually on disk.
+func secretFilesBaseDir() (string, error) {
+	for _, dir := range []string{xdg.RuntimeDir, "/dev/shm"} {
+		if isTmpfs(dir) {
+			return filepath.Join(dir, "toolhive", "secrets"), nil
+		}
+	}
+	return "", errNoSecretFilesTmpfs
+}
+
+// writeSecretFiles writes the secret files of a workload to the host and returns read-only
+// bind mounts of them. Docker cannot copy files into a tmpfs mounted in the container, as
+// copies only see a private view of its tmpfs mounts, so the files are written to a tmpfs
+// on the host instead, in a directory only accessible to the current user. The files are
+// therefore on a tmpfs inside the container too.
+func writeSecretFiles(baseDir, workloadName string, secretFiles []runtime.SecretFile) ([]runtime.Mount, error) {
+	if len(secretFiles) == 0 {
+		return nil, nil
+	}
+
+	dir := filepath.Join(baseDir, workloadName)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create secret files directory: %w", err)
+	}
+	if err := os.Chmod(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to set permissions of secret files directory: %w", err)
+	}
+
+	mounts := make([]runtime.Mount, 0, len(secretFiles))
+	for _, secretFile := range secretFiles {
+		// Name the files after their path in the container, so that the mounts of
+		// an existing container are unchanged when the workload is restarted
+		hash := sha256.Sum256([]byte(secretFile.Path))
+		hostPath := filepath.Join(dir, hex.EncodeToString(hash[:8]))
+
+		// The previous file may not be writable, depending on its mode
+		if err := os.Remove(hostPath); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove previous secret file for %s: %w", secretFile.Name, err)
+		}
+		if err := os.WriteFile(hostPath, []byte(secretFile.Value), 0600); err != nil {
+			return nil, fmt.Errorf("failed to write secret file for %s: %w", secretFile.Name, err)
+		}
+		if err := os.Chmod(hostPath, secretFile.Mode); err != nil {
+			return nil, fmt.Errorf("failed to set permissions of secret file for %s: %w", secretFile.Name, err)
+		}
+
+		mounts = append(mounts, runtime.Mount{
+			Source:   hostPath,
+			Target:   secretFile.Path,
+			ReadOnly: true,
+			Type:     runtime.MountTypeBind,
+		})
+	}
+
+	return mounts, nil
+}
+
+// removeSecretFiles removes the secret files of a workload from the host.
+func removeSecretFiles(baseDir, workloadName string) error {
+	dir := filepath.Join(baseDir, workloadName)
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove secret files of %s: %w", workloadName, err)
+	}
+	return nil
+}
+
+// MountSecretFiles writes the secret files of a workload to a tmpfs on the host and returns
+// read-only bind mounts of them. It is shared with the other runtimes which use bind mounts.
+func MountSecretFiles(workloadName string, secretFiles []runtime.SecretFile) ([]runtime.Mount, error) {
+	if len(secretFiles) == 0 {
+		return nil, nil
+	}
+	baseDir, err := secretFilesBaseDir()
+	if err != nil {
+		return nil, err
+	}
+	return writeSecretFiles(baseDir, workloadName, secretFiles)
+}
+
+// RemoveSecretFiles removes the secret files of a workload written by MountSecretFiles.
+func RemoveSecretFiles(workloadName string) error {
+	baseDir, err := secretFilesBaseDir()
+	if err != nil {
+		// Without a tmpfs, no secret files were written
+		return nil
+	}
+	return removeSecretFiles(baseDir, workloadName)
+}
@@ -0,0 +1,15 @@
+//go:build linux
+// +build linux
+
+package docker
+
+import "golang.org/x/sys/unix"
+
+// isTmpfs returns whether a directory exists and is on a tmpfs.
+func isTmpfs(dir string) bool {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return false
+	}
+	return stat.Type == unix.TMPFS_MAGIC
+}
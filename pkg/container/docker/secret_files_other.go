@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package docker
+
+// isTmpfs returns whether a directory exists and is on a tmpfs. There is no tmpfs outside of
+// Linux, so secret files are only available on Linux.
+func isTmpfs(_ string) bool {
+	return false
+}
@@ -0,0 +1,80 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+)
+
+func TestWriteSecretFiles(t *testing.T) {
+	t.Parallel()
+
+	baseDir := t.TempDir()
+	secretFiles := []runtime.SecretFile{
+		{Name: "github", Path: "/run/secrets/token", Mode: 0400, Value: "ghp_token"},
+		{Name: "tls", Path: "/etc/app/key.pem", Mode: 0444, Value: "private key"},
+	}
+
+	mounts, err := writeSecretFiles(baseDir, "github", secretFiles)
+	require.NoError(t, err)
+	require.Len(t, mounts, 2)
+
+	info, err := os.Stat(filepath.Join(baseDir, "github"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	for i, mount := range mounts {
+		assert.Equal(t, secretFiles[i].Path, mount.Target)
+		assert.True(t, mount.ReadOnly)
+		assert.Equal(t, runtime.MountTypeBind, mount.Type)
+		assert.Equal(t, filepath.Join(baseDir, "github"), filepath.Dir(mount.Source))
+
+		content, err := os.ReadFile(mount.Source)
+		require.NoError(t, err)
+		assert.Equal(t, secretFiles[i].Value, string(content))
+
+		info, err := os.Stat(mount.Source)
+		require.NoError(t, err)
+		assert.Equal(t, secretFiles[i].Mode, info.Mode().Perm())
+	}
+
+	// Restarting the workload overwrites read-only files at the same host paths
+	secretFiles[0].Value = "ghp_rotated"
+	restartMounts, err := writeSecretFiles(baseDir, "github", secretFiles)
+	require.NoError(t, err)
+	assert.Equal(t, mounts, restartMounts)
+	content, err := os.ReadFile(mounts[0].Source)
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_rotated", string(content))
+
+	require.NoError(t, removeSecretFiles(baseDir, "github"))
+	assert.NoDirExists(t, filepath.Join(baseDir, "github"))
+}
+
+func TestWriteSecretFilesWithoutSecrets(t *testing.T) {
+	t.Parallel()
+
+	baseDir := t.TempDir()
+	mounts, err := writeSecretFiles(baseDir, "fetch", nil)
+	require.NoError(t, err)
+	assert.Empty(t, mounts)
+	assert.NoDirExists(t, filepath.Join(baseDir, "fetch"))
+}
+
+func TestSecretFilesBaseDirIsTmpfs(t *testing.T) {
+	t.Parallel()
+
+	baseDir, err := secretFilesBaseDir()
+	if err != nil {
+		// Secret files are never written to disk when there is no tmpfs
+		assert.ErrorIs(t, err, errNoSecretFilesTmpfs)
+		return
+	}
+	assert.True(t, isTmpfs(filepath.Dir(filepath.Dir(baseDir))))
+	assert.False(t, isTmpfs(filepath.Join(baseDir, "does-not-exist")))
+}
@@ -0,0 +1,233 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	lb "github.com/stacklok/toolhive/pkg/labels"
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// serviceHealthPollInterval is how often the health of a service container is checked while it starts
+var serviceHealthPollInterval = time.Second
+
+// createServiceContainers starts the service containers of a workload on its network, in the
+// order they are declared, and waits for each service with a health check to be healthy before
+// starting the next one. Services of the workload which are no longer declared are removed.
+func (c *Client) createServiceContainers(
+	ctx context.Context,
+	workloadName string,
+	networkName string,
+	services []runtime.ServiceContainer,
+	additionalDNS string,
+	isolateNetwork bool,
+) error {
+	if err := c.removeServiceContainers(ctx, workloadName, services); err != nil {
+		return err
+	}
+
+	for _, service := range services {
+		logger.Infof("Starting service %s of %s with image %s...", service.Name, workloadName, service.Image)
+		containerID, err := c.createServiceContainer(ctx, workloadName, networkName, service, additionalDNS, isolateNetwork)
+		if err != nil {
+			return fmt.Errorf("failed to create service %s: %w", service.Name, err)
+		}
+		if service.HealthCheck == nil {
+			continue
+		}
+		if err := c.waitForServiceHealthy(ctx, containerID, service); err != nil {
+			return err
+		}
+		logger.Infof("Service %s of %s is healthy", service.Name, workloadName)
+	}
+	return nil
+}
+
+// createServiceContainer creates and starts the container of a service, which the other
+// containers on the network of the workload reach by the name of the service
+func (c *Client) createServiceContainer(
+	ctx context.Context,
+	workloadName string,
+	networkName string,
+	service runtime.ServiceContainer,
+	additionalDNS string,
+	isolateNetwork bool,
+) (string, error) {
+	if err := c.imageManager.PullImage(ctx, service.Image); err != nil {
+		// Check if the image exists locally before failing
+		exists, inspectErr := c.imageManager.ImageExists(ctx, service.Image)
+		if inspectErr != nil || !exists {
+			return "", fmt.Errorf("failed to pull image %s: %w", service.Image, err)
+		}
+		logger.Infof("Image %s exists locally, continuing despite pull failure", service.Image)
+	}
+
+	containerName := runtime.ServiceContainerName(workloadName, service.Name)
+	serviceLabels := map[string]string{
+		lb.LabelToolHive:               lb.LabelToolHiveValue,
+		lb.LabelName:                   containerName,
+		lb.LabelServiceOf:              workloadName,
+		ToolhiveAuxiliaryWorkloadLabel: LabelValueTrue,
+	}
+
+	envVars := maps.Clone(service.EnvVars)
+	if isolateNetwork {
+		// Services are subject to the same egress rules as the MCP server
+		envVars = addEgressEnvVars(envVars, fmt.Sprintf("%s-egress", workloadName))
+	}
+
+	config := &container.Config{
+		Image:  service.Image,
+		Cmd:    service.Command,
+		Env:    convertEnvVars(envVars),
+		Labels: serviceLabels,
+	}
+	if check := service.HealthCheck; check != nil {
+		config.Healthcheck = &container.HealthConfig{
+			Test:        append([]string{"CMD"}, check.Command...),
+			Interval:    check.Interval(),
+			Timeout:     check.Timeout(),
+			Retries:     check.RetryCount(),
+			StartPeriod: check.StartPeriod(),
+		}
+	}
+
+	hostConfig := &container.HostConfig{
+		NetworkMode: container.NetworkMode(networkName),
+		SecurityOpt: []string{"label:disable"},
+		RestartPolicy: container.RestartPolicy{
+			Name: "unless-stopped",
+		},
+	}
+	if additionalDNS != "" {
+		hostConfig.DNS = []string{additionalDNS}
+	}
+
+	endpointsConfig := map[string]*network.EndpointSettings{
+		networkName: {
+			NetworkID: networkName,
+			Aliases:   []string{service.Name},
+		},
+	}
+	return c.createContainer(ctx, containerName, config, hostConfig, endpointsConfig)
+}
+
+// waitForServiceHealthy waits until the health check of a service succeeds. It fails when
+// the service is unhealthy, exits, or does not become healthy within the deadline of its check.
+func (c *Client) waitForServiceHealthy(ctx context.Context, containerID string, service runtime.ServiceContainer) error {
+	ctx, cancel := context.WithTimeout(ctx, service.HealthCheck.Deadline())
+	defer cancel()
+
+	ticker := time.NewTicker(serviceHealthPollInterval)
+	defer ticker.Stop()
+	for {
+		info, err := c.api.ContainerInspect(ctx, containerID)
+		if err != nil {
+			return NewContainerError(err, containerID, fmt.Sprintf("failed to inspect service %s: %v", service.Name, err))
+		}
+
+		if state := info.State; state != nil {
+			if !state.Running && !state.Restarting {
+				return fmt.Errorf("service %s exited with code %d", service.Name, state.ExitCode)
+			}
+			if state.Health != nil {
+				switch state.Health.Status {
+				case container.Healthy:
+					return nil
+				case container.Unhealthy:
+					return fmt.Errorf("service %s is unhealthy%s", service.Name, lastHealthCheckOutput(state.Health))
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for service %s to be healthy", service.Name)
+		case <-ticker.C:
+		}
+	}
+}
+
+// lastHealthCheckOutput returns the output of the last health check, to explain why a service is unhealthy
+func lastHealthCheckOutput(health *container.Health) string {
+	if len(health.Log) == 0 {
+		return ""
+	}
+	output := strings.TrimSpace(health.Log[len(health.Log)-1].Output)
+	if output == "" {
+		return ""
+	}
+	return ": " + output
+}
+
+// addServicesNoProxy lets a workload reach its services directly rather than through the egress proxy
+func addServicesNoProxy(envVars map[string]string, services []runtime.ServiceContainer) map[string]string {
+	for _, key := range []string{"NO_PROXY", "no_proxy"} {
+		hosts := []string{}
+		if envVars[key] != "" {
+			hosts = append(hosts, envVars[key])
+		}
+		for _, service := range services {
+			hosts = append(hosts, service.Name)
+		}
+		envVars[key] = strings.Join(hosts, ",")
+	}
+	return envVars
+}
+
+// listServiceContainers returns the service containers of a workload
+func (c *Client) listServiceContainers(ctx context.Context, workloadName string) ([]container.Summary, error) {
+	containers, err := c.api.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", fmt.Sprintf("%s=%s", lb.LabelServiceOf, workloadName))),
+	})
+	if err != nil {
+		return nil, NewContainerError(err, "", fmt.Sprintf("failed to list service containers: %v", err))
+	}
+	return containers, nil
+}
+
+// stopServiceContainers stops the service containers of a workload. Errors are logged,
+// as the workload itself is already stopped.
+func (c *Client) stopServiceContainers(ctx context.Context, workloadName string, timeoutSeconds int) {
+	containers, err := c.listServiceContainers(ctx, workloadName)
+	if err != nil {
+		logger.Debugf("Failed to list the services of %s: %v", workloadName, err)
+		return
+	}
+	for _, ctr := range containers {
+		if err := c.api.ContainerStop(ctx, ctr.ID, container.StopOptions{Timeout: &timeoutSeconds}); err != nil {
+			logger.Debugf("Failed to stop service container %s: %v", ctr.ID, err)
+		}
+	}
+}
+
+// removeServiceContainers removes the service containers of a workload, except those of the services to keep
+func (c *Client) removeServiceContainers(ctx context.Context, workloadName string, keep []runtime.ServiceContainer) error {
+	containers, err := c.listServiceContainers(ctx, workloadName)
+	if err != nil {
+		return err
+	}
+
+	keepNames := make(map[string]bool, len(keep))
+	for _, service := range keep {
+		keepNames[runtime.ServiceContainerName(workloadName, service.Name)] = true
+	}
+	for _, ctr := range containers {
+		if len(ctr.Names) > 0 && keepNames[strings.TrimPrefix(ctr.Names[0], "/")] {
+			continue
+		}
+		if err := c.removeContainer(ctx, ctr.ID); err != nil {
+			return err // removeContainer already wraps the error with context.
+		}
+	}
+	return nil
+}
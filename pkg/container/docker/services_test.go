@@ -0,0 +1,168 @@
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	lb "github.com/stacklok/toolhive/pkg/labels"
+)
+
+func TestCreateServiceContainers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		created      []string
+		createdCfg   *container.Config
+		createdHost  *container.HostConfig
+		createdNet   *network.NetworkingConfig
+		inspectCalls int
+	)
+	api := &fakeDockerAPI{
+		createFunc: func(_ context.Context, config *container.Config, hostConfig *container.HostConfig,
+			networkingConfig *network.NetworkingConfig, _ *v1.Platform, containerName string) (container.CreateResponse, error) {
+			created = append(created, containerName)
+			createdCfg, createdHost, createdNet = config, hostConfig, networkingConfig
+			return container.CreateResponse{ID: "cid-" + containerName}, nil
+		},
+		inspectFunc: func(_ context.Context, id string) (container.InspectResponse, error) {
+			inspectCalls++
+			assert.Equal(t, "cid-app-svc-db", id)
+			return container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{
+				State: &container.State{Running: true, Health: &container.Health{Status: container.Healthy}},
+			}}, nil
+		},
+	}
+	c := &Client{
+		api:          api,
+		imageManager: &fakeImageManager{availableImages: map[string]struct{}{"postgres:16": {}, "redis:7": {}}},
+	}
+
+	services := []runtime.ServiceContainer{
+		{
+			Name:        "db",
+			Image:       "postgres:16",
+			EnvVars:     map[string]string{"POSTGRES_PASSWORD": "test"},
+			HealthCheck: &runtime.HealthCheck{Command: []string{"pg_isready"}, Retries: 3},
+		},
+		{Name: "cache", Image: "redis:7", Command: []string{"redis-server", "--save", ""}},
+	}
+	err := c.createServiceContainers(t.Context(), "app", "toolhive-app-internal", services, "172.18.0.10", true)
+	require.NoError(t, err)
+
+	// The services start in order, and only the service with a health check is waited for
+	assert.Equal(t, []string{"app-svc-db", "app-svc-cache"}, created)
+	assert.Equal(t, 1, inspectCalls)
+
+	assert.Equal(t, "redis:7", createdCfg.Image)
+	assert.Equal(t, []string{"redis-server", "--save", ""}, []string(createdCfg.Cmd))
+	assert.Nil(t, createdCfg.Healthcheck)
+	assert.Equal(t, "app", createdCfg.Labels[lb.LabelServiceOf])
+	assert.Equal(t, LabelValueTrue, createdCfg.Labels[ToolhiveAuxiliaryWorkloadLabel])
+	assert.Contains(t, createdCfg.Env, "HTTP_PROXY=http://app-egress:3128", "services should use the egress proxy")
+
+	assert.Equal(t, container.NetworkMode("toolhive-app-internal"), createdHost.NetworkMode)
+	assert.Equal(t, []string{"172.18.0.10"}, createdHost.DNS)
+	require.Contains(t, createdNet.EndpointsConfig, "toolhive-app-internal")
+	assert.Equal(t, []string{"cache"}, createdNet.EndpointsConfig["toolhive-app-internal"].Aliases)
+}
+
+func TestCreateServiceContainers_HealthCheck(t *testing.T) {
+	t.Parallel()
+
+	service := runtime.ServiceContainer{
+		Name:        "db",
+		Image:       "postgres:16",
+		HealthCheck: &runtime.HealthCheck{Command: []string{"pg_isready", "-U", "postgres"}, IntervalSeconds: 2, Retries: 3},
+	}
+
+	tests := []struct {
+		name    string
+		state   *container.State
+		wantErr string
+	}{
+		{
+			name:  "healthy",
+			state: &container.State{Running: true, Health: &container.Health{Status: container.Healthy}},
+		},
+		{
+			name: "unhealthy",
+			state: &container.State{Running: true, Health: &container.Health{
+				Status: container.Unhealthy,
+				Log:    []*container.HealthcheckResult{{ExitCode: 2, Output: "no response\n"}},
+			}},
+			wantErr: "service db is unhealthy: no response",
+		},
+		{
+			name:    "exited",
+			state:   &container.State{ExitCode: 1},
+			wantErr: "service db exited with code 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var createdCfg *container.Config
+			api := &fakeDockerAPI{
+				createFunc: func(_ context.Context, config *container.Config, _ *container.HostConfig,
+					_ *network.NetworkingConfig, _ *v1.Platform, _ string) (container.CreateResponse, error) {
+					createdCfg = config
+					return container.CreateResponse{ID: "cid-db"}, nil
+				},
+				inspectFunc: func(_ context.Context, _ string) (container.InspectResponse, error) {
+					return container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{State: tt.state}}, nil
+				},
+			}
+			c := &Client{api: api, imageManager: &fakeImageManager{availableImages: map[string]struct{}{"postgres:16": {}}}}
+
+			err := c.createServiceContainers(t.Context(), "app", "", []runtime.ServiceContainer{service}, "", false)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.NotNil(t, createdCfg.Healthcheck)
+			assert.Equal(t, []string{"CMD", "pg_isready", "-U", "postgres"}, createdCfg.Healthcheck.Test)
+			assert.Equal(t, 3, createdCfg.Healthcheck.Retries)
+			assert.Equal(t, "2s", createdCfg.Healthcheck.Interval.String())
+		})
+	}
+}
+
+func TestRemoveServiceContainers(t *testing.T) {
+	t.Parallel()
+
+	var removed []string
+	api := &fakeDockerAPI{
+		listFunc: func(_ context.Context, options container.ListOptions) ([]container.Summary, error) {
+			assert.True(t, options.Filters.ExactMatch("label", lb.LabelServiceOf+"=app"))
+			return []container.Summary{
+				{ID: "cid-db", Names: []string{"/app-svc-db"}},
+				{ID: "cid-cache", Names: []string{"/app-svc-cache"}},
+			}, nil
+		},
+		removeFunc: func(_ context.Context, id string, _ container.RemoveOptions) error {
+			removed = append(removed, id)
+			return nil
+		},
+	}
+	c := &Client{api: api}
+
+	// Services which are no longer declared are removed
+	err := c.removeServiceContainers(t.Context(), "app", []runtime.ServiceContainer{{Name: "db", Image: "postgres:16"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cid-cache"}, removed)
+
+	// All the services are removed with the workload
+	removed = nil
+	require.NoError(t, c.removeServiceContainers(t.Context(), "app", nil))
+	assert.Equal(t, []string{"cid-db", "cid-cache"}, removed)
+}
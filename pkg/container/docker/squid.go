@@ -11,20 +11,12 @@ import (
 	"github.com/docker/docker/api/types/network"
 
 	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/container/squid"
 	lb "github.com/stacklok/toolhive/pkg/labels"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/permissions"
 )
 
-const defaultSquidImage = "ghcr.io/stacklok/toolhive/egress-proxy:latest"
-
-type proxyDirection int
-
-const (
-	proxyIngress proxyDirection = iota
-	proxyEgress
-)
-
 // createIngressSquidContainer creates an instance of the squid proxy for ingress traffic.
 func createIngressSquidContainer(
 	ctx context.Context,
@@ -96,13 +88,13 @@ func createSquidContainer(
 	squidConfPath string,
 ) (string, error) {
 
-	logger.Infof("Setting up squid container for %s with image %s...", squidContainerName, getSquidImage())
+	logger.Infof("Setting up squid container for %s with image %s...", squidContainerName, squid.Image())
 	squidLabels := map[string]string{}
 	lb.AddStandardLabels(squidLabels, squidContainerName, squidContainerName, "stdio", 80)
 	squidLabels[ToolhiveAuxiliaryWorkloadLabel] = LabelValueTrue
 
 	// pull the squid image if it is not already pulled
-	squidImage := getSquidImage()
+	squidImage := squid.Image()
 	// TODO: Move these down into an image operations layer.
 	err := c.imageManager.PullImage(ctx, squidImage)
 	if err != nil {
@@ -117,7 +109,7 @@ func createSquidContainer(
 
 	// Create container options
 	config := &container.Config{
-		Image:        getSquidImage(),
+		Image:        squid.Image(),
 		Cmd:          nil,
 		Env:          nil,
 		Labels:       squidLabels,
@@ -172,26 +164,18 @@ func createTempEgressSquidConf(
 	networkPermissions *permissions.NetworkPermissions,
 	serverHostname string,
 ) (string, error) {
-	var sb strings.Builder
-
-	writeCommonConfig(&sb, serverHostname, proxyEgress)
-
-	if networkPermissions == nil || (networkPermissions.Outbound != nil && networkPermissions.Outbound.InsecureAllowAll) {
-		sb.WriteString("# Allow all traffic\nhttp_access allow all\n")
-	} else {
-		writeOutboundACLs(&sb, networkPermissions.Outbound)
-		writeHttpAccessRules(&sb, networkPermissions.Outbound)
+	config, err := squid.EgressConfig(networkPermissions, serverHostname)
+	if err != nil {
+		return "", err
 	}
 
-	sb.WriteString("http_access deny all\n")
-
 	tmpFile, err := os.CreateTemp("", "squid-*.conf")
 	if err != nil {
 		return "", err
 	}
 	defer tmpFile.Close()
 
-	if _, err := tmpFile.WriteString(sb.String()); err != nil {
+	if _, err := tmpFile.WriteString(config); err != nil {
 		return "", fmt.Errorf("failed to write to temporary file: %v", err)
 	}
 
@@ -203,70 +187,6 @@ func createTempEgressSquidConf(
 	return tmpFile.Name(), nil
 }
 
-func writeCommonConfig(sb *strings.Builder, hostnameBase string, direction proxyDirection) {
-	var serverHostname string
-
-	if direction == proxyEgress {
-		serverHostname = hostnameBase + "-egress"
-		sb.WriteString("http_port 3128\n")
-	} else {
-		serverHostname = hostnameBase + "-ingress"
-	}
-
-	sb.WriteString(
-		"visible_hostname " + serverHostname + "\n" +
-			"access_log stdio:/dev/stdout squid\n" +
-			"pid_filename /tmp/squid.pid\n" +
-			"# Avoid allocation errors caused by max_filedescriptors inference\n" +
-			"max_filedescriptors 1024\n" +
-			"# Disable memory and disk caching\n" +
-			"cache deny all\n" +
-			"cache_mem 0 MB\n" +
-			"maximum_object_size 0 KB\n" +
-			"maximum_object_size_in_memory 0 KB\n" +
-			"# Don't use cache directories\n" +
-			"cache_store_log none\n\n")
-}
-
-func writeOutboundACLs(sb *strings.Builder, outbound *permissions.OutboundNetworkPermissions) {
-	if len(outbound.AllowPort) > 0 {
-		sb.WriteString("# Define allowed ports\nacl allowed_ports port")
-		for _, port := range outbound.AllowPort {
-			sb.WriteString(" " + strconv.Itoa(port))
-		}
-		sb.WriteString("\n")
-	}
-
-	if len(outbound.AllowHost) > 0 {
-		sb.WriteString("# Define allowed destinations\nacl allowed_dsts dstdomain")
-		for _, host := range outbound.AllowHost {
-			sb.WriteString(" " + host)
-		}
-		sb.WriteString("\n")
-	}
-}
-
-func writeHttpAccessRules(sb *strings.Builder, outbound *permissions.OutboundNetworkPermissions) {
-	var conditions []string
-	if len(outbound.AllowPort) > 0 {
-		conditions = append(conditions, "allowed_ports")
-	}
-	if len(outbound.AllowHost) > 0 {
-		conditions = append(conditions, "allowed_dsts")
-	}
-	if len(conditions) > 0 {
-		sb.WriteString("\n# Define http_access rules\n")
-		sb.WriteString("http_access allow " + strings.Join(conditions, " ") + "\n")
-	}
-}
-
-func getSquidImage() string {
-	if egressImage := os.Getenv("TOOLHIVE_EGRESS_IMAGE"); egressImage != "" {
-		return egressImage
-	}
-	return defaultSquidImage
-}
-
 func createTempIngressSquidConf(
 	serverHostname string,
 	upstreamPort int,
@@ -275,7 +195,7 @@ func createTempIngressSquidConf(
 ) (string, error) {
 	var sb strings.Builder
 
-	writeCommonConfig(&sb, serverHostname, proxyIngress)
+	squid.WriteCommonConfig(&sb, serverHostname, squid.Ingress)
 
 	writeIngressProxyConfig(&sb, serverHostname, upstreamPort, squidPort, networkPermissions)
 	sb.WriteString("http_access deny all\n")
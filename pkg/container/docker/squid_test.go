@@ -159,6 +159,74 @@ func TestCreateTempEgressSquidConf_WithACLs(t *testing.T) {
 	assert.Equal(t, os.FileMode(0o644), info.Mode().Perm())
 }
 
+func TestCreateTempEgressSquidConf_WithRules(t *testing.T) {
+	t.Parallel()
+
+	cfg := &permissions.NetworkPermissions{
+		Outbound: &permissions.OutboundNetworkPermissions{
+			AllowPort:     []int{443},
+			AllowHost:     []string{"*.github.com", "api.github.com", "example.com"},
+			AllowCIDR:     []string{"10.0.0.0/8"},
+			AllowHostPort: []string{"db.internal:5432", "192.168.1.10:8080"},
+			DenyHost:      []string{"gist.github.com"},
+			DenyCIDR:      []string{"169.254.169.254/32"},
+		},
+	}
+	fp, err := createTempEgressSquidConf(cfg, "edge")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.Remove(fp) })
+
+	b, err := os.ReadFile(fp)
+	require.NoError(t, err)
+	s := string(b)
+
+	// Wildcards match subdomains, so the subdomains they cover are not listed
+	assert.Contains(t, s, "acl allowed_dsts dstdomain .github.com example.com\n")
+	assert.Contains(t, s, "acl allowed_cidrs dst 10.0.0.0/8\n")
+	assert.Contains(t, s, "acl allowed_host_port_0_dst dstdomain db.internal\nacl allowed_host_port_0_port port 5432\n")
+	assert.Contains(t, s, "acl allowed_host_port_1_dst dst 192.168.1.10\nacl allowed_host_port_1_port port 8080\n")
+	assert.Contains(t, s, "http_access allow allowed_ports allowed_dsts\n")
+	assert.Contains(t, s, "http_access allow allowed_ports allowed_cidrs\n")
+	assert.Contains(t, s, "http_access allow allowed_host_port_0_dst allowed_host_port_0_port\n")
+
+	// Deny rules precede the allow rules
+	assert.Contains(t, s, "acl denied_dsts dstdomain gist.github.com\n")
+	assert.Contains(t, s, "acl denied_cidrs dst 169.254.169.254/32\n")
+	assert.Less(t, strings.Index(s, "http_access deny denied_dsts"), strings.Index(s, "http_access allow"))
+	assert.Less(t, strings.Index(s, "http_access deny denied_cidrs"), strings.Index(s, "http_access allow"))
+	assert.True(t, strings.HasSuffix(strings.TrimSpace(s), "http_access deny all"))
+}
+
+func TestCreateTempEgressSquidConf_DenyRulesWithAllowAll(t *testing.T) {
+	t.Parallel()
+
+	cfg := &permissions.NetworkPermissions{
+		Outbound: &permissions.OutboundNetworkPermissions{
+			InsecureAllowAll: true,
+			DenyHost:         []string{".internal"},
+		},
+	}
+	fp, err := createTempEgressSquidConf(cfg, "edge")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.Remove(fp) })
+
+	b, err := os.ReadFile(fp)
+	require.NoError(t, err)
+	s := string(b)
+
+	assert.Less(t, strings.Index(s, "http_access deny denied_dsts"), strings.Index(s, "http_access allow all"))
+}
+
+func TestCreateTempEgressSquidConf_InvalidRules(t *testing.T) {
+	t.Parallel()
+
+	cfg := &permissions.NetworkPermissions{
+		Outbound: &permissions.OutboundNetworkPermissions{AllowCIDR: []string{"10.0.0.0/33"}},
+	}
+	_, err := createTempEgressSquidConf(cfg, "edge")
+	assert.Error(t, err)
+}
+
 func TestCreateTempIngressSquidConf_Basics(t *testing.T) {
 	t.Parallel()
 
@@ -262,27 +330,6 @@ func TestCreateTempIngressSquidConf_EmptyInboundHosts(t *testing.T) {
 	assert.Equal(t, os.FileMode(0o644), info.Mode().Perm())
 }
 
-func TestGetSquidImage(t *testing.T) {
-	t.Parallel()
-
-	// Save and restore env
-	orig, had := os.LookupEnv("TOOLHIVE_EGRESS_IMAGE")
-	if had {
-		t.Cleanup(func() { _ = os.Setenv("TOOLHIVE_EGRESS_IMAGE", orig) })
-	} else {
-		t.Cleanup(func() { _ = os.Unsetenv("TOOLHIVE_EGRESS_IMAGE") })
-	}
-
-	// Default
-	_ = os.Unsetenv("TOOLHIVE_EGRESS_IMAGE")
-	assert.Equal(t, "ghcr.io/stacklok/toolhive/egress-proxy:latest", getSquidImage())
-
-	// Override
-	override := "ghcr.io/example/custom-squid:1.2.3"
-	_ = os.Setenv("TOOLHIVE_EGRESS_IMAGE", override)
-	assert.Equal(t, override, getSquidImage())
-}
-
 // Safety: ensure generated files are written under system temp directory for cleanup logic assumptions
 func TestTempFilesWrittenToSystemTempDir(t *testing.T) {
 	t.Parallel()
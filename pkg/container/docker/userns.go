@@ -0,0 +1,312 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+const (
+	// subordinateUIDFile lists the subordinate UIDs of the users of the host
+	subordinateUIDFile = "/etc/subuid"
+	// remapUser is the user whose subordinate IDs Docker uses for userns-remap=default
+	remapUser = "dockremap"
+	// defaultRemapSize is the number of IDs mapped by userns-remap when the range is unknown
+	defaultRemapSize = 65536
+)
+
+// IDRange maps a range of the user IDs of containers to user IDs of the host
+type IDRange struct {
+	// ContainerID is the first ID of the range in containers
+	ContainerID uint32
+	// HostID is the ID on the host to which ContainerID is mapped
+	HostID uint32
+	// Size is the number of IDs in the range
+	Size uint32
+}
+
+// UserNamespaceSetup is the user namespace setup of a runtime, and how it maps the users of
+// containers to the users of the host
+type UserNamespaceSetup struct {
+	// Mode is how the users of containers are mapped to the users of the host
+	Mode runtime.UserNamespace
+	// UIDMap maps the user IDs of containers to user IDs of the host. It is empty with
+	// UserNamespaceHost, and only covers the IDs which are known to be mapped otherwise.
+	UIDMap []IDRange
+}
+
+// hostUID returns the user ID of the host to which a user ID of containers is mapped
+func (s UserNamespaceSetup) hostUID(containerUID uint32) (uint32, bool) {
+	if s.Mode == runtime.UserNamespaceHost {
+		return containerUID, true
+	}
+	for _, r := range s.UIDMap {
+		if containerUID >= r.ContainerID && containerUID-r.ContainerID < r.Size {
+			return r.HostID + (containerUID - r.ContainerID), true
+		}
+	}
+	return 0, false
+}
+
+// NewUserNamespaceSetup returns the user namespace setup of a runtime, with the UID map derived
+// from /etc/subuid. rootDir is the data root directory of the runtime, if known: with the
+// userns-remap option of Docker, it is named after the host IDs of root in containers, e.g.
+// /var/lib/docker/231072.231072.
+func NewUserNamespaceSetup(mode runtime.UserNamespace, rootDir string) UserNamespaceSetup {
+	// #nosec G304 -- the path is a constant
+	subordinateIDs, err := os.ReadFile(subordinateUIDFile)
+	if err != nil {
+		logger.Debugf("Failed to read %s: %v", subordinateUIDFile, err)
+	}
+	var names []string
+	if current, err := user.Current(); err == nil {
+		names = append(names, current.Username)
+	}
+	return newUserNamespaceSetup(mode, rootDir, string(subordinateIDs), os.Getuid(), names...)
+}
+
+// newUserNamespaceSetup returns the user namespace setup of a runtime started by the user with
+// the given UID and names, see NewUserNamespaceSetup
+func newUserNamespaceSetup(
+	mode runtime.UserNamespace,
+	rootDir string,
+	subordinateIDs string,
+	uid int,
+	names ...string,
+) UserNamespaceSetup {
+	setup := UserNamespaceSetup{Mode: mode}
+	switch mode {
+	case runtime.UserNamespaceRootless:
+		// Root in containers is the user running the runtime, and the other users are its subordinate IDs
+		setup.UIDMap = append(setup.UIDMap, IDRange{ContainerID: 0, HostID: uint32(uid), Size: 1}) // #nosec G115
+		if ranges := subordinateIDRanges(subordinateIDs, append(names, strconv.Itoa(uid))...); len(ranges) > 0 {
+			setup.UIDMap = append(setup.UIDMap, IDRange{ContainerID: 1, HostID: ranges[0].HostID, Size: ranges[0].Size})
+		}
+	case runtime.UserNamespaceRemapped:
+		// Root in containers is the first subordinate ID of the remap user
+		if root, ok := remapRoot(rootDir); ok {
+			size := uint32(defaultRemapSize)
+			for _, r := range subordinateIDRanges(subordinateIDs, "") {
+				if r.HostID == root {
+					size = r.Size
+				}
+			}
+			setup.UIDMap = append(setup.UIDMap, IDRange{ContainerID: 0, HostID: root, Size: size})
+		} else if ranges := subordinateIDRanges(subordinateIDs, remapUser); len(ranges) > 0 {
+			setup.UIDMap = append(setup.UIDMap, IDRange{ContainerID: 0, HostID: ranges[0].HostID, Size: ranges[0].Size})
+		}
+	case runtime.UserNamespaceHost:
+		// The users of containers are the users of the host
+	}
+	return setup
+}
+
+// subordinateIDRanges returns the ranges of the subordinate IDs of users in the format of
+// /etc/subuid. Each user may be given by name or ID; an empty name matches all users.
+func subordinateIDRanges(subordinateIDs string, names ...string) []IDRange {
+	var ranges []IDRange
+	scanner := bufio.NewScanner(strings.NewReader(subordinateIDs))
+	for scanner.Scan() {
+		fields := strings.Split(strings.TrimSpace(scanner.Text()), ":")
+		if len(fields) != 3 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		matched := false
+		for _, name := range names {
+			matched = matched || name == "" || name == fields[0]
+		}
+		start, startErr := strconv.ParseUint(fields[1], 10, 32)
+		size, sizeErr := strconv.ParseUint(fields[2], 10, 32)
+		if !matched || startErr != nil || sizeErr != nil || size == 0 {
+			continue
+		}
+		ranges = append(ranges, IDRange{HostID: uint32(start), Size: uint32(size)})
+	}
+	return ranges
+}
+
+// remapRoot returns the host UID of root in the containers of Docker with userns-remap,
+// from the name of its data root directory
+func remapRoot(rootDir string) (uint32, bool) {
+	uid, _, found := strings.Cut(filepath.Base(rootDir), ".")
+	if !found {
+		return 0, false
+	}
+	root, err := strconv.ParseUint(uid, 10, 32)
+	return uint32(root), err == nil
+}
+
+// UserNamespace returns the user namespace setup of the runtime
+func (c *Client) UserNamespace(ctx context.Context) (runtime.UserNamespaceInfo, error) {
+	setup, err := c.userNamespaceSetup(ctx)
+	if err != nil {
+		return runtime.UserNamespaceInfo{}, err
+	}
+	return NewUserNamespaceInfo(c.runtimeType, setup), nil
+}
+
+// userNamespaceSetup detects whether the runtime is rootless or remaps the users of containers
+func (c *Client) userNamespaceSetup(ctx context.Context) (UserNamespaceSetup, error) {
+	info, err := c.api.Info(ctx)
+	if err != nil {
+		return UserNamespaceSetup{}, fmt.Errorf("failed to get runtime info: %w", err)
+	}
+	return NewUserNamespaceSetup(UserNamespaceFromSecurityOptions(info.SecurityOptions), info.DockerRootDir), nil
+}
+
+// imageUser returns the user an image is configured to run as
+func (c *Client) imageUser(ctx context.Context, image string) (string, error) {
+	inspect, err := c.api.ImageInspect(ctx, image)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect image %s: %w", image, err)
+	}
+	if inspect.Config == nil {
+		return "", nil
+	}
+	return inspect.Config.User, nil
+}
+
+// UserNamespaceFromSecurityOptions returns the user namespace setup reported by the security options
+// of a Docker-compatible runtime, such as name=rootless for rootless Docker, Podman and nerdctl,
+// or name=userns for Docker with userns-remap.
+func UserNamespaceFromSecurityOptions(options []string) runtime.UserNamespace {
+	mode := runtime.UserNamespaceHost
+	for _, option := range options {
+		for _, field := range strings.Split(option, ",") {
+			switch field {
+			case "name=rootless":
+				return runtime.UserNamespaceRootless
+			case "name=userns":
+				mode = runtime.UserNamespaceRemapped
+			}
+		}
+	}
+	return mode
+}
+
+// NewUserNamespaceInfo describes how ApplyUserNamespace handles write mounts in a user namespace setup
+func NewUserNamespaceInfo(runtimeType runtime.Type, setup UserNamespaceSetup) runtime.UserNamespaceInfo {
+	info := runtime.UserNamespaceInfo{Mode: setup.Mode}
+	switch {
+	case setup.Mode == runtime.UserNamespaceRootless && runtimeType == runtime.TypePodman:
+		info.WriteMounts = "workloads with write mounts run with --userns=keep-id, so that the files they write " +
+			"are owned by your user"
+	case setup.Mode == runtime.UserNamespaceRootless:
+		info.WriteMounts = "workloads running as root in the container write as your user; for other users, " +
+			"ToolHive grants their subordinate UID on the host access to the mounted directories with ACLs, " +
+			"which the file system must support"
+		if len(setup.UIDMap) < 2 {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("no subordinate UIDs of your user were found in %s, "+
+				"so write mounts of workloads which do not run as root are refused", subordinateUIDFile))
+		}
+	case setup.Mode == runtime.UserNamespaceRemapped:
+		info.WriteMounts = "ToolHive grants the remapped UID of the user of the container on the host access to " +
+			"the mounted directories with ACLs, which the file system must support"
+		if len(setup.UIDMap) == 0 {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("the remapped UIDs were found neither in the data root "+
+				"directory of the runtime nor for the %s user in %s, so write mounts are refused",
+				remapUser, subordinateUIDFile))
+		}
+	default:
+		info.WriteMounts = "bind mounts keep the ownership of the files on the host"
+	}
+	if setup.Mode != runtime.UserNamespaceHost && runtimeType != runtime.TypePodman {
+		info.Warnings = append(info.Warnings, "write mounts of workloads whose user is a name rather than a UID "+
+			"are refused, as the UID is only known in the container")
+	}
+	return info
+}
+
+// ApplyUserNamespace adapts the permission config of a workload with write mounts to the user
+// namespace setup of the runtime, so that the workload can write to the mounted directories.
+// Rootless Podman maps your user to the user of the container with keep-id. The other runtimes
+// support neither idmapped bind mounts nor keep-id, so the host UID to which the user of the
+// container is mapped is granted access to the write mounts with ACLs, unless it is your user.
+// imageUser returns the user of the image, which is used if the config sets no user. An error
+// is returned if the user cannot be mapped, such as a user name, whose UID is only known in
+// the container; the host user namespace and root are never used as a fallback.
+func ApplyUserNamespace(
+	config *runtime.PermissionConfig,
+	runtimeType runtime.Type,
+	setup UserNamespaceSetup,
+	imageUser func() (string, error),
+) error {
+	switch {
+	case setup.Mode == runtime.UserNamespaceHost:
+		// The users of the container are the users of the host
+		return nil
+	case setup.Mode == runtime.UserNamespaceRootless && runtimeType == runtime.TypePodman:
+		config.UsernsMode = keepIDMode(config.User)
+		return nil
+	}
+
+	containerUser := config.User
+	if containerUser == "" {
+		var err error
+		if containerUser, err = imageUser(); err != nil {
+			return err
+		}
+	}
+	name, _, _ := strings.Cut(containerUser, ":")
+	containerUID, ok := parseContainerUID(name)
+	if !ok {
+		return fmt.Errorf("the %s runtime maps the users of containers to other users of the host, and the UID of "+
+			"the user %q of the container is unknown, so its write mounts cannot be made writable; set a numeric "+
+			"user in the permission profile", runtimeType, name)
+	}
+
+	hostUID, ok := setup.hostUID(containerUID)
+	if !ok {
+		return fmt.Errorf("the %s runtime maps user %d of the container to an unknown user of the host, so its "+
+			"write mounts cannot be made writable; configure the subordinate UIDs in %s",
+			runtimeType, containerUID, subordinateUIDFile)
+	}
+	if int(hostUID) == os.Getuid() {
+		// The workload writes as your user
+		return nil
+	}
+
+	for _, mount := range config.Mounts {
+		if mount.ReadOnly || mount.Type != runtime.MountTypeBind {
+			continue
+		}
+		if err := grantWriteAccess(mount.Source, hostUID); err != nil {
+			return fmt.Errorf("failed to grant user %d of the container (UID %d on the host) write access to %s: %w",
+				containerUID, hostUID, mount.Source, err)
+		}
+		logger.Debugf("Granted UID %d write access to %s with ACLs", hostUID, mount.Source)
+	}
+	return nil
+}
+
+// parseContainerUID returns the UID of the user of a container, if it is root or numeric
+func parseContainerUID(name string) (uint32, bool) {
+	if name == "" || name == "root" {
+		return 0, true
+	}
+	uid, err := strconv.ParseUint(name, 10, 32)
+	return uint32(uid), err == nil
+}
+
+// keepIDMode returns the keep-id user namespace of Podman for the user of a container, which maps
+// your user to that user so that the files it writes are owned by your user on the host
+func keepIDMode(user string) string {
+	uid, gid, _ := strings.Cut(user, ":")
+	if _, err := strconv.ParseUint(uid, 10, 32); err != nil {
+		// The ID of a user name is only known in the image, so only your own ID is kept
+		return "keep-id"
+	}
+	mode := "keep-id:uid=" + uid
+	if _, err := strconv.ParseUint(gid, 10, 32); err == nil {
+		mode += ",gid=" + gid
+	}
+	return mode
+}
@@ -0,0 +1,226 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/docker/docker/api/types/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+func TestUserNamespaceFromSecurityOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		options []string
+		want    runtime.UserNamespace
+	}{
+		{name: "rootful", options: []string{"name=apparmor", "name=seccomp,profile=builtin"}, want: runtime.UserNamespaceHost},
+		{name: "rootless", options: []string{"name=seccomp,profile=builtin", "name=rootless"}, want: runtime.UserNamespaceRootless},
+		{name: "userns-remap", options: []string{"name=seccomp,profile=builtin", "name=userns"}, want: runtime.UserNamespaceRemapped},
+		{name: "no options", want: runtime.UserNamespaceHost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, UserNamespaceFromSecurityOptions(tt.options))
+		})
+	}
+}
+
+func TestNewUserNamespaceSetup(t *testing.T) {
+	t.Parallel()
+
+	subordinateIDs := "# comment\nalice:100000:65536\n1001:165536:65536\ndockremap:231072:65536\n"
+
+	tests := []struct {
+		name    string
+		mode    runtime.UserNamespace
+		rootDir string
+		uid     int
+		names   []string
+		want    []IDRange
+	}{
+		{name: "host", mode: runtime.UserNamespaceHost, uid: 1000, names: []string{"alice"}},
+		{
+			name: "rootless by user name", mode: runtime.UserNamespaceRootless, uid: 1000, names: []string{"alice"},
+			want: []IDRange{{ContainerID: 0, HostID: 1000, Size: 1}, {ContainerID: 1, HostID: 100000, Size: 65536}},
+		},
+		{
+			name: "rootless by UID", mode: runtime.UserNamespaceRootless, uid: 1001, names: []string{"bob"},
+			want: []IDRange{{ContainerID: 0, HostID: 1001, Size: 1}, {ContainerID: 1, HostID: 165536, Size: 65536}},
+		},
+		{
+			name: "rootless without subordinate IDs", mode: runtime.UserNamespaceRootless, uid: 1002, names: []string{"carol"},
+			want: []IDRange{{ContainerID: 0, HostID: 1002, Size: 1}},
+		},
+		{
+			name: "remapped from the data root directory", mode: runtime.UserNamespaceRemapped,
+			rootDir: "/var/lib/docker/165536.165536", uid: 1000,
+			want: []IDRange{{ContainerID: 0, HostID: 165536, Size: 65536}},
+		},
+		{
+			name: "remapped from the remap user", mode: runtime.UserNamespaceRemapped, rootDir: "/var/lib/docker", uid: 1000,
+			want: []IDRange{{ContainerID: 0, HostID: 231072, Size: 65536}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			setup := newUserNamespaceSetup(tt.mode, tt.rootDir, subordinateIDs, tt.uid, tt.names...)
+			assert.Equal(t, tt.mode, setup.Mode)
+			assert.Equal(t, tt.want, setup.UIDMap)
+		})
+	}
+}
+
+func TestApplyUserNamespace(t *testing.T) {
+	t.Parallel()
+
+	uid := uint32(os.Getuid()) // #nosec G115
+	rootless := UserNamespaceSetup{
+		Mode:   runtime.UserNamespaceRootless,
+		UIDMap: []IDRange{{ContainerID: 0, HostID: uid, Size: 1}, {ContainerID: 1, HostID: 100000, Size: 65536}},
+	}
+	rootlessWithoutSubordinateIDs := UserNamespaceSetup{
+		Mode:   runtime.UserNamespaceRootless,
+		UIDMap: []IDRange{{ContainerID: 0, HostID: uid, Size: 1}},
+	}
+	remapped := UserNamespaceSetup{
+		Mode:   runtime.UserNamespaceRemapped,
+		UIDMap: []IDRange{{ContainerID: 0, HostID: 231072, Size: 65536}},
+	}
+
+	tests := []struct {
+		name        string
+		runtimeType runtime.Type
+		setup       UserNamespaceSetup
+		user        string
+		imageUser   string
+		wantUser    string
+		wantUserns  string
+		wantErr     bool
+	}{
+		{
+			name: "rootful", runtimeType: runtime.TypeDocker, setup: UserNamespaceSetup{Mode: runtime.UserNamespaceHost},
+			user: "1000", wantUser: "1000",
+		},
+		{
+			name: "rootless Podman", runtimeType: runtime.TypePodman, setup: rootless, wantUserns: "keep-id",
+		},
+		{
+			name: "rootless Podman with a user", runtimeType: runtime.TypePodman, setup: rootless,
+			user: "1000:1001", wantUser: "1000:1001", wantUserns: "keep-id:uid=1000,gid=1001",
+		},
+		{
+			name: "rootless Podman with a user name", runtimeType: runtime.TypePodman, setup: rootless,
+			user: "node", wantUser: "node", wantUserns: "keep-id",
+		},
+		{name: "rootless Docker with a root image", runtimeType: runtime.TypeDocker, setup: rootless},
+		{
+			name: "rootless Docker with a root image user", runtimeType: runtime.TypeDocker, setup: rootless,
+			imageUser: "root",
+		},
+		{
+			name: "rootless Docker with root", runtimeType: runtime.TypeDocker, setup: rootless,
+			user: "0:0", wantUser: "0:0",
+		},
+		{
+			name: "rootless Docker with a user", runtimeType: runtime.TypeDocker, setup: rootless,
+			user: "1000", wantUser: "1000",
+		},
+		{
+			name: "rootless Docker with an image user", runtimeType: runtime.TypeDocker, setup: rootless,
+			imageUser: "1000:1000",
+		},
+		{
+			name: "rootless Docker with a user name", runtimeType: runtime.TypeDocker, setup: rootless,
+			imageUser: "node", wantErr: true,
+		},
+		{
+			name: "rootless containerd without subordinate IDs", runtimeType: runtime.TypeContainerd,
+			setup: rootlessWithoutSubordinateIDs, user: "1000", wantErr: true,
+		},
+		{name: "userns-remap", runtimeType: runtime.TypeDocker, setup: remapped},
+		{
+			name: "userns-remap without a UID map", runtimeType: runtime.TypeDocker,
+			setup: UserNamespaceSetup{Mode: runtime.UserNamespaceRemapped}, wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			config := &runtime.PermissionConfig{User: tt.user}
+			err := ApplyUserNamespace(config, tt.runtimeType, tt.setup, func() (string, error) { return tt.imageUser, nil })
+			if tt.wantErr {
+				require.Error(t, err)
+				// The user namespace of the host and root are never used as a fallback
+				assert.Equal(t, tt.user, config.User)
+				assert.Empty(t, config.UsernsMode)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUser, config.User)
+			assert.Equal(t, tt.wantUserns, config.UsernsMode)
+		})
+	}
+}
+
+func TestDeployWorkload_WriteMountsWithRootlessPodman(t *testing.T) {
+	t.Parallel()
+
+	fops := &fakeDeployOps{}
+	c := newClientWithOps(fops)
+	c.runtimeType = runtime.TypePodman
+	c.api = &fakeDockerAPI{infoFunc: func(context.Context) (system.Info, error) {
+		return system.Info{SecurityOptions: []string{"name=seccomp,profile=default", "name=rootless"}}, nil
+	}}
+
+	profile := &permissions.Profile{Write: []permissions.MountDeclaration{"/tmp/project:/project"}}
+	_, err := c.DeployWorkload(t.Context(), "ghcr.io/example/mcp:latest", "app", nil, map[string]string{},
+		map[string]string{}, profile, "stdio", runtime.NewDeployWorkloadOptions(), false)
+	require.NoError(t, err)
+
+	require.True(t, fops.mcpCalled)
+	assert.Equal(t, "keep-id", fops.mcpPermissionCfg.UsernsMode)
+}
+
+func TestDeployWorkload_NoWriteMountsKeepsUserNamespace(t *testing.T) {
+	t.Parallel()
+
+	fops := &fakeDeployOps{}
+	c := newClientWithOps(fops)
+	c.runtimeType = runtime.TypePodman
+	c.api = &fakeDockerAPI{infoFunc: func(context.Context) (system.Info, error) {
+		return system.Info{SecurityOptions: []string{"name=rootless"}}, nil
+	}}
+
+	profile := &permissions.Profile{Read: []permissions.MountDeclaration{"/tmp/project:/project"}}
+	_, err := c.DeployWorkload(t.Context(), "ghcr.io/example/mcp:latest", "app", nil, map[string]string{},
+		map[string]string{}, profile, "stdio", runtime.NewDeployWorkloadOptions(), false)
+	require.NoError(t, err)
+
+	require.True(t, fops.mcpCalled)
+	assert.Empty(t, fops.mcpPermissionCfg.UsernsMode)
+	assert.Empty(t, fops.mcpPermissionCfg.User)
+}
+
+func TestUserNamespace(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{runtimeType: runtime.TypeDocker, api: &fakeDockerAPI{infoFunc: func(context.Context) (system.Info, error) {
+		return system.Info{SecurityOptions: []string{"name=userns"}}, nil
+	}}}
+
+	info, err := c.UserNamespace(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, runtime.UserNamespaceRemapped, info.Mode)
+	assert.Contains(t, info.WriteMounts, "ACLs")
+	assert.NotEmpty(t, info.Warnings)
+}
@@ -9,8 +9,10 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/stacklok/toolhive/pkg/container/containerd"
 	"github.com/stacklok/toolhive/pkg/container/docker"
 	"github.com/stacklok/toolhive/pkg/container/kubernetes"
+	"github.com/stacklok/toolhive/pkg/container/process"
 	"github.com/stacklok/toolhive/pkg/container/runtime"
 )
 
@@ -46,7 +48,7 @@ func NewFactory() *Factory {
 	return f
 }
 
-// registerDefaultRuntimes registers the built-in docker and kubernetes runtimes
+// registerDefaultRuntimes registers the built-in docker, kubernetes, containerd and process runtimes
 func (f *Factory) registerDefaultRuntimes() {
 	// Register Docker runtime
 	f.Register(&RuntimeInfo{ //nolint:gosec // Built-in runtime registration cannot fail
@@ -71,6 +73,30 @@ func (f *Factory) registerDefaultRuntimes() {
 			return runtime.IsKubernetesRuntime()
 		},
 	})
+
+	// Register containerd runtime
+	f.Register(&RuntimeInfo{ //nolint:gosec // Built-in runtime registration cannot fail
+		Name: containerd.RuntimeName,
+		Initializer: func(ctx context.Context) (runtime.Runtime, error) {
+			return containerd.NewClient(ctx)
+		},
+		AutoDetector: func() bool {
+			// Check if nerdctl is installed and a containerd socket is reachable
+			return containerd.IsAvailable()
+		},
+	})
+
+	// Register process runtime
+	f.Register(&RuntimeInfo{ //nolint:gosec // Built-in runtime registration cannot fail
+		Name: process.RuntimeName,
+		Initializer: func(ctx context.Context) (runtime.Runtime, error) {
+			return process.NewClient(ctx)
+		},
+		AutoDetector: func() bool {
+			// The process runtime has no container isolation, so it must be selected explicitly
+			return process.IsAvailable()
+		},
+	})
 }
 
 // Register registers a new runtime with the factory
@@ -134,7 +160,8 @@ func (f *Factory) ListAvailableRuntimes() map[string]*RuntimeInfo {
 }
 
 // autoDetectRuntime returns the first available runtime based on auto-detection
-// This checks runtimes in a predictable order: Docker first, then Kubernetes
+// This checks runtimes in a predictable order: Docker first, then Kubernetes, then containerd,
+// then the process runtime, which is only available when selected explicitly
 func (f *Factory) autoDetectRuntime() (string, *RuntimeInfo) {
 	available := f.ListAvailableRuntimes()
 
@@ -142,6 +169,8 @@ func (f *Factory) autoDetectRuntime() (string, *RuntimeInfo) {
 	preferredOrder := []string{
 		docker.RuntimeName,     // "docker"
 		kubernetes.RuntimeName, // "kubernetes"
+		containerd.RuntimeName, // "containerd"
+		process.RuntimeName,    // "process"
 	}
 
 	// Check runtimes in the preferred order
@@ -240,7 +269,8 @@ func CheckRuntimeAvailable() error {
 
 	if len(available) == 0 {
 		return fmt.Errorf("no container runtime available. ToolHive requires Docker, Podman, Colima, " +
-			"or a Kubernetes environment to run MCP servers")
+			"containerd with nerdctl, or a Kubernetes environment to run MCP servers. Trusted uvx://, npx:// and go:// servers can run " +
+			"without containers by setting TOOLHIVE_RUNTIME=process")
 	}
 
 	return nil
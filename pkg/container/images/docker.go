@@ -13,7 +13,9 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	dockerimage "github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/client"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 
+	"github.com/stacklok/toolhive/pkg/labels"
 	"github.com/stacklok/toolhive/pkg/logger"
 )
 
@@ -49,7 +51,7 @@ func (d *DockerImageManager) ImageExists(ctx context.Context, imageName string)
 
 // BuildImage builds a Docker image from a Dockerfile in the specified context directory
 func (d *DockerImageManager) BuildImage(ctx context.Context, contextDir, imageName string) error {
-	return buildDockerImage(ctx, d.client, contextDir, imageName)
+	return buildDockerImage(ctx, d.client, contextDir, imageName, nil)
 }
 
 // PullImage pulls an image from a registry
@@ -68,11 +70,15 @@ func (d *DockerImageManager) PullImage(ctx context.Context, imageName string) er
 		return fmt.Errorf("failed to process pull output: %v", err)
 	}
 
+	recordPull(ctx, d, imageName)
 	return nil
 }
 
-// buildDockerImage builds a Docker image using the Docker client API
-func buildDockerImage(ctx context.Context, dockerClient *client.Client, contextDir, imageName string) error {
+// buildDockerImage builds a Docker image using the Docker client API, for the
+// platform of the daemon unless another platform is given
+func buildDockerImage(
+	ctx context.Context, dockerClient *client.Client, contextDir, imageName string, platform *v1.Platform,
+) error {
 	logger.Infof("Building image %s from context directory %s", imageName, contextDir)
 
 	// Create a tar archive of the context directory
@@ -98,6 +104,11 @@ func buildDockerImage(ctx context.Context, dockerClient *client.Client, contextD
 		Tags:       []string{imageName},
 		Dockerfile: "Dockerfile",
 		Remove:     true,
+		// Images built by ToolHive are labeled so that they can be pruned
+		Labels: map[string]string{labels.LabelImage: labels.LabelToolHiveValue},
+	}
+	if platform != nil {
+		buildOptions.Platform = platform.String()
 	}
 
 	response, err := dockerClient.ImageBuild(ctx, tarFile, buildOptions)
@@ -4,6 +4,7 @@ package images
 import (
 	"context"
 
+	"github.com/stacklok/toolhive/pkg/container/containerd/nerdctl"
 	"github.com/stacklok/toolhive/pkg/container/docker/sdk"
 	"github.com/stacklok/toolhive/pkg/container/runtime"
 	"github.com/stacklok/toolhive/pkg/logger"
@@ -33,16 +34,32 @@ func NewImageManager(ctx context.Context) ImageManager {
 		return &NoopImageManager{}
 	}
 
+	// Images of the containerd runtime live in its own content store
+	if runtime.IsContainerdRuntime() {
+		return newNerdctlOrNoopImageManager()
+	}
+
 	// Check if we are running in a Docker or compatible environment
 	dockerClient, _, _, err := sdk.NewDockerClient(ctx)
 	if err != nil {
-		logger.Debug("no docker runtime found, using no-op image manager")
-		return &NoopImageManager{}
+		// containerd is auto-detected when Docker is not available
+		logger.Debug("no docker runtime found, checking for containerd")
+		return newNerdctlOrNoopImageManager()
 	}
 
 	return NewRegistryImageManager(dockerClient)
 }
 
+// newNerdctlOrNoopImageManager returns an image manager for containerd if it is available
+func newNerdctlOrNoopImageManager() ImageManager {
+	cli, err := nerdctl.New()
+	if err != nil {
+		logger.Debugf("containerd is not available, using no-op image manager: %v", err)
+		return &NoopImageManager{}
+	}
+	return NewNerdctlImageManager(cli)
+}
+
 // NoopImageManager is a no-op implementation of ImageManager.
 type NoopImageManager struct{}
 
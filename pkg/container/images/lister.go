@@ -0,0 +1,185 @@
+package images
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	dockerimage "github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-units"
+
+	"github.com/stacklok/toolhive/pkg/labels"
+)
+
+// Image is a local image
+type Image struct {
+	ID      string            `json:"id"`
+	Tags    []string          `json:"tags,omitempty"`
+	Digests []string          `json:"digests,omitempty"`
+	Created time.Time         `json:"created"`
+	Size    int64             `json:"size"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// ImageLister is implemented by image managers that can list and remove local images
+type ImageLister interface {
+	// ListImages returns the local images
+	ListImages(ctx context.Context) ([]Image, error)
+	// RemoveImage removes a local image by its ID, together with all its tags. Images used by
+	// containers, including stopped ones, are not removed.
+	RemoveImage(ctx context.Context, id string) error
+}
+
+// ListImages returns the images of the Docker daemon
+func (d *DockerImageManager) ListImages(ctx context.Context) ([]Image, error) {
+	return listDaemonImages(ctx, d.client)
+}
+
+// RemoveImage removes an image of the Docker daemon
+func (d *DockerImageManager) RemoveImage(ctx context.Context, id string) error {
+	return removeDaemonImage(ctx, d.client, id)
+}
+
+// ListImages returns the images of the Docker daemon
+func (r *RegistryImageManager) ListImages(ctx context.Context) ([]Image, error) {
+	return listDaemonImages(ctx, r.dockerClient)
+}
+
+// RemoveImage removes an image of the Docker daemon
+func (r *RegistryImageManager) RemoveImage(ctx context.Context, id string) error {
+	return removeDaemonImage(ctx, r.dockerClient, id)
+}
+
+// listDaemonImages returns the images of the Docker daemon
+func listDaemonImages(ctx context.Context, dockerClient *client.Client) ([]Image, error) {
+	summaries, err := dockerClient.ImageList(ctx, dockerimage.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+
+	images := make([]Image, 0, len(summaries))
+	for _, summary := range summaries {
+		image := Image{
+			ID:      summary.ID,
+			Created: time.Unix(summary.Created, 0),
+			Size:    summary.Size,
+			Labels:  summary.Labels,
+			Digests: summary.RepoDigests,
+		}
+		for _, tag := range summary.RepoTags {
+			if tag != "<none>:<none>" {
+				image.Tags = append(image.Tags, tag)
+			}
+		}
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+// removeDaemonImage removes an image of the Docker daemon with all its tags. Removing an image with
+// several tags by its ID must be forced, and forcing would also remove the images of stopped
+// containers, so images used by any container are refused first.
+func removeDaemonImage(ctx context.Context, dockerClient *client.Client, id string) error {
+	containers, err := dockerClient.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("ancestor", id)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list the containers of image %s: %w", id, err)
+	}
+	if len(containers) > 0 {
+		return fmt.Errorf("image %s is used by container %s", id, containers[0].ID)
+	}
+
+	_, err = dockerClient.ImageRemove(ctx, id, dockerimage.RemoveOptions{Force: true, PruneChildren: true})
+	if err != nil {
+		return fmt.Errorf("failed to remove image %s: %w", id, err)
+	}
+	return nil
+}
+
+// nerdctlImage is an image in the JSON output of nerdctl images
+type nerdctlImage struct {
+	ID         string `json:"ID"`
+	Repository string `json:"Repository"`
+	Tag        string `json:"Tag"`
+	Digest     string `json:"Digest"`
+	CreatedAt  string `json:"CreatedAt"`
+	Size       string `json:"Size"`
+}
+
+// ListImages returns the images of the containerd namespace
+func (n *NerdctlImageManager) ListImages(ctx context.Context) ([]Image, error) {
+	output, err := n.cli.Run(ctx, "images", "--no-trunc", "--format", "{{json .}}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+
+	// nerdctl lists an image once per tag
+	var images []Image
+	index := map[string]int{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		var entry nerdctlImage
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse images: %w", err)
+		}
+
+		i, ok := index[entry.ID]
+		if !ok {
+			image := Image{ID: entry.ID}
+			image.Created, _ = time.Parse("2006-01-02 15:04:05 -0700 MST", entry.CreatedAt)
+			if size, err := units.RAMInBytes(entry.Size); err == nil {
+				image.Size = size
+			}
+			i = len(images)
+			index[entry.ID] = i
+			images = append(images, image)
+		}
+		if entry.Repository == "" || entry.Repository == "<none>" {
+			continue
+		}
+		if entry.Tag != "" && entry.Tag != "<none>" {
+			images[i].Tags = append(images[i].Tags, entry.Repository+":"+entry.Tag)
+		}
+		if entry.Digest != "" {
+			images[i].Digests = append(images[i].Digests, entry.Repository+"@"+entry.Digest)
+		}
+	}
+
+	// The JSON output has no labels, so the images built by ToolHive are found with a filter
+	output, err = n.cli.Run(ctx, "images", "--quiet", "--no-trunc", "--filter", "label="+imageLabel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+	for _, id := range strings.Fields(string(output)) {
+		if i, ok := index[id]; ok {
+			images[i].Labels = map[string]string{labels.LabelImage: labels.LabelToolHiveValue}
+		}
+	}
+	return images, nil
+}
+
+// RemoveImage removes an image of the containerd namespace with all its tags. As with Docker,
+// the removal is forced, so images used by any container are refused first.
+func (n *NerdctlImageManager) RemoveImage(ctx context.Context, id string) error {
+	output, err := n.cli.Run(ctx, "ps", "--all", "--quiet", "--filter", "ancestor="+id)
+	if err != nil {
+		return fmt.Errorf("failed to list the containers of image %s: %w", id, err)
+	}
+	if containers := strings.Fields(string(output)); len(containers) > 0 {
+		return fmt.Errorf("image %s is used by container %s", id, containers[0])
+	}
+
+	if _, err := n.cli.Run(ctx, "rmi", "--force", id); err != nil {
+		return fmt.Errorf("failed to remove image %s: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package images
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/stacklok/toolhive/pkg/container/containerd/nerdctl"
+	"github.com/stacklok/toolhive/pkg/labels"
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// imageLabel labels the images built by ToolHive, so that they can be pruned
+const imageLabel = labels.LabelImage + "=" + labels.LabelToolHiveValue
+
+// NerdctlImageManager implements the ImageManager interface for containerd,
+// using nerdctl so that images are stored in the namespace of the workloads.
+type NerdctlImageManager struct {
+	cli *nerdctl.CLI
+}
+
+// NewNerdctlImageManager creates a new NerdctlImageManager instance
+// This is intended for the containerd runtime implementation.
+func NewNerdctlImageManager(cli *nerdctl.CLI) *NerdctlImageManager {
+	return &NerdctlImageManager{
+		cli: cli,
+	}
+}
+
+// ImageExists checks if an image exists locally
+func (n *NerdctlImageManager) ImageExists(ctx context.Context, imageName string) (bool, error) {
+	if _, err := n.cli.Run(ctx, "image", "inspect", imageName); err != nil {
+		if errors.Is(err, nerdctl.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to inspect image: %v", err)
+	}
+	return true, nil
+}
+
+// PullImage pulls an image from a registry
+func (n *NerdctlImageManager) PullImage(ctx context.Context, imageName string) error {
+	logger.Infof("Pulling image: %s", imageName)
+
+	cmd := n.cli.Command(ctx, "pull", "--quiet", imageName)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to pull image: %v", err)
+	}
+
+	recordPull(ctx, n, imageName)
+	return nil
+}
+
+// BuildImage builds an image from a Dockerfile in the specified context directory.
+// nerdctl delegates builds to BuildKit, which must be running.
+func (n *NerdctlImageManager) BuildImage(ctx context.Context, contextDir, imageName string) error {
+	logger.Infof("Building image %s from context directory %s", imageName, contextDir)
+
+	cmd := n.cli.Command(ctx, "build", "--label", imageLabel, "--tag", imageName, contextDir)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to build image: %v", err)
+	}
+
+	logger.Infof("Successfully built image: %s", imageName)
+	return nil
+}
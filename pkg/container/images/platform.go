@@ -0,0 +1,309 @@
+package images
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// ErrPlatformsUnsupported is returned when the image manager of the runtime cannot build images for other platforms
+var ErrPlatformsUnsupported = errors.New("the container runtime cannot build images for other platforms")
+
+// PlatformBuilder is implemented by image managers that can build and export images for
+// platforms other than the host, for example with QEMU emulation.
+type PlatformBuilder interface {
+	// BuildPlatformImage builds an image for a platform from a Dockerfile in the context directory
+	BuildPlatformImage(ctx context.Context, contextDir, imageName string, platform *v1.Platform) error
+	// SavePlatformImage writes the local image of a platform to w as a tarball in the format of docker save
+	SavePlatformImage(ctx context.Context, imageName string, platform *v1.Platform, w io.Writer) error
+}
+
+// BuildPlatformImage builds an image for a platform with the Docker daemon
+func (d *DockerImageManager) BuildPlatformImage(
+	ctx context.Context, contextDir, imageName string, platform *v1.Platform,
+) error {
+	return buildDockerImage(ctx, d.client, contextDir, imageName, platform)
+}
+
+// SavePlatformImage writes the image of a platform of the Docker daemon to w
+func (d *DockerImageManager) SavePlatformImage(
+	ctx context.Context, imageName string, _ *v1.Platform, w io.Writer,
+) error {
+	// The image was built for a single platform, so its tag refers to that platform
+	return saveDaemonImage(ctx, d.client, imageName, w)
+}
+
+// BuildPlatformImage builds an image for a platform with the Docker daemon
+func (r *RegistryImageManager) BuildPlatformImage(
+	ctx context.Context, contextDir, imageName string, platform *v1.Platform,
+) error {
+	return buildDockerImage(ctx, r.dockerClient, contextDir, imageName, platform)
+}
+
+// SavePlatformImage writes the image of a platform of the Docker daemon to w
+func (r *RegistryImageManager) SavePlatformImage(
+	ctx context.Context, imageName string, _ *v1.Platform, w io.Writer,
+) error {
+	return saveDaemonImage(ctx, r.dockerClient, imageName, w)
+}
+
+// BuildPlatformImage builds an image for a platform with BuildKit
+func (n *NerdctlImageManager) BuildPlatformImage(
+	ctx context.Context, contextDir, imageName string, platform *v1.Platform,
+) error {
+	logger.Infof("Building image %s for %s from context directory %s", imageName, platform, contextDir)
+
+	cmd := n.cli.Command(ctx, "build", "--platform", platform.String(),
+		"--label", imageLabel, "--tag", imageName, contextDir)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to build image: %v", err)
+	}
+	return nil
+}
+
+// SavePlatformImage writes the image of a platform of the containerd content store to w.
+// Only the content of the host platform is exported unless another platform is given.
+func (n *NerdctlImageManager) SavePlatformImage(
+	ctx context.Context, imageName string, platform *v1.Platform, w io.Writer,
+) error {
+	return n.saveImage(ctx, imageName, w, "--platform", platform.String())
+}
+
+// HostPlatform returns the platform of the images that run on the host
+func HostPlatform() *v1.Platform {
+	return getDefaultPlatform()
+}
+
+// ParsePlatforms parses platforms in the os/arch[/variant] format
+func ParsePlatforms(specs []string) ([]*v1.Platform, error) {
+	var platforms []*v1.Platform
+	for _, spec := range specs {
+		platform, err := v1.ParsePlatform(strings.TrimSpace(spec))
+		if err != nil {
+			return nil, fmt.Errorf("invalid platform %q: %w", spec, err)
+		}
+		if platform.OS == "" || platform.Architecture == "" {
+			return nil, fmt.Errorf("invalid platform %q: expected os/arch[/variant]", spec)
+		}
+		for _, p := range platforms {
+			if p.Equals(*platform) {
+				return nil, fmt.Errorf("duplicate platform %q", spec)
+			}
+		}
+		platforms = append(platforms, platform)
+	}
+	return platforms, nil
+}
+
+// PlatformImageName returns the local name of the image of a platform. The image of the host
+// platform has the name of the image, so that it can be run; the tags of the images of other
+// platforms have the platform as suffix, such as fetch:1.0-linux-arm64.
+func PlatformImageName(imageName string, platform *v1.Platform) string {
+	if platform.Equals(*HostPlatform()) {
+		return imageName
+	}
+
+	suffix := platform.OS + "-" + platform.Architecture
+	if platform.Variant != "" {
+		suffix += "-" + platform.Variant
+	}
+
+	// The tag follows the last colon after the last slash, which is not a registry port
+	repository, tag := imageName, "latest"
+	if i := strings.LastIndex(imageName, ":"); i > strings.LastIndex(imageName, "/") {
+		repository, tag = imageName[:i], imageName[i+1:]
+	}
+	return repository + ":" + tag + "-" + suffix
+}
+
+// PlatformImageManager builds an image for each of several platforms with an image manager,
+// and assembles them into a multi-platform image index that can be exported or pushed.
+type PlatformImageManager struct {
+	ImageManager
+	builder   PlatformBuilder
+	platforms []*v1.Platform
+	keychain  authn.Keychain
+}
+
+// NewPlatformImageManager creates a PlatformImageManager for the platforms.
+// ErrPlatformsUnsupported is returned if the image manager cannot build images for other platforms.
+func NewPlatformImageManager(imageManager ImageManager, platforms []*v1.Platform) (*PlatformImageManager, error) {
+	builder, ok := imageManager.(PlatformBuilder)
+	if !ok {
+		return nil, ErrPlatformsUnsupported
+	}
+	if len(platforms) == 0 {
+		platforms = []*v1.Platform{HostPlatform()}
+	}
+	return &PlatformImageManager{
+		ImageManager: imageManager,
+		builder:      builder,
+		platforms:    platforms,
+		keychain:     NewCompositeKeychain(),
+	}, nil
+}
+
+// WithKeychain sets the keychain for authentication to the registry images are pushed to
+func (m *PlatformImageManager) WithKeychain(keychain authn.Keychain) *PlatformImageManager {
+	m.keychain = keychain
+	return m
+}
+
+// ImageExists checks if the images of all platforms exist locally
+func (m *PlatformImageManager) ImageExists(ctx context.Context, imageName string) (bool, error) {
+	for _, platform := range m.platforms {
+		exists, err := m.ImageManager.ImageExists(ctx, PlatformImageName(imageName, platform))
+		if err != nil || !exists {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// BuildImage builds the image for each platform
+func (m *PlatformImageManager) BuildImage(ctx context.Context, contextDir, imageName string) error {
+	for _, platform := range m.platforms {
+		platformImage := PlatformImageName(imageName, platform)
+		logger.Infof("Building image %s for platform %s", platformImage, platform)
+		if err := m.builder.BuildPlatformImage(ctx, contextDir, platformImage, platform); err != nil {
+			return fmt.Errorf("failed to build image for platform %s: %w", platform, err)
+		}
+	}
+	return nil
+}
+
+// WriteOCILayout writes the images of all platforms to a tarball of an OCI image layout,
+// as an index named after the tag of the image
+func (m *PlatformImageManager) WriteOCILayout(ctx context.Context, imageName, path string) error {
+	idx, cleanup, err := m.index(ctx, imageName)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	layoutDir, err := os.MkdirTemp("", "toolhive-oci-layout-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(layoutDir)
+
+	layoutPath, err := layout.Write(layoutDir, empty.Index)
+	if err != nil {
+		return fmt.Errorf("failed to create OCI layout: %w", err)
+	}
+	annotations := map[string]string{"org.opencontainers.image.ref.name": imageName}
+	if err := layoutPath.AppendIndex(idx, layout.WithAnnotations(annotations)); err != nil {
+		return fmt.Errorf("failed to write OCI layout: %w", err)
+	}
+
+	// #nosec G304 -- the output is chosen by the user
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	err = createTarFromDir(layoutDir, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Push pushes the images of all platforms to a registry, as an index tagged with the name of the image
+func (m *PlatformImageManager) Push(ctx context.Context, imageName string) error {
+	ref, err := name.ParseReference(imageName)
+	if err != nil {
+		return fmt.Errorf("invalid image name %s: %w", imageName, err)
+	}
+
+	idx, cleanup, err := m.index(ctx, imageName)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	logger.Infof("Pushing image %s", ref.Name())
+	if err := remote.WriteIndex(ref, idx, remote.WithContext(ctx), remote.WithAuthFromKeychain(m.keychain)); err != nil {
+		return fmt.Errorf("failed to push image %s: %w", imageName, err)
+	}
+	return nil
+}
+
+// index returns an index of the images of all platforms. The images are saved to a temporary
+// directory, which the cleanup function removes once the index has been written.
+func (m *PlatformImageManager) index(ctx context.Context, imageName string) (v1.ImageIndex, func(), error) {
+	dir, err := os.MkdirTemp("", "toolhive-platforms-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(dir); err != nil {
+			logger.Debugf("Failed to remove %s: %v", dir, err)
+		}
+	}
+
+	idx := mutate.IndexMediaType(empty.Index, types.OCIImageIndex)
+	for i, platform := range m.platforms {
+		img, err := m.saveImage(ctx, PlatformImageName(imageName, platform), platform, filepath.Join(dir, fmt.Sprint(i, ".tar")))
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		idx = mutate.AppendManifests(idx, mutate.IndexAddendum{
+			Add:        img,
+			Descriptor: v1.Descriptor{Platform: platform},
+		})
+	}
+	return idx, cleanup, nil
+}
+
+// saveImage saves the local image of a platform to a file, and checks that it was built for the platform
+func (m *PlatformImageManager) saveImage(
+	ctx context.Context, imageName string, platform *v1.Platform, path string,
+) (v1.Image, error) {
+	// #nosec G304 -- the path is in a temporary directory
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	err = m.builder.SavePlatformImage(ctx, imageName, platform, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := tarball.ImageFromPath(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image %s: %w", imageName, err)
+	}
+	config, err := img.ConfigFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration of image %s: %w", imageName, err)
+	}
+	if built := config.Platform(); built == nil || !built.Satisfies(*platform) {
+		return nil, fmt.Errorf("image %s was built for platform %s instead of %s", imageName, config.Platform(), platform)
+	}
+	return img, nil
+}
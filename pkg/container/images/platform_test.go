@@ -0,0 +1,211 @@
+package images
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePlatformBuilder is an image manager that builds random images for platforms
+type fakePlatformBuilder struct {
+	NoopImageManager
+	images map[string]v1.Image
+	// builtPlatform overrides the platform of the built images
+	builtPlatform *v1.Platform
+}
+
+func (f *fakePlatformBuilder) ImageExists(_ context.Context, imageName string) (bool, error) {
+	_, ok := f.images[imageName]
+	return ok, nil
+}
+
+func (f *fakePlatformBuilder) BuildPlatformImage(_ context.Context, _, imageName string, platform *v1.Platform) error {
+	img, err := random.Image(128, 1)
+	if err != nil {
+		return err
+	}
+	if f.builtPlatform != nil {
+		platform = f.builtPlatform
+	}
+	config, err := img.ConfigFile()
+	if err != nil {
+		return err
+	}
+	config.OS, config.Architecture, config.Variant = platform.OS, platform.Architecture, platform.Variant
+	if f.images[imageName], err = mutate.ConfigFile(img, config); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (f *fakePlatformBuilder) SavePlatformImage(_ context.Context, imageName string, _ *v1.Platform, w io.Writer) error {
+	img, ok := f.images[imageName]
+	if !ok {
+		return fmt.Errorf("image %s not found", imageName)
+	}
+	tag, err := name.NewTag(imageName)
+	if err != nil {
+		return err
+	}
+	return tarball.Write(tag, img, w)
+}
+
+// newTestPlatformManager creates a PlatformImageManager with a fake builder for the platforms
+func newTestPlatformManager(t *testing.T, platforms ...string) (*PlatformImageManager, *fakePlatformBuilder) {
+	t.Helper()
+
+	parsed, err := ParsePlatforms(platforms)
+	require.NoError(t, err)
+	builder := &fakePlatformBuilder{images: map[string]v1.Image{}}
+	manager, err := NewPlatformImageManager(builder, parsed)
+	require.NoError(t, err)
+	return manager, builder
+}
+
+func TestPlatformImageName(t *testing.T) {
+	t.Parallel()
+
+	arm := &v1.Platform{OS: "linux", Architecture: "arm", Variant: "v7"}
+	assert.Equal(t, "toolhivelocal/npx-fetch:1.0-linux-arm-v7", PlatformImageName("toolhivelocal/npx-fetch:1.0", arm))
+	assert.Equal(t, "localhost:5000/fetch:latest-linux-arm-v7", PlatformImageName("localhost:5000/fetch", arm))
+	assert.Equal(t, "fetch:1.0", PlatformImageName("fetch:1.0", HostPlatform()))
+}
+
+func TestParsePlatforms(t *testing.T) {
+	t.Parallel()
+
+	platforms, err := ParsePlatforms([]string{"linux/amd64", " linux/arm64/v8"})
+	require.NoError(t, err)
+	assert.Equal(t, []*v1.Platform{
+		{OS: "linux", Architecture: "amd64"},
+		{OS: "linux", Architecture: "arm64", Variant: "v8"},
+	}, platforms)
+
+	_, err = ParsePlatforms([]string{"linux"})
+	assert.Error(t, err)
+	_, err = ParsePlatforms([]string{"linux/amd64", "linux/amd64"})
+	assert.ErrorContains(t, err, "duplicate platform")
+}
+
+func TestNewPlatformImageManagerUnsupported(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewPlatformImageManager(&NoopImageManager{}, nil)
+	assert.True(t, errors.Is(err, ErrPlatformsUnsupported))
+}
+
+func TestPlatformImageManagerBuild(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	manager, builder := newTestPlatformManager(t, "linux/amd64", "linux/arm64", "linux/riscv64")
+
+	exists, err := manager.ImageExists(ctx, "example/fetch:1.0")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, manager.BuildImage(ctx, t.TempDir(), "example/fetch:1.0"))
+	assert.Contains(t, builder.images, "example/fetch:1.0-linux-riscv64")
+	assert.Len(t, builder.images, 3)
+
+	exists, err = manager.ImageExists(ctx, "example/fetch:1.0")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestPlatformImageManagerPush(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	imageName := strings.TrimPrefix(server.URL, "http://") + "/example/fetch:1.0"
+
+	ctx := context.Background()
+	manager, _ := newTestPlatformManager(t, "linux/amd64", "linux/arm64")
+	manager.WithKeychain(authn.DefaultKeychain)
+	require.NoError(t, manager.BuildImage(ctx, t.TempDir(), imageName))
+	require.NoError(t, manager.Push(ctx, imageName))
+
+	ref, err := name.ParseReference(imageName)
+	require.NoError(t, err)
+	idx, err := remote.Index(ref)
+	require.NoError(t, err)
+	manifest, err := idx.IndexManifest()
+	require.NoError(t, err)
+
+	var platforms []string
+	for _, desc := range manifest.Manifests {
+		platforms = append(platforms, desc.Platform.String())
+	}
+	assert.Equal(t, []string{"linux/amd64", "linux/arm64"}, platforms)
+}
+
+func TestPlatformImageManagerWriteOCILayout(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	manager, _ := newTestPlatformManager(t, "linux/amd64", "linux/arm64")
+	require.NoError(t, manager.BuildImage(ctx, t.TempDir(), "example/fetch:1.0"))
+
+	path := filepath.Join(t.TempDir(), "fetch.tar")
+	require.NoError(t, manager.WriteOCILayout(ctx, "example/fetch:1.0", path))
+
+	// The tarball has an OCI layout whose index refers to the multi-platform index
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	files := map[string][]byte{}
+	tr := tar.NewReader(file)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = content
+	}
+	assert.Contains(t, files, "oci-layout")
+
+	var index v1.IndexManifest
+	require.NoError(t, json.Unmarshal(files["index.json"], &index))
+	require.Len(t, index.Manifests, 1)
+	assert.Equal(t, "example/fetch:1.0", index.Manifests[0].Annotations["org.opencontainers.image.ref.name"])
+
+	blob := "blobs/sha256/" + index.Manifests[0].Digest.Hex
+	require.NoError(t, json.Unmarshal(files[blob], &index))
+	assert.Len(t, index.Manifests, 2)
+}
+
+func TestPlatformImageManagerWrongPlatform(t *testing.T) {
+	t.Parallel()
+
+	// Builders without emulation may build images for the host platform instead
+	ctx := context.Background()
+	manager, builder := newTestPlatformManager(t, "linux/s390x")
+	builder.builtPlatform = &v1.Platform{OS: "linux", Architecture: "amd64"}
+	require.NoError(t, manager.BuildImage(ctx, t.TempDir(), "example/fetch:1.0"))
+
+	err := manager.Push(ctx, "example/fetch:1.0")
+	assert.ErrorContains(t, err, "was built for platform linux/amd64 instead of linux/s390x")
+}
@@ -0,0 +1,252 @@
+package images
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/stacklok/toolhive/pkg/labels"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/state"
+)
+
+// Origins of the images managed by ToolHive
+const (
+	// OriginBuilt is the origin of the images built from protocol schemes
+	OriginBuilt = "built"
+	// OriginPulled is the origin of the images pulled from registries
+	OriginPulled = "pulled"
+)
+
+// ManagedImage is a local image that ToolHive built or pulled
+type ManagedImage struct {
+	Image
+	Origin string `json:"origin"`
+	// Since is when the image was built, or last pulled
+	Since time.Time `json:"since"`
+}
+
+// pullRecord records that ToolHive pulled an image. Images cannot be labeled when they are pulled.
+type pullRecord struct {
+	ID       string    `json:"id"`
+	Image    string    `json:"image"`
+	PulledAt time.Time `json:"pulled_at"`
+}
+
+// pullRecordName returns the name of the record of an image ID in the state store
+func pullRecordName(id string) string {
+	return strings.ReplaceAll(id, ":", "-")
+}
+
+// recordPull records that ToolHive pulled an image. Failures are logged, as the image was pulled.
+func recordPull(ctx context.Context, lister ImageLister, imageName string) {
+	if err := savePullRecord(ctx, lister, imageName); err != nil {
+		logger.Debugf("Failed to record the pull of %s: %v", imageName, err)
+	}
+}
+
+func savePullRecord(ctx context.Context, lister ImageLister, imageName string) error {
+	images, err := lister.ListImages(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, image := range images {
+		if !image.hasReference(imageName) {
+			continue
+		}
+
+		store, err := state.NewPulledImageStore(state.DefaultAppName)
+		if err != nil {
+			return err
+		}
+		w, err := store.GetWriter(ctx, pullRecordName(image.ID))
+		if err != nil {
+			return err
+		}
+		err = json.NewEncoder(w).Encode(pullRecord{ID: image.ID, Image: imageName, PulledAt: time.Now()})
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}
+	return fmt.Errorf("image %s not found", imageName)
+}
+
+// loadPullRecords returns the records of pulled images by image ID
+func loadPullRecords(ctx context.Context, store state.Store) (map[string]pullRecord, error) {
+	names, err := store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pulled images: %w", err)
+	}
+
+	records := map[string]pullRecord{}
+	for _, recordName := range names {
+		r, err := store.GetReader(ctx, recordName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pulled image %s: %w", recordName, err)
+		}
+		var record pullRecord
+		err = json.NewDecoder(r).Decode(&record)
+		_ = r.Close()
+		if err != nil {
+			logger.Warnf("Ignoring invalid record of pulled image %s: %v", recordName, err)
+			continue
+		}
+		records[record.ID] = record
+	}
+	return records, nil
+}
+
+// ListManagedImages returns the local images that ToolHive built or pulled, newest first.
+// The records of pulled images that no longer exist are removed.
+func ListManagedImages(ctx context.Context, lister ImageLister) ([]ManagedImage, error) {
+	images, err := lister.ListImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	store, err := state.NewPulledImageStore(state.DefaultAppName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state store: %w", err)
+	}
+	records, err := loadPullRecords(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+
+	var managed []ManagedImage
+	for _, image := range images {
+		switch record, pulled := records[image.ID]; {
+		case image.Labels[labels.LabelImage] == labels.LabelToolHiveValue:
+			managed = append(managed, ManagedImage{Image: image, Origin: OriginBuilt, Since: image.Created})
+		case pulled:
+			managed = append(managed, ManagedImage{Image: image, Origin: OriginPulled, Since: record.PulledAt})
+		}
+		delete(records, image.ID)
+	}
+
+	// The remaining records are of images that were removed
+	for id := range records {
+		if err := store.Delete(ctx, pullRecordName(id)); err != nil {
+			logger.Debugf("Failed to delete the record of pulled image %s: %v", id, err)
+		}
+	}
+
+	sort.Slice(managed, func(i, j int) bool {
+		return managed[i].Since.After(managed[j].Since)
+	})
+	return managed, nil
+}
+
+// IsReferenced reports whether the image is one of the references, by tag or ID
+func (i *Image) IsReferenced(refs []string) bool {
+	for _, ref := range refs {
+		if i.hasReference(ref) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasReference reports whether a reference is a tag, a digest or the ID of the image. References
+// are compared in their canonical form, so that fetch refers to docker.io/library/fetch:latest.
+func (i *Image) hasReference(ref string) bool {
+	if ref == i.ID || strings.TrimPrefix(i.ID, "sha256:") == ref {
+		return true
+	}
+	canonical := canonicalReference(ref)
+	for _, tag := range append(append([]string{}, i.Tags...), i.Digests...) {
+		if canonicalReference(tag) == canonical {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalReference returns the canonical form of an image reference
+func canonicalReference(ref string) string {
+	parsed, err := name.ParseReference(ref)
+	if err != nil {
+		return ref
+	}
+	return parsed.Name()
+}
+
+// PruneOptions selects the images that Prune removes
+type PruneOptions struct {
+	// Unused removes all the images that are not referenced, instead of only the untagged
+	// images which were replaced by newer builds or pulls
+	Unused bool
+	// OlderThan only removes the images built or pulled longer ago than this duration
+	OlderThan time.Duration
+	// Referenced are the images of saved run configurations and of workloads about to start,
+	// which are never removed
+	Referenced []string
+}
+
+// Prune removes the images that ToolHive built or pulled which are selected by the options,
+// and returns the removed images. Images that cannot be removed, for example because they are
+// used by containers, are skipped with a warning.
+func Prune(ctx context.Context, lister ImageLister, opts PruneOptions) ([]ManagedImage, error) {
+	managed, err := ListManagedImages(ctx, lister)
+	if err != nil {
+		return nil, err
+	}
+	store, err := state.NewPulledImageStore(state.DefaultAppName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state store: %w", err)
+	}
+
+	var removed []ManagedImage
+	for _, image := range managed {
+		if !opts.selects(image) {
+			continue
+		}
+		if err := lister.RemoveImage(ctx, image.ID); err != nil {
+			logger.Warnf("Skipping image %s: %v", image.ID, err)
+			continue
+		}
+		if image.Origin == OriginPulled {
+			if err := store.Delete(ctx, pullRecordName(image.ID)); err != nil {
+				logger.Debugf("Failed to delete the record of pulled image %s: %v", image.ID, err)
+			}
+		}
+		removed = append(removed, image)
+	}
+	return removed, nil
+}
+
+// selects reports whether the options select an image for removal
+func (o *PruneOptions) selects(image ManagedImage) bool {
+	if image.IsReferenced(o.Referenced) {
+		return false
+	}
+	if !o.Unused && len(image.Tags) > 0 {
+		return false
+	}
+	return o.OlderThan <= 0 || time.Since(image.Since) > o.OlderThan
+}
+
+// PrunePolicy controls the automatic pruning of images after ToolHive builds or pulls an image
+type PrunePolicy struct {
+	// Enabled turns on automatic pruning
+	Enabled bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	// Unused prunes all the unused images, instead of only the untagged images
+	Unused bool `yaml:"unused,omitempty" json:"unused,omitempty"`
+	// MaxAgeDays only prunes the images built or pulled more than this number of days ago
+	MaxAgeDays int `yaml:"max_age_days,omitempty" json:"max_age_days,omitempty"`
+}
+
+// Options returns the prune options of the policy
+func (p PrunePolicy) Options(referenced []string) PruneOptions {
+	return PruneOptions{
+		Unused:     p.Unused,
+		OlderThan:  time.Duration(p.MaxAgeDays) * 24 * time.Hour,
+		Referenced: referenced,
+	}
+}
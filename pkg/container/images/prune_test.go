@@ -0,0 +1,254 @@
+package images
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/labels"
+)
+
+// fakeImageLister is an image manager with local images
+type fakeImageLister struct {
+	NoopImageManager
+	images []Image
+	// inUse are the IDs of the images used by containers, which cannot be removed
+	inUse []string
+}
+
+func (f *fakeImageLister) ListImages(_ context.Context) ([]Image, error) {
+	return slices.Clone(f.images), nil
+}
+
+func (f *fakeImageLister) RemoveImage(_ context.Context, id string) error {
+	if slices.Contains(f.inUse, id) {
+		return errors.New("image is used by a container")
+	}
+	for i, image := range f.images {
+		if image.ID == id {
+			f.images = slices.Delete(f.images, i, i+1)
+			return nil
+		}
+	}
+	return errors.New("no such image")
+}
+
+func (f *fakeImageLister) image(id string) *Image {
+	for i := range f.images {
+		if f.images[i].ID == id {
+			return &f.images[i]
+		}
+	}
+	return nil
+}
+
+func (f *fakeImageLister) ids() []string {
+	var ids []string
+	for _, image := range f.images {
+		ids = append(ids, image.ID)
+	}
+	return ids
+}
+
+func builtImage(id string, created time.Time, tags ...string) Image {
+	return Image{
+		ID:      id,
+		Tags:    tags,
+		Created: created,
+		Labels:  map[string]string{labels.LabelImage: labels.LabelToolHiveValue},
+	}
+}
+
+// setStateHome locates the state store in a temporary directory
+func setStateHome(t *testing.T) {
+	t.Helper()
+	t.Cleanup(xdg.Reload)
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	xdg.Reload()
+}
+
+func TestImageIsReferenced(t *testing.T) {
+	t.Parallel()
+
+	image := Image{
+		ID:      "sha256:0123456789abcdef",
+		Tags:    []string{"ghcr.io/stacklok/fetch:1.0", "fetch:latest"},
+		Digests: []string{"ghcr.io/stacklok/fetch@sha256:" + "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"},
+	}
+
+	tests := []struct {
+		name string
+		ref  string
+		want bool
+	}{
+		{name: "tag", ref: "ghcr.io/stacklok/fetch:1.0", want: true},
+		{name: "canonical tag", ref: "index.docker.io/library/fetch:latest", want: true},
+		{name: "implicit latest tag", ref: "fetch", want: true},
+		{name: "digest", ref: "ghcr.io/stacklok/fetch@sha256:" +
+			"a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90", want: true},
+		{name: "ID", ref: "sha256:0123456789abcdef", want: true},
+		{name: "ID without algorithm", ref: "0123456789abcdef", want: true},
+		{name: "other tag", ref: "ghcr.io/stacklok/fetch:2.0"},
+		{name: "other repository", ref: "ghcr.io/other/fetch:1.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, image.IsReferenced([]string{tt.ref}))
+		})
+	}
+}
+
+func TestPruneOptionsSelects(t *testing.T) {
+	t.Parallel()
+
+	old := time.Now().Add(-48 * time.Hour)
+	tests := []struct {
+		name  string
+		opts  PruneOptions
+		image ManagedImage
+		want  bool
+	}{
+		{
+			name:  "untagged image",
+			image: ManagedImage{Image: Image{ID: "sha256:a"}, Since: old},
+			want:  true,
+		},
+		{
+			name:  "tagged image",
+			image: ManagedImage{Image: Image{ID: "sha256:a", Tags: []string{"fetch:1.0"}}, Since: old},
+		},
+		{
+			name:  "unused tagged image",
+			opts:  PruneOptions{Unused: true},
+			image: ManagedImage{Image: Image{ID: "sha256:a", Tags: []string{"fetch:1.0"}}, Since: old},
+			want:  true,
+		},
+		{
+			name:  "referenced image",
+			opts:  PruneOptions{Unused: true, Referenced: []string{"fetch:1.0"}},
+			image: ManagedImage{Image: Image{ID: "sha256:a", Tags: []string{"fetch:1.0"}}, Since: old},
+		},
+		{
+			name:  "image older than the age",
+			opts:  PruneOptions{OlderThan: 24 * time.Hour},
+			image: ManagedImage{Image: Image{ID: "sha256:a"}, Since: old},
+			want:  true,
+		},
+		{
+			name:  "image newer than the age",
+			opts:  PruneOptions{OlderThan: 72 * time.Hour},
+			image: ManagedImage{Image: Image{ID: "sha256:a"}, Since: old},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.opts.selects(tt.image))
+		})
+	}
+}
+
+func TestListManagedImages(t *testing.T) { //nolint:paralleltest // the state store uses environment variables
+	setStateHome(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	lister := &fakeImageLister{images: []Image{
+		builtImage("sha256:built", now.Add(-time.Hour), "npx-fetch:latest"),
+		{ID: "sha256:pulled", Tags: []string{"ghcr.io/stacklok/fetch:1.0"}, Created: now.Add(-24 * time.Hour)},
+		{ID: "sha256:other", Tags: []string{"alpine:3"}, Created: now},
+	}}
+	recordPull(ctx, lister, "ghcr.io/stacklok/fetch:1.0")
+
+	managed, err := ListManagedImages(ctx, lister)
+	require.NoError(t, err)
+	require.Len(t, managed, 2)
+	// The pulled image was pulled after the other image was built
+	assert.Equal(t, "sha256:pulled", managed[0].ID)
+	assert.Equal(t, OriginPulled, managed[0].Origin)
+	assert.WithinDuration(t, now, managed[0].Since, time.Minute)
+	assert.Equal(t, "sha256:built", managed[1].ID)
+	assert.Equal(t, OriginBuilt, managed[1].Origin)
+
+	// The record of a pulled image is removed with the image
+	lister.images = lister.images[:1]
+	_, err = ListManagedImages(ctx, lister)
+	require.NoError(t, err)
+	lister.images = append(lister.images, Image{ID: "sha256:pulled", Tags: []string{"ghcr.io/stacklok/fetch:1.0"}})
+	managed, err = ListManagedImages(ctx, lister)
+	require.NoError(t, err)
+	require.Len(t, managed, 1)
+	assert.Equal(t, "sha256:built", managed[0].ID)
+}
+
+func TestPrune(t *testing.T) { //nolint:paralleltest // the state store uses environment variables
+	setStateHome(t)
+	ctx := context.Background()
+
+	old := time.Now().Add(-72 * time.Hour)
+	newImages := func() *fakeImageLister {
+		return &fakeImageLister{images: []Image{
+			builtImage("sha256:replaced", old),
+			builtImage("sha256:running", old),
+			builtImage("sha256:used", old, "npx-fetch:latest"),
+			builtImage("sha256:unused", old, "uvx-time:latest", "uvx-time:1.0"),
+			builtImage("sha256:recent", time.Now(), "go-github:latest"),
+			builtImage("sha256:stopped", old, "uvx-fetch:latest", "uvx-fetch:1.0"),
+			{ID: "sha256:other", Created: old},
+		}, inUse: []string{"sha256:running", "sha256:stopped"}}
+	}
+	referenced := []string{"npx-fetch"}
+
+	t.Run("untagged images", func(t *testing.T) { //nolint:paralleltest // shares the state store
+		lister := newImages()
+		removed, err := Prune(ctx, lister, PruneOptions{Referenced: referenced})
+		require.NoError(t, err)
+		require.Len(t, removed, 1)
+		assert.Equal(t, "sha256:replaced", removed[0].ID)
+		assert.NotContains(t, lister.ids(), "sha256:replaced")
+		assert.Contains(t, lister.ids(), "sha256:running")
+	})
+
+	t.Run("unused images older than a day", func(t *testing.T) { //nolint:paralleltest // shares the state store
+		lister := newImages()
+		removed, err := Prune(ctx, lister, PruneOptions{Unused: true, OlderThan: 24 * time.Hour, Referenced: referenced})
+		require.NoError(t, err)
+		assert.Len(t, removed, 2)
+		assert.ElementsMatch(t,
+			[]string{"sha256:running", "sha256:used", "sha256:recent", "sha256:stopped", "sha256:other"}, lister.ids())
+
+		// An image that cannot be removed keeps all its tags
+		assert.Equal(t, []string{"uvx-fetch:latest", "uvx-fetch:1.0"}, lister.image("sha256:stopped").Tags)
+	})
+
+	t.Run("pulled images", func(t *testing.T) { //nolint:paralleltest // shares the state store
+		lister := &fakeImageLister{images: []Image{{ID: "sha256:pulled", Tags: []string{"fetch:1.0"}}}}
+		recordPull(ctx, lister, "fetch:1.0")
+
+		removed, err := Prune(ctx, lister, PruneOptions{Unused: true})
+		require.NoError(t, err)
+		require.Len(t, removed, 1)
+		assert.Equal(t, OriginPulled, removed[0].Origin)
+		assert.Empty(t, lister.ids())
+
+		// The record of the pulled image was removed, so a new image with the ID is not managed
+		lister.images = []Image{{ID: "sha256:pulled"}}
+		managed, err := ListManagedImages(ctx, lister)
+		require.NoError(t, err)
+		assert.Empty(t, managed)
+	})
+}
+
+func TestPrunePolicyOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := PrunePolicy{Enabled: true, Unused: true, MaxAgeDays: 7}.Options([]string{"fetch"})
+	assert.Equal(t, PruneOptions{Unused: true, OlderThan: 7 * 24 * time.Hour, Referenced: []string{"fetch"}}, opts)
+}
@@ -106,12 +106,13 @@ func (r *RegistryImageManager) PullImage(ctx context.Context, imageName string)
 	fmt.Fprintf(os.Stdout, "Successfully pulled %s\n", imageName)
 	logger.Infof("Pull complete for image: %s, response: %s", imageName, response)
 
+	recordPull(ctx, r, imageName)
 	return nil
 }
 
 // BuildImage builds a Docker image from a Dockerfile in the specified context directory
 func (r *RegistryImageManager) BuildImage(ctx context.Context, contextDir, imageName string) error {
-	return buildDockerImage(ctx, r.dockerClient, contextDir, imageName)
+	return buildDockerImage(ctx, r.dockerClient, contextDir, imageName, nil)
 }
 
 // WithKeychain sets the keychain for authentication
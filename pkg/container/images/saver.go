@@ -0,0 +1,59 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/client"
+)
+
+// ImageSaver is implemented by image managers that can export local images,
+// for example to catalog the packages installed in them.
+type ImageSaver interface {
+	// SaveImage writes a local image to w as a tarball in the format of docker save
+	SaveImage(ctx context.Context, imageName string, w io.Writer) error
+}
+
+// SaveImage writes an image of the Docker daemon to w
+func (d *DockerImageManager) SaveImage(ctx context.Context, imageName string, w io.Writer) error {
+	return saveDaemonImage(ctx, d.client, imageName, w)
+}
+
+// SaveImage writes an image of the Docker daemon to w
+func (r *RegistryImageManager) SaveImage(ctx context.Context, imageName string, w io.Writer) error {
+	return saveDaemonImage(ctx, r.dockerClient, imageName, w)
+}
+
+// saveDaemonImage writes an image of the Docker daemon to w
+func saveDaemonImage(ctx context.Context, dockerClient *client.Client, imageName string, w io.Writer) error {
+	reader, err := dockerClient.ImageSave(ctx, []string{imageName})
+	if err != nil {
+		return fmt.Errorf("failed to save image %s: %w", imageName, err)
+	}
+	defer reader.Close()
+
+	if _, err := io.Copy(w, reader); err != nil {
+		return fmt.Errorf("failed to save image %s: %w", imageName, err)
+	}
+	return nil
+}
+
+// SaveImage writes an image of the containerd content store to w
+func (n *NerdctlImageManager) SaveImage(ctx context.Context, imageName string, w io.Writer) error {
+	return n.saveImage(ctx, imageName, w)
+}
+
+// saveImage runs nerdctl save with additional flags
+func (n *NerdctlImageManager) saveImage(ctx context.Context, imageName string, w io.Writer, flags ...string) error {
+	var stderr bytes.Buffer
+	cmd := n.cli.Command(ctx, append(append([]string{"save"}, flags...), imageName)...)
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to save image %s: %w: %s", imageName, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -13,9 +13,12 @@ import (
 	"time"
 
 	"github.com/cenkalti/backoff/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	apimwatch "k8s.io/apimachinery/pkg/watch"
@@ -32,6 +35,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/container/runtime"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/permissions"
+	"github.com/stacklok/toolhive/pkg/telemetry"
 	transtypes "github.com/stacklok/toolhive/pkg/transport/types"
 )
 
@@ -48,6 +52,9 @@ const (
 // RuntimeName is the name identifier for the Kubernetes runtime
 const RuntimeName = "kubernetes"
 
+// tracerName is the name of the tracer of Kubernetes runtime operations
+const tracerName = "github.com/stacklok/toolhive/pkg/container/kubernetes"
+
 // Client implements the Deployer interface for container operations
 type Client struct {
 	runtimeType      runtime.Type
@@ -282,12 +289,21 @@ func (c *Client) DeployWorkload(ctx context.Context,
 	command []string,
 	envVars map[string]string,
 	containerLabels map[string]string,
-	_ *permissions.Profile, // TODO: Implement permission profile support for Kubernetes
+	permissionProfile *permissions.Profile, // TODO: Implement the remaining permission profile support for Kubernetes
 	transportType string,
 	options *runtime.DeployWorkloadOptions,
-	_ bool,
-) (int, error) {
+	isolateNetwork bool,
+) (_ int, retErr error) {
+	ctx, span := telemetry.StartSpan(ctx, tracerName, "kubernetes.DeployWorkload",
+		attribute.String("workload.name", containerName),
+		attribute.String("workload.image", image),
+	)
+	defer func() {
+		telemetry.EndSpan(span, retErr)
+	}()
+
 	namespace := c.getCurrentNamespace()
+	span.SetAttributes(attribute.String("k8s.namespace.name", namespace))
 	containerLabels["app"] = containerName
 	containerLabels["toolhive"] = "true"
 
@@ -342,6 +358,48 @@ func (c *Client) DeployWorkload(ctx context.Context,
 		return 0, err
 	}
 
+	// Apply the resource limits and security settings of the permission profile
+	if permissionProfile != nil {
+		if err := configureResourceLimits(podTemplateSpec, permissionProfile.Resources); err != nil {
+			return 0, fmt.Errorf("failed to configure resource limits: %w", err)
+		}
+		if err := configureSecuritySettings(podTemplateSpec, permissionProfile.Security, platform); err != nil {
+			return 0, fmt.Errorf("failed to configure security settings: %w", err)
+		}
+	}
+
+	// Mount the secret files from a Secret, which must exist before the pods are created
+	if options != nil && len(options.SecretFiles) > 0 {
+		if err := c.applySecretFiles(ctx, containerName, namespace, containerLabels, options.SecretFiles); err != nil {
+			return 0, fmt.Errorf("failed to create secret files: %w", err)
+		}
+		if err := configureSecretFiles(podTemplateSpec, containerName, options.SecretFiles); err != nil {
+			return 0, fmt.Errorf("failed to configure secret files: %w", err)
+		}
+	}
+
+	// Enforce the network permissions, including host name rules, with an egress proxy sidecar
+	if isolateNetwork {
+		var network *permissions.NetworkPermissions
+		if permissionProfile != nil {
+			network = permissionProfile.Network
+		}
+		config, err := c.applyEgressProxyConfig(ctx, containerName, namespace, containerLabels, network)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create egress proxy configuration: %w", err)
+		}
+		if err := configureEgressProxy(podTemplateSpec, containerName, config, platform); err != nil {
+			return 0, fmt.Errorf("failed to configure egress proxy: %w", err)
+		}
+	}
+
+	// Run the services as sidecars which start before the MCP container
+	if options != nil && len(options.Services) > 0 {
+		if err := configureServices(podTemplateSpec, options.Services, isolateNetwork, platform); err != nil {
+			return 0, fmt.Errorf("failed to configure services: %w", err)
+		}
+	}
+
 	// Create an apply configuration for the statefulset
 	statefulSetApply := appsv1apply.StatefulSet(containerName, namespace).
 		WithLabels(containerLabels).
@@ -380,7 +438,10 @@ func (c *Client) DeployWorkload(ctx context.Context,
 	if c.waitForStatefulSetReadyFunc != nil {
 		waitFunc = c.waitForStatefulSetReadyFunc
 	}
-	err = waitFunc(ctx, c.client, namespace, createdStatefulSet.Name)
+	waitCtx, waitSpan := telemetry.StartSpan(ctx, tracerName, "kubernetes.WaitForStatefulSetReady",
+		attribute.String("k8s.statefulset.name", createdStatefulSet.Name))
+	err = waitFunc(waitCtx, c.client, namespace, createdStatefulSet.Name)
+	telemetry.EndSpan(waitSpan, err)
 	if err != nil {
 		return 0, fmt.Errorf("statefulset applied but failed to become ready: %w", err)
 	}
@@ -560,6 +621,13 @@ func (c *Client) RemoveWorkload(ctx context.Context, workloadName string) error
 	}
 
 	logger.Infof("Deleted statefulset %s", workloadName)
+
+	if err := c.removeSecretFiles(ctx, workloadName, namespace); err != nil {
+		logger.Warnf("Failed to remove secret files of %s: %v", workloadName, err)
+	}
+	if err := c.removeEgressProxyConfig(ctx, workloadName, namespace); err != nil {
+		logger.Warnf("Failed to remove egress proxy configuration of %s: %v", workloadName, err)
+	}
 	return nil
 }
 
@@ -610,6 +678,10 @@ func waitForStatefulSetReady(ctx context.Context, clientset kubernetes.Interface
 
 		logger.Infof("Waiting for statefulset %s to be ready (%d/%d replicas ready)...",
 			name, statefulSet.Status.ReadyReplicas, *statefulSet.Spec.Replicas)
+		trace.SpanFromContext(ctx).AddEvent("statefulset not ready", trace.WithAttributes(
+			attribute.Int("k8s.statefulset.ready_replicas", int(statefulSet.Status.ReadyReplicas)),
+			attribute.Int("k8s.statefulset.replicas", int(*statefulSet.Spec.Replicas)),
+		))
 		return false, nil
 	}
 
@@ -1221,6 +1293,159 @@ func configureMCPContainer(
 	return nil
 }
 
+// configureSecuritySettings applies the security settings of the permission profile to the pod
+// template. The settings of the profile take precedence over those of the pod template patch,
+// and tmpfs paths are mounted as memory-backed emptyDir volumes.
+func configureSecuritySettings(
+	podTemplateSpec *corev1apply.PodTemplateSpecApplyConfiguration,
+	settings *permissions.SecuritySettings,
+	platform Platform,
+) error {
+	if settings == nil {
+		return nil
+	}
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	mcpContainer := getMCPContainer(podTemplateSpec)
+	if mcpContainer == nil {
+		return fmt.Errorf("mcp container not found in pod template")
+	}
+
+	securityBuilder := NewSecurityContextBuilder(platform).WithSecuritySettings(settings)
+	desired := securityBuilder.BuildContainerSecurityContextApplyConfiguration()
+	if mcpContainer.SecurityContext == nil {
+		mcpContainer.WithSecurityContext(corev1apply.SecurityContext())
+	}
+	securityContext := mcpContainer.SecurityContext
+
+	if settings.User != "" {
+		securityContext.RunAsNonRoot = desired.RunAsNonRoot
+		securityContext.RunAsUser = desired.RunAsUser
+		securityContext.RunAsGroup = desired.RunAsGroup
+
+		podContext := securityBuilder.BuildPodSecurityContextApplyConfiguration()
+		if podTemplateSpec.Spec.SecurityContext == nil {
+			podTemplateSpec.Spec.WithSecurityContext(corev1apply.PodSecurityContext())
+		}
+		podTemplateSpec.Spec.SecurityContext.RunAsNonRoot = podContext.RunAsNonRoot
+		podTemplateSpec.Spec.SecurityContext.RunAsUser = podContext.RunAsUser
+		podTemplateSpec.Spec.SecurityContext.RunAsGroup = podContext.RunAsGroup
+		podTemplateSpec.Spec.SecurityContext.FSGroup = podContext.FSGroup
+	}
+	if len(settings.Capabilities) > 0 {
+		securityContext.Capabilities = desired.Capabilities
+	}
+	if settings.Seccomp != "" {
+		securityContext.SeccompProfile = desired.SeccompProfile
+	}
+	if settings.AppArmor != "" {
+		securityContext.AppArmorProfile = desired.AppArmorProfile
+	}
+	if settings.ReadOnlyRootFilesystem {
+		securityContext.ReadOnlyRootFilesystem = desired.ReadOnlyRootFilesystem
+	}
+	if settings.NoNewPrivileges {
+		securityContext.AllowPrivilegeEscalation = desired.AllowPrivilegeEscalation
+	}
+
+	for i, target := range settings.Tmpfs {
+		volumeName := fmt.Sprintf("toolhive-tmpfs-%d", i)
+		podTemplateSpec.Spec.WithVolumes(corev1apply.Volume().
+			WithName(volumeName).
+			WithEmptyDir(corev1apply.EmptyDirVolumeSource().WithMedium(corev1.StorageMediumMemory)))
+		mcpContainer.WithVolumeMounts(corev1apply.VolumeMount().
+			WithName(volumeName).
+			WithMountPath(target))
+	}
+
+	return nil
+}
+
+// Default resource requests of the MCP container when the profile sets limits. Kubernetes would
+// otherwise default the requests to the limits, so every pod would reserve its full limits, while
+// Docker reserves nothing.
+var (
+	defaultCPURequest    = resource.MustParse("100m")
+	defaultMemoryRequest = resource.MustParse("128Mi")
+)
+
+// configureResourceLimits sets the CPU and memory limits of the MCP container, and requests
+// of at most the default requests. Limits and requests set by the pod template patch are kept
+// unless the profile overrides them. Kubernetes has no per-container pids limits or ulimits,
+// so these are configured on the nodes instead.
+func configureResourceLimits(
+	podTemplateSpec *corev1apply.PodTemplateSpecApplyConfiguration,
+	limits *permissions.ResourceLimits,
+) error {
+	if limits.IsEmpty() {
+		return nil
+	}
+	if limits.PidsLimit > 0 || len(limits.Ulimits) > 0 {
+		logger.Warnf("Pids limits and ulimits are not supported by the Kubernetes runtime and will be ignored")
+	}
+
+	milliCPUs, err := limits.MilliCPUs()
+	if err != nil {
+		return err
+	}
+	memory, err := limits.MemoryBytes()
+	if err != nil {
+		return err
+	}
+
+	resourceList := corev1.ResourceList{}
+	if milliCPUs > 0 {
+		resourceList[corev1.ResourceCPU] = *resource.NewMilliQuantity(milliCPUs, resource.DecimalSI)
+	}
+	if memory > 0 {
+		resourceList[corev1.ResourceMemory] = *resource.NewQuantity(memory, resource.BinarySI)
+	}
+	if len(resourceList) == 0 {
+		return nil
+	}
+
+	mcpContainer := getMCPContainer(podTemplateSpec)
+	if mcpContainer == nil {
+		return fmt.Errorf("mcp container not found in pod template")
+	}
+	if mcpContainer.Resources == nil {
+		mcpContainer.WithResources(corev1apply.ResourceRequirements())
+	}
+	if mcpContainer.Resources.Limits != nil {
+		for name, quantity := range *mcpContainer.Resources.Limits {
+			if _, ok := resourceList[name]; !ok {
+				resourceList[name] = quantity
+			}
+		}
+	}
+	mcpContainer.Resources.WithLimits(resourceList)
+
+	requests := corev1.ResourceList{}
+	if mcpContainer.Resources.Requests != nil {
+		requests = *mcpContainer.Resources.Requests
+	}
+	for name, defaultRequest := range map[corev1.ResourceName]resource.Quantity{
+		corev1.ResourceCPU:    defaultCPURequest,
+		corev1.ResourceMemory: defaultMemoryRequest,
+	} {
+		limit, ok := resourceList[name]
+		if _, requested := requests[name]; !ok || requested {
+			continue
+		}
+		if limit.Cmp(defaultRequest) < 0 {
+			defaultRequest = limit
+		}
+		requests[name] = defaultRequest
+	}
+	if len(requests) > 0 {
+		mcpContainer.Resources.WithRequests(requests)
+	}
+
+	return nil
+}
+
 // getCurrentNamespace returns the namespace the pod is running in.
 // It tries multiple methods in order:
 // 1. Reading from the service account token file (when running inside a pod)
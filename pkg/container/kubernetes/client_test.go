@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
 	"k8s.io/client-go/kubernetes"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/stacklok/toolhive/pkg/container/runtime"
 	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/permissions"
 )
 
 func init() {
@@ -616,6 +618,96 @@ func TestConfigureMCPContainer(t *testing.T) {
 	}
 }
 
+// TestConfigureResourceLimits tests the configureResourceLimits function
+func TestConfigureResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	podTemplateSpec := corev1apply.PodTemplateSpec().
+		WithSpec(corev1apply.PodSpec().
+			WithContainers(corev1apply.Container().WithName(mcpContainerName).
+				WithResources(corev1apply.ResourceRequirements().WithLimits(corev1.ResourceList{
+					corev1.ResourceCPU:              resource.MustParse("4"),
+					corev1.ResourceEphemeralStorage: resource.MustParse("1Gi"),
+				}))))
+
+	err := configureResourceLimits(podTemplateSpec, &permissions.ResourceLimits{
+		CPUs:      "0.5",
+		Memory:    "512m",
+		PidsLimit: 100,
+	})
+	require.NoError(t, err)
+
+	limits := *getMCPContainer(podTemplateSpec).Resources.Limits
+	assert.Equal(t, "500m", limits.Cpu().String())
+	assert.Equal(t, "512Mi", limits.Memory().String())
+	// Limits from the pod template patch which are not set by the profile are kept
+	assert.Equal(t, "1Gi", limits.StorageEphemeral().String())
+
+	// Requests are set below the limits, so that pods do not reserve their full limits
+	requests := *getMCPContainer(podTemplateSpec).Resources.Requests
+	assert.Equal(t, "100m", requests.Cpu().String())
+	assert.Equal(t, "128Mi", requests.Memory().String())
+
+	// Requests are capped by the limits, and requests of the pod template patch are kept
+	podTemplateSpec = corev1apply.PodTemplateSpec().
+		WithSpec(corev1apply.PodSpec().
+			WithContainers(corev1apply.Container().WithName(mcpContainerName).
+				WithResources(corev1apply.ResourceRequirements().WithRequests(corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("250m"),
+				}))))
+	require.NoError(t, configureResourceLimits(podTemplateSpec, &permissions.ResourceLimits{CPUs: "1", Memory: "64m"}))
+	requests = *getMCPContainer(podTemplateSpec).Resources.Requests
+	assert.Equal(t, "250m", requests.Cpu().String())
+	assert.Equal(t, "64Mi", requests.Memory().String())
+
+	// Without limits the pod template is left unchanged
+	podTemplateSpec = corev1apply.PodTemplateSpec().
+		WithSpec(corev1apply.PodSpec().WithContainers(corev1apply.Container().WithName(mcpContainerName)))
+	require.NoError(t, configureResourceLimits(podTemplateSpec, nil))
+	assert.Nil(t, getMCPContainer(podTemplateSpec).Resources)
+
+	err = configureResourceLimits(podTemplateSpec, &permissions.ResourceLimits{Memory: "lots"})
+	assert.Error(t, err)
+}
+
+// TestConfigureSecuritySettings tests the configureSecuritySettings function
+func TestConfigureSecuritySettings(t *testing.T) {
+	t.Parallel()
+
+	podTemplateSpec := ensurePodTemplateConfig(corev1apply.PodTemplateSpec(), map[string]string{}, PlatformKubernetes)
+	err := configureMCPContainer(podTemplateSpec, "image", nil, false, nil, "stdio", nil, PlatformKubernetes)
+	require.NoError(t, err)
+	// Settings of the pod template patch which are not set by the profile are kept
+	getMCPContainer(podTemplateSpec).SecurityContext.WithProcMount(corev1.DefaultProcMount)
+
+	err = configureSecuritySettings(podTemplateSpec, &permissions.SecuritySettings{
+		Capabilities: []string{"NET_BIND_SERVICE"},
+		Seccomp:      permissions.SeccompProfileRuntimeDefault,
+		Tmpfs:        []string{"/tmp", "/var/cache"},
+		User:         "2000",
+	}, PlatformKubernetes)
+	require.NoError(t, err)
+
+	mcpContainer := getMCPContainer(podTemplateSpec)
+	securityContext := mcpContainer.SecurityContext
+	assert.Equal(t, int64(2000), *securityContext.RunAsUser)
+	assert.Nil(t, securityContext.RunAsGroup)
+	assert.Equal(t, []corev1.Capability{"NET_BIND_SERVICE"}, securityContext.Capabilities.Add)
+	assert.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, *securityContext.SeccompProfile.Type)
+	assert.True(t, *securityContext.ReadOnlyRootFilesystem)
+	assert.Equal(t, corev1.DefaultProcMount, *securityContext.ProcMount)
+	assert.Equal(t, int64(2000), *podTemplateSpec.Spec.SecurityContext.RunAsUser)
+
+	require.Len(t, podTemplateSpec.Spec.Volumes, 2)
+	assert.Equal(t, corev1.StorageMediumMemory, *podTemplateSpec.Spec.Volumes[0].EmptyDir.Medium)
+	require.Len(t, mcpContainer.VolumeMounts, 2)
+	assert.Equal(t, "/tmp", *mcpContainer.VolumeMounts[0].MountPath)
+	assert.Equal(t, *podTemplateSpec.Spec.Volumes[1].Name, *mcpContainer.VolumeMounts[1].Name)
+
+	err = configureSecuritySettings(podTemplateSpec, &permissions.SecuritySettings{User: "root"}, PlatformKubernetes)
+	assert.Error(t, err)
+}
+
 // TestCreateContainerWithMCP tests the CreateContainer function with MCP container configuration
 func TestCreateContainerWithMCP(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,159 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
+
+	"github.com/stacklok/toolhive/pkg/container/squid"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+const (
+	// egressProxyContainerName is the name of the egress proxy sidecar container
+	egressProxyContainerName = "egress-proxy"
+	// egressProxyConfigVolumeName is the name of the volume the squid configuration is mounted from
+	egressProxyConfigVolumeName = "toolhive-egress-proxy-config"
+	// egressProxyRunVolumeName is the name of the volume squid writes its runtime files to,
+	// as the root filesystem of the sidecar is read-only
+	egressProxyRunVolumeName = "toolhive-egress-proxy-run"
+	// egressProxyConfigKey is the key of the squid configuration in the ConfigMap
+	egressProxyConfigKey = "squid.conf"
+	// egressProxyChecksumAnnotation is the pod template annotation holding a checksum of the squid
+	// configuration, so that the pods are recreated when the network permissions change
+	egressProxyChecksumAnnotation = "toolhive.stacklok.dev/egress-proxy-checksum"
+)
+
+// egressProxyConfigMapName returns the name of the ConfigMap holding the egress proxy configuration
+// of a workload.
+func egressProxyConfigMapName(workloadName string) string {
+	return fmt.Sprintf("%s-egress-proxy", workloadName)
+}
+
+// egressProxyChecksum returns a checksum of the egress proxy configuration.
+func egressProxyChecksum(config string) string {
+	hash := sha256.Sum256([]byte(config))
+	return hex.EncodeToString(hash[:])
+}
+
+// applyEgressProxyConfig creates or updates the ConfigMap holding the configuration of the egress
+// proxy of a workload, and returns the configuration.
+func (c *Client) applyEgressProxyConfig(
+	ctx context.Context,
+	workloadName string,
+	namespace string,
+	labels map[string]string,
+	network *permissions.NetworkPermissions,
+) (string, error) {
+	config, err := squid.EgressConfig(network, workloadName)
+	if err != nil {
+		return "", err
+	}
+
+	configMapApply := corev1apply.ConfigMap(egressProxyConfigMapName(workloadName), namespace).
+		WithLabels(labels).
+		WithData(map[string]string{egressProxyConfigKey: config})
+
+	// Apply the configmap using server-side apply
+	fieldManager := "toolhive-container-manager"
+	_, err = c.client.CoreV1().ConfigMaps(namespace).
+		Apply(ctx, configMapApply, metav1.ApplyOptions{
+			FieldManager: fieldManager,
+			Force:        true,
+		})
+	if err != nil {
+		return "", fmt.Errorf("failed to apply configmap: %v", err)
+	}
+
+	logger.Infof("Applied configmap %s for the egress proxy", egressProxyConfigMapName(workloadName))
+	return config, nil
+}
+
+// removeEgressProxyConfig deletes the ConfigMap holding the egress proxy configuration of a workload, if any.
+func (c *Client) removeEgressProxyConfig(ctx context.Context, workloadName string, namespace string) error {
+	configMapName := egressProxyConfigMapName(workloadName)
+	err := c.client.CoreV1().ConfigMaps(namespace).Delete(ctx, configMapName, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete configmap %s: %w", configMapName, err)
+	}
+	return nil
+}
+
+// egressProxyEnvVars returns the environment variables which route the HTTP traffic of a container
+// through the egress proxy sidecar
+func egressProxyEnvVars() []*corev1apply.EnvVarApplyConfiguration {
+	proxyURL := "http://localhost:" + strconv.Itoa(squid.EgressPort)
+	noProxy := "localhost,127.0.0.1,::1"
+	return []*corev1apply.EnvVarApplyConfiguration{
+		corev1apply.EnvVar().WithName("HTTP_PROXY").WithValue(proxyURL),
+		corev1apply.EnvVar().WithName("HTTPS_PROXY").WithValue(proxyURL),
+		corev1apply.EnvVar().WithName("http_proxy").WithValue(proxyURL),
+		corev1apply.EnvVar().WithName("https_proxy").WithValue(proxyURL),
+		corev1apply.EnvVar().WithName("NO_PROXY").WithValue(noProxy),
+		corev1apply.EnvVar().WithName("no_proxy").WithValue(noProxy),
+	}
+}
+
+// configureEgressProxy adds the egress proxy sidecar to the pod template and routes the HTTP traffic
+// of the MCP container through it. The sidecar shares the network namespace of the pod, so it
+// enforces the host name rules of the network permissions which a NetworkPolicy cannot express.
+func configureEgressProxy(
+	podTemplateSpec *corev1apply.PodTemplateSpecApplyConfiguration,
+	workloadName string,
+	config string,
+	platform Platform,
+) error {
+	mcpContainer := getMCPContainer(podTemplateSpec)
+	if mcpContainer == nil {
+		return fmt.Errorf("mcp container not found in pod template")
+	}
+
+	mcpContainer.WithEnv(egressProxyEnvVars()...)
+
+	securityBuilder := NewSecurityContextBuilder(platform)
+	podTemplateSpec.Spec.WithContainers(corev1apply.Container().
+		WithName(egressProxyContainerName).
+		WithImage(squid.Image()).
+		WithPorts(corev1apply.ContainerPort().
+			WithName("egress-proxy").
+			WithContainerPort(squid.EgressPort)).
+		WithSecurityContext(securityBuilder.BuildContainerSecurityContextApplyConfiguration()).
+		WithVolumeMounts(
+			corev1apply.VolumeMount().
+				WithName(egressProxyConfigVolumeName).
+				WithMountPath("/etc/squid/squid.conf").
+				WithSubPath(egressProxyConfigKey).
+				WithReadOnly(true),
+			corev1apply.VolumeMount().
+				WithName(egressProxyRunVolumeName).
+				WithMountPath("/var/log/squid"),
+			corev1apply.VolumeMount().
+				WithName(egressProxyRunVolumeName).
+				WithMountPath("/tmp"),
+		))
+
+	podTemplateSpec.Spec.WithVolumes(
+		corev1apply.Volume().
+			WithName(egressProxyConfigVolumeName).
+			WithConfigMap(corev1apply.ConfigMapVolumeSource().
+				WithName(egressProxyConfigMapName(workloadName))),
+		corev1apply.Volume().
+			WithName(egressProxyRunVolumeName).
+			WithEmptyDir(corev1apply.EmptyDirVolumeSource()),
+	)
+
+	// Files mounted with a subPath are not updated when the ConfigMap changes,
+	// so the pods are recreated instead
+	podTemplateSpec.WithAnnotations(map[string]string{
+		egressProxyChecksumAnnotation: egressProxyChecksum(config),
+	})
+
+	return nil
+}
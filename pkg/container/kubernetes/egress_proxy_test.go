@@ -0,0 +1,143 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/container/squid"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+func TestDeployWorkloadWithEgressProxy(t *testing.T) {
+	t.Parallel()
+
+	mockStatefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-container",
+			Namespace: defaultNamespace,
+		},
+		Status: appsv1.StatefulSetStatus{
+			ReadyReplicas: 1,
+		},
+	}
+	// The simple clientset does not support creating objects with server-side apply
+	clientset := fake.NewClientset(mockStatefulSet)
+	client := NewClientWithConfigAndPlatformDetector(
+		clientset,
+		&rest.Config{Host: "https://fake-k8s-api.example.com"},
+		&mockPlatformDetector{platform: PlatformKubernetes},
+	)
+	client.waitForStatefulSetReadyFunc = mockWaitForStatefulSetReady
+	client.namespaceFunc = func() string { return defaultNamespace }
+
+	network := &permissions.NetworkPermissions{
+		Outbound: &permissions.OutboundNetworkPermissions{
+			AllowHost: []string{"api.github.com"},
+			AllowPort: []int{443},
+		},
+	}
+
+	ctx := context.Background()
+	_, err := client.DeployWorkload(
+		ctx,
+		"ghcr.io/example/mcp:latest",
+		"test-container",
+		nil,
+		map[string]string{},
+		map[string]string{},
+		&permissions.Profile{Network: network},
+		"stdio",
+		runtime.NewDeployWorkloadOptions(),
+		true,
+	)
+	require.NoError(t, err)
+
+	// The squid configuration is stored in a ConfigMap
+	expectedConfig, err := squid.EgressConfig(network, "test-container")
+	require.NoError(t, err)
+	configMap, err := clientset.CoreV1().ConfigMaps(defaultNamespace).Get(ctx, "test-container-egress-proxy", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, expectedConfig, configMap.Data[egressProxyConfigKey])
+
+	statefulSet, err := clientset.AppsV1().StatefulSets(defaultNamespace).Get(ctx, "test-container", metav1.GetOptions{})
+	require.NoError(t, err)
+	podTemplate := statefulSet.Spec.Template
+	assert.Equal(t, egressProxyChecksum(expectedConfig), podTemplate.Annotations[egressProxyChecksumAnnotation])
+
+	// The MCP container uses the sidecar as its HTTP proxy
+	require.Len(t, podTemplate.Spec.Containers, 2)
+	mcpContainer := podTemplate.Spec.Containers[0]
+	assert.Equal(t, "mcp", mcpContainer.Name)
+	assert.Contains(t, mcpContainer.Env, corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://localhost:3128"})
+	assert.Contains(t, mcpContainer.Env, corev1.EnvVar{Name: "no_proxy", Value: "localhost,127.0.0.1,::1"})
+
+	sidecar := podTemplate.Spec.Containers[1]
+	assert.Equal(t, egressProxyContainerName, sidecar.Name)
+	assert.Equal(t, squid.Image(), sidecar.Image)
+	assert.Contains(t, sidecar.VolumeMounts, corev1.VolumeMount{
+		Name:      egressProxyConfigVolumeName,
+		MountPath: "/etc/squid/squid.conf",
+		SubPath:   egressProxyConfigKey,
+		ReadOnly:  true,
+	})
+	require.NotNil(t, sidecar.SecurityContext)
+	assert.True(t, *sidecar.SecurityContext.ReadOnlyRootFilesystem)
+
+	// Removing the workload removes the ConfigMap
+	require.NoError(t, client.RemoveWorkload(ctx, "test-container"))
+	_, err = clientset.CoreV1().ConfigMaps(defaultNamespace).Get(ctx, "test-container-egress-proxy", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestDeployWorkloadWithoutEgressProxy(t *testing.T) {
+	t.Parallel()
+
+	mockStatefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-container",
+			Namespace: defaultNamespace,
+		},
+		Status: appsv1.StatefulSetStatus{
+			ReadyReplicas: 1,
+		},
+	}
+	clientset := fake.NewClientset(mockStatefulSet)
+	client := NewClientWithConfigAndPlatformDetector(
+		clientset,
+		&rest.Config{Host: "https://fake-k8s-api.example.com"},
+		&mockPlatformDetector{platform: PlatformKubernetes},
+	)
+	client.waitForStatefulSetReadyFunc = mockWaitForStatefulSetReady
+	client.namespaceFunc = func() string { return defaultNamespace }
+
+	ctx := context.Background()
+	_, err := client.DeployWorkload(
+		ctx,
+		"ghcr.io/example/mcp:latest",
+		"test-container",
+		nil,
+		map[string]string{},
+		map[string]string{},
+		nil,
+		"stdio",
+		runtime.NewDeployWorkloadOptions(),
+		false,
+	)
+	require.NoError(t, err)
+
+	statefulSet, err := clientset.AppsV1().StatefulSets(defaultNamespace).Get(ctx, "test-container", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Len(t, statefulSet.Spec.Template.Spec.Containers, 1)
+	_, err = clientset.CoreV1().ConfigMaps(defaultNamespace).Get(ctx, "test-container-egress-proxy", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+}
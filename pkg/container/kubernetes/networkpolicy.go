@@ -0,0 +1,238 @@
+package kubernetes
+
+import (
+	"fmt"
+	"net"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+// anyIPs are the CIDRs matching all destinations
+var anyIPs = []string{"0.0.0.0/0", "::/0"}
+
+// NetworkPolicyName returns the name of the NetworkPolicy of a workload.
+func NetworkPolicyName(workloadName string) string {
+	return fmt.Sprintf("%s-network-policy", workloadName)
+}
+
+// NetworkPolicyForWorkload returns a NetworkPolicy which enforces the network permissions on the
+// pods of a workload. All traffic which is not allowed is denied, except for DNS lookups and
+// traffic from pods in the same namespace, such as the proxy of the workload.
+//
+// NetworkPolicies cannot match host names. If the workload has an egress proxy, the traffic to
+// all addresses on the ports of the host name rules is allowed and the proxy checks the host
+// names; as the proxy shares the network of the workload, the workload can bypass it, so the host
+// name rules are only advisory. Without an egress proxy, the connections to hosts which are only
+// allowed by name are denied; see HostNameRules.
+func NetworkPolicyForWorkload(
+	workloadName string,
+	namespace string,
+	network *permissions.NetworkPermissions,
+	egressProxy bool,
+) (*networkingv1.NetworkPolicy, error) {
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      NetworkPolicyName(workloadName),
+			Namespace: namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": workloadName},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		},
+	}
+
+	var inbound *permissions.InboundNetworkPermissions
+	var outbound *permissions.OutboundNetworkPermissions
+	if network != nil {
+		inbound = network.Inbound
+		outbound = network.Outbound
+	}
+	if err := outbound.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid outbound network permissions: %w", err)
+	}
+
+	policy.Spec.Ingress = ingressRules(inbound)
+
+	// Without network permissions all outbound traffic is allowed, as with the egress proxy
+	if network == nil {
+		policy.Spec.Egress = []networkingv1.NetworkPolicyEgressRule{{}}
+		return policy, nil
+	}
+	egress, err := egressRules(outbound, egressProxy)
+	if err != nil {
+		return nil, err
+	}
+	policy.Spec.Egress = egress
+	return policy, nil
+}
+
+// HostNameRules returns the outbound rules which match host names, and so can only be
+// enforced by an egress proxy.
+func HostNameRules(network *permissions.NetworkPermissions) []string {
+	if network == nil || network.Outbound == nil || network.Outbound.InsecureAllowAll {
+		return nil
+	}
+
+	rules := append([]string{}, network.Outbound.AllowHost...)
+	for _, hostPort := range network.Outbound.AllowHostPort {
+		host, _, err := permissions.ParseHostPort(hostPort)
+		if err == nil && net.ParseIP(host) == nil {
+			rules = append(rules, hostPort)
+		}
+	}
+	return append(rules, network.Outbound.DenyHost...)
+}
+
+// ingressRules allows traffic from the pods in the namespace and from the inbound hosts
+// which are IP addresses or CIDRs. Other host names cannot be matched.
+func ingressRules(inbound *permissions.InboundNetworkPermissions) []networkingv1.NetworkPolicyIngressRule {
+	peers := []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{}}}
+	if inbound != nil {
+		for _, host := range inbound.AllowHost {
+			if cidr, ok := hostCIDR(host); ok {
+				peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+			}
+		}
+	}
+	return []networkingv1.NetworkPolicyIngressRule{{From: peers}}
+}
+
+// egressRules allows DNS lookups and the outbound traffic allowed by the permissions
+func egressRules(
+	outbound *permissions.OutboundNetworkPermissions,
+	egressProxy bool,
+) ([]networkingv1.NetworkPolicyEgressRule, error) {
+	rules := []networkingv1.NetworkPolicyEgressRule{{
+		Ports: []networkingv1.NetworkPolicyPort{
+			policyPort(corev1.ProtocolUDP, 53),
+			policyPort(corev1.ProtocolTCP, 53),
+		},
+	}}
+	if outbound == nil {
+		return rules, nil
+	}
+
+	var denied []*net.IPNet
+	for _, cidr := range outbound.DenyCIDR {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+		denied = append(denied, ipNet)
+	}
+
+	var allowedPorts []networkingv1.NetworkPolicyPort
+	for _, port := range outbound.AllowPort {
+		allowedPorts = append(allowedPorts, policyPort(corev1.ProtocolTCP, port))
+	}
+
+	// A rule without peers would allow all destinations, so rules whose destinations
+	// are all denied are left out
+	allow := func(cidrs []string, ports []networkingv1.NetworkPolicyPort) {
+		if peers := ipBlockPeers(cidrs, denied); len(peers) > 0 {
+			rules = append(rules, networkingv1.NetworkPolicyEgressRule{To: peers, Ports: ports})
+		}
+	}
+
+	if outbound.InsecureAllowAll {
+		allow(anyIPs, nil)
+		return rules, nil
+	}
+
+	if len(outbound.AllowCIDR) > 0 {
+		allow(outbound.AllowCIDR, allowedPorts)
+	} else if len(outbound.AllowHost) == 0 && len(allowedPorts) > 0 {
+		// Ports without destinations allow all destinations on those ports
+		allow(anyIPs, allowedPorts)
+	}
+
+	hostNames := len(outbound.AllowHost) > 0
+	proxyPorts := append([]networkingv1.NetworkPolicyPort{}, allowedPorts...)
+	for _, hostPort := range outbound.AllowHostPort {
+		host, port, err := permissions.ParseHostPort(hostPort)
+		if err != nil {
+			return nil, err
+		}
+		if cidr, ok := hostCIDR(host); ok {
+			allow([]string{cidr}, []networkingv1.NetworkPolicyPort{policyPort(corev1.ProtocolTCP, port)})
+			continue
+		}
+		hostNames = true
+		proxyPorts = append(proxyPorts, policyPort(corev1.ProtocolTCP, port))
+	}
+
+	// The egress proxy resolves the host names, so it must be able to reach all addresses
+	// on the ports of the host name rules. The proxy shares the network of the pod, so this
+	// allows the MCP server to reach these addresses directly as well.
+	if hostNames && egressProxy {
+		if len(outbound.AllowHost) > 0 && len(outbound.AllowPort) == 0 {
+			// Host names without allowed ports are allowed on all ports
+			proxyPorts = nil
+		}
+		allow(anyIPs, proxyPorts)
+	}
+	return rules, nil
+}
+
+// ipBlockPeers returns a peer for each CIDR which is not entirely denied, that is within a
+// denied CIDR, excluding the denied CIDRs within it.
+func ipBlockPeers(cidrs []string, denied []*net.IPNet) []networkingv1.NetworkPolicyPeer {
+	var peers []networkingv1.NetworkPolicyPeer
+	for _, cidr := range cidrs {
+		_, allowed, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		allowedOnes, allowedBits := allowed.Mask.Size()
+
+		block := &networkingv1.IPBlock{CIDR: allowed.String()}
+		excluded := false
+		for _, deniedNet := range denied {
+			deniedOnes, deniedBits := deniedNet.Mask.Size()
+			if deniedBits != allowedBits {
+				continue
+			}
+			// A denied CIDR containing the allowed CIDR denies it entirely
+			if deniedOnes <= allowedOnes && deniedNet.Contains(allowed.IP) {
+				excluded = true
+				break
+			}
+			if deniedOnes > allowedOnes && allowed.Contains(deniedNet.IP) {
+				block.Except = append(block.Except, deniedNet.String())
+			}
+		}
+		if !excluded {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: block})
+		}
+	}
+	return peers
+}
+
+// hostCIDR returns the CIDR of a host which is an IP address or a CIDR
+func hostCIDR(host string) (string, bool) {
+	if _, ipNet, err := net.ParseCIDR(host); err == nil {
+		return ipNet.String(), true
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return "", false
+	}
+	bits := 128
+	if ip.To4() != nil {
+		bits = 32
+	}
+	return (&net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}).String(), true
+}
+
+// policyPort returns a NetworkPolicy port
+func policyPort(protocol corev1.Protocol, port int) networkingv1.NetworkPolicyPort {
+	portValue := intstr.FromInt32(int32(port)) // #nosec G115 -- ports are validated to be at most 65535
+	return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &portValue}
+}
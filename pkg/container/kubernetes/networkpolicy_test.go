@@ -0,0 +1,186 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+func dnsRule() networkingv1.NetworkPolicyEgressRule {
+	return networkingv1.NetworkPolicyEgressRule{
+		Ports: []networkingv1.NetworkPolicyPort{
+			policyPort(corev1.ProtocolUDP, 53),
+			policyPort(corev1.ProtocolTCP, 53),
+		},
+	}
+}
+
+func ipBlock(cidr string, except ...string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr, Except: except}}
+}
+
+func TestNetworkPolicyForWorkload(t *testing.T) {
+	t.Parallel()
+
+	policy, err := NetworkPolicyForWorkload("fetch", "toolhive", &permissions.NetworkPermissions{
+		Inbound: &permissions.InboundNetworkPermissions{
+			AllowHost: []string{"10.1.0.0/16", "example.com"},
+		},
+	}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, "fetch-network-policy", policy.Name)
+	assert.Equal(t, "toolhive", policy.Namespace)
+	assert.Equal(t, map[string]string{"app": "fetch"}, policy.Spec.PodSelector.MatchLabels)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		policy.Spec.PolicyTypes)
+
+	// Pods in the namespace and inbound addresses are allowed, host names cannot be matched
+	require.Len(t, policy.Spec.Ingress, 1)
+	assert.Len(t, policy.Spec.Ingress[0].From, 2)
+	assert.Equal(t, ipBlock("10.1.0.0/16"), policy.Spec.Ingress[0].From[1])
+
+	// Without outbound permissions, only DNS is allowed
+	assert.Equal(t, []networkingv1.NetworkPolicyEgressRule{dnsRule()}, policy.Spec.Egress)
+}
+
+func TestNetworkPolicyForWorkload_NoNetworkPermissions(t *testing.T) {
+	t.Parallel()
+
+	policy, err := NetworkPolicyForWorkload("fetch", "toolhive", nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, []networkingv1.NetworkPolicyEgressRule{{}}, policy.Spec.Egress)
+}
+
+func TestNetworkPolicyForWorkload_Egress(t *testing.T) {
+	t.Parallel()
+
+	https := []networkingv1.NetworkPolicyPort{policyPort(corev1.ProtocolTCP, 443)}
+
+	tests := []struct {
+		name        string
+		outbound    *permissions.OutboundNetworkPermissions
+		egressProxy bool
+		expected    []networkingv1.NetworkPolicyEgressRule
+	}{
+		{
+			name:     "allow all except denied CIDRs",
+			outbound: &permissions.OutboundNetworkPermissions{InsecureAllowAll: true, DenyCIDR: []string{"169.254.0.0/16"}},
+			expected: []networkingv1.NetworkPolicyEgressRule{
+				dnsRule(),
+				{To: []networkingv1.NetworkPolicyPeer{ipBlock("0.0.0.0/0", "169.254.0.0/16"), ipBlock("::/0")}},
+			},
+		},
+		{
+			name: "allowed CIDRs on allowed ports",
+			outbound: &permissions.OutboundNetworkPermissions{
+				AllowCIDR: []string{"10.0.0.0/8", "192.168.1.0/24"},
+				AllowPort: []int{443},
+				DenyCIDR:  []string{"192.168.1.0/24", "10.1.0.0/16"},
+			},
+			expected: []networkingv1.NetworkPolicyEgressRule{
+				dnsRule(),
+				{To: []networkingv1.NetworkPolicyPeer{ipBlock("10.0.0.0/8", "10.1.0.0/16")}, Ports: https},
+			},
+		},
+		{
+			name:     "ports without destinations",
+			outbound: &permissions.OutboundNetworkPermissions{AllowPort: []int{443}},
+			expected: []networkingv1.NetworkPolicyEgressRule{
+				dnsRule(),
+				{To: []networkingv1.NetworkPolicyPeer{ipBlock("0.0.0.0/0"), ipBlock("::/0")}, Ports: https},
+			},
+		},
+		{
+			name:     "IP address host and port",
+			outbound: &permissions.OutboundNetworkPermissions{AllowHostPort: []string{"10.0.0.1:5432"}},
+			expected: []networkingv1.NetworkPolicyEgressRule{
+				dnsRule(),
+				{
+					To:    []networkingv1.NetworkPolicyPeer{ipBlock("10.0.0.1/32")},
+					Ports: []networkingv1.NetworkPolicyPort{policyPort(corev1.ProtocolTCP, 5432)},
+				},
+			},
+		},
+		{
+			name:     "host names without egress proxy",
+			outbound: &permissions.OutboundNetworkPermissions{AllowHost: []string{"api.github.com"}, AllowPort: []int{443}},
+			expected: []networkingv1.NetworkPolicyEgressRule{dnsRule()},
+		},
+		{
+			name:        "host names with egress proxy",
+			outbound:    &permissions.OutboundNetworkPermissions{AllowHost: []string{"api.github.com"}, AllowPort: []int{443}},
+			egressProxy: true,
+			expected: []networkingv1.NetworkPolicyEgressRule{
+				dnsRule(),
+				{To: []networkingv1.NetworkPolicyPeer{ipBlock("0.0.0.0/0"), ipBlock("::/0")}, Ports: https},
+			},
+		},
+		{
+			name:        "host names without ports with egress proxy",
+			outbound:    &permissions.OutboundNetworkPermissions{AllowHost: []string{"api.github.com"}},
+			egressProxy: true,
+			expected: []networkingv1.NetworkPolicyEgressRule{
+				dnsRule(),
+				{To: []networkingv1.NetworkPolicyPeer{ipBlock("0.0.0.0/0"), ipBlock("::/0")}},
+			},
+		},
+		{
+			name:     "all destinations denied",
+			outbound: &permissions.OutboundNetworkPermissions{AllowCIDR: []string{"10.0.0.0/8"}, DenyCIDR: []string{"10.0.0.0/8"}},
+			expected: []networkingv1.NetworkPolicyEgressRule{dnsRule()},
+		},
+		{
+			name: "allowed CIDR within a broader denied CIDR",
+			outbound: &permissions.OutboundNetworkPermissions{
+				AllowCIDR: []string{"10.1.0.0/16", "192.168.1.0/24"},
+				AllowPort: []int{443},
+				DenyCIDR:  []string{"10.0.0.0/8"},
+			},
+			expected: []networkingv1.NetworkPolicyEgressRule{
+				dnsRule(),
+				{To: []networkingv1.NetworkPolicyPeer{ipBlock("192.168.1.0/24")}, Ports: https},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			policy, err := NetworkPolicyForWorkload("fetch", "toolhive",
+				&permissions.NetworkPermissions{Outbound: tt.outbound}, tt.egressProxy)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, policy.Spec.Egress)
+		})
+	}
+}
+
+func TestNetworkPolicyForWorkload_InvalidPermissions(t *testing.T) {
+	t.Parallel()
+
+	_, err := NetworkPolicyForWorkload("fetch", "toolhive", &permissions.NetworkPermissions{
+		Outbound: &permissions.OutboundNetworkPermissions{AllowCIDR: []string{"not-a-cidr"}},
+	}, false)
+	assert.Error(t, err)
+}
+
+func TestHostNameRules(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, HostNameRules(nil))
+	assert.Nil(t, HostNameRules(&permissions.NetworkPermissions{
+		Outbound: &permissions.OutboundNetworkPermissions{InsecureAllowAll: true, AllowHost: []string{"example.com"}},
+	}))
+	assert.Equal(t, []string{"example.com", "db.internal:5432", "evil.example.com"}, HostNameRules(&permissions.NetworkPermissions{
+		Outbound: &permissions.OutboundNetworkPermissions{
+			AllowHost:     []string{"example.com"},
+			AllowHostPort: []string{"10.0.0.1:5432", "db.internal:5432"},
+			DenyHost:      []string{"evil.example.com"},
+		},
+	}))
+}
@@ -0,0 +1,137 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+const (
+	// secretFilesVolumeName is the name of the volume the secret files are mounted from
+	secretFilesVolumeName = "toolhive-secret-files"
+	// secretFilesChecksumAnnotation is the pod template annotation holding a checksum of the
+	// secret files, so that the pods are recreated when the values of the secrets change
+	secretFilesChecksumAnnotation = "toolhive.stacklok.dev/secret-files-checksum"
+)
+
+// secretFilesSecretName returns the name of the Secret holding the secret files of a workload.
+func secretFilesSecretName(workloadName string) string {
+	return fmt.Sprintf("%s-secret-files", workloadName)
+}
+
+// secretFileKey returns the key of a secret file in the Secret, derived from its path in the container.
+func secretFileKey(path string) string {
+	hash := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(hash[:8])
+}
+
+// secretFilesChecksum returns a checksum of the paths, modes and values of the secret files.
+func secretFilesChecksum(secretFiles []runtime.SecretFile) string {
+	sorted := make([]runtime.SecretFile, len(secretFiles))
+	copy(sorted, secretFiles)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	hash := sha256.New()
+	for _, secretFile := range sorted {
+		fmt.Fprintf(hash, "%s\x00%o\x00%s\x00", secretFile.Path, secretFile.Mode, secretFile.Value)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// applySecretFiles creates or updates the Secret holding the secret files of a workload.
+func (c *Client) applySecretFiles(
+	ctx context.Context,
+	workloadName string,
+	namespace string,
+	labels map[string]string,
+	secretFiles []runtime.SecretFile,
+) error {
+	data := make(map[string][]byte, len(secretFiles))
+	for _, secretFile := range secretFiles {
+		data[secretFileKey(secretFile.Path)] = []byte(secretFile.Value)
+	}
+
+	secretApply := corev1apply.Secret(secretFilesSecretName(workloadName), namespace).
+		WithLabels(labels).
+		WithType(corev1.SecretTypeOpaque).
+		WithData(data)
+
+	// Apply the secret using server-side apply
+	fieldManager := "toolhive-container-manager"
+	_, err := c.client.CoreV1().Secrets(namespace).
+		Apply(ctx, secretApply, metav1.ApplyOptions{
+			FieldManager: fieldManager,
+			Force:        true,
+		})
+	if err != nil {
+		return fmt.Errorf("failed to apply secret: %v", err)
+	}
+
+	logger.Infof("Applied secret %s for secret files", secretFilesSecretName(workloadName))
+	return nil
+}
+
+// removeSecretFiles deletes the Secret holding the secret files of a workload, if any.
+func (c *Client) removeSecretFiles(ctx context.Context, workloadName string, namespace string) error {
+	secretName := secretFilesSecretName(workloadName)
+	err := c.client.CoreV1().Secrets(namespace).Delete(ctx, secretName, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete secret %s: %w", secretName, err)
+	}
+	return nil
+}
+
+// configureSecretFiles mounts the secret files into the MCP container of the pod template.
+// Each file is mounted individually from a projected volume, so that the rest of the
+// directory it is mounted in remains visible in the container.
+func configureSecretFiles(
+	podTemplateSpec *corev1apply.PodTemplateSpecApplyConfiguration,
+	workloadName string,
+	secretFiles []runtime.SecretFile,
+) error {
+	mcpContainer := getMCPContainer(podTemplateSpec)
+	if mcpContainer == nil {
+		return fmt.Errorf("mcp container not found in pod template")
+	}
+
+	items := make([]*corev1apply.KeyToPathApplyConfiguration, 0, len(secretFiles))
+	for _, secretFile := range secretFiles {
+		key := secretFileKey(secretFile.Path)
+		items = append(items, corev1apply.KeyToPath().
+			WithKey(key).
+			WithPath(key).
+			WithMode(int32(secretFile.Mode.Perm()))) // #nosec G115 -- permissions fit in an int32
+
+		mcpContainer.WithVolumeMounts(corev1apply.VolumeMount().
+			WithName(secretFilesVolumeName).
+			WithMountPath(secretFile.Path).
+			WithSubPath(key).
+			WithReadOnly(true))
+	}
+
+	podTemplateSpec.Spec.WithVolumes(corev1apply.Volume().
+		WithName(secretFilesVolumeName).
+		WithProjected(corev1apply.ProjectedVolumeSource().
+			WithSources(corev1apply.VolumeProjection().
+				WithSecret(corev1apply.SecretProjection().
+					WithName(secretFilesSecretName(workloadName)).
+					WithItems(items...)))))
+
+	// Files mounted with a subPath are not updated when the Secret changes,
+	// so the pods are recreated instead
+	podTemplateSpec.WithAnnotations(map[string]string{
+		secretFilesChecksumAnnotation: secretFilesChecksum(secretFiles),
+	})
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+)
+
+func TestDeployWorkloadWithSecretFiles(t *testing.T) {
+	t.Parallel()
+
+	mockStatefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-container",
+			Namespace: defaultNamespace,
+		},
+		Status: appsv1.StatefulSetStatus{
+			ReadyReplicas: 1,
+		},
+	}
+	// The simple clientset does not support creating objects with server-side apply
+	clientset := fake.NewClientset(mockStatefulSet)
+	client := NewClientWithConfigAndPlatformDetector(
+		clientset,
+		&rest.Config{Host: "https://fake-k8s-api.example.com"},
+		&mockPlatformDetector{platform: PlatformKubernetes},
+	)
+	client.waitForStatefulSetReadyFunc = mockWaitForStatefulSetReady
+	client.namespaceFunc = func() string { return defaultNamespace }
+
+	secretFiles := []runtime.SecretFile{
+		{Name: "github-token", Path: "/run/secrets/github-token", Mode: 0400, Value: "token"},
+		{Name: "tls-key", Path: "/etc/ssl/private/server.key", Mode: 0440, Value: "key"},
+	}
+	options := runtime.NewDeployWorkloadOptions()
+	options.SecretFiles = secretFiles
+
+	ctx := context.Background()
+	_, err := client.DeployWorkload(
+		ctx,
+		"ghcr.io/example/mcp:latest",
+		"test-container",
+		nil,
+		map[string]string{},
+		map[string]string{},
+		nil,
+		"stdio",
+		options,
+		false,
+	)
+	require.NoError(t, err)
+
+	// The values of the secrets are stored in a Secret, keyed by the hash of their path
+	secret, err := clientset.CoreV1().Secrets(defaultNamespace).Get(ctx, "test-container-secret-files", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.SecretTypeOpaque, secret.Type)
+	assert.Equal(t, map[string][]byte{
+		secretFileKey("/run/secrets/github-token"):   []byte("token"),
+		secretFileKey("/etc/ssl/private/server.key"): []byte("key"),
+	}, secret.Data)
+
+	statefulSet, err := clientset.AppsV1().StatefulSets(defaultNamespace).Get(ctx, "test-container", metav1.GetOptions{})
+	require.NoError(t, err)
+	podTemplate := statefulSet.Spec.Template
+	assert.Equal(t, secretFilesChecksum(secretFiles), podTemplate.Annotations[secretFilesChecksumAnnotation])
+
+	// The secrets are projected with their modes
+	require.Len(t, podTemplate.Spec.Volumes, 1)
+	volume := podTemplate.Spec.Volumes[0]
+	assert.Equal(t, secretFilesVolumeName, volume.Name)
+	require.NotNil(t, volume.Projected)
+	require.Len(t, volume.Projected.Sources, 1)
+	projection := volume.Projected.Sources[0].Secret
+	require.NotNil(t, projection)
+	assert.Equal(t, "test-container-secret-files", projection.Name)
+	require.Len(t, projection.Items, 2)
+	assert.Equal(t, secretFileKey("/run/secrets/github-token"), projection.Items[0].Key)
+	assert.Equal(t, int32(0400), *projection.Items[0].Mode)
+	assert.Equal(t, int32(0440), *projection.Items[1].Mode)
+
+	// Each secret is mounted read-only at its path
+	require.Len(t, podTemplate.Spec.Containers, 1)
+	assert.Equal(t, []corev1.VolumeMount{
+		{
+			Name:      secretFilesVolumeName,
+			MountPath: "/run/secrets/github-token",
+			SubPath:   secretFileKey("/run/secrets/github-token"),
+			ReadOnly:  true,
+		},
+		{
+			Name:      secretFilesVolumeName,
+			MountPath: "/etc/ssl/private/server.key",
+			SubPath:   secretFileKey("/etc/ssl/private/server.key"),
+			ReadOnly:  true,
+		},
+	}, podTemplate.Spec.Containers[0].VolumeMounts)
+
+	// Removing the workload removes the Secret
+	require.NoError(t, client.RemoveWorkload(ctx, "test-container"))
+	_, err = clientset.CoreV1().Secrets(defaultNamespace).Get(ctx, "test-container-secret-files", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestSecretFilesChecksum(t *testing.T) {
+	t.Parallel()
+
+	files := []runtime.SecretFile{
+		{Name: "a", Path: "/run/secrets/a", Mode: 0400, Value: "one"},
+		{Name: "b", Path: "/run/secrets/b", Mode: 0400, Value: "two"},
+	}
+	reordered := []runtime.SecretFile{files[1], files[0]}
+	changed := []runtime.SecretFile{files[0], {Name: "b", Path: "/run/secrets/b", Mode: 0400, Value: "three"}}
+
+	assert.Equal(t, secretFilesChecksum(files), secretFilesChecksum(reordered))
+	assert.NotEqual(t, secretFilesChecksum(files), secretFilesChecksum(changed))
+}
@@ -1,16 +1,20 @@
 package kubernetes
 
 import (
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
 	"k8s.io/utils/ptr"
 
 	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/permissions"
 )
 
 // SecurityContextBuilder provides platform-aware security context configuration
 type SecurityContextBuilder struct {
 	platform Platform
+	settings *permissions.SecuritySettings
 }
 
 // NewSecurityContextBuilder creates a new SecurityContextBuilder for the given platform
@@ -20,6 +24,13 @@ func NewSecurityContextBuilder(platform Platform) *SecurityContextBuilder {
 	}
 }
 
+// WithSecuritySettings sets the security settings of a permission profile,
+// which take precedence over the platform defaults
+func (b *SecurityContextBuilder) WithSecuritySettings(settings *permissions.SecuritySettings) *SecurityContextBuilder {
+	b.settings = settings
+	return b
+}
+
 // BuildPodSecurityContext creates a platform-appropriate pod security context
 func (b *SecurityContextBuilder) BuildPodSecurityContext() *corev1.PodSecurityContext {
 	// Start with base security context
@@ -47,6 +58,14 @@ func (b *SecurityContextBuilder) BuildPodSecurityContext() *corev1.PodSecurityCo
 		logger.Info("Configuring pod security context for Kubernetes")
 	}
 
+	// Apply the user of the permission profile
+	if uid, gid := b.runAsUser(); uid != nil {
+		podSecurityContext.RunAsNonRoot = ptr.To(*uid != 0)
+		podSecurityContext.RunAsUser = uid
+		podSecurityContext.RunAsGroup = gid
+		podSecurityContext.FSGroup = gid
+	}
+
 	return podSecurityContext
 }
 
@@ -83,9 +102,89 @@ func (b *SecurityContextBuilder) BuildContainerSecurityContext() *corev1.Securit
 		logger.Info("Configuring container security context for Kubernetes")
 	}
 
+	b.applySecuritySettings(containerSecurityContext)
+
 	return containerSecurityContext
 }
 
+// applySecuritySettings applies the security settings of the permission profile to a container security context
+func (b *SecurityContextBuilder) applySecuritySettings(containerSecurityContext *corev1.SecurityContext) {
+	if b.settings == nil {
+		return
+	}
+
+	if uid, gid := b.runAsUser(); uid != nil {
+		containerSecurityContext.RunAsNonRoot = ptr.To(*uid != 0)
+		containerSecurityContext.RunAsUser = uid
+		containerSecurityContext.RunAsGroup = gid
+	}
+
+	if capabilities := b.settings.NormalizedCapabilities(); len(capabilities) > 0 {
+		containerSecurityContext.Capabilities = &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		}
+		for _, capability := range capabilities {
+			containerSecurityContext.Capabilities.Add = append(containerSecurityContext.Capabilities.Add,
+				corev1.Capability(capability))
+		}
+	}
+
+	switch {
+	case b.settings.Seccomp == "":
+	case b.settings.Seccomp == permissions.SeccompProfileUnconfined:
+		containerSecurityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined}
+	case strings.HasPrefix(b.settings.Seccomp, permissions.SeccompProfileLocalhostPrefix):
+		containerSecurityContext.SeccompProfile = &corev1.SeccompProfile{
+			Type:             corev1.SeccompProfileTypeLocalhost,
+			LocalhostProfile: ptr.To(strings.TrimPrefix(b.settings.Seccomp, permissions.SeccompProfileLocalhostPrefix)),
+		}
+	default:
+		// Profiles can only be loaded from the nodes, so the built-in and custom profiles fall back
+		// to the default profile of the container runtime
+		if b.settings.Seccomp != permissions.SeccompProfileRuntimeDefault {
+			logger.Warnf("Seccomp profile %s is not supported by Kubernetes, using the runtime default profile instead. "+
+				"Install the profile on the nodes and use %s<path> to apply it",
+				b.settings.Seccomp, permissions.SeccompProfileLocalhostPrefix)
+		}
+		containerSecurityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
+
+	switch b.settings.AppArmor {
+	case "":
+	case permissions.AppArmorProfileRuntimeDefault:
+		containerSecurityContext.AppArmorProfile = &corev1.AppArmorProfile{Type: corev1.AppArmorProfileTypeRuntimeDefault}
+	case permissions.AppArmorProfileUnconfined:
+		containerSecurityContext.AppArmorProfile = &corev1.AppArmorProfile{Type: corev1.AppArmorProfileTypeUnconfined}
+	default:
+		containerSecurityContext.AppArmorProfile = &corev1.AppArmorProfile{
+			Type:             corev1.AppArmorProfileTypeLocalhost,
+			LocalhostProfile: ptr.To(b.settings.AppArmor),
+		}
+	}
+
+	if b.settings.ReadOnlyRootFilesystem {
+		containerSecurityContext.ReadOnlyRootFilesystem = ptr.To(true)
+	}
+	if b.settings.NoNewPrivileges {
+		containerSecurityContext.AllowPrivilegeEscalation = ptr.To(false)
+	}
+}
+
+// runAsUser returns the user and group of the permission profile, or nil if they are not
+// set or are assigned by the platform
+func (b *SecurityContextBuilder) runAsUser() (*int64, *int64) {
+	uid, gid, err := b.settings.RunAsUser()
+	if err != nil {
+		logger.Warnf("Ignoring invalid user of the permission profile: %v", err)
+		return nil, nil
+	}
+	if uid != nil && b.platform == PlatformOpenShift {
+		logger.Warnf("Ignoring user %s of the permission profile, as OpenShift assigns the user", b.settings.User)
+		return nil, nil
+	}
+	return uid, gid
+}
+
 // BuildPodSecurityContextApplyConfiguration creates a platform-appropriate pod security context
 // using the ApplyConfiguration types used by the client
 func (b *SecurityContextBuilder) BuildPodSecurityContextApplyConfiguration() *corev1apply.PodSecurityContextApplyConfiguration {
@@ -110,8 +209,7 @@ func (b *SecurityContextBuilder) BuildPodSecurityContextApplyConfiguration() *co
 	}
 
 	if baseContext.SeccompProfile != nil {
-		applyConfig = applyConfig.WithSeccompProfile(
-			corev1apply.SeccompProfile().WithType(baseContext.SeccompProfile.Type))
+		applyConfig = applyConfig.WithSeccompProfile(seccompProfileApplyConfiguration(baseContext.SeccompProfile))
 	}
 
 	return applyConfig
@@ -149,8 +247,15 @@ func (b *SecurityContextBuilder) BuildContainerSecurityContextApplyConfiguration
 	}
 
 	if baseContext.SeccompProfile != nil {
-		applyConfig = applyConfig.WithSeccompProfile(
-			corev1apply.SeccompProfile().WithType(baseContext.SeccompProfile.Type))
+		applyConfig = applyConfig.WithSeccompProfile(seccompProfileApplyConfiguration(baseContext.SeccompProfile))
+	}
+
+	if baseContext.AppArmorProfile != nil {
+		appArmorProfile := corev1apply.AppArmorProfile().WithType(baseContext.AppArmorProfile.Type)
+		if baseContext.AppArmorProfile.LocalhostProfile != nil {
+			appArmorProfile = appArmorProfile.WithLocalhostProfile(*baseContext.AppArmorProfile.LocalhostProfile)
+		}
+		applyConfig = applyConfig.WithAppArmorProfile(appArmorProfile)
 	}
 
 	if baseContext.Capabilities != nil {
@@ -166,3 +271,12 @@ func (b *SecurityContextBuilder) BuildContainerSecurityContextApplyConfiguration
 
 	return applyConfig
 }
+
+// seccompProfileApplyConfiguration converts a seccomp profile to its ApplyConfiguration type
+func seccompProfileApplyConfiguration(profile *corev1.SeccompProfile) *corev1apply.SeccompProfileApplyConfiguration {
+	applyConfig := corev1apply.SeccompProfile().WithType(profile.Type)
+	if profile.LocalhostProfile != nil {
+		applyConfig = applyConfig.WithLocalhostProfile(*profile.LocalhostProfile)
+	}
+	return applyConfig
+}
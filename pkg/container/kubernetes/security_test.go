@@ -6,6 +6,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/stacklok/toolhive/pkg/permissions"
 )
 
 func TestNewSecurityContextBuilder(t *testing.T) {
@@ -174,3 +176,66 @@ func TestSecurityContextBuilder_ConsistentBehavior(t *testing.T) {
 	assert.Equal(t, containerCtx1.AllowPrivilegeEscalation, containerCtx2.AllowPrivilegeEscalation)
 	assert.Equal(t, containerCtx1.ReadOnlyRootFilesystem, containerCtx2.ReadOnlyRootFilesystem)
 }
+
+func TestSecurityContextBuilder_WithSecuritySettings(t *testing.T) {
+	t.Parallel()
+
+	settings := &permissions.SecuritySettings{
+		Capabilities:    []string{"cap_net_bind_service"},
+		Seccomp:         "localhost/profiles/mcp.json",
+		AppArmor:        "toolhive-mcp",
+		NoNewPrivileges: true,
+		User:            "2000:3000",
+	}
+	builder := NewSecurityContextBuilder(PlatformKubernetes).WithSecuritySettings(settings)
+
+	podCtx := builder.BuildPodSecurityContext()
+	assert.Equal(t, int64(2000), *podCtx.RunAsUser)
+	assert.Equal(t, int64(3000), *podCtx.RunAsGroup)
+	assert.Equal(t, int64(3000), *podCtx.FSGroup)
+	assert.True(t, *podCtx.RunAsNonRoot)
+
+	containerCtx := builder.BuildContainerSecurityContext()
+	assert.Equal(t, int64(2000), *containerCtx.RunAsUser)
+	assert.Equal(t, int64(3000), *containerCtx.RunAsGroup)
+	require.NotNil(t, containerCtx.Capabilities)
+	assert.Equal(t, []corev1.Capability{"ALL"}, containerCtx.Capabilities.Drop)
+	assert.Equal(t, []corev1.Capability{"NET_BIND_SERVICE"}, containerCtx.Capabilities.Add)
+	require.NotNil(t, containerCtx.SeccompProfile)
+	assert.Equal(t, corev1.SeccompProfileTypeLocalhost, containerCtx.SeccompProfile.Type)
+	assert.Equal(t, "profiles/mcp.json", *containerCtx.SeccompProfile.LocalhostProfile)
+	require.NotNil(t, containerCtx.AppArmorProfile)
+	assert.Equal(t, corev1.AppArmorProfileTypeLocalhost, containerCtx.AppArmorProfile.Type)
+	assert.Equal(t, "toolhive-mcp", *containerCtx.AppArmorProfile.LocalhostProfile)
+	assert.False(t, *containerCtx.AllowPrivilegeEscalation)
+
+	applyConfig := builder.BuildContainerSecurityContextApplyConfiguration()
+	require.NotNil(t, applyConfig.SeccompProfile)
+	assert.Equal(t, "profiles/mcp.json", *applyConfig.SeccompProfile.LocalhostProfile)
+	require.NotNil(t, applyConfig.AppArmorProfile)
+	assert.Equal(t, "toolhive-mcp", *applyConfig.AppArmorProfile.LocalhostProfile)
+}
+
+func TestSecurityContextBuilder_WithSecuritySettings_Fallbacks(t *testing.T) {
+	t.Parallel()
+
+	// Seccomp profiles which cannot be loaded from the nodes fall back to the runtime default
+	settings := &permissions.SecuritySettings{
+		Seccomp:  permissions.SeccompProfileMCPDefault,
+		AppArmor: permissions.AppArmorProfileUnconfined,
+		User:     "0",
+	}
+	containerCtx := NewSecurityContextBuilder(PlatformKubernetes).WithSecuritySettings(settings).
+		BuildContainerSecurityContext()
+	assert.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, containerCtx.SeccompProfile.Type)
+	assert.Equal(t, corev1.AppArmorProfileTypeUnconfined, containerCtx.AppArmorProfile.Type)
+	// Running as root requires RunAsNonRoot to be disabled
+	assert.False(t, *containerCtx.RunAsNonRoot)
+	assert.Equal(t, int64(0), *containerCtx.RunAsUser)
+
+	// OpenShift assigns the user, even if the profile sets one
+	containerCtx = NewSecurityContextBuilder(PlatformOpenShift).WithSecuritySettings(settings).
+		BuildContainerSecurityContext()
+	assert.Nil(t, containerCtx.RunAsUser)
+	assert.True(t, *containerCtx.RunAsNonRoot)
+}
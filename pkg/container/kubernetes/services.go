@@ -0,0 +1,119 @@
+package kubernetes
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+)
+
+// serviceHostIP is the address the MCP container reaches the services at, as the containers
+// of a pod share its network namespace
+const serviceHostIP = "127.0.0.1"
+
+// configureServices adds the services of a workload to the pod template as sidecar containers,
+// which start in the order they are declared before the MCP container and run for the lifetime
+// of the pod. A service with a health check is started once its startup probe succeeds. The MCP
+// container reaches the services by their names, which resolve to the loopback address of the pod.
+func configureServices(
+	podTemplateSpec *corev1apply.PodTemplateSpecApplyConfiguration,
+	services []runtime.ServiceContainer,
+	isolateNetwork bool,
+	platform Platform,
+) error {
+	mcpContainer := getMCPContainer(podTemplateSpec)
+	if mcpContainer == nil {
+		return fmt.Errorf("mcp container not found in pod template")
+	}
+
+	containerNames := map[string]bool{}
+	for _, container := range slices.Concat(podTemplateSpec.Spec.Containers, podTemplateSpec.Spec.InitContainers) {
+		if container.Name != nil {
+			containerNames[*container.Name] = true
+		}
+	}
+
+	hostnames := make([]string, 0, len(services))
+	for _, service := range services {
+		if containerNames[service.Name] {
+			return fmt.Errorf("service %s conflicts with a container of the pod", service.Name)
+		}
+		podTemplateSpec.Spec.WithInitContainers(serviceContainer(service, isolateNetwork, platform))
+		hostnames = append(hostnames, service.Name)
+	}
+
+	podTemplateSpec.Spec.WithHostAliases(corev1apply.HostAlias().
+		WithIP(serviceHostIP).
+		WithHostnames(hostnames...))
+
+	// The MCP container reaches its services directly rather than through the egress proxy
+	if isolateNetwork {
+		for i := range mcpContainer.Env {
+			env := &mcpContainer.Env[i]
+			if env.Name != nil && (*env.Name == "NO_PROXY" || *env.Name == "no_proxy") && env.Value != nil {
+				env.WithValue(*env.Value + "," + strings.Join(hostnames, ","))
+			}
+		}
+	}
+	return nil
+}
+
+// serviceContainer returns the sidecar container of a service
+func serviceContainer(
+	service runtime.ServiceContainer,
+	isolateNetwork bool,
+	platform Platform,
+) *corev1apply.ContainerApplyConfiguration {
+	// Restarting an init container always makes it a sidecar, which keeps running alongside
+	// the MCP container
+	container := corev1apply.Container().
+		WithName(service.Name).
+		WithImage(service.Image).
+		WithRestartPolicy(corev1.ContainerRestartPolicyAlways)
+
+	// Like in the other runtimes, the command of a service overrides the command of its image
+	if len(service.Command) > 0 {
+		container.WithArgs(service.Command...)
+	}
+
+	envVars := maps.Clone(service.EnvVars)
+	if isolateNetwork {
+		// Services are subject to the same egress rules as the MCP container
+		if envVars == nil {
+			envVars = map[string]string{}
+		}
+		for _, env := range egressProxyEnvVars() {
+			envVars[*env.Name] = *env.Value
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(envVars)) {
+		container.WithEnv(corev1apply.EnvVar().WithName(key).WithValue(envVars[key]))
+	}
+
+	// Services run arbitrary images such as databases, so they only get the pod security context
+	// and cannot gain privileges
+	securityContext := corev1apply.SecurityContext().
+		WithPrivileged(false).
+		WithAllowPrivilegeEscalation(false)
+	if platform == PlatformOpenShift {
+		securityContext.WithSeccompProfile(corev1apply.SeccompProfile().WithType(corev1.SeccompProfileTypeRuntimeDefault))
+	}
+	container.WithSecurityContext(securityContext)
+
+	if check := service.HealthCheck; check != nil {
+		periodSeconds := int32(check.Interval().Seconds())
+		// The start period of a health check is covered by additional failures of the probe
+		startFailures := int32((check.StartPeriod() + check.Interval() - 1) / check.Interval())
+		container.WithStartupProbe(corev1apply.Probe().
+			WithExec(corev1apply.ExecAction().WithCommand(check.Command...)).
+			WithPeriodSeconds(periodSeconds).
+			WithTimeoutSeconds(int32(check.Timeout().Seconds())).
+			WithFailureThreshold(int32(check.RetryCount()) + startFailures))
+	}
+	return container
+}
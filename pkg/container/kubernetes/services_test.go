@@ -0,0 +1,120 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+func TestDeployWorkloadWithServices(t *testing.T) {
+	t.Parallel()
+
+	mockStatefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-container",
+			Namespace: defaultNamespace,
+		},
+		Status: appsv1.StatefulSetStatus{
+			ReadyReplicas: 1,
+		},
+	}
+	// The simple clientset does not support creating objects with server-side apply
+	clientset := fake.NewClientset(mockStatefulSet)
+	client := NewClientWithConfigAndPlatformDetector(
+		clientset,
+		&rest.Config{Host: "https://fake-k8s-api.example.com"},
+		&mockPlatformDetector{platform: PlatformKubernetes},
+	)
+	client.waitForStatefulSetReadyFunc = mockWaitForStatefulSetReady
+	client.namespaceFunc = func() string { return defaultNamespace }
+
+	options := runtime.NewDeployWorkloadOptions()
+	options.Services = []runtime.ServiceContainer{
+		{
+			Name:    "db",
+			Image:   "postgres:16",
+			EnvVars: map[string]string{"POSTGRES_PASSWORD": "test", "POSTGRES_DB": "mcp"},
+			HealthCheck: &runtime.HealthCheck{
+				Command:            []string{"pg_isready", "-U", "postgres"},
+				IntervalSeconds:    2,
+				Retries:            3,
+				StartPeriodSeconds: 10,
+			},
+		},
+		{Name: "cache", Image: "redis:7", Command: []string{"redis-server", "--save", ""}},
+	}
+
+	ctx := context.Background()
+	_, err := client.DeployWorkload(
+		ctx,
+		"ghcr.io/example/mcp:latest",
+		"test-container",
+		nil,
+		map[string]string{},
+		map[string]string{},
+		&permissions.Profile{},
+		"stdio",
+		options,
+		true,
+	)
+	require.NoError(t, err)
+
+	statefulSet, err := clientset.AppsV1().StatefulSets(defaultNamespace).Get(ctx, "test-container", metav1.GetOptions{})
+	require.NoError(t, err)
+	podSpec := statefulSet.Spec.Template.Spec
+
+	// The services are sidecars which start in order before the MCP container
+	require.Len(t, podSpec.InitContainers, 2)
+	db, cache := podSpec.InitContainers[0], podSpec.InitContainers[1]
+	assert.Equal(t, "db", db.Name)
+	assert.Equal(t, "postgres:16", db.Image)
+	assert.Equal(t, ptr.To(corev1.ContainerRestartPolicyAlways), db.RestartPolicy)
+	assert.Contains(t, db.Env, corev1.EnvVar{Name: "POSTGRES_DB", Value: "mcp"})
+	assert.Contains(t, db.Env, corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://localhost:3128"},
+		"services should use the egress proxy")
+	assert.Equal(t, ptr.To(false), db.SecurityContext.AllowPrivilegeEscalation)
+
+	require.NotNil(t, db.StartupProbe)
+	assert.Equal(t, []string{"pg_isready", "-U", "postgres"}, db.StartupProbe.Exec.Command)
+	assert.Equal(t, int32(2), db.StartupProbe.PeriodSeconds)
+	assert.Equal(t, int32(8), db.StartupProbe.FailureThreshold, "the start period should add failures")
+
+	assert.Equal(t, "cache", cache.Name)
+	assert.Equal(t, []string{"redis-server", "--save", ""}, cache.Args)
+	assert.Nil(t, cache.StartupProbe)
+
+	// The MCP container reaches the services by name, without the egress proxy
+	assert.Equal(t, []corev1.HostAlias{{IP: "127.0.0.1", Hostnames: []string{"db", "cache"}}}, podSpec.HostAliases)
+	var mcp *corev1.Container
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == "mcp" {
+			mcp = &podSpec.Containers[i]
+		}
+	}
+	require.NotNil(t, mcp)
+	assert.Contains(t, mcp.Env, corev1.EnvVar{Name: "NO_PROXY", Value: "localhost,127.0.0.1,::1,db,cache"})
+}
+
+func TestConfigureServicesRejectsConflicts(t *testing.T) {
+	t.Parallel()
+
+	podTemplateSpec := ensureObjectMetaApplyConfigurationExists(corev1apply.PodTemplateSpec())
+	require.NoError(t, configureMCPContainer(podTemplateSpec, "ghcr.io/example/mcp:latest", nil, true, nil, "stdio",
+		runtime.NewDeployWorkloadOptions(), PlatformKubernetes))
+
+	err := configureServices(podTemplateSpec, []runtime.ServiceContainer{{Name: "mcp", Image: "postgres:16"}},
+		false, PlatformKubernetes)
+	require.ErrorContains(t, err, "service mcp conflicts with a container of the pod")
+}
@@ -0,0 +1,554 @@
+// Package process provides a runtime which runs MCP servers as host processes
+// instead of containers. It is meant for trusted uvx://, npx:// and go:// servers
+// on machines where no container runtime is available. Where the platform allows
+// it, the processes are sandboxed according to their permission profile.
+package process
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adrg/xdg"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/permissions"
+	"github.com/stacklok/toolhive/pkg/telemetry"
+)
+
+// RuntimeName is the name identifier for the process runtime
+const RuntimeName = "process"
+
+// tracerName is the name of the tracer of process runtime operations
+const tracerName = "github.com/stacklok/toolhive/pkg/container/process"
+
+const (
+	// stopTimeout is how long a workload may take to exit before it is killed
+	stopTimeout = 10 * time.Second
+	// pollInterval is how often process and log file changes are checked
+	pollInterval = 200 * time.Millisecond
+	// logTailLines is the number of lines returned by GetWorkloadLogs
+	logTailLines = 100
+)
+
+// IsAvailable returns true if the process runtime was selected explicitly.
+// It is never auto-detected, since it runs servers without container isolation.
+func IsAvailable() bool {
+	return runtime.IsProcessRuntime()
+}
+
+// commandResolver returns the host command line of a workload
+type commandResolver func(image string, args []string) ([]string, error)
+
+// attachment holds the standard streams of a workload started by this client
+type attachment struct {
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+}
+
+// Client implements the Runtime interface by running workloads as host processes.
+// The state of the workloads is stored on disk, so that they can be managed from
+// any ToolHive process. Their standard streams can only be attached to by the
+// client which started them.
+type Client struct {
+	baseDir string
+	resolve commandResolver
+
+	mu       sync.Mutex
+	attached map[string]*attachment
+}
+
+// NewClient creates a new process runtime client
+func NewClient(_ context.Context) (*Client, error) {
+	baseDir := filepath.Join(xdg.StateHome, "toolhive", "processes")
+	if err := os.MkdirAll(baseDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create process runtime directory: %w", err)
+	}
+	return newClient(baseDir, resolveCommand), nil
+}
+
+// newClient creates a client which keeps the workload state in baseDir
+func newClient(baseDir string, resolve commandResolver) *Client {
+	return &Client{
+		baseDir:  baseDir,
+		resolve:  resolve,
+		attached: make(map[string]*attachment),
+	}
+}
+
+// DeployWorkload starts the server of a protocol scheme as a host process.
+// The process gets a scrubbed environment with its own home and temporary
+// directories, and is sandboxed according to the permission profile where the
+// platform supports it. Port mappings are not possible, so HTTP servers listen
+// on the exposed port directly.
+func (c *Client) DeployWorkload(
+	ctx context.Context,
+	image,
+	name string,
+	command []string,
+	envVars,
+	labels map[string]string,
+	permissionProfile *permissions.Profile,
+	transportType string,
+	options *runtime.DeployWorkloadOptions,
+	isolateNetwork bool,
+) (_ int, retErr error) {
+	ctx, span := telemetry.StartSpan(ctx, tracerName, "process.DeployWorkload",
+		attribute.String("workload.name", name),
+		attribute.String("workload.image", image),
+	)
+	defer func() {
+		telemetry.EndSpan(span, retErr)
+	}()
+
+	if options == nil {
+		options = runtime.NewDeployWorkloadOptions()
+	}
+	if len(options.SecretFiles) > 0 {
+		return 0, fmt.Errorf("secrets mounted as files are not supported by the process runtime")
+	}
+	if len(options.Services) > 0 {
+		return 0, fmt.Errorf("services are not supported by the process runtime")
+	}
+	warnUnsupportedSettings(name, permissionProfile, isolateNetwork)
+
+	cmdline, err := c.resolve(image, command)
+	if err != nil {
+		return 0, err
+	}
+
+	dir, err := c.workloadDir(name)
+	if err != nil {
+		return 0, err
+	}
+
+	// Replace any previous instance of the workload
+	if err := c.StopWorkload(ctx, name); err != nil {
+		return 0, fmt.Errorf("failed to stop previous instance of workload %s: %w", name, err)
+	}
+
+	homeDir := filepath.Join(dir, "home")
+	tmpDir := filepath.Join(dir, "tmp")
+	for _, d := range []string{homeDir, tmpDir} {
+		if err := os.MkdirAll(d, 0700); err != nil {
+			return 0, fmt.Errorf("failed to create workload directory: %w", err)
+		}
+	}
+
+	// #nosec G304 -- the path is built from the validated workload name
+	logFile, err := os.OpenFile(filepath.Join(dir, logFileName), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create log file: %w", err)
+	}
+	// The process has its own descriptor once it is started
+	defer logFile.Close()
+
+	var spec *sandboxSpec
+	if sandboxSupported && helperEnabled.Load() {
+		spec = newSandboxSpec(cmdline, permissionProfile, transportType, dir)
+	} else {
+		logger.Warnf("Sandboxing is not available, %s runs with all permissions of the current user", name)
+	}
+
+	env := buildEnv(envVars, homeDir, tmpDir)
+	cmd, streams, err := startProcess(cmdline, spec, env, homeDir, logFile, options.AttachStdio)
+	if err != nil && spec != nil && spec.IsolateNetwork {
+		// Unprivileged user namespaces may be disabled on this host
+		logger.Warnf("Failed to isolate the network of %s (%v), starting it without network namespace", name, err)
+		spec.IsolateNetwork = false
+		cmd, streams, err = startProcess(cmdline, spec, env, homeDir, logFile, options.AttachStdio)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to start workload %s: %w", name, err)
+	}
+
+	ports, hostPort, err := portMappings(options.ExposedPorts, transportType)
+	if err != nil {
+		_ = killProcess(cmd.Process.Pid)
+		return 0, err
+	}
+
+	startTime, err := processStartTime(cmd.Process.Pid)
+	if err != nil {
+		_ = killProcess(cmd.Process.Pid)
+		return 0, fmt.Errorf("failed to read start time of workload %s: %w", name, err)
+	}
+
+	state := &workloadState{
+		Name:      name,
+		Image:     image,
+		Command:   cmdline,
+		PID:       cmd.Process.Pid,
+		StartTime: startTime,
+		Labels:    labels,
+		Ports:     ports,
+		Sandboxed: spec != nil,
+		Created:   time.Now().UTC(),
+	}
+	if err := c.writeState(state); err != nil {
+		_ = killProcess(cmd.Process.Pid)
+		return 0, err
+	}
+
+	if streams != nil {
+		c.mu.Lock()
+		c.attached[name] = streams
+		c.mu.Unlock()
+	}
+	go c.wait(cmd, name)
+
+	logger.Infof("Started workload %s as process %d", name, cmd.Process.Pid)
+	return hostPort, nil
+}
+
+// warnUnsupportedSettings warns about the settings of a workload which cannot be enforced for a host process
+func warnUnsupportedSettings(name string, profile *permissions.Profile, isolateNetwork bool) {
+	if isolateNetwork {
+		logger.Warnf("The process runtime has no egress proxy; outbound access of %s is only restricted by the sandbox", name)
+	}
+	if profile == nil {
+		return
+	}
+	if profile.Privileged {
+		logger.Warnf("Privileged mode has no effect in the process runtime; %s runs as the current user", name)
+	}
+	if !profile.Resources.IsEmpty() {
+		logger.Warnf("Resource limits are not enforced by the process runtime for %s", name)
+	}
+	if profile.Security != nil {
+		logger.Warnf("Container security settings are not applied by the process runtime for %s", name)
+	}
+}
+
+// startProcess starts the command of a workload, through the sandbox helper if spec is set.
+// It returns the standard streams of the process if attachStdio is set.
+func startProcess(
+	cmdline []string,
+	spec *sandboxSpec,
+	env []string,
+	dir string,
+	logFile *os.File,
+	attachStdio bool,
+) (*exec.Cmd, *attachment, error) {
+	// #nosec G204 -- the command is resolved from the protocol scheme of the workload
+	cmd := exec.Command(cmdline[0], cmdline[1:]...)
+	if spec != nil {
+		self, err := os.Executable()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to find the sandbox helper: %w", err)
+		}
+		specJSON, err := json.Marshal(spec)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal sandbox spec: %w", err)
+		}
+		cmd = &exec.Cmd{Path: self, Args: []string{sandboxHelperName}}
+		env = append(env, sandboxSpecEnv+"="+string(specJSON))
+	}
+	cmd.Env = env
+	cmd.Dir = dir
+	cmd.SysProcAttr = sysProcAttr(spec)
+	cmd.Stderr = logFile
+
+	if !attachStdio {
+		cmd.Stdout = logFile
+		if err := cmd.Start(); err != nil {
+			return nil, nil, err
+		}
+		return cmd, nil, nil
+	}
+
+	// The pipes are created by hand rather than with StdinPipe and StdoutPipe, since
+	// those are closed by Wait, which runs independently of the readers of the output.
+	stdinReader, stdinWriter, err := os.Pipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		_ = stdinReader.Close()
+		_ = stdinWriter.Close()
+		return nil, nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	cmd.Stdin = stdinReader
+	cmd.Stdout = stdoutWriter
+
+	err = cmd.Start()
+	// The child has its own copies of these ends
+	_ = stdinReader.Close()
+	_ = stdoutWriter.Close()
+	if err != nil {
+		_ = stdinWriter.Close()
+		_ = stdoutReader.Close()
+		return nil, nil, err
+	}
+	return cmd, &attachment{stdin: stdinWriter, stdout: stdoutReader}, nil
+}
+
+// portMappings returns the ports of a workload and the port the proxy should connect to.
+// Host processes cannot be remapped, so every port is published as itself.
+func portMappings(exposedPorts map[string]struct{}, transportType string) ([]runtime.PortMapping, int, error) {
+	if transportType == "stdio" {
+		return nil, 0, nil
+	}
+
+	var ports []runtime.PortMapping
+	hostPort := 0
+	for exposed := range exposedPorts {
+		portValue, protocol, _ := strings.Cut(exposed, "/")
+		port, err := strconv.Atoi(portValue)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid exposed port %q: %w", exposed, err)
+		}
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		ports = append(ports, runtime.PortMapping{ContainerPort: port, HostPort: port, Protocol: protocol})
+		if hostPort == 0 || port < hostPort {
+			hostPort = port
+		}
+	}
+	return ports, hostPort, nil
+}
+
+// wait reaps the process of a workload and records its exit
+func (c *Client) wait(cmd *exec.Cmd, name string) {
+	err := cmd.Wait()
+
+	c.mu.Lock()
+	delete(c.attached, name)
+	c.mu.Unlock()
+
+	state, readErr := c.readState(name)
+	// The workload may have been removed or redeployed in the meantime
+	if readErr != nil || state.PID != cmd.Process.Pid {
+		return
+	}
+
+	exited := time.Now().UTC()
+	state.Exited = &exited
+	state.ExitCode = cmd.ProcessState.ExitCode()
+	if writeErr := c.writeState(state); writeErr != nil {
+		logger.Warnf("Failed to record exit of workload %s: %v", name, writeErr)
+	}
+	logger.Debugf("Workload %s exited: %v", name, err)
+}
+
+// ListWorkloads lists the workloads of the process runtime
+func (c *Client) ListWorkloads(_ context.Context) ([]runtime.ContainerInfo, error) {
+	states, err := c.listStates()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]runtime.ContainerInfo, 0, len(states))
+	for _, state := range states {
+		result = append(result, state.info())
+	}
+	return result, nil
+}
+
+// StopWorkload stops the process of a workload, killing it if it does not exit in time.
+// If the workload is already stopped or does not exist, it returns success.
+func (c *Client) StopWorkload(ctx context.Context, workloadName string) error {
+	state, err := c.readState(workloadName)
+	if err != nil {
+		if errors.Is(err, runtime.ErrWorkloadNotFound) {
+			return nil
+		}
+		return err
+	}
+	if state.status() != runtime.WorkloadStatusRunning {
+		return nil
+	}
+
+	if err := terminateProcess(state.PID); err != nil {
+		return fmt.Errorf("failed to stop workload %s: %w", workloadName, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, stopTimeout)
+	defer cancel()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for state.alive() {
+		select {
+		case <-ctx.Done():
+			if !state.alive() {
+				return nil
+			}
+			logger.Warnf("Workload %s did not exit in time, killing it", workloadName)
+			if err := killProcess(state.PID); err != nil {
+				return fmt.Errorf("failed to kill workload %s: %w", workloadName, err)
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// AttachToWorkload returns the standard input and output of a workload.
+// Only workloads started by this client with AttachStdio can be attached to.
+func (c *Client) AttachToWorkload(ctx context.Context, workloadName string) (io.WriteCloser, io.ReadCloser, error) {
+	running, err := c.IsWorkloadRunning(ctx, workloadName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !running {
+		return nil, nil, fmt.Errorf("workload %s is not running", workloadName)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	streams, ok := c.attached[workloadName]
+	if !ok {
+		return nil, nil, fmt.Errorf("workload %s was not started with attached stdio by this process", workloadName)
+	}
+	return streams.stdin, streams.stdout, nil
+}
+
+// IsWorkloadRunning checks if the process of a workload is running
+func (c *Client) IsWorkloadRunning(_ context.Context, workloadName string) (bool, error) {
+	state, err := c.readState(workloadName)
+	if err != nil {
+		return false, err
+	}
+	return state.status() == runtime.WorkloadStatusRunning, nil
+}
+
+// RemoveWorkload stops a workload and removes its directory, including its home directory and logs
+func (c *Client) RemoveWorkload(ctx context.Context, workloadName string) error {
+	if err := c.StopWorkload(ctx, workloadName); err != nil {
+		return err
+	}
+
+	dir, err := c.workloadDir(workloadName)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove workload %s: %w", workloadName, err)
+	}
+	return nil
+}
+
+// GetWorkloadLogs returns the last lines of output of a workload.
+// If follow is true, the output is copied to stdout until the workload exits.
+func (c *Client) GetWorkloadLogs(ctx context.Context, workloadName string, follow bool) (string, error) {
+	if follow {
+		return "", c.StreamWorkloadLogs(ctx, workloadName, time.Time{}, os.Stdout, os.Stdout)
+	}
+
+	path, err := c.logPath(workloadName)
+	if err != nil {
+		return "", err
+	}
+	// #nosec G304 -- the path is built from the validated workload name
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read logs of workload %s: %w", workloadName, err)
+	}
+	return tailLines(data, logTailLines), nil
+}
+
+// StreamWorkloadLogs copies the output of a workload to stdout until ctx is done or the workload exits.
+// The log file only holds the output of the current run, so since is not needed to skip older output.
+// Both streams are written to the same file, so everything is copied to stdout.
+func (c *Client) StreamWorkloadLogs(
+	ctx context.Context, workloadName string, _ time.Time, stdout, _ io.Writer,
+) error {
+	path, err := c.logPath(workloadName)
+	if err != nil {
+		return err
+	}
+	// #nosec G304 -- the path is built from the validated workload name
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open logs of workload %s: %w", workloadName, err)
+	}
+	defer file.Close()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := io.Copy(stdout, file); err != nil {
+			return fmt.Errorf("failed to stream logs of workload %s: %w", workloadName, err)
+		}
+		running, err := c.IsWorkloadRunning(ctx, workloadName)
+		if err != nil || !running {
+			// Copy whatever was written before the process exited
+			_, _ = io.Copy(stdout, file)
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// logPath returns the path of the log file of an existing workload
+func (c *Client) logPath(workloadName string) (string, error) {
+	if _, err := c.readState(workloadName); err != nil {
+		return "", err
+	}
+	dir, err := c.workloadDir(workloadName)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, logFileName), nil
+}
+
+// GetWorkloadInfo returns information about a workload
+func (c *Client) GetWorkloadInfo(_ context.Context, workloadName string) (runtime.ContainerInfo, error) {
+	state, err := c.readState(workloadName)
+	if err != nil {
+		return runtime.ContainerInfo{}, err
+	}
+	return state.info(), nil
+}
+
+// IsRunning checks that workloads can be started. The runtime has no daemon, so
+// this only verifies that the state directory is usable.
+func (c *Client) IsRunning(_ context.Context) error {
+	info, err := os.Stat(c.baseDir)
+	if err != nil {
+		return fmt.Errorf("process runtime directory is not available: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("process runtime directory %s is not a directory", c.baseDir)
+	}
+	return nil
+}
+
+// tailLines returns the last n lines of data
+func tailLines(data []byte, n int) string {
+	data = bytes.TrimRight(data, "\n")
+	if len(data) == 0 {
+		return ""
+	}
+	start := len(data)
+	for i := 0; i < n; i++ {
+		idx := bytes.LastIndexByte(data[:start], '\n')
+		if idx < 0 {
+			return string(data) + "\n"
+		}
+		start = idx
+	}
+	return string(data[start+1:]) + "\n"
+}
@@ -0,0 +1,174 @@
+//go:build !windows
+// +build !windows
+
+package process
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+)
+
+// shellResolver runs the "image" of a workload as a shell script
+func shellResolver(image string, args []string) ([]string, error) {
+	return append([]string{"/bin/sh", "-c", image, "sh"}, args...), nil
+}
+
+func TestClient_StdioWorkload(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newClient(t.TempDir(), shellResolver)
+	options := runtime.NewDeployWorkloadOptions()
+	options.AttachStdio = true
+
+	labels := map[string]string{"toolhive": "true"}
+	_, err := client.DeployWorkload(ctx, `echo "started" >&2; cat`, "echo", nil,
+		map[string]string{"MCP_TRANSPORT": "stdio"}, labels, nil, "stdio", options, false)
+	require.NoError(t, err)
+
+	stdin, stdout, err := client.AttachToWorkload(ctx, "echo")
+	require.NoError(t, err)
+
+	_, err = fmt.Fprintln(stdin, `{"jsonrpc":"2.0","method":"ping","id":1}`)
+	require.NoError(t, err)
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","method":"ping","id":1}`+"\n", line)
+
+	workloads, err := client.ListWorkloads(ctx)
+	require.NoError(t, err)
+	require.Len(t, workloads, 1)
+	assert.Equal(t, "echo", workloads[0].Name)
+	assert.Equal(t, runtime.WorkloadStatusRunning, workloads[0].State)
+	assert.Equal(t, labels, workloads[0].Labels)
+
+	require.Eventually(t, func() bool {
+		logs, err := client.GetWorkloadLogs(ctx, "echo", false)
+		return err == nil && logs == "started\n"
+	}, 5*time.Second, 50*time.Millisecond)
+
+	require.NoError(t, client.StopWorkload(ctx, "echo"))
+	running, err := client.IsWorkloadRunning(ctx, "echo")
+	require.NoError(t, err)
+	assert.False(t, running)
+
+	// Stopping a stopped workload succeeds
+	require.NoError(t, client.StopWorkload(ctx, "echo"))
+
+	require.NoError(t, client.RemoveWorkload(ctx, "echo"))
+	_, err = client.GetWorkloadInfo(ctx, "echo")
+	assert.ErrorIs(t, err, runtime.ErrWorkloadNotFound)
+}
+
+func TestClient_HTTPWorkload(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newClient(t.TempDir(), shellResolver)
+	options := runtime.NewDeployWorkloadOptions()
+	options.ExposedPorts["8080/tcp"] = struct{}{}
+
+	port, err := client.DeployWorkload(ctx, `echo "port=$MCP_PORT home=${HOME##*/}"; exit 3`, "http", nil,
+		map[string]string{"MCP_PORT": "8080"}, nil, nil, "streamable-http", options, false)
+	require.NoError(t, err)
+	assert.Equal(t, 8080, port)
+
+	require.Eventually(t, func() bool {
+		info, err := client.GetWorkloadInfo(ctx, "http")
+		return err == nil && info.State == runtime.WorkloadStatusStopped && strings.HasPrefix(info.Status, "Exited (3)")
+	}, 5*time.Second, 50*time.Millisecond)
+
+	info, err := client.GetWorkloadInfo(ctx, "http")
+	require.NoError(t, err)
+	assert.Equal(t, []runtime.PortMapping{{ContainerPort: 8080, HostPort: 8080, Protocol: "tcp"}}, info.Ports)
+
+	logs, err := client.GetWorkloadLogs(ctx, "http", false)
+	require.NoError(t, err)
+	assert.Equal(t, "port=8080 home=home\n", logs)
+
+	_, _, err = client.AttachToWorkload(ctx, "http")
+	assert.Error(t, err)
+}
+
+func TestClient_StopWorkloadKillsProcessGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newClient(t.TempDir(), shellResolver)
+
+	_, err := client.DeployWorkload(ctx, `sleep 60 & wait`, "sleeper", nil, nil, nil, nil, "stdio",
+		runtime.NewDeployWorkloadOptions(), false)
+	require.NoError(t, err)
+
+	state, err := client.readState("sleeper")
+	require.NoError(t, err)
+
+	require.NoError(t, client.StopWorkload(ctx, "sleeper"))
+	assert.False(t, processAlive(state.PID))
+}
+
+func TestClient_StopWorkloadIgnoresReusedPID(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newClient(t.TempDir(), shellResolver)
+
+	// The state of a workload whose PID now belongs to another process, the test itself
+	startTime, err := processStartTime(os.Getpid())
+	require.NoError(t, err)
+	if startTime == 0 {
+		t.Skip("process start times are not supported on this platform")
+	}
+	require.NoError(t, client.writeState(&workloadState{
+		Name:      "reused",
+		PID:       os.Getpid(),
+		StartTime: startTime + 1,
+		Created:   time.Now().UTC(),
+	}))
+
+	running, err := client.IsWorkloadRunning(ctx, "reused")
+	require.NoError(t, err)
+	assert.False(t, running)
+	require.NoError(t, client.StopWorkload(ctx, "reused"))
+}
+
+func TestClient_DeployWorkloadRejectsSecretFiles(t *testing.T) {
+	t.Parallel()
+
+	client := newClient(t.TempDir(), shellResolver)
+	options := runtime.NewDeployWorkloadOptions()
+	options.SecretFiles = []runtime.SecretFile{{Name: "token", Path: "/run/secrets/token", Value: "secret"}}
+
+	_, err := client.DeployWorkload(context.Background(), "true", "secrets", nil, nil, nil, nil, "stdio", options, false)
+	assert.Error(t, err)
+}
+
+func TestClient_DeployWorkloadRejectsServices(t *testing.T) {
+	t.Parallel()
+
+	client := newClient(t.TempDir(), shellResolver)
+	options := runtime.NewDeployWorkloadOptions()
+	options.Services = []runtime.ServiceContainer{{Name: "db", Image: "postgres:16"}}
+
+	_, err := client.DeployWorkload(context.Background(), "true", "services", nil, nil, nil, nil, "stdio", options, false)
+	assert.Error(t, err)
+}
+
+func TestTailLines(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", tailLines(nil, 2))
+	assert.Equal(t, "a\n", tailLines([]byte("a"), 2))
+	assert.Equal(t, "b\nc\n", tailLines([]byte("a\nb\nc\n"), 2))
+	assert.Equal(t, "a\nb\n", tailLines([]byte("a\nb\n\n"), 5))
+}
@@ -0,0 +1,106 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Protocol schemes which can be run as host processes. These mirror the schemes
+// understood by the runner, which are built into images for container runtimes.
+const (
+	uvxScheme = "uvx://"
+	npxScheme = "npx://"
+	goScheme  = "go://"
+)
+
+// inheritedEnvVars are the only variables of the ToolHive environment passed to workloads.
+// Everything else, including credentials, is scrubbed.
+var inheritedEnvVars = []string{
+	"PATH",
+	"LANG",
+	"LC_ALL",
+	"LC_CTYPE",
+	"TZ",
+	"SYSTEMROOT",
+	"HTTP_PROXY",
+	"HTTPS_PROXY",
+	"NO_PROXY",
+	"http_proxy",
+	"https_proxy",
+	"no_proxy",
+	"SSL_CERT_FILE",
+	"SSL_CERT_DIR",
+	"NODE_EXTRA_CA_CERTS",
+}
+
+// resolveCommand returns the host command line which runs the server of a protocol scheme.
+// Container images cannot be run by the process runtime.
+func resolveCommand(image string, args []string) ([]string, error) {
+	scheme, pkg, ok := strings.Cut(image, "://")
+	if !ok {
+		return nil, fmt.Errorf("the process runtime can only run uvx://, npx:// or go:// servers, not %q", image)
+	}
+	if pkg == "" {
+		return nil, fmt.Errorf("no package specified in %q", image)
+	}
+
+	var command []string
+	switch scheme + "://" {
+	case npxScheme:
+		command = []string{"npx", "--yes", pkg}
+	case uvxScheme:
+		command = []string{"uvx", pkg}
+	case goScheme:
+		// Remote packages must be versioned to be run outside of a module
+		if !isLocalPath(pkg) && !strings.Contains(pkg, "@") {
+			pkg += "@latest"
+		}
+		command = []string{"go", "run", pkg}
+	default:
+		return nil, fmt.Errorf("the process runtime can only run uvx://, npx:// or go:// servers, not %q", image)
+	}
+
+	path, err := exec.LookPath(command[0])
+	if err != nil {
+		return nil, fmt.Errorf("%s is required to run %s with the process runtime: %w", command[0], image, err)
+	}
+	command[0] = path
+
+	return append(command, args...), nil
+}
+
+// isLocalPath returns true if the go:// package is a path on the host
+func isLocalPath(pkg string) bool {
+	return pkg == "." || strings.HasPrefix(pkg, "./") || strings.HasPrefix(pkg, "../") || filepath.IsAbs(pkg)
+}
+
+// buildEnv returns the environment of a workload. Only a small set of variables is
+// inherited from ToolHive; HOME and the temporary directory point into the workload
+// directory so the server cannot read the configuration of the user.
+func buildEnv(envVars map[string]string, homeDir, tmpDir string) []string {
+	env := make(map[string]string, len(envVars)+len(inheritedEnvVars)+5)
+	for _, name := range inheritedEnvVars {
+		if value, ok := os.LookupEnv(name); ok {
+			env[name] = value
+		}
+	}
+	env["HOME"] = homeDir
+	env["USERPROFILE"] = homeDir
+	env["TMPDIR"] = tmpDir
+	env["TEMP"] = tmpDir
+	env["TMP"] = tmpDir
+	for name, value := range envVars {
+		env[name] = value
+	}
+
+	result := make([]string, 0, len(env))
+	for name, value := range env {
+		result = append(result, name+"="+value)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,47 @@
+package process
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveCommand(t *testing.T) {
+	t.Parallel()
+
+	goPath, err := exec.LookPath("go")
+	require.NoError(t, err)
+
+	command, err := resolveCommand("go://github.com/example/server", []string{"--verbose"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{goPath, "run", "github.com/example/server@latest", "--verbose"}, command)
+
+	command, err = resolveCommand("go://github.com/example/server@v1.2.3", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{goPath, "run", "github.com/example/server@v1.2.3"}, command)
+
+	command, err = resolveCommand("go://./cmd/server", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{goPath, "run", "./cmd/server"}, command)
+
+	_, err = resolveCommand("ghcr.io/example/server:latest", nil)
+	assert.Error(t, err)
+
+	_, err = resolveCommand("npx://", nil)
+	assert.Error(t, err)
+}
+
+func TestBuildEnv(t *testing.T) {
+	t.Setenv("PATH", "/usr/bin")
+	t.Setenv("GITHUB_TOKEN", "secret")
+
+	env := buildEnv(map[string]string{"MCP_TRANSPORT": "stdio", "TMPDIR": "/custom"}, "/w/home", "/w/tmp")
+	assert.Contains(t, env, "PATH=/usr/bin")
+	assert.Contains(t, env, "HOME=/w/home")
+	assert.Contains(t, env, "MCP_TRANSPORT=stdio")
+	assert.Contains(t, env, "TMPDIR=/custom")
+	assert.Contains(t, env, "TMP=/w/tmp")
+	assert.NotContains(t, env, "GITHUB_TOKEN=secret")
+}
@@ -0,0 +1,174 @@
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+const (
+	// sandboxHelperName is the argv[0] with which ToolHive re-executes itself as the sandbox helper
+	sandboxHelperName = "thv-sandbox-helper"
+	// sandboxSpecEnv is the environment variable through which the sandbox helper receives its spec.
+	// The helper removes it from the environment before running the workload.
+	sandboxSpecEnv = "TOOLHIVE_SANDBOX_SPEC"
+)
+
+// systemReadPaths are the host paths a sandboxed process may always read and execute,
+// so that interpreters and their shared libraries can be loaded. Only the entries of /proc,
+// /sys and /run which language runtimes read are included, since the trees expose the
+// environment of the other processes of the user and sockets such as the Docker socket.
+var systemReadPaths = []string{
+	"/bin", "/sbin", "/usr", "/lib", "/lib32", "/lib64", "/etc", "/opt", "/nix", "/snap",
+	// The process itself, CPU and memory information, and kernel parameters
+	"/proc/self", "/proc/cpuinfo", "/proc/meminfo", "/proc/stat", "/proc/loadavg",
+	"/proc/filesystems", "/proc/sys/kernel", "/proc/sys/vm",
+	// CPU topology, cgroup limits and memory settings
+	"/sys/devices/system/cpu", "/sys/fs/cgroup", "/sys/kernel/mm/transparent_hugepage",
+	// Targets of the /etc/resolv.conf symlink
+	"/run/systemd/resolve", "/run/resolvconf", "/run/NetworkManager/resolv.conf",
+}
+
+// systemWritePaths are the host paths a sandboxed process may always write to
+var systemWritePaths = []string{"/dev"}
+
+// helperEnabled is set when the current binary can be re-executed as the sandbox helper
+var helperEnabled atomic.Bool
+
+// sandboxSpec describes the restrictions the sandbox helper applies before it runs a workload
+type sandboxSpec struct {
+	// Command is the command line of the workload
+	Command []string `json:"command"`
+	// ReadPaths are the paths the workload may read and execute
+	ReadPaths []string `json:"read_paths,omitempty"`
+	// WritePaths are the paths the workload may read and write
+	WritePaths []string `json:"write_paths,omitempty"`
+	// RestrictConnect indicates whether outgoing TCP connections are limited to ConnectPorts
+	RestrictConnect bool `json:"restrict_connect,omitempty"`
+	// ConnectPorts are the TCP ports the workload may connect to if RestrictConnect is set
+	ConnectPorts []int `json:"connect_ports,omitempty"`
+	// IsolateNetwork indicates whether the workload runs in its own network namespace,
+	// which has no network access at all. This is only possible for stdio workloads.
+	IsolateNetwork bool `json:"isolate_network,omitempty"`
+}
+
+// HandleSandboxHelper must be called at the very start of main. If the process was
+// started as the sandbox helper of the process runtime, it applies the sandbox and
+// replaces itself with the workload, and never returns. Otherwise it records that the
+// binary can act as the helper, which enables sandboxing of process workloads.
+func HandleSandboxHelper() {
+	if filepath.Base(os.Args[0]) != sandboxHelperName {
+		helperEnabled.Store(true)
+		return
+	}
+
+	var spec sandboxSpec
+	if err := json.Unmarshal([]byte(os.Getenv(sandboxSpecEnv)), &spec); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: invalid sandbox spec: %v\n", sandboxHelperName, err)
+		os.Exit(126)
+	}
+	if len(spec.Command) == 0 {
+		fmt.Fprintf(os.Stderr, "%s: no command specified\n", sandboxHelperName)
+		os.Exit(126)
+	}
+	_ = os.Unsetenv(sandboxSpecEnv)
+
+	// runSandboxed only returns if the sandbox could not be applied or the command not executed
+	err := runSandboxed(&spec)
+	fmt.Fprintf(os.Stderr, "%s: %v\n", sandboxHelperName, err)
+	os.Exit(126)
+}
+
+// newSandboxSpec derives the sandbox of a workload from its permission profile.
+// workloadDir is always writable, since it holds the home and temporary directories.
+func newSandboxSpec(
+	command []string,
+	profile *permissions.Profile,
+	transportType string,
+	workloadDir string,
+) *sandboxSpec {
+	spec := &sandboxSpec{Command: command}
+
+	spec.ReadPaths = append(spec.ReadPaths, systemReadPaths...)
+	spec.ReadPaths = append(spec.ReadPaths, installPrefixes(command[0])...)
+	spec.WritePaths = append(spec.WritePaths, systemWritePaths...)
+	spec.WritePaths = append(spec.WritePaths, workloadDir)
+
+	if profile == nil {
+		profile = permissions.BuiltinNoneProfile()
+	}
+	spec.ReadPaths = append(spec.ReadPaths, mountSources(profile.Read)...)
+	spec.WritePaths = append(spec.WritePaths, mountSources(profile.Write)...)
+
+	var outbound *permissions.OutboundNetworkPermissions
+	if profile.Network != nil {
+		outbound = profile.Network.Outbound
+	}
+	switch {
+	case outbound != nil && outbound.InsecureAllowAll:
+		// No restrictions
+	case outbound == nil || (len(outbound.AllowHost) == 0 && len(outbound.AllowPort) == 0 &&
+		len(outbound.AllowCIDR) == 0 && len(outbound.AllowHostPort) == 0):
+		// The server may not connect anywhere
+		spec.RestrictConnect = true
+		spec.IsolateNetwork = transportType == "stdio"
+	default:
+		ports := slices.Clone(outbound.AllowPort)
+		for _, hostPort := range outbound.AllowHostPort {
+			if _, port, err := permissions.ParseHostPort(hostPort); err == nil {
+				ports = append(ports, port)
+			}
+		}
+		if len(ports) > 0 {
+			slices.Sort(ports)
+			spec.RestrictConnect = true
+			spec.ConnectPorts = slices.Compact(ports)
+		}
+		if len(outbound.AllowHost) > 0 || len(outbound.AllowCIDR) > 0 || len(outbound.AllowHostPort) > 0 {
+			logger.Warnf("The process runtime cannot restrict outbound connections by host or CIDR; " +
+				"only the allowed ports are enforced")
+		}
+	}
+
+	return spec
+}
+
+// installPrefixes returns the installation prefixes of a binary, e.g. ~/.nvm/versions/node/v22
+// for ~/.nvm/versions/node/v22/bin/npx, so the interpreter and its libraries can be read
+func installPrefixes(binary string) []string {
+	prefixes := []string{filepath.Dir(filepath.Dir(binary))}
+	if resolved, err := filepath.EvalSymlinks(binary); err == nil {
+		if prefix := filepath.Dir(filepath.Dir(resolved)); !slices.Contains(prefixes, prefix) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// mountSources returns the host paths of mount declarations. The process sees the host file
+// system, so the container paths of the declarations do not apply.
+func mountSources(mounts []permissions.MountDeclaration) []string {
+	sources := make([]string, 0, len(mounts))
+	for _, mount := range mounts {
+		if mount.IsResourceURI() {
+			logger.Warnf("Skipping mount %s: resource URIs are not supported by the process runtime", mount)
+			continue
+		}
+		source, _, err := mount.Parse()
+		if err != nil {
+			logger.Warnf("Skipping invalid mount declaration: %s (%v)", mount, err)
+			continue
+		}
+		if abs, err := filepath.Abs(source); err == nil {
+			source = abs
+		}
+		sources = append(sources, source)
+	}
+	return sources
+}
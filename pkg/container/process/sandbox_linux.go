@@ -0,0 +1,170 @@
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	goruntime "runtime"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// sandboxSupported indicates whether processes can be sandboxed on this platform
+	sandboxSupported = true
+
+	// landlockReadAccess are the rights granted on read-only paths
+	landlockReadAccess = unix.LANDLOCK_ACCESS_FS_EXECUTE | unix.LANDLOCK_ACCESS_FS_READ_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_DIR
+	// landlockFileAccess are the rights which apply to files rather than directories
+	landlockFileAccess = unix.LANDLOCK_ACCESS_FS_EXECUTE | unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	// landlockAccessV1 are the file system rights known to the first Landlock ABI
+	landlockAccessV1 = landlockReadAccess | unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_DIR | unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_CHAR | unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_REG | unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_FIFO | unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+
+	// landlockRuleNetPort is the type of Landlock rules for TCP ports
+	landlockRuleNetPort = 2
+)
+
+// errLandlockUnsupported is returned when the kernel does not support Landlock
+var errLandlockUnsupported = errors.New("landlock is not supported by the kernel")
+
+// landlockNetPortAttr is the kernel's struct landlock_net_port_attr
+type landlockNetPortAttr struct {
+	allowedAccess uint64
+	port          uint64
+}
+
+// sysProcAttr returns the attributes of a workload process. The process is started in
+// its own process group, so that the whole tree can be stopped, and is terminated when
+// ToolHive exits. Stdio workloads without network permissions get their own network
+// namespace, created in an unprivileged user namespace.
+func sysProcAttr(spec *sandboxSpec) *syscall.SysProcAttr {
+	attr := &syscall.SysProcAttr{
+		Setpgid:   true,
+		Pdeathsig: syscall.SIGTERM,
+	}
+	if spec != nil && spec.IsolateNetwork {
+		attr.Cloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWNET
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1}}
+	}
+	return attr
+}
+
+// runSandboxed restricts the current thread with Landlock and executes the workload in its place
+func runSandboxed(spec *sandboxSpec) error {
+	// Landlock restricts the calling thread, which is the one replaced by the workload on exec
+	goruntime.LockOSThread()
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no_new_privs: %w", err)
+	}
+
+	if err := applyLandlock(spec); err != nil {
+		if !errors.Is(err, errLandlockUnsupported) {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "%s: %v, file system access is not restricted\n", sandboxHelperName, err)
+	}
+
+	// #nosec G204 -- the command is resolved by the process runtime
+	if err := unix.Exec(spec.Command[0], spec.Command, os.Environ()); err != nil {
+		return fmt.Errorf("failed to execute %s: %w", spec.Command[0], err)
+	}
+	return nil
+}
+
+// applyLandlock restricts file system access, and TCP connections if the kernel supports it,
+// to the paths and ports of the spec
+func applyLandlock(spec *sandboxSpec) error {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return errLandlockUnsupported
+	}
+
+	handledAccess := uint64(landlockAccessV1)
+	if abi >= 3 {
+		handledAccess |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+	attr := unix.LandlockRulesetAttr{Access_fs: handledAccess}
+	if spec.RestrictConnect {
+		if abi >= 4 {
+			attr.Access_net = unix.LANDLOCK_ACCESS_NET_CONNECT_TCP
+		} else if !spec.IsolateNetwork {
+			fmt.Fprintf(os.Stderr, "%s: landlock ABI %d cannot restrict network access\n", sandboxHelperName, abi)
+		}
+	}
+
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET,
+		uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to create landlock ruleset: %w", errno)
+	}
+	rulesetFd := int(fd)
+	defer unix.Close(rulesetFd)
+
+	for _, path := range spec.ReadPaths {
+		if err := addPathRule(rulesetFd, path, landlockReadAccess&handledAccess); err != nil {
+			return err
+		}
+	}
+	for _, path := range spec.WritePaths {
+		if err := addPathRule(rulesetFd, path, handledAccess); err != nil {
+			return err
+		}
+	}
+	if attr.Access_net != 0 {
+		for _, port := range spec.ConnectPorts {
+			rule := landlockNetPortAttr{allowedAccess: unix.LANDLOCK_ACCESS_NET_CONNECT_TCP, port: uint64(port)}
+			_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(rulesetFd), landlockRuleNetPort,
+				uintptr(unsafe.Pointer(&rule)), 0, 0, 0)
+			if errno != 0 {
+				return fmt.Errorf("failed to allow connections to port %d: %w", port, errno)
+			}
+		}
+	}
+
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, uintptr(rulesetFd), 0, 0); errno != 0 {
+		return fmt.Errorf("failed to apply landlock ruleset: %w", errno)
+	}
+	return nil
+}
+
+// addPathRule grants access to the file or directory tree at path. Missing paths are skipped.
+func addPathRule(rulesetFd int, path string, access uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		if errors.Is(err, unix.ENOENT) {
+			return nil
+		}
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer unix.Close(fd)
+
+	var stat unix.Stat_t
+	if err := unix.Fstat(fd, &stat); err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if stat.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= landlockFileAccess
+	}
+
+	rule := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)}
+	_, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(rulesetFd), unix.LANDLOCK_RULE_PATH_BENEATH,
+		uintptr(unsafe.Pointer(&rule)), 0, 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to allow access to %s: %w", path, errno)
+	}
+	return nil
+}
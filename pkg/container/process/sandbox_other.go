@@ -0,0 +1,24 @@
+//go:build !linux && !windows
+// +build !linux,!windows
+
+package process
+
+import (
+	"fmt"
+	goruntime "runtime"
+	"syscall"
+)
+
+// sandboxSupported indicates whether processes can be sandboxed on this platform
+const sandboxSupported = false
+
+// sysProcAttr returns the attributes of a workload process. The process is started in
+// its own process group, so that the whole tree can be stopped.
+func sysProcAttr(_ *sandboxSpec) *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setpgid: true}
+}
+
+// runSandboxed is not available on this platform
+func runSandboxed(_ *sandboxSpec) error {
+	return fmt.Errorf("sandboxing processes is not supported on %s", goruntime.GOOS)
+}
@@ -0,0 +1,104 @@
+package process
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+func TestNewSandboxSpec(t *testing.T) {
+	t.Parallel()
+
+	command := []string{"/usr/bin/npx", "--yes", "server"}
+
+	tests := []struct {
+		name            string
+		profile         *permissions.Profile
+		transportType   string
+		restrictConnect bool
+		connectPorts    []int
+		isolateNetwork  bool
+	}{
+		{
+			name:            "nil profile denies all network access",
+			profile:         nil,
+			transportType:   "stdio",
+			restrictConnect: true,
+			isolateNetwork:  true,
+		},
+		{
+			name:            "http servers keep their network namespace",
+			profile:         permissions.BuiltinNoneProfile(),
+			transportType:   "sse",
+			restrictConnect: true,
+		},
+		{
+			name:          "network profile allows everything",
+			profile:       permissions.BuiltinNetworkProfile(),
+			transportType: "stdio",
+		},
+		{
+			name: "allowed ports are enforced",
+			profile: &permissions.Profile{
+				Network: &permissions.NetworkPermissions{
+					Outbound: &permissions.OutboundNetworkPermissions{
+						AllowHost:     []string{"api.github.com"},
+						AllowPort:     []int{443},
+						AllowHostPort: []string{"db.internal:5432", "api.github.com:443"},
+					},
+				},
+			},
+			transportType:   "stdio",
+			restrictConnect: true,
+			connectPorts:    []int{443, 5432},
+		},
+		{
+			name: "hosts without ports cannot be enforced",
+			profile: &permissions.Profile{
+				Network: &permissions.NetworkPermissions{
+					Outbound: &permissions.OutboundNetworkPermissions{
+						AllowHost: []string{"api.github.com"},
+					},
+				},
+			},
+			transportType: "stdio",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			spec := newSandboxSpec(command, tt.profile, tt.transportType, "/state/workload")
+			assert.Equal(t, command, spec.Command)
+			assert.Equal(t, tt.restrictConnect, spec.RestrictConnect)
+			assert.Equal(t, tt.connectPorts, spec.ConnectPorts)
+			assert.Equal(t, tt.isolateNetwork, spec.IsolateNetwork)
+			assert.Contains(t, spec.WritePaths, "/state/workload")
+			assert.Contains(t, spec.ReadPaths, "/usr")
+			assert.Contains(t, spec.ReadPaths, "/proc/self")
+			for _, path := range []string{"/proc", "/sys", "/run", "/run/user", "/var/run"} {
+				assert.NotContains(t, spec.ReadPaths, path)
+			}
+		})
+	}
+}
+
+func TestNewSandboxSpec_Mounts(t *testing.T) {
+	t.Parallel()
+
+	profile := &permissions.Profile{
+		Read:  []permissions.MountDeclaration{"/data/in:/in", "/etc/config"},
+		Write: []permissions.MountDeclaration{"/data/out:/out", "volume://cache:/cache"},
+	}
+
+	spec := newSandboxSpec([]string{"/usr/bin/uvx", "server"}, profile, "stdio", "/state/workload")
+	assert.Contains(t, spec.ReadPaths, "/data/in")
+	assert.Contains(t, spec.ReadPaths, "/etc/config")
+	assert.NotContains(t, spec.ReadPaths, "/in")
+	assert.Contains(t, spec.WritePaths, "/data/out")
+	assert.NotContains(t, spec.WritePaths, "/out")
+	assert.NotContains(t, spec.WritePaths, "/cache")
+}
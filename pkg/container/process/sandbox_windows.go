@@ -0,0 +1,22 @@
+//go:build windows
+// +build windows
+
+package process
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// sandboxSupported indicates whether processes can be sandboxed on this platform
+const sandboxSupported = false
+
+// sysProcAttr returns the attributes of a workload process
+func sysProcAttr(_ *sandboxSpec) *syscall.SysProcAttr {
+	return nil
+}
+
+// runSandboxed is not available on this platform
+func runSandboxed(_ *sandboxSpec) error {
+	return fmt.Errorf("sandboxing processes is not supported on windows")
+}
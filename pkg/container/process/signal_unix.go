@@ -0,0 +1,38 @@
+//go:build !windows
+// +build !windows
+
+package process
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processAlive returns true if a process with the given PID exists
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// terminateProcess asks the process group of a workload to exit
+func terminateProcess(pid int) error {
+	return signalGroup(pid, syscall.SIGTERM)
+}
+
+// killProcess forcibly stops the process group of a workload
+func killProcess(pid int) error {
+	return signalGroup(pid, syscall.SIGKILL)
+}
+
+// signalGroup signals the process group led by pid, falling back to the process itself
+// if it is not a group leader
+func signalGroup(pid int, signal syscall.Signal) error {
+	err := syscall.Kill(-pid, signal)
+	if errors.Is(err, syscall.ESRCH) {
+		err = syscall.Kill(pid, signal)
+	}
+	if errors.Is(err, syscall.ESRCH) {
+		return nil
+	}
+	return err
+}
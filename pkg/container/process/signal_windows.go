@@ -0,0 +1,25 @@
+//go:build windows
+// +build windows
+
+package process
+
+import (
+	proc "github.com/stacklok/toolhive/pkg/process"
+)
+
+// processAlive returns true if a process with the given PID exists
+func processAlive(pid int) bool {
+	alive, err := proc.FindProcess(pid)
+	return err == nil && alive
+}
+
+// terminateProcess stops a workload. Windows has no graceful equivalent of SIGTERM
+// for console processes, so the process is killed.
+func terminateProcess(pid int) error {
+	return proc.KillProcess(pid)
+}
+
+// killProcess forcibly stops a workload
+func killProcess(pid int) error {
+	return proc.KillProcess(pid)
+}
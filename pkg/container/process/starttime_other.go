@@ -0,0 +1,9 @@
+//go:build !linux
+// +build !linux
+
+package process
+
+// processStartTime is not supported on this platform; a start time of 0 is never checked
+func processStartTime(_ int) (uint64, error) {
+	return 0, nil
+}
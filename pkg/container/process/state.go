@@ -0,0 +1,183 @@
+package process
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+)
+
+const (
+	// stateFileName is the name of the file in which the state of a workload is stored
+	stateFileName = "state.json"
+	// logFileName is the name of the file to which the output of a workload is written
+	logFileName = "output.log"
+)
+
+// workloadState is the state of a workload which is persisted to disk, so that
+// other ToolHive processes can list, stop and remove the workload.
+type workloadState struct {
+	// Name is the workload name
+	Name string `json:"name"`
+	// Image is the protocol scheme the workload was started from, e.g. npx://pkg
+	Image string `json:"image"`
+	// Command is the command line of the process
+	Command []string `json:"command"`
+	// PID is the process ID of the workload
+	PID int `json:"pid"`
+	// StartTime is the start time of the process in clock ticks since boot, where supported.
+	// It tells the process apart from a later process which reuses its PID.
+	StartTime uint64 `json:"start_time,omitempty"`
+	// Labels are the labels of the workload
+	Labels map[string]string `json:"labels,omitempty"`
+	// Ports are the ports the workload listens on
+	Ports []runtime.PortMapping `json:"ports,omitempty"`
+	// Sandboxed indicates whether the process was started through the sandbox helper
+	Sandboxed bool `json:"sandboxed"`
+	// Created is the time the workload was started
+	Created time.Time `json:"created"`
+	// Exited is the time the process exited, if it has been observed to exit
+	Exited *time.Time `json:"exited,omitempty"`
+	// ExitCode is the exit code of the process, if it has been observed to exit
+	ExitCode int `json:"exit_code,omitempty"`
+}
+
+// workloadDir returns the directory in which the files of a workload are kept
+func (c *Client) workloadDir(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid workload name: %q", name)
+	}
+	return filepath.Join(c.baseDir, name), nil
+}
+
+// readState reads the state of a workload. It returns runtime.ErrWorkloadNotFound
+// if the workload does not exist.
+func (c *Client) readState(name string) (*workloadState, error) {
+	dir, err := c.workloadDir(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// #nosec G304 -- the path is built from the validated workload name
+	data, err := os.ReadFile(filepath.Join(dir, stateFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", runtime.ErrWorkloadNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to read state of workload %s: %w", name, err)
+	}
+
+	var state workloadState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state of workload %s: %w", name, err)
+	}
+	return &state, nil
+}
+
+// writeState atomically writes the state of a workload
+func (c *Client) writeState(state *workloadState) error {
+	dir, err := c.workloadDir(state.Name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create workload directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state of workload %s: %w", state.Name, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, stateFileName+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write state of workload %s: %w", state.Name, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write state of workload %s: %w", state.Name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write state of workload %s: %w", state.Name, err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, stateFileName)); err != nil {
+		return fmt.Errorf("failed to write state of workload %s: %w", state.Name, err)
+	}
+	return nil
+}
+
+// listStates reads the states of all workloads. Workloads with unreadable state are skipped.
+func (c *Client) listStates() ([]*workloadState, error) {
+	entries, err := os.ReadDir(c.baseDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list workloads: %w", err)
+	}
+
+	states := make([]*workloadState, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		state, err := c.readState(entry.Name())
+		if err != nil {
+			continue
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// status returns the status of the workload, checking whether its process is still alive
+func (s *workloadState) status() runtime.WorkloadStatus {
+	if s.Exited != nil || s.PID <= 0 {
+		return runtime.WorkloadStatusStopped
+	}
+	if !s.alive() {
+		return runtime.WorkloadStatusStopped
+	}
+	return runtime.WorkloadStatusRunning
+}
+
+// alive returns true if the process of the workload is alive. A process with the PID of the
+// workload but another start time is unrelated, e.g. after a reboot or when the exit of the
+// workload was not recorded, and the PID has been reused.
+func (s *workloadState) alive() bool {
+	if !processAlive(s.PID) {
+		return false
+	}
+	if s.StartTime == 0 {
+		return true
+	}
+	startTime, err := processStartTime(s.PID)
+	return err == nil && startTime == s.StartTime
+}
+
+// info converts the state to the runtime representation of a workload
+func (s *workloadState) info() runtime.ContainerInfo {
+	state := s.status()
+	description := fmt.Sprintf("Up since %s (pid %d)", s.Created.Format(time.RFC3339), s.PID)
+	if state != runtime.WorkloadStatusRunning {
+		description = "Exited"
+		if s.Exited != nil {
+			description = fmt.Sprintf("Exited (%d) at %s", s.ExitCode, s.Exited.Format(time.RFC3339))
+		}
+	}
+	return runtime.ContainerInfo{
+		Name:    s.Name,
+		Image:   s.Image,
+		Status:  description,
+		State:   state,
+		Created: s.Created,
+		Labels:  s.Labels,
+		Ports:   s.Ports,
+	}
+}
@@ -0,0 +1,137 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ServiceContainer is an auxiliary container which a workload depends on, such as a database.
+// Services start before the MCP server, share its network and network isolation, and are
+// stopped and removed with it. The MCP server reaches a service by its name.
+type ServiceContainer struct {
+	// Name is the host name of the service, which must be a DNS label
+	Name string `json:"name" yaml:"name"`
+	// Image is the image of the service
+	Image string `json:"image" yaml:"image"`
+	// Command overrides the command of the image
+	Command []string `json:"command,omitempty" yaml:"command,omitempty"`
+	// EnvVars are the environment variables of the service
+	EnvVars map[string]string `json:"env_vars,omitempty" yaml:"env_vars,omitempty"`
+	// HealthCheck decides when the service is ready. Services without a health check
+	// are ready once they are started.
+	HealthCheck *HealthCheck `json:"health_check,omitempty" yaml:"health_check,omitempty"`
+}
+
+// HealthCheck is a command which succeeds once a service is ready
+type HealthCheck struct {
+	// Command is run in the service container, without a shell
+	Command []string `json:"command" yaml:"command"`
+	// IntervalSeconds is the time between two checks (default 5)
+	IntervalSeconds int `json:"interval_seconds,omitempty" yaml:"interval_seconds,omitempty"`
+	// TimeoutSeconds is the time after which a check fails (default 5)
+	TimeoutSeconds int `json:"timeout_seconds,omitempty" yaml:"timeout_seconds,omitempty"`
+	// Retries is the number of consecutive failed checks after which the service is unhealthy (default 12)
+	Retries int `json:"retries,omitempty" yaml:"retries,omitempty"`
+	// StartPeriodSeconds is the time the service may take to start, during which failed checks don't count
+	StartPeriodSeconds int `json:"start_period_seconds,omitempty" yaml:"start_period_seconds,omitempty"`
+}
+
+// Defaults of health checks
+const (
+	defaultHealthCheckInterval = 5 * time.Second
+	defaultHealthCheckTimeout  = 5 * time.Second
+	defaultHealthCheckRetries  = 12
+)
+
+// serviceNamePattern matches DNS labels, which can be used as host names and container names
+var serviceNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Interval returns the time between two checks
+func (h *HealthCheck) Interval() time.Duration {
+	if h.IntervalSeconds > 0 {
+		return time.Duration(h.IntervalSeconds) * time.Second
+	}
+	return defaultHealthCheckInterval
+}
+
+// Timeout returns the time after which a check fails
+func (h *HealthCheck) Timeout() time.Duration {
+	if h.TimeoutSeconds > 0 {
+		return time.Duration(h.TimeoutSeconds) * time.Second
+	}
+	return defaultHealthCheckTimeout
+}
+
+// RetryCount returns the number of consecutive failed checks after which the service is unhealthy
+func (h *HealthCheck) RetryCount() int {
+	if h.Retries > 0 {
+		return h.Retries
+	}
+	return defaultHealthCheckRetries
+}
+
+// StartPeriod returns the time the service may take to start
+func (h *HealthCheck) StartPeriod() time.Duration {
+	return time.Duration(h.StartPeriodSeconds) * time.Second
+}
+
+// Deadline returns how long a service may take to become healthy: its start period, and the
+// time it takes for all the retries to fail
+func (h *HealthCheck) Deadline() time.Duration {
+	return h.StartPeriod() + time.Duration(h.RetryCount()+1)*(h.Interval()+h.Timeout())
+}
+
+// ValidateServices checks that the services have valid and unique names, an image, and
+// a command for their health check
+func ValidateServices(services []ServiceContainer) error {
+	names := make(map[string]bool, len(services))
+	for _, service := range services {
+		if !serviceNamePattern.MatchString(service.Name) {
+			return fmt.Errorf("invalid service name %q: must be a lowercase DNS label", service.Name)
+		}
+		if names[service.Name] {
+			return fmt.Errorf("duplicate service name %q", service.Name)
+		}
+		names[service.Name] = true
+
+		if service.Image == "" {
+			return fmt.Errorf("service %s has no image", service.Name)
+		}
+		if check := service.HealthCheck; check != nil {
+			if len(check.Command) == 0 {
+				return fmt.Errorf("the health check of service %s has no command", service.Name)
+			}
+			if check.IntervalSeconds < 0 || check.TimeoutSeconds < 0 || check.Retries < 0 || check.StartPeriodSeconds < 0 {
+				return fmt.Errorf("the health check of service %s has a negative setting", service.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadServices loads a list of services from a YAML or JSON file, and validates them
+func LoadServices(path string) ([]ServiceContainer, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read services file: %w", err)
+	}
+
+	var services []ServiceContainer
+	if err := yaml.UnmarshalStrict(data, &services); err != nil {
+		return nil, fmt.Errorf("failed to parse services file %s: %w", path, err)
+	}
+	if err := ValidateServices(services); err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+// ServiceContainerName returns the name of the container of a service of a workload
+func ServiceContainerName(workloadName, serviceName string) string {
+	return fmt.Sprintf("%s-svc-%s", workloadName, serviceName)
+}
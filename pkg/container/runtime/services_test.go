@@ -0,0 +1,99 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateServices(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		services []ServiceContainer
+		wantErr  string
+	}{
+		{
+			name: "valid services",
+			services: []ServiceContainer{
+				{Name: "db", Image: "postgres:16", HealthCheck: &HealthCheck{Command: []string{"pg_isready"}}},
+				{Name: "cache-1", Image: "redis:7"},
+			},
+		},
+		{
+			name:     "invalid name",
+			services: []ServiceContainer{{Name: "My_DB", Image: "postgres:16"}},
+			wantErr:  `invalid service name "My_DB"`,
+		},
+		{
+			name:     "duplicate name",
+			services: []ServiceContainer{{Name: "db", Image: "postgres:16"}, {Name: "db", Image: "mysql:8"}},
+			wantErr:  `duplicate service name "db"`,
+		},
+		{
+			name:     "missing image",
+			services: []ServiceContainer{{Name: "db"}},
+			wantErr:  "service db has no image",
+		},
+		{
+			name:     "health check without command",
+			services: []ServiceContainer{{Name: "db", Image: "postgres:16", HealthCheck: &HealthCheck{}}},
+			wantErr:  "the health check of service db has no command",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateServices(tt.services)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadServices(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "services.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+- name: db
+  image: postgres:16
+  env_vars:
+    POSTGRES_PASSWORD: test
+  health_check:
+    command: ["pg_isready", "-U", "postgres"]
+    interval_seconds: 2
+`), 0600))
+
+	services, err := LoadServices(path)
+	require.NoError(t, err)
+	assert.Equal(t, []ServiceContainer{{
+		Name:        "db",
+		Image:       "postgres:16",
+		EnvVars:     map[string]string{"POSTGRES_PASSWORD": "test"},
+		HealthCheck: &HealthCheck{Command: []string{"pg_isready", "-U", "postgres"}, IntervalSeconds: 2},
+	}}, services)
+
+	// Unknown fields are rejected rather than ignored
+	require.NoError(t, os.WriteFile(path, []byte(`[{"name": "db", "image": "postgres:16", "healthcheck": {}}]`), 0600))
+	_, err = LoadServices(path)
+	assert.Error(t, err)
+}
+
+func TestHealthCheckDeadline(t *testing.T) {
+	t.Parallel()
+
+	check := &HealthCheck{Command: []string{"true"}}
+	assert.Equal(t, 13*10*time.Second, check.Deadline())
+
+	check = &HealthCheck{Command: []string{"true"}, IntervalSeconds: 1, TimeoutSeconds: 2, Retries: 3, StartPeriodSeconds: 30}
+	assert.Equal(t, 30*time.Second+4*3*time.Second, check.Deadline())
+}
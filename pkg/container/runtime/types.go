@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"time"
 
@@ -167,6 +168,45 @@ type Runtime interface {
 	IsRunning(ctx context.Context) error
 }
 
+// LogStreamer is implemented by runtimes which can stream the output of a
+// workload as it is produced. It is used to collect workload logs to disk.
+type LogStreamer interface {
+	// StreamWorkloadLogs copies the output of the primary container of the workload,
+	// starting at since, to stdout and stderr until ctx is done or the container exits.
+	StreamWorkloadLogs(ctx context.Context, workloadName string, since time.Time, stdout, stderr io.Writer) error
+}
+
+// UserNamespace describes how a container runtime maps the users of containers to the users of the host
+type UserNamespace string
+
+const (
+	// UserNamespaceHost indicates that the users of containers are the users of the host
+	UserNamespaceHost UserNamespace = "host"
+	// UserNamespaceRootless indicates that the runtime runs as an unprivileged user, which is
+	// root in containers, and that the other users of containers are subordinate IDs of that user
+	UserNamespaceRootless UserNamespace = "rootless"
+	// UserNamespaceRemapped indicates that the runtime maps the users of containers to subordinate
+	// IDs of the host, such as with the userns-remap option of Docker
+	UserNamespaceRemapped UserNamespace = "remapped"
+)
+
+// UserNamespaceInfo describes the user namespace setup of a container runtime
+type UserNamespaceInfo struct {
+	// Mode is how the users of containers are mapped to the users of the host
+	Mode UserNamespace
+	// WriteMounts describes how the runtime makes the write mounts of permission profiles writable
+	WriteMounts string
+	// Warnings are problems of the setup which may prevent workloads from writing to their mounts
+	Warnings []string
+}
+
+// UserNamespaceDetector is implemented by runtimes which can detect rootless and user namespace
+// remapped setups. It is used to diagnose the ownership of the files of write mounts.
+type UserNamespaceDetector interface {
+	// UserNamespace returns the user namespace setup of the runtime
+	UserNamespace(ctx context.Context) (UserNamespaceInfo, error)
+}
+
 // Monitor defines the interface for container monitoring
 type Monitor interface {
 	// StartMonitoring starts monitoring the container
@@ -189,6 +229,10 @@ const (
 	TypeKubernetes Type = "kubernetes"
 	// TypeColima represents the Colima runtime
 	TypeColima Type = "colima"
+	// TypeProcess represents the process runtime, which runs workloads as host processes
+	TypeProcess Type = "process"
+	// TypeContainerd represents the containerd runtime, which is driven through nerdctl
+	TypeContainerd Type = "containerd"
 )
 
 // MountType represents the type of mount
@@ -220,6 +264,15 @@ type PermissionConfig struct {
 	SecurityOpt []string
 	// Privileged indicates whether the container should run in privileged mode
 	Privileged bool
+	// Resources are the resource limits of the container
+	Resources *permissions.ResourceLimits
+	// ReadOnlyRootfs indicates whether the root filesystem of the container is read-only
+	ReadOnlyRootfs bool
+	// User is the user to run the container as, in the format uid[:gid]
+	User string
+	// UsernsMode is the user namespace of the container, such as host or keep-id.
+	// It is empty for the default user namespace of the runtime.
+	UsernsMode string
 }
 
 // DeployWorkloadOptions represents configuration options for deploying a workload.
@@ -251,6 +304,26 @@ type DeployWorkloadOptions struct {
 	// IgnoreConfig contains configuration for ignore patterns and tmpfs overlays
 	// Used to filter bind mount contents by hiding sensitive files
 	IgnoreConfig *ignore.Config
+
+	// SecretFiles are secrets which are mounted into the workload as read-only files
+	// rather than passed as environment variables
+	SecretFiles []SecretFile
+
+	// Services are auxiliary containers which start before the workload and are
+	// removed with it, such as a database the MCP server depends on
+	Services []ServiceContainer
+}
+
+// SecretFile is a secret which is mounted into a workload as a read-only file.
+type SecretFile struct {
+	// Name is the name of the secret
+	Name string
+	// Path is the absolute path of the file in the container
+	Path string
+	// Mode is the permission mode of the file
+	Mode os.FileMode
+	// Value is the content of the file
+	Value string
 }
 
 // PortBinding represents a host port binding
@@ -304,6 +377,28 @@ func IsKubernetesRuntimeWithEnv(envReader env.Reader) bool {
 	return envReader.Getenv("KUBERNETES_SERVICE_HOST") != ""
 }
 
+// IsProcessRuntime checks if the process runtime was selected through the
+// TOOLHIVE_RUNTIME environment variable
+func IsProcessRuntime() bool {
+	return IsProcessRuntimeWithEnv(&env.OSReader{})
+}
+
+// IsProcessRuntimeWithEnv checks if the process runtime was selected using the provided environment reader.
+func IsProcessRuntimeWithEnv(envReader env.Reader) bool {
+	return strings.TrimSpace(envReader.Getenv("TOOLHIVE_RUNTIME")) == string(TypeProcess)
+}
+
+// IsContainerdRuntime checks if the containerd runtime was selected through the
+// TOOLHIVE_RUNTIME environment variable
+func IsContainerdRuntime() bool {
+	return IsContainerdRuntimeWithEnv(&env.OSReader{})
+}
+
+// IsContainerdRuntimeWithEnv checks if the containerd runtime was selected using the provided environment reader.
+func IsContainerdRuntimeWithEnv(envReader env.Reader) bool {
+	return strings.TrimSpace(envReader.Getenv("TOOLHIVE_RUNTIME")) == string(TypeContainerd)
+}
+
 // Common errors
 var (
 	// ErrWorkloadNotFound indicates that the specified workload was not found.
@@ -0,0 +1,488 @@
+package sbom
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"debug/buildinfo"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Package URL types
+const (
+	PURLTypeDeb    = "deb"
+	PURLTypeAPK    = "apk"
+	PURLTypePyPI   = "pypi"
+	PURLTypeNPM    = "npm"
+	PURLTypeGolang = "golang"
+	PURLTypeNuGet  = "nuget"
+)
+
+// maxBinarySize is the size of the largest executable that is read to find Go build information
+const maxBinarySize = 256 << 20
+
+// maxManifestSize is the size of the largest package database or manifest that is read
+const maxManifestSize = 64 << 20
+
+var (
+	// pythonMetadataPattern matches the metadata files of installed Python distributions
+	pythonMetadataPattern = regexp.MustCompile(`/(site|dist)-packages/[^/]+\.(dist-info/METADATA|egg-info/PKG-INFO)$`)
+	// npmManifestPattern matches the manifests of packages installed in node_modules
+	npmManifestPattern = regexp.MustCompile(`(^|/)node_modules/(@[^/]+/)?[^/@][^/]*/package\.json$`)
+)
+
+// osRelease identifies the distribution of an image, which is the namespace of its
+// distribution packages
+type osRelease struct {
+	ID        string
+	VersionID string
+}
+
+// distroPackage is a package of the distribution, whose package URL depends on the
+// distribution, which may be found later in the filesystem
+type distroPackage struct {
+	purlType     string
+	name         string
+	version      string
+	architecture string
+	location     string
+}
+
+// cataloger collects the packages found in the files of an image
+type cataloger struct {
+	os         osRelease
+	distro     []distroPackage
+	components []Component
+}
+
+// catalog reads the flattened filesystem of an image as a tar stream and returns the
+// packages installed in it
+func catalog(ctx context.Context, fs io.Reader) ([]Component, error) {
+	c := &cataloger{}
+	tr := tar.NewReader(fs)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := strings.TrimPrefix(path.Clean("/"+header.Name), "/")
+		if err := c.catalogFile(name, header, tr); err != nil {
+			return nil, fmt.Errorf("failed to catalog %s: %w", name, err)
+		}
+	}
+
+	for _, p := range c.distro {
+		c.components = append(c.components, c.distroComponent(p))
+	}
+	return c.components, nil
+}
+
+// catalogFile collects the packages of a file, if it is a package database, a manifest or a Go binary
+func (c *cataloger) catalogFile(name string, header *tar.Header, r io.Reader) error {
+	switch {
+	case name == "etc/os-release" || (name == "usr/lib/os-release" && c.os.ID == ""):
+		return withContent(r, header, func(data []byte) error {
+			c.os = parseOSRelease(data)
+			return nil
+		})
+	case name == "var/lib/dpkg/status" ||
+		(path.Dir(name) == "var/lib/dpkg/status.d" && !strings.HasSuffix(name, ".md5sums")):
+		return withContent(r, header, func(data []byte) error {
+			c.distro = append(c.distro, parseDpkgStatus(data, name)...)
+			return nil
+		})
+	case name == "lib/apk/db/installed":
+		return withContent(r, header, func(data []byte) error {
+			c.distro = append(c.distro, parseAPKInstalled(data, name)...)
+			return nil
+		})
+	case pythonMetadataPattern.MatchString(name):
+		return withContent(r, header, func(data []byte) error {
+			if component, ok := parsePythonMetadata(data, name); ok {
+				c.components = append(c.components, component)
+			}
+			return nil
+		})
+	case npmManifestPattern.MatchString(name):
+		return withContent(r, header, func(data []byte) error {
+			if component, ok := parseNPMManifest(data, name); ok {
+				c.components = append(c.components, component)
+			}
+			return nil
+		})
+	case strings.HasSuffix(name, ".deps.json"):
+		return withContent(r, header, func(data []byte) error {
+			c.components = append(c.components, parseDotnetDeps(data, name)...)
+			return nil
+		})
+	case header.Mode&0111 != 0 && header.Size > 4 && header.Size <= maxBinarySize:
+		return c.catalogBinary(name, r)
+	}
+	return nil
+}
+
+// withContent reads a file and passes its content to fn. Oversized files are skipped.
+func withContent(r io.Reader, header *tar.Header, fn func([]byte) error) error {
+	if header.Size > maxManifestSize {
+		return nil
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return fn(data)
+}
+
+// catalogBinary collects the modules a Go executable was built from
+func (c *cataloger) catalogBinary(name string, r io.Reader) error {
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return err
+	}
+	if !bytes.Equal(magic, []byte("\x7fELF")) {
+		return nil
+	}
+
+	rest, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	info, err := buildinfo.Read(bytes.NewReader(append(magic, rest...)))
+	if err != nil {
+		// Not a Go executable
+		return nil
+	}
+
+	if info.Main.Path != "" {
+		c.components = append(c.components, newComponent(ComponentTypeApplication, info.Main.Path,
+			goVersion(info.Main.Version), golangPURL(info.Main.Path, goVersion(info.Main.Version)), name))
+	}
+	for _, dep := range info.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		c.components = append(c.components, newComponent(ComponentTypeLibrary, dep.Path, dep.Version,
+			golangPURL(dep.Path, dep.Version), name))
+	}
+	c.components = append(c.components, newComponent(ComponentTypeLibrary, "stdlib", info.GoVersion,
+		golangPURL("stdlib", info.GoVersion), name))
+	return nil
+}
+
+// goVersion returns the version of the main module of a Go executable, which is (devel) when
+// it was built from a source tree
+func goVersion(version string) string {
+	if version == "(devel)" {
+		return ""
+	}
+	return version
+}
+
+// newComponent creates a component found in a file
+func newComponent(componentType, name, version, purl, location string) Component {
+	return Component{
+		Type:       componentType,
+		BOMRef:     purl + "#" + location,
+		Name:       name,
+		Version:    version,
+		PURL:       purl,
+		Properties: []Property{{Name: LocationProperty, Value: location}},
+	}
+}
+
+// distroComponent creates the component of a distribution package
+func (c *cataloger) distroComponent(p distroPackage) Component {
+	namespace := c.os.ID
+	if namespace == "" {
+		namespace = map[string]string{PURLTypeDeb: "debian", PURLTypeAPK: "alpine"}[p.purlType]
+	}
+
+	qualifiers := url.Values{}
+	if p.architecture != "" {
+		qualifiers.Set("arch", p.architecture)
+	}
+	if c.os.ID != "" && c.os.VersionID != "" {
+		qualifiers.Set("distro", c.os.ID+"-"+c.os.VersionID)
+	}
+
+	purl := fmt.Sprintf("pkg:%s/%s/%s", p.purlType, purlEscape(namespace), purlEscape(p.name))
+	if p.version != "" {
+		purl += "@" + purlEscape(p.version)
+	}
+	if len(qualifiers) > 0 {
+		purl += "?" + qualifiers.Encode()
+	}
+	return newComponent(ComponentTypeLibrary, p.name, p.version, purl, p.location)
+}
+
+// parseOSRelease parses an os-release file
+func parseOSRelease(data []byte) osRelease {
+	var release osRelease
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"'`)
+		switch key {
+		case "ID":
+			release.ID = value
+		case "VERSION_ID":
+			release.VersionID = value
+		}
+	}
+	return release
+}
+
+// parseControlParagraphs parses the RFC 822 style paragraphs of Debian control files,
+// ignoring continuation lines
+func parseControlParagraphs(data []byte) []map[string]string {
+	var paragraphs []map[string]string
+	current := map[string]string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				paragraphs = append(paragraphs, current)
+				current = map[string]string{}
+			}
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			current[key] = strings.TrimSpace(value)
+		}
+	}
+	if len(current) > 0 {
+		paragraphs = append(paragraphs, current)
+	}
+	return paragraphs
+}
+
+// parseDpkgStatus parses the status database of dpkg
+func parseDpkgStatus(data []byte, location string) []distroPackage {
+	var packages []distroPackage
+	for _, p := range parseControlParagraphs(data) {
+		if p["Package"] == "" {
+			continue
+		}
+		// Removed packages may remain in the database with their configuration files
+		if status, ok := p["Status"]; ok && !strings.HasSuffix(status, " installed") {
+			continue
+		}
+		packages = append(packages, distroPackage{
+			purlType:     PURLTypeDeb,
+			name:         p["Package"],
+			version:      p["Version"],
+			architecture: p["Architecture"],
+			location:     location,
+		})
+	}
+	return packages
+}
+
+// parseAPKInstalled parses the installed database of apk
+func parseAPKInstalled(data []byte, location string) []distroPackage {
+	var packages []distroPackage
+	var current distroPackage
+	flush := func() {
+		if current.name != "" {
+			packages = append(packages, current)
+		}
+		current = distroPackage{}
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if line == "" {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		current.purlType = PURLTypeAPK
+		current.location = location
+		switch key {
+		case "P":
+			current.name = value
+		case "V":
+			current.version = value
+		case "A":
+			current.architecture = value
+		}
+	}
+	flush()
+	return packages
+}
+
+// parsePythonMetadata parses the metadata of an installed Python distribution
+func parsePythonMetadata(data []byte, location string) (Component, bool) {
+	var name, version string
+	for _, line := range strings.Split(string(data), "\n") {
+		// The headers end at the first empty line, which starts the description
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Name":
+			name = strings.TrimSpace(value)
+		case "Version":
+			version = strings.TrimSpace(value)
+		}
+	}
+	if name == "" || version == "" {
+		return Component{}, false
+	}
+
+	purl := fmt.Sprintf("pkg:%s/%s@%s", PURLTypePyPI, purlEscape(NormalizePythonName(name)), purlEscape(version))
+	return newComponent(ComponentTypeLibrary, name, version, purl, path.Dir(location)), true
+}
+
+// pythonNamePattern matches the separators that are equivalent in Python project names
+var pythonNamePattern = regexp.MustCompile(`[-_.]+`)
+
+// NormalizePythonName normalizes a Python project name as specified by PEP 503
+func NormalizePythonName(name string) string {
+	return strings.ToLower(pythonNamePattern.ReplaceAllString(name, "-"))
+}
+
+// parseNPMManifest parses the package.json of an installed npm package
+func parseNPMManifest(data []byte, location string) (Component, bool) {
+	var manifest struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil || manifest.Name == "" || manifest.Version == "" {
+		return Component{}, false
+	}
+
+	purl := "pkg:" + PURLTypeNPM + "/"
+	if scope, name, ok := strings.Cut(manifest.Name, "/"); ok {
+		purl += purlEscape(scope) + "/" + purlEscape(name)
+	} else {
+		purl += purlEscape(manifest.Name)
+	}
+	purl += "@" + purlEscape(manifest.Version)
+	return newComponent(ComponentTypeLibrary, manifest.Name, manifest.Version, purl, path.Dir(location)), true
+}
+
+// parseDotnetDeps parses the dependency manifest of a .NET application
+func parseDotnetDeps(data []byte, location string) []Component {
+	var deps struct {
+		Libraries map[string]struct {
+			Type string `json:"type"`
+		} `json:"libraries"`
+	}
+	if err := json.Unmarshal(data, &deps); err != nil {
+		return nil
+	}
+
+	var components []Component
+	for library, info := range deps.Libraries {
+		// Projects are part of the application itself
+		if info.Type != "package" {
+			continue
+		}
+		name, version, ok := strings.Cut(library, "/")
+		if !ok {
+			continue
+		}
+		purl := fmt.Sprintf("pkg:%s/%s@%s", PURLTypeNuGet, purlEscape(name), purlEscape(version))
+		components = append(components, newComponent(ComponentTypeLibrary, name, version, purl, location))
+	}
+	return components
+}
+
+// golangPURL returns the package URL of a Go module. The path is split in segments, as
+// recommended by the purl specification.
+func golangPURL(modulePath, version string) string {
+	segments := strings.Split(modulePath, "/")
+	for i, segment := range segments {
+		segments[i] = purlEscape(segment)
+	}
+	purl := "pkg:" + PURLTypeGolang + "/" + strings.Join(segments, "/")
+	if version != "" {
+		purl += "@" + purlEscape(version)
+	}
+	return purl
+}
+
+// purlEscape escapes a segment of a package URL
+func purlEscape(s string) string {
+	s = url.PathEscape(s)
+	return strings.NewReplacer("+", "%2B", ":", "%3A", "@", "%40").Replace(s)
+}
+
+// PackageURL is a parsed package URL
+type PackageURL struct {
+	Type      string
+	Namespace string
+	Name      string
+	Version   string
+}
+
+// ParsePackageURL parses the package URL of a component
+func ParsePackageURL(purl string) (PackageURL, error) {
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return PackageURL{}, fmt.Errorf("invalid package URL %q", purl)
+	}
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, _, _ = strings.Cut(rest, "?")
+
+	purlType, rest, ok := strings.Cut(rest, "/")
+	if !ok {
+		return PackageURL{}, fmt.Errorf("invalid package URL %q", purl)
+	}
+
+	var version string
+	if i := strings.LastIndex(rest, "@"); i >= 0 {
+		rest, version = rest[:i], rest[i+1:]
+	}
+
+	segments := strings.Split(rest, "/")
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			return PackageURL{}, fmt.Errorf("invalid package URL %q: %w", purl, err)
+		}
+		segments[i] = unescaped
+	}
+	version, err := url.PathUnescape(version)
+	if err != nil {
+		return PackageURL{}, fmt.Errorf("invalid package URL %q: %w", purl, err)
+	}
+
+	return PackageURL{
+		Type:      purlType,
+		Namespace: strings.Join(segments[:len(segments)-1], "/"),
+		Name:      segments[len(segments)-1],
+		Version:   version,
+	}, nil
+}
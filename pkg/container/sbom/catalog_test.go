@@ -0,0 +1,160 @@
+package sbom
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tarFile is a file of a test layer
+type tarFile struct {
+	name    string
+	content string
+	mode    int64
+}
+
+// newLayerTar returns a tar archive of files
+func newLayerTar(t *testing.T, files []tarFile) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range files {
+		mode := f.mode
+		if mode == 0 {
+			mode = 0644
+		}
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     f.name,
+			Mode:     mode,
+			Size:     int64(len(f.content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	layerTar := newLayerTar(t, []tarFile{
+		{name: "var/lib/dpkg/status", content: "Package: libc6\nStatus: install ok installed\n" +
+			"Architecture: amd64\nVersion: 2.36-9+deb12u4\nDescription: GNU C Library\n continued\n\n" +
+			"Package: removed\nStatus: deinstall ok config-files\nVersion: 1.0\n"},
+		{name: "etc/os-release", content: "ID=debian\nVERSION_ID=\"12\"\n"},
+		{name: "usr/lib/python3/site-packages/mcp_server_fetch-0.6.2.dist-info/METADATA",
+			content: "Metadata-Version: 2.1\nName: mcp_server_fetch\nVersion: 0.6.2\n\nName: not a header\n"},
+		{name: "app/node_modules/@scope/server/package.json", content: `{"name":"@scope/server","version":"1.2.3"}`},
+		{name: "app/node_modules/@scope/server/node_modules/zod/package.json", content: `{"name":"zod","version":"3.23.8"}`},
+		// Manifests of files inside packages are not packages
+		{name: "app/node_modules/zod/lib/package.json", content: `{"name":"lib","version":"0.0.0"}`},
+		{name: "opt/tool/Tool.deps.json", content: `{"libraries":{"Tool/1.0.0":{"type":"project"},
+			"ModelContextProtocol/0.3.0":{"type":"package"}}}`},
+		{name: "usr/bin/script", content: "#!/bin/sh\necho hello\n", mode: 0755},
+	})
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(layerTar)), nil
+	})
+	require.NoError(t, err)
+	img, err := mutate.AppendLayers(empty.Image, layer)
+	require.NoError(t, err)
+
+	doc, err := Generate(context.Background(), img, "example/server:latest")
+	require.NoError(t, err)
+
+	assert.Equal(t, BOMFormat, doc.BOMFormat)
+	assert.Equal(t, "example/server:latest", doc.Metadata.Component.Name)
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, doc.Metadata.Component.Version)
+
+	purls := map[string]string{}
+	for _, c := range doc.Components {
+		purls[c.PURL] = c.Location()
+	}
+	pythonPath := "usr/lib/python3/site-packages/mcp_server_fetch-0.6.2.dist-info"
+	assert.Equal(t, map[string]string{
+		"pkg:deb/debian/libc6@2.36-9%2Bdeb12u4?arch=amd64&distro=debian-12": "var/lib/dpkg/status",
+		"pkg:pypi/mcp-server-fetch@0.6.2":                                   pythonPath,
+		"pkg:npm/%40scope/server@1.2.3":                                     "app/node_modules/@scope/server",
+		"pkg:npm/zod@3.23.8":                                                "app/node_modules/@scope/server/node_modules/zod",
+		"pkg:nuget/ModelContextProtocol@0.3.0":                              "opt/tool/Tool.deps.json",
+	}, purls)
+}
+
+func TestCatalogGoBinary(t *testing.T) {
+	t.Parallel()
+
+	// The test binary is a Go executable with build information
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	content, err := os.ReadFile(executable)
+	require.NoError(t, err)
+	if !bytes.HasPrefix(content, []byte("\x7fELF")) {
+		t.Skip("the test binary is not an ELF executable")
+	}
+
+	layerTar := newLayerTar(t, []tarFile{{name: "app/mcp-server", content: string(content), mode: 0755}})
+	components, err := catalog(context.Background(), bytes.NewReader(layerTar))
+	require.NoError(t, err)
+
+	var modules []string
+	for _, c := range components {
+		assert.Equal(t, "app/mcp-server", c.Location())
+		modules = append(modules, c.Name)
+	}
+	assert.Contains(t, modules, "github.com/stretchr/testify")
+	assert.Contains(t, modules, "stdlib")
+}
+
+func TestParsePackageURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		purl    string
+		want    PackageURL
+		wantErr bool
+	}{
+		{
+			purl: "pkg:npm/%40scope/server@1.2.3",
+			want: PackageURL{Type: "npm", Namespace: "@scope", Name: "server", Version: "1.2.3"},
+		},
+		{
+			purl: "pkg:deb/debian/libc6@2.36-9%2Bdeb12u4?arch=amd64",
+			want: PackageURL{Type: "deb", Namespace: "debian", Name: "libc6", Version: "2.36-9+deb12u4"},
+		},
+		{
+			purl: "pkg:golang/github.com/example/server@v1.0.0",
+			want: PackageURL{Type: "golang", Namespace: "github.com/example", Name: "server", Version: "v1.0.0"},
+		},
+		{
+			purl: "pkg:pypi/fetch",
+			want: PackageURL{Type: "pypi", Name: "fetch"},
+		},
+		{purl: "npm/server@1.0.0", wantErr: true},
+		{purl: "pkg:npm", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.purl, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParsePackageURL(tt.purl)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
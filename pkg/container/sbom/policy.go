@@ -0,0 +1,102 @@
+package sbom
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrPolicyViolation is returned when an image has more vulnerabilities than the policy allows
+var ErrPolicyViolation = errors.New("image violates the vulnerability policy")
+
+// maxReportedFindings is the number of vulnerabilities listed in policy violations
+const maxReportedFindings = 10
+
+// Policy blocks images with vulnerabilities listed in an offline vulnerability database
+type Policy struct {
+	// Database is the path of a directory or zip archive of OSV advisories.
+	// The policy is disabled when it is empty.
+	Database string `yaml:"database,omitempty" json:"database,omitempty"`
+	// Severity is the lowest severity of the vulnerabilities that count against the
+	// threshold. Defaults to critical.
+	Severity string `yaml:"severity,omitempty" json:"severity,omitempty"`
+	// MaxFindings is the number of vulnerabilities of the severity or higher that are tolerated
+	MaxFindings int `yaml:"max_findings,omitempty" json:"max_findings,omitempty"`
+	// Ignore lists the IDs or aliases of accepted vulnerabilities, such as CVE-2024-1234
+	Ignore []string `yaml:"ignore,omitempty" json:"ignore,omitempty"`
+}
+
+// Enabled reports whether the policy checks images
+func (p *Policy) Enabled() bool {
+	return p != nil && p.Database != ""
+}
+
+// Validate checks the settings of the policy
+func (p *Policy) Validate() error {
+	if p.Severity != "" {
+		if _, err := ParseSeverity(p.Severity); err != nil {
+			return err
+		}
+	}
+	if p.MaxFindings < 0 {
+		return fmt.Errorf("max_findings must not be negative")
+	}
+	return nil
+}
+
+// Evaluate checks an SBOM against the vulnerability database of the policy. It returns the
+// vulnerabilities that count against the threshold, and ErrPolicyViolation if there are too many.
+func (p *Policy) Evaluate(doc *Document) ([]Finding, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	db, err := LoadDatabase(p.Database)
+	if err != nil {
+		return nil, err
+	}
+	return p.evaluate(db, doc)
+}
+
+// evaluate checks an SBOM against a vulnerability database
+func (p *Policy) evaluate(db *Database, doc *Document) ([]Finding, error) {
+	minSeverity := SeverityCritical
+	if p.Severity != "" {
+		minSeverity, _ = ParseSeverity(p.Severity)
+	}
+
+	var findings []Finding
+	for _, finding := range db.Match(doc) {
+		if finding.Severity >= minSeverity && !p.ignores(finding.Advisory) {
+			findings = append(findings, finding)
+		}
+	}
+	if len(findings) <= p.MaxFindings {
+		return findings, nil
+	}
+
+	var ids []string
+	for _, finding := range findings {
+		if len(ids) == maxReportedFindings {
+			ids = append(ids, "...")
+			break
+		}
+		ids = append(ids, fmt.Sprintf("%s in %s %s", finding.Advisory.ID, finding.Component.Name, finding.Component.Version))
+	}
+	return findings, fmt.Errorf("%w: %d %s or higher vulnerabilities found, %d allowed: %s",
+		ErrPolicyViolation, len(findings), minSeverity, p.MaxFindings, strings.Join(ids, ", "))
+}
+
+// ignores reports whether an advisory is accepted by the policy
+func (p *Policy) ignores(advisory *Advisory) bool {
+	for _, ignored := range p.Ignore {
+		if strings.EqualFold(ignored, advisory.ID) {
+			return true
+		}
+		for _, alias := range advisory.Aliases {
+			if strings.EqualFold(ignored, alias) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package sbom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyEvaluate(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDatabase(t)
+
+	tests := []struct {
+		name         string
+		policy       Policy
+		wantFindings int
+		wantErr      bool
+	}{
+		{
+			name:         "critical vulnerabilities are blocked by default",
+			policy:       Policy{},
+			wantFindings: 2,
+			wantErr:      true,
+		},
+		{
+			name:         "tolerated vulnerabilities",
+			policy:       Policy{MaxFindings: 2},
+			wantFindings: 2,
+		},
+		{
+			name:         "ignored vulnerabilities by alias",
+			policy:       Policy{Ignore: []string{"cve-2024-0001", "DSA-crit"}},
+			wantFindings: 0,
+		},
+		{
+			name:         "lower severity",
+			policy:       Policy{Severity: "high", MaxFindings: 2},
+			wantFindings: 3,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			findings, err := tt.policy.evaluate(db, testDocument)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrPolicyViolation)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Len(t, findings, tt.wantFindings)
+		})
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, (&Policy{Severity: "Medium"}).Validate())
+	assert.Error(t, (&Policy{Severity: "urgent"}).Validate())
+	assert.Error(t, (&Policy{MaxFindings: -1}).Validate())
+	assert.False(t, (&Policy{}).Enabled())
+	assert.True(t, (&Policy{Database: "/var/lib/osv"}).Enabled())
+}
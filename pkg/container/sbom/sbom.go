@@ -0,0 +1,193 @@
+// Package sbom generates software bills of materials (SBOMs) for the images of MCP servers,
+// and checks the packages in them against an offline vulnerability database.
+// SBOMs are CycloneDX documents, which identify packages by their package URL (purl).
+package sbom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/uuid"
+
+	"github.com/stacklok/toolhive/pkg/container/images"
+	"github.com/stacklok/toolhive/pkg/versions"
+)
+
+// CycloneDX constants
+const (
+	// BOMFormat is the format of CycloneDX documents
+	BOMFormat = "CycloneDX"
+	// SpecVersion is the version of the CycloneDX specification of the documents
+	SpecVersion = "1.5"
+)
+
+// Component types
+const (
+	ComponentTypeContainer   = "container"
+	ComponentTypeApplication = "application"
+	ComponentTypeLibrary     = "library"
+)
+
+// LocationProperty is the name of the property which contains the path of the file a
+// component was found in
+const LocationProperty = "toolhive:location"
+
+// Document is a CycloneDX SBOM
+type Document struct {
+	BOMFormat    string      `json:"bomFormat"`
+	SpecVersion  string      `json:"specVersion"`
+	SerialNumber string      `json:"serialNumber"`
+	Version      int         `json:"version"`
+	Metadata     Metadata    `json:"metadata"`
+	Components   []Component `json:"components"`
+}
+
+// Metadata describes the image an SBOM was generated for
+type Metadata struct {
+	Timestamp string     `json:"timestamp"`
+	Tools     Tools      `json:"tools"`
+	Component *Component `json:"component,omitempty"`
+}
+
+// Tools lists the tools that generated an SBOM
+type Tools struct {
+	Components []Component `json:"components"`
+}
+
+// Component is a package, or the image itself in the metadata of a document
+type Component struct {
+	Type       string     `json:"type"`
+	BOMRef     string     `json:"bom-ref,omitempty"`
+	Name       string     `json:"name"`
+	Version    string     `json:"version,omitempty"`
+	PURL       string     `json:"purl,omitempty"`
+	Properties []Property `json:"properties,omitempty"`
+}
+
+// Property is a name-value pair of a component
+type Property struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Location returns the path of the file the component was found in
+func (c *Component) Location() string {
+	for _, p := range c.Properties {
+		if p.Name == LocationProperty {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// ErrUnsupported is returned when the image manager of the runtime cannot read local images
+var ErrUnsupported = errors.New("SBOM generation is not supported by the container runtime")
+
+// GenerateForImage catalogs the packages installed in a local image of an image manager
+func GenerateForImage(ctx context.Context, imageManager images.ImageManager, imageName string) (*Document, error) {
+	saver, ok := imageManager.(images.ImageSaver)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+
+	// Images are read several times, so they are saved instead of being kept in memory
+	archive, err := os.CreateTemp("", "toolhive-sbom-*.tar")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(archive.Name())
+
+	err = saver.SaveImage(ctx, imageName, archive)
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := tarball.ImageFromPath(archive.Name(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image %s: %w", imageName, err)
+	}
+	return Generate(ctx, img, imageName)
+}
+
+// Generate catalogs the packages installed in an image. The name of the image is recorded
+// in the document along with its ID, which is the digest of its configuration.
+func Generate(ctx context.Context, img v1.Image, imageName string) (*Document, error) {
+	id, err := img.ConfigName()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ID of %s: %w", imageName, err)
+	}
+
+	// The flattened filesystem of the image, with deleted files removed
+	fs := mutate.Extract(img)
+	defer fs.Close()
+
+	components, err := catalog(ctx, fs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to catalog packages of %s: %w", imageName, err)
+	}
+
+	return newDocument(imageName, id.String(), components), nil
+}
+
+// newDocument creates a document for the components of an image
+func newDocument(imageName, imageID string, components []Component) *Document {
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].PURL != components[j].PURL {
+			return components[i].PURL < components[j].PURL
+		}
+		return components[i].Location() < components[j].Location()
+	})
+
+	return &Document{
+		BOMFormat:    BOMFormat,
+		SpecVersion:  SpecVersion,
+		SerialNumber: "urn:uuid:" + uuid.NewString(),
+		Version:      1,
+		Metadata: Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Tools: Tools{Components: []Component{{
+				Type:    ComponentTypeApplication,
+				Name:    "toolhive",
+				Version: versions.GetVersionInfo().Version,
+			}}},
+			Component: &Component{
+				Type:    ComponentTypeContainer,
+				BOMRef:  imageName,
+				Name:    imageName,
+				Version: imageID,
+			},
+		},
+		Components: components,
+	}
+}
+
+// Write encodes the document as indented JSON
+func (d *Document) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(d)
+}
+
+// Read decodes a document
+func Read(r io.Reader) (*Document, error) {
+	var doc Document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode SBOM: %w", err)
+	}
+	if doc.BOMFormat != BOMFormat {
+		return nil, fmt.Errorf("unsupported SBOM format %q", doc.BOMFormat)
+	}
+	return &doc, nil
+}
@@ -0,0 +1,65 @@
+package sbom
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stacklok/toolhive/pkg/state"
+)
+
+// Save stores the SBOM of the image of a workload, next to its run configuration
+func Save(ctx context.Context, workloadName string, doc *Document) error {
+	store, err := state.NewSBOMStore(state.DefaultAppName)
+	if err != nil {
+		return fmt.Errorf("failed to create SBOM store: %w", err)
+	}
+
+	writer, err := store.GetWriter(ctx, workloadName)
+	if err != nil {
+		return fmt.Errorf("failed to get writer for SBOM: %w", err)
+	}
+	defer writer.Close()
+
+	if err := doc.Write(writer); err != nil {
+		return fmt.Errorf("failed to write SBOM: %w", err)
+	}
+	return nil
+}
+
+// Load returns the stored SBOM of the image of a workload
+func Load(ctx context.Context, workloadName string) (*Document, error) {
+	store, err := state.NewSBOMStore(state.DefaultAppName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SBOM store: %w", err)
+	}
+
+	exists, err := store.Exists(ctx, workloadName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check if SBOM exists: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("no SBOM found for workload %s", workloadName)
+	}
+
+	reader, err := store.GetReader(ctx, workloadName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reader for SBOM: %w", err)
+	}
+	defer reader.Close()
+
+	return Read(reader)
+}
+
+// Delete removes the stored SBOM of a workload, if there is one
+func Delete(ctx context.Context, workloadName string) error {
+	store, err := state.NewSBOMStore(state.DefaultAppName)
+	if err != nil {
+		return fmt.Errorf("failed to create SBOM store: %w", err)
+	}
+
+	exists, err := store.Exists(ctx, workloadName)
+	if err != nil || !exists {
+		return err
+	}
+	return store.Delete(ctx, workloadName)
+}
@@ -0,0 +1,401 @@
+package sbom
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/mod/semver"
+)
+
+// Severity is the severity of a vulnerability
+type Severity int
+
+// Severities, in increasing order
+const (
+	SeverityUnknown Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+// String returns the name of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityLow:
+		return "low"
+	case SeverityMedium:
+		return "medium"
+	case SeverityHigh:
+		return "high"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseSeverity parses the name of a severity, as used in policies and advisories
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(s) {
+	case "low":
+		return SeverityLow, nil
+	case "medium", "moderate":
+		return SeverityMedium, nil
+	case "high":
+		return SeverityHigh, nil
+	case "critical":
+		return SeverityCritical, nil
+	default:
+		return SeverityUnknown, fmt.Errorf("invalid severity %q (valid severities: low, medium, high, critical)", s)
+	}
+}
+
+// severityFromScore returns the qualitative severity of a CVSS score
+func severityFromScore(score float64) Severity {
+	switch {
+	case score >= 9.0:
+		return SeverityCritical
+	case score >= 7.0:
+		return SeverityHigh
+	case score >= 4.0:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	default:
+		return SeverityUnknown
+	}
+}
+
+// purlEcosystems maps the types of package URLs to OSV ecosystems
+var purlEcosystems = map[string]string{
+	PURLTypeDeb:    "Debian",
+	PURLTypeAPK:    "Alpine",
+	PURLTypePyPI:   "PyPI",
+	PURLTypeNPM:    "npm",
+	PURLTypeGolang: "Go",
+	PURLTypeNuGet:  "NuGet",
+	"cargo":        "crates.io",
+}
+
+// Advisory is a vulnerability in the OSV format, as published by osv.dev
+type Advisory struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Summary  string   `json:"summary,omitempty"`
+	Severity []struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	} `json:"severity,omitempty"`
+	Affected []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string `json:"type"`
+			Events []struct {
+				Introduced   string `json:"introduced,omitempty"`
+				Fixed        string `json:"fixed,omitempty"`
+				LastAffected string `json:"last_affected,omitempty"`
+			} `json:"events"`
+		} `json:"ranges,omitempty"`
+		Versions []string `json:"versions,omitempty"`
+	} `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity,omitempty"`
+	} `json:"database_specific,omitempty"`
+	Withdrawn string `json:"withdrawn,omitempty"`
+}
+
+// SeverityLevel returns the severity of the advisory, from its database specific severity
+// or its highest CVSS v3 score
+func (a *Advisory) SeverityLevel() Severity {
+	if s, err := ParseSeverity(a.DatabaseSpecific.Severity); err == nil {
+		return s
+	}
+
+	severity := SeverityUnknown
+	for _, s := range a.Severity {
+		if s.Type != "CVSS_V3" {
+			continue
+		}
+		if score, err := CVSS3BaseScore(s.Score); err == nil && severityFromScore(score) > severity {
+			severity = severityFromScore(score)
+		}
+	}
+	return severity
+}
+
+// Database is an offline vulnerability database of OSV advisories
+type Database struct {
+	// advisories are indexed by ecosystem and package name
+	advisories map[string][]*Advisory
+}
+
+// LoadDatabase loads the OSV advisories of a directory of JSON files, or of a zip
+// archive such as the ecosystem exports of osv.dev
+func LoadDatabase(path string) (*Database, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vulnerability database: %w", err)
+	}
+
+	db := &Database{advisories: map[string][]*Advisory{}}
+	if info.IsDir() {
+		err = filepath.WalkDir(path, func(name string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || filepath.Ext(name) != ".json" {
+				return err
+			}
+			// #nosec G304 -- the database is chosen by the user
+			file, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			return db.add(file, name)
+		})
+	} else {
+		err = db.loadZip(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load vulnerability database %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// loadZip loads the advisories of a zip archive
+func (db *Database) loadZip(path string) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		if filepath.Ext(file.Name) != ".json" {
+			continue
+		}
+		r, err := file.Open()
+		if err != nil {
+			return err
+		}
+		err = db.add(r, file.Name)
+		_ = r.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add adds an advisory to the database
+func (db *Database) add(r io.Reader, name string) error {
+	var advisory Advisory
+	if err := json.NewDecoder(r).Decode(&advisory); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	if advisory.Withdrawn != "" {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	for _, affected := range advisory.Affected {
+		key := advisoryKey(affected.Package.Ecosystem, affected.Package.Name)
+		if !seen[key] {
+			db.advisories[key] = append(db.advisories[key], &advisory)
+			seen[key] = true
+		}
+	}
+	return nil
+}
+
+// advisoryKey returns the key of the advisories of a package. The release of the distribution
+// in ecosystems such as Debian:12 is ignored.
+func advisoryKey(ecosystem, name string) string {
+	ecosystem, _, _ = strings.Cut(ecosystem, ":")
+	if ecosystem == "PyPI" {
+		name = NormalizePythonName(name)
+	}
+	return ecosystem + "/" + name
+}
+
+// Finding is a vulnerability affecting a component
+type Finding struct {
+	Advisory  *Advisory
+	Component Component
+	Severity  Severity
+}
+
+// Match returns the vulnerabilities affecting the components of a document
+func (db *Database) Match(doc *Document) []Finding {
+	var findings []Finding
+	for _, component := range doc.Components {
+		purl, err := ParsePackageURL(component.PURL)
+		if err != nil || purl.Version == "" {
+			continue
+		}
+		ecosystem, ok := purlEcosystems[purl.Type]
+		if !ok {
+			continue
+		}
+
+		name := purl.Name
+		if purl.Namespace != "" && (purl.Type == PURLTypeNPM || purl.Type == PURLTypeGolang) {
+			name = purl.Namespace + "/" + purl.Name
+		}
+
+		for _, advisory := range db.advisories[advisoryKey(ecosystem, name)] {
+			if advisory.affects(ecosystem, name, purl.Version) {
+				findings = append(findings, Finding{Advisory: advisory, Component: component, Severity: advisory.SeverityLevel()})
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Severity != findings[j].Severity {
+			return findings[i].Severity > findings[j].Severity
+		}
+		return findings[i].Advisory.ID < findings[j].Advisory.ID
+	})
+	return findings
+}
+
+// affects reports whether a version of a package is affected by the advisory
+func (a *Advisory) affects(ecosystem, name, version string) bool {
+	for _, affected := range a.Affected {
+		if advisoryKey(affected.Package.Ecosystem, affected.Package.Name) != advisoryKey(ecosystem, name) {
+			continue
+		}
+		for _, v := range affected.Versions {
+			if v == version {
+				return true
+			}
+		}
+		for _, r := range affected.Ranges {
+			if r.Type == "GIT" {
+				continue
+			}
+
+			// Events are ordered; the version is affected after an introduced event
+			// until a fixed or last affected event
+			inRange := false
+			for _, event := range r.Events {
+				switch {
+				case event.Introduced != "":
+					if event.Introduced == "0" || compareVersions(r.Type, version, event.Introduced) >= 0 {
+						inRange = true
+					}
+				case event.Fixed != "":
+					if compareVersions(r.Type, version, event.Fixed) >= 0 {
+						inRange = false
+					}
+				case event.LastAffected != "":
+					if compareVersions(r.Type, version, event.LastAffected) > 0 {
+						inRange = false
+					}
+				}
+			}
+			if inRange {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compareVersions compares two versions. Semantic versions are compared as specified by
+// semver; other versions are compared by their numeric and alphabetic parts, which is an
+// approximation of the version ordering of most ecosystems.
+func compareVersions(rangeType, a, b string) int {
+	if rangeType == "SEMVER" {
+		va, vb := canonicalSemver(a), canonicalSemver(b)
+		if semver.IsValid(va) && semver.IsValid(vb) {
+			return semver.Compare(va, vb)
+		}
+	}
+	return compareVersionParts(versionParts(a), versionParts(b))
+}
+
+// canonicalSemver adds the v prefix that the semver package requires. Go toolchain
+// versions such as go1.22.1 are also accepted.
+func canonicalSemver(v string) string {
+	v = strings.TrimPrefix(v, "go")
+	return "v" + strings.TrimPrefix(v, "v")
+}
+
+// versionParts splits a version into runs of digits and runs of letters
+func versionParts(v string) []string {
+	var parts []string
+	current := ""
+	for _, r := range v {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			if current != "" {
+				parts = append(parts, current)
+			}
+			current = ""
+		case current != "" && unicode.IsDigit(r) != unicode.IsDigit(rune(current[0])):
+			parts = append(parts, current)
+			current = string(r)
+		default:
+			current += string(r)
+		}
+	}
+	if current != "" {
+		parts = append(parts, current)
+	}
+	return parts
+}
+
+// compareVersionParts compares the parts of two versions. Numbers are greater than letters,
+// so that 1.0.1 is greater than 1.0rc1. Missing parts are less than numbers and greater
+// than letters, so that 1.0 is between 1.0rc1 and 1.0.1.
+func compareVersionParts(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		switch {
+		case i >= len(a):
+			return -compareMissingPart(b[i])
+		case i >= len(b):
+			return compareMissingPart(a[i])
+		}
+
+		na, errA := strconv.Atoi(a[i])
+		nb, errB := strconv.Atoi(b[i])
+		switch {
+		case errA == nil && errB == nil:
+			if na != nb {
+				if na < nb {
+					return -1
+				}
+				return 1
+			}
+		case errA == nil:
+			return 1
+		case errB == nil:
+			return -1
+		default:
+			if c := strings.Compare(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// compareMissingPart compares a part of a version with the missing part of another version
+func compareMissingPart(part string) int {
+	if _, err := strconv.Atoi(part); err == nil {
+		return 1
+	}
+	return -1
+}
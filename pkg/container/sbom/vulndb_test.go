@@ -0,0 +1,147 @@
+package sbom
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAdvisories are OSV advisories of a test vulnerability database
+var testAdvisories = map[string]string{
+	"GHSA-crit.json": `{"id":"GHSA-crit","aliases":["CVE-2024-0001"],"summary":"Remote code execution",
+		"affected":[{"package":{"ecosystem":"npm","name":"@scope/server"},
+			"ranges":[{"type":"SEMVER","events":[{"introduced":"0"},{"fixed":"1.2.4"}]}]}],
+		"database_specific":{"severity":"CRITICAL"}}`,
+	"GHSA-fixed.json": `{"id":"GHSA-fixed","affected":[{"package":{"ecosystem":"npm","name":"@scope/server"},
+			"ranges":[{"type":"SEMVER","events":[{"introduced":"0"},{"fixed":"1.0.0"}]}]}],
+		"database_specific":{"severity":"CRITICAL"}}`,
+	"PYSEC-high.json": `{"id":"PYSEC-high","affected":[{"package":{"ecosystem":"PyPI","name":"MCP_Server_Fetch"},
+			"versions":["0.6.2"]}],
+		"severity":[{"type":"CVSS_V3","score":"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N"}]}`,
+	"DSA-crit.json": `{"id":"DSA-crit","affected":[{"package":{"ecosystem":"Debian:12","name":"libc6"},
+			"ranges":[{"type":"ECOSYSTEM","events":[{"introduced":"0"},{"fixed":"2.36-9+deb12u7"}]}]}],
+		"severity":[{"type":"CVSS_V3","score":"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}]}`,
+	"GO-withdrawn.json": `{"id":"GO-withdrawn","withdrawn":"2024-01-01T00:00:00Z",
+		"affected":[{"package":{"ecosystem":"Go","name":"stdlib"},
+			"ranges":[{"type":"SEMVER","events":[{"introduced":"0"}]}]}]}`,
+}
+
+// testDocument is an SBOM with packages affected by the test advisories
+var testDocument = &Document{Components: []Component{
+	{Name: "@scope/server", Version: "1.2.3", PURL: "pkg:npm/%40scope/server@1.2.3"},
+	{Name: "mcp_server_fetch", Version: "0.6.2", PURL: "pkg:pypi/mcp-server-fetch@0.6.2"},
+	{Name: "libc6", Version: "2.36-9+deb12u4", PURL: "pkg:deb/debian/libc6@2.36-9%2Bdeb12u4?arch=amd64"},
+	{Name: "stdlib", Version: "go1.22.1", PURL: "pkg:golang/stdlib@go1.22.1"},
+}}
+
+// newTestDatabase writes the test advisories to a directory and loads them
+func newTestDatabase(t *testing.T) *Database {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range testAdvisories {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	db, err := LoadDatabase(dir)
+	require.NoError(t, err)
+	return db
+}
+
+func TestDatabaseMatch(t *testing.T) {
+	t.Parallel()
+
+	findings := newTestDatabase(t).Match(testDocument)
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Advisory.ID+" "+f.Severity.String()+" "+f.Component.Name)
+	}
+	assert.Equal(t, []string{
+		"DSA-crit critical libc6",
+		"GHSA-crit critical @scope/server",
+		"PYSEC-high high mcp_server_fetch",
+	}, got)
+}
+
+func TestLoadDatabaseZip(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "all.zip")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	zw := zip.NewWriter(file)
+	for name, content := range testAdvisories {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, file.Close())
+
+	db, err := LoadDatabase(path)
+	require.NoError(t, err)
+	assert.Len(t, db.Match(testDocument), 3)
+}
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		rangeType string
+		a, b      string
+		want      int
+	}{
+		{"SEMVER", "1.2.3", "1.2.4", -1},
+		{"SEMVER", "v1.10.0", "1.9.0", 1},
+		{"SEMVER", "go1.22.1", "1.22.1", 0},
+		{"SEMVER", "1.0.0-rc.1", "1.0.0", -1},
+		{"ECOSYSTEM", "2.36-9+deb12u4", "2.36-9+deb12u7", -1},
+		{"ECOSYSTEM", "1.0rc1", "1.0", -1},
+		{"ECOSYSTEM", "1.0.1", "1.0", 1},
+		{"ECOSYSTEM", "1.10", "1.9", 1},
+		{"ECOSYSTEM", "1.0", "1.0", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, compareVersions(tt.rangeType, tt.a, tt.b))
+		})
+	}
+}
+
+func TestCVSS3BaseScore(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		vector  string
+		want    float64
+		wantErr bool
+	}{
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", want: 9.8},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H", want: 9.9},
+		{vector: "CVSS:3.0/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:N/A:N", want: 5.5},
+		{vector: "CVSS:3.1/AV:N/AC:H/PR:N/UI:R/S:U/C:L/I:N/A:N", want: 3.1},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", want: 0},
+		{vector: "AV:N/AC:L/Au:N/C:P/I:P/A:P", wantErr: true},
+		{vector: "CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.vector, func(t *testing.T) {
+			t.Parallel()
+			got, err := CVSS3BaseScore(tt.vector)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 0.001)
+		})
+	}
+}
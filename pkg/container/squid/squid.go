@@ -0,0 +1,226 @@
+// Package squid generates the configuration of the squid proxies which enforce the
+// network permissions of MCP servers.
+package squid
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+// DefaultImage is the default image of the squid proxy
+const DefaultImage = "ghcr.io/stacklok/toolhive/egress-proxy:latest"
+
+// EgressPort is the port the egress proxy listens on
+const EgressPort = 3128
+
+// Direction is the direction of the traffic handled by a proxy
+type Direction int
+
+const (
+	// Ingress is a reverse proxy for the traffic to the MCP server
+	Ingress Direction = iota
+	// Egress is a forward proxy for the outbound traffic of the MCP server
+	Egress
+)
+
+// EgressConfig returns the configuration of the egress proxy of a server, which allows the
+// outbound connections permitted by the network permissions. Deny rules take precedence over
+// all allow rules, and all connections are allowed if networkPermissions is nil.
+func EgressConfig(networkPermissions *permissions.NetworkPermissions, serverHostname string) (string, error) {
+	var sb strings.Builder
+
+	WriteCommonConfig(&sb, serverHostname, Egress)
+
+	var outbound *permissions.OutboundNetworkPermissions
+	if networkPermissions != nil {
+		outbound = networkPermissions.Outbound
+	}
+	if err := outbound.Validate(); err != nil {
+		return "", fmt.Errorf("invalid outbound network permissions: %w", err)
+	}
+
+	// Deny rules take precedence over all allow rules
+	writeDenyRules(&sb, outbound)
+
+	if networkPermissions == nil || (outbound != nil && outbound.InsecureAllowAll) {
+		sb.WriteString("# Allow all traffic\nhttp_access allow all\n")
+	} else if outbound != nil {
+		writeOutboundACLs(&sb, outbound)
+		writeHttpAccessRules(&sb, outbound)
+	}
+
+	sb.WriteString("http_access deny all\n")
+	return sb.String(), nil
+}
+
+// WriteCommonConfig writes the configuration shared by the ingress and egress proxies of a server
+func WriteCommonConfig(sb *strings.Builder, hostnameBase string, direction Direction) {
+	var serverHostname string
+
+	if direction == Egress {
+		serverHostname = hostnameBase + "-egress"
+		sb.WriteString("http_port " + strconv.Itoa(EgressPort) + "\n")
+	} else {
+		serverHostname = hostnameBase + "-ingress"
+	}
+
+	sb.WriteString(
+		"visible_hostname " + serverHostname + "\n" +
+			"access_log stdio:/dev/stdout squid\n" +
+			"pid_filename /tmp/squid.pid\n" +
+			"# Avoid allocation errors caused by max_filedescriptors inference\n" +
+			"max_filedescriptors 1024\n" +
+			"# Disable memory and disk caching\n" +
+			"cache deny all\n" +
+			"cache_mem 0 MB\n" +
+			"maximum_object_size 0 KB\n" +
+			"maximum_object_size_in_memory 0 KB\n" +
+			"# Don't use cache directories\n" +
+			"cache_store_log none\n\n")
+}
+
+// writeDenyRules writes the ACLs and http_access rules of the denied destinations
+func writeDenyRules(sb *strings.Builder, outbound *permissions.OutboundNetworkPermissions) {
+	if outbound == nil || (len(outbound.DenyHost) == 0 && len(outbound.DenyCIDR) == 0) {
+		return
+	}
+
+	sb.WriteString("# Define denied destinations\n")
+	if len(outbound.DenyHost) > 0 {
+		sb.WriteString("acl denied_dsts dstdomain " + strings.Join(squidDomains(outbound.DenyHost), " ") + "\n")
+	}
+	if len(outbound.DenyCIDR) > 0 {
+		sb.WriteString("acl denied_cidrs dst " + strings.Join(outbound.DenyCIDR, " ") + "\n")
+	}
+	if len(outbound.DenyHost) > 0 {
+		sb.WriteString("http_access deny denied_dsts\n")
+	}
+	if len(outbound.DenyCIDR) > 0 {
+		sb.WriteString("http_access deny denied_cidrs\n")
+	}
+	sb.WriteString("\n")
+}
+
+func writeOutboundACLs(sb *strings.Builder, outbound *permissions.OutboundNetworkPermissions) {
+	if len(outbound.AllowPort) > 0 {
+		sb.WriteString("# Define allowed ports\nacl allowed_ports port")
+		for _, port := range outbound.AllowPort {
+			sb.WriteString(" " + strconv.Itoa(port))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(outbound.AllowHost) > 0 {
+		sb.WriteString("# Define allowed destinations\nacl allowed_dsts dstdomain")
+		for _, host := range squidDomains(outbound.AllowHost) {
+			sb.WriteString(" " + host)
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(outbound.AllowCIDR) > 0 {
+		sb.WriteString("# Define allowed IP ranges\nacl allowed_cidrs dst " + strings.Join(outbound.AllowCIDR, " ") + "\n")
+	}
+
+	for i, hostPort := range outbound.AllowHostPort {
+		// Host and port pairs are validated before the configuration is written
+		host, port, _ := permissions.ParseHostPort(hostPort)
+		aclType := "dstdomain"
+		if net.ParseIP(host) != nil {
+			aclType = "dst"
+		} else {
+			host = squidDomains([]string{host})[0]
+		}
+		name := fmt.Sprintf("allowed_host_port_%d", i)
+		if i == 0 {
+			sb.WriteString("# Define allowed host and port pairs\n")
+		}
+		sb.WriteString("acl " + name + "_dst " + aclType + " " + host + "\n")
+		sb.WriteString("acl " + name + "_port port " + strconv.Itoa(port) + "\n")
+	}
+}
+
+func writeHttpAccessRules(sb *strings.Builder, outbound *permissions.OutboundNetworkPermissions) {
+	var rules []string
+
+	// Destinations are only allowed on the allowed ports, if any are set
+	var portCondition string
+	if len(outbound.AllowPort) > 0 {
+		portCondition = "allowed_ports "
+	}
+	if len(outbound.AllowHost) > 0 {
+		rules = append(rules, portCondition+"allowed_dsts")
+	}
+	if len(outbound.AllowCIDR) > 0 {
+		rules = append(rules, portCondition+"allowed_cidrs")
+	}
+	if len(rules) == 0 && portCondition != "" {
+		rules = append(rules, strings.TrimSpace(portCondition))
+	}
+	for i := range outbound.AllowHostPort {
+		name := fmt.Sprintf("allowed_host_port_%d", i)
+		rules = append(rules, name+"_dst "+name+"_port")
+	}
+
+	if len(rules) > 0 {
+		sb.WriteString("\n# Define http_access rules\n")
+		for _, rule := range rules {
+			sb.WriteString("http_access allow " + rule + "\n")
+		}
+	}
+}
+
+// squidDomains converts hosts to the domains of a squid dstdomain ACL, where wildcards
+// are written with a leading dot. Squid rejects domains which are already matched by a
+// wildcard of the same ACL, so these are removed.
+func squidDomains(hosts []string) []string {
+	var wildcards []string
+	for _, host := range hosts {
+		if permissions.IsWildcardHost(host) {
+			wildcards = append(wildcards, strings.ToLower(permissions.WildcardDomain(host)))
+		}
+	}
+
+	coveredByWildcard := func(domain string, self bool) bool {
+		for _, wildcard := range wildcards {
+			if strings.HasSuffix(domain, "."+wildcard) || (!self && domain == wildcard) {
+				return true
+			}
+		}
+		return false
+	}
+
+	domains := make([]string, 0, len(hosts))
+	seen := map[string]bool{}
+	for _, host := range hosts {
+		domain := strings.ToLower(host)
+		wildcard := permissions.IsWildcardHost(host)
+		if wildcard {
+			domain = strings.ToLower(permissions.WildcardDomain(host))
+		}
+		if coveredByWildcard(domain, wildcard) {
+			continue
+		}
+		if wildcard {
+			domain = "." + domain
+		}
+		if !seen[domain] {
+			seen[domain] = true
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+// Image returns the image of the squid proxy, which can be overridden with TOOLHIVE_EGRESS_IMAGE
+func Image() string {
+	if egressImage := os.Getenv("TOOLHIVE_EGRESS_IMAGE"); egressImage != "" {
+		return egressImage
+	}
+	return DefaultImage
+}
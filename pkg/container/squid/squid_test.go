@@ -0,0 +1,62 @@
+package squid
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+func TestEgressConfig(t *testing.T) {
+	t.Parallel()
+
+	config, err := EgressConfig(&permissions.NetworkPermissions{
+		Outbound: &permissions.OutboundNetworkPermissions{
+			AllowPort: []int{443},
+			AllowHost: []string{"api.github.com"},
+		},
+	}, "server")
+	require.NoError(t, err)
+	assert.Contains(t, config, "visible_hostname server-egress\n")
+	assert.Contains(t, config, "http_port 3128\n")
+	assert.Contains(t, config, "http_access allow allowed_ports allowed_dsts\n")
+	assert.True(t, strings.HasSuffix(config, "http_access deny all\n"))
+
+	_, err = EgressConfig(&permissions.NetworkPermissions{
+		Outbound: &permissions.OutboundNetworkPermissions{AllowHost: []string{"not a host"}},
+	}, "server")
+	assert.Error(t, err)
+}
+
+func TestSquidDomains(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{".example.com", "github.com"},
+		squidDomains([]string{"*.example.com", "Example.com", "api.example.com", ".a.example.com", "github.com", "github.com"}))
+	assert.Empty(t, squidDomains(nil))
+}
+
+func TestImage(t *testing.T) {
+	t.Parallel()
+
+	// Save and restore env
+	orig, had := os.LookupEnv("TOOLHIVE_EGRESS_IMAGE")
+	if had {
+		t.Cleanup(func() { _ = os.Setenv("TOOLHIVE_EGRESS_IMAGE", orig) })
+	} else {
+		t.Cleanup(func() { _ = os.Unsetenv("TOOLHIVE_EGRESS_IMAGE") })
+	}
+
+	// Default
+	_ = os.Unsetenv("TOOLHIVE_EGRESS_IMAGE")
+	assert.Equal(t, "ghcr.io/stacklok/toolhive/egress-proxy:latest", Image())
+
+	// Override
+	override := "ghcr.io/example/custom-squid:1.2.3"
+	_ = os.Setenv("TOOLHIVE_EGRESS_IMAGE", override)
+	assert.Equal(t, override, Image())
+}
@@ -1,5 +1,5 @@
 // Package templates provides utilities for generating Dockerfile templates
-// based on different transport types (uvx, npx).
+// based on different transport types (uvx, npx, go, pipx, cargo, dotnet).
 package templates
 
 import (
@@ -22,6 +22,12 @@ type TemplateData struct {
 	CACertContent string
 	// IsLocalPath indicates if the MCPPackage is a local path that should be copied into the container.
 	IsLocalPath bool
+	// BinName is the executable of a package when it cannot be derived from MCPPackage.
+	// For local Node.js packages it is the path of the bin script relative to the package.
+	BinName string
+	// PackageHash is the pinned hash of the package, in the format of the pins of its
+	// package index. The build fails when the installed package does not match it.
+	PackageHash string
 }
 
 // TransportType represents the type of transport to use.
@@ -34,6 +40,15 @@ const (
 	TransportTypeNPX TransportType = "npx"
 	// TransportTypeGO represents the go transport.
 	TransportTypeGO TransportType = "go"
+	// TransportTypePipx represents the pipx transport.
+	TransportTypePipx TransportType = "pipx"
+	// TransportTypeCargo represents the cargo transport.
+	TransportTypeCargo TransportType = "cargo"
+	// TransportTypeDotnet represents the dotnet transport.
+	TransportTypeDotnet TransportType = "dotnet"
+	// TransportTypeGit represents sources in git repositories. It has no template of its own;
+	// repositories are built with the template of their language.
+	TransportTypeGit TransportType = "git"
 )
 
 // GetDockerfileTemplate returns the Dockerfile template for the specified transport type.
@@ -48,6 +63,12 @@ func GetDockerfileTemplate(transportType TransportType, data TemplateData) (stri
 		templateName = "npx.tmpl"
 	case TransportTypeGO:
 		templateName = "go.tmpl"
+	case TransportTypePipx:
+		templateName = "pipx.tmpl"
+	case TransportTypeCargo:
+		templateName = "cargo.tmpl"
+	case TransportTypeDotnet:
+		templateName = "dotnet.tmpl"
 	default:
 		return "", fmt.Errorf("unsupported transport type: %s", transportType)
 	}
@@ -82,6 +103,12 @@ func ParseTransportType(s string) (TransportType, error) {
 		return TransportTypeNPX, nil
 	case "go":
 		return TransportTypeGO, nil
+	case "pipx":
+		return TransportTypePipx, nil
+	case "cargo":
+		return TransportTypeCargo, nil
+	case "dotnet":
+		return TransportTypeDotnet, nil
 	default:
 		return "", fmt.Errorf("unsupported transport type: %s", s)
 	}
@@ -224,6 +224,195 @@ func TestGetDockerfileTemplate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:          "NPX transport with local path",
+			transportType: TransportTypeNPX,
+			data: TemplateData{
+				MCPPackage:  ".",
+				MCPArgs:     []string{"--stdio"},
+				IsLocalPath: true,
+				BinName:     "dist/index.js",
+			},
+			wantContains: []string{
+				"COPY . /build/",
+				"npm run build --if-present",
+				"npm prune --omit=dev",
+				"ENTRYPOINT [\"node\", \"/app/dist/index.js\", \"--stdio\"]",
+			},
+			wantNotContains: []string{
+				"entrypoint.sh",
+			},
+			wantErr: false,
+		},
+		{
+			name:          "UVX transport with local path",
+			transportType: TransportTypeUVX,
+			data: TemplateData{
+				MCPPackage:  ".",
+				IsLocalPath: true,
+				BinName:     "example-server",
+			},
+			wantContains: []string{
+				"uv pip install --system /build/",
+				"COPY --from=builder --chown=appuser:appgroup /usr/local/bin /usr/local/bin",
+				"package='example-server'",
+			},
+			wantErr: false,
+		},
+		{
+			name:          "PIPX transport",
+			transportType: TransportTypePipx,
+			data: TemplateData{
+				MCPPackage: "example-package@1.2.3",
+				MCPArgs:    []string{"--arg1"},
+			},
+			wantContains: []string{
+				"pip install --no-cache-dir pipx",
+				"package_spec=$(echo \"$package\" | sed 's/@/==/')",
+				"pipx install \"$package_spec\"",
+				"COPY --from=builder --chown=appuser:appgroup /opt/pipx /opt/pipx",
+				"package='example-package@1.2.3'",
+			},
+			wantMatches: []string{
+				`FROM python:\d+\.\d+-slim AS builder`,
+			},
+			wantNotContains: []string{
+				"Add custom CA certificate",
+				"PIP_CERT",
+			},
+			wantErr: false,
+		},
+		{
+			name:          "PIPX transport with CA certificate",
+			transportType: TransportTypePipx,
+			data: TemplateData{
+				MCPPackage:    "example-package",
+				CACertContent: "-----BEGIN CERTIFICATE-----\nMIICertificateContent\n-----END CERTIFICATE-----",
+			},
+			wantContains: []string{
+				"COPY ca-cert.crt /tmp/custom-ca.crt",
+				"update-ca-certificates",
+				"ENV PIP_CERT=/etc/ssl/certs/ca-certificates.crt",
+			},
+			wantErr: false,
+		},
+		{
+			name:          "NPX transport with pinned hash",
+			transportType: TransportTypeNPX,
+			data: TemplateData{
+				MCPPackage:  "@example/package@1.2.3",
+				PackageHash: "sha512-abc==",
+			},
+			wantContains: []string{
+				"RUN npm install --save @example/package@1.2.3",
+				"lock.packages[\"node_modules/\" + process.argv[1]]",
+				"\"${package%@*}\" 'sha512-abc=='",
+			},
+		},
+		{
+			name:          "UVX transport with pinned hash",
+			transportType: TransportTypeUVX,
+			data: TemplateData{
+				MCPPackage:  "example-package@1.0.0",
+				PackageHash: "sha256:0123abcd",
+			},
+			wantContains: []string{
+				"--hash=sha256:0123abcd\" > /tmp/pinned.txt",
+				"pip download --no-deps --require-hashes --requirement /tmp/pinned.txt --dest /tmp/pinned",
+				"uv tool install \"${package%@*} @ file://$(ls /tmp/pinned/*)\"",
+			},
+			wantNotContains: []string{
+				"uv tool install \"$package_spec\"",
+			},
+		},
+		{
+			name:          "GO transport with pinned hash",
+			transportType: TransportTypeGO,
+			data: TemplateData{
+				MCPPackage:  "example.com/server@v1.0.0",
+				PackageHash: "h1:abc=",
+			},
+			wantContains: []string{
+				"go version -m /app/mcp-server",
+				"if [ \"$sum\" != 'h1:abc=' ]; then",
+			},
+		},
+		{
+			name:          "CARGO transport",
+			transportType: TransportTypeCargo,
+			data: TemplateData{
+				MCPPackage: "example-crate@0.4.0",
+				MCPArgs:    []string{"--arg1", "value"},
+			},
+			wantContains: []string{
+				"cargo install --locked --root /opt/cargo-install --version \"${package#*@}\" \"$crate\"",
+				"package=\"example-crate@0.4.0\"",
+				"COPY --from=builder --chown=appuser:appgroup /app/mcp-server /app/mcp-server",
+				"ENTRYPOINT [\"/app/mcp-server\", \"--arg1\", \"value\"]",
+			},
+			wantMatches: []string{
+				`FROM rust:\S+ AS builder`,
+				`FROM debian:\S+`,
+			},
+			wantNotContains: []string{
+				"Add custom CA certificate",
+				"COPY . /build/",
+			},
+			wantErr: false,
+		},
+		{
+			name:          "CARGO transport with local path and CA certificate",
+			transportType: TransportTypeCargo,
+			data: TemplateData{
+				MCPPackage:    ".",
+				IsLocalPath:   true,
+				BinName:       "example-server",
+				CACertContent: "-----BEGIN CERTIFICATE-----\nMIICertificateContent\n-----END CERTIFICATE-----",
+			},
+			wantContains: []string{
+				"COPY . /build/",
+				"cargo install --locked --path . --root /opt/cargo-install",
+				"package=\"example-server\"",
+				"COPY ca-cert.crt /tmp/custom-ca.crt",
+				"update-ca-certificates",
+			},
+			wantErr: false,
+		},
+		{
+			name:          "DOTNET transport",
+			transportType: TransportTypeDotnet,
+			data: TemplateData{
+				MCPPackage: "Example.McpServer@1.0.0",
+			},
+			wantContains: []string{
+				"dotnet tool install --tool-path /opt/dotnet-tools --version \"${package#*@}\" \"$tool\"",
+				"COPY --from=builder --chown=appuser:appgroup /opt/dotnet-tools /opt/dotnet-tools",
+				"DOTNET_ROLL_FORWARD=Major",
+				"ENTRYPOINT [\"/opt/dotnet-tools/.mcp-server\"]",
+			},
+			wantMatches: []string{
+				`FROM mcr.microsoft.com/dotnet/sdk:\d+\.\d+ AS builder`,
+				`FROM mcr.microsoft.com/dotnet/aspnet:\d+\.\d+`,
+			},
+			wantNotContains: []string{
+				"Add custom CA certificate",
+			},
+			wantErr: false,
+		},
+		{
+			name:          "DOTNET transport with CA certificate",
+			transportType: TransportTypeDotnet,
+			data: TemplateData{
+				MCPPackage:    "Example.McpServer",
+				CACertContent: "-----BEGIN CERTIFICATE-----\nMIICertificateContent\n-----END CERTIFICATE-----",
+			},
+			wantContains: []string{
+				"Add custom CA certificate BEFORE any network operations",
+				"cat /tmp/custom-ca.crt >> /etc/ssl/certs/ca-certificates.crt",
+				"update-ca-certificates",
+			},
+			wantErr: false,
+		},
 		{
 			name:          "Unsupported transport",
 			transportType: "unsupported",
@@ -305,6 +494,24 @@ func TestParseTransportType(t *testing.T) {
 			want:    TransportTypeGO,
 			wantErr: false,
 		},
+		{
+			name:    "PIPX transport",
+			s:       "pipx",
+			want:    TransportTypePipx,
+			wantErr: false,
+		},
+		{
+			name:    "CARGO transport",
+			s:       "cargo",
+			want:    TransportTypeCargo,
+			wantErr: false,
+		},
+		{
+			name:    "DOTNET transport",
+			s:       "dotnet",
+			want:    TransportTypeDotnet,
+			wantErr: false,
+		},
 		{
 			name:    "Unsupported transport",
 			s:       "unsupported",
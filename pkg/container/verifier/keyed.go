@@ -0,0 +1,80 @@
+package verifier
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+// verifyKeyedSignatures reports whether an image has a cosign signature made with one of the
+// public keys. The signed payload must refer to the digest of the image.
+func verifyKeyedSignatures(imageRef string, keychain authn.Keychain, keys []signature.Verifier) (bool, error) {
+	opts := []remote.Option{remote.WithAuthFromKeychain(keychain)}
+
+	ref, err := name.ParseReference(imageRef)
+	if err != nil {
+		return false, fmt.Errorf("error parsing image reference: %w", err)
+	}
+	desc, err := remote.Get(ref, opts...)
+	if err != nil {
+		return false, fmt.Errorf("error getting image descriptor: %w", err)
+	}
+
+	// The signatures are in the sha256-<hash>.sig tag of the repository
+	sigTag := ref.Context().Tag(fmt.Sprint(desc.Digest.Algorithm, "-", desc.Digest.Hex, ".sig"))
+	layers, err := getSimpleSigningLayersFromSignatureManifest(sigTag.Name(), keychain)
+	if err != nil {
+		logger.Debugf("No signatures found for %s: %v", imageRef, err)
+		return false, nil
+	}
+
+	for _, layer := range layers {
+		sig, err := base64.StdEncoding.DecodeString(layer.Annotations["dev.cosignproject.cosign/signature"])
+		if err != nil || len(sig) == 0 {
+			continue
+		}
+
+		// The payload is the content of the simple signing layer
+		blob, err := remote.Layer(ref.Context().Digest(layer.Digest.String()), opts...)
+		if err != nil {
+			return false, fmt.Errorf("error getting signature payload: %w", err)
+		}
+		rc, err := blob.Compressed()
+		if err != nil {
+			return false, fmt.Errorf("error getting signature payload: %w", err)
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, MaxAttestationsBytesLimit))
+		_ = rc.Close()
+		if err != nil {
+			return false, fmt.Errorf("error reading signature payload: %w", err)
+		}
+
+		for _, key := range keys {
+			if key.VerifySignature(bytes.NewReader(sig), bytes.NewReader(content)) != nil {
+				continue
+			}
+			var p payload.SimpleContainerImage
+			if err := json.Unmarshal(content, &p); err != nil {
+				logger.Debugf("Invalid signature payload for %s: %v", imageRef, err)
+				break
+			}
+			if p.Critical.Image.DockerManifestDigest == desc.Digest.String() {
+				return true, nil
+			}
+			logger.Debugf("Signature payload for %s refers to another image: %s",
+				imageRef, p.Critical.Image.DockerManifestDigest)
+			break
+		}
+	}
+	return false, nil
+}
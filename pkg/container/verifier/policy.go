@@ -0,0 +1,328 @@
+package verifier
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"gopkg.in/yaml.v3"
+
+	"github.com/stacklok/toolhive/pkg/container/images"
+)
+
+// ErrImageNotVerified is returned when an image does not meet the requirements of a verification policy
+var ErrImageNotVerified = errors.New("image does not meet the verification policy")
+
+// slsaProvenancePrefix is the prefix of the predicate types of all versions of SLSA provenance
+const slsaProvenancePrefix = "https://slsa.dev/provenance/"
+
+// maxSLSALevel is the highest SLSA build level
+const maxSLSALevel = 3
+
+// Policy is a local verification policy. It adds trusted signers and requirements for images,
+// in addition to the provenance information of the registry.
+type Policy struct {
+	// RequireVerified refuses to run images which cannot be verified, either by a rule of the
+	// policy or by the provenance information of the registry. Images without a rule or
+	// provenance information are refused, as they have no trusted signers.
+	RequireVerified bool `json:"require_verified,omitempty" yaml:"require_verified,omitempty"`
+	// Images are the rules of the policy. The first rule whose pattern matches an image applies.
+	Images []ImagePolicy `json:"images,omitempty" yaml:"images,omitempty"`
+}
+
+// ImagePolicy is a rule of a verification policy for the images matching a pattern
+type ImagePolicy struct {
+	// Image is a pattern matching repository names such as ghcr.io/stacklok/dockyard/fetch.
+	// A * matches any characters except /, and ** matches any characters.
+	Image string `json:"image" yaml:"image"`
+	// SigstoreURL is the TUF repository of the trusted root of keyless signatures,
+	// defaults to the Sigstore public good instance
+	SigstoreURL string `json:"sigstore_url,omitempty" yaml:"sigstore_url,omitempty"`
+	// Identities are the trusted signers of keyless signatures and attestations
+	Identities []Identity `json:"identities,omitempty" yaml:"identities,omitempty"`
+	// PublicKeys are the trusted cosign public keys, as PEM or as paths relative to the policy file
+	PublicKeys []string `json:"public_keys,omitempty" yaml:"public_keys,omitempty"`
+	// Attestations are the predicate types of the attestations images must have
+	Attestations []string `json:"attestations,omitempty" yaml:"attestations,omitempty"`
+	// SLSALevel is the minimum SLSA build level of the provenance of images
+	SLSALevel int `json:"slsa_level,omitempty" yaml:"slsa_level,omitempty"`
+
+	pattern    *regexp.Regexp
+	identities []verify.CertificateIdentity
+	keys       []signature.Verifier
+}
+
+// Identity is a trusted signer of keyless signatures, identified by the issuer and subject
+// of its certificate. Either the exact value or a regular expression is given for each.
+type Identity struct {
+	Issuer        string `json:"issuer,omitempty" yaml:"issuer,omitempty"`
+	IssuerRegExp  string `json:"issuer_regexp,omitempty" yaml:"issuer_regexp,omitempty"`
+	Subject       string `json:"subject,omitempty" yaml:"subject,omitempty"`
+	SubjectRegExp string `json:"subject_regexp,omitempty" yaml:"subject_regexp,omitempty"`
+}
+
+// LoadPolicy loads and validates a verification policy file
+func LoadPolicy(path string) (*Policy, error) {
+	// #nosec G304 -- the policy file is chosen by the user
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read verification policy: %w", err)
+	}
+
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse verification policy %s: %w", path, err)
+	}
+	if err := policy.compile(filepath.Dir(path)); err != nil {
+		return nil, fmt.Errorf("invalid verification policy %s: %w", path, err)
+	}
+	return &policy, nil
+}
+
+// compile validates the rules of the policy, and loads their patterns, identities and keys.
+// Public keys are resolved relative to a directory.
+func (p *Policy) compile(dir string) error {
+	for i := range p.Images {
+		if err := p.Images[i].compile(dir); err != nil {
+			return fmt.Errorf("rule %d (%s): %w", i+1, p.Images[i].Image, err)
+		}
+	}
+	return nil
+}
+
+func (r *ImagePolicy) compile(dir string) error {
+	if r.Image == "" {
+		return errors.New("image pattern is required")
+	}
+	if len(r.Identities) == 0 && len(r.PublicKeys) == 0 {
+		return errors.New("at least one identity or public key is required")
+	}
+	if (len(r.Attestations) > 0 || r.SLSALevel > 0) && len(r.Identities) == 0 {
+		return errors.New("identities are required to verify attestations")
+	}
+	if r.SLSALevel < 0 || r.SLSALevel > maxSLSALevel {
+		return fmt.Errorf("invalid SLSA level %d (valid levels: 0 to %d)", r.SLSALevel, maxSLSALevel)
+	}
+	if r.SigstoreURL != "" {
+		if _, err := verifierOptions(r.SigstoreURL); err != nil {
+			return err
+		}
+	}
+
+	pattern, err := compilePattern(r.Image)
+	if err != nil {
+		return err
+	}
+	r.pattern = pattern
+
+	r.identities = nil
+	for _, id := range r.Identities {
+		identity, err := verify.NewShortCertificateIdentity(id.Issuer, id.IssuerRegExp, id.Subject, id.SubjectRegExp)
+		if err != nil {
+			return fmt.Errorf("invalid identity: %w", err)
+		}
+		r.identities = append(r.identities, identity)
+	}
+
+	r.keys = nil
+	for _, key := range r.PublicKeys {
+		verifier, err := loadPublicKey(key, dir)
+		if err != nil {
+			return err
+		}
+		r.keys = append(r.keys, verifier)
+	}
+	return nil
+}
+
+// loadPublicKey loads a cosign public key, given as PEM or as the path of a PEM file
+func loadPublicKey(key, dir string) (signature.Verifier, error) {
+	pemBytes := []byte(key)
+	if !strings.HasPrefix(strings.TrimSpace(key), "-----BEGIN") {
+		path := key
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		var err error
+		// #nosec G304 -- the key is chosen by the user
+		pemBytes, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public key: %w", err)
+		}
+	}
+
+	publicKey, err := cryptoutils.UnmarshalPEMToPublicKey(pemBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	return signature.LoadVerifier(publicKey, crypto.SHA256)
+}
+
+// compilePattern converts an image pattern into a regular expression
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
+
+// Match returns the rule of the policy that applies to an image, or nil if there is none
+func (p *Policy) Match(imageRef string) *ImagePolicy {
+	repository, err := repositoryName(imageRef)
+	if err != nil {
+		return nil
+	}
+	for i := range p.Images {
+		if p.Images[i].pattern != nil && p.Images[i].pattern.MatchString(repository) {
+			return &p.Images[i]
+		}
+	}
+	return nil
+}
+
+// repositoryName returns the repository of an image reference, including its registry.
+// Images of Docker Hub are in the docker.io registry, as they are usually written.
+func repositoryName(imageRef string) (string, error) {
+	ref, err := name.ParseReference(imageRef)
+	if err != nil {
+		return "", err
+	}
+	registry := ref.Context().RegistryStr()
+	if registry == name.DefaultRegistry {
+		registry = "docker.io"
+	}
+	return registry + "/" + ref.Context().RepositoryStr(), nil
+}
+
+// NewForPolicy creates a verifier for a rule of a verification policy. The trusted root of
+// keyless signatures is only fetched when the rule has identities.
+func NewForPolicy(rule *ImagePolicy) (*Sigstore, error) {
+	if len(rule.identities) == 0 {
+		return &Sigstore{keychain: images.NewCompositeKeychain()}, nil
+	}
+	return NewWithTrustedRoot(rule.SigstoreURL)
+}
+
+// VerifyPolicy verifies an image against a rule of a verification policy. The image must be
+// signed by one of the trusted identities or keys of the rule, and have the attestations it
+// requires. ErrImageNotVerified is returned when the image does not meet the rule.
+func (s *Sigstore) VerifyPolicy(imageRef string, rule *ImagePolicy) error {
+	keyed := false
+	if len(rule.keys) > 0 {
+		var err error
+		keyed, err = verifyKeyedSignatures(imageRef, s.keychain, rule.keys)
+		if err != nil {
+			return err
+		}
+	}
+
+	var results []*verify.VerificationResult
+	if len(rule.identities) > 0 {
+		if s.verifier == nil {
+			return errors.New("the verifier has no trusted root for keyless signatures")
+		}
+		var err error
+		results, err = s.getIdentityResults(imageRef, rule.identities)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rule.evaluate(keyed, results)
+}
+
+// getIdentityResults returns the signatures and attestations of an image that were signed by
+// one of the identities
+func (s *Sigstore) getIdentityResults(
+	imageRef string,
+	identities []verify.CertificateIdentity,
+) ([]*verify.VerificationResult, error) {
+	signatures, err := bundleFromSigstoreSignedImage(imageRef, s.keychain)
+	if err != nil && !errors.Is(err, ErrProvenanceNotFoundOrIncomplete) {
+		return nil, err
+	}
+	attestations, err := bundleFromAttestation(imageRef, s.keychain)
+	if err != nil && !errors.Is(err, ErrProvenanceNotFoundOrIncomplete) {
+		return nil, err
+	}
+
+	opts := make([]verify.PolicyOption, 0, len(identities))
+	for _, identity := range identities {
+		opts = append(opts, verify.WithCertificateIdentity(identity))
+	}
+	return getVerifiedResults(s.verifier, append(signatures, attestations...), opts...), nil
+}
+
+// evaluate checks the requirements of the rule against the verified signatures and
+// attestations of an image
+func (r *ImagePolicy) evaluate(keyed bool, results []*verify.VerificationResult) error {
+	if !keyed && len(results) == 0 {
+		return fmt.Errorf("%w: no signature by a trusted identity or key", ErrImageNotVerified)
+	}
+
+	for _, predicateType := range r.Attestations {
+		found := false
+		for _, res := range results {
+			if res.Statement != nil && res.Statement.PredicateType == predicateType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: no %s attestation by a trusted identity", ErrImageNotVerified, predicateType)
+		}
+	}
+
+	if r.SLSALevel > 0 {
+		level := 0
+		for _, res := range results {
+			level = max(level, slsaBuildLevel(res))
+		}
+		if level < r.SLSALevel {
+			return fmt.Errorf("%w: provenance meets SLSA build level %d, level %d is required",
+				ErrImageNotVerified, level, r.SLSALevel)
+		}
+	}
+	return nil
+}
+
+// slsaBuildLevel returns the SLSA build level of a verified provenance attestation. Provenance
+// signed by a trusted identity meets level 1. Provenance signed on a hosted runner of a build
+// platform, whose workflow is in the certificate, meets level 2. Provenance signed by a reusable
+// workflow, isolated from the build instructions of the repository, meets level 3.
+func slsaBuildLevel(res *verify.VerificationResult) int {
+	if res == nil || res.Statement == nil || !strings.HasPrefix(res.Statement.PredicateType, slsaProvenancePrefix) {
+		return 0
+	}
+	if res.Signature == nil || res.Signature.Certificate == nil {
+		return 1
+	}
+
+	cert := res.Signature.Certificate
+	if cert.BuildSignerURI == "" || cert.RunnerEnvironment == "" || cert.RunnerEnvironment == "self-hosted" {
+		return 1
+	}
+	if cert.BuildConfigURI == "" || cert.BuildSignerURI == cert.BuildConfigURI {
+		return 2
+	}
+	return 3
+}
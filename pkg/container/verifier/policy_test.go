@@ -0,0 +1,356 @@
+package verifier
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	in_toto "github.com/in-toto/attestation/go/v1"
+	"github.com/sigstore/sigstore-go/pkg/fulcio/certificate"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePolicy writes a verification policy file and returns its path
+func writePolicy(t *testing.T, dir, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "verification-policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+// newPublicKey generates a key pair and writes the PEM public key to a directory
+func newPublicKey(t *testing.T, dir string) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	pemBytes, err := cryptoutils.MarshalPublicKeyToPEM(key.Public())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cosign.pub"), pemBytes, 0600))
+	return key, string(pemBytes)
+}
+
+func TestLoadPolicy(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, publicKey := newPublicKey(t, dir)
+
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr string
+	}{
+		{
+			name: "valid policy",
+			policy: `require_verified: true
+images:
+  - image: ghcr.io/stacklok/dockyard/**
+    identities:
+      - issuer: https://token.actions.githubusercontent.com
+        subject_regexp: ^https://github.com/stacklok/dockyard/
+    attestations: [https://slsa.dev/provenance/v1]
+    slsa_level: 3
+  - image: registry.example.com/mcp/*
+    public_keys: [cosign.pub]
+  - image: docker.io/example/*
+    public_keys:
+      - |
+` + indent(publicKey, "        "),
+		},
+		{
+			name:    "no signers",
+			policy:  "images:\n  - image: ghcr.io/**\n",
+			wantErr: "at least one identity or public key is required",
+		},
+		{
+			name:    "attestations without identities",
+			policy:  "images:\n  - image: ghcr.io/**\n    public_keys: [cosign.pub]\n    slsa_level: 1\n",
+			wantErr: "identities are required to verify attestations",
+		},
+		{
+			name:    "invalid SLSA level",
+			policy:  "images:\n  - image: ghcr.io/**\n    identities: [{issuer: i, subject: s}]\n    slsa_level: 4\n",
+			wantErr: "invalid SLSA level 4",
+		},
+		{
+			name:    "invalid identity",
+			policy:  "images:\n  - image: ghcr.io/**\n    identities: [{subject: s}]\n",
+			wantErr: "invalid identity",
+		},
+		{
+			name:    "missing public key",
+			policy:  "images:\n  - image: ghcr.io/**\n    public_keys: [missing.pub]\n",
+			wantErr: "failed to read public key",
+		},
+		{
+			name:    "unknown trusted root",
+			policy:  "images:\n  - image: ghcr.io/**\n    public_keys: [cosign.pub]\n    sigstore_url: tuf.example.com\n",
+			wantErr: "unknown trusted root",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Each policy is in its own directory next to the public key
+			policyDir := filepath.Join(dir, fmt.Sprint(i))
+			require.NoError(t, os.Mkdir(policyDir, 0700))
+			require.NoError(t, os.WriteFile(filepath.Join(policyDir, "cosign.pub"), []byte(publicKey), 0600))
+
+			policy, err := LoadPolicy(writePolicy(t, policyDir, tt.policy))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, policy.RequireVerified)
+			assert.Len(t, policy.Images[0].identities, 1)
+			assert.Len(t, policy.Images[1].keys, 1)
+			assert.Len(t, policy.Images[2].keys, 1)
+		})
+	}
+}
+
+// indent indents the lines of a string
+func indent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(strings.TrimSpace(s), "\n", "\n"+prefix) + "\n"
+}
+
+func TestPolicyMatch(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	newPublicKey(t, dir)
+	policy, err := LoadPolicy(writePolicy(t, dir, `images:
+  - image: ghcr.io/stacklok/dockyard/**
+    public_keys: [cosign.pub]
+  - image: ghcr.io/stacklok/*
+    public_keys: [cosign.pub]
+  - image: docker.io/library/*
+    public_keys: [cosign.pub]
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{image: "ghcr.io/stacklok/dockyard/uvx/fetch:0.6.2", want: "ghcr.io/stacklok/dockyard/**"},
+		{image: "ghcr.io/stacklok/toolhive@sha256:" + strings.Repeat("a", 64), want: "ghcr.io/stacklok/*"},
+		{image: "ghcr.io/stacklok/other/server", want: ""},
+		{image: "alpine:3.20", want: "docker.io/library/*"},
+		{image: "ghcr.io/example/server:latest", want: ""},
+		{image: "not a reference", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			t.Parallel()
+			rule := policy.Match(tt.image)
+			if tt.want == "" {
+				assert.Nil(t, rule)
+				return
+			}
+			require.NotNil(t, rule)
+			assert.Equal(t, tt.want, rule.Image)
+		})
+	}
+}
+
+// newAttestationResult returns the verification result of an attestation
+func newAttestationResult(predicateType string, extensions certificate.Extensions) *verify.VerificationResult {
+	return &verify.VerificationResult{
+		Statement: &in_toto.Statement{PredicateType: predicateType},
+		Signature: &verify.SignatureVerificationResult{
+			Certificate: &certificate.Summary{Extensions: extensions},
+		},
+	}
+}
+
+func TestImagePolicyEvaluate(t *testing.T) {
+	t.Parallel()
+
+	const provenance = "https://slsa.dev/provenance/v1"
+	hosted := certificate.Extensions{
+		RunnerEnvironment: "github-hosted",
+		BuildSignerURI:    "https://github.com/example/server/.github/workflows/release.yml@refs/tags/v1.0.0",
+		BuildConfigURI:    "https://github.com/example/server/.github/workflows/release.yml@refs/tags/v1.0.0",
+	}
+	reusable := hosted
+	reusable.BuildSignerURI = "https://github.com/slsa-framework/slsa-github-generator/.github/workflows/generator.yml@v2"
+	selfHosted := hosted
+	selfHosted.RunnerEnvironment = "self-hosted"
+
+	tests := []struct {
+		name    string
+		rule    ImagePolicy
+		keyed   bool
+		results []*verify.VerificationResult
+		wantErr string
+	}{
+		{
+			name:    "unsigned",
+			rule:    ImagePolicy{},
+			wantErr: "no signature by a trusted identity or key",
+		},
+		{
+			name:  "keyed signature",
+			rule:  ImagePolicy{},
+			keyed: true,
+		},
+		{
+			name:    "keyless signature",
+			rule:    ImagePolicy{},
+			results: []*verify.VerificationResult{{}},
+		},
+		{
+			name:    "required attestation",
+			rule:    ImagePolicy{Attestations: []string{"https://spdx.dev/Document"}},
+			results: []*verify.VerificationResult{newAttestationResult("https://spdx.dev/Document", hosted)},
+		},
+		{
+			name:    "missing attestation",
+			rule:    ImagePolicy{Attestations: []string{"https://spdx.dev/Document"}},
+			results: []*verify.VerificationResult{newAttestationResult(provenance, hosted)},
+			wantErr: "no https://spdx.dev/Document attestation",
+		},
+		{
+			name:    "SLSA level 2 of a hosted runner",
+			rule:    ImagePolicy{SLSALevel: 2},
+			results: []*verify.VerificationResult{newAttestationResult(provenance, hosted)},
+		},
+		{
+			name:    "SLSA level 1 of a self-hosted runner",
+			rule:    ImagePolicy{SLSALevel: 2},
+			results: []*verify.VerificationResult{newAttestationResult(provenance, selfHosted)},
+			wantErr: "provenance meets SLSA build level 1, level 2 is required",
+		},
+		{
+			name:    "SLSA level 3 of a reusable workflow",
+			rule:    ImagePolicy{SLSALevel: 3},
+			results: []*verify.VerificationResult{newAttestationResult(provenance, hosted), newAttestationResult(provenance, reusable)},
+		},
+		{
+			name:    "no provenance",
+			rule:    ImagePolicy{SLSALevel: 1},
+			results: []*verify.VerificationResult{newAttestationResult("https://spdx.dev/Document", hosted)},
+			wantErr: "provenance meets SLSA build level 0, level 1 is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.rule.evaluate(tt.keyed, tt.results)
+			if tt.wantErr != "" {
+				require.ErrorIs(t, err, ErrImageNotVerified)
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+// pushSignedImage pushes an image and its cosign signature to a registry
+func pushSignedImage(t *testing.T, repository string, key *ecdsa.PrivateKey, signedDigest string) string {
+	t.Helper()
+
+	img, err := random.Image(256, 1)
+	require.NoError(t, err)
+	ref, err := name.ParseReference(repository + ":latest")
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, img))
+	digest, err := img.Digest()
+	require.NoError(t, err)
+	if signedDigest == "" {
+		signedDigest = digest.String()
+	}
+
+	var p payload.SimpleContainerImage
+	p.Critical.Type = payload.CosignSignatureType
+	p.Critical.Identity.DockerReference = ref.Context().Name()
+	p.Critical.Image.DockerManifestDigest = signedDigest
+	content, err := json.Marshal(p)
+	require.NoError(t, err)
+
+	signer, err := signature.LoadECDSASigner(key, crypto.SHA256)
+	require.NoError(t, err)
+	sig, err := signer.SignMessage(strings.NewReader(string(content)))
+	require.NoError(t, err)
+
+	sigImg, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer:       static.NewLayer(content, "application/vnd.dev.cosign.simplesigning.v1+json"),
+		MediaType:   "application/vnd.dev.cosign.simplesigning.v1+json",
+		Annotations: map[string]string{"dev.cosignproject.cosign/signature": base64.StdEncoding.EncodeToString(sig)},
+	})
+	require.NoError(t, err)
+	sigImg = mutate.MediaType(sigImg, types.OCIManifestSchema1)
+	sigTag := ref.Context().Tag(fmt.Sprint(digest.Algorithm, "-", digest.Hex, ".sig"))
+	require.NoError(t, remote.Write(sigTag, sigImg))
+
+	return ref.Name()
+}
+
+func TestVerifyKeyedSignatures(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	dir := t.TempDir()
+	key, _ := newPublicKey(t, dir)
+	policy, err := LoadPolicy(writePolicy(t, dir, "images:\n  - image: "+host+"/**\n    public_keys: [cosign.pub]\n"))
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	signed := pushSignedImage(t, host+"/signed", key, "")
+	otherSigner := pushSignedImage(t, host+"/other-signer", otherKey, "")
+	otherImage := pushSignedImage(t, host+"/other-image", key, "sha256:"+strings.Repeat("0", 64))
+	unsigned := host + "/unsigned:latest"
+	img, err := random.Image(256, 1)
+	require.NoError(t, err)
+	unsignedRef, err := name.ParseReference(unsigned)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(unsignedRef, img))
+
+	v := (&Sigstore{}).WithKeychain(authn.DefaultKeychain)
+	for image, want := range map[string]bool{signed: true, otherSigner: false, otherImage: false, unsigned: false} {
+		rule := policy.Match(image)
+		require.NotNil(t, rule)
+		err := v.VerifyPolicy(image, rule)
+		if want {
+			assert.NoError(t, err, image)
+		} else {
+			assert.ErrorIs(t, err, ErrImageNotVerified, image)
+		}
+	}
+}
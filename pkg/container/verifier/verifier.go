@@ -44,8 +44,11 @@ func New(serverInfo *registry.ImageMetadata) (*Sigstore, error) {
 	if serverInfo == nil || serverInfo.Provenance == nil {
 		return nil, ErrProvenanceServerInformationNotSet
 	}
-	sigstoreTUFRepoURL := serverInfo.Provenance.SigstoreURL
+	return NewWithTrustedRoot(serverInfo.Provenance.SigstoreURL)
+}
 
+// NewWithTrustedRoot creates a new Sigstore verifier for the trusted root of a TUF repository
+func NewWithTrustedRoot(sigstoreTUFRepoURL string) (*Sigstore, error) {
 	// Default the sigstoreTUFRepoURL to the sigstore public trusted root repo if not provided.
 	// Note: Update this if we want to support more sigstore instances
 	if sigstoreTUFRepoURL == "" {
@@ -100,14 +103,15 @@ func (s *Sigstore) GetVerificationResults(
 	}
 
 	// Construct the verification result for each bundle we managed to generate.
-	return getVerifiedResults(s.verifier, bundles), nil
+	return getVerifiedResults(s.verifier, bundles, verify.WithoutIdentitiesUnsafe()), nil
 }
 
 // getVerifiedResults verifies the artifact using the bundles against the configured sigstore instance
-// and returns the extracted metadata that we need for ingestion
+// and the identities of the policy options, and returns the extracted metadata that we need for ingestion
 func getVerifiedResults(
 	sev *verify.Verifier,
 	bundles []sigstoreBundle,
+	policyOpts ...verify.PolicyOption,
 ) []*verify.VerificationResult {
 	var results []*verify.VerificationResult
 
@@ -116,7 +120,7 @@ func getVerifiedResults(
 		// Create a new verification result. At this point, we managed to extract a bundle, so lets verify it.
 		verificationResult, err := sev.Verify(b.bundle, verify.NewPolicy(
 			verify.WithArtifactDigest(b.digestAlgo, b.digestBytes),
-			verify.WithoutIdentitiesUnsafe(),
+			policyOpts...,
 		))
 		if err != nil {
 			logger.Infof("bundle verification failed: %v", err)
@@ -7,12 +7,13 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/stacklok/toolhive/pkg/container/runtime"
 	"github.com/stacklok/toolhive/pkg/secrets"
 )
 
 // ParseSecretParameters parses the secret parameters from the command line,
 // fetches them from the secrets manager, and returns a map of secrets and
-// their environment variable names.
+// their environment variable names. Secrets with file targets are skipped.
 func ParseSecretParameters(ctx context.Context, parameters []string, secretsManager secrets.Provider) (map[string]string, error) {
 	secretVariables := make(map[string]string, len(parameters))
 	for _, param := range parameters {
@@ -20,6 +21,9 @@ func ParseSecretParameters(ctx context.Context, parameters []string, secretsMana
 		if err != nil {
 			return nil, err
 		}
+		if parameter.IsFile() {
+			continue
+		}
 
 		secret, err := secretsManager.GetSecret(ctx, parameter.Name)
 		if err != nil {
@@ -32,6 +36,42 @@ func ParseSecretParameters(ctx context.Context, parameters []string, secretsMana
 	return secretVariables, nil
 }
 
+// ParseSecretFiles parses the secret parameters from the command line and fetches
+// the secrets with file targets from the secrets manager.
+func ParseSecretFiles(ctx context.Context, parameters []string, secretsManager secrets.Provider) ([]runtime.SecretFile, error) {
+	var secretFiles []runtime.SecretFile
+	paths := make(map[string]string)
+	for _, param := range parameters {
+		parameter, err := secrets.ParseSecretParameter(param)
+		if err != nil {
+			return nil, err
+		}
+		if !parameter.IsFile() {
+			continue
+		}
+
+		filePath := parameter.FilePath()
+		if other, ok := paths[filePath]; ok {
+			return nil, fmt.Errorf("secrets %s and %s are both mounted at %s", other, parameter.Name, filePath)
+		}
+		paths[filePath] = parameter.Name
+
+		secret, err := secretsManager.GetSecret(ctx, parameter.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		secretFiles = append(secretFiles, runtime.SecretFile{
+			Name:  parameter.Name,
+			Path:  filePath,
+			Mode:  parameter.FileMode(),
+			Value: secret,
+		})
+	}
+
+	return secretFiles, nil
+}
+
 // ParseEnvironmentVariables parses environment variables from a slice of strings
 // in the format KEY=VALUE
 func ParseEnvironmentVariables(envVars []string) (map[string]string, error) {
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/stacklok/toolhive/pkg/container/runtime"
 	"github.com/stacklok/toolhive/pkg/secrets"
 )
 
@@ -79,6 +80,19 @@ func TestParseSecretParameters(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:       "File targets are skipped",
+			parameters: []string{"secret1,target=ENV_VAR1", "secret2,target=file:/run/secrets/token,mode=0400"},
+			provider: &mockSecretsProvider{
+				secrets: map[string]string{
+					"secret1": "value1",
+				},
+			},
+			want: map[string]string{
+				"ENV_VAR1": "value1",
+			},
+			wantErr: false,
+		},
 		{
 			name:       "Invalid parameter format",
 			parameters: []string{"invalid-format"},
@@ -119,6 +133,82 @@ func TestParseSecretParameters(t *testing.T) {
 	}
 }
 
+func TestParseSecretFiles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		parameters []string
+		provider   *mockSecretsProvider
+		want       []runtime.SecretFile
+		wantErr    bool
+	}{
+		{
+			name: "Success case",
+			parameters: []string{
+				"secret1,target=ENV_VAR1",
+				"secret2,target=file:/run/secrets/token,mode=0400",
+				"secret3,target=file:/etc/app/key.pem",
+			},
+			provider: &mockSecretsProvider{
+				secrets: map[string]string{
+					"secret2": "value2",
+					"secret3": "value3",
+				},
+			},
+			want: []runtime.SecretFile{
+				{Name: "secret2", Path: "/run/secrets/token", Mode: 0400, Value: "value2"},
+				{Name: "secret3", Path: "/etc/app/key.pem", Mode: 0444, Value: "value3"},
+			},
+		},
+		{
+			name:       "Relative path",
+			parameters: []string{"secret1,target=file:run/secrets/token"},
+			provider:   &mockSecretsProvider{},
+			wantErr:    true,
+		},
+		{
+			name:       "Mode of environment variable target",
+			parameters: []string{"secret1,target=ENV_VAR1,mode=0400"},
+			provider:   &mockSecretsProvider{},
+			wantErr:    true,
+		},
+		{
+			name:       "Duplicate path",
+			parameters: []string{"secret1,target=file:/run/secrets/token", "secret2,target=file:/run/secrets/token"},
+			provider: &mockSecretsProvider{
+				secrets: map[string]string{
+					"secret1": "value1",
+					"secret2": "value2",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "GetSecret error",
+			parameters: []string{"secret1,target=file:/run/secrets/token"},
+			provider: &mockSecretsProvider{
+				getErr: errors.New("failed to get secret"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseSecretFiles(t.Context(), tt.parameters, tt.provider)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSecretFiles() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSecretFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestParseEnvironmentVariables(t *testing.T) {
 	t.Parallel()
 
@@ -39,6 +39,22 @@ const (
 	// LabelAuxiliary is the label that indicates this is an auxiliary workload (like inspector)
 	LabelAuxiliary = "toolhive-auxiliary"
 
+	// LabelServiceOf is the label that contains the name of the workload a service container belongs to
+	LabelServiceOf = "toolhive-service-of"
+
+	// LabelSource is the image label that contains the protocol scheme an image was built from
+	LabelSource = "toolhive-source"
+
+	// LabelPinVersion is the image label that contains the exact version of the package of an image
+	LabelPinVersion = "toolhive-pin-version"
+
+	// LabelPinHash is the image label that contains the content hash of the package of an image
+	LabelPinHash = "toolhive-pin-hash"
+
+	// LabelImage is the image label that indicates an image was built by ToolHive. It is distinct
+	// from LabelToolHive, which containers would otherwise inherit from their image.
+	LabelImage = "toolhive-image"
+
 	// LabelToolHiveValue is the value for the LabelToolHive label
 	LabelToolHiveValue = "true"
 )
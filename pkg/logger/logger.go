@@ -2,6 +2,7 @@
 package logger
 
 import (
+	"context"
 	"strconv"
 	"time"
 
@@ -119,6 +120,22 @@ func Fatalw(msg string, keysAndValues ...any) {
 	zap.S().Fatalw(msg, keysAndValues...)
 }
 
+// WithContext returns the singleton logger annotated with ctx, so that log records
+// exported via OpenTelemetry carry the trace and span IDs of the active span.
+// The context itself is never written to the console or JSON output.
+func WithContext(ctx context.Context) *zap.SugaredLogger {
+	return zap.S().With(zap.Any("context", ctx))
+}
+
+// AddCore tees the output of the singleton logger into core, in addition to the
+// console or JSON output configured by Initialize. It returns a function which
+// restores the previous logger.
+func AddCore(core zapcore.Core) func() {
+	return zap.ReplaceGlobals(zap.L().WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(c, core)
+	})))
+}
+
 // NewLogr returns a logr.Logger which uses zap logger
 func NewLogr() logr.Logger {
 	return zapr.NewLogger(zap.L())
@@ -155,7 +172,44 @@ func InitializeWithEnv(envReader env.Reader) {
 		config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
 	}
 
-	zap.ReplaceGlobals(zap.Must(config.Build()))
+	zap.ReplaceGlobals(zap.Must(config.Build(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return &contextFilterCore{Core: c}
+	}))))
+}
+
+// contextFilterCore drops context.Context fields, which are only meaningful to
+// cores exporting log records via OpenTelemetry.
+type contextFilterCore struct {
+	zapcore.Core
+}
+
+// With adds structured context to the core, without any context.Context fields.
+func (c *contextFilterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &contextFilterCore{Core: c.Core.With(withoutContextFields(fields))}
+}
+
+// Check determines whether the entry should be logged by this core.
+func (c *contextFilterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write serializes the entry and fields, without any context.Context fields.
+func (c *contextFilterCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, withoutContextFields(fields))
+}
+
+func withoutContextFields(fields []zapcore.Field) []zapcore.Field {
+	filtered := fields[:0:0]
+	for _, field := range fields {
+		if _, ok := field.Interface.(context.Context); ok {
+			continue
+		}
+		filtered = append(filtered, field)
+	}
+	return filtered
 }
 
 func unstructuredLogsWithEnv(envReader env.Reader) bool {
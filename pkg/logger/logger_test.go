@@ -2,6 +2,7 @@ package logger
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -200,3 +201,32 @@ func TestInitialize(t *testing.T) { //nolint:paralleltest // Uses global logger
 		assert.Contains(t, output, "INFO", "Expected output to contain 'INFO'")
 	})
 }
+
+// TestAddCoreWithContext tests that context fields only reach additional cores
+func TestAddCoreWithContext(t *testing.T) { //nolint:paralleltest // Uses global logger state
+	var buf bytes.Buffer
+	console := zapcore.NewCore(
+		zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig()),
+		zapcore.AddSync(&buf),
+		zapcore.InfoLevel,
+	)
+	zap.ReplaceGlobals(zap.New(&contextFilterCore{Core: console}))
+
+	observed, observedLogs := observer.New(zapcore.InfoLevel)
+	restore := AddCore(observed)
+	defer restore()
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	WithContext(ctx).Infof("handled %s", "request")
+
+	entries := observedLogs.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "handled request", entries[0].Message)
+	require.Len(t, entries[0].Context, 1)
+	assert.Equal(t, ctx, entries[0].Context[0].Interface)
+
+	output := buf.String()
+	assert.Contains(t, output, "handled request")
+	assert.NotContains(t, output, "context")
+}
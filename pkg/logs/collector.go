@@ -0,0 +1,44 @@
+package logs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Collector reads raw output line by line, parses each line into a structured
+// record and writes it as a JSON line to its destination.
+type Collector struct {
+	source Source
+	dest   io.Writer
+	now    func() time.Time
+}
+
+// NewCollector creates a collector writing records of the given source to dest.
+func NewCollector(source Source, dest io.Writer) *Collector {
+	return &Collector{source: source, dest: dest, now: time.Now}
+}
+
+// Collect consumes r until EOF or a read error. Lines which are not log
+// entries, such as JSON-RPC protocol traffic, are dropped.
+func (c *Collector) Collect(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	encoder := json.NewEncoder(c.dest)
+	for scanner.Scan() {
+		record, ok := ParseLine(c.source, scanner.Text(), c.now())
+		if !ok {
+			continue
+		}
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("failed to write log record: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil && err != io.ErrClosedPipe {
+		return fmt.Errorf("failed to read log stream: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Filter selects log records. The zero value matches every record.
+type Filter struct {
+	// MinLevel is the minimum level of records to include.
+	// Records without a level are treated as LevelInfo.
+	MinLevel Level
+	// Since excludes records produced before this time, if set
+	Since time.Time
+	// Until excludes records produced after this time, if set
+	Until time.Time
+	// Pattern excludes records whose message and fields do not match, if set
+	Pattern *regexp.Regexp
+}
+
+// FilterOptions holds the user-facing representation of a Filter,
+// as passed on the command line or in API query parameters.
+type FilterOptions struct {
+	// Level is the minimum level name, e.g. "warning"
+	Level string
+	// Since is either a duration relative to now (e.g. "10m") or an RFC 3339 timestamp
+	Since string
+	// Until is either a duration relative to now (e.g. "5m") or an RFC 3339 timestamp
+	Until string
+	// Grep is a regular expression matched against the record
+	Grep string
+}
+
+// IsZero reports whether no filtering option is set.
+func (o FilterOptions) IsZero() bool {
+	return o == FilterOptions{}
+}
+
+// NewFilter builds a Filter from its user-facing options, resolving relative
+// times against now.
+func NewFilter(opts FilterOptions, now time.Time) (*Filter, error) {
+	filter := &Filter{}
+
+	if opts.Level != "" {
+		level, ok := ParseLevel(opts.Level)
+		if !ok {
+			return nil, fmt.Errorf("invalid log level: %s", opts.Level)
+		}
+		filter.MinLevel = level
+	}
+
+	var err error
+	if filter.Since, err = parseTimeBound(opts.Since, now); err != nil {
+		return nil, fmt.Errorf("invalid since value: %w", err)
+	}
+	if filter.Until, err = parseTimeBound(opts.Until, now); err != nil {
+		return nil, fmt.Errorf("invalid until value: %w", err)
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && filter.Until.Before(filter.Since) {
+		return nil, fmt.Errorf("until must not be before since")
+	}
+
+	if opts.Grep != "" {
+		if filter.Pattern, err = regexp.Compile(opts.Grep); err != nil {
+			return nil, fmt.Errorf("invalid grep pattern: %w", err)
+		}
+	}
+
+	return filter, nil
+}
+
+// Match reports whether the record is selected by the filter.
+func (f *Filter) Match(r Record) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.MinLevel != "" {
+		level := r.Level
+		if level == "" {
+			level = LevelInfo
+		}
+		if level.Severity() < f.MinLevel.Severity() {
+			return false
+		}
+	}
+
+	if !f.Since.IsZero() && r.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && r.Time.After(f.Until) {
+		return false
+	}
+
+	if f.Pattern != nil {
+		if f.Pattern.MatchString(r.Message) || f.Pattern.MatchString(r.Logger) {
+			return true
+		}
+		if len(r.Fields) == 0 {
+			return false
+		}
+		encoded, err := json.Marshal(r.Fields)
+		return err == nil && f.Pattern.Match(encoded)
+	}
+
+	return true
+}
+
+// parseTimeBound parses either a duration relative to now or an RFC 3339 timestamp.
+func parseTimeBound(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("duration must not be negative: %s", value)
+		}
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a duration (e.g. 10m) or an RFC 3339 timestamp: %s", value)
+	}
+	return t, nil
+}
@@ -0,0 +1,113 @@
+package logs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFilter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		opts    FilterOptions
+		wantErr bool
+		check   func(t *testing.T, f *Filter)
+	}{
+		{
+			name: "empty options",
+			opts: FilterOptions{},
+			check: func(t *testing.T, f *Filter) {
+				t.Helper()
+				assert.Equal(t, &Filter{}, f)
+			},
+		},
+		{
+			name: "relative since and absolute until",
+			opts: FilterOptions{Since: "1h", Until: "2025-10-01T11:30:00Z"},
+			check: func(t *testing.T, f *Filter) {
+				t.Helper()
+				assert.Equal(t, now.Add(-time.Hour), f.Since)
+				assert.Equal(t, time.Date(2025, 10, 1, 11, 30, 0, 0, time.UTC), f.Until)
+			},
+		},
+		{
+			name: "level alias",
+			opts: FilterOptions{Level: "warn"},
+			check: func(t *testing.T, f *Filter) {
+				t.Helper()
+				assert.Equal(t, LevelWarning, f.MinLevel)
+			},
+		},
+		{name: "invalid level", opts: FilterOptions{Level: "loud"}, wantErr: true},
+		{name: "invalid since", opts: FilterOptions{Since: "yesterday"}, wantErr: true},
+		{name: "negative duration", opts: FilterOptions{Since: "-5m"}, wantErr: true},
+		{name: "until before since", opts: FilterOptions{Since: "10m", Until: "1h"}, wantErr: true},
+		{name: "invalid pattern", opts: FilterOptions{Grep: "("}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := NewFilter(tt.opts, now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, f)
+		})
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	record := Record{
+		Time:    now.Add(-30 * time.Minute),
+		Level:   LevelWarning,
+		Source:  SourceContainer,
+		Message: "request to api.github.com was slow",
+		Fields:  map[string]any{"duration_ms": float64(1500)},
+	}
+
+	tests := []struct {
+		name     string
+		opts     FilterOptions
+		record   Record
+		expected bool
+	}{
+		{name: "no filter", record: record, expected: true},
+		{name: "level at minimum", opts: FilterOptions{Level: "warning"}, record: record, expected: true},
+		{name: "level below minimum", opts: FilterOptions{Level: "error"}, record: record, expected: false},
+		{
+			name:     "unknown level counts as info",
+			opts:     FilterOptions{Level: "info"},
+			record:   Record{Time: record.Time, Message: "plain"},
+			expected: true,
+		},
+		{name: "inside time window", opts: FilterOptions{Since: "1h", Until: "10m"}, record: record, expected: true},
+		{name: "before since", opts: FilterOptions{Since: "10m"}, record: record, expected: false},
+		{name: "after until", opts: FilterOptions{Until: "45m"}, record: record, expected: false},
+		{name: "grep on message", opts: FilterOptions{Grep: `github\.com`}, record: record, expected: true},
+		{name: "grep on fields", opts: FilterOptions{Grep: "duration_ms"}, record: record, expected: true},
+		{name: "grep without match", opts: FilterOptions{Grep: "gitlab"}, record: record, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := NewFilter(tt.opts, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, f.Match(tt.record))
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mcpLogMethod is the MCP notification used by servers to send log messages.
+const mcpLogMethod = "notifications/message"
+
+// ansiEscape matches ANSI color sequences emitted by console loggers.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// Well-known keys used by logging libraries for the standard fields.
+var (
+	timeKeys    = []string{"time", "ts", "timestamp", "@timestamp"}
+	levelKeys   = []string{"level", "severity", "lvl", "levelname"}
+	messageKeys = []string{"msg", "message", "event"}
+	loggerKeys  = []string{"logger", "name", "logger_name"}
+)
+
+// ParseLine parses a single line of output into a structured record.
+// JSON lines and MCP notifications/message entries are parsed into their fields,
+// plain text lines become a record with the line as message. collectedAt is used
+// when the line carries no timestamp. It returns false for lines which are not
+// log entries, such as blank lines or JSON-RPC protocol traffic.
+func ParseLine(source Source, line string, collectedAt time.Time) (Record, bool) {
+	line = strings.TrimRight(ansiEscape.ReplaceAllString(line, ""), "\r\n")
+	if strings.TrimSpace(line) == "" {
+		return Record{}, false
+	}
+
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, "{") {
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(trimmed), &obj); err == nil {
+			return parseObject(source, obj, collectedAt)
+		}
+	}
+
+	return parseText(source, line, collectedAt), true
+}
+
+// parseObject converts a decoded JSON line into a record.
+func parseObject(source Source, obj map[string]any, collectedAt time.Time) (Record, bool) {
+	if _, ok := obj["jsonrpc"]; ok {
+		return parseJSONRPC(source, obj, collectedAt)
+	}
+
+	// Lines previously written by a Collector are already records
+	if _, ok := obj["source"]; ok {
+		if record, ok := decodeRecord(obj); ok {
+			return record, true
+		}
+	}
+
+	record := Record{Time: collectedAt, Source: source}
+	if t, key, ok := lookupTime(obj); ok {
+		record.Time = t
+		delete(obj, key)
+	}
+	if v, key, ok := lookupString(obj, levelKeys); ok {
+		if level, ok := ParseLevel(v); ok {
+			record.Level = level
+			delete(obj, key)
+		}
+	}
+	if v, key, ok := lookupString(obj, messageKeys); ok {
+		record.Message = v
+		delete(obj, key)
+	}
+	if v, key, ok := lookupString(obj, loggerKeys); ok {
+		record.Logger = v
+		delete(obj, key)
+	}
+	if len(obj) > 0 {
+		record.Fields = obj
+	}
+	return record, true
+}
+
+// parseJSONRPC extracts MCP log notifications. Any other JSON-RPC message is
+// protocol traffic and is skipped.
+func parseJSONRPC(source Source, obj map[string]any, collectedAt time.Time) (Record, bool) {
+	if method, _ := obj["method"].(string); method != mcpLogMethod {
+		return Record{}, false
+	}
+
+	params, _ := obj["params"].(map[string]any)
+	record := Record{Time: collectedAt, Source: source}
+	if level, ok := ParseLevel(fmt.Sprint(params["level"])); ok {
+		record.Level = level
+	}
+	record.Logger, _ = params["logger"].(string)
+
+	switch data := params["data"].(type) {
+	case string:
+		record.Message = data
+	case map[string]any:
+		if msg, key, ok := lookupString(data, messageKeys); ok {
+			record.Message = msg
+			delete(data, key)
+		}
+		if len(data) > 0 {
+			record.Fields = data
+		}
+	case nil:
+	default:
+		encoded, _ := json.Marshal(data)
+		record.Message = string(encoded)
+	}
+	return record, true
+}
+
+// parseText parses a plain text line. Console loggers usually emit the
+// timestamp and level as the first whitespace-separated fields, so those are
+// detected when present.
+func parseText(source Source, line string, collectedAt time.Time) Record {
+	record := Record{Time: collectedAt, Source: source, Message: strings.TrimSpace(line)}
+
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return record
+	}
+
+	rest := line
+	consume := func(field string) {
+		rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), field))
+	}
+
+	if t, ok := parseTimestamp(fields[0]); ok {
+		record.Time = t
+		consume(fields[0])
+		fields = fields[1:]
+	}
+	// Only upper-case or bracketed tokens are taken as levels, so that a
+	// message such as "error connecting" keeps its first word
+	if len(fields) > 0 && (fields[0] == strings.ToUpper(fields[0]) || strings.HasPrefix(fields[0], "[")) {
+		if level, ok := ParseLevel(strings.Trim(fields[0], "[]:")); ok {
+			record.Level = level
+			consume(fields[0])
+		}
+	}
+	record.Message = rest
+	return record
+}
+
+// decodeRecord converts an object previously encoded from a Record.
+func decodeRecord(obj map[string]any) (Record, bool) {
+	encoded, err := json.Marshal(obj)
+	if err != nil {
+		return Record{}, false
+	}
+	var record Record
+	if err := json.Unmarshal(encoded, &record); err != nil || record.Source == "" || record.Time.IsZero() {
+		return Record{}, false
+	}
+	return record, true
+}
+
+// lookupString returns the first string value found under one of keys.
+func lookupString(obj map[string]any, keys []string) (string, string, bool) {
+	for _, key := range keys {
+		if v, ok := obj[key].(string); ok {
+			return v, key, true
+		}
+	}
+	return "", "", false
+}
+
+// lookupTime returns the first timestamp found under one of the well-known time keys.
+// Both RFC 3339 strings and Unix epoch seconds are supported.
+func lookupTime(obj map[string]any) (time.Time, string, bool) {
+	for _, key := range timeKeys {
+		switch v := obj[key].(type) {
+		case string:
+			if t, ok := parseTimestamp(v); ok {
+				return t, key, true
+			}
+		case float64:
+			sec, frac := splitFloat(v)
+			return time.Unix(sec, frac).UTC(), key, true
+		}
+	}
+	return time.Time{}, "", false
+}
+
+// parseTimestamp parses the timestamp formats commonly emitted by loggers.
+func parseTimestamp(s string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.000Z0700", "2006-01-02T15:04:05Z0700"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && f > 1e9 {
+		sec, frac := splitFloat(f)
+		return time.Unix(sec, frac).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+// splitFloat splits epoch seconds into whole seconds and nanoseconds.
+func splitFloat(f float64) (int64, int64) {
+	sec := int64(f)
+	return sec, int64((f - float64(sec)) * 1e9)
+}
@@ -0,0 +1,170 @@
+package logs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLine(t *testing.T) {
+	t.Parallel()
+
+	collectedAt := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		line     string
+		wantOK   bool
+		expected Record
+	}{
+		{
+			name:   "blank line",
+			line:   "   ",
+			wantOK: false,
+		},
+		{
+			name:   "plain text",
+			line:   "server listening on :8080",
+			wantOK: true,
+			expected: Record{
+				Time:    collectedAt,
+				Source:  SourceContainer,
+				Message: "server listening on :8080",
+			},
+		},
+		{
+			name:   "plain text starting with a lower-case level word",
+			line:   "error connecting to upstream",
+			wantOK: true,
+			expected: Record{
+				Time:    collectedAt,
+				Source:  SourceContainer,
+				Message: "error connecting to upstream",
+			},
+		},
+		{
+			name:   "console logger line",
+			line:   "2025-10-01T10:00:00Z WARN disk almost full",
+			wantOK: true,
+			expected: Record{
+				Time:    time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC),
+				Level:   LevelWarning,
+				Source:  SourceContainer,
+				Message: "disk almost full",
+			},
+		},
+		{
+			name:   "colored level",
+			line:   "\x1b[31mERROR\x1b[0m\tfailed to start",
+			wantOK: true,
+			expected: Record{
+				Time:    collectedAt,
+				Level:   LevelError,
+				Source:  SourceContainer,
+				Message: "failed to start",
+			},
+		},
+		{
+			name:   "zap JSON line",
+			line:   `{"level":"info","ts":1759320000.5,"caller":"main.go:10","msg":"started","port":8080}`,
+			wantOK: true,
+			expected: Record{
+				Time:    time.Unix(1759320000, 500000000).UTC(),
+				Level:   LevelInfo,
+				Source:  SourceContainer,
+				Message: "started",
+				Fields:  map[string]any{"caller": "main.go:10", "port": float64(8080)},
+			},
+		},
+		{
+			name:   "python JSON line",
+			line:   `{"timestamp":"2025-10-01T11:00:00Z","levelname":"WARNING","message":"slow","name":"app"}`,
+			wantOK: true,
+			expected: Record{
+				Time:    time.Date(2025, 10, 1, 11, 0, 0, 0, time.UTC),
+				Level:   LevelWarning,
+				Source:  SourceContainer,
+				Logger:  "app",
+				Message: "slow",
+			},
+		},
+		{
+			name:   "MCP log notification with string data",
+			line:   `{"jsonrpc":"2.0","method":"notifications/message","params":{"level":"error","logger":"db","data":"connection lost"}}`,
+			wantOK: true,
+			expected: Record{
+				Time:    collectedAt,
+				Level:   LevelError,
+				Source:  SourceContainer,
+				Logger:  "db",
+				Message: "connection lost",
+			},
+		},
+		{
+			name:   "MCP log notification with object data",
+			line:   `{"jsonrpc":"2.0","method":"notifications/message","params":{"level":"notice","data":{"message":"cache warm","entries":3}}}`,
+			wantOK: true,
+			expected: Record{
+				Time:    collectedAt,
+				Level:   LevelNotice,
+				Source:  SourceContainer,
+				Message: "cache warm",
+				Fields:  map[string]any{"entries": float64(3)},
+			},
+		},
+		{
+			name:   "JSON-RPC response is protocol traffic",
+			line:   `{"jsonrpc":"2.0","id":1,"result":{"tools":[]}}`,
+			wantOK: false,
+		},
+		{
+			name:   "JSON-RPC request is protocol traffic",
+			line:   `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+			wantOK: false,
+		},
+		{
+			name:   "previously collected record",
+			line:   `{"time":"2025-10-01T09:00:00Z","level":"debug","source":"container","message":"hello"}`,
+			wantOK: true,
+			expected: Record{
+				Time:    time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC),
+				Level:   LevelDebug,
+				Source:  SourceContainer,
+				Message: "hello",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			record, ok := ParseLine(SourceContainer, tt.line, collectedAt)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.expected, record)
+			}
+		})
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]Level{
+		"WARN":    LevelWarning,
+		"warning": LevelWarning,
+		"Fatal":   LevelCritical,
+		"trace":   LevelDebug,
+		"emerg":   LevelEmergency,
+	}
+	for name, expected := range tests {
+		level, ok := ParseLevel(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, expected, level, name)
+	}
+
+	_, ok := ParseLevel("verbose")
+	assert.False(t, ok)
+}
@@ -0,0 +1,122 @@
+// Package logs provides structured log collection for ToolHive workloads.
+// It captures container and proxy output into rotated files on disk, parses
+// JSON lines and MCP notifications/message entries into structured records,
+// and supports filtering them by level, time window and pattern.
+package logs
+
+import (
+	"strings"
+	"time"
+)
+
+// Source identifies where a log record came from.
+type Source string
+
+const (
+	// SourceContainer identifies output of the MCP server container
+	SourceContainer Source = "container"
+	// SourceProxy identifies output of the ToolHive proxy process
+	SourceProxy Source = "proxy"
+)
+
+// Level is the severity of a log record.
+// The levels follow the syslog severities used by MCP logging notifications.
+type Level string
+
+const (
+	// LevelDebug is the debug level
+	LevelDebug Level = "debug"
+	// LevelInfo is the info level
+	LevelInfo Level = "info"
+	// LevelNotice is the notice level
+	LevelNotice Level = "notice"
+	// LevelWarning is the warning level
+	LevelWarning Level = "warning"
+	// LevelError is the error level
+	LevelError Level = "error"
+	// LevelCritical is the critical level
+	LevelCritical Level = "critical"
+	// LevelAlert is the alert level
+	LevelAlert Level = "alert"
+	// LevelEmergency is the emergency level
+	LevelEmergency Level = "emergency"
+)
+
+// levelSeverity maps levels to their ordering, from least to most severe.
+var levelSeverity = map[Level]int{
+	LevelDebug:     1,
+	LevelInfo:      2,
+	LevelNotice:    3,
+	LevelWarning:   4,
+	LevelError:     5,
+	LevelCritical:  6,
+	LevelAlert:     7,
+	LevelEmergency: 8,
+}
+
+// levelAliases maps common level spellings of logging libraries to a Level.
+var levelAliases = map[string]Level{
+	"trace":     LevelDebug,
+	"debug":     LevelDebug,
+	"info":      LevelInfo,
+	"notice":    LevelNotice,
+	"warn":      LevelWarning,
+	"warning":   LevelWarning,
+	"err":       LevelError,
+	"error":     LevelError,
+	"crit":      LevelCritical,
+	"critical":  LevelCritical,
+	"dpanic":    LevelCritical,
+	"panic":     LevelCritical,
+	"fatal":     LevelCritical,
+	"alert":     LevelAlert,
+	"emerg":     LevelEmergency,
+	"emergency": LevelEmergency,
+}
+
+// ParseLevel normalizes a level name as emitted by common logging libraries.
+// It returns false if the name is not a known level.
+func ParseLevel(name string) (Level, bool) {
+	level, ok := levelAliases[strings.ToLower(strings.TrimSpace(name))]
+	return level, ok
+}
+
+// Severity returns the ordering of the level. Unknown levels have severity 0.
+func (l Level) Severity() int {
+	return levelSeverity[l]
+}
+
+// Record is a single structured log entry.
+type Record struct {
+	// Time is when the entry was produced, or when it was collected if the
+	// entry carries no timestamp
+	Time time.Time `json:"time"`
+	// Level is the severity of the entry, empty if unknown
+	Level Level `json:"level,omitempty"`
+	// Source identifies the producer of the entry
+	Source Source `json:"source"`
+	// Logger is the name of the logger, e.g. from MCP notifications/message
+	Logger string `json:"logger,omitempty"`
+	// Message is the human-readable message
+	Message string `json:"message"`
+	// Fields contains any additional structured fields of the entry
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+// String formats the record as a single human-readable line.
+func (r Record) String() string {
+	var b strings.Builder
+	b.WriteString(r.Time.Format(time.RFC3339))
+	if r.Level != "" {
+		b.WriteString(" ")
+		b.WriteString(strings.ToUpper(string(r.Level)))
+	}
+	if r.Logger != "" {
+		b.WriteString(" [")
+		b.WriteString(r.Logger)
+		b.WriteString("]")
+	}
+	b.WriteString(" ")
+	b.WriteString(r.Message)
+	return b.String()
+}
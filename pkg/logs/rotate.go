@@ -0,0 +1,253 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxSizeMB is the default size at which a log file is rotated
+	DefaultMaxSizeMB = 10
+	// DefaultMaxFiles is the default number of rotated files kept per log
+	DefaultMaxFiles = 5
+	// DefaultMaxAgeDays is the default number of days rotated files are kept
+	DefaultMaxAgeDays = 7
+)
+
+// RetentionPolicy controls how log files are rotated and how long they are kept.
+// Zero values are replaced by the defaults.
+type RetentionPolicy struct {
+	// MaxSizeMB is the size in megabytes at which a log file is rotated
+	MaxSizeMB int `yaml:"max_size_mb,omitempty" json:"max_size_mb,omitempty"`
+	// MaxFiles is the number of rotated files kept in addition to the active one
+	MaxFiles int `yaml:"max_files,omitempty" json:"max_files,omitempty"`
+	// MaxAgeDays is the number of days after which rotated files are removed
+	MaxAgeDays int `yaml:"max_age_days,omitempty" json:"max_age_days,omitempty"`
+}
+
+// WithDefaults returns a copy of the policy with unset values replaced by the defaults.
+func (p RetentionPolicy) WithDefaults() RetentionPolicy {
+	if p.MaxSizeMB <= 0 {
+		p.MaxSizeMB = DefaultMaxSizeMB
+	}
+	if p.MaxFiles <= 0 {
+		p.MaxFiles = DefaultMaxFiles
+	}
+	if p.MaxAgeDays <= 0 {
+		p.MaxAgeDays = DefaultMaxAgeDays
+	}
+	return p
+}
+
+// maxSize returns the rotation size in bytes.
+func (p RetentionPolicy) maxSize() int64 {
+	return int64(p.WithDefaults().MaxSizeMB) * 1024 * 1024
+}
+
+// maxAge returns the retention duration of rotated files.
+func (p RetentionPolicy) maxAge() time.Duration {
+	return time.Duration(p.WithDefaults().MaxAgeDays) * 24 * time.Hour
+}
+
+// RotatingWriter is an io.WriteCloser writing to a log file which is rotated
+// once it reaches the policy's maximum size. Rotated files are named
+// <path>.1 (most recent) to <path>.N (oldest).
+type RotatingWriter struct {
+	path   string
+	policy RetentionPolicy
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewRotatingWriter opens or creates the log file at path for appending.
+func NewRotatingWriter(path string, policy RetentionPolicy) (*RotatingWriter, error) {
+	w := &RotatingWriter{path: path, policy: policy.WithDefaults()}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write writes p to the log file, rotating it first if p would exceed the maximum size.
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, fs.ErrClosed
+	}
+
+	if w.size > 0 && w.size+int64(len(p)) > w.policy.maxSize() {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close closes the log file.
+func (w *RotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// open opens the active log file and records its current size.
+func (w *RotatingWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0750); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	// #nosec G304 - the path is built by the Store from a workload name
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open log file %s: %w", w.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat log file %s: %w", w.path, err)
+	}
+
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+// rotate closes the active file, shifts the rotated files and reopens a new active file.
+func (w *RotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file %s: %w", w.path, err)
+	}
+	w.file = nil
+
+	if err := Rotate(w.path, w.policy); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+// Rotate moves the log file at path to <path>.1, shifting older rotated files and
+// removing those beyond the policy's limits. It is a no-op if path does not exist.
+func Rotate(path string, policy RetentionPolicy) error {
+	policy = policy.WithDefaults()
+
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	// Drop the oldest file, then shift the rest up by one
+	if err := removeIfExists(rotatedPath(path, policy.MaxFiles)); err != nil {
+		return err
+	}
+	for i := policy.MaxFiles - 1; i >= 1; i-- {
+		if err := renameIfExists(rotatedPath(path, i), rotatedPath(path, i+1)); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(path, rotatedPath(path, 1)); err != nil {
+		return fmt.Errorf("failed to rotate log file %s: %w", path, err)
+	}
+
+	return ApplyRetention(path, policy)
+}
+
+// RotateIfNeeded rotates the log file at path if it has reached the maximum size.
+// This is used for log files written directly by another process, which cannot be
+// rotated while they are written.
+func RotateIfNeeded(path string, policy RetentionPolicy) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stat log file %s: %w", path, err)
+	}
+	if info.Size() < policy.maxSize() {
+		return ApplyRetention(path, policy)
+	}
+	return Rotate(path, policy)
+}
+
+// ApplyRetention removes rotated files of the log at path which are older than the
+// policy's maximum age or beyond its maximum number of files.
+func ApplyRetention(path string, policy RetentionPolicy) error {
+	policy = policy.WithDefaults()
+	cutoff := time.Now().Add(-policy.maxAge())
+
+	for _, rotated := range rotatedFiles(path) {
+		index := rotatedIndex(path, rotated)
+		info, err := os.Stat(rotated)
+		if err != nil {
+			continue
+		}
+		if index > policy.MaxFiles || info.ModTime().Before(cutoff) {
+			if err := removeIfExists(rotated); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rotatedPath returns the name of the i-th rotated file of path.
+func rotatedPath(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
+
+// rotatedFiles lists the rotated files of path, in no particular order.
+func rotatedFiles(path string) []string {
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, match := range matches {
+		if rotatedIndex(path, match) > 0 {
+			files = append(files, match)
+		}
+	}
+	return files
+}
+
+// rotatedIndex returns the rotation index of a rotated file of path, or 0.
+func rotatedIndex(path, rotated string) int {
+	var index int
+	if _, err := fmt.Sscanf(rotated[len(path):], ".%d", &index); err != nil {
+		return 0
+	}
+	if rotatedPath(path, index) != rotated {
+		return 0
+	}
+	return index
+}
+
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove log file %s: %w", path, err)
+	}
+	return nil
+}
+
+func renameIfExists(from, to string) error {
+	if err := os.Rename(from, to); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to rotate log file %s: %w", from, err)
+	}
+	return nil
+}
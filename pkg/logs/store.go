@@ -0,0 +1,289 @@
+package logs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+const (
+	// containerLogsDir is the subdirectory holding container logs
+	containerLogsDir = "containers"
+	// logFileSuffix is the suffix of active log files
+	logFileSuffix = ".log"
+	// maxLineSize is the largest log line read from a file or stream
+	maxLineSize = 1024 * 1024
+	// followPollInterval is how often a followed log file is checked for new content
+	followPollInterval = 250 * time.Millisecond
+)
+
+// Store manages the log files of workloads on disk.
+// Proxy logs are kept at <dir>/<workload>.log, container logs at
+// <dir>/containers/<workload>.log, each with their rotated files alongside.
+type Store struct {
+	dir    string
+	policy RetentionPolicy
+}
+
+// NewStore creates a store rooted at dir.
+func NewStore(dir string, policy RetentionPolicy) *Store {
+	return &Store{dir: dir, policy: policy.WithDefaults()}
+}
+
+// DefaultDir returns the directory where ToolHive keeps workload logs.
+func DefaultDir() (string, error) {
+	dir, err := xdg.DataFile("toolhive/logs")
+	if err != nil {
+		return "", fmt.Errorf("failed to get logs directory path: %w", err)
+	}
+	return dir, nil
+}
+
+// NewDefaultStore creates a store in the default logs directory.
+func NewDefaultStore(policy RetentionPolicy) (*Store, error) {
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(dir, policy), nil
+}
+
+// Dir returns the root directory of the store.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Policy returns the retention policy of the store.
+func (s *Store) Policy() RetentionPolicy {
+	return s.policy
+}
+
+// Path returns the path of the active log file of a workload for the given source.
+func (s *Store) Path(workloadName string, source Source) string {
+	name := filepath.Base(filepath.Clean(workloadName)) + logFileSuffix
+	if source == SourceContainer {
+		return filepath.Join(s.dir, containerLogsDir, name)
+	}
+	return filepath.Join(s.dir, name)
+}
+
+// Files returns the existing log files of a workload for the given source,
+// from the oldest rotated file to the active one.
+func (s *Store) Files(workloadName string, source Source) []string {
+	path := s.Path(workloadName, source)
+
+	rotated := rotatedFiles(path)
+	sort.Slice(rotated, func(i, j int) bool {
+		return rotatedIndex(path, rotated[i]) > rotatedIndex(path, rotated[j])
+	})
+
+	files := rotated
+	if _, err := os.Stat(path); err == nil {
+		files = append(files, path)
+	}
+	return files
+}
+
+// Writer opens a rotating writer for the log of a workload for the given source.
+func (s *Store) Writer(workloadName string, source Source) (*RotatingWriter, error) {
+	return NewRotatingWriter(s.Path(workloadName, source), s.policy)
+}
+
+// Read returns the records of a workload for the given source which match the filter.
+// It returns an empty slice if the workload has no logs.
+func (s *Store) Read(workloadName string, source Source, filter *Filter) ([]Record, error) {
+	var records []Record
+	for _, file := range s.Files(workloadName, source) {
+		err := readFile(file, source, func(record Record) error {
+			if filter.Match(record) {
+				records = append(records, record)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// Follow calls fn for every record appended to the log of a workload after Follow
+// was called and matching the filter, until ctx is done or fn returns an error.
+// Rotation of the active file is detected and followed.
+func (s *Store) Follow(
+	ctx context.Context, workloadName string, source Source, filter *Filter, fn func(Record) error,
+) error {
+	path := s.Path(workloadName, source)
+
+	var (
+		file   *os.File
+		reader *bufio.Reader
+		offset int64
+		// Records present when following starts are skipped
+		skipExisting = true
+	)
+	defer func() {
+		if file != nil {
+			_ = file.Close()
+		}
+	}()
+
+	ticker := time.NewTicker(followPollInterval)
+	defer ticker.Stop()
+
+	for {
+		info, statErr := os.Stat(path)
+		if statErr == nil && file != nil {
+			// Reopen the file if it was rotated or truncated
+			current, err := file.Stat()
+			if err != nil || !os.SameFile(info, current) || info.Size() < offset {
+				_ = file.Close()
+				file = nil
+			}
+		}
+
+		if file == nil && statErr == nil {
+			// #nosec G304 - the path is built from a workload name
+			f, err := os.Open(path)
+			if err != nil {
+				return fmt.Errorf("failed to open log file %s: %w", path, err)
+			}
+			file, offset = f, 0
+			if skipExisting {
+				if offset, err = file.Seek(0, io.SeekEnd); err != nil {
+					return fmt.Errorf("failed to seek log file %s: %w", path, err)
+				}
+			}
+			reader = bufio.NewReaderSize(file, 64*1024)
+		}
+		skipExisting = false
+
+		if reader != nil {
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					// Keep partial lines until they are complete
+					if len(line) > 0 {
+						if _, seekErr := file.Seek(offset, io.SeekStart); seekErr == nil {
+							reader.Reset(file)
+						}
+					}
+					break
+				}
+				offset += int64(len(line))
+				if record, ok := ParseLine(source, line, time.Now()); ok && filter.Match(record) {
+					if err := fn(record); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Remove deletes all log files of a workload.
+func (s *Store) Remove(workloadName string) error {
+	var errs []error
+	for _, source := range []Source{SourceProxy, SourceContainer} {
+		for _, file := range s.Files(workloadName, source) {
+			if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Workloads returns the names of all workloads which have log files in the store.
+func (s *Store) Workloads() ([]string, error) {
+	seen := map[string]bool{}
+	for _, dir := range []string{s.dir, filepath.Join(s.dir, containerLogsDir)} {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list log files: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			if name, ok := workloadFromFileName(entry.Name()); ok {
+				seen[name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// workloadFromFileName returns the workload name of an active or rotated log file name.
+func workloadFromFileName(fileName string) (string, bool) {
+	base := fileName
+	if ext := filepath.Ext(base); ext != logFileSuffix {
+		// Rotated files are named <workload>.log.<n>
+		base = base[:len(base)-len(ext)]
+		if rotatedIndex(base, fileName) == 0 {
+			return "", false
+		}
+	}
+	if filepath.Ext(base) != logFileSuffix {
+		return "", false
+	}
+	return base[:len(base)-len(logFileSuffix)], true
+}
+
+// readFile calls fn for every record of a log file.
+func readFile(path string, source Source, fn func(Record) error) error {
+	// #nosec G304 - the path is built from a workload name
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open log file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat log file %s: %w", path, err)
+	}
+
+	// Lines without a timestamp get the time the file was last written
+	fallback := info.ModTime()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		if record, ok := ParseLine(source, scanner.Text(), fallback); ok {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read log file %s: %w", path, err)
+	}
+	return nil
+}
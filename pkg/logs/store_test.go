@@ -0,0 +1,197 @@
+package logs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingWriter_Rotates(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "server.log")
+	policy := RetentionPolicy{MaxSizeMB: 1, MaxFiles: 2}
+
+	w, err := NewRotatingWriter(path, policy)
+	require.NoError(t, err)
+
+	chunk := []byte(strings.Repeat("x", 600*1024) + "\n")
+	for i := 0; i < 5; i++ {
+		_, err := w.Write(chunk)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	assert.FileExists(t, path)
+	assert.FileExists(t, path+".1")
+	assert.FileExists(t, path+".2")
+	assert.NoFileExists(t, path+".3")
+
+	_, err = w.Write(chunk)
+	assert.Error(t, err)
+}
+
+func TestApplyRetention_RemovesExpiredFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "server.log")
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		require.NoError(t, os.WriteFile(name, []byte("line\n"), 0600))
+	}
+	old := time.Now().Add(-10 * 24 * time.Hour)
+	require.NoError(t, os.Chtimes(path+".2", old, old))
+
+	require.NoError(t, ApplyRetention(path, RetentionPolicy{MaxAgeDays: 7}))
+
+	assert.FileExists(t, path)
+	assert.FileExists(t, path+".1")
+	assert.NoFileExists(t, path+".2")
+}
+
+func TestRotateIfNeeded(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "server.log")
+
+	// Missing files are ignored
+	require.NoError(t, RotateIfNeeded(path, RetentionPolicy{}))
+
+	require.NoError(t, os.WriteFile(path, []byte("small\n"), 0600))
+	require.NoError(t, RotateIfNeeded(path, RetentionPolicy{MaxSizeMB: 1}))
+	assert.NoFileExists(t, path+".1")
+
+	require.NoError(t, os.WriteFile(path, []byte(strings.Repeat("x", 2*1024*1024)), 0600))
+	require.NoError(t, RotateIfNeeded(path, RetentionPolicy{MaxSizeMB: 1}))
+	assert.NoFileExists(t, path)
+	assert.FileExists(t, path+".1")
+}
+
+func TestStore_CollectAndRead(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(t.TempDir(), RetentionPolicy{})
+
+	w, err := store.Writer("fetch", SourceContainer)
+	require.NoError(t, err)
+
+	output := strings.Join([]string{
+		`{"level":"debug","ts":"2025-10-01T10:00:00Z","msg":"booting"}`,
+		`{"jsonrpc":"2.0","id":1,"result":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/message","params":{"level":"error","data":"upstream down"}}`,
+		`plain output`,
+	}, "\n")
+	require.NoError(t, NewCollector(SourceContainer, w).Collect(strings.NewReader(output)))
+	require.NoError(t, w.Close())
+
+	all, err := store.Read("fetch", SourceContainer, nil)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "booting", all[0].Message)
+	assert.Equal(t, time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC), all[0].Time)
+	assert.Equal(t, "upstream down", all[1].Message)
+	assert.Equal(t, "plain output", all[2].Message)
+
+	errorsOnly, err := store.Read("fetch", SourceContainer, &Filter{MinLevel: LevelError})
+	require.NoError(t, err)
+	require.Len(t, errorsOnly, 1)
+	assert.Equal(t, "upstream down", errorsOnly[0].Message)
+
+	missing, err := store.Read("other", SourceContainer, nil)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+}
+
+func TestStore_ReadAcrossRotatedFiles(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(t.TempDir(), RetentionPolicy{})
+	path := store.Path("fetch", SourceProxy)
+	require.NoError(t, os.WriteFile(path+".2", []byte("oldest\n"), 0600))
+	require.NoError(t, os.WriteFile(path+".1", []byte("older\n"), 0600))
+	require.NoError(t, os.WriteFile(path, []byte("newest\n"), 0600))
+
+	records, err := store.Read("fetch", SourceProxy, nil)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "oldest", records[0].Message)
+	assert.Equal(t, "older", records[1].Message)
+	assert.Equal(t, "newest", records[2].Message)
+}
+
+func TestStore_WorkloadsAndRemove(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(t.TempDir(), RetentionPolicy{})
+	for _, source := range []Source{SourceProxy, SourceContainer} {
+		w, err := store.Writer("fetch", source)
+		require.NoError(t, err)
+		_, err = w.Write([]byte("line\n"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	}
+	require.NoError(t, os.WriteFile(store.Path("github", SourceProxy)+".1", []byte("line\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(store.Dir(), "notes.txt"), []byte("x"), 0600))
+
+	names, err := store.Workloads()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fetch", "github"}, names)
+
+	require.NoError(t, store.Remove("fetch"))
+	assert.Empty(t, store.Files("fetch", SourceProxy))
+	assert.Empty(t, store.Files("fetch", SourceContainer))
+}
+
+func TestStore_Follow(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(t.TempDir(), RetentionPolicy{})
+	w, err := store.Writer("fetch", SourceProxy)
+	require.NoError(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte("INFO existing line\n"))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := make(chan Record, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- store.Follow(ctx, "fetch", SourceProxy, &Filter{MinLevel: LevelWarning}, func(r Record) error {
+			received <- r
+			return nil
+		})
+	}()
+
+	// Give the follower time to open the file before appending
+	time.Sleep(2 * followPollInterval)
+	for i, level := range []string{"INFO", "ERROR", "WARN"} {
+		_, err = fmt.Fprintf(w, "%s appended %d\n", level, i)
+		require.NoError(t, err)
+	}
+
+	var messages []string
+	for len(messages) < 2 {
+		select {
+		case r := <-received:
+			messages = append(messages, r.Message)
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for records, got %v", messages)
+		}
+	}
+	assert.Equal(t, []string{"appended 1", "appended 2"}, messages)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
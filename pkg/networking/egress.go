@@ -0,0 +1,95 @@
+package networking
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EgressConnection describes an outbound connection which was handled by the egress proxy
+type EgressConnection struct {
+	// Time is when the connection was handled
+	Time time.Time `json:"time"`
+	// Client is the address of the container which attempted the connection
+	Client string `json:"client"`
+	// Action is the result of the connection, e.g. TCP_TUNNEL or TCP_DENIED
+	Action string `json:"action"`
+	// Method is the HTTP method of the request, which is CONNECT for HTTPS connections
+	Method string `json:"method"`
+	// Destination is the URL, or host and port, of the connection
+	Destination string `json:"destination"`
+}
+
+// Denied returns true if the connection was denied by the network permissions
+func (c EgressConnection) Denied() bool {
+	return c.Action == "TCP_DENIED"
+}
+
+// Host returns the host name or IP address of the destination
+func (c EgressConnection) Host() string {
+	host, _ := c.hostPort()
+	return host
+}
+
+// Port returns the port of the destination, defaulting to the port of the URL scheme
+func (c EgressConnection) Port() int {
+	_, port := c.hostPort()
+	return port
+}
+
+func (c EgressConnection) hostPort() (string, int) {
+	destination := c.Destination
+	defaultPort := 443
+	if scheme, rest, ok := strings.Cut(destination, "://"); ok {
+		destination = rest
+		if strings.EqualFold(scheme, "http") {
+			defaultPort = 80
+		}
+	}
+	destination, _, _ = strings.Cut(destination, "/")
+
+	host, portValue := destination, ""
+	if strings.HasPrefix(destination, "[") {
+		var rest string
+		host, rest, _ = strings.Cut(strings.TrimPrefix(destination, "["), "]")
+		portValue = strings.TrimPrefix(rest, ":")
+	} else {
+		host, portValue, _ = strings.Cut(destination, ":")
+	}
+
+	port, err := strconv.Atoi(portValue)
+	if err != nil {
+		port = defaultPort
+	}
+	return host, port
+}
+
+// ParseEgressConnection parses a line of the access log of the egress proxy, which uses the native
+// squid format "time elapsed client action/status size method URL user hierarchy type".
+// It returns false if the line is not an access log entry.
+func ParseEgressConnection(line string) (EgressConnection, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 7 {
+		return EgressConnection{}, false
+	}
+
+	action, _, ok := strings.Cut(fields[3], "/")
+	if !ok || !strings.HasPrefix(action, "TCP_") {
+		return EgressConnection{}, false
+	}
+
+	timestamp, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return EgressConnection{}, false
+	}
+	seconds, fraction := math.Modf(timestamp)
+
+	return EgressConnection{
+		Time:        time.Unix(int64(seconds), int64(fraction*1e9)).UTC(),
+		Client:      fields[2],
+		Action:      action,
+		Method:      fields[5],
+		Destination: fields[6],
+	}, true
+}
@@ -0,0 +1,70 @@
+package networking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEgressConnection_Denied(t *testing.T) {
+	t.Parallel()
+
+	denial, ok := ParseEgressConnection(
+		"1700000000.250      0 172.18.0.3 TCP_DENIED/403 3890 CONNECT evil.example.com:443 - HIER_NONE/- text/html")
+	require.True(t, ok)
+	assert.True(t, denial.Denied())
+	assert.Equal(t, time.Unix(1700000000, 250000000).UTC(), denial.Time)
+	assert.Equal(t, "172.18.0.3", denial.Client)
+	assert.Equal(t, "TCP_DENIED", denial.Action)
+	assert.Equal(t, "CONNECT", denial.Method)
+	assert.Equal(t, "evil.example.com:443", denial.Destination)
+	assert.Equal(t, "evil.example.com", denial.Host())
+
+	denial, ok = ParseEgressConnection(
+		"1700000000.250      1 172.18.0.3 TCP_DENIED/403 3890 GET http://10.0.0.1:8080/path - HIER_NONE/- text/html")
+	require.True(t, ok)
+	assert.True(t, denial.Denied())
+	assert.Equal(t, "10.0.0.1", denial.Host())
+
+	_, ok = ParseEgressConnection("")
+	assert.False(t, ok)
+}
+
+func TestParseEgressConnection(t *testing.T) {
+	t.Parallel()
+
+	connection, ok := ParseEgressConnection(
+		"1700000000.250    120 172.18.0.3 TCP_TUNNEL/200 5120 CONNECT api.github.com:443 - HIER_DIRECT/140.82.112.6 -")
+	require.True(t, ok)
+	assert.False(t, connection.Denied())
+	assert.Equal(t, "TCP_TUNNEL", connection.Action)
+	assert.Equal(t, "api.github.com", connection.Host())
+	assert.Equal(t, 443, connection.Port())
+
+	_, ok = ParseEgressConnection("2024/01/01 00:00:00| Starting Squid Cache version 6.6 for x86_64-pc-linux-musl...")
+	assert.False(t, ok)
+}
+
+func TestEgressConnection_HostPort(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		destination string
+		host        string
+		port        int
+	}{
+		{destination: "[::1]:8443", host: "::1", port: 8443},
+		{destination: "https://example.com/", host: "example.com", port: 443},
+		{destination: "http://example.com/index.html", host: "example.com", port: 80},
+		{destination: "http://example.com:8080/", host: "example.com", port: 8080},
+		{destination: "example.com:22", host: "example.com", port: 22},
+	}
+
+	for _, tc := range tests {
+		connection := EgressConnection{Destination: tc.destination}
+		assert.Equal(t, tc.host, connection.Host(), tc.destination)
+		assert.Equal(t, tc.port, connection.Port(), tc.destination)
+	}
+}
@@ -0,0 +1,127 @@
+package permissions
+
+import (
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PathAccess describes how a path under a mount was accessed
+type PathAccess struct {
+	// Path is the path on the host
+	Path string `json:"path"`
+	// Write indicates whether the path was modified, rather than only read
+	Write bool `json:"write"`
+}
+
+// Recorder records the network destinations and the mounted paths accessed by a workload,
+// and suggests the least-privilege permission profile which allows them.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu          sync.Mutex
+	hosts       map[string]struct{}
+	ports       map[int]struct{}
+	mounts      []MountDeclaration
+	mountAccess map[MountDeclaration]bool
+	paths       map[string]bool
+}
+
+// NewRecorder creates a recorder for a workload with the given mounts. Mounts which are not
+// accessed by the workload are left out of the suggested profile.
+func NewRecorder(mounts []MountDeclaration) *Recorder {
+	return &Recorder{
+		hosts:       make(map[string]struct{}),
+		ports:       make(map[int]struct{}),
+		mounts:      mounts,
+		mountAccess: make(map[MountDeclaration]bool),
+		paths:       make(map[string]bool),
+	}
+}
+
+// RecordConnection records an outbound connection to a host name or IP address and port
+func (r *Recorder) RecordConnection(host string, port int) {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hosts[host] = struct{}{}
+	if port > 0 {
+		r.ports[port] = struct{}{}
+	}
+}
+
+// RecordAccess records that a path under a mount was read or, if write is true, modified
+func (r *Recorder) RecordAccess(mount MountDeclaration, path string, write bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mountAccess[mount] = r.mountAccess[mount] || write
+	r.paths[path] = r.paths[path] || write
+}
+
+// Paths returns the paths accessed by the workload, sorted by path
+func (r *Recorder) Paths() []PathAccess {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	paths := make([]PathAccess, 0, len(r.paths))
+	for path, write := range r.paths {
+		paths = append(paths, PathAccess{Path: path, Write: write})
+	}
+	sort.Slice(paths, func(i, j int) bool { return paths[i].Path < paths[j].Path })
+	return paths
+}
+
+// SuggestedProfile returns a permission profile which allows the recorded accesses and
+// nothing else. Mounts which were modified are suggested as writable, mounts which were
+// only read as read-only, and connections to IP addresses are allowed by CIDR.
+func (r *Recorder) SuggestedProfile() *Profile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	outbound := &OutboundNetworkPermissions{
+		AllowHost: []string{},
+		AllowPort: []int{},
+	}
+	for host := range r.hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			bits := 128
+			if ip.To4() != nil {
+				bits = 32
+			}
+			outbound.AllowCIDR = append(outbound.AllowCIDR, (&net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}).String())
+			continue
+		}
+		outbound.AllowHost = append(outbound.AllowHost, host)
+	}
+	for port := range r.ports {
+		outbound.AllowPort = append(outbound.AllowPort, port)
+	}
+	sort.Strings(outbound.AllowHost)
+	sort.Strings(outbound.AllowCIDR)
+	slices.Sort(outbound.AllowPort)
+
+	profile := &Profile{
+		Read:  []MountDeclaration{},
+		Write: []MountDeclaration{},
+		Network: &NetworkPermissions{
+			Outbound: outbound,
+		},
+	}
+	for _, mount := range r.mounts {
+		write, accessed := r.mountAccess[mount]
+		switch {
+		case !accessed:
+			continue
+		case write:
+			profile.Write = append(profile.Write, mount)
+		default:
+			profile.Read = append(profile.Read, mount)
+		}
+	}
+	return profile
+}
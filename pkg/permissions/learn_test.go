@@ -0,0 +1,57 @@
+package permissions
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder_SuggestedProfile(t *testing.T) {
+	t.Parallel()
+
+	recorder := NewRecorder([]MountDeclaration{"/data:/data", "/cache:/cache", "/unused:/unused"})
+
+	var wg sync.WaitGroup
+	for _, host := range []string{"api.github.com", "API.github.com.", "10.0.0.1", "::1"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recorder.RecordConnection(host, 443)
+		}()
+	}
+	wg.Wait()
+	recorder.RecordConnection("pypi.org", 80)
+	recorder.RecordConnection("", 8080)
+	recorder.RecordAccess("/data:/data", "/data/config.json", false)
+	recorder.RecordAccess("/cache:/cache", "/cache/index", false)
+	recorder.RecordAccess("/cache:/cache", "/cache/index", true)
+	recorder.RecordAccess("/cache:/cache", "/cache/tmp", false)
+
+	profile := recorder.SuggestedProfile()
+	assert.Equal(t, []MountDeclaration{"/data:/data"}, profile.Read)
+	assert.Equal(t, []MountDeclaration{"/cache:/cache"}, profile.Write)
+	assert.Equal(t, &OutboundNetworkPermissions{
+		AllowHost: []string{"api.github.com", "pypi.org"},
+		AllowPort: []int{80, 443},
+		AllowCIDR: []string{"10.0.0.1/32", "::1/128"},
+	}, profile.Network.Outbound)
+	assert.NoError(t, profile.Network.Outbound.Validate())
+
+	assert.Equal(t, []PathAccess{
+		{Path: "/cache/index", Write: true},
+		{Path: "/cache/tmp", Write: false},
+		{Path: "/data/config.json", Write: false},
+	}, recorder.Paths())
+}
+
+func TestRecorder_SuggestedProfileWithoutAccesses(t *testing.T) {
+	t.Parallel()
+
+	profile := NewRecorder(nil).SuggestedProfile()
+	assert.Empty(t, profile.Read)
+	assert.Empty(t, profile.Write)
+	assert.False(t, profile.Network.Outbound.InsecureAllowAll)
+	assert.Empty(t, profile.Network.Outbound.AllowHost)
+	assert.Empty(t, profile.Network.Outbound.AllowPort)
+}
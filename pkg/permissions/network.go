@@ -0,0 +1,72 @@
+package permissions
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hostRegex matches host names, optionally preceded by a wildcard
+var hostRegex = regexp.MustCompile(`^(\*\.|\.)?[a-zA-Z0-9_]([a-zA-Z0-9_-]*[a-zA-Z0-9_])?(\.[a-zA-Z0-9_]([a-zA-Z0-9_-]*[a-zA-Z0-9_])?)*$`)
+
+// Validate checks that the outbound network permissions are well-formed
+func (o *OutboundNetworkPermissions) Validate() error {
+	if o == nil {
+		return nil
+	}
+
+	for _, host := range append(append([]string{}, o.AllowHost...), o.DenyHost...) {
+		if !hostRegex.MatchString(host) {
+			return fmt.Errorf("invalid host %q", host)
+		}
+	}
+
+	for _, port := range o.AllowPort {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("invalid port %d", port)
+		}
+	}
+
+	for _, cidr := range append(append([]string{}, o.AllowCIDR...), o.DenyCIDR...) {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+	}
+
+	for _, hostPort := range o.AllowHostPort {
+		if _, _, err := ParseHostPort(hostPort); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsWildcardHost returns true if the host matches a domain and all its subdomains,
+// e.g. "*.example.com" or ".example.com"
+func IsWildcardHost(host string) bool {
+	return strings.HasPrefix(host, "*.") || strings.HasPrefix(host, ".")
+}
+
+// WildcardDomain returns the domain matched by a host, without the wildcard prefix
+func WildcardDomain(host string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(host, "*"), ".")
+}
+
+// ParseHostPort parses a host and port pair in the format host:port, e.g. "api.github.com:443"
+func ParseHostPort(value string) (string, int, error) {
+	host, portValue, err := net.SplitHostPort(value)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid host and port %q: expected format host:port", value)
+	}
+	if !hostRegex.MatchString(host) && net.ParseIP(host) == nil {
+		return "", 0, fmt.Errorf("invalid host and port %q: invalid host", value)
+	}
+	port, err := strconv.Atoi(portValue)
+	if err != nil || port < 1 || port > 65535 {
+		return "", 0, fmt.Errorf("invalid host and port %q: invalid port", value)
+	}
+	return host, port, nil
+}
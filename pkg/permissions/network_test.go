@@ -0,0 +1,77 @@
+package permissions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboundNetworkPermissions_Validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		outbound    *OutboundNetworkPermissions
+		expectError bool
+	}{
+		{name: "Nil permissions", outbound: nil},
+		{
+			name: "Valid permissions",
+			outbound: &OutboundNetworkPermissions{
+				AllowHost:     []string{"example.com", "*.github.com", ".googleapis.com", "10.1.2.3"},
+				AllowPort:     []int{443},
+				AllowCIDR:     []string{"10.0.0.0/8", "fd00::/8"},
+				AllowHostPort: []string{"db.internal:5432", "[::1]:8080"},
+				DenyHost:      []string{"gist.github.com"},
+				DenyCIDR:      []string{"169.254.169.254/32"},
+			},
+		},
+		{name: "Invalid host", outbound: &OutboundNetworkPermissions{AllowHost: []string{"exa mple.com"}}, expectError: true},
+		{name: "Wildcard in the middle", outbound: &OutboundNetworkPermissions{DenyHost: []string{"api.*.com"}}, expectError: true},
+		{name: "Invalid port", outbound: &OutboundNetworkPermissions{AllowPort: []int{70000}}, expectError: true},
+		{name: "Invalid CIDR", outbound: &OutboundNetworkPermissions{DenyCIDR: []string{"10.0.0.0"}}, expectError: true},
+		{name: "Missing port", outbound: &OutboundNetworkPermissions{AllowHostPort: []string{"example.com"}}, expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.outbound.Validate()
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseHostPort(t *testing.T) {
+	t.Parallel()
+
+	host, port, err := ParseHostPort("api.github.com:443")
+	require.NoError(t, err)
+	assert.Equal(t, "api.github.com", host)
+	assert.Equal(t, 443, port)
+
+	host, port, err = ParseHostPort("*.github.com:22")
+	require.NoError(t, err)
+	assert.Equal(t, "*.github.com", host)
+	assert.Equal(t, 22, port)
+
+	_, _, err = ParseHostPort("api.github.com:https")
+	assert.Error(t, err)
+	_, _, err = ParseHostPort("api.github.com:0")
+	assert.Error(t, err)
+}
+
+func TestWildcardHosts(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsWildcardHost("*.example.com"))
+	assert.True(t, IsWildcardHost(".example.com"))
+	assert.False(t, IsWildcardHost("example.com"))
+	assert.Equal(t, "example.com", WildcardDomain("*.example.com"))
+	assert.Equal(t, "example.com", WildcardDomain(".example.com"))
+	assert.Equal(t, "example.com", WildcardDomain("example.com"))
+}
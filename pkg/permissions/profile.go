@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -42,6 +43,13 @@ type Profile struct {
 	// When true, the container has access to all host devices and capabilities
 	// Use with extreme caution as this removes most security isolation
 	Privileged bool `json:"privileged,omitempty" yaml:"privileged,omitempty"`
+
+	// Resources defines the CPU, memory and process limits of the container
+	Resources *ResourceLimits `json:"resources,omitempty" yaml:"resources,omitempty"`
+
+	// Security defines the capabilities, seccomp and AppArmor profiles, root filesystem
+	// and user of the container
+	Security *SecuritySettings `json:"security,omitempty" yaml:"security,omitempty"`
 }
 
 // NetworkPermissions defines network permissions for a container
@@ -56,13 +64,29 @@ type NetworkPermissions struct {
 // OutboundNetworkPermissions defines outbound network permissions
 type OutboundNetworkPermissions struct {
 	// InsecureAllowAll allows all outbound network connections
+	// Connections matching the deny rules are still denied
 	InsecureAllowAll bool `json:"insecure_allow_all,omitempty" yaml:"insecure_allow_all,omitempty"`
 
 	// AllowHost is a list of allowed hosts
+	// Wildcards such as "*.example.com" or ".example.com" match the domain and all its subdomains
 	AllowHost []string `json:"allow_host,omitempty" yaml:"allow_host,omitempty"`
 
 	// AllowPort is a list of allowed ports
 	AllowPort []int `json:"allow_port,omitempty" yaml:"allow_port,omitempty"`
+
+	// AllowCIDR is a list of allowed destination IP ranges, e.g. "10.0.0.0/8"
+	AllowCIDR []string `json:"allow_cidr,omitempty" yaml:"allow_cidr,omitempty"`
+
+	// AllowHostPort is a list of hosts which are allowed on a single port, in the format host:port,
+	// e.g. "api.github.com:443". These are allowed regardless of AllowPort
+	AllowHostPort []string `json:"allow_host_port,omitempty" yaml:"allow_host_port,omitempty"`
+
+	// DenyHost is a list of denied hosts, which take precedence over all allow rules
+	// Wildcards are supported as in AllowHost
+	DenyHost []string `json:"deny_host,omitempty" yaml:"deny_host,omitempty"`
+
+	// DenyCIDR is a list of denied destination IP ranges, which take precedence over all allow rules
+	DenyCIDR []string `json:"deny_cidr,omitempty" yaml:"deny_cidr,omitempty"`
 }
 
 // InboundNetworkPermissions defines inbound network permissions
@@ -501,6 +525,37 @@ func (m MountDeclaration) GetResourceType() (string, error) {
 	return schemeParts[0], nil
 }
 
+// ValidateSecretFiles checks that secret files mounted at the given container paths do not
+// conflict with the mounts of the profile. A secret file cannot be mounted at the target of
+// another mount, and no mount can target a path inside a secret file.
+func (p *Profile) ValidateSecretFiles(paths []string) error {
+	mounts := make([]MountDeclaration, 0, len(p.Read)+len(p.Write))
+	mounts = append(mounts, p.Read...)
+	mounts = append(mounts, p.Write...)
+
+	for _, secretPath := range paths {
+		if err := validatePath(secretPath); err != nil {
+			return err
+		}
+		if !strings.HasPrefix(secretPath, "/") || path.Clean(secretPath) != secretPath {
+			return fmt.Errorf("secret file path must be an absolute, clean path: %s", secretPath)
+		}
+
+		for _, mount := range mounts {
+			_, target, err := mount.Parse()
+			if err != nil {
+				return fmt.Errorf("invalid mount declaration: %s (%v)", mount, err)
+			}
+			target = path.Clean(filepath.ToSlash(target))
+			if target == secretPath || strings.HasPrefix(target, secretPath+"/") {
+				return fmt.Errorf("secret file %s conflicts with mount %s", secretPath, mount)
+			}
+		}
+	}
+
+	return nil
+}
+
 // ParseMountDeclarations parses a list of mount declarations
 func ParseMountDeclarations(declarations []string) ([]MountDeclaration, error) {
 	result := make([]MountDeclaration, 0, len(declarations))
@@ -472,3 +472,57 @@ func TestMountDeclaration_EdgeCases(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid mount declaration format")
 }
+
+func TestProfile_ValidateSecretFiles(t *testing.T) {
+	t.Parallel()
+
+	profile := &Profile{
+		Read:  []MountDeclaration{"/home/user/config:/etc/app"},
+		Write: []MountDeclaration{"/home/user/data:/data", "/home/user/token:/run/secrets/token/cache"},
+	}
+
+	tests := []struct {
+		name          string
+		paths         []string
+		errorContains string
+	}{
+		{
+			name:  "No conflicts",
+			paths: []string{"/run/secrets/api-key", "/etc/app/key.pem"},
+		},
+		{
+			name:          "Same target as a mount",
+			paths:         []string{"/data"},
+			errorContains: "conflicts with mount /home/user/data:/data",
+		},
+		{
+			name:          "Mount inside the secret file",
+			paths:         []string{"/run/secrets/token"},
+			errorContains: "conflicts with mount /home/user/token:/run/secrets/token/cache",
+		},
+		{
+			name:          "Relative path",
+			paths:         []string{"run/secrets/api-key"},
+			errorContains: "must be an absolute, clean path",
+		},
+		{
+			name:          "Unclean path",
+			paths:         []string{"/run/secrets/../api-key"},
+			errorContains: "must be an absolute, clean path",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := profile.ValidateSecretFiles(tt.paths)
+			if tt.errorContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
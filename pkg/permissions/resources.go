@@ -0,0 +1,179 @@
+package permissions
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-units"
+)
+
+// ulimitNameRegex matches the names of resource limits, e.g. "nofile" or "nproc".
+var ulimitNameRegex = regexp.MustCompile(`^[a-z]+$`)
+
+// ResourceLimits defines the resources a container may use
+type ResourceLimits struct {
+	// CPUs is the number of CPUs the container may use, e.g. "0.5" or "2"
+	CPUs string `json:"cpus,omitempty" yaml:"cpus,omitempty"`
+
+	// Memory is the maximum amount of memory the container may use, e.g. "512m" or "1Gi"
+	// Suffixes are binary multiples, so "512m" and "512Mi" are both 512 mebibytes
+	Memory string `json:"memory,omitempty" yaml:"memory,omitempty"`
+
+	// PidsLimit is the maximum number of processes in the container
+	PidsLimit int64 `json:"pids_limit,omitempty" yaml:"pids_limit,omitempty"`
+
+	// Ulimits are the resource limits of the processes in the container
+	Ulimits []Ulimit `json:"ulimits,omitempty" yaml:"ulimits,omitempty"`
+}
+
+// Ulimit defines a resource limit of the processes in a container
+type Ulimit struct {
+	// Name is the name of the limit, e.g. "nofile"
+	Name string `json:"name" yaml:"name"`
+
+	// Soft is the soft limit
+	Soft int64 `json:"soft" yaml:"soft"`
+
+	// Hard is the hard limit
+	Hard int64 `json:"hard" yaml:"hard"`
+}
+
+// IsEmpty returns true if no limits are set
+func (r *ResourceLimits) IsEmpty() bool {
+	return r == nil || (r.CPUs == "" && r.Memory == "" && r.PidsLimit == 0 && len(r.Ulimits) == 0)
+}
+
+// Validate checks that the limits are well-formed
+func (r *ResourceLimits) Validate() error {
+	if r == nil {
+		return nil
+	}
+	if _, err := r.MilliCPUs(); err != nil {
+		return err
+	}
+	if _, err := r.MemoryBytes(); err != nil {
+		return err
+	}
+	if r.PidsLimit < 0 {
+		return fmt.Errorf("invalid pids limit %d: must not be negative", r.PidsLimit)
+	}
+	seen := map[string]bool{}
+	for _, ulimit := range r.Ulimits {
+		if err := ulimit.Validate(); err != nil {
+			return err
+		}
+		if seen[ulimit.Name] {
+			return fmt.Errorf("duplicate ulimit %s", ulimit.Name)
+		}
+		seen[ulimit.Name] = true
+	}
+	return nil
+}
+
+// MilliCPUs returns the CPU limit in thousandths of a CPU, or 0 if there is no limit
+func (r *ResourceLimits) MilliCPUs() (int64, error) {
+	if r == nil || r.CPUs == "" {
+		return 0, nil
+	}
+	cpus, err := strconv.ParseFloat(r.CPUs, 64)
+	if err != nil || cpus <= 0 || math.IsInf(cpus, 0) || cpus > math.MaxInt32 {
+		return 0, fmt.Errorf("invalid CPU limit %q: must be a positive number", r.CPUs)
+	}
+	milliCPUs := int64(math.Round(cpus * 1000))
+	if milliCPUs == 0 {
+		return 0, fmt.Errorf("invalid CPU limit %q: must be at least 0.001", r.CPUs)
+	}
+	return milliCPUs, nil
+}
+
+// MemoryBytes returns the memory limit in bytes, or 0 if there is no limit
+func (r *ResourceLimits) MemoryBytes() (int64, error) {
+	if r == nil || r.Memory == "" {
+		return 0, nil
+	}
+	value := r.Memory
+	if strings.HasSuffix(strings.ToLower(value), "i") {
+		// Accept Kubernetes quantities such as "512Mi", which are not understood by RAMInBytes
+		value += "b"
+	}
+	memory, err := units.RAMInBytes(value)
+	if err != nil || memory <= 0 {
+		return 0, fmt.Errorf("invalid memory limit %q: must be a positive size, e.g. 512m or 1Gi", r.Memory)
+	}
+	return memory, nil
+}
+
+// Validate checks that the limit is well-formed
+func (u Ulimit) Validate() error {
+	if !ulimitNameRegex.MatchString(u.Name) {
+		return fmt.Errorf("invalid ulimit name %q", u.Name)
+	}
+	if u.Soft < -1 || u.Hard < -1 {
+		return fmt.Errorf("invalid ulimit %s: limits must be -1 (unlimited) or greater", u.Name)
+	}
+	if u.Hard != -1 && (u.Soft == -1 || u.Soft > u.Hard) {
+		return fmt.Errorf("invalid ulimit %s: soft limit %d exceeds hard limit %d", u.Name, u.Soft, u.Hard)
+	}
+	return nil
+}
+
+// ParseUlimit parses a ulimit in the format name=soft[:hard], e.g. "nofile=1024:2048".
+// If the hard limit is omitted, it is the same as the soft limit.
+func ParseUlimit(value string) (Ulimit, error) {
+	name, limits, ok := strings.Cut(value, "=")
+	if !ok {
+		return Ulimit{}, fmt.Errorf("invalid ulimit %q: expected format name=soft[:hard]", value)
+	}
+	softValue, hardValue, hasHard := strings.Cut(limits, ":")
+	soft, err := strconv.ParseInt(softValue, 10, 64)
+	if err != nil {
+		return Ulimit{}, fmt.Errorf("invalid ulimit %q: invalid soft limit", value)
+	}
+	hard := soft
+	if hasHard {
+		hard, err = strconv.ParseInt(hardValue, 10, 64)
+		if err != nil {
+			return Ulimit{}, fmt.Errorf("invalid ulimit %q: invalid hard limit", value)
+		}
+	}
+
+	ulimit := Ulimit{Name: name, Soft: soft, Hard: hard}
+	if err := ulimit.Validate(); err != nil {
+		return Ulimit{}, err
+	}
+	return ulimit, nil
+}
+
+// MergeResourceLimits combines resource limits in order of precedence, so that each limit
+// is taken from the first of the given limits which sets it. Returns nil if no limits are set.
+func MergeResourceLimits(limits ...*ResourceLimits) *ResourceLimits {
+	merged := &ResourceLimits{}
+	seen := map[string]bool{}
+	for _, l := range limits {
+		if l == nil {
+			continue
+		}
+		if merged.CPUs == "" {
+			merged.CPUs = l.CPUs
+		}
+		if merged.Memory == "" {
+			merged.Memory = l.Memory
+		}
+		if merged.PidsLimit == 0 {
+			merged.PidsLimit = l.PidsLimit
+		}
+		for _, ulimit := range l.Ulimits {
+			if !seen[ulimit.Name] {
+				seen[ulimit.Name] = true
+				merged.Ulimits = append(merged.Ulimits, ulimit)
+			}
+		}
+	}
+	if merged.IsEmpty() {
+		return nil
+	}
+	return merged
+}
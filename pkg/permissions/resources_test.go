@@ -0,0 +1,179 @@
+package permissions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUlimit(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		value       string
+		expected    Ulimit
+		expectError bool
+	}{
+		{
+			name:     "Soft limit only",
+			value:    "nofile=1024",
+			expected: Ulimit{Name: "nofile", Soft: 1024, Hard: 1024},
+		},
+		{
+			name:     "Soft and hard limits",
+			value:    "nofile=1024:2048",
+			expected: Ulimit{Name: "nofile", Soft: 1024, Hard: 2048},
+		},
+		{
+			name:     "Unlimited",
+			value:    "core=-1",
+			expected: Ulimit{Name: "core", Soft: -1, Hard: -1},
+		},
+		{
+			name:        "Missing limit",
+			value:       "nofile",
+			expectError: true,
+		},
+		{
+			name:        "Invalid soft limit",
+			value:       "nofile=many",
+			expectError: true,
+		},
+		{
+			name:        "Invalid hard limit",
+			value:       "nofile=1024:many",
+			expectError: true,
+		},
+		{
+			name:        "Soft limit exceeds hard limit",
+			value:       "nofile=2048:1024",
+			expectError: true,
+		},
+		{
+			name:        "Invalid name",
+			value:       "NoFile=1024",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ulimit, err := ParseUlimit(tc.value)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, ulimit)
+		})
+	}
+}
+
+func TestResourceLimits_Validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		limits      *ResourceLimits
+		expectError bool
+	}{
+		{name: "Nil limits", limits: nil},
+		{name: "Empty limits", limits: &ResourceLimits{}},
+		{
+			name: "Valid limits",
+			limits: &ResourceLimits{
+				CPUs:      "0.5",
+				Memory:    "512m",
+				PidsLimit: 100,
+				Ulimits:   []Ulimit{{Name: "nofile", Soft: 1024, Hard: 2048}},
+			},
+		},
+		{name: "Negative CPUs", limits: &ResourceLimits{CPUs: "-1"}, expectError: true},
+		{name: "Non-numeric CPUs", limits: &ResourceLimits{CPUs: "two"}, expectError: true},
+		{name: "Too few CPUs", limits: &ResourceLimits{CPUs: "0.0001"}, expectError: true},
+		{name: "Invalid memory", limits: &ResourceLimits{Memory: "lots"}, expectError: true},
+		{name: "Negative pids limit", limits: &ResourceLimits{PidsLimit: -1}, expectError: true},
+		{
+			name: "Duplicate ulimits",
+			limits: &ResourceLimits{Ulimits: []Ulimit{
+				{Name: "nofile", Soft: 1024, Hard: 1024},
+				{Name: "nofile", Soft: 2048, Hard: 2048},
+			}},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.limits.Validate()
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestResourceLimits_Quantities(t *testing.T) {
+	t.Parallel()
+
+	limits := &ResourceLimits{CPUs: "1.5", Memory: "512m"}
+	milliCPUs, err := limits.MilliCPUs()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1500), milliCPUs)
+	memory, err := limits.MemoryBytes()
+	require.NoError(t, err)
+	assert.Equal(t, int64(512*1024*1024), memory)
+
+	// Kubernetes-style suffixes are also accepted
+	limits.Memory = "1Gi"
+	memory, err = limits.MemoryBytes()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1024*1024*1024), memory)
+
+	// Unset limits are zero
+	var empty *ResourceLimits
+	milliCPUs, err = empty.MilliCPUs()
+	require.NoError(t, err)
+	assert.Zero(t, milliCPUs)
+	memory, err = empty.MemoryBytes()
+	require.NoError(t, err)
+	assert.Zero(t, memory)
+}
+
+func TestMergeResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, MergeResourceLimits())
+	assert.Nil(t, MergeResourceLimits(nil, &ResourceLimits{}))
+
+	flags := &ResourceLimits{
+		Memory:  "1g",
+		Ulimits: []Ulimit{{Name: "nofile", Soft: 4096, Hard: 4096}},
+	}
+	profile := &ResourceLimits{
+		CPUs:   "2",
+		Memory: "256m",
+	}
+	registry := &ResourceLimits{
+		CPUs:      "1",
+		PidsLimit: 100,
+		Ulimits: []Ulimit{
+			{Name: "nofile", Soft: 1024, Hard: 1024},
+			{Name: "nproc", Soft: 50, Hard: 50},
+		},
+	}
+
+	assert.Equal(t, &ResourceLimits{
+		CPUs:      "2",
+		Memory:    "1g",
+		PidsLimit: 100,
+		Ulimits: []Ulimit{
+			{Name: "nofile", Soft: 4096, Hard: 4096},
+			{Name: "nproc", Soft: 50, Hard: 50},
+		},
+	}, MergeResourceLimits(flags, nil, profile, registry))
+}
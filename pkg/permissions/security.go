@@ -0,0 +1,193 @@
+package permissions
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Seccomp profiles with a special meaning
+const (
+	// SeccompProfileMCPDefault is the built-in seccomp profile for MCP servers. It is the allowlist
+	// of the default profile of Docker, without the system calls used to administer the host or
+	// escape the container, whatever the capabilities of the container
+	SeccompProfileMCPDefault = "mcp-default"
+	// SeccompProfileRuntimeDefault is the default seccomp profile of the container runtime
+	SeccompProfileRuntimeDefault = "runtime-default"
+	// SeccompProfileUnconfined disables seccomp filtering
+	SeccompProfileUnconfined = "unconfined"
+	// SeccompProfileLocalhostPrefix is the prefix of seccomp profiles installed on Kubernetes nodes,
+	// followed by the path of the profile relative to the seccomp directory of the kubelet
+	SeccompProfileLocalhostPrefix = "localhost/"
+)
+
+// AppArmor profiles with a special meaning
+const (
+	// AppArmorProfileRuntimeDefault is the default AppArmor profile of the container runtime
+	AppArmorProfileRuntimeDefault = "runtime-default"
+	// AppArmorProfileUnconfined disables AppArmor confinement
+	AppArmorProfileUnconfined = "unconfined"
+)
+
+//go:embed seccomp/mcp-default.json
+var mcpDefaultSeccompProfile []byte
+
+var (
+	// capabilityRegex matches the names of Linux capabilities without the CAP_ prefix
+	capabilityRegex = regexp.MustCompile(`^[A-Z][A-Z_]*$`)
+	// appArmorProfileRegex matches the names of AppArmor profiles
+	appArmorProfileRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.\-/]*$`)
+)
+
+// SecuritySettings defines how a container is confined
+type SecuritySettings struct {
+	// Capabilities is the allowlist of Linux capabilities of the container, e.g. "NET_BIND_SERVICE"
+	// All other capabilities are dropped
+	Capabilities []string `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+
+	// Seccomp is the seccomp profile of the container. This can be one of:
+	// - "mcp-default": The built-in profile for MCP servers
+	// - "runtime-default": The default profile of the container runtime
+	// - "unconfined": No seccomp filtering
+	// - "localhost/<path>": A profile installed on the nodes (Kubernetes only)
+	// - The path to a JSON seccomp profile (Docker and Podman only)
+	// If empty, the default profile of the container runtime is used
+	Seccomp string `json:"seccomp,omitempty" yaml:"seccomp,omitempty"`
+
+	// AppArmor is the AppArmor profile of the container. This can be "runtime-default",
+	// "unconfined", or the name of a profile loaded on the host
+	AppArmor string `json:"apparmor,omitempty" yaml:"apparmor,omitempty"`
+
+	// ReadOnlyRootFilesystem mounts the root filesystem of the container read-only
+	ReadOnlyRootFilesystem bool `json:"read_only_root_filesystem,omitempty" yaml:"read_only_root_filesystem,omitempty"`
+
+	// Tmpfs is a list of paths in the container where writable tmpfs filesystems are mounted,
+	// e.g. "/tmp" when the root filesystem is read-only
+	Tmpfs []string `json:"tmpfs,omitempty" yaml:"tmpfs,omitempty"`
+
+	// NoNewPrivileges prevents the processes of the container from gaining privileges,
+	// e.g. through setuid binaries
+	NoNewPrivileges bool `json:"no_new_privileges,omitempty" yaml:"no_new_privileges,omitempty"`
+
+	// User is the numeric user and optional group to run the container as, in the format uid[:gid]
+	// If empty, the user of the image is used
+	User string `json:"user,omitempty" yaml:"user,omitempty"`
+}
+
+// Validate checks that the security settings are well-formed
+func (s *SecuritySettings) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	for _, capability := range s.Capabilities {
+		name := NormalizeCapability(capability)
+		if !capabilityRegex.MatchString(name) || name == "ALL" {
+			return fmt.Errorf("invalid capability %q", capability)
+		}
+	}
+
+	switch {
+	case s.Seccomp == "", s.Seccomp == SeccompProfileMCPDefault,
+		s.Seccomp == SeccompProfileRuntimeDefault, s.Seccomp == SeccompProfileUnconfined:
+	case strings.HasPrefix(s.Seccomp, SeccompProfileLocalhostPrefix):
+		if strings.TrimPrefix(s.Seccomp, SeccompProfileLocalhostPrefix) == "" {
+			return fmt.Errorf("invalid seccomp profile %q: missing the path of the profile", s.Seccomp)
+		}
+	default:
+		if _, err := s.SeccompProfileJSON(); err != nil {
+			return err
+		}
+	}
+
+	if s.AppArmor != "" && !appArmorProfileRegex.MatchString(s.AppArmor) {
+		return fmt.Errorf("invalid AppArmor profile %q", s.AppArmor)
+	}
+
+	for _, tmpfs := range s.Tmpfs {
+		if !path.IsAbs(tmpfs) || path.Clean(tmpfs) != tmpfs || tmpfs == "/" {
+			return fmt.Errorf("invalid tmpfs path %q: must be a clean absolute path", tmpfs)
+		}
+	}
+
+	if _, _, err := s.RunAsUser(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NormalizedCapabilities returns the capabilities without the CAP_ prefix, in upper case
+func (s *SecuritySettings) NormalizedCapabilities() []string {
+	if s == nil || len(s.Capabilities) == 0 {
+		return nil
+	}
+	capabilities := make([]string, 0, len(s.Capabilities))
+	for _, capability := range s.Capabilities {
+		capabilities = append(capabilities, NormalizeCapability(capability))
+	}
+	return capabilities
+}
+
+// NormalizeCapability returns the name of a capability without the CAP_ prefix, in upper case
+func NormalizeCapability(capability string) string {
+	return strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(capability)), "CAP_")
+}
+
+// SeccompProfileJSON returns the contents of the seccomp profile if it is the built-in MCP
+// profile or a JSON file, or an empty string otherwise
+func (s *SecuritySettings) SeccompProfileJSON() (string, error) {
+	if s == nil {
+		return "", nil
+	}
+
+	var data []byte
+	switch {
+	case s.Seccomp == "", s.Seccomp == SeccompProfileRuntimeDefault, s.Seccomp == SeccompProfileUnconfined,
+		strings.HasPrefix(s.Seccomp, SeccompProfileLocalhostPrefix):
+		return "", nil
+	case s.Seccomp == SeccompProfileMCPDefault:
+		data = mcpDefaultSeccompProfile
+	default:
+		var err error
+		// #nosec G304 - This is intentional as we're reading a user-specified seccomp profile
+		data, err = os.ReadFile(s.Seccomp)
+		if err != nil {
+			return "", fmt.Errorf("failed to read seccomp profile: %w", err)
+		}
+	}
+
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, data); err != nil {
+		return "", fmt.Errorf("invalid seccomp profile %s: %w", s.Seccomp, err)
+	}
+	return compacted.String(), nil
+}
+
+// RunAsUser returns the user and group IDs to run the container as, or nil if they are not set
+func (s *SecuritySettings) RunAsUser() (uid *int64, gid *int64, err error) {
+	if s == nil || s.User == "" {
+		return nil, nil, nil
+	}
+
+	userValue, groupValue, hasGroup := strings.Cut(s.User, ":")
+	parsedUID, err := strconv.ParseInt(userValue, 10, 64)
+	if err != nil || parsedUID < 0 {
+		return nil, nil, fmt.Errorf("invalid user %q: expected format uid[:gid] with numeric IDs", s.User)
+	}
+	uid = &parsedUID
+	if hasGroup {
+		parsedGID, err := strconv.ParseInt(groupValue, 10, 64)
+		if err != nil || parsedGID < 0 {
+			return nil, nil, fmt.Errorf("invalid user %q: expected format uid[:gid] with numeric IDs", s.User)
+		}
+		gid = &parsedGID
+	}
+	return uid, gid, nil
+}
@@ -0,0 +1,141 @@
+package permissions
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecuritySettings_Validate(t *testing.T) {
+	t.Parallel()
+
+	customProfile := filepath.Join(t.TempDir(), "seccomp.json")
+	require.NoError(t, os.WriteFile(customProfile, []byte(`{"defaultAction": "SCMP_ACT_ALLOW"}`), 0600))
+	invalidProfile := filepath.Join(t.TempDir(), "invalid.json")
+	require.NoError(t, os.WriteFile(invalidProfile, []byte(`not json`), 0600))
+
+	tests := []struct {
+		name        string
+		settings    *SecuritySettings
+		expectError bool
+	}{
+		{name: "Nil settings", settings: nil},
+		{
+			name: "Valid settings",
+			settings: &SecuritySettings{
+				Capabilities:           []string{"NET_BIND_SERVICE", "cap_chown"},
+				Seccomp:                SeccompProfileMCPDefault,
+				AppArmor:               "toolhive-mcp",
+				ReadOnlyRootFilesystem: true,
+				Tmpfs:                  []string{"/tmp", "/home/app/.cache"},
+				NoNewPrivileges:        true,
+				User:                   "1000:1000",
+			},
+		},
+		{name: "Custom seccomp profile", settings: &SecuritySettings{Seccomp: customProfile}},
+		{name: "Node-local seccomp profile", settings: &SecuritySettings{Seccomp: "localhost/profiles/mcp.json"}},
+		{name: "Invalid seccomp profile", settings: &SecuritySettings{Seccomp: invalidProfile}, expectError: true},
+		{name: "Missing seccomp profile", settings: &SecuritySettings{Seccomp: "/missing.json"}, expectError: true},
+		{name: "Empty node-local seccomp profile", settings: &SecuritySettings{Seccomp: "localhost/"}, expectError: true},
+		{name: "All capabilities", settings: &SecuritySettings{Capabilities: []string{"ALL"}}, expectError: true},
+		{name: "Invalid capability", settings: &SecuritySettings{Capabilities: []string{"NET BIND"}}, expectError: true},
+		{name: "Invalid AppArmor profile", settings: &SecuritySettings{AppArmor: "-profile"}, expectError: true},
+		{name: "Relative tmpfs path", settings: &SecuritySettings{Tmpfs: []string{"tmp"}}, expectError: true},
+		{name: "Root tmpfs path", settings: &SecuritySettings{Tmpfs: []string{"/"}}, expectError: true},
+		{name: "User name", settings: &SecuritySettings{User: "app"}, expectError: true},
+		{name: "Invalid group", settings: &SecuritySettings{User: "1000:app"}, expectError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.settings.Validate()
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSecuritySettings_NormalizedCapabilities(t *testing.T) {
+	t.Parallel()
+
+	settings := &SecuritySettings{Capabilities: []string{"NET_BIND_SERVICE", "cap_chown", " CAP_SETUID"}}
+	assert.Equal(t, []string{"NET_BIND_SERVICE", "CHOWN", "SETUID"}, settings.NormalizedCapabilities())
+
+	var empty *SecuritySettings
+	assert.Nil(t, empty.NormalizedCapabilities())
+}
+
+func TestSecuritySettings_SeccompProfileJSON(t *testing.T) {
+	t.Parallel()
+
+	// The built-in profile is valid JSON which only allows the system calls it lists
+	profile, err := (&SecuritySettings{Seccomp: SeccompProfileMCPDefault}).SeccompProfileJSON()
+	require.NoError(t, err)
+	var decoded struct {
+		DefaultAction string `json:"defaultAction"`
+		Syscalls      []struct {
+			Names  []string `json:"names"`
+			Action string   `json:"action"`
+		} `json:"syscalls"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(profile), &decoded))
+	assert.Equal(t, "SCMP_ACT_ERRNO", decoded.DefaultAction)
+	var allowed []string
+	for _, syscall := range decoded.Syscalls {
+		if syscall.Action == "SCMP_ACT_ALLOW" {
+			allowed = append(allowed, syscall.Names...)
+		}
+	}
+	assert.Contains(t, allowed, "read")
+	assert.Contains(t, allowed, "clone")
+	for _, name := range []string{"mount", "ptrace", "unshare", "setns", "bpf", "keyctl", "clone3"} {
+		assert.NotContains(t, allowed, name)
+	}
+
+	// Custom profiles are read from files and compacted
+	customProfile := filepath.Join(t.TempDir(), "seccomp.json")
+	require.NoError(t, os.WriteFile(customProfile, []byte("{\n  \"defaultAction\": \"SCMP_ACT_ERRNO\"\n}\n"), 0600))
+	profile, err = (&SecuritySettings{Seccomp: customProfile}).SeccompProfileJSON()
+	require.NoError(t, err)
+	assert.Equal(t, `{"defaultAction":"SCMP_ACT_ERRNO"}`, profile)
+
+	// Profiles applied by the runtime have no contents
+	for _, seccomp := range []string{"", SeccompProfileRuntimeDefault, SeccompProfileUnconfined, "localhost/mcp.json"} {
+		profile, err = (&SecuritySettings{Seccomp: seccomp}).SeccompProfileJSON()
+		require.NoError(t, err)
+		assert.Empty(t, profile)
+	}
+}
+
+func TestSecuritySettings_RunAsUser(t *testing.T) {
+	t.Parallel()
+
+	uid, gid, err := (&SecuritySettings{User: "1000:2000"}).RunAsUser()
+	require.NoError(t, err)
+	require.NotNil(t, uid)
+	require.NotNil(t, gid)
+	assert.Equal(t, int64(1000), *uid)
+	assert.Equal(t, int64(2000), *gid)
+
+	uid, gid, err = (&SecuritySettings{User: "0"}).RunAsUser()
+	require.NoError(t, err)
+	require.NotNil(t, uid)
+	assert.Equal(t, int64(0), *uid)
+	assert.Nil(t, gid)
+
+	uid, gid, err = (&SecuritySettings{}).RunAsUser()
+	require.NoError(t, err)
+	assert.Nil(t, uid)
+	assert.Nil(t, gid)
+
+	_, _, err = (&SecuritySettings{User: "-1"}).RunAsUser()
+	assert.Error(t, err)
+}
@@ -77,6 +77,9 @@ type ImageMetadata struct {
 	TargetPort int `json:"target_port,omitempty" yaml:"target_port,omitempty"`
 	// Permissions defines the security profile and access permissions for the server
 	Permissions *permissions.Profile `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	// Resources defines the default CPU, memory and process limits of the server.
+	// Limits set in the permission profile or on the command line take precedence.
+	Resources *permissions.ResourceLimits `json:"resources,omitempty" yaml:"resources,omitempty"`
 	// EnvVars defines environment variables that can be passed to the server
 	EnvVars []*EnvVar `json:"env_vars,omitempty" yaml:"env_vars,omitempty"`
 	// Args are the default command-line arguments to pass to the MCP server container.
@@ -27,6 +27,8 @@ type ToolhivePublisherExtension struct {
 	CustomMetadata map[string]any `json:"custom_metadata,omitempty" yaml:"custom_metadata,omitempty"`
 	// Permissions defines the security profile and access permissions for the server
 	Permissions *permissions.Profile `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+	// Resources defines the default resource limits of the server
+	Resources *permissions.ResourceLimits `json:"resources,omitempty" yaml:"resources,omitempty"`
 	// TargetPort is the port for the container to expose (only applicable to SSE and Streamable HTTP transports)
 	TargetPort int `json:"target_port,omitempty" yaml:"target_port,omitempty"`
 	// DockerTags lists the available Docker tags for this server image
@@ -120,6 +122,7 @@ func convertToImageMetadata(upstream *UpstreamServerDetail, toolhiveExt *Toolhiv
 		Image:       formatImageName(primaryPackage),
 		TargetPort:  getIntOrDefault(toolhiveExt, func(t *ToolhivePublisherExtension) int { return t.TargetPort }, 0),
 		Permissions: getPermissionsOrDefault(toolhiveExt),
+		Resources:   getResourcesOrDefault(toolhiveExt),
 		EnvVars:     convertEnvironmentVariables(primaryPackage.EnvironmentVariables),
 		Args:        convertPackageArguments(primaryPackage.PackageArguments),
 		DockerTags:  getSliceOrDefault(toolhiveExt, func(t *ToolhivePublisherExtension) []string { return t.DockerTags }, []string{}),
@@ -179,6 +182,7 @@ func ConvertToolhiveToUpstream(server ServerMetadata) (*UpstreamServerDetail, er
 		if imageServer, ok := server.(*ImageMetadata); ok {
 			toolhiveExt.TargetPort = imageServer.TargetPort
 			toolhiveExt.Permissions = imageServer.Permissions
+			toolhiveExt.Resources = imageServer.Resources
 			toolhiveExt.DockerTags = imageServer.DockerTags
 			toolhiveExt.Provenance = imageServer.Provenance
 
@@ -464,6 +468,13 @@ func getPermissionsOrDefault(ext *ToolhivePublisherExtension) *permissions.Profi
 	return nil
 }
 
+func getResourcesOrDefault(ext *ToolhivePublisherExtension) *permissions.ResourceLimits {
+	if ext != nil {
+		return ext.Resources
+	}
+	return nil
+}
+
 func getProvenanceOrDefault(ext *ToolhivePublisherExtension) *Provenance {
 	if ext != nil && ext.Provenance != nil {
 		return ext.Provenance
@@ -24,6 +24,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/state"
 	"github.com/stacklok/toolhive/pkg/telemetry"
 	"github.com/stacklok/toolhive/pkg/transport/types"
+	"github.com/stacklok/toolhive/pkg/usage"
 	workloadtypes "github.com/stacklok/toolhive/pkg/workloads/types"
 )
 
@@ -115,10 +116,17 @@ type RunConfig struct {
 	// TelemetryConfig contains the OpenTelemetry configuration
 	TelemetryConfig *telemetry.Config `json:"telemetry_config,omitempty" yaml:"telemetry_config,omitempty"`
 
+	// UsageConfig contains the usage accounting and quota configuration
+	UsageConfig *usage.Config `json:"usage_config,omitempty" yaml:"usage_config,omitempty"`
+
 	// Secrets are the secret parameters to pass to the container
 	// Format: "<secret name>,target=<target environment variable>"
 	Secrets []string `json:"secrets,omitempty" yaml:"secrets,omitempty"`
 
+	// SecretVersions are the versions of the secrets the workload was last started with,
+	// keyed by secret name. Only secrets of providers which version their secrets are recorded.
+	SecretVersions map[string]string `json:"secret_versions,omitempty" yaml:"secret_versions,omitempty"`
+
 	// K8sPodTemplatePatch is a JSON string to patch the Kubernetes pod template
 	// Only applicable when using Kubernetes runtime
 	K8sPodTemplatePatch string `json:"k8s_pod_template_patch,omitempty" yaml:"k8s_pod_template_patch,omitempty"`
@@ -126,12 +134,31 @@ type RunConfig struct {
 	// Deployer is the container runtime to use (not serialized)
 	Deployer rt.Deployer `json:"-" yaml:"-"`
 
+	// SecretFiles are the resolved secrets with file targets (not serialized)
+	SecretFiles []rt.SecretFile `json:"-" yaml:"-"`
+
 	// buildContext indicates whether this config is being built for CLI or operator use (not serialized)
 	buildContext BuildContext
 
 	// IsolateNetwork indicates whether to isolate the network for the container
 	IsolateNetwork bool `json:"isolate_network,omitempty" yaml:"isolate_network,omitempty"`
 
+	// Services are auxiliary containers, such as a database, which start before the MCP server,
+	// share its network and network isolation, and are removed with it
+	Services []rt.ServiceContainer `json:"services,omitempty" yaml:"services,omitempty"`
+
+	// LearnPermissions indicates whether to record the network destinations and mounted paths
+	// accessed by the container, and suggest a permission profile which allows them
+	LearnPermissions bool `json:"learn_permissions,omitempty" yaml:"learn_permissions,omitempty"`
+
+	// LearnPermissionsOutput is the path the suggested permission profile is written to
+	// when LearnPermissions is set
+	LearnPermissionsOutput string `json:"learn_permissions_output,omitempty" yaml:"learn_permissions_output,omitempty"`
+
+	// NetworkBeforeLearning is the network configuration which permission learning replaced,
+	// and which is restored in the saved configuration once learning finishes
+	NetworkBeforeLearning *NetworkBeforeLearning `json:"network_before_learning,omitempty" yaml:"network_before_learning,omitempty"`
+
 	// TrustProxyHeaders indicates whether to trust X-Forwarded-* headers from reverse proxies
 	TrustProxyHeaders bool `json:"trust_proxy_headers,omitempty" yaml:"trust_proxy_headers,omitempty"`
 
@@ -314,7 +341,12 @@ func (c *RunConfig) ValidateSecrets(ctx context.Context, secretManager secrets.P
 		return fmt.Errorf("failed to get secrets: %w", err)
 	}
 
-	return nil
+	secretFiles, err := environment.ParseSecretFiles(ctx, c.Secrets, secretManager)
+	if err != nil {
+		return fmt.Errorf("failed to get secrets: %w", err)
+	}
+
+	return c.validateSecretFiles(secretFiles)
 }
 
 // WithSecrets processes secrets and adds them to environment variables
@@ -338,9 +370,34 @@ func (c *RunConfig) WithSecrets(ctx context.Context, secretManager secrets.Provi
 		c.EnvVars[key] = value
 	}
 
+	secretFiles, err := environment.ParseSecretFiles(ctx, c.Secrets, secretManager)
+	if err != nil {
+		return c, fmt.Errorf("failed to get secrets: %v", err)
+	}
+	if err := c.validateSecretFiles(secretFiles); err != nil {
+		return c, err
+	}
+	c.SecretFiles = secretFiles
+
 	return c, nil
 }
 
+// validateSecretFiles checks that the secret files do not conflict with the mounts of the permission profile
+func (c *RunConfig) validateSecretFiles(secretFiles []rt.SecretFile) error {
+	if len(secretFiles) == 0 || c.PermissionProfile == nil {
+		return nil
+	}
+
+	paths := make([]string, 0, len(secretFiles))
+	for _, secretFile := range secretFiles {
+		paths = append(paths, secretFile.Path)
+	}
+	if err := c.PermissionProfile.ValidateSecretFiles(paths); err != nil {
+		return fmt.Errorf("invalid secret files: %w", err)
+	}
+	return nil
+}
+
 // mergeEnvVars is a helper method to merge environment variables into RunConfig
 func (c *RunConfig) mergeEnvVars(envVars map[string]string) *RunConfig {
 	// Initialize EnvVars if it's nil
@@ -436,6 +493,34 @@ func LoadState(ctx context.Context, name string) (*RunConfig, error) {
 	return state.LoadRunConfig(ctx, name, ReadJSON)
 }
 
+// ReferencedImages returns the images of all saved run configurations and of their services. An error
+// is returned if any configuration cannot be loaded, so that images which may still be needed are never pruned.
+func ReferencedImages(ctx context.Context) ([]string, error) {
+	store, err := state.NewRunConfigStore(state.DefaultAppName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state store: %w", err)
+	}
+	names, err := store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list run configurations: %w", err)
+	}
+
+	var images []string
+	for _, name := range names {
+		config, err := LoadState(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load run configuration %s: %w", name, err)
+		}
+		if config.Image != "" {
+			images = append(images, config.Image)
+		}
+		for _, service := range config.Services {
+			images = append(images, service.Image)
+		}
+	}
+	return images, nil
+}
+
 // RemoteAuthConfig holds configuration for remote authentication
 type RemoteAuthConfig struct {
 	ClientID         string        `json:"client_id,omitempty" yaml:"client_id,omitempty"`
@@ -21,6 +21,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/telemetry"
 	"github.com/stacklok/toolhive/pkg/transport"
 	"github.com/stacklok/toolhive/pkg/transport/types"
+	"github.com/stacklok/toolhive/pkg/usage"
 )
 
 // BuildContext defines the context in which the RunConfigBuilder is being used
@@ -43,6 +44,8 @@ type runConfigBuilder struct {
 	targetPort int
 	// Build context determines which validation and features are enabled
 	buildContext BuildContext
+	// Store resource limits separately, as they are applied to the permission profile once it is loaded
+	resourceLimits *permissions.ResourceLimits
 }
 
 // RunConfigBuilderOption is a function that modifies the RunConfigBuilder
@@ -211,6 +214,18 @@ func WithPermissionProfile(profile *permissions.Profile) RunConfigBuilderOption
 	}
 }
 
+// WithResourceLimits sets the resource limits of the container. Limits which are set take
+// precedence over those of the permission profile and the registry.
+func WithResourceLimits(limits *permissions.ResourceLimits) RunConfigBuilderOption {
+	return func(b *runConfigBuilder) error {
+		if err := limits.Validate(); err != nil {
+			return fmt.Errorf("invalid resource limits: %w", err)
+		}
+		b.resourceLimits = limits
+		return nil
+	}
+}
+
 // WithNetworkIsolation sets network isolation
 func WithNetworkIsolation(isolate bool) RunConfigBuilderOption {
 	return func(b *runConfigBuilder) error {
@@ -219,6 +234,17 @@ func WithNetworkIsolation(isolate bool) RunConfigBuilderOption {
 	}
 }
 
+// WithPermissionLearning enables the permission learning mode, in which outbound connections are
+// allowed and recorded and a suggested permission profile is written to output when the workload stops.
+// If output is empty, the profile is written to the ToolHive state directory.
+func WithPermissionLearning(learn bool, output string) RunConfigBuilderOption {
+	return func(b *runConfigBuilder) error {
+		b.config.LearnPermissions = learn
+		b.config.LearnPermissionsOutput = output
+		return nil
+	}
+}
+
 // WithTrustProxyHeaders sets whether to trust X-Forwarded-* headers from reverse proxies
 func WithTrustProxyHeaders(trust bool) RunConfigBuilderOption {
 	return func(b *runConfigBuilder) error {
@@ -297,6 +323,14 @@ func WithAuditEnabled(enableAudit bool, auditConfigPath string) RunConfigBuilder
 	}
 }
 
+// WithUsageConfig configures usage accounting and quotas
+func WithUsageConfig(usageConfig *usage.Config) RunConfigBuilderOption {
+	return func(b *runConfigBuilder) error {
+		b.config.UsageConfig = usageConfig
+		return nil
+	}
+}
+
 // WithOIDCConfig configures OIDC settings
 func WithOIDCConfig(
 	oidcIssuer string,
@@ -353,6 +387,8 @@ func WithTelemetryConfig(
 	otelHeaders []string,
 	otelInsecure bool,
 	otelEnvironmentVariables []string,
+	otelLogsEnabled bool,
+	otelExemplarsEnabled bool,
 ) RunConfigBuilderOption {
 	return func(b *runConfigBuilder) error {
 		if otelEndpoint == "" && !otelEnablePrometheusMetricsPath {
@@ -393,6 +429,8 @@ func WithTelemetryConfig(
 			ServiceVersion:              telemetry.DefaultConfig().ServiceVersion,
 			TracingEnabled:              otelTracingEnabled,
 			MetricsEnabled:              otelMetricsEnabled,
+			LogsEnabled:                 otelLogsEnabled,
+			ExemplarsEnabled:            otelExemplarsEnabled,
 			SamplingRate:                otelSamplingRate,
 			Headers:                     headers,
 			Insecure:                    otelInsecure,
@@ -428,6 +466,32 @@ func WithIgnoreConfig(ignoreConfig *ignore.Config) RunConfigBuilderOption {
 	}
 }
 
+// WithServices sets the auxiliary service containers of the workload
+func WithServices(services []rt.ServiceContainer) RunConfigBuilderOption {
+	return func(b *runConfigBuilder) error {
+		if err := rt.ValidateServices(services); err != nil {
+			return err
+		}
+		b.config.Services = services
+		return nil
+	}
+}
+
+// WithServicesFile loads the auxiliary service containers of the workload from a YAML or JSON file
+func WithServicesFile(path string) RunConfigBuilderOption {
+	return func(b *runConfigBuilder) error {
+		if path == "" {
+			return nil
+		}
+		services, err := rt.LoadServices(path)
+		if err != nil {
+			return err
+		}
+		b.config.Services = services
+		return nil
+	}
+}
+
 // WithMiddlewareFromFlags creates middleware configurations directly from flag values
 func WithMiddlewareFromFlags(
 	oidcConfig *auth.TokenValidatorConfig,
@@ -438,6 +502,7 @@ func WithMiddlewareFromFlags(
 	authzConfigPath string,
 	enableAudit bool,
 	auditConfigPath string,
+	usageConfig *usage.Config,
 	serverName string,
 	transportType string,
 ) RunConfigBuilderOption {
@@ -464,6 +529,7 @@ func WithMiddlewareFromFlags(
 		middlewareConfigs = addTelemetryMiddleware(middlewareConfigs, telemetryConfig, serverName, transportType)
 		middlewareConfigs = addAuthzMiddleware(middlewareConfigs, authzConfigPath)
 		middlewareConfigs = addAuditMiddleware(middlewareConfigs, enableAudit, auditConfigPath, serverName, transportType)
+		middlewareConfigs = addUsageMiddleware(middlewareConfigs, usageConfig, serverName)
 
 		// Set the populated middleware configs
 		b.config.MiddlewareConfigs = middlewareConfigs
@@ -633,6 +699,27 @@ func addAuditMiddleware(
 	return middlewareConfigs
 }
 
+// addUsageMiddleware adds usage accounting middleware if configured
+func addUsageMiddleware(
+	middlewareConfigs []types.MiddlewareConfig,
+	usageConfig *usage.Config,
+	serverName string,
+) []types.MiddlewareConfig {
+	if usageConfig == nil {
+		return middlewareConfigs
+	}
+
+	usageParams := usage.MiddlewareParams{
+		Config:     usageConfig,
+		ServerName: serverName,
+	}
+	if usageMwConfig, err := types.NewMiddlewareConfig(usage.MiddlewareType, usageParams); err == nil {
+		middlewareConfigs = append(middlewareConfigs, *usageMwConfig)
+	}
+
+	return middlewareConfigs
+}
+
 // NewOperatorRunConfigBuilder creates a new RunConfigBuilder configured for operator use
 func NewOperatorRunConfigBuilder(
 	ctx context.Context,
@@ -772,6 +859,26 @@ func (b *runConfigBuilder) validateConfig(imageMetadata *registry.ImageMetadata)
 		return err
 	}
 
+	// Apply the resource limits, which are taken from the flags, the permission profile
+	// and the registry in order of precedence
+	var registryLimits *permissions.ResourceLimits
+	if imageMetadata != nil {
+		registryLimits = imageMetadata.Resources
+	}
+	c.PermissionProfile.Resources = permissions.MergeResourceLimits(
+		b.resourceLimits, c.PermissionProfile.Resources, registryLimits)
+	if err := c.PermissionProfile.Resources.Validate(); err != nil {
+		return fmt.Errorf("invalid resource limits: %w", err)
+	}
+	if err := c.PermissionProfile.Security.Validate(); err != nil {
+		return fmt.Errorf("invalid security settings: %w", err)
+	}
+	if c.PermissionProfile.Network != nil {
+		if err := c.PermissionProfile.Network.Outbound.Validate(); err != nil {
+			return fmt.Errorf("invalid outbound network permissions: %w", err)
+		}
+	}
+
 	// Process volume mounts
 	if err = b.processVolumeMounts(); err != nil {
 		return err
@@ -786,6 +893,12 @@ func (b *runConfigBuilder) validateConfig(imageMetadata *registry.ImageMetadata)
 	// Add standard labels
 	c.WithStandardLabels()
 
+	if c.LearnPermissions {
+		if err := c.enablePermissionLearning(); err != nil {
+			return err
+		}
+	}
+
 	// Add authorization configuration if provided
 	if c.AuthzConfigPath != "" {
 		authzConfig, err := authz.LoadConfig(c.AuthzConfigPath)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -230,6 +231,98 @@ func TestRunConfigBuilder_Build_WithPermissionProfile(t *testing.T) {
 	}
 }
 
+func TestRunConfigBuilder_Build_WithResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	logger.Initialize()
+
+	imageMetadata := &registry.ImageMetadata{
+		BaseServerMetadata: registry.BaseServerMetadata{
+			Name: "test-image",
+		},
+		Resources: &permissions.ResourceLimits{CPUs: "1", Memory: "256m", PidsLimit: 50},
+	}
+	profile := permissions.BuiltinNoneProfile()
+	profile.Resources = &permissions.ResourceLimits{Memory: "512m"}
+
+	config, err := NewRunConfigBuilder(
+		context.Background(),
+		imageMetadata,
+		nil,
+		&mockEnvVarValidator{},
+		WithPermissionProfile(profile),
+		WithResourceLimits(&permissions.ResourceLimits{CPUs: "2"}),
+	)
+	require.NoError(t, err)
+
+	// Flags take precedence over the profile, which takes precedence over the registry
+	assert.Equal(t, &permissions.ResourceLimits{CPUs: "2", Memory: "512m", PidsLimit: 50},
+		config.PermissionProfile.Resources)
+
+	_, err = NewRunConfigBuilder(
+		context.Background(),
+		imageMetadata,
+		nil,
+		&mockEnvVarValidator{},
+		WithResourceLimits(&permissions.ResourceLimits{Memory: "lots"}),
+	)
+	assert.Error(t, err)
+}
+
+func TestRunConfigBuilder_Build_WithPermissionLearning(t *testing.T) {
+	t.Parallel()
+
+	logger.Initialize()
+
+	profile := permissions.BuiltinNoneProfile()
+	profile.Read = []permissions.MountDeclaration{"/data:/data"}
+	profile.Network.Outbound.AllowHost = []string{"example.com"}
+	profile.Network.Inbound = &permissions.InboundNetworkPermissions{AllowHost: []string{"localhost"}}
+	output := filepath.Join(t.TempDir(), "profile.json")
+
+	config, err := NewRunConfigBuilder(
+		context.Background(),
+		nil,
+		nil,
+		&mockEnvVarValidator{},
+		WithName("learning"),
+		WithPermissionProfile(profile),
+		WithPermissionLearning(true, output),
+	)
+	require.NoError(t, err)
+
+	// All outbound connections go through the egress proxy, which allows them
+	assert.True(t, config.IsolateNetwork)
+	assert.True(t, config.PermissionProfile.Network.Outbound.InsecureAllowAll)
+	assert.Empty(t, config.PermissionProfile.Network.Outbound.AllowHost)
+	assert.Equal(t, profile.Network.Inbound, config.PermissionProfile.Network.Inbound)
+	assert.Equal(t, profile.Read, config.PermissionProfile.Read)
+	assert.Equal(t, output, config.LearnPermissionsOutput)
+
+	// The declared profile is left unchanged
+	assert.False(t, profile.Network.Outbound.InsecureAllowAll)
+	assert.Equal(t, []string{"example.com"}, profile.Network.Outbound.AllowHost)
+
+	// Once learning finishes, the network configuration from before learning is restored
+	config.restoreNetworkBeforeLearning()
+	assert.False(t, config.LearnPermissions)
+	assert.False(t, config.IsolateNetwork)
+	assert.Nil(t, config.NetworkBeforeLearning)
+	assert.Equal(t, profile.Network, config.PermissionProfile.Network)
+	assert.Equal(t, profile.Read, config.PermissionProfile.Read)
+
+	_, err = NewRunConfigBuilder(
+		context.Background(),
+		nil,
+		nil,
+		&mockEnvVarValidator{},
+		WithName("learning"),
+		WithRemoteURL("https://example.com/mcp"),
+		WithPermissionLearning(true, output),
+	)
+	assert.Error(t, err)
+}
+
 func TestRunConfigBuilder_Build_WithVolumeMounts(t *testing.T) {
 	t.Parallel()
 
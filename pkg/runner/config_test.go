@@ -8,11 +8,13 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/adrg/xdg"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	"github.com/stacklok/toolhive/pkg/authz"
+	rt "github.com/stacklok/toolhive/pkg/container/runtime"
 	runtimemocks "github.com/stacklok/toolhive/pkg/container/runtime/mocks"
 	"github.com/stacklok/toolhive/pkg/ignore"
 	"github.com/stacklok/toolhive/pkg/logger"
@@ -577,7 +579,7 @@ func TestRunConfigBuilder(t *testing.T) {
 		WithLabels(nil),
 		WithGroup(""),
 		WithOIDCConfig(oidcIssuer, oidcAudience, oidcJwksURL, "", oidcClientID, "", "", "", "", false),
-		WithTelemetryConfig("", false, false, false, "", 0.1, nil, false, nil),
+		WithTelemetryConfig("", false, false, false, "", 0.1, nil, false, nil, false, false),
 		WithToolsFilter(nil),
 		WithIgnoreConfig(&ignore.Config{
 			LoadGlobal:    false,
@@ -825,7 +827,7 @@ func TestRunConfigBuilder_MetadataOverrides(t *testing.T) {
 				WithLabels(nil),
 				WithGroup(""),
 				WithOIDCConfig("", "", "", "", "", "", "", "", "", false),
-				WithTelemetryConfig("", false, false, false, "", 0, nil, false, nil),
+				WithTelemetryConfig("", false, false, false, "", 0, nil, false, nil, false, false),
 				WithToolsFilter(nil),
 				WithIgnoreConfig(&ignore.Config{
 					LoadGlobal:    false,
@@ -872,7 +874,7 @@ func TestRunConfigBuilder_EnvironmentVariableTransportDependency(t *testing.T) {
 		WithLabels(nil),
 		WithGroup(""),
 		WithOIDCConfig("", "", "", "", "", "", "", "", "", false),
-		WithTelemetryConfig("", false, false, false, "", 0, nil, false, nil),
+		WithTelemetryConfig("", false, false, false, "", 0, nil, false, nil, false, false),
 		WithToolsFilter(nil),
 		WithIgnoreConfig(&ignore.Config{
 			LoadGlobal:    false,
@@ -924,7 +926,7 @@ func TestRunConfigBuilder_CmdArgsMetadataOverride(t *testing.T) {
 		WithLabels(nil),
 		WithGroup(""),
 		WithOIDCConfig("", "", "", "", "", "", "", "", "", false),
-		WithTelemetryConfig("", false, false, false, "", 0, nil, false, nil),
+		WithTelemetryConfig("", false, false, false, "", 0, nil, false, nil, false, false),
 		WithToolsFilter(nil),
 		WithIgnoreConfig(&ignore.Config{
 			LoadGlobal:    false,
@@ -979,7 +981,7 @@ func TestRunConfigBuilder_CmdArgsMetadataDefaults(t *testing.T) {
 		WithLabels(nil),
 		WithGroup(""),
 		WithOIDCConfig("", "", "", "", "", "", "", "", "", false),
-		WithTelemetryConfig("", false, false, false, "", 0, nil, false, nil),
+		WithTelemetryConfig("", false, false, false, "", 0, nil, false, nil, false, false),
 		WithToolsFilter(nil),
 		WithIgnoreConfig(&ignore.Config{
 			LoadGlobal:    false,
@@ -1030,7 +1032,7 @@ func TestRunConfigBuilder_VolumeProcessing(t *testing.T) {
 		WithLabels(nil),
 		WithGroup(""),
 		WithOIDCConfig("", "", "", "", "", "", "", "", "", false),
-		WithTelemetryConfig("", false, false, false, "", 0, nil, false, nil),
+		WithTelemetryConfig("", false, false, false, "", 0, nil, false, nil, false, false),
 		WithToolsFilter(nil),
 		WithIgnoreConfig(&ignore.Config{
 			LoadGlobal:    false,
@@ -1103,7 +1105,7 @@ func TestRunConfigBuilder_FilesystemMCPScenario(t *testing.T) {
 		WithLabels(nil),
 		WithGroup(""),
 		WithOIDCConfig("", "", "", "", "", "", "", "", "", false),
-		WithTelemetryConfig("", false, false, false, "", 0, nil, false, nil),
+		WithTelemetryConfig("", false, false, false, "", 0, nil, false, nil, false, false),
 		WithToolsFilter(nil),
 		WithIgnoreConfig(&ignore.Config{
 			LoadGlobal:    false,
@@ -1390,3 +1392,22 @@ func TestConfigFileLoading(t *testing.T) {
 		assert.Nil(t, file, "File handle should be nil when file does not exist")
 	})
 }
+
+func TestReferencedImages(t *testing.T) { //nolint:paralleltest // the state store uses environment variables
+	t.Cleanup(xdg.Reload)
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	xdg.Reload()
+
+	ctx := context.Background()
+	for _, config := range []*RunConfig{
+		{BaseName: "fetch", Image: "ghcr.io/stacklok/fetch:1.0"},
+		{BaseName: "remote", RemoteURL: "https://mcp.example.com"},
+		{BaseName: "db", Image: "ghcr.io/example/db-mcp:1.0", Services: []rt.ServiceContainer{{Name: "db", Image: "postgres:16"}}},
+	} {
+		require.NoError(t, config.SaveState(ctx))
+	}
+
+	images, err := ReferencedImages(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"ghcr.io/stacklok/fetch:1.0", "ghcr.io/example/db-mcp:1.0", "postgres:16"}, images)
+}
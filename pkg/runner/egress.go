@@ -0,0 +1,98 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/audit"
+	rt "github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/networking"
+)
+
+// startEgressMonitoring follows the access log of the egress proxy of a workload with network
+// isolation, starting at since, and reports the outbound connections which were denied by its
+// network permissions in the proxy logs and as audit events. In permission learning mode, all
+// outbound connections are recorded. It is a no-op for workloads without network isolation and
+// for runtimes which cannot stream logs.
+func (r *Runner) startEgressMonitoring(ctx context.Context, since time.Time) {
+	if !r.Config.IsolateNetwork || r.Config.RemoteURL != "" {
+		return
+	}
+	streamer, ok := r.Config.Deployer.(rt.LogStreamer)
+	if !ok {
+		return
+	}
+
+	var auditLogger *slog.Logger
+	if r.Config.AuditConfig != nil && r.Config.AuditConfig.ShouldAuditEvent(audit.EventTypeNetworkEgressDenied) {
+		writer, err := r.Config.AuditConfig.GetLogWriter()
+		if err != nil {
+			logger.Warnf("Warning: Failed to open audit log for egress events: %v", err)
+		} else {
+			auditLogger = audit.NewAuditLogger(writer)
+		}
+	}
+
+	egressContainerName := r.Config.ContainerName + "-egress"
+	pr, pw := io.Pipe()
+	go func() {
+		err := streamer.StreamWorkloadLogs(ctx, egressContainerName, since, pw, pw)
+		if err != nil {
+			logger.Debugf("Egress log stream for %s ended: %v", r.Config.ContainerName, err)
+		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	go func() {
+		scanner := bufio.NewScanner(pr)
+		for scanner.Scan() {
+			connection, ok := networking.ParseEgressConnection(scanner.Text())
+			if !ok {
+				continue
+			}
+			if r.learner != nil {
+				r.learner.recorder.RecordConnection(connection.Host(), connection.Port())
+			}
+			if connection.Denied() {
+				r.reportEgressDenial(ctx, auditLogger, connection)
+			}
+		}
+	}()
+}
+
+// reportEgressDenial logs an outbound connection which was denied by the egress proxy
+func (r *Runner) reportEgressDenial(ctx context.Context, auditLogger *slog.Logger, denial networking.EgressConnection) {
+	logger.Warnf("Egress denied for %s: %s %s is not allowed by the network permissions of the permission profile",
+		r.Config.ContainerName, denial.Method, denial.Destination)
+
+	if auditLogger == nil {
+		return
+	}
+
+	component := r.Config.AuditConfig.Component
+	if component == "" {
+		component = r.Config.ContainerName
+	}
+	event := audit.NewAuditEvent(
+		audit.EventTypeNetworkEgressDenied,
+		audit.EventSource{Type: audit.SourceTypeNetwork, Value: denial.Client},
+		audit.OutcomeDenied,
+		map[string]string{},
+		component,
+	).WithTarget(map[string]string{
+		audit.TargetKeyType:     audit.TargetTypeNetwork,
+		audit.TargetKeyName:     denial.Host(),
+		audit.TargetKeyEndpoint: denial.Destination,
+		audit.TargetKeyMethod:   denial.Method,
+	})
+	event.LoggedAt = denial.Time
+	if data, err := json.Marshal(denial); err == nil {
+		event.WithDataFromString(string(data))
+	}
+	event.LogTo(ctx, auditLogger, audit.LevelAudit)
+}
@@ -0,0 +1,473 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/stacklok/toolhive/pkg/container/images"
+	"github.com/stacklok/toolhive/pkg/container/templates"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/runner/pin"
+)
+
+// commitHashPattern matches full and abbreviated commit hashes
+var commitHashPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// gitSource is a server published as a git repository, in the format host/repo[@ref][#subdir]
+type gitSource struct {
+	// URL is the clone URL of the repository
+	URL string
+	// Ref is the branch, tag or commit to build. The default branch is used when it is empty.
+	Ref string
+	// Subdir is the directory of the server within the repository
+	Subdir string
+}
+
+// gitProject is a server detected in a git repository
+type gitProject struct {
+	// TransportType selects the template which builds the project
+	TransportType templates.TransportType
+	// ContextDir is the build context, which contains the project manifest
+	ContextDir string
+	// Package is the package to build, relative to the build context
+	Package string
+	// BinName is the executable of the project, see templates.TemplateData
+	BinName string
+}
+
+// parseGitSource parses the package of a git:// scheme
+func parseGitSource(packageName string) (*gitSource, error) {
+	repo, subdir, _ := strings.Cut(packageName, "#")
+	repo, ref, _ := strings.Cut(repo, "@")
+	repo = strings.TrimSuffix(repo, "/")
+
+	host, repoPath, ok := strings.Cut(repo, "/")
+	if !ok || host == "" || repoPath == "" {
+		return nil, fmt.Errorf("invalid git source %q: expected git://host/repo[@ref][#subdir]", packageName)
+	}
+
+	if subdir != "" {
+		subdir = path.Clean(subdir)
+		if path.IsAbs(subdir) || subdir == ".." || strings.HasPrefix(subdir, "../") {
+			return nil, fmt.Errorf("invalid git source %q: the subdirectory must be within the repository", packageName)
+		}
+		if subdir == "." {
+			subdir = ""
+		}
+	}
+
+	return &gitSource{
+		URL:    "https://" + repo,
+		Ref:    ref,
+		Subdir: subdir,
+	}, nil
+}
+
+// cloneGitSource clones the ref of a git source into dir, without its history, and returns
+// the hash of the checked out commit
+func cloneGitSource(ctx context.Context, src *gitSource, dir string) (string, error) {
+	logger.Infof("Cloning %s", src.URL)
+
+	var repo *git.Repository
+	var err error
+	switch {
+	case src.Ref == "":
+		repo, err = git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{URL: src.URL, Depth: 1})
+	case commitHashPattern.MatchString(src.Ref):
+		// Commits cannot be fetched on their own, so the full repository is needed
+		repo, err = cloneCommit(ctx, src, dir)
+	default:
+		// The ref may be a tag or a branch
+		repo, err = git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
+			URL:           src.URL,
+			Depth:         1,
+			ReferenceName: plumbing.NewTagReferenceName(src.Ref),
+			SingleBranch:  true,
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			if err := resetDir(dir); err != nil {
+				return "", err
+			}
+			repo, err = git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
+				URL:           src.URL,
+				Depth:         1,
+				ReferenceName: plumbing.NewBranchReferenceName(src.Ref),
+				SingleBranch:  true,
+			})
+		}
+	}
+	if err != nil {
+		if src.Ref != "" {
+			return "", fmt.Errorf("failed to clone %s at %s: %w", src.URL, src.Ref, err)
+		}
+		return "", fmt.Errorf("failed to clone %s: %w", src.URL, err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD of %s: %w", src.URL, err)
+	}
+
+	// The history is not needed to build the server
+	if err := os.RemoveAll(filepath.Join(dir, ".git")); err != nil {
+		return "", err
+	}
+	return head.Hash().String(), nil
+}
+
+// cloneCommit clones a repository and checks out a commit
+func cloneCommit(ctx context.Context, src *gitSource, dir string) (*git.Repository, error) {
+	repo, err := git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{URL: src.URL})
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(src.Ref))
+	if err != nil {
+		return nil, fmt.Errorf("commit not found: %w", err)
+	}
+
+	workTree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %w", err)
+	}
+	if err := workTree.Checkout(&git.CheckoutOptions{Hash: *hash}); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// resetDir empties a directory after a failed clone
+func resetDir(dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", dir, err)
+	}
+	return os.MkdirAll(dir, 0750)
+}
+
+// binNamePattern matches the executable names which may be used in the generated Dockerfile
+var binNamePattern = regexp.MustCompile(`^[A-Za-z0-9._/-]+$`)
+
+// detectGitProject detects the language of the server in the subdirectory of a repository
+// from its manifest. Go modules may be declared in a parent directory of the server.
+func detectGitProject(repoDir, subdir string) (*gitProject, error) {
+	repoDir, err := filepath.EvalSymlinks(repoDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the repository directory: %w", err)
+	}
+	// The repository may contain symlinks, which must not lead the build context out of it
+	dir, err := filepath.EvalSymlinks(filepath.Join(repoDir, filepath.FromSlash(subdir)))
+	if err != nil {
+		return nil, fmt.Errorf("directory %q not found in the repository", subdir)
+	}
+	if rel, err := filepath.Rel(repoDir, dir); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("directory %q is outside of the repository", subdir)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("directory %q not found in the repository", subdir)
+	}
+
+	var project *gitProject
+	switch {
+	case fileExists(filepath.Join(dir, "go.mod")):
+		return &gitProject{TransportType: templates.TransportTypeGO, ContextDir: dir, Package: "."}, nil
+	case fileExists(filepath.Join(dir, "Cargo.toml")):
+		binName, err := cargoBinName(filepath.Join(dir, "Cargo.toml"))
+		if err != nil {
+			return nil, err
+		}
+		project = &gitProject{TransportType: templates.TransportTypeCargo, ContextDir: dir, Package: ".", BinName: binName}
+	case fileExists(filepath.Join(dir, "pyproject.toml")):
+		binName, err := pythonBinName(filepath.Join(dir, "pyproject.toml"))
+		if err != nil {
+			return nil, err
+		}
+		project = &gitProject{TransportType: templates.TransportTypeUVX, ContextDir: dir, Package: ".", BinName: binName}
+	case fileExists(filepath.Join(dir, "package.json")):
+		binName, err := nodeBinName(filepath.Join(dir, "package.json"))
+		if err != nil {
+			return nil, err
+		}
+		project = &gitProject{TransportType: templates.TransportTypeNPX, ContextDir: dir, Package: ".", BinName: binName}
+	}
+	if project != nil {
+		// The executable comes from the manifest of the repository and is written into the Dockerfile
+		if !binNamePattern.MatchString(project.BinName) || strings.Contains(project.BinName, "..") {
+			return nil, fmt.Errorf("invalid executable name %q in the manifest of the repository", project.BinName)
+		}
+		return project, nil
+	}
+
+	// Look for the Go module which contains the server
+	for moduleDir := filepath.Dir(dir); strings.HasPrefix(moduleDir, repoDir); moduleDir = filepath.Dir(moduleDir) {
+		if fileExists(filepath.Join(moduleDir, "go.mod")) {
+			rel, err := filepath.Rel(moduleDir, dir)
+			if err != nil {
+				return nil, err
+			}
+			return &gitProject{
+				TransportType: templates.TransportTypeGO,
+				ContextDir:    moduleDir,
+				Package:       "./" + filepath.ToSlash(rel),
+			}, nil
+		}
+		if moduleDir == repoDir {
+			break
+		}
+	}
+
+	return nil, fmt.Errorf("cannot detect the language of %q: no package.json, pyproject.toml, go.mod or Cargo.toml found",
+		path.Join(".", subdir))
+}
+
+// fileExists returns true if path is a regular file
+func fileExists(name string) bool {
+	info, err := os.Stat(name)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// cargoBinName returns the executable of a crate: its only [[bin]] target, or the crate name
+func cargoBinName(manifestPath string) (string, error) {
+	var manifest struct {
+		Package struct {
+			Name string `toml:"name"`
+		} `toml:"package"`
+		Bin []struct {
+			Name string `toml:"name"`
+		} `toml:"bin"`
+	}
+	if err := readTOML(manifestPath, &manifest); err != nil {
+		return "", err
+	}
+
+	if len(manifest.Bin) == 1 && manifest.Bin[0].Name != "" {
+		return manifest.Bin[0].Name, nil
+	}
+	if manifest.Package.Name == "" {
+		return "", fmt.Errorf("no package found in %s; cargo workspaces must be built from the directory of the server crate",
+			filepath.Base(manifestPath))
+	}
+	return manifest.Package.Name, nil
+}
+
+// pythonBinName returns the executable of a Python project: the script named after the project,
+// its only script, or the project name
+func pythonBinName(manifestPath string) (string, error) {
+	var manifest struct {
+		Project struct {
+			Name    string            `toml:"name"`
+			Scripts map[string]string `toml:"scripts"`
+		} `toml:"project"`
+	}
+	if err := readTOML(manifestPath, &manifest); err != nil {
+		return "", err
+	}
+
+	return pickBinName(manifest.Project.Name, manifest.Project.Scripts, manifest.Project.Name)
+}
+
+// nodeBinName returns the bin script of a Node.js package, relative to the package
+func nodeBinName(manifestPath string) (string, error) {
+	// #nosec G304 -- the manifest is in a repository cloned by ToolHive
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", filepath.Base(manifestPath), err)
+	}
+
+	var manifest struct {
+		Name string          `json:"name"`
+		Main string          `json:"main"`
+		Bin  json.RawMessage `json:"bin"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", filepath.Base(manifestPath), err)
+	}
+
+	script := manifest.Main
+	if len(manifest.Bin) > 0 {
+		var single string
+		var named map[string]string
+		switch {
+		case json.Unmarshal(manifest.Bin, &single) == nil:
+			script = single
+		case json.Unmarshal(manifest.Bin, &named) == nil:
+			// The command of a scoped package is named without its scope
+			_, unscoped, _ := strings.Cut(manifest.Name, "/")
+			if unscoped == "" {
+				unscoped = manifest.Name
+			}
+			script, err = pickBinName(unscoped, named, manifest.Main)
+			if err != nil {
+				return "", err
+			}
+		default:
+			return "", fmt.Errorf("invalid bin field in %s", filepath.Base(manifestPath))
+		}
+	}
+	if script == "" {
+		script = "index.js"
+	}
+
+	script = path.Clean(script)
+	if path.IsAbs(script) || strings.HasPrefix(script, "../") {
+		return "", fmt.Errorf("invalid bin script %q in %s", script, filepath.Base(manifestPath))
+	}
+	return script, nil
+}
+
+// pickBinName returns the entry of commands named name, the only entry, or fallback when there
+// are none. The first entry in order is used when there are several.
+func pickBinName(name string, commands map[string]string, fallback string) (string, error) {
+	if value, ok := commands[name]; ok {
+		return pickValue(name, value), nil
+	}
+
+	keys := make([]string, 0, len(commands))
+	for key := range commands {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	switch len(keys) {
+	case 0:
+		if fallback == "" {
+			return "", fmt.Errorf("cannot determine the executable of %q", name)
+		}
+		return fallback, nil
+	case 1:
+	default:
+		logger.Warnf("%q declares several executables, using %s", name, keys[0])
+	}
+	return pickValue(keys[0], commands[keys[0]]), nil
+}
+
+// pickValue returns the part of a command entry which names the executable. Python scripts are
+// named by their key, while Node.js commands run the script of their value.
+func pickValue(key, value string) string {
+	if strings.Contains(value, ":") {
+		// Python entry points have the form module:function
+		return key
+	}
+	return value
+}
+
+// readTOML parses a TOML manifest
+func readTOML(manifestPath string, v any) error {
+	// #nosec G304 -- the manifest is in a repository cloned by ToolHive
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(manifestPath), err)
+	}
+	if err := toml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filepath.Base(manifestPath), err)
+	}
+	return nil
+}
+
+// buildFromGitSource clones a git source, detects its language and builds it with the template
+// of that language. The source is pinned to the cloned commit. If opts.DryRun is true, returns
+// the Dockerfile content instead of building the image.
+func buildFromGitSource(
+	ctx context.Context,
+	imageManager images.ImageManager,
+	serverOrImage string,
+	packageName string,
+	opts BuildOptions,
+	lock *pin.LockFile,
+	locked *pin.Pin,
+) (string, error) {
+	src, err := parseGitSource(packageName)
+	if err != nil {
+		return "", err
+	}
+	if locked != nil {
+		logger.Debugf("Using commit %s of %s from the lockfile", locked.Version, serverOrImage)
+		src.Ref = locked.Version
+	}
+	return buildGitSource(ctx, imageManager, src, serverOrImage, packageName, opts, lock)
+}
+
+// buildGitSource builds a parsed git source, see buildFromGitSource
+func buildGitSource(
+	ctx context.Context,
+	imageManager images.ImageManager,
+	src *gitSource,
+	serverOrImage string,
+	packageName string,
+	opts BuildOptions,
+	lock *pin.LockFile,
+) (string, error) {
+	tempCtx, err := setupTempBuildContext()
+	if err != nil {
+		return "", err
+	}
+	defer tempCtx.CleanupFunc()
+
+	commit, err := cloneGitSource(ctx, src, tempCtx.Dir)
+	if err != nil {
+		return "", err
+	}
+	sourcePin := &pin.Pin{Package: packageName, Version: commit}
+
+	project, err := detectGitProject(tempCtx.Dir, src.Subdir)
+	if err != nil {
+		return "", err
+	}
+	logger.Infof("Detected %s project in %s at commit %s", project.TransportType, packageName, commit)
+
+	templateData := templates.TemplateData{
+		MCPPackage:  project.Package,
+		MCPArgs:     []string{}, // No additional arguments for now
+		IsLocalPath: true,
+		BinName:     project.BinName,
+	}
+	if opts.CACertPath != "" {
+		if err := addCACertToTemplate(opts.CACertPath, &templateData); err != nil {
+			return "", err
+		}
+	}
+
+	dockerfileContent, err := templates.GetDockerfileTemplate(project.TransportType, templateData)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Dockerfile template: %w", err)
+	}
+	dockerfileContent += pinLabels(serverOrImage, sourcePin)
+
+	if opts.DryRun {
+		return dockerfileContent, saveLock(lock, serverOrImage, sourcePin, opts)
+	}
+
+	imageName := opts.ImageName
+	if imageName == "" {
+		imageName = pinnedImageName(templates.TransportTypeGit, sourcePin, dockerfileContent)
+		if cached, err := imageManager.ImageExists(ctx, imageName); err == nil && cached {
+			logger.Infof("Using cached image %s for commit %s", imageName, commit)
+			return imageName, saveLock(lock, serverOrImage, sourcePin, opts)
+		}
+	}
+
+	// The clone is removed with the temporary build context
+	buildCtx := &buildContext{
+		Dir:            project.ContextDir,
+		DockerfilePath: filepath.Join(project.ContextDir, "Dockerfile"),
+		CleanupFunc:    func() {},
+	}
+	imageName, err = buildImageInContext(ctx, imageManager, buildCtx, project.TransportType, packageName,
+		dockerfileContent, templateData.CACertContent, imageName)
+	if err != nil {
+		return "", err
+	}
+	return imageName, saveLock(lock, serverOrImage, sourcePin, opts)
+}
@@ -0,0 +1,347 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/container/templates"
+)
+
+func TestParseGitSource(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    *gitSource
+		wantErr bool
+	}{
+		{
+			name:  "repository only",
+			input: "github.com/example/server",
+			want:  &gitSource{URL: "https://github.com/example/server"},
+		},
+		{
+			name:  "ref and subdirectory",
+			input: "github.com/example/servers@v1.2.0#src/fetch/",
+			want:  &gitSource{URL: "https://github.com/example/servers", Ref: "v1.2.0", Subdir: "src/fetch"},
+		},
+		{
+			name:  "branch with slashes",
+			input: "gitlab.example.com/group/sub/repo.git@feature/new-tool",
+			want:  &gitSource{URL: "https://gitlab.example.com/group/sub/repo.git", Ref: "feature/new-tool"},
+		},
+		{
+			name:  "current directory",
+			input: "github.com/example/server#.",
+			want:  &gitSource{URL: "https://github.com/example/server"},
+		},
+		{
+			name:    "missing repository path",
+			input:   "github.com",
+			wantErr: true,
+		},
+		{
+			name:    "subdirectory outside of the repository",
+			input:   "github.com/example/server#../other",
+			wantErr: true,
+		},
+		{
+			name:    "absolute subdirectory",
+			input:   "github.com/example/server#/etc",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseGitSource(tt.input)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+}
+
+func TestDetectGitProject(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		subdir  string
+		want    gitProject
+		wantErr bool
+	}{
+		{
+			name:   "go module",
+			files:  map[string]string{"go.mod": "module example.com/server\n"},
+			subdir: "",
+			want:   gitProject{TransportType: templates.TransportTypeGO, Package: "."},
+		},
+		{
+			name:   "go package in a parent module",
+			files:  map[string]string{"go.mod": "module example.com/server\n", "cmd/server/main.go": "package main\n"},
+			subdir: "cmd/server",
+			want:   gitProject{TransportType: templates.TransportTypeGO, Package: "./cmd/server"},
+		},
+		{
+			name: "rust crate with a bin target",
+			files: map[string]string{"Cargo.toml": `[package]
+name = "example-server"
+
+[[bin]]
+name = "example-mcp"
+path = "src/main.rs"
+`},
+			want: gitProject{TransportType: templates.TransportTypeCargo, Package: ".", BinName: "example-mcp"},
+		},
+		{
+			name:    "rust workspace",
+			files:   map[string]string{"Cargo.toml": "[workspace]\nmembers = [\"server\"]\n"},
+			wantErr: true,
+		},
+		{
+			name: "python project with scripts",
+			files: map[string]string{"pyproject.toml": `[project]
+name = "example-server"
+
+[project.scripts]
+example-admin = "example.admin:main"
+example-server = "example.server:main"
+`},
+			want: gitProject{TransportType: templates.TransportTypeUVX, Package: ".", BinName: "example-server"},
+		},
+		{
+			name:  "python project without scripts",
+			files: map[string]string{"pyproject.toml": "[project]\nname = \"example-server\"\n"},
+			want:  gitProject{TransportType: templates.TransportTypeUVX, Package: ".", BinName: "example-server"},
+		},
+		{
+			name: "scoped node package with several commands",
+			files: map[string]string{"packages/server/package.json": `{
+  "name": "@example/server",
+  "bin": {"server": "./dist/index.js", "server-cli": "./dist/cli.js"}
+}`},
+			subdir: "packages/server",
+			want:   gitProject{TransportType: templates.TransportTypeNPX, Package: ".", BinName: "dist/index.js"},
+		},
+		{
+			name:  "node package with a single bin script",
+			files: map[string]string{"package.json": `{"name": "example", "bin": "build/main.js"}`},
+			want:  gitProject{TransportType: templates.TransportTypeNPX, Package: ".", BinName: "build/main.js"},
+		},
+		{
+			name:  "node package without bin",
+			files: map[string]string{"package.json": `{"name": "example"}`},
+			want:  gitProject{TransportType: templates.TransportTypeNPX, Package: ".", BinName: "index.js"},
+		},
+		{
+			name: "python script with shell metacharacters",
+			files: map[string]string{"pyproject.toml": `[project]
+name = "example-server"
+
+[project.scripts]
+"x'; touch /tmp/pwned; '" = "example.server:main"
+`},
+			wantErr: true,
+		},
+		{
+			name:    "rust binary with a space",
+			files:   map[string]string{"Cargo.toml": "[package]\nname = \"example server\"\n"},
+			wantErr: true,
+		},
+		{
+			name:    "node bin script with a quote",
+			files:   map[string]string{"package.json": `{"name": "example", "bin": "dist/index.js\", \"-e\", \"x"}`},
+			wantErr: true,
+		},
+		{
+			name:    "unknown language",
+			files:   map[string]string{"README.md": "# Example\n"},
+			wantErr: true,
+		},
+		{
+			name:    "missing subdirectory",
+			files:   map[string]string{"go.mod": "module example.com/server\n"},
+			subdir:  "missing",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			repoDir, err := filepath.EvalSymlinks(t.TempDir())
+			require.NoError(t, err)
+			writeFiles(t, repoDir, tt.files)
+
+			got, err := detectGitProject(repoDir, tt.subdir)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want.TransportType, got.TransportType)
+			assert.Equal(t, tt.want.Package, got.Package)
+			assert.Equal(t, tt.want.BinName, got.BinName)
+			if tt.want.Package == "." {
+				assert.Equal(t, filepath.Join(repoDir, filepath.FromSlash(tt.subdir)), got.ContextDir)
+			} else {
+				assert.Equal(t, repoDir, got.ContextDir)
+			}
+		})
+	}
+}
+
+func TestDetectGitProjectRejectsSymlinkOutsideRepository(t *testing.T) {
+	t.Parallel()
+
+	outside := t.TempDir()
+	writeFiles(t, outside, map[string]string{"go.mod": "module example.com/outside\n"})
+	repoDir := t.TempDir()
+	require.NoError(t, os.Symlink(outside, filepath.Join(repoDir, "server")))
+
+	_, err := detectGitProject(repoDir, "server")
+	require.Error(t, err)
+}
+
+func TestWriteNewFileDoesNotFollowSymlinks(t *testing.T) {
+	t.Parallel()
+
+	target := filepath.Join(t.TempDir(), "bashrc")
+	require.NoError(t, os.WriteFile(target, []byte("original"), 0600))
+	contextDir := t.TempDir()
+	dockerfilePath := filepath.Join(contextDir, "Dockerfile")
+	require.NoError(t, os.Symlink(target, dockerfilePath))
+
+	require.NoError(t, writeDockerfile(dockerfilePath, "FROM scratch\n", false))
+
+	content, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "original", string(content))
+	info, err := os.Lstat(dockerfilePath)
+	require.NoError(t, err)
+	assert.True(t, info.Mode().IsRegular())
+}
+
+// newTestRepository creates a repository with a tagged first commit, a second commit on
+// master and a branch, and returns its path and the hash of the first commit
+func newTestRepository(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	workTree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	commit := func(content string) plumbing.Hash {
+		writeFiles(t, dir, map[string]string{"version.txt": content})
+		_, err := workTree.Add("version.txt")
+		require.NoError(t, err)
+		hash, err := workTree.Commit(content, &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+		return hash
+	}
+
+	first := commit("v1")
+	_, err = repo.CreateTag("v1.0.0", first, nil)
+	require.NoError(t, err)
+	second := commit("v2")
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("feature/x"), second)))
+	commit("v3")
+
+	return dir, first.String()
+}
+
+func TestCloneGitSource(t *testing.T) {
+	t.Parallel()
+
+	repoDir, firstCommit := newTestRepository(t)
+
+	tests := []struct {
+		name    string
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{name: "default branch", ref: "", want: "v3"},
+		{name: "tag", ref: "v1.0.0", want: "v1"},
+		{name: "branch", ref: "feature/x", want: "v2"},
+		{name: "abbreviated commit", ref: firstCommit[:10], want: "v1"},
+		{name: "missing ref", ref: "does-not-exist", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+
+			commit, err := cloneGitSource(context.Background(), &gitSource{URL: repoDir, Ref: tt.ref}, dir)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Regexp(t, `^[0-9a-f]{40}$`, commit)
+
+			content, err := os.ReadFile(filepath.Join(dir, "version.txt"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(content))
+			assert.NoDirExists(t, filepath.Join(dir, ".git"))
+		})
+	}
+}
+
+func TestBuildGitSourceDryRun(t *testing.T) {
+	t.Parallel()
+
+	repoDir := t.TempDir()
+	repo, err := git.PlainInit(repoDir, false)
+	require.NoError(t, err)
+	workTree, err := repo.Worktree()
+	require.NoError(t, err)
+	writeFiles(t, repoDir, map[string]string{
+		"go.mod":             "module example.com/server\n",
+		"cmd/server/main.go": "package main\n\nfunc main() {}\n",
+	})
+	require.NoError(t, workTree.AddGlob("."))
+	_, err = workTree.Commit("initial", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	src := &gitSource{URL: repoDir, Subdir: "cmd/server"}
+	dockerfile, err := buildGitSource(context.Background(), nil, src, "git://example.com/server#cmd/server",
+		"example.com/server#cmd/server", BuildOptions{DryRun: true}, nil)
+	require.NoError(t, err)
+	assert.Contains(t, dockerfile, "COPY . /build/")
+	assert.Contains(t, dockerfile, "go build -o /app/mcp-server ./cmd/server")
+	assert.Contains(t, dockerfile, `toolhive-source="git://example.com/server#cmd/server"`)
+	assert.Regexp(t, `toolhive-pin-version="[0-9a-f]{40}"`, dockerfile)
+}
@@ -0,0 +1,192 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+// permissionLearner records the accesses of a workload in permission learning mode
+type permissionLearner struct {
+	recorder *permissions.Recorder
+	output   string
+	// baseName is the name of the saved run configuration of the workload
+	baseName string
+	// declared is the permission profile the workload was started with
+	declared *permissions.Profile
+	// stopWatching stops recording file accesses, and is nil if they are not recorded
+	stopWatching func()
+}
+
+// NetworkBeforeLearning is the network configuration of a workload before permission learning
+type NetworkBeforeLearning struct {
+	// Network are the network permissions of the permission profile
+	Network *permissions.NetworkPermissions `json:"network,omitempty" yaml:"network,omitempty"`
+	// IsolateNetwork indicates whether the network of the container was isolated
+	IsolateNetwork bool `json:"isolate_network,omitempty" yaml:"isolate_network,omitempty"`
+}
+
+// watchedMount is a mount whose file accesses are recorded
+type watchedMount struct {
+	declaration permissions.MountDeclaration
+	// source is the absolute path of the mount on the host
+	source string
+}
+
+// enablePermissionLearning routes the outbound traffic of the container through the egress proxy,
+// which allows and records all of it. The mounts and inbound network permissions are kept.
+func (c *RunConfig) enablePermissionLearning() error {
+	if c.RemoteURL != "" {
+		return fmt.Errorf("permission learning is not supported for remote MCP servers")
+	}
+
+	network := &permissions.NetworkPermissions{
+		Outbound: &permissions.OutboundNetworkPermissions{InsecureAllowAll: true},
+	}
+	profile := permissions.Profile{}
+	if c.PermissionProfile != nil {
+		profile = *c.PermissionProfile
+		if profile.Network != nil {
+			network.Inbound = profile.Network.Inbound
+		}
+	}
+	c.NetworkBeforeLearning = &NetworkBeforeLearning{Network: profile.Network, IsolateNetwork: c.IsolateNetwork}
+	profile.Network = network
+	c.PermissionProfile = &profile
+	c.IsolateNetwork = true
+
+	if c.LearnPermissionsOutput == "" {
+		output, err := xdg.StateFile(filepath.Join("toolhive", "permissions", c.BaseName+".json"))
+		if err != nil {
+			return fmt.Errorf("failed to determine the path of the suggested permission profile: %w", err)
+		}
+		c.LearnPermissionsOutput = output
+	}
+	return nil
+}
+
+// startPermissionLearning starts recording the mounted paths accessed by the workload. The outbound
+// connections are recorded by the egress monitoring. It returns nil if learning is not enabled.
+func (r *Runner) startPermissionLearning() *permissionLearner {
+	if !r.Config.LearnPermissions || r.Config.PermissionProfile == nil {
+		return nil
+	}
+
+	profile := r.Config.PermissionProfile
+	var mounts []watchedMount
+	var declarations []permissions.MountDeclaration
+	for _, declaration := range append(append([]permissions.MountDeclaration{}, profile.Read...), profile.Write...) {
+		if declaration.IsResourceURI() {
+			continue
+		}
+		source, _, err := declaration.Parse()
+		if err != nil {
+			continue
+		}
+		source, err = filepath.Abs(source)
+		if err != nil {
+			continue
+		}
+		mounts = append(mounts, watchedMount{declaration: declaration, source: source})
+		declarations = append(declarations, declaration)
+	}
+
+	learner := &permissionLearner{
+		recorder: permissions.NewRecorder(declarations),
+		output:   r.Config.LearnPermissionsOutput,
+		baseName: r.Config.BaseName,
+		declared: profile,
+	}
+	if len(mounts) > 0 {
+		stop, err := watchMounts(mounts, learner.recorder)
+		if err != nil {
+			logger.Warnf("Warning: Not recording file accesses, the mounts of the permission profile are suggested unchanged: %v", err)
+		} else {
+			learner.stopWatching = stop
+		}
+	}
+
+	logger.Infof("Learning permissions of %s: all outbound connections are allowed and recorded", r.Config.ContainerName)
+	return learner
+}
+
+// finish stops recording, writes the suggested permission profile, and ends learning in the saved
+// run configuration, so that the workload is not restarted with the permissions of learning mode
+func (l *permissionLearner) finish() {
+	if l == nil {
+		return
+	}
+	defer l.endLearning(context.Background())
+
+	// Stop recording first, so that the accesses recorded while shutting down are suggested too
+	if l.stopWatching != nil {
+		l.stopWatching()
+	}
+	profile := l.recorder.SuggestedProfile()
+	if l.stopWatching != nil {
+		for _, access := range l.recorder.Paths() {
+			mode := "read"
+			if access.Write {
+				mode = "write"
+			}
+			logger.Infof("Learned file access: %s (%s)", access.Path, mode)
+		}
+	} else {
+		profile.Read = append(profile.Read, l.declared.Read...)
+		profile.Write = append(profile.Write, l.declared.Write...)
+	}
+
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		logger.Warnf("Warning: Failed to serialize the suggested permission profile: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(l.output), 0750); err != nil {
+		logger.Warnf("Warning: Failed to create directory for the suggested permission profile: %v", err)
+		return
+	}
+	if err := os.WriteFile(l.output, append(data, '\n'), 0600); err != nil {
+		logger.Warnf("Warning: Failed to write the suggested permission profile: %v", err)
+		return
+	}
+	logger.Infof("Suggested permission profile written to %s", l.output)
+}
+
+// endLearning restores the network configuration from before learning in the saved run configuration
+func (l *permissionLearner) endLearning(ctx context.Context) {
+	config, err := LoadState(ctx, l.baseName)
+	if err != nil {
+		logger.Debugf("Not ending permission learning in the saved configuration of %s: %v", l.baseName, err)
+		return
+	}
+	if !config.LearnPermissions {
+		return
+	}
+	config.restoreNetworkBeforeLearning()
+	if err := config.SaveState(ctx); err != nil {
+		logger.Warnf("Warning: Failed to end permission learning in the saved configuration of %s: %v", l.baseName, err)
+	}
+}
+
+// restoreNetworkBeforeLearning turns off permission learning, and restores the network configuration
+// it replaced
+func (c *RunConfig) restoreNetworkBeforeLearning() {
+	c.LearnPermissions = false
+	if c.NetworkBeforeLearning == nil {
+		return
+	}
+	if c.PermissionProfile != nil {
+		profile := *c.PermissionProfile
+		profile.Network = c.NetworkBeforeLearning.Network
+		c.PermissionProfile = &profile
+	}
+	c.IsolateNetwork = c.NetworkBeforeLearning.IsolateNetwork
+	c.NetworkBeforeLearning = nil
+}
@@ -0,0 +1,162 @@
+//go:build linux
+// +build linux
+
+package runner
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+const (
+	// inotifyWriteMask are the events which modify a watched path
+	inotifyWriteMask = unix.IN_MODIFY | unix.IN_ATTRIB | unix.IN_CREATE | unix.IN_DELETE |
+		unix.IN_MOVED_FROM | unix.IN_MOVED_TO
+	// inotifyMask are the events which are recorded
+	inotifyMask = inotifyWriteMask | unix.IN_ACCESS | unix.IN_OPEN
+
+	// maxWatchedDirectories limits the number of directories which are watched across all mounts
+	maxWatchedDirectories = 8192
+
+	// thvIgnoreFile is read by ToolHive itself when the mounts are set up
+	thvIgnoreFile = ".thvignore"
+)
+
+// mountWatcher records the file accesses under mounts with inotify. Accesses from containers
+// are visible on the host because bind mounts share the host file system.
+type mountWatcher struct {
+	fd       int
+	file     *os.File
+	recorder *permissions.Recorder
+
+	mu      sync.Mutex
+	watches map[int32]watchedMount
+	limited bool
+}
+
+// watchMounts records the file accesses under the mounts until the returned function is called
+func watchMounts(mounts []watchedMount, recorder *permissions.Recorder) (func(), error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize inotify: %w", err)
+	}
+
+	w := &mountWatcher{
+		fd: fd,
+		// The descriptor is non-blocking, so closing the file interrupts pending reads
+		file:     os.NewFile(uintptr(fd), "inotify"),
+		recorder: recorder,
+		watches:  make(map[int32]watchedMount),
+	}
+	for _, mount := range mounts {
+		w.addTree(mount.source, mount.declaration)
+	}
+
+	go w.run()
+	return func() { _ = w.file.Close() }, nil
+}
+
+// addTree watches path and, if it is a directory, all directories below it
+func (w *mountWatcher) addTree(path string, declaration permissions.MountDeclaration) {
+	// Collect the directories before watching them, so walking them is not recorded
+	var paths []string
+	_ = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if p == path || d.IsDir() {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, p := range paths {
+		if len(w.watches) >= maxWatchedDirectories {
+			if !w.limited {
+				logger.Warnf("Warning: Not recording file accesses below %s, more than %d directories are mounted",
+					p, maxWatchedDirectories)
+				w.limited = true
+			}
+			return
+		}
+		wd, err := unix.InotifyAddWatch(w.fd, p, inotifyMask)
+		if err != nil {
+			logger.Debugf("Failed to watch %s: %v", p, err)
+			continue
+		}
+		w.watches[int32(wd)] = watchedMount{declaration: declaration, source: p} //nolint:gosec // wd is an int32
+	}
+}
+
+// run reads inotify events until the watcher is closed
+func (w *mountWatcher) run() {
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			return
+		}
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			wd := int32(binary.NativeEndian.Uint32(buf[offset:])) //nolint:gosec // wd is an int32
+			mask := binary.NativeEndian.Uint32(buf[offset+4:])
+			nameLen := int(binary.NativeEndian.Uint32(buf[offset+12:]))
+			offset += unix.SizeofInotifyEvent
+			name := ""
+			if nameLen > 0 && offset+nameLen <= n {
+				name = strings.TrimRight(string(buf[offset:offset+nameLen]), "\x00")
+			}
+			offset += nameLen
+			w.handle(wd, mask, name)
+		}
+	}
+}
+
+// handle records a single inotify event
+func (w *mountWatcher) handle(wd int32, mask uint32, name string) {
+	if mask&unix.IN_Q_OVERFLOW != 0 {
+		logger.Warnf("Warning: Too many file accesses, some were not recorded")
+		return
+	}
+
+	w.mu.Lock()
+	watched, ok := w.watches[wd]
+	if ok && mask&unix.IN_IGNORED != 0 {
+		delete(w.watches, wd)
+	}
+	w.mu.Unlock()
+	if !ok || mask&unix.IN_IGNORED != 0 {
+		return
+	}
+
+	path := watched.source
+	if name != "" {
+		path = filepath.Join(path, name)
+	}
+	if filepath.Base(path) == thvIgnoreFile {
+		return
+	}
+
+	isDir := mask&unix.IN_ISDIR != 0
+	if isDir && mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0 {
+		w.addTree(path, watched.declaration)
+	}
+
+	// Listing a directory is not recorded as a read, only reading the files in it is
+	write := mask&inotifyWriteMask != 0
+	if !write && isDir {
+		return
+	}
+	w.recorder.RecordAccess(watched.declaration, path, write)
+}
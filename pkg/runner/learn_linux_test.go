@@ -0,0 +1,98 @@
+//go:build linux
+// +build linux
+
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+func TestWatchMounts(t *testing.T) {
+	t.Parallel()
+
+	logger.Initialize()
+
+	readDir := t.TempDir()
+	writeDir := t.TempDir()
+	unusedDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(readDir, "nested"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(readDir, "nested", "config.json"), []byte("{}"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(readDir, ".thvignore"), []byte("*.key"), 0600))
+
+	mounts := []watchedMount{
+		{declaration: permissions.MountDeclaration(readDir + ":/read"), source: readDir},
+		{declaration: permissions.MountDeclaration(writeDir + ":/write"), source: writeDir},
+		{declaration: permissions.MountDeclaration(unusedDir + ":/unused"), source: unusedDir},
+	}
+	declarations := []permissions.MountDeclaration{mounts[0].declaration, mounts[1].declaration, mounts[2].declaration}
+	recorder := permissions.NewRecorder(declarations)
+
+	stop, err := watchMounts(mounts, recorder)
+	require.NoError(t, err)
+	t.Cleanup(stop)
+
+	_, err = os.ReadFile(filepath.Join(readDir, "nested", "config.json"))
+	require.NoError(t, err)
+	_, err = os.ReadFile(filepath.Join(readDir, ".thvignore"))
+	require.NoError(t, err)
+	_, err = os.ReadDir(unusedDir)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(writeDir, "cache"), 0750))
+	// Wait for the new directory to be watched before writing to it
+	require.Eventually(t, func() bool { return len(recorder.Paths()) >= 2 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, os.WriteFile(filepath.Join(writeDir, "cache", "index"), []byte("data"), 0600))
+
+	require.Eventually(t, func() bool {
+		profile := recorder.SuggestedProfile()
+		return len(profile.Read) == 1 && len(profile.Write) == 1 &&
+			slices.ContainsFunc(recorder.Paths(), func(access permissions.PathAccess) bool {
+				return access.Path == filepath.Join(writeDir, "cache", "index")
+			})
+	}, 5*time.Second, 10*time.Millisecond)
+
+	profile := recorder.SuggestedProfile()
+	assert.Equal(t, []permissions.MountDeclaration{mounts[0].declaration}, profile.Read)
+	assert.Equal(t, []permissions.MountDeclaration{mounts[1].declaration}, profile.Write)
+	assert.Contains(t, recorder.Paths(),
+		permissions.PathAccess{Path: filepath.Join(readDir, "nested", "config.json"), Write: false})
+	for _, access := range recorder.Paths() {
+		assert.NotEqual(t, ".thvignore", filepath.Base(access.Path))
+	}
+}
+
+func TestPermissionLearner_Finish(t *testing.T) {
+	t.Parallel()
+
+	logger.Initialize()
+
+	output := filepath.Join(t.TempDir(), "permissions", "server.json")
+	declared := &permissions.Profile{Read: []permissions.MountDeclaration{"/data:/data"}}
+	learner := &permissionLearner{
+		recorder: permissions.NewRecorder(declared.Read),
+		output:   output,
+		declared: declared,
+	}
+	learner.recorder.RecordConnection("api.github.com", 443)
+	learner.finish()
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	var profile permissions.Profile
+	require.NoError(t, json.Unmarshal(data, &profile))
+
+	// Without recorded file accesses, the declared mounts are suggested unchanged
+	assert.Equal(t, declared.Read, profile.Read)
+	assert.Equal(t, []string{"api.github.com"}, profile.Network.Outbound.AllowHost)
+	assert.Equal(t, []int{443}, profile.Network.Outbound.AllowPort)
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+// +build !linux
+
+package runner
+
+import (
+	"fmt"
+
+	"github.com/stacklok/toolhive/pkg/permissions"
+)
+
+// watchMounts records the file accesses under the mounts. This is only available on Linux.
+func watchMounts(_ []watchedMount, _ *permissions.Recorder) (func(), error) {
+	return nil, fmt.Errorf("recording file accesses is only available on Linux")
+}
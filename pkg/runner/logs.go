@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/config"
+	rt "github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/logs"
+)
+
+// startLogCollection streams the container output of the workload, starting at since,
+// into the workload's rotated log files until ctx is done or the container exits.
+// It is a no-op for remote workloads and for runtimes which cannot stream logs.
+func (r *Runner) startLogCollection(ctx context.Context, since time.Time) {
+	if r.Config.RemoteURL != "" {
+		return
+	}
+	streamer, ok := r.Config.Deployer.(rt.LogStreamer)
+	if !ok {
+		return
+	}
+
+	cfg := config.NewDefaultProvider().GetConfig()
+	store, err := logs.NewDefaultStore(cfg.LogRetention)
+	if err != nil {
+		logger.Warnf("Warning: Failed to set up log collection: %v", err)
+		return
+	}
+
+	writer, err := store.Writer(r.Config.BaseName, logs.SourceContainer)
+	if err != nil {
+		logger.Warnf("Warning: Failed to open container log file: %v", err)
+		return
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		err := streamer.StreamWorkloadLogs(ctx, r.Config.ContainerName, since, pw, pw)
+		if err != nil {
+			logger.Debugf("Container log stream for %s ended: %v", r.Config.ContainerName, err)
+		}
+		_ = pw.CloseWithError(err)
+	}()
+
+	go func() {
+		defer writer.Close()
+		if err := logs.NewCollector(logs.SourceContainer, writer).Collect(pr); err != nil {
+			logger.Warnf("Warning: Failed to collect container logs for %s: %v", r.Config.ContainerName, err)
+		}
+	}()
+
+	logger.Debugf("Collecting container logs to %s", store.Path(r.Config.BaseName, logs.SourceContainer))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/stacklok/toolhive/pkg/mcp"
 	"github.com/stacklok/toolhive/pkg/telemetry"
 	"github.com/stacklok/toolhive/pkg/transport/types"
+	"github.com/stacklok/toolhive/pkg/usage"
 )
 
 // GetSupportedMiddlewareFactories returns a map of supported middleware types to their factory functions
@@ -23,6 +24,7 @@ func GetSupportedMiddlewareFactories() map[string]types.MiddlewareFactory {
 		telemetry.MiddlewareType:         telemetry.CreateMiddleware,
 		authz.MiddlewareType:             authz.CreateMiddleware,
 		audit.MiddlewareType:             audit.CreateMiddleware,
+		usage.MiddlewareType:             usage.CreateMiddleware,
 	}
 }
 
@@ -121,6 +123,19 @@ func PopulateMiddlewareConfigs(config *RunConfig) error {
 		middlewareConfigs = append(middlewareConfigs, *auditConfig)
 	}
 
+	// Usage accounting middleware (if enabled)
+	if config.UsageConfig != nil {
+		usageParams := usage.MiddlewareParams{
+			Config:     config.UsageConfig,
+			ServerName: config.Name,
+		}
+		usageConfig, err := types.NewMiddlewareConfig(usage.MiddlewareType, usageParams)
+		if err != nil {
+			return fmt.Errorf("failed to create usage middleware config: %w", err)
+		}
+		middlewareConfigs = append(middlewareConfigs, *usageConfig)
+	}
+
 	// Set the populated middleware configs
 	config.MiddlewareConfigs = middlewareConfigs
 	return nil
@@ -0,0 +1,90 @@
+package pin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultLockFile is the lockfile used by builds of protocol schemes, relative to the current directory
+const DefaultLockFile = "toolhive.lock"
+
+// lockFileVersion is the version of the lockfile format
+const lockFileVersion = 1
+
+// LockFile records the pins of protocol schemes, so that builds on different machines use
+// identical packages. Packages are keyed by the protocol scheme as given by the user,
+// for example npx://@modelcontextprotocol/server-filesystem.
+type LockFile struct {
+	Version  int             `json:"version"`
+	Packages map[string]*Pin `json:"packages"`
+}
+
+// LoadLockFile reads a lockfile. A missing lockfile is returned as an empty one.
+func LoadLockFile(path string) (*LockFile, error) {
+	lock := &LockFile{Version: lockFileVersion, Packages: map[string]*Pin{}}
+
+	// #nosec G304 -- the lockfile path is chosen by the user
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return lock, nil
+		}
+		return nil, fmt.Errorf("failed to read lockfile %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile %s: %w", path, err)
+	}
+	if lock.Version > lockFileVersion {
+		return nil, fmt.Errorf("lockfile %s has version %d, which is newer than the supported version %d",
+			path, lock.Version, lockFileVersion)
+	}
+	if lock.Packages == nil {
+		lock.Packages = map[string]*Pin{}
+	}
+	return lock, nil
+}
+
+// Get returns the pin of a protocol scheme, or nil if it is not locked
+func (l *LockFile) Get(reference string) *Pin {
+	return l.Packages[reference]
+}
+
+// Set records the pin of a protocol scheme
+func (l *LockFile) Set(reference string, pin *Pin) {
+	l.Packages[reference] = pin
+}
+
+// Save writes the lockfile atomically
+func (l *LockFile) Save(path string) error {
+	l.Version = lockFileVersion
+
+	// Maps are encoded with sorted keys, so the lockfile diffs cleanly
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode lockfile: %w", err)
+	}
+	data = append(data, '\n')
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to write lockfile %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write lockfile %s: %w", path, err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write lockfile %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write lockfile %s: %w", path, err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
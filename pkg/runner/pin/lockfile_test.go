@@ -0,0 +1,53 @@
+package pin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), DefaultLockFile)
+
+	// A missing lockfile is empty
+	lock, err := LoadLockFile(path)
+	require.NoError(t, err)
+	assert.Nil(t, lock.Get("npx://server"))
+
+	p := &Pin{Package: "server", Version: "1.0.0", Hash: "sha512-abc"}
+	lock.Set("npx://server", p)
+	require.NoError(t, lock.Save(path))
+
+	loaded, err := LoadLockFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, p, loaded.Get("npx://server"))
+	assert.Equal(t, lockFileVersion, loaded.Version)
+}
+
+func TestLoadLockFileErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "invalid json", content: "{"},
+		{name: "newer version", content: `{"version": 99, "packages": {}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), DefaultLockFile)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+
+			_, err := LoadLockFile(path)
+			assert.Error(t, err)
+		})
+	}
+}
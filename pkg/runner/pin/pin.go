@@ -0,0 +1,368 @@
+// Package pin resolves the packages of protocol schemes to exact versions and content
+// hashes, so that images built from them are reproducible, and records them in lockfiles.
+package pin
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+
+	"github.com/stacklok/toolhive/pkg/container/templates"
+	"github.com/stacklok/toolhive/pkg/versions"
+)
+
+// Default package index URLs
+const (
+	DefaultNPMRegistry = "https://registry.npmjs.org"
+	DefaultPyPIIndex   = "https://pypi.org/pypi"
+	DefaultGoProxy     = "https://proxy.golang.org"
+	DefaultGoSumDB     = "https://sum.golang.org"
+	DefaultCratesIndex = "https://crates.io/api/v1"
+	DefaultNuGetIndex  = "https://api.nuget.org/v3-flatcontainer"
+)
+
+// requestTimeout is the timeout of requests to package indexes
+const requestTimeout = 30 * time.Second
+
+// ErrUnsupported is returned for transport types whose packages cannot be pinned
+var ErrUnsupported = errors.New("pinning is not supported")
+
+// ErrHashMismatch is returned when a package index reports another hash for a locked version
+var ErrHashMismatch = errors.New("package hash does not match the lockfile")
+
+// Pin is the exact version of a package and the hash of its content
+type Pin struct {
+	// Package is the name of the package, without version
+	Package string `json:"package"`
+	// Version is the exact version of the package
+	Version string `json:"version"`
+	// Hash is the content hash reported by the package index: an npm integrity hash,
+	// a PyPI or crates.io sha256, a Go module sum or a NuGet sha512
+	Hash string `json:"hash,omitempty"`
+}
+
+// Spec returns the package at its pinned version, in the format of protocol schemes
+func (p *Pin) Spec() string {
+	return p.Package + "@" + p.Version
+}
+
+// Resolver resolves packages against their package indexes
+type Resolver struct {
+	client *http.Client
+
+	NPMRegistry string
+	PyPIIndex   string
+	GoProxy     string
+	GoSumDB     string
+	CratesIndex string
+	NuGetIndex  string
+}
+
+// NewResolver creates a resolver for the public package indexes
+func NewResolver() *Resolver {
+	return &Resolver{
+		client:      &http.Client{Timeout: requestTimeout},
+		NPMRegistry: DefaultNPMRegistry,
+		PyPIIndex:   DefaultPyPIIndex,
+		GoProxy:     DefaultGoProxy,
+		GoSumDB:     DefaultGoSumDB,
+		CratesIndex: DefaultCratesIndex,
+		NuGetIndex:  DefaultNuGetIndex,
+	}
+}
+
+// SplitVersion splits the package of a protocol scheme into its name and version.
+// The version is empty if the package is not versioned.
+func SplitVersion(packageSpec string) (string, string) {
+	// The @ of npm scopes is not a version separator
+	i := strings.LastIndex(packageSpec, "@")
+	if i <= 0 {
+		return packageSpec, ""
+	}
+	return packageSpec[:i], packageSpec[i+1:]
+}
+
+// Resolve resolves a package of a protocol scheme to a pin. If locked is not nil, its version
+// is used and the hash reported by the package index must match the locked hash.
+func (r *Resolver) Resolve(
+	ctx context.Context,
+	transportType templates.TransportType,
+	packageSpec string,
+	locked *Pin,
+) (*Pin, error) {
+	name, version := SplitVersion(packageSpec)
+	if locked != nil {
+		name, version = locked.Package, locked.Version
+	}
+
+	var pin *Pin
+	var err error
+	switch transportType {
+	case templates.TransportTypeNPX:
+		pin, err = r.resolveNPM(ctx, name, version)
+	case templates.TransportTypeUVX, templates.TransportTypePipx:
+		pin, err = r.resolvePyPI(ctx, name, version)
+	case templates.TransportTypeGO:
+		pin, err = r.resolveGo(ctx, name, version)
+	case templates.TransportTypeCargo:
+		pin, err = r.resolveCrate(ctx, name, version)
+	case templates.TransportTypeDotnet:
+		pin, err = r.resolveNuGet(ctx, name, version)
+	default:
+		return nil, fmt.Errorf("%w for %s packages", ErrUnsupported, transportType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", packageSpec, err)
+	}
+
+	if locked != nil && locked.Hash != "" && pin.Hash != locked.Hash {
+		return nil, fmt.Errorf("%w: %s is locked to %s but the index reports %s",
+			ErrHashMismatch, pin.Spec(), locked.Hash, pin.Hash)
+	}
+	return pin, nil
+}
+
+// resolveNPM resolves a version or dist-tag of an npm package
+func (r *Resolver) resolveNPM(ctx context.Context, name, version string) (*Pin, error) {
+	if version == "" {
+		version = "latest"
+	}
+
+	var manifest struct {
+		Version string `json:"version"`
+		Dist    struct {
+			Integrity string `json:"integrity"`
+			Shasum    string `json:"shasum"`
+		} `json:"dist"`
+	}
+	// Scoped packages keep their @ but escape the slash
+	escaped := strings.Replace(name, "/", "%2F", 1)
+	if err := r.getJSON(ctx, r.NPMRegistry+"/"+escaped+"/"+url.PathEscape(version), &manifest); err != nil {
+		return nil, err
+	}
+
+	hash := manifest.Dist.Integrity
+	if hash == "" && manifest.Dist.Shasum != "" {
+		// Old packages only have a hex shasum, which npm records as a sha1 integrity
+		shasum, err := hex.DecodeString(manifest.Dist.Shasum)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shasum of %s: %w", name, err)
+		}
+		hash = "sha1-" + base64.StdEncoding.EncodeToString(shasum)
+	}
+	return &Pin{Package: name, Version: manifest.Version, Hash: hash}, nil
+}
+
+// resolvePyPI resolves a Python package. The hash is the sha256 of the source distribution,
+// or of the first distribution if there is none.
+func (r *Resolver) resolvePyPI(ctx context.Context, name, version string) (*Pin, error) {
+	// Extras are not part of the project name
+	project, _, _ := strings.Cut(name, "[")
+
+	endpoint := r.PyPIIndex + "/" + url.PathEscape(project) + "/json"
+	if version != "" {
+		endpoint = r.PyPIIndex + "/" + url.PathEscape(project) + "/" + url.PathEscape(version) + "/json"
+	}
+
+	var release struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		URLs []struct {
+			PackageType string `json:"packagetype"`
+			Digests     struct {
+				SHA256 string `json:"sha256"`
+			} `json:"digests"`
+		} `json:"urls"`
+	}
+	if err := r.getJSON(ctx, endpoint, &release); err != nil {
+		return nil, err
+	}
+	if len(release.URLs) == 0 {
+		return nil, fmt.Errorf("no distributions found for %s %s", project, release.Info.Version)
+	}
+
+	digest := release.URLs[0].Digests.SHA256
+	for _, dist := range release.URLs {
+		if dist.PackageType == "sdist" {
+			digest = dist.Digests.SHA256
+			break
+		}
+	}
+	return &Pin{Package: name, Version: release.Info.Version, Hash: "sha256:" + digest}, nil
+}
+
+// resolveGo resolves the module of a Go package through the module proxy, and looks up
+// the module sum in the checksum database
+func (r *Resolver) resolveGo(ctx context.Context, name, version string) (*Pin, error) {
+	if version == "" {
+		version = "latest"
+	}
+
+	// The package may be in a subdirectory of its module, so try its parents
+	var modulePath, resolved string
+	var lastErr error
+	for candidate := name; candidate != "." && candidate != ""; candidate = parentPath(candidate) {
+		escaped, err := module.EscapePath(candidate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid module path %s: %w", candidate, err)
+		}
+
+		endpoint := r.GoProxy + "/" + escaped + "/@latest"
+		if version != "latest" {
+			escapedVersion, err := module.EscapeVersion(version)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version %s: %w", version, err)
+			}
+			endpoint = r.GoProxy + "/" + escaped + "/@v/" + escapedVersion + ".info"
+		}
+
+		var info struct {
+			Version string `json:"Version"`
+		}
+		if err := r.getJSON(ctx, endpoint, &info); err != nil {
+			lastErr = err
+			continue
+		}
+		modulePath, resolved = candidate, info.Version
+		break
+	}
+	if modulePath == "" {
+		return nil, fmt.Errorf("no module found for %s: %w", name, lastErr)
+	}
+
+	lookup, err := r.get(ctx, r.GoSumDB+"/lookup/"+modulePath+"@"+resolved)
+	if err != nil {
+		return nil, err
+	}
+	prefix := modulePath + " " + resolved + " "
+	for _, line := range strings.Split(string(lookup), "\n") {
+		if strings.HasPrefix(line, prefix) {
+			return &Pin{Package: name, Version: resolved, Hash: strings.TrimPrefix(line, prefix)}, nil
+		}
+	}
+	return nil, fmt.Errorf("no module sum found for %s@%s", modulePath, resolved)
+}
+
+// parentPath returns the parent of a module path
+func parentPath(p string) string {
+	i := strings.LastIndex(p, "/")
+	if i < 0 {
+		return ""
+	}
+	return p[:i]
+}
+
+// resolveCrate resolves a crate through the crates.io API
+func (r *Resolver) resolveCrate(ctx context.Context, name, version string) (*Pin, error) {
+	var crate struct {
+		Crate struct {
+			MaxStableVersion string `json:"max_stable_version"`
+			MaxVersion       string `json:"max_version"`
+		} `json:"crate"`
+		Versions []struct {
+			Num      string `json:"num"`
+			Checksum string `json:"checksum"`
+			Yanked   bool   `json:"yanked"`
+		} `json:"versions"`
+	}
+	if err := r.getJSON(ctx, r.CratesIndex+"/crates/"+url.PathEscape(name), &crate); err != nil {
+		return nil, err
+	}
+
+	if version == "" {
+		version = crate.Crate.MaxStableVersion
+		if version == "" {
+			version = crate.Crate.MaxVersion
+		}
+	}
+	for _, v := range crate.Versions {
+		if v.Num != version {
+			continue
+		}
+		if v.Yanked {
+			return nil, fmt.Errorf("version %s of %s has been yanked", version, name)
+		}
+		return &Pin{Package: name, Version: v.Num, Hash: "sha256:" + v.Checksum}, nil
+	}
+	return nil, fmt.Errorf("version %q of %s not found", version, name)
+}
+
+// resolveNuGet resolves a NuGet package. NuGet does not publish content hashes in its
+// flat container, so the hash is computed from the package itself.
+func (r *Resolver) resolveNuGet(ctx context.Context, name, version string) (*Pin, error) {
+	id := strings.ToLower(name)
+
+	var index struct {
+		Versions []string `json:"versions"`
+	}
+	if err := r.getJSON(ctx, r.NuGetIndex+"/"+url.PathEscape(id)+"/index.json", &index); err != nil {
+		return nil, err
+	}
+
+	resolved := ""
+	for _, v := range index.Versions {
+		switch {
+		case version != "" && strings.EqualFold(v, version):
+			resolved = v
+		case version == "" && !strings.Contains(v, "-"):
+			// Versions are listed in ascending order; prereleases are skipped
+			resolved = v
+		}
+	}
+	if resolved == "" {
+		return nil, fmt.Errorf("version %q of %s not found", version, name)
+	}
+
+	lower := strings.ToLower(resolved)
+	content, err := r.get(ctx, fmt.Sprintf("%s/%s/%s/%s.%s.nupkg", r.NuGetIndex, id, lower, id, lower))
+	if err != nil {
+		return nil, err
+	}
+	sum := sha512.Sum512(content)
+	return &Pin{Package: name, Version: resolved, Hash: "sha512-" + base64.StdEncoding.EncodeToString(sum[:])}, nil
+}
+
+// getJSON fetches and decodes a JSON document
+func (r *Resolver) getJSON(ctx context.Context, endpoint string, v any) error {
+	body, err := r.get(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse response of %s: %w", endpoint, err)
+	}
+	return nil
+}
+
+// get fetches a document from a package index
+func (r *Resolver) get(ctx context.Context, endpoint string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	// crates.io rejects requests without a user agent
+	req.Header.Set("User-Agent", "ToolHive/"+versions.GetVersionInfo().Version)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", endpoint, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
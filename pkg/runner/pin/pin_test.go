@@ -0,0 +1,184 @@
+package pin
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/container/templates"
+)
+
+// newTestResolver returns a resolver for a fake package index serving the given paths
+func newTestResolver(t *testing.T, responses map[string]string) *Resolver {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.EscapedPath()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return &Resolver{
+		client:      server.Client(),
+		NPMRegistry: server.URL + "/npm",
+		PyPIIndex:   server.URL + "/pypi",
+		GoProxy:     server.URL + "/goproxy",
+		GoSumDB:     server.URL + "/sumdb",
+		CratesIndex: server.URL + "/crates",
+		NuGetIndex:  server.URL + "/nuget",
+	}
+}
+
+func TestSplitVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		spec        string
+		wantName    string
+		wantVersion string
+	}{
+		{"mcp-server-fetch", "mcp-server-fetch", ""},
+		{"mcp-server-fetch@1.0.0", "mcp-server-fetch", "1.0.0"},
+		{"@modelcontextprotocol/server-filesystem", "@modelcontextprotocol/server-filesystem", ""},
+		{"@modelcontextprotocol/server-filesystem@latest", "@modelcontextprotocol/server-filesystem", "latest"},
+		{"github.com/example/server@v1.2.3", "github.com/example/server", "v1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			t.Parallel()
+			name, version := SplitVersion(tt.spec)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantVersion, version)
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	nupkg := "nupkg content"
+	nupkgSum := sha512.Sum512([]byte(nupkg))
+
+	resolver := newTestResolver(t, map[string]string{
+		"/npm/@scope%2Fserver/latest": `{"version":"2.1.0","dist":{"integrity":"sha512-abc"}}`,
+		"/npm/legacy/1.0.0":           `{"version":"1.0.0","dist":{"shasum":"deadbeef"}}`,
+		"/pypi/mcp-server-fetch/json": `{"info":{"version":"0.6.2"},"urls":[
+			{"packagetype":"bdist_wheel","digests":{"sha256":"wheel"}},
+			{"packagetype":"sdist","digests":{"sha256":"sdist"}}]}`,
+		"/goproxy/github.com/!example/server/@latest": `{"Version":"v1.4.0"}`,
+		"/sumdb/lookup/github.com/Example/server@v1.4.0": "123\n" +
+			"github.com/Example/server v1.4.0 h1:module=\n" +
+			"github.com/Example/server v1.4.0/go.mod h1:gomod=\n",
+		"/crates/crates/mcp-server": `{"crate":{"max_stable_version":"0.3.0","max_version":"0.4.0-rc.1"},
+			"versions":[{"num":"0.4.0-rc.1","checksum":"rc"},{"num":"0.3.0","checksum":"stable"},
+			{"num":"0.2.0","checksum":"old","yanked":true}]}`,
+		"/nuget/example.mcp/index.json":                    `{"versions":["1.0.0","1.1.0","2.0.0-preview"]}`,
+		"/nuget/example.mcp/1.1.0/example.mcp.1.1.0.nupkg": nupkg,
+	})
+
+	tests := []struct {
+		name          string
+		transportType templates.TransportType
+		spec          string
+		want          *Pin
+		wantErr       bool
+	}{
+		{
+			name:          "npm scoped package",
+			transportType: templates.TransportTypeNPX,
+			spec:          "@scope/server",
+			want:          &Pin{Package: "@scope/server", Version: "2.1.0", Hash: "sha512-abc"},
+		},
+		{
+			name:          "npm package without integrity",
+			transportType: templates.TransportTypeNPX,
+			spec:          "legacy@1.0.0",
+			want:          &Pin{Package: "legacy", Version: "1.0.0", Hash: "sha1-3q2+7w=="},
+		},
+		{
+			name:          "pypi prefers the sdist",
+			transportType: templates.TransportTypeUVX,
+			spec:          "mcp-server-fetch",
+			want:          &Pin{Package: "mcp-server-fetch", Version: "0.6.2", Hash: "sha256:sdist"},
+		},
+		{
+			name:          "go package in a subdirectory of its module",
+			transportType: templates.TransportTypeGO,
+			spec:          "github.com/Example/server/cmd/server",
+			want:          &Pin{Package: "github.com/Example/server/cmd/server", Version: "v1.4.0", Hash: "h1:module="},
+		},
+		{
+			name:          "crate skips prereleases",
+			transportType: templates.TransportTypeCargo,
+			spec:          "mcp-server",
+			want:          &Pin{Package: "mcp-server", Version: "0.3.0", Hash: "sha256:stable"},
+		},
+		{
+			name:          "yanked crate",
+			transportType: templates.TransportTypeCargo,
+			spec:          "mcp-server@0.2.0",
+			wantErr:       true,
+		},
+		{
+			name:          "nuget package hashes the nupkg",
+			transportType: templates.TransportTypeDotnet,
+			spec:          "Example.Mcp",
+			want: &Pin{Package: "Example.Mcp", Version: "1.1.0",
+				Hash: "sha512-" + base64.StdEncoding.EncodeToString(nupkgSum[:])},
+		},
+		{
+			name:          "missing package",
+			transportType: templates.TransportTypeNPX,
+			spec:          "missing",
+			wantErr:       true,
+		},
+		{
+			name:          "unsupported transport",
+			transportType: templates.TransportTypeGit,
+			spec:          "github.com/example/server",
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := resolver.Resolve(context.Background(), tt.transportType, tt.spec, nil)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolveLocked(t *testing.T) {
+	t.Parallel()
+
+	resolver := newTestResolver(t, map[string]string{
+		"/npm/server/1.0.0": `{"version":"1.0.0","dist":{"integrity":"sha512-published"}}`,
+	})
+
+	// The locked version is used instead of the latest one
+	got, err := resolver.Resolve(context.Background(), templates.TransportTypeNPX, "server",
+		&Pin{Package: "server", Version: "1.0.0", Hash: "sha512-published"})
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", got.Version)
+
+	_, err = resolver.Resolve(context.Background(), templates.TransportTypeNPX, "server",
+		&Pin{Package: "server", Version: "1.0.0", Hash: "sha512-tampered"})
+	require.ErrorIs(t, err, ErrHashMismatch)
+}
@@ -0,0 +1,135 @@
+package runner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stacklok/toolhive/pkg/container/templates"
+	"github.com/stacklok/toolhive/pkg/labels"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/runner/pin"
+)
+
+// packageResolver resolves the packages of protocol schemes to pins
+type packageResolver interface {
+	Resolve(ctx context.Context, transportType templates.TransportType, packageSpec string, locked *pin.Pin) (*pin.Pin, error)
+}
+
+// loadLock loads the lockfile of the build and returns the pin of serverOrImage in it.
+// The pin is nil when the package is not locked or when the lock is being updated.
+func loadLock(serverOrImage string, opts BuildOptions) (*pin.LockFile, *pin.Pin, error) {
+	if opts.LockFile == "" {
+		return nil, nil, nil
+	}
+
+	lock, err := pin.LoadLockFile(opts.LockFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.UpdateLock {
+		return lock, nil, nil
+	}
+	return lock, lock.Get(serverOrImage), nil
+}
+
+// resolvePin resolves a package to a pin. Packages that cannot be resolved are built unpinned,
+// unless the lock is being updated or the package does not match its lock.
+func resolvePin(
+	ctx context.Context,
+	transportType templates.TransportType,
+	packageName string,
+	opts BuildOptions,
+	locked *pin.Pin,
+) (*pin.Pin, error) {
+	// Local sources have no version to pin
+	if transportType == templates.TransportTypeGO && isLocalGoPath(packageName) {
+		if opts.UpdateLock {
+			return nil, fmt.Errorf("local path %s cannot be locked", packageName)
+		}
+		return nil, nil
+	}
+
+	resolver := opts.resolver
+	if resolver == nil {
+		resolver = pin.NewResolver()
+	}
+
+	p, err := resolver.Resolve(ctx, transportType, packageName, locked)
+	if err == nil {
+		logger.Debugf("Pinned %s to %s (%s)", packageName, p.Version, p.Hash)
+		return p, nil
+	}
+	if opts.UpdateLock || errors.Is(err, pin.ErrHashMismatch) {
+		return nil, err
+	}
+
+	// Package indexes may be unreachable, for example in air-gapped environments
+	if locked != nil {
+		logger.Warnf("Failed to verify %s, using the version in the lockfile: %v", packageName, err)
+		return locked, nil
+	}
+	logger.Warnf("Failed to pin %s, building it unpinned: %v", packageName, err)
+	return nil, nil
+}
+
+// saveLock records a pin in the lockfile when the lock is being updated
+func saveLock(lock *pin.LockFile, serverOrImage string, p *pin.Pin, opts BuildOptions) error {
+	if lock == nil || p == nil || !opts.UpdateLock {
+		return nil
+	}
+
+	lock.Set(serverOrImage, p)
+	if err := lock.Save(opts.LockFile); err != nil {
+		return err
+	}
+	logger.Infof("Locked %s to %s in %s", serverOrImage, p.Version, opts.LockFile)
+	return nil
+}
+
+// pinLabels returns the Dockerfile instruction which labels the image with its pin
+func pinLabels(serverOrImage string, p *pin.Pin) string {
+	if p == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n# Pinned package\nLABEL")
+	fmt.Fprintf(&b, " %s=%s", labels.LabelSource, strconv.Quote(serverOrImage))
+	fmt.Fprintf(&b, " %s=%s", labels.LabelPinVersion, strconv.Quote(p.Version))
+	if p.Hash != "" {
+		fmt.Fprintf(&b, " %s=%s", labels.LabelPinHash, strconv.Quote(p.Hash))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// pinnedImageName returns the name of the image of a pin. The tag contains the pinned version
+// and a hash of the Dockerfile, so the image is rebuilt when the template or CA certificate change.
+func pinnedImageName(transportType templates.TransportType, p *pin.Pin, dockerfileContent string) string {
+	sum := sha256.Sum256([]byte(dockerfileContent))
+
+	// Tags may only contain letters, digits, underscores, periods and dashes
+	version := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.', r == '-':
+			return r
+		default:
+			return '-'
+		}
+	}, p.Version)
+	// Tags are at most 128 characters long, and git commits are 40
+	if len(version) > 64 {
+		version = version[:64]
+	}
+
+	return strings.ToLower(fmt.Sprintf("toolhivelocal/%s-%s:%s-%s",
+		string(transportType),
+		PackageNameToImageName(p.Package),
+		version,
+		hex.EncodeToString(sum[:])[:12]))
+}
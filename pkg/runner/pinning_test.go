@@ -0,0 +1,187 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/container/templates"
+	"github.com/stacklok/toolhive/pkg/runner/pin"
+)
+
+// fakeResolver resolves every package to the same pin
+type fakeResolver struct {
+	pin *pin.Pin
+	err error
+}
+
+func (f *fakeResolver) Resolve(
+	_ context.Context, _ templates.TransportType, _ string, locked *pin.Pin,
+) (*pin.Pin, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if locked != nil && locked.Hash != f.pin.Hash {
+		return nil, fmt.Errorf("%w: %s", pin.ErrHashMismatch, locked.Hash)
+	}
+	return f.pin, nil
+}
+
+// fakeImageManager records the images it builds
+type fakeImageManager struct {
+	existing map[string]bool
+	built    []string
+}
+
+func (f *fakeImageManager) ImageExists(_ context.Context, image string) (bool, error) {
+	return f.existing[image], nil
+}
+
+func (*fakeImageManager) PullImage(_ context.Context, _ string) error {
+	return nil
+}
+
+func (f *fakeImageManager) BuildImage(_ context.Context, _, imageName string) error {
+	f.built = append(f.built, imageName)
+	return nil
+}
+
+func TestBuildFromProtocolSchemePinned(t *testing.T) {
+	t.Parallel()
+
+	resolved := &pin.Pin{Package: "@scope/server", Version: "1.2.3", Hash: "sha512-abc"}
+	lockPath := filepath.Join(t.TempDir(), pin.DefaultLockFile)
+
+	dockerfile, err := BuildFromProtocolScheme(context.Background(), nil, "npx://@scope/server", BuildOptions{
+		DryRun:     true,
+		LockFile:   lockPath,
+		UpdateLock: true,
+		resolver:   &fakeResolver{pin: resolved},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, dockerfile, "@scope/server@1.2.3")
+	assert.Contains(t, dockerfile,
+		`LABEL toolhive-source="npx://@scope/server" toolhive-pin-version="1.2.3" toolhive-pin-hash="sha512-abc"`)
+	// The build verifies the installed package against the pinned hash
+	assert.Contains(t, dockerfile, `"${package%@*}" 'sha512-abc'`)
+
+	lock, err := pin.LoadLockFile(lockPath)
+	require.NoError(t, err)
+	assert.Equal(t, resolved, lock.Get("npx://@scope/server"))
+}
+
+func TestBuildFromProtocolSchemeImageCache(t *testing.T) {
+	t.Parallel()
+
+	opts := BuildOptions{resolver: &fakeResolver{pin: &pin.Pin{Package: "server", Version: "1.0.0", Hash: "sha512-abc"}}}
+
+	imageManager := &fakeImageManager{}
+	imageName, err := BuildFromProtocolScheme(context.Background(), imageManager, "npx://server", opts)
+	require.NoError(t, err)
+	assert.Regexp(t, `^toolhivelocal/npx-server:1\.0\.0-[0-9a-f]{12}$`, imageName)
+	assert.Equal(t, []string{imageName}, imageManager.built)
+
+	// The image of the same pin is reused
+	cached := &fakeImageManager{existing: map[string]bool{imageName: true}}
+	cachedName, err := BuildFromProtocolScheme(context.Background(), cached, "npx://server", opts)
+	require.NoError(t, err)
+	assert.Equal(t, imageName, cachedName)
+	assert.Empty(t, cached.built)
+}
+
+func TestResolvePin(t *testing.T) {
+	t.Parallel()
+
+	resolved := &pin.Pin{Package: "server", Version: "2.0.0", Hash: "sha512-new"}
+	locked := &pin.Pin{Package: "server", Version: "1.0.0", Hash: "sha512-old"}
+	unreachable := &fakeResolver{err: errors.New("connection refused")}
+
+	tests := []struct {
+		name          string
+		transportType templates.TransportType
+		packageName   string
+		opts          BuildOptions
+		locked        *pin.Pin
+		want          *pin.Pin
+		wantErr       bool
+	}{
+		{
+			name:          "resolved",
+			transportType: templates.TransportTypeNPX,
+			packageName:   "server",
+			opts:          BuildOptions{resolver: &fakeResolver{pin: resolved}},
+			want:          resolved,
+		},
+		{
+			name:          "hash mismatch",
+			transportType: templates.TransportTypeNPX,
+			packageName:   "server",
+			opts:          BuildOptions{resolver: &fakeResolver{pin: resolved}},
+			locked:        locked,
+			wantErr:       true,
+		},
+		{
+			name:          "unreachable index uses the lock",
+			transportType: templates.TransportTypeNPX,
+			packageName:   "server",
+			opts:          BuildOptions{resolver: unreachable},
+			locked:        locked,
+			want:          locked,
+		},
+		{
+			name:          "unreachable index builds unpinned",
+			transportType: templates.TransportTypeNPX,
+			packageName:   "server",
+			opts:          BuildOptions{resolver: unreachable},
+		},
+		{
+			name:          "unreachable index fails the lock",
+			transportType: templates.TransportTypeNPX,
+			packageName:   "server",
+			opts:          BuildOptions{resolver: unreachable, UpdateLock: true},
+			wantErr:       true,
+		},
+		{
+			name:          "local go path",
+			transportType: templates.TransportTypeGO,
+			packageName:   "./cmd/server",
+			opts:          BuildOptions{resolver: &fakeResolver{pin: resolved}},
+		},
+		{
+			name:          "local go path cannot be locked",
+			transportType: templates.TransportTypeGO,
+			packageName:   "./cmd/server",
+			opts:          BuildOptions{resolver: &fakeResolver{pin: resolved}, UpdateLock: true},
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := resolvePin(context.Background(), tt.transportType, tt.packageName, tt.opts, tt.locked)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPinnedImageName(t *testing.T) {
+	t.Parallel()
+
+	p := &pin.Pin{Package: "github.com/Example/server", Version: "v0.0.0-20250101000000-abcdef+incompatible"}
+	name := pinnedImageName(templates.TransportTypeGO, p, "FROM scratch")
+	assert.Regexp(t, `^toolhivelocal/go-github-com-example-server:v0\.0\.0-20250101000000-abcdef-incompatible-[0-9a-f]{12}$`, name)
+
+	// A different Dockerfile gives a different image
+	assert.NotEqual(t, name, pinnedImageName(templates.TransportTypeGO, p, "FROM busybox"))
+}
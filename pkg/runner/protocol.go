@@ -2,6 +2,7 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -18,13 +19,49 @@ import (
 
 // Protocol schemes
 const (
-	UVXScheme = "uvx://"
-	NPXScheme = "npx://"
-	GOScheme  = "go://"
+	UVXScheme    = "uvx://"
+	NPXScheme    = "npx://"
+	GOScheme     = "go://"
+	PipxScheme   = "pipx://"
+	CargoScheme  = "cargo://"
+	DotnetScheme = "dotnet://"
+	GitScheme    = "git://"
 )
 
-// HandleProtocolScheme checks if the serverOrImage string contains a protocol scheme (uvx://, npx://, or go://)
-// and builds a Docker image for it if needed.
+// protocolSchemes maps each protocol scheme to its transport type
+var protocolSchemes = []struct {
+	scheme        string
+	transportType templates.TransportType
+}{
+	{UVXScheme, templates.TransportTypeUVX},
+	{NPXScheme, templates.TransportTypeNPX},
+	{GOScheme, templates.TransportTypeGO},
+	{PipxScheme, templates.TransportTypePipx},
+	{CargoScheme, templates.TransportTypeCargo},
+	{DotnetScheme, templates.TransportTypeDotnet},
+	{GitScheme, templates.TransportTypeGit},
+}
+
+// BuildOptions configures the build of a protocol scheme
+type BuildOptions struct {
+	// CACertPath is the path of a CA certificate to install in the image
+	CACertPath string
+	// ImageName is the name of the image. A name is generated when it is empty.
+	ImageName string
+	// DryRun returns the Dockerfile instead of building the image
+	DryRun bool
+	// LockFile is the path of the lockfile which pins packages. Builds follow its pins when
+	// it exists. No lockfile is used when it is empty.
+	LockFile string
+	// UpdateLock resolves the package again and records its pin in the lockfile
+	UpdateLock bool
+
+	// resolver resolves packages to pins; it is replaced in tests
+	resolver packageResolver
+}
+
+// HandleProtocolScheme checks if the serverOrImage string contains a protocol scheme (uvx://, npx://, go://,
+// pipx://, cargo://, dotnet:// or git://) and builds a Docker image for it if needed.
 // Returns the Docker image name to use and any error encountered.
 func HandleProtocolScheme(
 	ctx context.Context,
@@ -35,10 +72,11 @@ func HandleProtocolScheme(
 	return BuildFromProtocolSchemeWithName(ctx, imageManager, serverOrImage, caCertPath, "", false)
 }
 
-// BuildFromProtocolSchemeWithName checks if the serverOrImage string contains a protocol scheme (uvx://, npx://, or go://)
-// and builds a Docker image for it if needed with a custom image name.
+// BuildFromProtocolSchemeWithName checks if the serverOrImage string contains a protocol scheme (uvx://, npx://, go://,
+// pipx://, cargo://, dotnet:// or git://) and builds a Docker image for it if needed with a custom image name.
 // If imageName is empty, a default name will be generated.
 // If dryRun is true, returns the Dockerfile content instead of building the image.
+// No lockfile is used; use BuildFromProtocolScheme to build the versions pinned by a lockfile.
 // Returns the Docker image name (or Dockerfile content if dryRun) and any error encountered.
 func BuildFromProtocolSchemeWithName(
 	ctx context.Context,
@@ -47,39 +85,90 @@ func BuildFromProtocolSchemeWithName(
 	caCertPath string,
 	imageName string,
 	dryRun bool,
+) (string, error) {
+	return BuildFromProtocolScheme(ctx, imageManager, serverOrImage, BuildOptions{
+		CACertPath: caCertPath,
+		ImageName:  imageName,
+		DryRun:     dryRun,
+	})
+}
+
+// BuildFromProtocolScheme builds a Docker image for a protocol scheme. Packages are pinned to
+// exact versions, which are recorded in the labels and tag of the image, and an image built
+// from the same pin is reused instead of building it again.
+// Returns the Docker image name (or Dockerfile content if opts.DryRun) and any error encountered.
+func BuildFromProtocolScheme(
+	ctx context.Context,
+	imageManager images.ImageManager,
+	serverOrImage string,
+	opts BuildOptions,
 ) (string, error) {
 	transportType, packageName, err := ParseProtocolScheme(serverOrImage)
 	if err != nil {
 		return "", err
 	}
 
-	templateData, err := createTemplateData(transportType, packageName, caCertPath)
+	lock, locked, err := loadLock(serverOrImage, opts)
+	if err != nil {
+		return "", err
+	}
+
+	// Git sources are built with the template of the language of the repository
+	if transportType == templates.TransportTypeGit {
+		return buildFromGitSource(ctx, imageManager, serverOrImage, packageName, opts, lock, locked)
+	}
+
+	packagePin, err := resolvePin(ctx, transportType, packageName, opts, locked)
+	if err != nil {
+		return "", err
+	}
+	if packagePin != nil {
+		packageName = packagePin.Spec()
+	}
+
+	templateData, err := createTemplateData(transportType, packageName, opts.CACertPath)
 	if err != nil {
 		return "", err
 	}
+	// The build verifies the installed package against its pinned hash
+	if packagePin != nil {
+		templateData.PackageHash = packagePin.Hash
+	}
+
+	dockerfileContent, err := templates.GetDockerfileTemplate(transportType, templateData)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Dockerfile template: %w", err)
+	}
+	dockerfileContent += pinLabels(serverOrImage, packagePin)
 
 	// If dry-run, just return the Dockerfile content
-	if dryRun {
-		dockerfileContent, err := templates.GetDockerfileTemplate(transportType, templateData)
-		if err != nil {
-			return "", fmt.Errorf("failed to get Dockerfile template: %w", err)
+	if opts.DryRun {
+		return dockerfileContent, saveLock(lock, serverOrImage, packagePin, opts)
+	}
+
+	imageName := opts.ImageName
+	if imageName == "" && packagePin != nil {
+		imageName = pinnedImageName(transportType, packagePin, dockerfileContent)
+		if cached, err := imageManager.ImageExists(ctx, imageName); err == nil && cached {
+			logger.Infof("Using cached image %s for %s", imageName, packagePin.Spec())
+			return imageName, saveLock(lock, serverOrImage, packagePin, opts)
 		}
-		return dockerfileContent, nil
 	}
 
-	return buildImageFromTemplateWithName(ctx, imageManager, transportType, packageName, templateData, imageName)
+	imageName, err = buildImageFromTemplateWithName(
+		ctx, imageManager, transportType, packageName, templateData, dockerfileContent, imageName)
+	if err != nil {
+		return "", err
+	}
+	return imageName, saveLock(lock, serverOrImage, packagePin, opts)
 }
 
 // ParseProtocolScheme extracts the transport type and package name from the protocol scheme.
 func ParseProtocolScheme(serverOrImage string) (templates.TransportType, string, error) {
-	if strings.HasPrefix(serverOrImage, UVXScheme) {
-		return templates.TransportTypeUVX, strings.TrimPrefix(serverOrImage, UVXScheme), nil
-	}
-	if strings.HasPrefix(serverOrImage, NPXScheme) {
-		return templates.TransportTypeNPX, strings.TrimPrefix(serverOrImage, NPXScheme), nil
-	}
-	if strings.HasPrefix(serverOrImage, GOScheme) {
-		return templates.TransportTypeGO, strings.TrimPrefix(serverOrImage, GOScheme), nil
+	for _, ps := range protocolSchemes {
+		if strings.HasPrefix(serverOrImage, ps.scheme) {
+			return ps.transportType, strings.TrimPrefix(serverOrImage, ps.scheme), nil
+		}
 	}
 	return "", "", fmt.Errorf("unsupported protocol scheme: %s", serverOrImage)
 }
@@ -131,6 +220,9 @@ type buildContext struct {
 	Dir            string
 	DockerfilePath string
 	CleanupFunc    func()
+	// UserOwned is set when the directory belongs to the user, so that an existing
+	// Dockerfile is used and generated files are removed after the build.
+	UserOwned bool
 }
 
 // setupBuildContext sets up the appropriate build context directory based on whether
@@ -168,6 +260,7 @@ func setupLocalBuildContext(packageName string) (*buildContext, error) {
 	return &buildContext{
 		Dir:            currentDir,
 		DockerfilePath: dockerfilePath,
+		UserOwned:      true,
 		CleanupFunc: func() {
 			// Clean up the temporary Dockerfile only if we created it
 			if _, err := os.Stat(dockerfilePath); err == nil {
@@ -221,7 +314,7 @@ func writeDockerfile(dockerfilePath, dockerfileContent string, isLocalPath bool)
 	// Add a comment marker to identify our generated Dockerfile
 	markedContent := "# Generated by ToolHive - temporary file\n" + dockerfileContent
 
-	if err := os.WriteFile(dockerfilePath, []byte(markedContent), 0600); err != nil {
+	if err := writeNewFile(dockerfilePath, []byte(markedContent)); err != nil {
 		return fmt.Errorf("failed to write Dockerfile: %w", err)
 	}
 
@@ -239,7 +332,7 @@ func writeCACertificate(buildContextDir, caCertContent string, isLocalPath bool)
 	}
 
 	caCertFilePath := filepath.Join(buildContextDir, "ca-cert.crt")
-	if err := os.WriteFile(caCertFilePath, []byte(caCertContent), 0600); err != nil {
+	if err := writeNewFile(caCertFilePath, []byte(caCertContent)); err != nil {
 		return nil, fmt.Errorf("failed to write CA certificate file: %w", err)
 	}
 
@@ -261,6 +354,26 @@ func writeCACertificate(buildContextDir, caCertContent string, isLocalPath bool)
 	return cleanupFunc, nil
 }
 
+// writeNewFile replaces the file at path with a new file. An existing file or symlink is removed
+// rather than written through, since build contexts such as git clones are not trusted and may
+// contain symlinks to files outside of them.
+func writeNewFile(path string, data []byte) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	// O_EXCL fails if the path exists, including as a symlink
+	// #nosec G304 -- the path is within the build context
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 // generateImageName generates a unique Docker image name based on the package and transport type.
 func generateImageName(transportType templates.TransportType, packageName string) string {
 	tag := time.Now().Format("20060102150405")
@@ -270,7 +383,7 @@ func generateImageName(transportType templates.TransportType, packageName string
 		tag))
 }
 
-// buildImageFromTemplateWithName builds a Docker image from the template data with a custom image name.
+// buildImageFromTemplateWithName builds a Docker image from the Dockerfile of the template data with a custom image name.
 // If imageName is empty, a default name will be generated.
 func buildImageFromTemplateWithName(
 	ctx context.Context,
@@ -278,15 +391,9 @@ func buildImageFromTemplateWithName(
 	transportType templates.TransportType,
 	packageName string,
 	templateData templates.TemplateData,
+	dockerfileContent string,
 	imageName string,
 ) (string, error) {
-
-	// Get the Dockerfile content
-	dockerfileContent, err := templates.GetDockerfileTemplate(transportType, templateData)
-	if err != nil {
-		return "", fmt.Errorf("failed to get Dockerfile template: %w", err)
-	}
-
 	// Set up the build context
 	buildCtx, err := setupBuildContext(packageName, templateData.IsLocalPath)
 	if err != nil {
@@ -294,13 +401,29 @@ func buildImageFromTemplateWithName(
 	}
 	defer buildCtx.CleanupFunc()
 
+	return buildImageInContext(
+		ctx, imageManager, buildCtx, transportType, packageName, dockerfileContent, templateData.CACertContent, imageName)
+}
+
+// buildImageInContext writes the Dockerfile to the build context and builds the image.
+// If imageName is empty, a default name will be generated.
+func buildImageInContext(
+	ctx context.Context,
+	imageManager images.ImageManager,
+	buildCtx *buildContext,
+	transportType templates.TransportType,
+	packageName string,
+	dockerfileContent string,
+	caCertContent string,
+	imageName string,
+) (string, error) {
 	// Write the Dockerfile
-	if err := writeDockerfile(buildCtx.DockerfilePath, dockerfileContent, templateData.IsLocalPath); err != nil {
+	if err := writeDockerfile(buildCtx.DockerfilePath, dockerfileContent, buildCtx.UserOwned); err != nil {
 		return "", err
 	}
 
 	// Write CA certificate if provided
-	caCertCleanup, err := writeCACertificate(buildCtx.Dir, templateData.CACertContent, templateData.IsLocalPath)
+	caCertCleanup, err := writeCACertificate(buildCtx.Dir, caCertContent, buildCtx.UserOwned)
 	if err != nil {
 		return "", err
 	}
@@ -349,6 +472,8 @@ func PackageNameToImageName(packageName string) string {
 	imageName = strings.ReplaceAll(imageName, "/", "-")
 	imageName = strings.ReplaceAll(imageName, "@", "-")
 	imageName = strings.ReplaceAll(imageName, ".", "-")
+	imageName = strings.ReplaceAll(imageName, "#", "-")
+	imageName = strings.ReplaceAll(imageName, ":", "-")
 
 	// Ensure the name doesn't start with a dash
 	imageName = strings.TrimPrefix(imageName, "-")
@@ -367,9 +492,13 @@ func isLocalGoPath(path string) bool {
 	return strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../") || strings.HasPrefix(path, "/") || path == "."
 }
 
-// IsImageProtocolScheme checks if the serverOrImage string contains a protocol scheme (uvx://, npx://, or go://)
+// IsImageProtocolScheme checks if the serverOrImage string contains a protocol scheme
+// (uvx://, npx://, go://, pipx://, cargo://, dotnet:// or git://)
 func IsImageProtocolScheme(serverOrImage string) bool {
-	return strings.HasPrefix(serverOrImage, UVXScheme) ||
-		strings.HasPrefix(serverOrImage, NPXScheme) ||
-		strings.HasPrefix(serverOrImage, GOScheme)
+	for _, ps := range protocolSchemes {
+		if strings.HasPrefix(serverOrImage, ps.scheme) {
+			return true
+		}
+	}
+	return false
 }
@@ -108,6 +108,11 @@ func TestPackageNameToImageName(t *testing.T) {
 			input:    "./cmd/my.server/main",
 			expected: "cmd-my-server-main",
 		},
+		{
+			name:     "git source with subdirectory",
+			input:    "github.com/user/repo@main#servers/fetch",
+			expected: "github-com-user-repo-main-servers-fetch",
+		},
 	}
 
 	for _, tt := range tests {
@@ -148,6 +153,26 @@ func TestIsImageProtocolScheme(t *testing.T) {
 			input:    "go://./cmd/server",
 			expected: true,
 		},
+		{
+			name:     "pipx scheme",
+			input:    "pipx://package-name",
+			expected: true,
+		},
+		{
+			name:     "cargo scheme",
+			input:    "cargo://crate-name@1.0.0",
+			expected: true,
+		},
+		{
+			name:     "dotnet scheme",
+			input:    "dotnet://Tool.Name",
+			expected: true,
+		},
+		{
+			name:     "git scheme",
+			input:    "git://github.com/example/server@v1.0.0#packages/server",
+			expected: true,
+		},
 		{
 			name:     "regular image name",
 			input:    "docker.io/library/alpine:latest",
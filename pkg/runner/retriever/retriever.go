@@ -7,15 +7,23 @@ import (
 	"fmt"
 
 	nameref "github.com/google/go-containerregistry/pkg/name"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/stacklok/toolhive/pkg/config"
 	"github.com/stacklok/toolhive/pkg/container/images"
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/container/sbom"
 	"github.com/stacklok/toolhive/pkg/container/verifier"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/registry"
 	"github.com/stacklok/toolhive/pkg/runner"
+	"github.com/stacklok/toolhive/pkg/telemetry"
 )
 
+// tracerName is the name of the tracer of MCP server retrievals.
+const tracerName = "github.com/stacklok/toolhive/pkg/runner/retriever"
+
 const (
 	// VerifyImageWarn prints a warning when image validation fails.
 	VerifyImageWarn = "warn"
@@ -34,25 +42,54 @@ var (
 	ErrInvalidRunConfig = errors.New("invalid run configuration provided")
 )
 
+// Options configures the retrieval of an MCP server.
+type Options struct {
+	// CACertPath is the path of a CA certificate to install in images built from protocol schemes.
+	// The CA certificate of the configuration is used when it is empty.
+	CACertPath string
+	// VerifyImage is the image verification setting: VerifyImageWarn, VerifyImageEnabled or VerifyImageDisabled.
+	VerifyImage string
+	// GroupName is the registry group in which the server is looked up.
+	GroupName string
+	// LockFile is the path of the lockfile which pins the packages of protocol schemes.
+	// No lockfile is used when it is empty.
+	LockFile string
+	// WorkloadName is the name of the workload the server is retrieved for. The SBOM of
+	// its image is saved under this name when it is set.
+	WorkloadName string
+}
+
 // Retriever is a function that retrieves the MCP server definition from the registry.
-type Retriever func(context.Context, string, string, string, string) (string, registry.ServerMetadata, error)
+type Retriever func(context.Context, string, Options) (string, registry.ServerMetadata, error)
 
 // GetMCPServer retrieves the MCP server definition from the registry.
 func GetMCPServer(
 	ctx context.Context,
 	serverOrImage string,
-	rawCACertPath string,
-	verificationType string,
-	groupName string,
-) (string, registry.ServerMetadata, error) {
+	opts Options,
+) (_ string, _ registry.ServerMetadata, retErr error) {
+	ctx, span := telemetry.StartSpan(ctx, tracerName, "retriever.GetMCPServer",
+		attribute.String("mcp.server", serverOrImage),
+		attribute.String("image.verification", opts.VerifyImage),
+	)
+	defer func() {
+		telemetry.EndSpan(span, retErr)
+	}()
+
 	var imageMetadata *registry.ImageMetadata
 	var imageToUse string
 
+	// The process runtime runs protocol schemes directly on the host, so there is nothing to build or pull
+	if runtime.IsProcessRuntime() && runner.IsImageProtocolScheme(serverOrImage) {
+		logger.Debugf("Using the process runtime, running %s without an image", serverOrImage)
+		return serverOrImage, nil, nil
+	}
+
 	imageManager := images.NewImageManager(ctx)
-	// Check if the serverOrImage is a protocol scheme, e.g., uvx://, npx://, or go://
+	// Check if the serverOrImage is a protocol scheme, e.g., uvx://, npx://, go:// or git://
 	if runner.IsImageProtocolScheme(serverOrImage) {
 		var err error
-		imageToUse, imageMetadata, err = handleProtocolScheme(ctx, serverOrImage, rawCACertPath, imageManager)
+		imageToUse, imageMetadata, err = handleProtocolScheme(ctx, serverOrImage, opts, imageManager)
 		if err != nil {
 			return "", nil, err
 		}
@@ -60,10 +97,10 @@ func GetMCPServer(
 		logger.Debugf("No protocol scheme detected, using image: %s", serverOrImage)
 
 		// If group name is provided, look up server in the group first
-		if groupName != "" {
+		if opts.GroupName != "" {
 			var err error
 			var server registry.ServerMetadata
-			imageToUse, imageMetadata, server, err = handleGroupLookup(ctx, serverOrImage, groupName)
+			imageToUse, imageMetadata, server, err = handleGroupLookup(ctx, serverOrImage, opts.GroupName)
 			if err != nil {
 				return "", nil, err
 			}
@@ -85,13 +122,31 @@ func GetMCPServer(
 		}
 	}
 
-	// Verify the image against the expected provenance info (if applicable)
-	if err := verifyImage(imageToUse, imageMetadata, verificationType); err != nil {
-		return "", nil, err
+	if runtime.IsProcessRuntime() {
+		return "", nil, fmt.Errorf("the process runtime cannot run the container image %s; "+
+			"use a uvx://, npx:// or go:// server instead", imageToUse)
+	}
+
+	span.SetAttributes(attribute.String("image", imageToUse))
+
+	// Verify the image against the expected provenance info (if applicable). Images of protocol
+	// schemes are built locally, so they have no signatures or provenance to verify.
+	if runner.IsImageProtocolScheme(serverOrImage) {
+		logger.Infof("Skipping verification of %s, which is built locally from %s", imageToUse, serverOrImage)
+	} else {
+		_, verifySpan := telemetry.StartSpan(ctx, tracerName, "retriever.VerifyImage", attribute.String("image", imageToUse))
+		err := verifyImage(imageToUse, imageMetadata, opts.VerifyImage)
+		telemetry.EndSpan(verifySpan, err)
+		if err != nil {
+			return "", nil, err
+		}
 	}
 
 	// Pull the image if necessary
-	if err := pullImage(ctx, imageToUse, imageManager); err != nil {
+	pullCtx, pullSpan := telemetry.StartSpan(ctx, tracerName, "retriever.PullImage", attribute.String("image", imageToUse))
+	err := pullImage(pullCtx, imageToUse, imageManager)
+	telemetry.EndSpan(pullSpan, err)
+	if err != nil {
 		// Check if the error is due to context cancellation/timeout
 		if ctx.Err() == context.DeadlineExceeded {
 			return "", nil, fmt.Errorf("image pull timed out - the image may be too large or the connection too slow")
@@ -102,14 +157,140 @@ func GetMCPServer(
 		return "", nil, fmt.Errorf("failed to retrieve or pull image: %v", err)
 	}
 
+	// Catalog the packages of the image, and check them against the vulnerability policy.
+	// Images are not available locally in Kubernetes.
+	if !runtime.IsKubernetesRuntime() {
+		policy := config.NewDefaultProvider().GetConfig().VulnerabilityPolicy
+		_, sbomSpan := telemetry.StartSpan(ctx, tracerName, "retriever.CheckVulnerabilities",
+			attribute.String("image", imageToUse))
+		err = checkVulnerabilities(ctx, imageManager, &policy, imageToUse, opts.WorkloadName)
+		telemetry.EndSpan(sbomSpan, err)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	pruneImages(ctx, imageManager, imageToUse)
+
 	return imageToUse, imageMetadata, nil
 }
 
+// VerifyServiceImages checks the images of the service containers of a workload like the image
+// of its MCP server: against the image verification setting of opts and the verification policy,
+// and, outside of Kubernetes, against the vulnerability policy. The images are pulled if necessary.
+func VerifyServiceImages(ctx context.Context, services []runtime.ServiceContainer, opts Options) error {
+	if len(services) == 0 {
+		return nil
+	}
+	verificationPolicy, err := loadVerificationPolicy()
+	if err != nil {
+		return err
+	}
+	vulnerabilityPolicy := config.NewDefaultProvider().GetConfig().VulnerabilityPolicy
+
+	// Images are not available locally in Kubernetes
+	var imageManager images.ImageManager
+	if !runtime.IsKubernetesRuntime() {
+		imageManager = images.NewImageManager(ctx)
+	}
+	return verifyServiceImages(ctx, imageManager, services, opts.VerifyImage, verificationPolicy, &vulnerabilityPolicy)
+}
+
+// verifyServiceImages checks the images of service containers, see VerifyServiceImages.
+// The vulnerability policy is only checked if imageManager is set.
+func verifyServiceImages(
+	ctx context.Context,
+	imageManager images.ImageManager,
+	services []runtime.ServiceContainer,
+	verifySetting string,
+	verificationPolicy *verifier.Policy,
+	vulnerabilityPolicy *sbom.Policy,
+) error {
+	for _, service := range services {
+		if err := verifyImageWithPolicy(service.Image, nil, verifySetting, verificationPolicy); err != nil {
+			return fmt.Errorf("service %s: %w", service.Name, err)
+		}
+		if imageManager == nil {
+			continue
+		}
+		if err := pullImage(ctx, service.Image, imageManager); err != nil {
+			return fmt.Errorf("failed to pull image of service %s: %w", service.Name, err)
+		}
+		if err := checkVulnerabilities(ctx, imageManager, vulnerabilityPolicy, service.Image, ""); err != nil {
+			return fmt.Errorf("service %s: %w", service.Name, err)
+		}
+	}
+	return nil
+}
+
+// checkVulnerabilities generates the SBOM of an image and stores it with the state of the workload,
+// if any. Images which violate the vulnerability policy are refused.
+func checkVulnerabilities(
+	ctx context.Context,
+	imageManager images.ImageManager,
+	policy *sbom.Policy,
+	image string,
+	workloadName string,
+) error {
+	doc, err := sbom.GenerateForImage(ctx, imageManager, image)
+	if err != nil {
+		if policy.Enabled() {
+			return fmt.Errorf("failed to generate SBOM of %s for the vulnerability policy: %w", image, err)
+		}
+		logger.Warnf("Failed to generate SBOM of %s: %v", image, err)
+		return nil
+	}
+	logger.Debugf("Found %d packages in %s", len(doc.Components), image)
+
+	if workloadName != "" {
+		if err := sbom.Save(ctx, workloadName, doc); err != nil {
+			logger.Warnf("Failed to save SBOM of %s: %v", image, err)
+		}
+	}
+
+	if !policy.Enabled() {
+		return nil
+	}
+	findings, err := policy.Evaluate(doc)
+	if err != nil {
+		return fmt.Errorf("refusing to run %s: %w", image, err)
+	}
+	if len(findings) > 0 {
+		logger.Warnf("%s has %d vulnerabilities tolerated by the vulnerability policy; "+
+			"run 'thv inspect sbom %s --vulnerabilities' to list them", image, len(findings), workloadName)
+	}
+	return nil
+}
+
+// pruneImages prunes the images built or pulled by ToolHive when the automatic prune policy is enabled.
+// The image about to run and the images of saved run configurations are kept. Failures are only logged.
+func pruneImages(ctx context.Context, imageManager images.ImageManager, imageToUse string) {
+	policy := config.NewDefaultProvider().GetConfig().ImagePrune
+	lister, ok := imageManager.(images.ImageLister)
+	if !policy.Enabled || !ok {
+		return
+	}
+
+	referenced, err := runner.ReferencedImages(ctx)
+	if err != nil {
+		logger.Warnf("Skipping image pruning: %v", err)
+		return
+	}
+	removed, err := images.Prune(ctx, lister, policy.Options(append(referenced, imageToUse)))
+	if err != nil {
+		logger.Warnf("Failed to prune images: %v", err)
+		return
+	}
+	for _, image := range removed {
+		logger.Debugf("Pruned image %s", image.ID)
+	}
+}
+
 // handleProtocolScheme handles the protocol scheme case
 func handleProtocolScheme(
 	ctx context.Context,
 	serverOrImage string,
-	rawCACertPath string,
+	opts Options,
 	imageManager images.ImageManager,
 ) (string, *registry.ImageMetadata, error) {
 	var imageMetadata *registry.ImageMetadata
@@ -117,8 +298,13 @@ func handleProtocolScheme(
 
 	logger.Debugf("Detected protocol scheme: %s", serverOrImage)
 	// Process the protocol scheme and build the image
-	caCertPath := resolveCACertPath(rawCACertPath)
-	generatedImage, err := runner.HandleProtocolScheme(ctx, imageManager, serverOrImage, caCertPath)
+	buildCtx, buildSpan := telemetry.StartSpan(ctx, tracerName, "retriever.BuildImage",
+		attribute.String("mcp.server", serverOrImage))
+	generatedImage, err := runner.BuildFromProtocolScheme(buildCtx, imageManager, serverOrImage, runner.BuildOptions{
+		CACertPath: resolveCACertPath(opts.CACertPath),
+		LockFile:   opts.LockFile,
+	})
+	telemetry.EndSpan(buildSpan, err)
 	if err != nil {
 		return "", nil, errors.Join(ErrBadProtocolScheme, err)
 	}
@@ -251,6 +437,7 @@ func pullImage(ctx context.Context, image string, imageManager images.ImageManag
 
 			// Pull failed, check if it exists locally
 			logger.Infof("Pull failed, checking if image exists locally: %s", image)
+			trace.SpanFromContext(ctx).RecordError(err)
 			imageExists, checkErr := imageManager.ImageExists(ctx, image)
 			if checkErr != nil {
 				return fmt.Errorf("failed to check if image exists: %v", checkErr)
@@ -315,12 +502,46 @@ func resolveCACertPath(flagValue string) string {
 	return ""
 }
 
-// verifyImage verifies the image using the specified verification setting (warn, enabled, or disabled)
+// verifyImage verifies the image using the specified verification setting (warn, enabled, or disabled).
+// The rule of the verification policy for the image applies in addition to the provenance information
+// of the registry, and the policy may require all images to be verified.
 func verifyImage(image string, server *registry.ImageMetadata, verifySetting string) error {
+	policy, err := loadVerificationPolicy()
+	if err != nil {
+		return err
+	}
+	return verifyImageWithPolicy(image, server, verifySetting, policy)
+}
+
+// verifyImageWithPolicy verifies the image using the specified verification setting and verification policy
+func verifyImageWithPolicy(image string, server *registry.ImageMetadata, verifySetting string, policy *verifier.Policy) error {
+	if policy.RequireVerified {
+		if verifySetting == VerifyImageDisabled {
+			return fmt.Errorf("image verification cannot be disabled: the verification policy requires verified images")
+		}
+		verifySetting = VerifyImageEnabled
+	}
+
 	switch verifySetting {
 	case VerifyImageDisabled:
 		logger.Warn("Image verification is disabled")
 	case VerifyImageWarn, VerifyImageEnabled:
+		hasProvenance := server != nil && server.Provenance != nil
+
+		// Verify the image against the rule of the policy that matches it, if any
+		if rule := policy.Match(image); rule != nil {
+			if err := verifyImagePolicy(image, rule, verifySetting); err != nil {
+				return err
+			}
+			if !hasProvenance {
+				return nil
+			}
+		} else if !hasProvenance && policy.RequireVerified {
+			// Without a rule or provenance information, there is no trusted signer to verify the image against
+			return fmt.Errorf("MCP server %s cannot be verified: the verification policy requires verified images, "+
+				"but neither a rule of the policy nor the registry configures its trusted signers", image)
+		}
+
 		// Create a new verifier
 		v, err := verifier.New(server)
 		if err != nil {
@@ -353,6 +574,34 @@ func verifyImage(image string, server *registry.ImageMetadata, verifySetting str
 	return nil
 }
 
+// loadVerificationPolicy loads the verification policy of the configuration, if any
+func loadVerificationPolicy() (*verifier.Policy, error) {
+	cfg := config.NewDefaultProvider().GetConfig()
+	if cfg.VerificationPolicyPath == "" {
+		return &verifier.Policy{}, nil
+	}
+	return verifier.LoadPolicy(cfg.VerificationPolicyPath)
+}
+
+// verifyImagePolicy verifies the image against a rule of the verification policy
+func verifyImagePolicy(image string, rule *verifier.ImagePolicy, verifySetting string) error {
+	v, err := verifier.NewForPolicy(rule)
+	if err != nil {
+		return err
+	}
+
+	err = v.VerifyPolicy(image, rule)
+	switch {
+	case errors.Is(err, verifier.ErrImageNotVerified) && verifySetting == VerifyImageWarn:
+		logger.Warnf("MCP server %s failed image verification: %v", image, err)
+	case err != nil:
+		return fmt.Errorf("MCP server %s failed image verification: %w", image, err)
+	default:
+		logger.Infof("MCP server %s is verified by the verification policy", image)
+	}
+	return nil
+}
+
 // hasLatestTag checks if the given image reference has the "latest" tag or no tag (which defaults to "latest")
 func hasLatestTag(imageRef string) bool {
 	ref, err := nameref.ParseReference(imageRef)
@@ -7,6 +7,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/stacklok/toolhive/pkg/container/images"
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/container/sbom"
+	"github.com/stacklok/toolhive/pkg/container/verifier"
 	"github.com/stacklok/toolhive/pkg/registry"
 )
 
@@ -88,13 +92,10 @@ func TestGetMCPServer_WithGroup(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			imageURL, serverMetadata, err := GetMCPServer(
-				ctx,
-				tt.serverName,
-				"",
-				VerifyImageDisabled,
-				tt.groupName,
-			)
+			imageURL, serverMetadata, err := GetMCPServer(ctx, tt.serverName, Options{
+				VerifyImage: VerifyImageDisabled,
+				GroupName:   tt.groupName,
+			})
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -121,13 +122,8 @@ func TestGetMCPServer_WithoutGroup(t *testing.T) {
 	ctx := context.Background()
 
 	// Test that passing empty group name still works (normal behavior)
-	imageURL, serverMetadata, err := GetMCPServer(
-		ctx,
-		"osv",               // Use a known server from the registry
-		"",                  // rawCACertPath
-		VerifyImageDisabled, // verificationType
-		"",                  // empty groupName should use normal registry lookup
-	)
+	// An empty group name should use the normal registry lookup of a known server
+	imageURL, serverMetadata, err := GetMCPServer(ctx, "osv", Options{VerifyImage: VerifyImageDisabled})
 
 	// This should work as it's the normal flow
 	assert.NoError(t, err)
@@ -215,3 +211,129 @@ func TestHasLatestTag(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckVulnerabilities_WithoutSBOM(t *testing.T) {
+	t.Parallel()
+
+	// The no-op image manager cannot export images, so no SBOM can be generated
+	imageManager := &images.NoopImageManager{}
+
+	tests := []struct {
+		name      string
+		policy    *sbom.Policy
+		expectErr bool
+	}{
+		{
+			name:      "disabled policy tolerates a missing SBOM",
+			policy:    &sbom.Policy{},
+			expectErr: false,
+		},
+		{
+			name:      "enabled policy refuses the image",
+			policy:    &sbom.Policy{Database: "/nonexistent/vulnerabilities.json"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkVulnerabilities(context.Background(), imageManager, tt.policy, "ghcr.io/example/server:1.0.0", "")
+			if tt.expectErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, sbom.ErrUnsupported)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestVerifyImageWithPolicy_RequireVerified(t *testing.T) {
+	t.Parallel()
+
+	policy := &verifier.Policy{RequireVerified: true}
+
+	tests := []struct {
+		name          string
+		verifySetting string
+		expectedErr   string
+	}{
+		{
+			name:          "image without trusted signers is refused",
+			verifySetting: VerifyImageWarn,
+			expectedErr:   "neither a rule of the policy nor the registry configures its trusted signers",
+		},
+		{
+			name:          "verification cannot be disabled",
+			verifySetting: VerifyImageDisabled,
+			expectedErr:   "image verification cannot be disabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := verifyImageWithPolicy("ghcr.io/example/server:1.0.0", nil, tt.verifySetting, policy)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestVerifyServiceImages(t *testing.T) {
+	t.Parallel()
+
+	services := []runtime.ServiceContainer{{Name: "db", Image: "docker.io/library/postgres:16"}}
+
+	tests := []struct {
+		name                string
+		imageManager        images.ImageManager
+		verificationPolicy  *verifier.Policy
+		vulnerabilityPolicy *sbom.Policy
+		expectedErr         string
+	}{
+		{
+			name:                "policies allow the service image",
+			imageManager:        &images.NoopImageManager{},
+			verificationPolicy:  &verifier.Policy{},
+			vulnerabilityPolicy: &sbom.Policy{},
+		},
+		{
+			name:                "verification policy refuses the service image",
+			imageManager:        &images.NoopImageManager{},
+			verificationPolicy:  &verifier.Policy{RequireVerified: true},
+			vulnerabilityPolicy: &sbom.Policy{},
+			expectedErr:         "service db",
+		},
+		{
+			name:                "vulnerability policy refuses the service image",
+			imageManager:        &images.NoopImageManager{},
+			verificationPolicy:  &verifier.Policy{},
+			vulnerabilityPolicy: &sbom.Policy{Database: "/nonexistent/vulnerabilities.json"},
+			expectedErr:         "service db",
+		},
+		{
+			name:                "vulnerability policy is not checked without local images",
+			verificationPolicy:  &verifier.Policy{},
+			vulnerabilityPolicy: &sbom.Policy{Database: "/nonexistent/vulnerabilities.json"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := verifyServiceImages(context.Background(), tt.imageManager, services, VerifyImageDisabled,
+				tt.verificationPolicy, tt.vulnerabilityPolicy)
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
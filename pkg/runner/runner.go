@@ -4,12 +4,14 @@ package runner
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/oauth2"
 
 	"github.com/stacklok/toolhive/pkg/client"
@@ -25,6 +27,9 @@ import (
 	"github.com/stacklok/toolhive/pkg/workloads/statuses"
 )
 
+// tracerName is the name of the tracer of MCP server startups.
+const tracerName = "github.com/stacklok/toolhive/pkg/runner"
+
 // Runner is responsible for running an MCP server with the provided configuration
 type Runner struct {
 	// Config is the configuration for the runner
@@ -49,6 +54,9 @@ type Runner struct {
 	prometheusHandler http.Handler
 
 	statusManager statuses.StatusManager
+
+	// learner records the accesses of the workload in permission learning mode
+	learner *permissionLearner
 }
 
 // NewRunner creates a new Runner with the provided configuration
@@ -89,7 +97,19 @@ func (c *RunConfig) GetPort() int {
 // Run runs the MCP server with the provided configuration
 //
 //nolint:gocyclo // This function is complex but manageable
-func (r *Runner) Run(ctx context.Context) error {
+func (r *Runner) Run(ctx context.Context) (retErr error) {
+	// Trace the startup of the MCP server; the span ends once the workload is running
+	ctx, span := telemetry.StartSpan(ctx, tracerName, "runner.Run",
+		attribute.String("workload.name", r.Config.ContainerName),
+		attribute.String("workload.transport", r.Config.Transport.String()),
+	)
+	startupDone := false
+	defer func() {
+		if !startupDone {
+			telemetry.EndSpan(span, retErr)
+		}
+	}()
+
 	// Check if middleware configs are already populated (new direct configuration)
 	// If not, use backwards compatibility to populate from old config fields
 	if len(r.Config.MiddlewareConfigs) == 0 {
@@ -158,6 +178,7 @@ func (r *Runner) Run(ctx context.Context) error {
 		if _, err = r.Config.WithSecrets(ctx, secretManager); err != nil {
 			return err
 		}
+		r.recordSecretVersions(ctx, secretManager)
 	}
 
 	// Set up the transport
@@ -184,22 +205,40 @@ func (r *Runner) Run(ctx context.Context) error {
 		r.Config.Deployer = nil
 	}
 
-	if err := transportHandler.Setup(
-		ctx, r.Config.Deployer, r.Config.ContainerName, r.Config.Image, r.Config.CmdArgs,
+	// Container output produced from here on is collected into the workload log files
+	logsSince := time.Now()
+
+	// Record the file accesses from before the container starts, and write the suggested
+	// permission profile once the workload stops
+	r.learner = r.startPermissionLearning()
+	defer r.learner.finish()
+
+	setupCtx, setupSpan := telemetry.StartSpan(ctx, tracerName, "runner.SetupTransport")
+	err = transportHandler.Setup(
+		setupCtx, r.Config.Deployer, r.Config.ContainerName, r.Config.Image, r.Config.CmdArgs,
 		r.Config.EnvVars, r.Config.ContainerLabels, r.Config.PermissionProfile, r.Config.K8sPodTemplatePatch,
 		r.Config.IsolateNetwork, r.Config.IgnoreConfig,
-	); err != nil {
+		types.SetupOptions{SecretFiles: r.Config.SecretFiles, Services: r.Config.Services},
+	)
+	telemetry.EndSpan(setupSpan, err)
+	if err != nil {
 		return fmt.Errorf("failed to set up transport: %v", err)
 	}
 
 	// Start the transport (which also starts the container and monitoring)
 	logger.Infof("Starting %s transport for %s...", r.Config.Transport, r.Config.ContainerName)
-	if err := transportHandler.Start(ctx); err != nil {
+	startCtx, startSpan := telemetry.StartSpan(ctx, tracerName, "runner.StartTransport")
+	err = transportHandler.Start(startCtx)
+	telemetry.EndSpan(startSpan, err)
+	if err != nil {
 		return fmt.Errorf("failed to start transport: %v", err)
 	}
 
 	logger.Infof("MCP server %s started successfully", r.Config.ContainerName)
 
+	r.startLogCollection(ctx, logsSince)
+	r.startEgressMonitoring(ctx, logsSince)
+
 	// Update client configurations with the MCP server URL.
 	// Note that this function checks the configuration to determine which
 	// clients should be updated, if any.
@@ -305,6 +344,8 @@ func (r *Runner) Run(ctx context.Context) error {
 		// If we can't set the status to `running` - treat it as a fatal error.
 		return fmt.Errorf("failed to set workload status: %v", err)
 	}
+	telemetry.EndSpan(span, nil)
+	startupDone = true
 
 	// Wait for either a signal or the done channel to be closed
 	select {
@@ -327,6 +368,42 @@ func (r *Runner) Run(ctx context.Context) error {
 	return nil
 }
 
+// recordSecretVersions saves the versions of the secrets the workload is started with, so
+// that workloads started with a previous version of a rotated secret can be found.
+// The configuration is reloaded from the state store to avoid saving resolved secrets.
+func (r *Runner) recordSecretVersions(ctx context.Context, secretManager secrets.Provider) {
+	versions := make(map[string]string)
+	for _, secret := range r.Config.Secrets {
+		param, err := secrets.ParseSecretParameter(secret)
+		if err != nil {
+			continue
+		}
+		version, err := secrets.GetSecretVersion(ctx, secretManager, param.Name)
+		if err != nil {
+			logger.Warnf("Failed to get version of secret %s: %v", param.Name, err)
+			continue
+		}
+		if version != "" {
+			versions[param.Name] = version
+		}
+	}
+
+	if maps.Equal(versions, r.Config.SecretVersions) {
+		return
+	}
+	r.Config.SecretVersions = versions
+
+	savedConfig, err := LoadState(ctx, r.Config.BaseName)
+	if err != nil {
+		logger.Warnf("Failed to load state to record secret versions: %v", err)
+		return
+	}
+	savedConfig.SecretVersions = versions
+	if err := savedConfig.SaveState(ctx); err != nil {
+		logger.Warnf("Failed to record secret versions: %v", err)
+	}
+}
+
 // handleRemoteAuthentication handles authentication for remote MCP servers
 func (r *Runner) handleRemoteAuthentication(ctx context.Context) (oauth2.TokenSource, error) {
 	if r.Config.RemoteAuthConfig == nil {
@@ -0,0 +1,201 @@
+package secrets
+
+import (
+	"context"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/stacklok/toolhive/pkg/secrets/aes"
+)
+
+const (
+	// bundleVersion is the version of the format of secrets bundles.
+	bundleVersion = 1
+	// bundleKDF is the key derivation function used to derive the key of bundles from their passphrase.
+	bundleKDF = "pbkdf2-sha256"
+	// bundleIterations is the number of PBKDF2 iterations used for new bundles.
+	bundleIterations = 600000
+	// bundleMaxIterations bounds the work done to decrypt a bundle from an untrusted source.
+	bundleMaxIterations = 10 * bundleIterations
+	// bundleSaltSize is the size of the random salt of new bundles in bytes.
+	bundleSaltSize = 16
+)
+
+// ErrInvalidBundlePassphrase is returned when a secrets bundle cannot be decrypted with the passphrase.
+var ErrInvalidBundlePassphrase = errors.New("invalid passphrase or corrupted secrets bundle")
+
+// bundleFile is the structure of a secrets bundle. The secrets are encrypted with AES-256-GCM
+// using a key derived from a passphrase, so that they can be moved to another machine.
+type bundleFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// TransferResult describes the outcome of importing or migrating secrets.
+type TransferResult struct {
+	// Imported are the names of the secrets which were written to the provider
+	Imported []string
+	// Skipped are the names of the secrets which already existed in the provider
+	Skipped []string
+}
+
+// ExportSecrets reads the values of all the secrets of a provider.
+func ExportSecrets(ctx context.Context, provider Provider) (map[string]string, error) {
+	capabilities := provider.Capabilities()
+	if !capabilities.CanList || !capabilities.CanRead {
+		return nil, errors.New("the secrets provider does not support listing and reading secrets")
+	}
+
+	descriptions, err := provider.ListSecrets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	values := make(map[string]string, len(descriptions))
+	for _, description := range descriptions {
+		value, err := provider.GetSecret(ctx, description.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secret %s: %w", description.Key, err)
+		}
+		values[description.Key] = value
+	}
+	return values, nil
+}
+
+// ImportSecrets writes secrets to a provider. Secrets which already exist in the provider
+// are skipped, unless overwrite is set.
+func ImportSecrets(
+	ctx context.Context,
+	provider Provider,
+	values map[string]string,
+	overwrite bool,
+) (*TransferResult, error) {
+	if !provider.Capabilities().CanWrite {
+		return nil, errors.New("the secrets provider does not support writing secrets")
+	}
+
+	existing := map[string]bool{}
+	if !overwrite && provider.Capabilities().CanList {
+		// Listing avoids treating secrets from environment variables as existing
+		descriptions, err := provider.ListSecrets(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list secrets: %w", err)
+		}
+		for _, description := range descriptions {
+			existing[description.Key] = true
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := &TransferResult{}
+	for _, name := range names {
+		if !overwrite {
+			exists := existing[name]
+			if !provider.Capabilities().CanList {
+				_, err := provider.GetSecret(ctx, name)
+				if err != nil && !IsNotFoundError(err) {
+					return result, fmt.Errorf("failed to check whether secret %s exists: %w", name, err)
+				}
+				exists = err == nil
+			}
+			if exists {
+				result.Skipped = append(result.Skipped, name)
+				continue
+			}
+		}
+
+		if err := provider.SetSecret(ctx, name, values[name]); err != nil {
+			return result, fmt.Errorf("failed to write secret %s: %w", name, err)
+		}
+		result.Imported = append(result.Imported, name)
+	}
+	return result, nil
+}
+
+// MigrateSecrets copies all the secrets of one provider to another. The secrets are not
+// removed from the source provider.
+func MigrateSecrets(ctx context.Context, source, destination Provider, overwrite bool) (*TransferResult, error) {
+	values, err := ExportSecrets(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets from the source provider: %w", err)
+	}
+	return ImportSecrets(ctx, destination, values, overwrite)
+}
+
+// EncryptBundle encrypts secrets into a bundle protected by a passphrase.
+func EncryptBundle(values map[string]string, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase cannot be empty")
+	}
+
+	salt := make([]byte, bundleSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, bundleIterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	contents, err := json.Marshal(fileStructure{Secrets: values})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal secrets: %w", err)
+	}
+	ciphertext, err := aes.Encrypt(contents, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt secrets: %w", err)
+	}
+
+	return json.MarshalIndent(bundleFile{
+		Version:    bundleVersion,
+		KDF:        bundleKDF,
+		Iterations: bundleIterations,
+		Salt:       salt,
+		Ciphertext: ciphertext,
+	}, "", "  ")
+}
+
+// DecryptBundle decrypts the secrets of a bundle created by EncryptBundle.
+func DecryptBundle(data []byte, passphrase string) (map[string]string, error) {
+	var bundle bundleFile
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to decode secrets bundle: %w", err)
+	}
+	if bundle.Version != bundleVersion {
+		return nil, fmt.Errorf("unsupported secrets bundle version: %d", bundle.Version)
+	}
+	if bundle.KDF != bundleKDF || bundle.Iterations <= 0 || bundle.Iterations > bundleMaxIterations || len(bundle.Salt) == 0 {
+		return nil, fmt.Errorf("unsupported key derivation in secrets bundle: %s", bundle.KDF)
+	}
+
+	key, err := pbkdf2.Key(sha256.New, passphrase, bundle.Salt, bundle.Iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	contents, err := aes.Decrypt(bundle.Ciphertext, key)
+	if err != nil {
+		return nil, ErrInvalidBundlePassphrase
+	}
+
+	var decoded fileStructure
+	if err := json.Unmarshal(contents, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode secrets: %w", err)
+	}
+	if decoded.Secrets == nil {
+		decoded.Secrets = map[string]string{}
+	}
+	return decoded.Secrets, nil
+}
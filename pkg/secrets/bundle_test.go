@@ -0,0 +1,100 @@
+package secrets_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stacklok/toolhive/pkg/secrets"
+	"github.com/stacklok/toolhive/pkg/secrets/mocks"
+)
+
+func newEncryptedProvider(t *testing.T, values map[string]string) secrets.Provider {
+	t.Helper()
+	key := make([]byte, 32)
+	provider, err := secrets.NewEncryptedManager(filepath.Join(t.TempDir(), "secrets_encrypted"), key)
+	require.NoError(t, err)
+	for name, value := range values {
+		require.NoError(t, provider.SetSecret(context.Background(), name, value))
+	}
+	return provider
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]string{"github-token": "token", "api-key": "key"}
+	bundle, err := secrets.EncryptBundle(values, "correct horse battery staple")
+	require.NoError(t, err)
+	assert.NotContains(t, string(bundle), "token")
+
+	decrypted, err := secrets.DecryptBundle(bundle, "correct horse battery staple")
+	require.NoError(t, err)
+	assert.Equal(t, values, decrypted)
+
+	_, err = secrets.DecryptBundle(bundle, "wrong passphrase")
+	assert.ErrorIs(t, err, secrets.ErrInvalidBundlePassphrase)
+
+	_, err = secrets.DecryptBundle([]byte(`{"version": 2}`), "correct horse battery staple")
+	assert.ErrorContains(t, err, "unsupported secrets bundle version")
+
+	_, err = secrets.EncryptBundle(values, "")
+	assert.Error(t, err)
+}
+
+func TestExportImportSecrets(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	source := newEncryptedProvider(t, map[string]string{"github-token": "new", "api-key": "key"})
+	values, err := secrets.ExportSecrets(ctx, source)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"github-token": "new", "api-key": "key"}, values)
+
+	// Existing secrets are skipped unless they are overwritten
+	destination := newEncryptedProvider(t, map[string]string{"github-token": "old"})
+	result, err := secrets.ImportSecrets(ctx, destination, values, false)
+	require.NoError(t, err)
+	assert.Equal(t, &secrets.TransferResult{Imported: []string{"api-key"}, Skipped: []string{"github-token"}}, result)
+	value, err := destination.GetSecret(ctx, "github-token")
+	require.NoError(t, err)
+	assert.Equal(t, "old", value)
+
+	result, err = secrets.ImportSecrets(ctx, destination, values, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api-key", "github-token"}, result.Imported)
+	value, err = destination.GetSecret(ctx, "github-token")
+	require.NoError(t, err)
+	assert.Equal(t, "new", value)
+}
+
+func TestMigrateSecrets(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	source := newEncryptedProvider(t, map[string]string{"github-token": "token"})
+	destination := newEncryptedProvider(t, nil)
+	result, err := secrets.MigrateSecrets(ctx, source, destination, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github-token"}, result.Imported)
+
+	// Secrets are kept in the source provider
+	value, err := source.GetSecret(ctx, "github-token")
+	require.NoError(t, err)
+	assert.Equal(t, "token", value)
+
+	// Secrets cannot be migrated to read-only providers
+	ctrl := gomock.NewController(t)
+	readOnly := mocks.NewMockProvider(ctrl)
+	readOnly.EXPECT().Capabilities().Return(secrets.ProviderCapabilities{CanRead: true}).AnyTimes()
+	_, err = secrets.MigrateSecrets(ctx, source, readOnly, false)
+	assert.ErrorContains(t, err, "does not support writing secrets")
+
+	// Or from providers which cannot list their secrets
+	_, err = secrets.MigrateSecrets(ctx, readOnly, destination, false)
+	assert.ErrorContains(t, err, "does not support listing and reading secrets")
+}
@@ -0,0 +1,185 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrVaultNotFound is returned when a Vault path does not exist.
+var ErrVaultNotFound = errors.New("path not found in Vault")
+
+// VaultSecret is the response of the Vault HTTP API for secrets and logins.
+type VaultSecret struct {
+	// LeaseID is the ID of the lease of a dynamic secret
+	LeaseID string `json:"lease_id"`
+	// LeaseDuration is the duration of the lease in seconds
+	LeaseDuration int `json:"lease_duration"`
+	// Renewable indicates whether the lease can be renewed
+	Renewable bool `json:"renewable"`
+	// Data contains the secret data
+	Data map[string]any `json:"data"`
+	// Auth contains the token issued by a login
+	Auth *VaultAuth `json:"auth"`
+}
+
+// VaultAuth is the token issued by a Vault login.
+type VaultAuth struct {
+	ClientToken   string `json:"client_token"`
+	LeaseDuration int    `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+// VaultClient is a minimal client of the Vault HTTP API, which is also served by OpenBao.
+type VaultClient struct {
+	address    string
+	namespace  string
+	httpClient *http.Client
+
+	mu    sync.RWMutex
+	token string
+}
+
+// NewVaultClient creates a client of the Vault server at address. If caCertPath is set,
+// the server certificate is verified against it instead of the system roots.
+func NewVaultClient(address, namespace, caCertPath string) (*VaultClient, error) {
+	parsed, err := url.Parse(address)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid Vault address: %s", address)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if caCertPath != "" {
+		caCert, err := os.ReadFile(filepath.Clean(caCertPath))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Vault CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in %s", caCertPath)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return &VaultClient{
+		address:    strings.TrimSuffix(address, "/"),
+		namespace:  namespace,
+		httpClient: &http.Client{Transport: transport, Timeout: 30 * time.Second},
+	}, nil
+}
+
+// SetToken sets the token used to authenticate requests.
+func (c *VaultClient) SetToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+}
+
+// Read reads the secret at path. It returns ErrVaultNotFound if the path does not exist.
+func (c *VaultClient) Read(ctx context.Context, path string) (*VaultSecret, error) {
+	return c.do(ctx, http.MethodGet, path, nil)
+}
+
+// Write writes data to path and returns the response, which may be nil.
+func (c *VaultClient) Write(ctx context.Context, path string, data any) (*VaultSecret, error) {
+	return c.do(ctx, http.MethodPost, path, data)
+}
+
+// Delete deletes the secret at path.
+func (c *VaultClient) Delete(ctx context.Context, path string) error {
+	_, err := c.do(ctx, http.MethodDelete, path, nil)
+	return err
+}
+
+// List lists the keys under path. Keys ending with a slash are folders.
+// It returns no keys if the path does not exist.
+func (c *VaultClient) List(ctx context.Context, path string) ([]string, error) {
+	secret, err := c.do(ctx, "LIST", path, nil)
+	if errors.Is(err, ErrVaultNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	if secret != nil {
+		rawKeys, _ := secret.Data["keys"].([]any)
+		for _, key := range rawKeys {
+			if k, ok := key.(string); ok {
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys, nil
+}
+
+func (c *VaultClient) do(ctx context.Context, method, path string, body any) (*VaultSecret, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode Vault request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.address+"/v1/"+strings.TrimPrefix(path, "/"), reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Vault request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+	c.mu.RLock()
+	if c.token != "" {
+		req.Header.Set("X-Vault-Token", c.token)
+	}
+	c.mu.RUnlock()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach Vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Vault response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrVaultNotFound, path)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		var errResp struct {
+			Errors []string `json:"errors"`
+		}
+		if json.Unmarshal(respBody, &errResp) == nil && len(errResp.Errors) > 0 {
+			return nil, fmt.Errorf("vault returned %d for %s: %s", resp.StatusCode, path, strings.Join(errResp.Errors, "; "))
+		}
+		return nil, fmt.Errorf("vault returned %d for %s", resp.StatusCode, path)
+	}
+
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		return nil, nil
+	}
+	var secret VaultSecret
+	if err := json.Unmarshal(respBody, &secret); err != nil {
+		return nil, fmt.Errorf("failed to decode Vault response: %w", err)
+	}
+	return &secret, nil
+}
@@ -99,6 +99,21 @@ func (*EncryptedManager) Capabilities() ProviderCapabilities {
 	}
 }
 
+// RotateKey re-encrypts the secrets file with a new key.
+func (e *EncryptedManager) RotateKey(key []byte) error {
+	if len(key) == 0 {
+		return errors.New("key cannot be empty")
+	}
+
+	previousKey := e.key
+	e.key = key
+	if err := e.updateFile(); err != nil {
+		e.key = previousKey
+		return err
+	}
+	return nil
+}
+
 func (e *EncryptedManager) updateFile() error {
 	// Convert syncmap.Map to map[string]string for JSON marshaling
 	secretsMap := make(map[string]string)
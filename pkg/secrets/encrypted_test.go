@@ -355,6 +355,28 @@ func TestEncryptedManager_Concurrency(t *testing.T) {
 	}
 }
 
+func TestEncryptedManager_RotateKey(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	tempFile := filepath.Join(t.TempDir(), "secrets_encrypted")
+
+	oldKey := generateRandomKey(t)
+	manager := createEncryptedManager(t, tempFile, oldKey)
+	require.NoError(t, manager.SetSecret(ctx, "github-token", "token"))
+
+	newKey := generateRandomKey(t)
+	require.NoError(t, manager.RotateKey(newKey))
+	require.Error(t, manager.RotateKey(nil))
+
+	// The file can only be decrypted with the new key
+	_, err := NewEncryptedManager(tempFile, oldKey)
+	require.Error(t, err)
+	rotated := createEncryptedManager(t, tempFile, newKey)
+	value, err := rotated.GetSecret(ctx, "github-token")
+	require.NoError(t, err)
+	assert.Equal(t, "token", value)
+}
+
 // End of tests
 
 // Helper functions
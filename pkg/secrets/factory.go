@@ -56,8 +56,21 @@ const (
 
 	// EnvironmentType represents the environment variable secret provider
 	EnvironmentType ProviderType = "environment"
+
+	// VaultType represents the HashiCorp Vault (or OpenBao) secret provider.
+	VaultType ProviderType = "vault"
 )
 
+// isBuiltinProviderType returns true if the provider type is built into ToolHive.
+func isBuiltinProviderType(providerType ProviderType) bool {
+	switch providerType {
+	case EncryptedType, OnePasswordType, NoneType, EnvironmentType, VaultType:
+		return true
+	default:
+		return false
+	}
+}
+
 // ErrUnknownManagerType is returned when an invalid value for ProviderType is specified.
 var ErrUnknownManagerType = errors.New("unknown secret manager type")
 
@@ -104,7 +117,12 @@ func ValidateProviderWithPassword(ctx context.Context, providerType ProviderType
 		return validateNoneProvider(result)
 	case EnvironmentType:
 		return ValidateEnvironmentProvider(ctx, provider, result)
+	case VaultType:
+		return validateVaultProvider(ctx, provider, result)
 	default:
+		if IsPluginProviderType(providerType) {
+			return validatePluginProvider(ctx, provider, result)
+		}
 		result.Error = fmt.Errorf("unknown provider type: %s", providerType)
 		result.Message = "Unknown provider type"
 		return result
@@ -185,6 +203,52 @@ func validateOnePasswordProvider(ctx context.Context, provider Provider, result
 	return result
 }
 
+// validateVaultProvider tests Vault provider connectivity. Authentication already happened
+// when the provider was created, so this checks that the token can access ToolHive secrets.
+func validateVaultProvider(ctx context.Context, provider Provider, result *SetupResult) *SetupResult {
+	capabilities := provider.Capabilities()
+	if !capabilities.CanRead {
+		result.Error = fmt.Errorf("the Vault token cannot read ToolHive secrets")
+		result.Message = "Vault provider validation failed"
+		return result
+	}
+
+	if capabilities.CanList {
+		if _, err := provider.ListSecrets(ctx); err != nil {
+			result.Error = fmt.Errorf("failed to list secrets in Vault: %w", err)
+			result.Message = "Failed to connect to Vault"
+			return result
+		}
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("Vault provider validation successful (%s)", capabilities)
+	return result
+}
+
+// validatePluginProvider tests a secrets provider plugin. The plugin already reported its
+// capabilities when the provider was created, so this checks that it can access its store.
+func validatePluginProvider(ctx context.Context, provider Provider, result *SetupResult) *SetupResult {
+	capabilities := provider.Capabilities()
+	if !capabilities.CanRead {
+		result.Error = fmt.Errorf("the secrets provider plugin %s cannot read secrets", result.ProviderType)
+		result.Message = "Plugin provider validation failed"
+		return result
+	}
+
+	if capabilities.CanList {
+		if _, err := provider.ListSecrets(ctx); err != nil {
+			result.Error = fmt.Errorf("failed to list secrets: %w", err)
+			result.Message = fmt.Sprintf("Failed to connect to secrets provider plugin %s", result.ProviderType)
+			return result
+		}
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("Plugin provider %s validation successful (%s)", result.ProviderType, capabilities)
+	return result
+}
+
 // validateNoneProvider validates the none provider (always succeeds)
 func validateNoneProvider(result *SetupResult) *SetupResult {
 	// None provider doesn't need validation, it always works
@@ -244,11 +308,17 @@ func CreateSecretProviderWithPassword(managerType ProviderType, password string)
 		primary, err = NewOnePasswordManager()
 	case NoneType:
 		primary, err = NewNoneManager()
+	case VaultType:
+		primary, err = NewVaultManager()
 	case EnvironmentType:
 		// Direct environment provider - no fallback needed
 		return NewEnvironmentProvider(), nil
 	default:
-		return nil, ErrUnknownManagerType
+		// Provider types which are not built in are implemented by plugins
+		if !IsPluginProviderType(managerType) {
+			return nil, ErrUnknownManagerType
+		}
+		primary, err = NewPluginManager(string(managerType))
 	}
 
 	if err != nil {
@@ -340,6 +410,51 @@ func readPasswordStdin() ([]byte, error) {
 	return password, nil
 }
 
+// RotateSecretsPassword re-encrypts the secrets of the encrypted provider with a key derived
+// from a new password, and replaces the password stored in the OS keyring.
+func RotateSecretsPassword(newPassword string) error {
+	if newPassword == "" {
+		return errors.New("password cannot be empty")
+	}
+	if !IsKeyringAvailable() {
+		return ErrKeyringNotAvailable
+	}
+
+	provider := getKeyringProvider()
+	currentPassword, err := provider.Get(keyringService, keyringService)
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return errors.New("no password is stored in the keyring, the encrypted secrets provider has not been set up")
+		}
+		return fmt.Errorf("failed to read password from keyring: %w", err)
+	}
+
+	secretsPath, err := xdg.DataFile("toolhive/secrets_encrypted")
+	if err != nil {
+		return fmt.Errorf("unable to access secrets file path %v", err)
+	}
+	currentKey := sha256.Sum256([]byte(currentPassword))
+	manager, err := NewEncryptedManager(secretsPath, currentKey[:])
+	if err != nil {
+		return err
+	}
+	encrypted := manager.(*EncryptedManager)
+
+	newKey := sha256.Sum256([]byte(newPassword))
+	if err := encrypted.RotateKey(newKey[:]); err != nil {
+		return fmt.Errorf("failed to re-encrypt secrets: %w", err)
+	}
+
+	if err := provider.Set(keyringService, keyringService, newPassword); err != nil {
+		// Restore the previous key, so that the secrets can still be decrypted with the stored password
+		if restoreErr := encrypted.RotateKey(currentKey[:]); restoreErr != nil {
+			return fmt.Errorf("failed to store password in keyring (%v) and to restore the previous key: %w", err, restoreErr)
+		}
+		return fmt.Errorf("failed to store password in keyring: %w", err)
+	}
+	return nil
+}
+
 // ResetKeyringSecret clears out the secret from the keystore (if present).
 func ResetKeyringSecret() error {
 	provider := getKeyringProvider()
@@ -64,6 +64,12 @@ func (f *FallbackProvider) ListSecrets(ctx context.Context) ([]SecretDescription
 	return f.primary.ListSecrets(ctx)
 }
 
+// GetSecretVersion returns the version of the secret in the primary provider.
+// Secrets from environment variables are not versioned.
+func (f *FallbackProvider) GetSecretVersion(ctx context.Context, name string) (string, error) {
+	return GetSecretVersion(ctx, f.primary, name)
+}
+
 // Cleanup delegates to the primary provider
 func (f *FallbackProvider) Cleanup() error {
 	return f.primary.Cleanup()
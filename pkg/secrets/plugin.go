@@ -0,0 +1,332 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	goruntime "runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Secrets provider plugins are executables named thv-secrets-<name> on the PATH, which
+// allow integrating secret stores that are not built into ToolHive. The plugin is run
+// once per operation: ToolHive writes a single PluginRequest as JSON to its standard
+// input and reads a single PluginResponse as JSON from its standard output. Anything
+// the plugin writes to standard error is included in error messages. The plugin is
+// passed the environment of ToolHive, so it can use the usual credentials of its store.
+//
+// The supported operations are:
+//   - capabilities: returns the operations the plugin supports in Capabilities
+//   - get: returns the value of the secret Name in Value
+//   - set: sets the secret Name to Value
+//   - delete: deletes the secret Name
+//   - list: returns the secrets in Secrets
+//
+// Failures are reported in Error, using PluginErrorNotFound when a secret does not exist.
+
+const (
+	// PluginExecutablePrefix is the prefix of the names of secrets provider plugin executables.
+	PluginExecutablePrefix = "thv-secrets-"
+
+	// PluginProtocolVersion is the version of the protocol spoken with secrets provider plugins.
+	PluginProtocolVersion = "1"
+
+	// PluginErrorNotFound is the error code returned by plugins when a secret does not exist.
+	PluginErrorNotFound = "not_found"
+
+	// PluginErrorUnsupported is the error code returned by plugins for unsupported operations.
+	PluginErrorUnsupported = "unsupported"
+
+	// pluginTimeout is the maximum time a plugin may take to perform an operation.
+	pluginTimeout = 30 * time.Second
+)
+
+// PluginOperation is an operation performed by a secrets provider plugin.
+type PluginOperation string
+
+const (
+	// PluginOperationCapabilities returns the operations supported by the plugin.
+	PluginOperationCapabilities PluginOperation = "capabilities"
+	// PluginOperationGet returns the value of a secret.
+	PluginOperationGet PluginOperation = "get"
+	// PluginOperationSet creates or updates a secret.
+	PluginOperationSet PluginOperation = "set"
+	// PluginOperationDelete deletes a secret.
+	PluginOperationDelete PluginOperation = "delete"
+	// PluginOperationList lists the secrets.
+	PluginOperationList PluginOperation = "list"
+)
+
+// pluginNameRegex matches valid plugin names, which must not contain path separators.
+var pluginNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// PluginRequest is written by ToolHive to the standard input of a secrets provider plugin.
+type PluginRequest struct {
+	// Version is the version of the protocol
+	Version string `json:"version"`
+	// Operation is the operation to perform
+	Operation PluginOperation `json:"operation"`
+	// Name is the name of the secret, for the get, set and delete operations
+	Name string `json:"name,omitempty"`
+	// Value is the value of the secret, for the set operation
+	Value string `json:"value,omitempty"`
+}
+
+// PluginResponse is written by a secrets provider plugin to its standard output.
+type PluginResponse struct {
+	// Value is the value of the secret, for the get operation
+	Value string `json:"value,omitempty"`
+	// Secrets are the secrets, for the list operation
+	Secrets []SecretDescription `json:"secrets,omitempty"`
+	// Capabilities are the supported operations, for the capabilities operation
+	Capabilities *PluginCapabilities `json:"capabilities,omitempty"`
+	// Error is set if the operation failed
+	Error *PluginError `json:"error,omitempty"`
+}
+
+// PluginCapabilities describes the operations supported by a secrets provider plugin.
+type PluginCapabilities struct {
+	Read   bool `json:"read"`
+	Write  bool `json:"write"`
+	Delete bool `json:"delete"`
+	List   bool `json:"list"`
+}
+
+// PluginError describes an operation which failed in a secrets provider plugin.
+type PluginError struct {
+	// Code identifies the kind of error, e.g. PluginErrorNotFound
+	Code string `json:"code,omitempty"`
+	// Message is a human-readable description of the error
+	Message string `json:"message"`
+}
+
+// PluginManager manages secrets using a secrets provider plugin.
+type PluginManager struct {
+	name         string
+	path         string
+	capabilities ProviderCapabilities
+}
+
+// IsPluginProviderType returns true if the provider type is not built in, and a plugin
+// executable for it is installed on the PATH.
+func IsPluginProviderType(providerType ProviderType) bool {
+	if isBuiltinProviderType(providerType) {
+		return false
+	}
+	_, err := FindPlugin(string(providerType))
+	return err == nil
+}
+
+// FindPlugin returns the path of the executable of the named secrets provider plugin.
+func FindPlugin(name string) (string, error) {
+	if !pluginNameRegex.MatchString(name) {
+		return "", fmt.Errorf("invalid secrets provider plugin name: %s", name)
+	}
+	path, err := exec.LookPath(PluginExecutablePrefix + name)
+	if err != nil {
+		return "", fmt.Errorf("secrets provider plugin %s not found: %w", name, err)
+	}
+	return path, nil
+}
+
+// ListPlugins returns the names of the secrets provider plugins installed on the PATH.
+func ListPlugins() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), PluginExecutablePrefix)
+			if !ok || entry.IsDir() {
+				continue
+			}
+			if goruntime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			if seen[name] || !pluginNameRegex.MatchString(name) || isBuiltinProviderType(ProviderType(name)) {
+				continue
+			}
+			if _, err := exec.LookPath(filepath.Join(dir, entry.Name())); err != nil {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewPluginManager creates a secrets provider which uses the named plugin.
+func NewPluginManager(name string) (Provider, error) {
+	path, err := FindPlugin(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewPluginManagerWithPath(context.Background(), name, path)
+}
+
+// NewPluginManagerWithPath creates a secrets provider which uses the plugin executable at path.
+// The plugin is asked for its capabilities, which ensures it speaks the protocol.
+func NewPluginManagerWithPath(ctx context.Context, name, path string) (*PluginManager, error) {
+	manager := &PluginManager{
+		name: name,
+		path: path,
+	}
+
+	response, err := manager.call(ctx, PluginRequest{Operation: PluginOperationCapabilities})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get capabilities of secrets provider plugin %s: %w", name, err)
+	}
+	if response.Capabilities == nil {
+		return nil, fmt.Errorf("secrets provider plugin %s did not return its capabilities", name)
+	}
+	manager.capabilities = ProviderCapabilities{
+		CanRead:   response.Capabilities.Read,
+		CanWrite:  response.Capabilities.Write,
+		CanDelete: response.Capabilities.Delete,
+		CanList:   response.Capabilities.List,
+	}
+
+	return manager, nil
+}
+
+// GetSecret retrieves a secret from the plugin.
+func (p *PluginManager) GetSecret(ctx context.Context, name string) (string, error) {
+	if name == "" {
+		return "", errors.New("secret name cannot be empty")
+	}
+	if !p.capabilities.CanRead {
+		return "", p.unsupported(PluginOperationGet)
+	}
+
+	response, err := p.call(ctx, PluginRequest{Operation: PluginOperationGet, Name: name})
+	if err != nil {
+		return "", err
+	}
+	return response.Value, nil
+}
+
+// SetSecret stores a secret using the plugin.
+func (p *PluginManager) SetSecret(ctx context.Context, name, value string) error {
+	if name == "" {
+		return errors.New("secret name cannot be empty")
+	}
+	if !p.capabilities.CanWrite {
+		return p.unsupported(PluginOperationSet)
+	}
+
+	_, err := p.call(ctx, PluginRequest{Operation: PluginOperationSet, Name: name, Value: value})
+	return err
+}
+
+// DeleteSecret deletes a secret using the plugin.
+func (p *PluginManager) DeleteSecret(ctx context.Context, name string) error {
+	if name == "" {
+		return errors.New("secret name cannot be empty")
+	}
+	if !p.capabilities.CanDelete {
+		return p.unsupported(PluginOperationDelete)
+	}
+
+	_, err := p.call(ctx, PluginRequest{Operation: PluginOperationDelete, Name: name})
+	return err
+}
+
+// ListSecrets lists the secrets available through the plugin.
+func (p *PluginManager) ListSecrets(ctx context.Context) ([]SecretDescription, error) {
+	if !p.capabilities.CanList {
+		return nil, p.unsupported(PluginOperationList)
+	}
+
+	response, err := p.call(ctx, PluginRequest{Operation: PluginOperationList})
+	if err != nil {
+		return nil, err
+	}
+	if response.Secrets == nil {
+		return []SecretDescription{}, nil
+	}
+	return response.Secrets, nil
+}
+
+// Cleanup is not supported by plugins, as they may give access to secrets not managed by ToolHive.
+func (*PluginManager) Cleanup() error {
+	return nil
+}
+
+// Capabilities returns the capabilities reported by the plugin.
+func (p *PluginManager) Capabilities() ProviderCapabilities {
+	return p.capabilities
+}
+
+// call runs the plugin to perform a single operation.
+func (p *PluginManager) call(ctx context.Context, request PluginRequest) (*PluginResponse, error) {
+	request.Version = PluginProtocolVersion
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode plugin request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pluginTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.path) // #nosec G204 -- the plugin is an executable installed by the user
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	var response PluginResponse
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("secrets provider plugin %s failed: %w%s", p.name, runErr, formatPluginStderr(&stderr))
+		}
+		return nil, fmt.Errorf("invalid response from secrets provider plugin %s: %w", p.name, err)
+	}
+
+	if response.Error != nil {
+		switch response.Error.Code {
+		case PluginErrorNotFound:
+			return nil, fmt.Errorf("secret not found: %s", request.Name)
+		case PluginErrorUnsupported:
+			return nil, p.unsupported(request.Operation)
+		default:
+			return nil, fmt.Errorf("secrets provider plugin %s failed to %s secret: %s",
+				p.name, request.Operation, response.Error.Message)
+		}
+	}
+	if runErr != nil {
+		return nil, fmt.Errorf("secrets provider plugin %s failed: %w%s", p.name, runErr, formatPluginStderr(&stderr))
+	}
+
+	return &response, nil
+}
+
+func (p *PluginManager) unsupported(operation PluginOperation) error {
+	return fmt.Errorf("secrets provider plugin %s does not support the %s operation", p.name, operation)
+}
+
+// formatPluginStderr formats the standard error of a plugin for inclusion in an error message.
+func formatPluginStderr(stderr *bytes.Buffer) string {
+	output := strings.TrimSpace(stderr.String())
+	if output == "" {
+		return ""
+	}
+	const maxLength = 1024
+	if len(output) > maxLength {
+		output = output[:maxLength] + "..."
+	}
+	return ": " + output
+}
@@ -0,0 +1,172 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	goruntime "runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePluginStoreEnvVar makes the test binary act as a secrets provider plugin, which
+// stores its secrets in the JSON file named by the variable.
+const fakePluginStoreEnvVar = "TOOLHIVE_TEST_SECRETS_PLUGIN_STORE"
+
+func TestMain(m *testing.M) {
+	if store := os.Getenv(fakePluginStoreEnvVar); store != "" {
+		os.Exit(runFakePlugin(store))
+	}
+	os.Exit(m.Run())
+}
+
+func runFakePlugin(store string) int {
+	var request PluginRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid request: %v\n", err)
+		return 1
+	}
+	if request.Version != PluginProtocolVersion {
+		fmt.Fprintf(os.Stderr, "unsupported protocol version %s\n", request.Version)
+		return 1
+	}
+	if request.Name == "crash" {
+		fmt.Fprintln(os.Stderr, "something went wrong")
+		return 2
+	}
+
+	values := map[string]string{}
+	if data, err := os.ReadFile(store); err == nil {
+		_ = json.Unmarshal(data, &values)
+	}
+
+	var response PluginResponse
+	switch request.Operation {
+	case PluginOperationCapabilities:
+		response.Capabilities = &PluginCapabilities{Read: true, Write: true, Delete: true, List: true}
+	case PluginOperationGet:
+		value, ok := values[request.Name]
+		if !ok {
+			response.Error = &PluginError{Code: PluginErrorNotFound, Message: "no such secret"}
+		}
+		response.Value = value
+	case PluginOperationSet:
+		if request.Name == "readonly" {
+			response.Error = &PluginError{Message: "access denied"}
+			break
+		}
+		values[request.Name] = request.Value
+	case PluginOperationDelete:
+		delete(values, request.Name)
+	case PluginOperationList:
+		for name := range values {
+			response.Secrets = append(response.Secrets, SecretDescription{Key: name})
+		}
+	default:
+		response.Error = &PluginError{Code: PluginErrorUnsupported}
+	}
+
+	data, _ := json.Marshal(values)
+	_ = os.WriteFile(store, data, 0600)
+	_ = json.NewEncoder(os.Stdout).Encode(response)
+	return 0
+}
+
+// installFakePlugin installs the test binary as the named plugin in a new directory on the PATH.
+func installFakePlugin(t *testing.T, name string) string {
+	t.Helper()
+	if goruntime.GOOS == "windows" {
+		t.Skip("plugin tests use symbolic links")
+	}
+
+	executable, err := os.Executable()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, PluginExecutablePrefix+name)
+	require.NoError(t, os.Symlink(executable, path))
+	t.Setenv("PATH", dir)
+	t.Setenv(fakePluginStoreEnvVar, filepath.Join(t.TempDir(), "store.json"))
+	return path
+}
+
+func TestPluginManager(t *testing.T) {
+	path := installFakePlugin(t, "fake")
+	ctx := context.Background()
+
+	manager, err := NewPluginManagerWithPath(ctx, "fake", path)
+	require.NoError(t, err)
+	assert.Equal(t, ProviderCapabilities{CanRead: true, CanWrite: true, CanDelete: true, CanList: true},
+		manager.Capabilities())
+
+	_, err = manager.GetSecret(ctx, "github-token")
+	require.Error(t, err)
+	assert.True(t, IsNotFoundError(err))
+
+	require.NoError(t, manager.SetSecret(ctx, "github-token", "token"))
+	value, err := manager.GetSecret(ctx, "github-token")
+	require.NoError(t, err)
+	assert.Equal(t, "token", value)
+
+	secrets, err := manager.ListSecrets(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []SecretDescription{{Key: "github-token"}}, secrets)
+
+	require.NoError(t, manager.DeleteSecret(ctx, "github-token"))
+	secrets, err = manager.ListSecrets(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, secrets)
+
+	// Errors reported by the plugin are returned
+	err = manager.SetSecret(ctx, "readonly", "value")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "secrets provider plugin fake failed to set secret: access denied")
+
+	// The standard error of plugins which fail is included in the error
+	_, err = manager.GetSecret(ctx, "crash")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "something went wrong")
+	assert.False(t, IsNotFoundError(err))
+}
+
+func TestPluginManagerUnsupportedOperations(t *testing.T) {
+	t.Parallel()
+
+	manager := &PluginManager{name: "readonly", capabilities: ProviderCapabilities{CanRead: true}}
+	ctx := context.Background()
+
+	err := manager.SetSecret(ctx, "name", "value")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support the set operation")
+
+	require.Error(t, manager.DeleteSecret(ctx, "name"))
+	_, err = manager.ListSecrets(ctx)
+	require.Error(t, err)
+}
+
+func TestPluginDiscovery(t *testing.T) {
+	installFakePlugin(t, "fake")
+	dir := os.Getenv("PATH")
+
+	// Plugins named after built-in providers and files which are not executable are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, PluginExecutablePrefix+"vault"), []byte("#!/bin/sh\n"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, PluginExecutablePrefix+"text"), []byte("text"), 0600))
+
+	assert.Equal(t, []string{"fake"}, ListPlugins())
+	assert.True(t, IsPluginProviderType("fake"))
+	assert.False(t, IsPluginProviderType(VaultType))
+	assert.False(t, IsPluginProviderType("missing"))
+	assert.False(t, IsPluginProviderType("../fake"))
+
+	t.Setenv("TOOLHIVE_DISABLE_ENV_FALLBACK", "true")
+	provider, err := CreateSecretProvider("fake")
+	require.NoError(t, err)
+	assert.IsType(t, &PluginManager{}, provider)
+
+	_, err = CreateSecretProvider("missing")
+	assert.ErrorIs(t, err, ErrUnknownManagerType)
+}
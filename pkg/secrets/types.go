@@ -4,16 +4,30 @@ package secrets
 import (
 	"context"
 	"fmt"
+	"os"
+	"path"
 	"regexp"
+	"strconv"
+	"strings"
 )
 
 const (
 	// EnvVarPrefix is the prefix used for environment variable secrets
 	EnvVarPrefix = "TOOLHIVE_SECRET_"
+
+	// SecretFileTargetPrefix is the prefix of targets which mount the secret as a file
+	// rather than passing it as an environment variable, e.g. "file:/run/secrets/token".
+	SecretFileTargetPrefix = "file:"
+
+	// DefaultSecretFileMode is the permission mode of secret files without an explicit mode.
+	// Secret files are owned by the user running ToolHive rather than the container user,
+	// so they are readable by all users of the container unless a stricter mode is set.
+	DefaultSecretFileMode os.FileMode = 0444
 )
 
-// regex to extract name and target from secret parameter, e.g. "name,target=target"
-var secretParamRegex = regexp.MustCompile(`^([^,]+),target=(.+)$`)
+// regex to extract name, target and optional file mode from secret parameter,
+// e.g. "name,target=target" or "name,target=file:/path,mode=0400"
+var secretParamRegex = regexp.MustCompile(`^([^,]+),target=([^,]+)(?:,mode=([0-7]{3,4}))?$`)
 
 // ProviderCapabilities represents what operations a secrets provider supports.
 type ProviderCapabilities struct {
@@ -56,32 +70,94 @@ type Provider interface {
 	Capabilities() ProviderCapabilities
 }
 
+// VersionedProvider is implemented by providers which version their secrets, which
+// allows detecting workloads that were started with a previous version of a secret.
+type VersionedProvider interface {
+	// GetSecretVersion returns the current version of the named secret.
+	GetSecretVersion(ctx context.Context, name string) (string, error)
+}
+
+// GetSecretVersion returns the current version of the named secret, or an empty string
+// if the provider does not version its secrets.
+func GetSecretVersion(ctx context.Context, provider Provider, name string) (string, error) {
+	versioned, ok := provider.(VersionedProvider)
+	if !ok {
+		return "", nil
+	}
+	return versioned.GetSecretVersion(ctx, name)
+}
+
 // SecretParameter represents a parsed `--secret` parameter.
 type SecretParameter struct {
 	Name   string `json:"name"`
 	Target string `json:"target"`
+	// Mode is the octal permission mode of a secret file, e.g. "0400".
+	// It is only valid for file targets.
+	Mode string `json:"mode,omitempty"`
 }
 
 // ParseSecretParameter creates an instance of SecretParameter from a string.
-// Expected format: `<Name>,target=<Target>`.
+// Expected format: `<Name>,target=<Target>` or `<Name>,target=file:<Path>[,mode=<Mode>]`.
 func ParseSecretParameter(parameter string) (SecretParameter, error) {
 	if parameter == "" {
 		return SecretParameter{}, fmt.Errorf("secret parameter cannot be empty")
 	}
 
-	// extract name and target using secretParamRegex
+	// extract name, target and mode using secretParamRegex
 	matches := secretParamRegex.FindStringSubmatch(parameter)
-	if len(matches) != 3 { // The first element is the full match, followed by capture groups
+	if len(matches) != 4 { // The first element is the full match, followed by capture groups
 		return SecretParameter{}, fmt.Errorf("invalid secret parameter format: %s", parameter)
 	}
 
-	name := matches[1]
-	target := matches[2]
+	result := SecretParameter{
+		Name:   matches[1],
+		Target: matches[2],
+		Mode:   matches[3],
+	}
+	if err := result.validate(); err != nil {
+		return SecretParameter{}, fmt.Errorf("invalid secret parameter %s: %w", parameter, err)
+	}
+	return result, nil
+}
+
+// IsFile returns true if the secret is mounted as a file rather than passed as an environment variable.
+func (p SecretParameter) IsFile() bool {
+	return strings.HasPrefix(p.Target, SecretFileTargetPrefix)
+}
+
+// FilePath returns the path of the secret file in the container.
+func (p SecretParameter) FilePath() string {
+	return strings.TrimPrefix(p.Target, SecretFileTargetPrefix)
+}
+
+// FileMode returns the permission mode of the secret file.
+func (p SecretParameter) FileMode() os.FileMode {
+	if p.Mode == "" {
+		return DefaultSecretFileMode
+	}
+	mode, err := strconv.ParseUint(p.Mode, 8, 32)
+	if err != nil {
+		return DefaultSecretFileMode
+	}
+	return os.FileMode(mode) & os.ModePerm
+}
+
+func (p SecretParameter) validate() error {
+	if !p.IsFile() {
+		if p.Mode != "" {
+			return fmt.Errorf("mode is only supported for file targets")
+		}
+		return nil
+	}
 
-	return SecretParameter{
-		Name:   name,
-		Target: target,
-	}, nil
+	filePath := p.FilePath()
+	if !path.IsAbs(filePath) || path.Clean(filePath) != filePath || filePath == "/" {
+		return fmt.Errorf("secret file path must be an absolute, clean path: %s", filePath)
+	}
+	if p.FileMode()&0444 == 0 {
+		return fmt.Errorf("secret file mode %s does not allow reading the file", p.Mode)
+	}
+	return nil
 }
 
 // SecretParametersToCLI does the reverse of `ParseSecretParameter`
@@ -90,6 +166,9 @@ func SecretParametersToCLI(params []SecretParameter) []string {
 	result := make([]string, len(params))
 	for i, p := range params {
 		result[i] = fmt.Sprintf("%s,target=%s", p.Name, p.Target)
+		if p.Mode != "" {
+			result[i] += ",mode=" + p.Mode
+		}
 	}
 	return result
 }
@@ -0,0 +1,550 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/env"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/secrets/clients"
+)
+
+// Environment variables configuring the Vault provider. VAULT_ADDR, VAULT_TOKEN,
+// VAULT_NAMESPACE and VAULT_CACERT follow the conventions of the Vault CLI.
+const (
+	VaultAddrEnvVar       = "VAULT_ADDR"
+	VaultTokenEnvVar      = "VAULT_TOKEN"
+	VaultNamespaceEnvVar  = "VAULT_NAMESPACE"
+	VaultCACertEnvVar     = "VAULT_CACERT"
+	VaultAuthMethodEnvVar = "TOOLHIVE_VAULT_AUTH_METHOD"
+	VaultAuthMountEnvVar  = "TOOLHIVE_VAULT_AUTH_MOUNT"
+	VaultRoleIDEnvVar     = "TOOLHIVE_VAULT_ROLE_ID"
+	VaultSecretIDEnvVar   = "TOOLHIVE_VAULT_SECRET_ID"
+	VaultRoleEnvVar       = "TOOLHIVE_VAULT_ROLE"
+	VaultJWTPathEnvVar    = "TOOLHIVE_VAULT_JWT_PATH"
+	VaultKVMountEnvVar    = "TOOLHIVE_VAULT_KV_MOUNT"
+	VaultPathPrefixEnvVar = "TOOLHIVE_VAULT_PATH_PREFIX"
+)
+
+// VaultAuthMethod is a method of authenticating to Vault.
+type VaultAuthMethod string
+
+const (
+	// VaultAuthToken authenticates with the token in VAULT_TOKEN.
+	VaultAuthToken VaultAuthMethod = "token"
+	// VaultAuthAppRole authenticates with an AppRole role ID and secret ID.
+	VaultAuthAppRole VaultAuthMethod = "approle"
+	// VaultAuthKubernetes authenticates with the Kubernetes service account token of the pod.
+	VaultAuthKubernetes VaultAuthMethod = "kubernetes"
+)
+
+const (
+	defaultVaultKVMount     = "secret"
+	defaultVaultPathPrefix  = "toolhive"
+	defaultVaultJWTPath     = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	vaultDefaultSecretField = "value"
+
+	// VaultRawPathPrefix marks a secret name as a path read as-is from Vault, such as a
+	// dynamic secret engine. For example: vault:database/creds/readonly#password
+	VaultRawPathPrefix = "vault:"
+)
+
+// ErrVaultRawPathReadOnly is returned when writing or deleting a secret read as-is from Vault.
+var ErrVaultRawPathReadOnly = errors.New("secrets with the vault: prefix are read-only")
+
+// VaultConfig is the configuration of the Vault provider.
+type VaultConfig struct {
+	Address    string
+	Namespace  string
+	CACertPath string
+	AuthMethod VaultAuthMethod
+	// AuthMount is the mount path of the AppRole or Kubernetes auth method
+	AuthMount string
+	Token     string
+	RoleID    string
+	SecretID  string
+	// Role is the role of the Kubernetes auth method
+	Role string
+	// JWTPath is the path of the service account token used by the Kubernetes auth method
+	JWTPath string
+	// KVMount is the mount path of the KV v2 secrets engine
+	KVMount string
+	// PathPrefix is the path under KVMount where ToolHive secrets are stored
+	PathPrefix string
+}
+
+// NewVaultConfigFromEnv reads the configuration of the Vault provider from the environment.
+func NewVaultConfigFromEnv(envReader env.Reader) (*VaultConfig, error) {
+	config := &VaultConfig{
+		Address:    envReader.Getenv(VaultAddrEnvVar),
+		Namespace:  envReader.Getenv(VaultNamespaceEnvVar),
+		CACertPath: envReader.Getenv(VaultCACertEnvVar),
+		AuthMethod: VaultAuthMethod(envReader.Getenv(VaultAuthMethodEnvVar)),
+		AuthMount:  envReader.Getenv(VaultAuthMountEnvVar),
+		Token:      envReader.Getenv(VaultTokenEnvVar),
+		RoleID:     envReader.Getenv(VaultRoleIDEnvVar),
+		SecretID:   envReader.Getenv(VaultSecretIDEnvVar),
+		Role:       envReader.Getenv(VaultRoleEnvVar),
+		JWTPath:    envReader.Getenv(VaultJWTPathEnvVar),
+		KVMount:    envReader.Getenv(VaultKVMountEnvVar),
+		PathPrefix: envReader.Getenv(VaultPathPrefixEnvVar),
+	}
+
+	if config.AuthMethod == "" {
+		switch {
+		case config.Token != "":
+			config.AuthMethod = VaultAuthToken
+		case config.RoleID != "":
+			config.AuthMethod = VaultAuthAppRole
+		case config.Role != "":
+			config.AuthMethod = VaultAuthKubernetes
+		default:
+			config.AuthMethod = VaultAuthToken
+		}
+	}
+	if config.AuthMount == "" && config.AuthMethod != VaultAuthToken {
+		config.AuthMount = string(config.AuthMethod)
+	}
+	if config.JWTPath == "" {
+		config.JWTPath = defaultVaultJWTPath
+	}
+	if config.KVMount == "" {
+		config.KVMount = defaultVaultKVMount
+	}
+	if config.PathPrefix == "" {
+		config.PathPrefix = defaultVaultPathPrefix
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Validate checks that the configuration is complete for its auth method.
+func (c *VaultConfig) Validate() error {
+	if c.Address == "" {
+		return fmt.Errorf("%s is not set", VaultAddrEnvVar)
+	}
+	switch c.AuthMethod {
+	case VaultAuthToken:
+		if c.Token == "" {
+			return fmt.Errorf("%s is not set", VaultTokenEnvVar)
+		}
+	case VaultAuthAppRole:
+		if c.RoleID == "" || c.SecretID == "" {
+			return fmt.Errorf("%s and %s must be set for AppRole auth", VaultRoleIDEnvVar, VaultSecretIDEnvVar)
+		}
+	case VaultAuthKubernetes:
+		if c.Role == "" {
+			return fmt.Errorf("%s must be set for Kubernetes auth", VaultRoleEnvVar)
+		}
+	default:
+		return fmt.Errorf("invalid %s: %s (valid methods: %s, %s, %s)", VaultAuthMethodEnvVar, c.AuthMethod,
+			VaultAuthToken, VaultAuthAppRole, VaultAuthKubernetes)
+	}
+	return nil
+}
+
+// VaultManager manages secrets in the KV v2 secrets engine of HashiCorp Vault or OpenBao.
+// Secrets are stored in the "value" field of <KVMount>/data/<PathPrefix>/<name>. Other
+// fields are addressed as <name>#<field>. Names with the vault: prefix are read as-is,
+// which allows using dynamic secrets, whose leases are renewed until Cleanup is called.
+type VaultManager struct {
+	client       *clients.VaultClient
+	config       *VaultConfig
+	capabilities ProviderCapabilities
+
+	mu sync.Mutex
+	// leased caches dynamic secrets by path, so that fields of the same lease are consistent
+	leased map[string]*clients.VaultSecret
+	stop   chan struct{}
+	once   sync.Once
+}
+
+// NewVaultManager creates an instance of VaultManager configured from the environment.
+func NewVaultManager() (Provider, error) {
+	config, err := NewVaultConfigFromEnv(&env.OSReader{})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return NewVaultManagerWithConfig(ctx, config)
+}
+
+// NewVaultManagerWithConfig creates an instance of VaultManager and authenticates to Vault.
+func NewVaultManagerWithConfig(ctx context.Context, config *VaultConfig) (*VaultManager, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := clients.NewVaultClient(config.Address, config.Namespace, config.CACertPath)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &VaultManager{
+		client: client,
+		config: config,
+		leased: map[string]*clients.VaultSecret{},
+		stop:   make(chan struct{}),
+	}
+	if err := v.login(ctx); err != nil {
+		return nil, err
+	}
+	v.capabilities = v.lookupCapabilities(ctx)
+	return v, nil
+}
+
+// login authenticates to Vault and renews the token of AppRole and Kubernetes logins.
+func (v *VaultManager) login(ctx context.Context) error {
+	var body map[string]string
+	switch v.config.AuthMethod {
+	case VaultAuthToken:
+		v.client.SetToken(v.config.Token)
+		return nil
+	case VaultAuthAppRole:
+		body = map[string]string{"role_id": v.config.RoleID, "secret_id": v.config.SecretID}
+	case VaultAuthKubernetes:
+		jwt, err := os.ReadFile(filepath.Clean(v.config.JWTPath))
+		if err != nil {
+			return fmt.Errorf("failed to read service account token: %w", err)
+		}
+		body = map[string]string{"role": v.config.Role, "jwt": strings.TrimSpace(string(jwt))}
+	}
+
+	secret, err := v.client.Write(ctx, path.Join("auth", v.config.AuthMount, "login"), body)
+	if err != nil {
+		return fmt.Errorf("failed to log in to Vault with %s auth: %w", v.config.AuthMethod, err)
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return fmt.Errorf("vault %s login returned no token", v.config.AuthMethod)
+	}
+	v.client.SetToken(secret.Auth.ClientToken)
+
+	if secret.Auth.Renewable && secret.Auth.LeaseDuration > 0 {
+		v.renew("token", secret.Auth.LeaseDuration, func(ctx context.Context, increment int) (int, error) {
+			renewed, err := v.client.Write(ctx, "auth/token/renew-self", map[string]int{"increment": increment})
+			if err != nil {
+				return 0, err
+			}
+			if renewed == nil || renewed.Auth == nil {
+				return 0, nil
+			}
+			return renewed.Auth.LeaseDuration, nil
+		})
+	}
+	return nil
+}
+
+// lookupCapabilities maps the capabilities of the token on the KV paths of ToolHive secrets
+// onto ProviderCapabilities. If they cannot be looked up, full access is assumed and
+// operations are left to fail with Vault's permission errors.
+func (v *VaultManager) lookupCapabilities(ctx context.Context) ProviderCapabilities {
+	dataPath, metadataPath := v.kvPaths("*")
+	secret, err := v.client.Write(ctx, "sys/capabilities-self", map[string][]string{
+		"paths": {dataPath, metadataPath},
+	})
+	if err != nil || secret == nil {
+		logger.Debugf("Failed to look up Vault token capabilities, assuming full access: %v", err)
+		return ProviderCapabilities{CanRead: true, CanWrite: true, CanDelete: true, CanList: true}
+	}
+
+	capabilitiesOf := func(p string) []string {
+		var result []string
+		rawCapabilities, _ := secret.Data[p].([]any)
+		for _, c := range rawCapabilities {
+			if s, ok := c.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	data := capabilitiesOf(dataPath)
+	metadata := capabilitiesOf(metadataPath)
+	has := func(capabilities []string, wanted ...string) bool {
+		if slices.Contains(capabilities, "root") {
+			return true
+		}
+		return slices.ContainsFunc(wanted, func(w string) bool { return slices.Contains(capabilities, w) })
+	}
+
+	return ProviderCapabilities{
+		CanRead:   has(data, "read"),
+		CanWrite:  has(data, "create", "update"),
+		CanDelete: has(metadata, "delete"),
+		CanList:   has(metadata, "list"),
+	}
+}
+
+// GetSecret retrieves a secret from Vault.
+func (v *VaultManager) GetSecret(ctx context.Context, name string) (string, error) {
+	secretPath, field, raw, err := v.parseName(name)
+	if err != nil {
+		return "", err
+	}
+
+	var data map[string]any
+	if raw {
+		data, err = v.readRaw(ctx, secretPath)
+	} else {
+		data, err = v.readKV(ctx, secretPath)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("secret not found: %s has no field %s", name, field)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+// GetSecretVersion returns the current version of a secret in the KV v2 engine.
+// Dynamic secrets read from other paths are not versioned.
+func (v *VaultManager) GetSecretVersion(ctx context.Context, name string) (string, error) {
+	secretPath, _, raw, err := v.parseName(name)
+	if err != nil || raw {
+		return "", err
+	}
+
+	dataPath, _ := v.kvPaths(secretPath)
+	secret, err := v.client.Read(ctx, dataPath)
+	if errors.Is(err, clients.ErrVaultNotFound) || (err == nil && secret == nil) {
+		return "", fmt.Errorf("secret not found: %s", secretPath)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %s: %w", secretPath, err)
+	}
+
+	metadata, _ := secret.Data["metadata"].(map[string]any)
+	if version, ok := metadata["version"].(float64); ok {
+		return strconv.FormatInt(int64(version), 10), nil
+	}
+	return "", nil
+}
+
+// SetSecret stores a secret in Vault. Other fields of the secret are preserved.
+func (v *VaultManager) SetSecret(ctx context.Context, name, value string) error {
+	secretPath, field, raw, err := v.parseName(name)
+	if err != nil {
+		return err
+	}
+	if raw {
+		return ErrVaultRawPathReadOnly
+	}
+
+	data, err := v.readKV(ctx, secretPath)
+	if err != nil && !errors.Is(err, clients.ErrVaultNotFound) {
+		return err
+	}
+	if data == nil {
+		data = map[string]any{}
+	}
+	data[field] = value
+
+	dataPath, _ := v.kvPaths(secretPath)
+	if _, err := v.client.Write(ctx, dataPath, map[string]any{"data": data}); err != nil {
+		return fmt.Errorf("failed to write secret %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteSecret deletes all versions of a secret from Vault.
+func (v *VaultManager) DeleteSecret(ctx context.Context, name string) error {
+	secretPath, field, raw, err := v.parseName(name)
+	if err != nil {
+		return err
+	}
+	if raw {
+		return ErrVaultRawPathReadOnly
+	}
+	if field != vaultDefaultSecretField {
+		return fmt.Errorf("cannot delete a single field of secret %s", name)
+	}
+
+	// Vault does not report missing secrets on delete
+	if _, err := v.readKV(ctx, secretPath); err != nil {
+		return err
+	}
+
+	_, metadataPath := v.kvPaths(secretPath)
+	if err := v.client.Delete(ctx, metadataPath); err != nil {
+		return fmt.Errorf("failed to delete secret %s: %w", name, err)
+	}
+	return nil
+}
+
+// ListSecrets lists the names of the secrets under the path prefix, including nested folders.
+func (v *VaultManager) ListSecrets(ctx context.Context) ([]SecretDescription, error) {
+	var secrets []SecretDescription
+	folders := []string{""}
+	for len(folders) > 0 {
+		folder := folders[0]
+		folders = folders[1:]
+
+		_, metadataPath := v.kvPaths(folder)
+		keys, err := v.client.List(ctx, metadataPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list secrets: %w", err)
+		}
+		for _, key := range keys {
+			if strings.HasSuffix(key, "/") {
+				folders = append(folders, folder+key)
+				continue
+			}
+			secrets = append(secrets, SecretDescription{Key: folder + key})
+		}
+	}
+
+	slices.SortFunc(secrets, func(a, b SecretDescription) int { return strings.Compare(a.Key, b.Key) })
+	return secrets, nil
+}
+
+// Cleanup stops renewing the leases of dynamic secrets and of the Vault token.
+// Secrets stored in Vault are left untouched.
+func (v *VaultManager) Cleanup() error {
+	v.once.Do(func() {
+		close(v.stop)
+	})
+	return nil
+}
+
+// Capabilities returns the capabilities of the Vault token on ToolHive secrets.
+func (v *VaultManager) Capabilities() ProviderCapabilities {
+	return v.capabilities
+}
+
+// parseName splits a secret name into its path and field, and reports whether the
+// path is read as-is rather than from the KV v2 engine.
+func (*VaultManager) parseName(name string) (secretPath, field string, raw bool, err error) {
+	if name == "" {
+		return "", "", false, errors.New("secret name cannot be empty")
+	}
+
+	secretPath, raw = strings.CutPrefix(name, VaultRawPathPrefix)
+	field = vaultDefaultSecretField
+	if p, f, ok := strings.Cut(secretPath, "#"); ok {
+		secretPath, field = p, f
+	}
+	secretPath = strings.Trim(secretPath, "/")
+	if secretPath == "" || field == "" || slices.Contains(strings.Split(secretPath, "/"), "..") {
+		return "", "", false, fmt.Errorf("invalid secret name: %s", name)
+	}
+	return secretPath, field, raw, nil
+}
+
+// kvPaths returns the data and metadata paths of a KV v2 secret.
+func (v *VaultManager) kvPaths(secretPath string) (string, string) {
+	return path.Join(v.config.KVMount, "data", v.config.PathPrefix, secretPath),
+		path.Join(v.config.KVMount, "metadata", v.config.PathPrefix, secretPath)
+}
+
+func (v *VaultManager) readKV(ctx context.Context, secretPath string) (map[string]any, error) {
+	dataPath, _ := v.kvPaths(secretPath)
+	secret, err := v.client.Read(ctx, dataPath)
+	if errors.Is(err, clients.ErrVaultNotFound) {
+		return nil, fmt.Errorf("secret not found: %s: %w", secretPath, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %s: %w", secretPath, err)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("secret not found: %s: %w", secretPath, clients.ErrVaultNotFound)
+	}
+
+	// KV v2 nests the secret data in data.data
+	data, _ := secret.Data["data"].(map[string]any)
+	if data == nil {
+		return nil, fmt.Errorf("secret not found: %s: %w", secretPath, clients.ErrVaultNotFound)
+	}
+	return data, nil
+}
+
+// readRaw reads a path as-is. Leased secrets are cached and renewed, so that reading
+// several fields of a dynamic secret, such as a username and password, uses one lease.
+func (v *VaultManager) readRaw(ctx context.Context, secretPath string) (map[string]any, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if secret, ok := v.leased[secretPath]; ok {
+		return secret.Data, nil
+	}
+
+	secret, err := v.client.Read(ctx, secretPath)
+	if errors.Is(err, clients.ErrVaultNotFound) {
+		return nil, fmt.Errorf("secret not found: %s: %w", secretPath, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %s: %w", secretPath, err)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("secret not found: %s: %w", secretPath, clients.ErrVaultNotFound)
+	}
+
+	if secret.LeaseID != "" {
+		v.leased[secretPath] = secret
+		if secret.Renewable && secret.LeaseDuration > 0 {
+			leaseID := secret.LeaseID
+			v.renew(leaseID, secret.LeaseDuration, func(ctx context.Context, increment int) (int, error) {
+				renewed, err := v.client.Write(ctx, "sys/leases/renew", map[string]any{
+					"lease_id":  leaseID,
+					"increment": increment,
+				})
+				if err != nil {
+					return 0, err
+				}
+				if renewed == nil {
+					return 0, nil
+				}
+				return renewed.LeaseDuration, nil
+			})
+		}
+	}
+	return secret.Data, nil
+}
+
+// renew renews a lease at two thirds of its duration, until renewal fails, the lease
+// is no longer extended, or Cleanup is called.
+func (v *VaultManager) renew(leaseID string, duration int, renewFn func(context.Context, int) (int, error)) {
+	go func() {
+		for {
+			timer := time.NewTimer(time.Duration(duration) * time.Second * 2 / 3)
+			select {
+			case <-v.stop:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			renewed, err := renewFn(ctx, duration)
+			cancel()
+			if err != nil {
+				logger.Warnf("Failed to renew Vault lease %s: %v", leaseID, err)
+				return
+			}
+			if renewed <= 0 {
+				logger.Debugf("Vault lease %s reached its maximum TTL", leaseID)
+				return
+			}
+			logger.Debugf("Renewed Vault lease %s for %ds", leaseID, renewed)
+			duration = renewed
+		}
+	}()
+}
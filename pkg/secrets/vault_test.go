@@ -0,0 +1,468 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stacklok/toolhive/pkg/env/mocks"
+)
+
+const devRootToken = "root"
+
+// fakeVault is a stand-in for a Vault server in dev mode, with a KV v2 engine mounted
+// at secret/, AppRole and Kubernetes auth methods, and a dynamic database secret.
+type fakeVault struct {
+	mu           sync.Mutex
+	tokens       map[string]bool
+	kv           map[string]map[string]any
+	versions     map[string]int
+	capabilities []string
+	leases       int
+	renewals     map[string]int
+	tokenRenewal int
+}
+
+func newFakeVault(t *testing.T) (*fakeVault, *httptest.Server) {
+	t.Helper()
+	f := &fakeVault{
+		tokens:       map[string]bool{devRootToken: true},
+		kv:           map[string]map[string]any{},
+		versions:     map[string]int{},
+		capabilities: []string{"root"},
+		renewals:     map[string]int{},
+	}
+	server := httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+func (f *fakeVault) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p := strings.TrimPrefix(r.URL.Path, "/v1/")
+	var body map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	if !strings.HasPrefix(p, "auth/approle/login") && !strings.HasPrefix(p, "auth/kubernetes/login") &&
+		!f.tokens[r.Header.Get("X-Vault-Token")] {
+		writeVault(w, http.StatusForbidden, map[string]any{"errors": []string{"permission denied"}})
+		return
+	}
+
+	switch {
+	case p == "auth/approle/login":
+		if body["role_id"] != "role" || body["secret_id"] != "secret" {
+			writeVault(w, http.StatusBadRequest, map[string]any{"errors": []string{"invalid role or secret ID"}})
+			return
+		}
+		f.tokens["approle-token"] = true
+		writeVault(w, http.StatusOK, map[string]any{"auth": map[string]any{
+			"client_token": "approle-token", "lease_duration": 1, "renewable": true,
+		}})
+	case p == "auth/kubernetes/login":
+		if body["role"] != "toolhive" || body["jwt"] != "service-account-jwt" {
+			writeVault(w, http.StatusForbidden, map[string]any{"errors": []string{"permission denied"}})
+			return
+		}
+		f.tokens["kubernetes-token"] = true
+		writeVault(w, http.StatusOK, map[string]any{"auth": map[string]any{"client_token": "kubernetes-token"}})
+	case p == "auth/token/renew-self":
+		f.tokenRenewal++
+		writeVault(w, http.StatusOK, map[string]any{"auth": map[string]any{
+			"client_token": r.Header.Get("X-Vault-Token"), "lease_duration": 1, "renewable": true,
+		}})
+	case p == "sys/capabilities-self":
+		data := map[string]any{}
+		for _, path := range body["paths"].([]any) {
+			data[path.(string)] = f.capabilities
+		}
+		writeVault(w, http.StatusOK, map[string]any{"data": data})
+	case p == "sys/leases/renew":
+		f.renewals[body["lease_id"].(string)]++
+		writeVault(w, http.StatusOK, map[string]any{"lease_id": body["lease_id"], "lease_duration": 1, "renewable": true})
+	case p == "database/creds/readonly":
+		f.leases++
+		writeVault(w, http.StatusOK, map[string]any{
+			"lease_id":       fmt.Sprintf("database/creds/readonly/%d", f.leases),
+			"lease_duration": 1,
+			"renewable":      true,
+			"data": map[string]any{
+				"username": fmt.Sprintf("v-toolhive-%d", f.leases),
+				"password": fmt.Sprintf("password-%d", f.leases),
+			},
+		})
+	case strings.HasPrefix(p, "secret/data/"):
+		f.serveKVData(w, r, strings.TrimPrefix(p, "secret/data/"), body)
+	case strings.HasPrefix(p, "secret/metadata/"):
+		f.serveKVMetadata(w, r, strings.TrimPrefix(p, "secret/metadata/"))
+	default:
+		writeVault(w, http.StatusNotFound, map[string]any{"errors": []string{}})
+	}
+}
+
+func (f *fakeVault) serveKVData(w http.ResponseWriter, r *http.Request, path string, body map[string]any) {
+	switch r.Method {
+	case http.MethodGet:
+		data, ok := f.kv[path]
+		if !ok {
+			writeVault(w, http.StatusNotFound, map[string]any{"errors": []string{}})
+			return
+		}
+		writeVault(w, http.StatusOK, map[string]any{"data": map[string]any{
+			"data": data, "metadata": map[string]any{"version": f.versions[path]},
+		}})
+	case http.MethodPost, http.MethodPut:
+		f.kv[path] = body["data"].(map[string]any)
+		f.versions[path]++
+		writeVault(w, http.StatusOK, map[string]any{"data": map[string]any{"version": f.versions[path]}})
+	}
+}
+
+func (f *fakeVault) serveKVMetadata(w http.ResponseWriter, r *http.Request, path string) {
+	switch r.Method {
+	case http.MethodDelete:
+		delete(f.kv, path)
+		delete(f.versions, path)
+		w.WriteHeader(http.StatusNoContent)
+	case "LIST":
+		prefix := strings.TrimSuffix(path, "/") + "/"
+		seen := map[string]bool{}
+		for k := range f.kv {
+			if rest, ok := strings.CutPrefix(k, prefix); ok {
+				if folder, _, nested := strings.Cut(rest, "/"); nested {
+					seen[folder+"/"] = true
+				} else {
+					seen[rest] = true
+				}
+			}
+		}
+		if len(seen) == 0 {
+			writeVault(w, http.StatusNotFound, map[string]any{"errors": []string{}})
+			return
+		}
+		keys := make([]string, 0, len(seen))
+		for k := range seen {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		writeVault(w, http.StatusOK, map[string]any{"data": map[string]any{"keys": keys}})
+	}
+}
+
+func writeVault(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func newTestVaultManager(t *testing.T, address string) *VaultManager {
+	t.Helper()
+	manager, err := NewVaultManagerWithConfig(context.Background(), &VaultConfig{
+		Address:    address,
+		AuthMethod: VaultAuthToken,
+		Token:      devRootToken,
+		KVMount:    "secret",
+		PathPrefix: "toolhive",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = manager.Cleanup() })
+	return manager
+}
+
+func TestNewVaultConfigFromEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		env           map[string]string
+		expected      *VaultConfig
+		errorContains string
+	}{
+		{
+			name: "token auth with defaults",
+			env:  map[string]string{VaultAddrEnvVar: "http://127.0.0.1:8200", VaultTokenEnvVar: "root"},
+			expected: &VaultConfig{
+				Address:    "http://127.0.0.1:8200",
+				AuthMethod: VaultAuthToken,
+				Token:      "root",
+				JWTPath:    defaultVaultJWTPath,
+				KVMount:    "secret",
+				PathPrefix: "toolhive",
+			},
+		},
+		{
+			name: "AppRole auth is inferred from the role ID",
+			env: map[string]string{
+				VaultAddrEnvVar:       "https://vault.example.com",
+				VaultRoleIDEnvVar:     "role",
+				VaultSecretIDEnvVar:   "secret",
+				VaultKVMountEnvVar:    "kv",
+				VaultPathPrefixEnvVar: "teams/search",
+			},
+			expected: &VaultConfig{
+				Address:    "https://vault.example.com",
+				AuthMethod: VaultAuthAppRole,
+				AuthMount:  "approle",
+				RoleID:     "role",
+				SecretID:   "secret",
+				JWTPath:    defaultVaultJWTPath,
+				KVMount:    "kv",
+				PathPrefix: "teams/search",
+			},
+		},
+		{
+			name: "Kubernetes auth with custom mount",
+			env: map[string]string{
+				VaultAddrEnvVar:       "https://vault.example.com",
+				VaultAuthMethodEnvVar: "kubernetes",
+				VaultAuthMountEnvVar:  "k8s-prod",
+				VaultRoleEnvVar:       "toolhive",
+			},
+			expected: &VaultConfig{
+				Address:    "https://vault.example.com",
+				AuthMethod: VaultAuthKubernetes,
+				AuthMount:  "k8s-prod",
+				Role:       "toolhive",
+				JWTPath:    defaultVaultJWTPath,
+				KVMount:    "secret",
+				PathPrefix: "toolhive",
+			},
+		},
+		{
+			name:          "missing address",
+			env:           map[string]string{VaultTokenEnvVar: "root"},
+			errorContains: "VAULT_ADDR is not set",
+		},
+		{
+			name:          "missing credentials",
+			env:           map[string]string{VaultAddrEnvVar: "http://127.0.0.1:8200"},
+			errorContains: "VAULT_TOKEN is not set",
+		},
+		{
+			name: "AppRole without secret ID",
+			env: map[string]string{
+				VaultAddrEnvVar:   "http://127.0.0.1:8200",
+				VaultRoleIDEnvVar: "role",
+			},
+			errorContains: "must be set for AppRole auth",
+		},
+		{
+			name: "invalid auth method",
+			env: map[string]string{
+				VaultAddrEnvVar:       "http://127.0.0.1:8200",
+				VaultAuthMethodEnvVar: "userpass",
+			},
+			errorContains: "invalid TOOLHIVE_VAULT_AUTH_METHOD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			envReader := mocks.NewMockReader(ctrl)
+			envReader.EXPECT().Getenv(gomock.Any()).DoAndReturn(func(key string) string {
+				return tt.env[key]
+			}).AnyTimes()
+
+			config, err := NewVaultConfigFromEnv(envReader)
+			if tt.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, config)
+		})
+	}
+}
+
+func TestVaultManagerKV(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fake, server := newFakeVault(t)
+	manager := newTestVaultManager(t, server.URL)
+
+	assert.Equal(t, ProviderCapabilities{CanRead: true, CanWrite: true, CanDelete: true, CanList: true},
+		manager.Capabilities())
+
+	// Empty engine
+	secrets, err := manager.ListSecrets(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, secrets)
+	_, err = manager.GetSecret(ctx, "github")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "secret not found")
+
+	require.NoError(t, manager.SetSecret(ctx, "github", "ghp_token"))
+	require.NoError(t, manager.SetSecret(ctx, "teams/search/db", "password"))
+	// Setting another field preserves the value
+	require.NoError(t, manager.SetSecret(ctx, "teams/search/db#username", "search"))
+
+	assert.Equal(t, map[string]any{"value": "password", "username": "search"}, fake.kv["toolhive/teams/search/db"])
+
+	value, err := manager.GetSecret(ctx, "github")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_token", value)
+	value, err = manager.GetSecret(ctx, "teams/search/db#username")
+	require.NoError(t, err)
+	assert.Equal(t, "search", value)
+	_, err = manager.GetSecret(ctx, "github#missing")
+	assert.ErrorContains(t, err, "has no field missing")
+
+	version, err := GetSecretVersion(ctx, manager, "teams/search/db#username")
+	require.NoError(t, err)
+	assert.Equal(t, "2", version)
+	version, err = GetSecretVersion(ctx, NewFallbackProvider(manager), "github")
+	require.NoError(t, err)
+	assert.Equal(t, "1", version)
+	version, err = GetSecretVersion(ctx, manager, "vault:database/creds/readonly#password")
+	require.NoError(t, err)
+	assert.Empty(t, version)
+
+	secrets, err = manager.ListSecrets(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []SecretDescription{{Key: "github"}, {Key: "teams/search/db"}}, secrets)
+
+	require.NoError(t, manager.DeleteSecret(ctx, "github"))
+	_, err = manager.GetSecret(ctx, "github")
+	assert.ErrorContains(t, err, "secret not found")
+	assert.ErrorContains(t, manager.DeleteSecret(ctx, "github"), "secret not found")
+	assert.ErrorContains(t, manager.DeleteSecret(ctx, "teams/search/db#username"), "cannot delete a single field")
+
+	// Invalid names and read-only raw paths
+	assert.Error(t, manager.SetSecret(ctx, "", "value"))
+	assert.ErrorContains(t, manager.SetSecret(ctx, "../sys/policy", "value"), "invalid secret name")
+	assert.ErrorIs(t, manager.SetSecret(ctx, "vault:database/creds/readonly#password", "value"), ErrVaultRawPathReadOnly)
+	assert.ErrorIs(t, manager.DeleteSecret(ctx, "vault:database/creds/readonly"), ErrVaultRawPathReadOnly)
+}
+
+func TestVaultManagerCapabilities(t *testing.T) {
+	t.Parallel()
+
+	fake, server := newFakeVault(t)
+	fake.capabilities = []string{"read", "list"}
+
+	manager := newTestVaultManager(t, server.URL)
+	capabilities := manager.Capabilities()
+	assert.True(t, capabilities.CanRead)
+	assert.True(t, capabilities.CanList)
+	assert.False(t, capabilities.CanWrite)
+	assert.False(t, capabilities.CanDelete)
+
+	result := validateVaultProvider(context.Background(), manager, &SetupResult{ProviderType: VaultType})
+	assert.True(t, result.Success, result.Error)
+
+	fake.capabilities = []string{"deny"}
+	manager = newTestVaultManager(t, server.URL)
+	result = validateVaultProvider(context.Background(), manager, &SetupResult{ProviderType: VaultType})
+	assert.False(t, result.Success)
+	assert.ErrorContains(t, result.Error, "cannot read ToolHive secrets")
+}
+
+func TestVaultManagerAuth(t *testing.T) {
+	t.Parallel()
+
+	fake, server := newFakeVault(t)
+	fake.kv["toolhive/github"] = map[string]any{"value": "ghp_token"}
+
+	jwtPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(jwtPath, []byte("service-account-jwt\n"), 0600))
+
+	tests := []struct {
+		name          string
+		config        VaultConfig
+		errorContains string
+	}{
+		{
+			name:   "AppRole",
+			config: VaultConfig{AuthMethod: VaultAuthAppRole, AuthMount: "approle", RoleID: "role", SecretID: "secret"},
+		},
+		{
+			name:   "Kubernetes",
+			config: VaultConfig{AuthMethod: VaultAuthKubernetes, AuthMount: "kubernetes", Role: "toolhive", JWTPath: jwtPath},
+		},
+		{
+			name:          "invalid token",
+			config:        VaultConfig{AuthMethod: VaultAuthToken, Token: "expired"},
+			errorContains: "permission denied",
+		},
+		{
+			name:          "invalid AppRole credentials",
+			config:        VaultConfig{AuthMethod: VaultAuthAppRole, AuthMount: "approle", RoleID: "role", SecretID: "wrong"},
+			errorContains: "failed to log in to Vault with approle auth",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := tt.config
+			config.Address = server.URL
+			config.KVMount = "secret"
+			config.PathPrefix = "toolhive"
+
+			manager, err := NewVaultManagerWithConfig(context.Background(), &config)
+			if err == nil {
+				t.Cleanup(func() { _ = manager.Cleanup() })
+				_, err = manager.GetSecret(context.Background(), "github")
+			}
+			if tt.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestVaultManagerDynamicSecrets(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fake, server := newFakeVault(t)
+	manager := newTestVaultManager(t, server.URL)
+
+	// Fields of a dynamic secret come from the same lease
+	username, err := manager.GetSecret(ctx, "vault:database/creds/readonly#username")
+	require.NoError(t, err)
+	password, err := manager.GetSecret(ctx, "vault:database/creds/readonly#password")
+	require.NoError(t, err)
+	assert.Equal(t, "v-toolhive-1", username)
+	assert.Equal(t, "password-1", password)
+
+	_, err = manager.GetSecret(ctx, "vault:database/creds/missing#password")
+	assert.ErrorContains(t, err, "secret not found")
+
+	// The lease is renewed until cleanup
+	renewals := func() int {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		return fake.renewals["database/creds/readonly/1"]
+	}
+	require.Eventually(t, func() bool { return renewals() >= 2 }, 5*time.Second, 50*time.Millisecond)
+
+	require.NoError(t, manager.Cleanup())
+	stopped := renewals()
+	time.Sleep(1500 * time.Millisecond)
+	assert.LessOrEqual(t, renewals(), stopped+1)
+}
@@ -10,6 +10,12 @@ const (
 
 	// GroupConfigsDir is the directory name for storing group configurations
 	GroupConfigsDir = "groups"
+
+	// SBOMsDir is the directory name for storing the SBOMs of workload images
+	SBOMsDir = "sboms"
+
+	// PulledImagesDir is the directory name for storing the records of pulled images
+	PulledImagesDir = "images"
 )
 
 // NewRunConfigStore creates a store for run configuration state
@@ -37,3 +43,19 @@ func NewGroupConfigStoreWithDetector(appName string) (Store, error) {
 	}
 	return NewLocalStore(appName, GroupConfigsDir)
 }
+
+// NewSBOMStore creates a store for the SBOMs of workload images
+func NewSBOMStore(appName string) (Store, error) {
+	if runtime.IsKubernetesRuntime() {
+		return NewKubernetesStore(), nil
+	}
+	return NewLocalStore(appName, SBOMsDir)
+}
+
+// NewPulledImageStore creates a store for the records of the images pulled by ToolHive
+func NewPulledImageStore(appName string) (Store, error) {
+	if runtime.IsKubernetesRuntime() {
+		return NewKubernetesStore(), nil
+	}
+	return NewLocalStore(appName, PulledImagesDir)
+}
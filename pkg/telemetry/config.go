@@ -6,11 +6,17 @@ import (
 	"fmt"
 	"net/http"
 
+	"go.opentelemetry.io/contrib/bridges/otelzap"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/log"
+	logglobal "go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
+	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/telemetry/providers"
 	"github.com/stacklok/toolhive/pkg/transport/types"
 	"github.com/stacklok/toolhive/pkg/versions"
@@ -36,6 +42,14 @@ type Config struct {
 	// This is independent of EnablePrometheusMetricsPath
 	MetricsEnabled bool `json:"metricsEnabled"`
 
+	// LogsEnabled controls whether ToolHive logs and audit events are exported via OTLP
+	// Log records emitted during a traced request carry its trace and span IDs
+	LogsEnabled bool `json:"logsEnabled,omitempty"`
+
+	// ExemplarsEnabled controls whether metrics record exemplars linking
+	// measurements, such as a slow request duration bucket, to sampled traces
+	ExemplarsEnabled bool `json:"exemplarsEnabled,omitempty"`
+
 	// SamplingRate is the trace sampling rate (0.0-1.0)
 	// Only used when TracingEnabled is true
 	SamplingRate float64 `json:"samplingRate"`
@@ -66,6 +80,8 @@ func DefaultConfig() Config {
 		ServiceVersion:              versionInfo.Version,
 		TracingEnabled:              true, // Enable tracing by default if endpoint is configured
 		MetricsEnabled:              true, // Enable metrics by default if endpoint is configured
+		LogsEnabled:                 false,
+		ExemplarsEnabled:            false,
 		SamplingRate:                0.05, // 5% sampling by default
 		Headers:                     make(map[string]string),
 		Insecure:                    false,
@@ -79,6 +95,7 @@ type Provider struct {
 	config            Config
 	tracerProvider    trace.TracerProvider
 	meterProvider     metric.MeterProvider
+	loggerProvider    log.LoggerProvider
 	prometheusHandler http.Handler
 	shutdown          func(context.Context) error
 }
@@ -98,6 +115,8 @@ func NewProvider(ctx context.Context, config Config) (*Provider, error) {
 		providers.WithInsecure(config.Insecure),
 		providers.WithTracingEnabled(config.TracingEnabled),
 		providers.WithMetricsEnabled(config.MetricsEnabled),
+		providers.WithLogsEnabled(config.LogsEnabled),
+		providers.WithExemplarsEnabled(config.ExemplarsEnabled),
 		providers.WithSamplingRate(config.SamplingRate),
 		providers.WithEnablePrometheusMetricsPath(config.EnablePrometheusMetricsPath),
 	}
@@ -114,24 +133,47 @@ func NewProvider(ctx context.Context, config Config) (*Provider, error) {
 func setGlobalProvidersAndReturn(telemetryProviders *providers.CompositeProvider, config Config) (*Provider, error) {
 	tracingProvider := telemetryProviders.TracerProvider()
 	meterProvider := telemetryProviders.MeterProvider()
+	loggerProvider := telemetryProviders.LoggerProvider()
 
 	// set the global providers for OTEL
 	otel.SetTracerProvider(tracingProvider)
 	otel.SetMeterProvider(meterProvider)
+	logglobal.SetLoggerProvider(loggerProvider)
 	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
 		propagation.TraceContext{},
 		propagation.Baggage{},
 	))
 
+	shutdown := telemetryProviders.Shutdown
+	if config.Endpoint != "" && config.LogsEnabled {
+		restoreLogger := exportLogs(loggerProvider)
+		shutdown = func(ctx context.Context) error {
+			// Stop teeing logs into the provider before it is shut down
+			restoreLogger()
+			return telemetryProviders.Shutdown(ctx)
+		}
+	}
+
 	return &Provider{
 		config:            config,
 		tracerProvider:    tracingProvider,
 		meterProvider:     meterProvider,
+		loggerProvider:    loggerProvider,
 		prometheusHandler: telemetryProviders.PrometheusHandler(),
-		shutdown:          telemetryProviders.Shutdown,
+		shutdown:          shutdown,
 	}, nil
 }
 
+// exportLogs tees the ToolHive logger into the logger provider, at the level of the
+// existing logger. It returns a function which stops exporting logs.
+func exportLogs(loggerProvider log.LoggerProvider) func() {
+	var core zapcore.Core = otelzap.NewCore("github.com/stacklok/toolhive/pkg/logger", otelzap.WithLoggerProvider(loggerProvider))
+	if leveled, err := zapcore.NewIncreaseLevelCore(core, zap.L().Level()); err == nil {
+		core = leveled
+	}
+	return logger.AddCore(core)
+}
+
 // Middleware returns an HTTP middleware that instruments requests with OpenTelemetry.
 // serverName is the name of the MCP server (e.g., "github", "fetch")
 // transport is the backend transport type ("stdio" or "sse")
@@ -157,6 +199,11 @@ func (p *Provider) MeterProvider() metric.MeterProvider {
 	return p.meterProvider
 }
 
+// LoggerProvider returns the configured logger provider.
+func (p *Provider) LoggerProvider() log.LoggerProvider {
+	return p.loggerProvider
+}
+
 // PrometheusHandler returns the Prometheus metrics handler if configured.
 // Returns nil if no metrics port is configured.
 func (p *Provider) PrometheusHandler() http.Handler {
@@ -165,10 +212,10 @@ func (p *Provider) PrometheusHandler() http.Handler {
 
 // validateOtelConfig validates the otel configuration
 func validateOtelConfig(config Config) error {
-	// If OTLP endpoint is configured but both tracing and metrics are disabled, that's an error
-	if config.Endpoint != "" && !config.TracingEnabled && !config.MetricsEnabled {
-		return fmt.Errorf("OTLP endpoint is configured but both tracing and metrics are disabled; " +
-			"either enable tracing or metrics, or remove the endpoint")
+	// If OTLP endpoint is configured but tracing, metrics and logs are all disabled, that's an error
+	if config.Endpoint != "" && !config.TracingEnabled && !config.MetricsEnabled && !config.LogsEnabled {
+		return fmt.Errorf("OTLP endpoint is configured but tracing, metrics and logs are all disabled; " +
+			"either enable tracing, metrics or logs, or remove the endpoint")
 	}
 	return nil
 }
@@ -25,6 +25,7 @@ func TestTelemetryProviderValidation(t *testing.T) {
 		errorContains           string
 		expectedTracerType      string
 		expectedMeterType       string
+		expectedLoggerType      string
 		expectPrometheusHandler bool
 		description             string
 	}{
@@ -55,7 +56,7 @@ func TestTelemetryProviderValidation(t *testing.T) {
 				EnablePrometheusMetricsPath: false,
 			},
 			expectError:   true,
-			errorContains: "OTLP endpoint is configured but both tracing and metrics are disabled",
+			errorContains: "OTLP endpoint is configured but tracing, metrics and logs are all disabled",
 			description:   "Should error when OTLP endpoint is configured but not used",
 		},
 		{
@@ -106,6 +107,21 @@ func TestTelemetryProviderValidation(t *testing.T) {
 			expectPrometheusHandler: true,
 			description:             "Should create SDK tracer and SDK meter with OTLP readers",
 		},
+		{
+			name: "OTLP endpoint with only logs enabled - should configure OTLP logs only",
+			config: Config{
+				ServiceName:    "test-service",
+				ServiceVersion: "1.0.0",
+				Endpoint:       "localhost:4318",
+				LogsEnabled:    true,
+			},
+			expectError:             false,
+			expectedTracerType:      "trace/noop.TracerProvider",
+			expectedMeterType:       "metric/noop.MeterProvider",
+			expectedLoggerType:      "sdk/log.LoggerProvider",
+			expectPrometheusHandler: false,
+			description:             "Should create no-op tracer and meter with an SDK logger provider",
+		},
 	}
 
 	for _, tt := range tests {
@@ -139,6 +155,12 @@ func TestTelemetryProviderValidation(t *testing.T) {
 			assert.Equal(t, tt.expectedMeterType, actualMeterType,
 				"Meter provider type should match expected for %s", tt.name)
 
+			// Validate logger provider type
+			if tt.expectedLoggerType != "" {
+				assert.Equal(t, tt.expectedLoggerType, getProviderTypeName(provider.LoggerProvider()),
+					"Logger provider type should match expected for %s", tt.name)
+			}
+
 			// Validate Prometheus handler presence
 			prometheusHandler := provider.PrometheusHandler()
 			if tt.expectPrometheusHandler {
@@ -0,0 +1,108 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/stacklok/toolhive/pkg/telemetry/providers"
+)
+
+const (
+	// TraceparentEnvVar is the environment variable carrying the W3C trace context
+	// of a workload lifecycle operation into a child process.
+	TraceparentEnvVar = "TRACEPARENT"
+
+	// TracestateEnvVar is the environment variable carrying the W3C trace state
+	// of a workload lifecycle operation into a child process.
+	TracestateEnvVar = "TRACESTATE"
+)
+
+// NewTracingProvider creates a provider which only exports traces, and installs it as
+// the global tracer provider. It is used to trace workload lifecycle operations, such as
+// image retrieval and container creation, which happen before the telemetry middleware
+// of the proxy is created. Global meter and logger providers are left untouched.
+// If config is nil, or tracing is not configured, the returned provider does nothing.
+func NewTracingProvider(ctx context.Context, config *Config) (*Provider, error) {
+	if config == nil || config.Endpoint == "" || !config.TracingEnabled {
+		return &Provider{}, nil
+	}
+
+	telemetryProviders, err := providers.NewCompositeProvider(ctx,
+		providers.WithServiceName(config.ServiceName),
+		providers.WithServiceVersion(config.ServiceVersion),
+		providers.WithOTLPEndpoint(config.Endpoint),
+		providers.WithHeaders(config.Headers),
+		providers.WithInsecure(config.Insecure),
+		providers.WithTracingEnabled(true),
+		providers.WithSamplingRate(config.SamplingRate),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tracing provider: %w", err)
+	}
+
+	otel.SetTracerProvider(telemetryProviders.TracerProvider())
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return &Provider{
+		config:         *config,
+		tracerProvider: telemetryProviders.TracerProvider(),
+		shutdown:       telemetryProviders.Shutdown,
+	}, nil
+}
+
+// StartSpan starts a span of a workload lifecycle operation with the global tracer provider.
+// The tracer is looked up on every call, so that spans are exported by the provider which is
+// installed at the time the operation starts.
+func StartSpan(
+	ctx context.Context, tracerName, spanName string, attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, spanName, trace.WithAttributes(attrs...))
+}
+
+// EndSpan ends the span of a workload lifecycle operation, recording err as its failure.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
+
+// InjectTraceContextEnv appends the W3C trace context of ctx to env, so that a child
+// process can continue the trace with ExtractTraceContextEnv.
+func InjectTraceContextEnv(ctx context.Context, env []string) []string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	if traceparent := carrier.Get("traceparent"); traceparent != "" {
+		env = append(env, TraceparentEnvVar+"="+traceparent)
+	}
+	if tracestate := carrier.Get("tracestate"); tracestate != "" {
+		env = append(env, TracestateEnvVar+"="+tracestate)
+	}
+	return env
+}
+
+// ExtractTraceContextEnv returns ctx with the remote span context carried by the
+// environment of the process, if ctx does not carry a span context already.
+func ExtractTraceContextEnv(ctx context.Context) context.Context {
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	carrier := propagation.MapCarrier{
+		"traceparent": os.Getenv(TraceparentEnvVar),
+		"tracestate":  os.Getenv(TracestateEnvVar),
+	}
+	return propagation.TraceContext{}.Extract(ctx, carrier)
+}
@@ -0,0 +1,88 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewTracingProviderWithoutTracing(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config *Config
+	}{
+		{name: "nil config"},
+		{name: "no endpoint", config: &Config{TracingEnabled: true}},
+		{name: "tracing disabled", config: &Config{Endpoint: "localhost:4318", MetricsEnabled: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := NewTracingProvider(context.Background(), tt.config)
+			require.NoError(t, err)
+			assert.Nil(t, provider.TracerProvider())
+			assert.NoError(t, provider.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestEndSpan(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, failed := tracer.Start(context.Background(), "failed")
+	EndSpan(failed, errors.New("image not found"))
+	_, succeeded := tracer.Start(context.Background(), "succeeded")
+	EndSpan(succeeded, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "image not found", spans[0].Status().Description)
+	require.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, "exception", spans[0].Events()[0].Name)
+
+	assert.Equal(t, codes.Ok, spans[1].Status().Code)
+	assert.Empty(t, spans[1].Events())
+}
+
+func TestTraceContextEnv(t *testing.T) {
+	sc := testSpanContext(t)
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	env := InjectTraceContextEnv(ctx, []string{"TOOLHIVE_DETACHED=1"})
+	assert.Equal(t, []string{
+		"TOOLHIVE_DETACHED=1",
+		"TRACEPARENT=00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}, env)
+
+	// Contexts without a span context leave the environment untouched
+	assert.Equal(t, []string{"TOOLHIVE_DETACHED=1"},
+		InjectTraceContextEnv(context.Background(), []string{"TOOLHIVE_DETACHED=1"}))
+
+	t.Setenv(TraceparentEnvVar, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	extracted := trace.SpanContextFromContext(ExtractTraceContextEnv(context.Background()))
+	assert.True(t, extracted.IsRemote())
+	assert.Equal(t, sc.TraceID(), extracted.TraceID())
+	assert.Equal(t, sc.SpanID(), extracted.SpanID())
+
+	// A span context of the context takes precedence over the environment
+	local := sc.WithTraceID(trace.TraceID{0x01})
+	localCtx := trace.ContextWithSpanContext(context.Background(), local)
+	assert.Equal(t, local, trace.SpanContextFromContext(ExtractTraceContextEnv(localCtx)))
+}
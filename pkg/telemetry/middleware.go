@@ -1,9 +1,11 @@
 package telemetry
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strconv"
@@ -17,6 +19,7 @@ import (
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/jsonrpc2"
 
 	"github.com/stacklok/toolhive/pkg/logger"
 	mcpparser "github.com/stacklok/toolhive/pkg/mcp"
@@ -26,6 +29,10 @@ import (
 const (
 	// instrumentationName is the name of this instrumentation package
 	instrumentationName = "github.com/stacklok/toolhive/pkg/telemetry"
+
+	// maxCapturedResponseBytes limits how much of a JSON response is buffered
+	// to look up a trace context reported back by the MCP server
+	maxCapturedResponseBytes = 1 << 20
 )
 
 // HTTPMiddleware provides OpenTelemetry instrumentation for HTTP requests.
@@ -156,6 +163,14 @@ func (m *HTTPMiddleware) Handler(next http.Handler) http.Handler {
 		// Add environment variables as attributes
 		m.addEnvironmentAttributes(span)
 
+		// Propagate the trace context inside the MCP message itself, as stdio
+		// servers never see the HTTP headers
+		parsedMCP := mcpparser.GetParsedMCPRequest(ctx)
+		if parsedMCP != nil && parsedMCP.IsRequest && !parsedMCP.IsBatch {
+			injectTraceContextIntoRequest(ctx, r)
+			rw.captured = &bytes.Buffer{}
+		}
+
 		// Record request start time
 		startTime := time.Now()
 
@@ -164,6 +179,7 @@ func (m *HTTPMiddleware) Handler(next http.Handler) http.Handler {
 
 		// Record completion metrics and finalize span
 		duration := time.Since(startTime)
+		linkServerSpan(span, rw)
 		m.finalizeSpan(span, rw, duration)
 		m.recordMetrics(ctx, r, rw, duration)
 	})
@@ -413,11 +429,68 @@ func (*HTTPMiddleware) finalizeSpan(span trace.Span, rw *responseWriter, duratio
 	}
 }
 
+// injectTraceContextIntoRequest rewrites the request body so that params._meta
+// carries the trace context of ctx.
+func injectTraceContextIntoRequest(ctx context.Context, r *http.Request) {
+	if r.Body == nil {
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		logger.Debugf("Failed to read request body for trace propagation: %v", err)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		return
+	}
+
+	body = injectTraceContextIntoBody(ctx, body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	if r.Header.Get("Content-Length") != "" {
+		r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+}
+
+// linkServerSpan links the proxy span to the span reported by the MCP server in
+// the _meta of a JSON response, if the server reported one.
+func linkServerSpan(span trace.Span, rw *responseWriter) {
+	if rw.captured == nil || rw.captured.Len() == 0 || rw.truncated {
+		return
+	}
+	if !strings.HasPrefix(rw.Header().Get("Content-Type"), "application/json") {
+		return
+	}
+
+	msg, err := jsonrpc2.DecodeMessage(rw.captured.Bytes())
+	if err != nil {
+		return
+	}
+
+	serverSpan := RemoteSpanContext(msg)
+	if !serverSpan.IsValid() || serverSpan.Equal(span.SpanContext()) {
+		return
+	}
+
+	span.AddLink(trace.Link{
+		SpanContext: serverSpan,
+		Attributes:  []attribute.KeyValue{attribute.String("mcp.link.type", "server_span")},
+	})
+	span.SetAttributes(
+		attribute.String("mcp.server.trace_id", serverSpan.TraceID().String()),
+		attribute.String("mcp.server.span_id", serverSpan.SpanID().String()),
+	)
+}
+
 // responseWriter wraps http.ResponseWriter to capture response details.
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode   int
 	bytesWritten int64
+
+	// captured holds the response body when it should be inspected for a
+	// server-reported trace context; nil disables capturing
+	captured  *bytes.Buffer
+	truncated bool
 }
 
 // WriteHeader captures the status code with panic protection.
@@ -437,6 +510,13 @@ func (rw *responseWriter) WriteHeader(statusCode int) {
 func (rw *responseWriter) Write(data []byte) (int, error) {
 	n, err := rw.ResponseWriter.Write(data)
 	rw.bytesWritten += int64(n)
+	if rw.captured != nil && !rw.truncated {
+		if rw.captured.Len()+n > maxCapturedResponseBytes {
+			rw.truncated = true
+		} else {
+			rw.captured.Write(data[:n])
+		}
+	}
 	return n, err
 }
 
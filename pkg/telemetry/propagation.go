@@ -0,0 +1,192 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/jsonrpc2"
+)
+
+const (
+	// MetaKey is the reserved MCP field used to carry protocol-level metadata
+	// in request params and response results.
+	MetaKey = "_meta"
+
+	// TraceparentKey is the W3C trace context key carried inside _meta.
+	TraceparentKey = "traceparent"
+
+	// TracestateKey is the W3C trace state key carried inside _meta.
+	TracestateKey = "tracestate"
+)
+
+// MetaCarrier adapts an MCP _meta object to the OpenTelemetry TextMapCarrier interface.
+// Only string values are considered when reading from the carrier.
+type MetaCarrier map[string]any
+
+// Get returns the string value associated with the passed key.
+func (c MetaCarrier) Get(key string) string {
+	if v, ok := c[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// Set stores the key-value pair.
+func (c MetaCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// Keys lists the keys stored in this carrier.
+func (c MetaCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// InjectTraceContext writes the W3C trace context of ctx into params._meta of a
+// JSON-RPC request, so that MCP servers without HTTP headers (e.g. stdio) can
+// continue the trace. Responses, requests with non-object params and contexts
+// without a valid span are left untouched.
+func InjectTraceContext(ctx context.Context, msg jsonrpc2.Message) error {
+	req, ok := msg.(*jsonrpc2.Request)
+	if !ok {
+		return nil
+	}
+
+	params, err := injectIntoObject(ctx, req.Params)
+	if err != nil {
+		return err
+	}
+	if params != nil {
+		req.Params = params
+	}
+	return nil
+}
+
+// ExtractTraceContext reads a W3C trace context from the _meta object of a
+// JSON-RPC message. For requests the params are inspected, for responses the
+// result. The returned context carries the remote span context, if any.
+func ExtractTraceContext(ctx context.Context, msg jsonrpc2.Message) context.Context {
+	meta := metaFromObject(messageObject(msg))
+	if meta == nil {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, meta)
+}
+
+// RemoteSpanContext returns the span context reported in the _meta object of a
+// JSON-RPC message. The returned span context is invalid if none is present.
+func RemoteSpanContext(msg jsonrpc2.Message) trace.SpanContext {
+	meta := metaFromObject(messageObject(msg))
+	if meta == nil {
+		return trace.SpanContext{}
+	}
+	// Use the W3C propagator directly so the result does not depend on
+	// whether the global propagator has been configured.
+	ctx := propagation.TraceContext{}.Extract(context.Background(), meta)
+	return trace.SpanContextFromContext(ctx)
+}
+
+// injectTraceContextIntoBody rewrites a single JSON-RPC request body so that its
+// params._meta carries the trace context of ctx. The original body is returned
+// when nothing was injected.
+func injectTraceContextIntoBody(ctx context.Context, body []byte) []byte {
+	msg, err := jsonrpc2.DecodeMessage(body)
+	if err != nil {
+		return body
+	}
+
+	req, ok := msg.(*jsonrpc2.Request)
+	if !ok {
+		return body
+	}
+
+	params, err := injectIntoObject(ctx, req.Params)
+	if err != nil || params == nil {
+		return body
+	}
+	req.Params = params
+
+	encoded, err := jsonrpc2.EncodeMessage(req)
+	if err != nil {
+		return body
+	}
+	return encoded
+}
+
+// injectIntoObject injects the trace context of ctx into the _meta field of a raw
+// JSON object. It returns nil when there is nothing to inject.
+func injectIntoObject(ctx context.Context, raw json.RawMessage) (json.RawMessage, error) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return nil, nil
+	}
+
+	obj := map[string]json.RawMessage{}
+	if len(raw) > 0 && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			// Positional (array) params cannot carry _meta
+			return nil, nil
+		}
+	}
+
+	meta := MetaCarrier{}
+	if existing, ok := obj[MetaKey]; ok {
+		if err := json.Unmarshal(existing, &meta); err != nil {
+			return nil, fmt.Errorf("invalid %s object: %w", MetaKey, err)
+		}
+		if meta == nil {
+			meta = MetaCarrier{}
+		}
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, meta)
+	if meta.Get(TraceparentKey) == "" {
+		// The global propagator is not configured for W3C trace context
+		propagation.TraceContext{}.Inject(ctx, meta)
+	}
+
+	encodedMeta, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", MetaKey, err)
+	}
+	obj[MetaKey] = encodedMeta
+
+	encoded, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode params: %w", err)
+	}
+	return encoded, nil
+}
+
+// messageObject returns the raw params of a request or the raw result of a response.
+func messageObject(msg jsonrpc2.Message) json.RawMessage {
+	switch m := msg.(type) {
+	case *jsonrpc2.Request:
+		return m.Params
+	case *jsonrpc2.Response:
+		return m.Result
+	default:
+		return nil
+	}
+}
+
+// metaFromObject returns the _meta object of a raw JSON object, or nil.
+func metaFromObject(raw json.RawMessage) MetaCarrier {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var obj struct {
+		Meta MetaCarrier `json:"_meta"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil
+	}
+	return obj.Meta
+}
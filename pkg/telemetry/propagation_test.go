@@ -0,0 +1,180 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/jsonrpc2"
+
+	mcpparser "github.com/stacklok/toolhive/pkg/mcp"
+)
+
+func testSpanContext(t *testing.T) trace.SpanContext {
+	t.Helper()
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+}
+
+func TestInjectTraceContext(t *testing.T) {
+	t.Parallel()
+
+	sc := testSpanContext(t)
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	wantTraceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	tests := []struct {
+		name       string
+		params     json.RawMessage
+		ctx        context.Context
+		wantMeta   map[string]any
+		wantParams string
+	}{
+		{
+			name:     "object params",
+			params:   json.RawMessage(`{"name":"fetch"}`),
+			ctx:      ctx,
+			wantMeta: map[string]any{TraceparentKey: wantTraceparent},
+		},
+		{
+			name:     "no params",
+			ctx:      ctx,
+			wantMeta: map[string]any{TraceparentKey: wantTraceparent},
+		},
+		{
+			name:     "existing meta is preserved",
+			params:   json.RawMessage(`{"name":"fetch","_meta":{"progressToken":"abc"}}`),
+			ctx:      ctx,
+			wantMeta: map[string]any{TraceparentKey: wantTraceparent, "progressToken": "abc"},
+		},
+		{
+			name:       "positional params are untouched",
+			params:     json.RawMessage(`["a","b"]`),
+			ctx:        ctx,
+			wantParams: `["a","b"]`,
+		},
+		{
+			name:       "no span in context",
+			params:     json.RawMessage(`{"name":"fetch"}`),
+			ctx:        context.Background(),
+			wantParams: `{"name":"fetch"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := jsonrpc2.NewCall(jsonrpc2.Int64ID(1), "tools/call", nil)
+			require.NoError(t, err)
+			req.Params = tt.params
+
+			require.NoError(t, InjectTraceContext(tt.ctx, req))
+
+			if tt.wantParams != "" {
+				assert.JSONEq(t, tt.wantParams, string(req.Params))
+				return
+			}
+
+			var params map[string]any
+			require.NoError(t, json.Unmarshal(req.Params, &params))
+			assert.Equal(t, tt.wantMeta, params[MetaKey])
+		})
+	}
+}
+
+func TestInjectTraceContext_IgnoresResponses(t *testing.T) {
+	t.Parallel()
+
+	ctx := trace.ContextWithSpanContext(context.Background(), testSpanContext(t))
+	resp, err := jsonrpc2.NewResponse(jsonrpc2.Int64ID(1), map[string]any{"ok": true}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, InjectTraceContext(ctx, resp))
+	assert.JSONEq(t, `{"ok":true}`, string(resp.Result))
+}
+
+func TestRemoteSpanContext(t *testing.T) {
+	t.Parallel()
+
+	resp, err := jsonrpc2.NewResponse(jsonrpc2.Int64ID(1), map[string]any{
+		"content": []any{},
+		MetaKey: map[string]any{
+			TraceparentKey: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	sc := RemoteSpanContext(resp)
+	assert.True(t, sc.IsValid())
+	assert.True(t, sc.IsRemote())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", sc.SpanID().String())
+
+	plain, err := jsonrpc2.NewResponse(jsonrpc2.Int64ID(2), map[string]any{"content": []any{}}, nil)
+	require.NoError(t, err)
+	assert.False(t, RemoteSpanContext(plain).IsValid())
+}
+
+func TestHTTPMiddleware_PropagatesTraceContextInMeta(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	middleware := NewHTTPMiddleware(Config{}, tracerProvider, noop.NewMeterProvider(), "github", "stdio")
+
+	serverTraceparent := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	var forwarded []byte
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		forwarded, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(forwarded)), r.ContentLength)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"content":[],"_meta":{"traceparent":"` +
+			serverTraceparent + `"}}}`))
+	}))
+
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"fetch"}}`)
+	req := httptest.NewRequest(http.MethodPost, "/messages", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	// Run the parsing middleware first, as the proxy does
+	rec := httptest.NewRecorder()
+	mcpparser.ParsingMiddleware(handler).ServeHTTP(rec, req)
+
+	require.Len(t, recorder.Ended(), 1)
+	span := recorder.Ended()[0]
+
+	var msg struct {
+		Params struct {
+			Meta map[string]string `json:"_meta"`
+		} `json:"params"`
+	}
+	require.NoError(t, json.Unmarshal(forwarded, &msg))
+	traceparent := msg.Params.Meta[TraceparentKey]
+	require.NotEmpty(t, traceparent)
+	assert.Contains(t, traceparent, span.SpanContext().TraceID().String())
+	assert.Contains(t, traceparent, span.SpanContext().SpanID().String())
+
+	require.Len(t, span.Links(), 1)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", span.Links()[0].SpanContext.TraceID().String())
+	assert.Equal(t, "b7ad6b7169203331", span.Links()[0].SpanContext.SpanID().String())
+}
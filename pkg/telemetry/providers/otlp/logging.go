@@ -1,7 +1,55 @@
 package otlp
 
-// This file is a placeholder for future OTLP logging support
-// OpenTelemetry Logs are still in development and will be added when stable
+import (
+	"context"
+	"fmt"
 
-// TODO: Implement OTLP logging when the specification is stable
-// Reference: https://opentelemetry.io/docs/specs/otel/logs/
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/log"
+	lognoop "go.opentelemetry.io/otel/log/noop"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func createLogExporter(ctx context.Context, config Config) (sdklog.Exporter, error) {
+	opts := []otlploghttp.Option{
+		otlploghttp.WithEndpoint(config.Endpoint),
+	}
+
+	if len(config.Headers) > 0 {
+		opts = append(opts, otlploghttp.WithHeaders(config.Headers))
+	}
+
+	if config.Insecure {
+		opts = append(opts, otlploghttp.WithInsecure())
+	}
+
+	exporter, err := otlploghttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log exporter: %w", err)
+	}
+	return exporter, nil
+}
+
+// NewLoggerProviderWithShutdown creates an OTLP logger provider with a shutdown function
+func NewLoggerProviderWithShutdown(
+	ctx context.Context,
+	config Config,
+	res *resource.Resource,
+) (log.LoggerProvider, func(context.Context) error, error) {
+	if config.Endpoint == "" {
+		return lognoop.NewLoggerProvider(), nil, nil
+	}
+
+	exporter, err := createLogExporter(ctx, config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create logger provider: %w", err)
+	}
+
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+		sdklog.WithResource(res),
+	)
+
+	return provider, provider.Shutdown, nil
+}
@@ -0,0 +1,81 @@
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+func TestNewLoggerProviderWithShutdown(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		config         Config
+		expectNoOp     bool
+		expectShutdown bool
+	}{
+		{
+			name: "valid config with endpoint returns SDK provider with shutdown",
+			config: Config{
+				Endpoint: "localhost:4318",
+				Headers:  map[string]string{"Authorization": "Bearer token"},
+				Insecure: true,
+			},
+			expectNoOp:     false,
+			expectShutdown: true,
+		},
+		{
+			name: "secure config returns SDK provider with shutdown",
+			config: Config{
+				Endpoint: "secure.example.com:4318",
+			},
+			expectNoOp:     false,
+			expectShutdown: true,
+		},
+		{
+			name:           "no endpoint returns noop provider with nil shutdown",
+			config:         Config{},
+			expectNoOp:     true,
+			expectShutdown: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			res, err := resource.New(ctx,
+				resource.WithAttributes(
+					semconv.ServiceName("test-service"),
+					semconv.ServiceVersion("1.0.0"),
+				),
+			)
+			require.NoError(t, err)
+
+			provider, shutdown, err := NewLoggerProviderWithShutdown(ctx, tt.config, res)
+			require.NoError(t, err)
+			require.NotNil(t, provider)
+
+			providerType := fmt.Sprintf("%T", provider)
+			if tt.expectNoOp {
+				assert.Contains(t, providerType, "noop")
+			} else {
+				assert.NotContains(t, providerType, "noop")
+			}
+
+			if tt.expectShutdown {
+				require.NotNil(t, shutdown)
+				assert.NoError(t, shutdown(ctx))
+			} else {
+				assert.Nil(t, shutdown)
+			}
+		})
+	}
+}
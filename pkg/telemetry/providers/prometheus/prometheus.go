@@ -18,6 +18,9 @@ type Config struct {
 	EnableMetricsPath bool
 	// IncludeRuntimeMetrics adds Go runtime metrics to the registry
 	IncludeRuntimeMetrics bool
+	// EnableExemplars serves the OpenMetrics format when negotiated, which is
+	// the only exposition format carrying exemplars
+	EnableExemplars bool
 }
 
 // NewReader creates a Prometheus metric reader and HTTP handler for use in a unified meter provider
@@ -43,8 +46,9 @@ func NewReader(config Config) (sdkmetric.Reader, http.Handler, error) {
 
 	// Create HTTP handler
 	handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{
-		ErrorHandling: promhttp.ContinueOnError,
-		ErrorLog:      nil,
+		ErrorHandling:     promhttp.ContinueOnError,
+		ErrorLog:          nil,
+		EnableOpenMetrics: config.EnableExemplars,
 	})
 
 	return exporter, handler, nil
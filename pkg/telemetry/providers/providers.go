@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"time"
 
+	"go.opentelemetry.io/otel/log"
+	lognoop "go.opentelemetry.io/otel/log/noop"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/noop"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -30,8 +32,12 @@ type Config struct {
 	Insecure       bool              // Insecure enables insecure transport (no TLS) for OTLP
 	TracingEnabled bool              // TracingEnabled controls whether tracing is enabled for OTLP
 	MetricsEnabled bool              // MetricsEnabled controls whether metrics are enabled for OTLP
+	LogsEnabled    bool              // LogsEnabled controls whether logs are enabled for OTLP
 	SamplingRate   float64           // SamplingRate controls trace sampling (0.0 to 1.0)
 
+	// ExemplarsEnabled attaches exemplars referencing sampled traces to metrics
+	ExemplarsEnabled bool
+
 	// Prometheus configuration
 	EnablePrometheusMetricsPath bool // EnablePrometheusMetricsPath enables Prometheus /metrics endpoint
 }
@@ -101,6 +107,22 @@ func WithMetricsEnabled(metricsEnabled bool) ProviderOption {
 	}
 }
 
+// WithLogsEnabled sets the logs enabled flag
+func WithLogsEnabled(logsEnabled bool) ProviderOption {
+	return func(config *Config) error {
+		config.LogsEnabled = logsEnabled
+		return nil
+	}
+}
+
+// WithExemplarsEnabled sets the exemplars enabled flag
+func WithExemplarsEnabled(exemplarsEnabled bool) ProviderOption {
+	return func(config *Config) error {
+		config.ExemplarsEnabled = exemplarsEnabled
+		return nil
+	}
+}
+
 // WithSamplingRate sets the sampling rate
 func WithSamplingRate(samplingRate float64) ProviderOption {
 	return func(config *Config) error {
@@ -118,10 +140,11 @@ func WithEnablePrometheusMetricsPath(enablePrometheusMetricsPath bool) ProviderO
 }
 
 // CompositeProvider combines telemetry providers into a single interface.
-// It manages tracer providers, meter providers, Prometheus handlers, and cleanup.
+// It manages tracer providers, meter providers, logger providers, Prometheus handlers, and cleanup.
 type CompositeProvider struct {
 	tracerProvider    trace.TracerProvider          // tracerProvider provides distributed tracing
 	meterProvider     metric.MeterProvider          // meterProvider provides metrics collection
+	loggerProvider    log.LoggerProvider            // loggerProvider provides log export
 	prometheusHandler http.Handler                  // prometheusHandler serves Prometheus metrics
 	shutdownFuncs     []func(context.Context) error // shutdownFuncs clean up resources on shutdown
 }
@@ -167,6 +190,7 @@ func createNoOpProvider() *CompositeProvider {
 	return &CompositeProvider{
 		tracerProvider:    tracenoop.NewTracerProvider(),
 		meterProvider:     noop.NewMeterProvider(),
+		loggerProvider:    lognoop.NewLoggerProvider(),
 		prometheusHandler: nil,
 		shutdownFuncs:     []func(context.Context) error{},
 	}
@@ -191,6 +215,10 @@ func buildProviders(
 		return nil, err
 	}
 
+	if err := createLoggingProvider(ctx, config, composite, selector, res); err != nil {
+		return nil, err
+	}
+
 	logger.Infof("Telemetry providers created successfully")
 	return composite, nil
 }
@@ -252,6 +280,33 @@ func createTracingProvider(
 	return nil
 }
 
+// createLoggingProvider creates the logger provider for the composite provider
+func createLoggingProvider(
+	ctx context.Context,
+	config Config,
+	composite *CompositeProvider,
+	selector *StrategySelector,
+	res *resource.Resource,
+) error {
+	// Create logger provider using selected strategy
+	loggerStrategy := selector.SelectLoggerStrategy()
+	loggerProvider, loggerShutdown, err := loggerStrategy.CreateLoggerProvider(ctx, config, res)
+	if err != nil {
+		return fmt.Errorf("failed to create logger provider with config (endpoint: %s, logs enabled: %t): %w",
+			config.OTLPEndpoint,
+			config.LogsEnabled,
+			err)
+	}
+
+	composite.loggerProvider = loggerProvider
+
+	if loggerShutdown != nil {
+		composite.shutdownFuncs = append(composite.shutdownFuncs, loggerShutdown)
+	}
+
+	return nil
+}
+
 // TracerProvider returns the tracer provider
 func (p *CompositeProvider) TracerProvider() trace.TracerProvider {
 	return p.tracerProvider
@@ -262,6 +317,11 @@ func (p *CompositeProvider) MeterProvider() metric.MeterProvider {
 	return p.meterProvider
 }
 
+// LoggerProvider returns the logger provider
+func (p *CompositeProvider) LoggerProvider() log.LoggerProvider {
+	return p.loggerProvider
+}
+
 // PrometheusHandler returns the Prometheus metrics handler if configured
 func (p *CompositeProvider) PrometheusHandler() http.Handler {
 	return p.prometheusHandler
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"net/http"
 
+	"go.opentelemetry.io/otel/log"
+	lognoop "go.opentelemetry.io/otel/log/noop"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/noop"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/trace"
 	tracenoop "go.opentelemetry.io/otel/trace/noop"
@@ -66,6 +69,53 @@ func (*OTLPTracerStrategy) CreateTracerProvider(
 	return provider, shutdown, nil
 }
 
+// LoggerStrategy defines the interface for creating logger providers.
+// Implementations create log providers based on configuration and resource information.
+type LoggerStrategy interface {
+	// CreateLoggerProvider creates a logger provider with optional shutdown function
+	CreateLoggerProvider(ctx context.Context, config Config, res *resource.Resource) (
+		log.LoggerProvider, func(context.Context) error, error)
+}
+
+// NoOpLoggerStrategy creates a no-op logger provider that discards all log records.
+// It's used when log export is disabled or no OTLP endpoint is configured.
+type NoOpLoggerStrategy struct{}
+
+// CreateLoggerProvider creates a no-op logger provider
+func (*NoOpLoggerStrategy) CreateLoggerProvider(
+	_ context.Context,
+	_ Config,
+	_ *resource.Resource,
+) (log.LoggerProvider, func(context.Context) error, error) {
+	logger.Debugf("Creating no-op logger provider")
+	return lognoop.NewLoggerProvider(), nil, nil
+}
+
+// OTLPLoggerStrategy creates an OTLP logger provider that sends log records to an OTLP collector.
+// Records emitted within a span carry its trace and span IDs.
+type OTLPLoggerStrategy struct{}
+
+// CreateLoggerProvider creates an OTLP logger provider with the configured endpoint
+func (*OTLPLoggerStrategy) CreateLoggerProvider(
+	ctx context.Context,
+	config Config,
+	res *resource.Resource,
+) (log.LoggerProvider, func(context.Context) error, error) {
+	logger.Infof("Creating OTLP logger provider for endpoint: %s", config.OTLPEndpoint)
+
+	otlpConfig := otlp.Config{
+		Endpoint: config.OTLPEndpoint,
+		Headers:  config.Headers,
+		Insecure: config.Insecure,
+	}
+
+	provider, shutdown, err := otlp.NewLoggerProviderWithShutdown(ctx, otlpConfig, res)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create OTLP logger provider for endpoint %s: %w", config.OTLPEndpoint, err)
+	}
+	return provider, shutdown, nil
+}
+
 // MeterResult contains the result of creating a meter provider
 type MeterResult struct {
 	MeterProvider     metric.MeterProvider
@@ -101,6 +151,7 @@ func (*NoOpMeterStrategy) CreateMeterProvider(
 type UnifiedMeterStrategy struct {
 	EnableOTLP       bool // EnableOTLP controls whether to add an OTLP metrics reader
 	EnablePrometheus bool // EnablePrometheus controls whether to add a Prometheus reader
+	EnableExemplars  bool // EnableExemplars controls whether measurements in sampled traces record exemplars
 }
 
 // CreateMeterProvider creates a unified meter provider with OTLP and/or Prometheus readers
@@ -136,6 +187,7 @@ func (s *UnifiedMeterStrategy) CreateMeterProvider(
 		promConfig := prometheus.Config{
 			EnableMetricsPath:     true,
 			IncludeRuntimeMetrics: true,
+			EnableExemplars:       s.EnableExemplars,
 		}
 		reader, handler, err := prometheus.NewReader(promConfig)
 		if err != nil {
@@ -154,7 +206,16 @@ func (s *UnifiedMeterStrategy) CreateMeterProvider(
 		}, nil
 	}
 
-	opts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+	// Exemplars are only taken from sampled spans, so every exemplar links to an exported trace
+	exemplarFilter := exemplar.AlwaysOffFilter
+	if s.EnableExemplars {
+		exemplarFilter = exemplar.TraceBasedFilter
+	}
+
+	opts := []sdkmetric.Option{
+		sdkmetric.WithResource(res),
+		sdkmetric.WithExemplarFilter(exemplarFilter),
+	}
 	for _, reader := range readers {
 		opts = append(opts, sdkmetric.WithReader(reader))
 	}
@@ -210,12 +271,21 @@ func (s *StrategySelector) SelectMeterStrategy() MeterStrategy {
 	return &UnifiedMeterStrategy{
 		EnableOTLP:       wantsOTLPMetrics,
 		EnablePrometheus: wantsPrometheus,
+		EnableExemplars:  s.config.ExemplarsEnabled,
 	}
 }
 
-// IsFullyNoOp returns true if both tracer and meter would be no-op.
+// SelectLoggerStrategy determines the appropriate logger strategy based on configuration.
+func (s *StrategySelector) SelectLoggerStrategy() LoggerStrategy {
+	if s.hasOTLPLogs() {
+		return &OTLPLoggerStrategy{}
+	}
+	return &NoOpLoggerStrategy{}
+}
+
+// IsFullyNoOp returns true if the tracer, meter and logger would all be no-op.
 func (s *StrategySelector) IsFullyNoOp() bool {
-	return !s.hasOTLPMetrics() && !s.hasOTLPTracing() && !s.hasPrometheus()
+	return !s.hasOTLPMetrics() && !s.hasOTLPTracing() && !s.hasOTLPLogs() && !s.hasPrometheus()
 }
 
 // hasOTLPMetrics returns true if OTLP metrics are wanted.
@@ -228,6 +298,11 @@ func (s *StrategySelector) hasOTLPTracing() bool {
 	return s.config.OTLPEndpoint != "" && s.config.TracingEnabled
 }
 
+// hasOTLPLogs returns true if OTLP logs are wanted.
+func (s *StrategySelector) hasOTLPLogs() bool {
+	return s.config.OTLPEndpoint != "" && s.config.LogsEnabled
+}
+
 // hasPrometheus returns true if Prometheus metrics are wanted.
 func (s *StrategySelector) hasPrometheus() bool {
 	return s.config.EnablePrometheusMetricsPath
@@ -61,6 +61,72 @@ func TestStrategySelector_SelectTracerStrategy(t *testing.T) {
 	}
 }
 
+func TestStrategySelector_SelectLoggerStrategy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		config       Config
+		expectedType string
+	}{
+		{
+			name: "OTLP logger when endpoint and logs enabled",
+			config: Config{
+				OTLPEndpoint: "localhost:4318",
+				LogsEnabled:  true,
+			},
+			expectedType: "*providers.OTLPLoggerStrategy",
+		},
+		{
+			name: "NoOp logger when endpoint but logs disabled",
+			config: Config{
+				OTLPEndpoint: "localhost:4318",
+			},
+			expectedType: "*providers.NoOpLoggerStrategy",
+		},
+		{
+			name: "NoOp logger when no endpoint",
+			config: Config{
+				LogsEnabled: true,
+			},
+			expectedType: "*providers.NoOpLoggerStrategy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			selector := NewStrategySelector(tt.config)
+			strategy := selector.SelectLoggerStrategy()
+
+			assert.NotNil(t, strategy)
+			assert.Equal(t, tt.expectedType, getTypeName(strategy))
+		})
+	}
+}
+
+func TestOTLPLoggerStrategy_CreateLoggerProvider(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	res := createTestResource(t)
+	config := Config{
+		OTLPEndpoint: "localhost:4318",
+		Insecure:     true,
+		LogsEnabled:  true,
+	}
+
+	strategy := &OTLPLoggerStrategy{}
+	provider, shutdown, err := strategy.CreateLoggerProvider(ctx, config, res)
+
+	require.NoError(t, err)
+	require.NotNil(t, provider)
+	require.NotNil(t, shutdown, "Expected shutdown function for OTLP logger")
+	assert.NotContains(t, getTypeName(provider), "noop")
+	assert.NoError(t, shutdown(ctx))
+}
+
 func TestNoOpTracerStrategy_CreateTracerProvider(t *testing.T) {
 	t.Parallel()
 
@@ -249,6 +315,14 @@ func TestStrategySelector_IsFullyNoOp(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name: "not no-op when OTLP logs enabled",
+			config: Config{
+				OTLPEndpoint: "localhost:4318",
+				LogsEnabled:  true,
+			},
+			expected: false,
+		},
 		{
 			name: "fully no-op when endpoint but nothing enabled",
 			config: Config{
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func TestUnifiedMeterProvider_BothProviders(t *testing.T) {
@@ -130,3 +131,53 @@ func TestUnifiedMeterProvider_OTLPOnly(t *testing.T) {
 	gauge.Add(ctx, 100)
 	gauge.Add(ctx, -50)
 }
+
+func TestUnifiedMeterProvider_Exemplars(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		exemplarsEnabled bool
+	}{
+		{name: "exemplars enabled", exemplarsEnabled: true},
+		{name: "exemplars disabled", exemplarsEnabled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			provider, err := NewCompositeProvider(ctx,
+				WithServiceName("test-service"),
+				WithServiceVersion("1.0.0"),
+				WithEnablePrometheusMetricsPath(true),
+				WithExemplarsEnabled(tt.exemplarsEnabled),
+			)
+			require.NoError(t, err)
+			defer provider.Shutdown(ctx)
+
+			// Record a measurement inside a sampled span
+			tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+			defer tracerProvider.Shutdown(ctx)
+			spanCtx, span := tracerProvider.Tracer("test").Start(ctx, "request")
+			histogram, err := provider.MeterProvider().Meter("test").Float64Histogram("test_duration")
+			require.NoError(t, err)
+			histogram.Record(spanCtx, 0.42)
+			span.End()
+
+			req := httptest.NewRequest("GET", "/metrics", nil)
+			req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+			rec := httptest.NewRecorder()
+			provider.PrometheusHandler().ServeHTTP(rec, req)
+
+			assert.Equal(t, 200, rec.Code)
+			traceID := span.SpanContext().TraceID().String()
+			if tt.exemplarsEnabled {
+				assert.Contains(t, rec.Body.String(), traceID)
+			} else {
+				assert.NotContains(t, rec.Body.String(), traceID)
+			}
+		})
+	}
+}
@@ -156,6 +156,7 @@ func (t *HTTPTransport) Setup(
 	k8sPodTemplatePatch string,
 	isolateNetwork bool,
 	ignoreConfig *ignore.Config,
+	options types.SetupOptions,
 ) error {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
@@ -185,6 +186,8 @@ func (t *HTTPTransport) Setup(
 	containerOptions := rt.NewDeployWorkloadOptions()
 	containerOptions.K8sPodTemplatePatch = k8sPodTemplatePatch
 	containerOptions.IgnoreConfig = ignoreConfig
+	containerOptions.SecretFiles = options.SecretFiles
+	containerOptions.Services = options.Services
 
 	// Expose the target port in the container
 	containerPortStr := fmt.Sprintf("%d/tcp", t.targetPort)
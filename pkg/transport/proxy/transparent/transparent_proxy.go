@@ -167,7 +167,7 @@ func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 			// Expected during shutdown or client disconnect—silently ignore
 			return nil, err
 		}
-		logger.Errorf("Failed to forward request: %v", err)
+		logger.WithContext(req.Context()).Errorf("Failed to forward request: %v", err)
 		return nil, err
 	}
 	if resp.StatusCode == http.StatusOK {
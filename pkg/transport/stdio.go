@@ -11,6 +11,9 @@ import (
 	"time"
 	"unicode"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/jsonrpc2"
 
 	"github.com/stacklok/toolhive/pkg/container"
@@ -18,12 +21,18 @@ import (
 	"github.com/stacklok/toolhive/pkg/ignore"
 	"github.com/stacklok/toolhive/pkg/logger"
 	"github.com/stacklok/toolhive/pkg/permissions"
+	"github.com/stacklok/toolhive/pkg/telemetry"
 	"github.com/stacklok/toolhive/pkg/transport/errors"
 	"github.com/stacklok/toolhive/pkg/transport/proxy/httpsse"
 	"github.com/stacklok/toolhive/pkg/transport/proxy/streamable"
 	"github.com/stacklok/toolhive/pkg/transport/types"
 )
 
+const (
+	// instrumentationName is the name used for spans created by the transport
+	instrumentationName = "github.com/stacklok/toolhive/pkg/transport"
+)
+
 // StdioTransport implements the Transport interface using standard input/output.
 // It acts as a proxy between the MCP client and the container's stdin/stdout.
 type StdioTransport struct {
@@ -105,6 +114,7 @@ func (t *StdioTransport) Setup(
 	k8sPodTemplatePatch string,
 	isolateNetwork bool,
 	ignoreConfig *ignore.Config,
+	options types.SetupOptions,
 ) error {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
@@ -120,6 +130,8 @@ func (t *StdioTransport) Setup(
 	containerOptions.AttachStdio = true
 	containerOptions.K8sPodTemplatePatch = k8sPodTemplatePatch
 	containerOptions.IgnoreConfig = ignoreConfig
+	containerOptions.SecretFiles = options.SecretFiles
+	containerOptions.Services = options.Services
 
 	// Create the container
 	logger.Infof("Deploying workload %s from image %s...", containerName, image)
@@ -446,6 +458,9 @@ func (t *StdioTransport) parseAndForwardJSONRPC(ctx context.Context, line string
 	// Log the message
 	logger.Infof("Received JSON-RPC message: %T", msg)
 
+	// Pick up the span reported back by the server, if any
+	t.recordServerSpan(ctx, msg)
+
 	if err := t.httpProxy.ForwardResponseToClients(ctx, msg); err != nil {
 		if t.proxyMode == types.ProxyModeStreamableHTTP {
 			logger.Errorf("Error forwarding to streamable-http client: %v", err)
@@ -456,7 +471,23 @@ func (t *StdioTransport) parseAndForwardJSONRPC(ctx context.Context, line string
 }
 
 // sendMessageToContainer sends a JSON-RPC message to the container.
-func (*StdioTransport) sendMessageToContainer(_ context.Context, stdin io.Writer, msg jsonrpc2.Message) error {
+// The trace context carried in the message _meta (injected by the telemetry middleware)
+// is continued with a client span, and the message is re-stamped with that span so the
+// server's own spans become its children.
+func (*StdioTransport) sendMessageToContainer(ctx context.Context, stdin io.Writer, msg jsonrpc2.Message) error {
+	ctx = telemetry.ExtractTraceContext(ctx, msg)
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		var span trace.Span
+		ctx, span = otel.Tracer(instrumentationName).Start(ctx, "mcp.stdio.send", trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End()
+		if req, ok := msg.(*jsonrpc2.Request); ok {
+			span.SetAttributes(attribute.String("mcp.method", req.Method))
+		}
+		if err := telemetry.InjectTraceContext(ctx, msg); err != nil {
+			logger.Debugf("Failed to inject trace context into message: %v", err)
+		}
+	}
+
 	// Serialize the message
 	data, err := jsonrpc2.EncodeMessage(msg)
 	if err != nil {
@@ -476,6 +507,30 @@ func (*StdioTransport) sendMessageToContainer(_ context.Context, stdin io.Writer
 	return nil
 }
 
+// recordServerSpan records a span for a response whose _meta carries the trace context of
+// the server span that produced it, so the trace continues into the tool implementation.
+func (*StdioTransport) recordServerSpan(ctx context.Context, msg jsonrpc2.Message) {
+	resp, ok := msg.(*jsonrpc2.Response)
+	if !ok {
+		return
+	}
+
+	serverSpan := telemetry.RemoteSpanContext(msg)
+	if !serverSpan.IsValid() {
+		return
+	}
+
+	ctx = trace.ContextWithRemoteSpanContext(ctx, serverSpan)
+	_, span := otel.Tracer(instrumentationName).Start(ctx, "mcp.stdio.receive", trace.WithSpanKind(trace.SpanKindClient))
+	if resp.ID.IsValid() {
+		span.SetAttributes(attribute.String("mcp.request.id", fmt.Sprintf("%v", resp.ID.Raw())))
+	}
+	if resp.Error != nil {
+		span.SetAttributes(attribute.String("mcp.error", resp.Error.Error()))
+	}
+	span.End()
+}
+
 // handleContainerExit handles container exit events.
 func (t *StdioTransport) handleContainerExit(ctx context.Context) {
 	select {
@@ -1,12 +1,15 @@
 package transport
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/jsonrpc2"
 
 	"github.com/stacklok/toolhive/pkg/logger"
@@ -216,3 +219,52 @@ func TestIsSpace(t *testing.T) {
 		})
 	}
 }
+
+func TestSendMessageToContainer_TraceContext(t *testing.T) {
+	t.Parallel()
+	logger.Initialize()
+
+	tests := []struct {
+		name            string
+		params          string
+		wantTraceparent bool
+	}{
+		{
+			name:            "message carrying trace context in _meta",
+			params:          `{"name":"fetch","_meta":{"traceparent":"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}}`,
+			wantTraceparent: true,
+		},
+		{
+			name:   "message without trace context",
+			params: `{"name":"fetch"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			msg, err := jsonrpc2.NewCall(jsonrpc2.Int64ID(1), "tools/call", nil)
+			require.NoError(t, err)
+			msg.Params = json.RawMessage(tt.params)
+
+			var stdin bytes.Buffer
+			transport := &StdioTransport{}
+			require.NoError(t, transport.sendMessageToContainer(context.Background(), &stdin, msg))
+
+			var written struct {
+				Params map[string]json.RawMessage `json:"params"`
+			}
+			require.NoError(t, json.Unmarshal(stdin.Bytes(), &written))
+			assert.Contains(t, string(written.Params["name"]), "fetch")
+
+			meta, ok := written.Params["_meta"]
+			if !tt.wantTraceparent {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Contains(t, string(meta), "4bf92f3577b34da6a3ce929d0e0e4736")
+		})
+	}
+}
@@ -78,6 +78,14 @@ type RunnerConfig interface {
 // and configures the runner with the middleware and any additional handlers it provides.
 type MiddlewareFactory func(config *MiddlewareConfig, runner MiddlewareRunner) error
 
+// SetupOptions are the additional parts of a workload which a transport deploys with its container
+type SetupOptions struct {
+	// SecretFiles are mounted into the container as read-only files
+	SecretFiles []rt.SecretFile
+	// Services are auxiliary containers which start before the container
+	Services []rt.ServiceContainer
+}
+
 // Transport defines the interface for MCP transport implementations.
 // It provides methods for handling communication between the client and server.
 type Transport interface {
@@ -91,9 +99,10 @@ type Transport interface {
 	// The runtime parameter provides access to container operations.
 	// The permissionProfile is used to configure container permissions.
 	// The k8sPodTemplatePatch is a JSON string to patch the Kubernetes pod template.
+	// The options add secret files and services to the workload.
 	Setup(ctx context.Context, runtime rt.Deployer, containerName string, image string, cmdArgs []string,
 		envVars, labels map[string]string, permissionProfile *permissions.Profile, k8sPodTemplatePatch string,
-		isolateNetwork bool, ignoreConfig *ignore.Config) error
+		isolateNetwork bool, ignoreConfig *ignore.Config, options SetupOptions) error
 
 	// Start initializes the transport and begins processing messages.
 	// The transport is responsible for container operations like attaching to stdin/stdout if needed.
@@ -0,0 +1,146 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/stacklok/toolhive/pkg/logger"
+)
+
+const (
+	// DefaultFlushInterval is how often accounted usage is persisted to the store
+	DefaultFlushInterval = 30 * time.Second
+	// retentionDays is how long daily usage is kept in the ledger
+	retentionDays = 400
+)
+
+// Accountant accounts the usage of a workload and enforces its quotas.
+// Usage is kept in memory and persisted to the store periodically and on Close.
+type Accountant struct {
+	config *Config
+	store  Store
+	now    func() time.Time
+
+	mu     sync.Mutex
+	ledger *Ledger
+	dirty  bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewAccountant creates an accountant for the workload, resuming from the ledger in the store.
+func NewAccountant(ctx context.Context, workload string, config *Config, store Store) (*Accountant, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid usage config: %w", err)
+	}
+
+	ledger, err := store.Load(ctx, workload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load usage: %w", err)
+	}
+
+	return &Accountant{
+		config: config,
+		store:  store,
+		now:    time.Now,
+		ledger: ledger,
+	}, nil
+}
+
+// Check returns a QuotaExceededError if the principal has exhausted any of its quotas.
+func (a *Accountant) Check(principal string) error {
+	now := a.now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, quota := range a.config.Quotas {
+		if !quota.appliesTo(principal) {
+			continue
+		}
+		start, resetsAt := quota.window(now)
+		used := a.ledger.Usage(principal, start, now)
+		if err := quota.check(principal, used, resetsAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Record accounts the counters to the principal.
+func (a *Accountant) Record(principal string, counters Counters) {
+	now := a.now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.ledger.Add(now, principal, counters)
+	a.dirty = true
+}
+
+// Flush persists the accounted usage if it changed since the last flush.
+func (a *Accountant) Flush(ctx context.Context) error {
+	a.mu.Lock()
+	if !a.dirty {
+		a.mu.Unlock()
+		return nil
+	}
+	a.ledger.Prune(a.now().AddDate(0, 0, -retentionDays))
+	data := &Ledger{Workload: a.ledger.Workload, Days: make(map[string]map[string]*Counters, len(a.ledger.Days))}
+	for date, principals := range a.ledger.Days {
+		copied := make(map[string]*Counters, len(principals))
+		for principal, counters := range principals {
+			c := *counters
+			copied[principal] = &c
+		}
+		data.Days[date] = copied
+	}
+	a.dirty = false
+	a.mu.Unlock()
+
+	if err := a.store.Save(ctx, data); err != nil {
+		a.mu.Lock()
+		a.dirty = true
+		a.mu.Unlock()
+		return fmt.Errorf("failed to save usage: %w", err)
+	}
+	return nil
+}
+
+// Start persists the accounted usage every interval until Close is called.
+func (a *Accountant) Start(interval time.Duration) {
+	a.stop = make(chan struct{})
+	a.done = make(chan struct{})
+
+	go func() {
+		defer close(a.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := a.Flush(context.Background()); err != nil {
+					logger.Warnf("Failed to persist usage of workload %s: %v", a.ledger.Workload, err)
+				}
+			case <-a.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the periodic persistence and persists the remaining usage.
+func (a *Accountant) Close() error {
+	if a.stop != nil {
+		close(a.stop)
+		<-a.done
+		a.stop = nil
+	}
+	return a.Flush(context.Background())
+}
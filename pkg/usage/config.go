@@ -0,0 +1,162 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Period is the window a quota applies to.
+type Period string
+
+const (
+	// PeriodDaily quotas reset at midnight UTC
+	PeriodDaily Period = "daily"
+	// PeriodMonthly quotas reset on the first day of the month, at midnight UTC
+	PeriodMonthly Period = "monthly"
+)
+
+// DefaultPrincipalClaim is the JWT claim identifying the principal usage is accounted to
+const DefaultPrincipalClaim = "sub"
+
+// Config represents the usage accounting configuration.
+type Config struct {
+	// PrincipalClaim is the JWT claim usage is accounted by, such as "sub" or a team claim.
+	// Defaults to "sub".
+	PrincipalClaim string `json:"principal_claim,omitempty" yaml:"principal_claim,omitempty"`
+	// Quotas limit the usage of each principal. Once a quota is exhausted,
+	// further tool calls are rejected with a JSON-RPC error until the period resets.
+	Quotas []Quota `json:"quotas,omitempty" yaml:"quotas,omitempty"`
+}
+
+// Quota limits the usage of a principal over a period.
+type Quota struct {
+	// Period is the window the quota applies to, either "daily" or "monthly"
+	Period Period `json:"period" yaml:"period"`
+	// Principal restricts the quota to a single principal. If empty, the quota applies
+	// to every principal separately.
+	Principal string `json:"principal,omitempty" yaml:"principal,omitempty"`
+	// MaxToolCalls is the maximum number of tool calls in the period, 0 for unlimited
+	MaxToolCalls int64 `json:"max_tool_calls,omitempty" yaml:"max_tool_calls,omitempty"`
+	// MaxBytes is the maximum number of bytes transferred in the period, 0 for unlimited
+	MaxBytes int64 `json:"max_bytes,omitempty" yaml:"max_bytes,omitempty"`
+}
+
+// DefaultConfig returns a configuration which accounts usage without enforcing quotas.
+func DefaultConfig() *Config {
+	return &Config{
+		PrincipalClaim: DefaultPrincipalClaim,
+	}
+}
+
+// NewConfigWithQuotas returns a configuration which enforces the given number of tool calls
+// per principal per day and month. A zero quota is not enforced.
+func NewConfigWithQuotas(dailyToolCalls, monthlyToolCalls int64) *Config {
+	config := DefaultConfig()
+	if dailyToolCalls > 0 {
+		config.Quotas = append(config.Quotas, Quota{Period: PeriodDaily, MaxToolCalls: dailyToolCalls})
+	}
+	if monthlyToolCalls > 0 {
+		config.Quotas = append(config.Quotas, Quota{Period: PeriodMonthly, MaxToolCalls: monthlyToolCalls})
+	}
+	return config
+}
+
+// LoadFromFile loads usage configuration from a file.
+func LoadFromFile(path string) (*Config, error) {
+	// Clean the path to prevent directory traversal
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open usage config file: %w", err)
+	}
+	defer file.Close()
+
+	return LoadFromReader(file)
+}
+
+// LoadFromReader loads usage configuration from an io.Reader.
+func LoadFromReader(r io.Reader) (*Config, error) {
+	var config Config
+	decoder := json.NewDecoder(r)
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to decode usage config: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid usage config: %w", err)
+	}
+
+	return &config, nil
+}
+
+// Validate validates the usage configuration.
+func (c *Config) Validate() error {
+	for i, quota := range c.Quotas {
+		if quota.Period != PeriodDaily && quota.Period != PeriodMonthly {
+			return fmt.Errorf("quota %d: period must be %q or %q, got %q", i, PeriodDaily, PeriodMonthly, quota.Period)
+		}
+		if quota.MaxToolCalls < 0 || quota.MaxBytes < 0 {
+			return fmt.Errorf("quota %d: limits cannot be negative", i)
+		}
+		if quota.MaxToolCalls == 0 && quota.MaxBytes == 0 {
+			return fmt.Errorf("quota %d: at least one of max_tool_calls or max_bytes must be set", i)
+		}
+	}
+	return nil
+}
+
+// GetPrincipalClaim returns the JWT claim usage is accounted by.
+func (c *Config) GetPrincipalClaim() string {
+	if c == nil || c.PrincipalClaim == "" {
+		return DefaultPrincipalClaim
+	}
+	return c.PrincipalClaim
+}
+
+// appliesTo reports whether the quota limits the principal.
+func (q Quota) appliesTo(principal string) bool {
+	return q.Principal == "" || q.Principal == principal
+}
+
+// window returns the first day of the period containing now and the start of the next period.
+func (q Quota) window(now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	if q.Period == PeriodMonthly {
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// check returns an error if the usage exhausts the quota.
+func (q Quota) check(principal string, used Counters, resetsAt time.Time) error {
+	if q.MaxToolCalls > 0 && used.ToolCalls >= q.MaxToolCalls {
+		return &QuotaExceededError{Principal: principal, Quota: q, Resource: "tool call", ResetsAt: resetsAt}
+	}
+	if q.MaxBytes > 0 && used.Bytes() >= q.MaxBytes {
+		return &QuotaExceededError{Principal: principal, Quota: q, Resource: "byte", ResetsAt: resetsAt}
+	}
+	return nil
+}
+
+// QuotaExceededError is returned when a principal has exhausted a quota.
+type QuotaExceededError struct {
+	Principal string
+	Quota     Quota
+	// Resource is the exhausted resource, "tool call" or "byte"
+	Resource string
+	ResetsAt time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	limit := e.Quota.MaxToolCalls
+	if e.Resource != "tool call" {
+		limit = e.Quota.MaxBytes
+	}
+	return fmt.Sprintf("%s %s quota of %d exhausted for principal %s, resets at %s",
+		e.Quota.Period, e.Resource, limit, e.Principal, e.ResetsAt.Format(time.RFC3339))
+}
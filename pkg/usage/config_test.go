@@ -0,0 +1,95 @@
+package usage
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFromReader(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		json          string
+		expected      *Config
+		errorContains string
+	}{
+		{
+			name: "valid config",
+			json: `{"principal_claim":"team","quotas":[{"period":"daily","max_tool_calls":100},` +
+				`{"period":"monthly","principal":"alice","max_bytes":1048576}]}`,
+			expected: &Config{
+				PrincipalClaim: "team",
+				Quotas: []Quota{
+					{Period: PeriodDaily, MaxToolCalls: 100},
+					{Period: PeriodMonthly, Principal: "alice", MaxBytes: 1048576},
+				},
+			},
+		},
+		{
+			name:          "invalid period",
+			json:          `{"quotas":[{"period":"weekly","max_tool_calls":100}]}`,
+			errorContains: `period must be "daily" or "monthly"`,
+		},
+		{
+			name:          "quota without limits",
+			json:          `{"quotas":[{"period":"daily"}]}`,
+			errorContains: "at least one of max_tool_calls or max_bytes must be set",
+		},
+		{
+			name:          "negative limit",
+			json:          `{"quotas":[{"period":"daily","max_tool_calls":-1}]}`,
+			errorContains: "limits cannot be negative",
+		},
+		{
+			name:          "invalid JSON",
+			json:          `{"quotas":`,
+			errorContains: "failed to decode usage config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config, err := LoadFromReader(strings.NewReader(tt.json))
+			if tt.errorContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, config)
+		})
+	}
+}
+
+func TestNewConfigWithQuotas(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, NewConfigWithQuotas(0, 0).Quotas)
+	assert.Equal(t, DefaultPrincipalClaim, NewConfigWithQuotas(0, 0).GetPrincipalClaim())
+	assert.Equal(t, []Quota{
+		{Period: PeriodDaily, MaxToolCalls: 10},
+		{Period: PeriodMonthly, MaxToolCalls: 200},
+	}, NewConfigWithQuotas(10, 200).Quotas)
+	assert.Equal(t, []Quota{{Period: PeriodMonthly, MaxToolCalls: 200}}, NewConfigWithQuotas(0, 200).Quotas)
+}
+
+func TestQuotaWindow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 12, 31, 15, 0, 0, 0, time.UTC)
+
+	start, resetsAt := Quota{Period: PeriodDaily}.window(now)
+	assert.Equal(t, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), resetsAt)
+
+	start, resetsAt = Quota{Period: PeriodMonthly}.window(now)
+	assert.Equal(t, time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), resetsAt)
+}
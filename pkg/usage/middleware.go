@@ -0,0 +1,166 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/exp/jsonrpc2"
+
+	"github.com/stacklok/toolhive/pkg/auth"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/mcp"
+	"github.com/stacklok/toolhive/pkg/transport/types"
+)
+
+// Middleware type constant
+const (
+	MiddlewareType = "usage"
+)
+
+// AnonymousPrincipal is the principal of requests without a principal claim
+const AnonymousPrincipal = "anonymous"
+
+// MiddlewareParams represents the parameters for usage middleware
+type MiddlewareParams struct {
+	Config     *Config `json:"config,omitempty"`
+	ServerName string  `json:"server_name"`
+}
+
+// Middleware wraps usage accounting middleware functionality
+type Middleware struct {
+	accountant *Accountant
+}
+
+// Handler returns the middleware function used by the proxy.
+func (m *Middleware) Handler() types.MiddlewareFunction {
+	return m.accountant.Middleware
+}
+
+// Close persists the accounted usage.
+func (m *Middleware) Close() error {
+	return m.accountant.Close()
+}
+
+// CreateMiddleware factory function for usage middleware
+func CreateMiddleware(config *types.MiddlewareConfig, runner types.MiddlewareRunner) error {
+
+	var params MiddlewareParams
+	if err := json.Unmarshal(config.Parameters, &params); err != nil {
+		return fmt.Errorf("failed to unmarshal usage middleware parameters: %w", err)
+	}
+
+	store, err := NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to create usage store: %w", err)
+	}
+
+	accountant, err := NewAccountant(context.Background(), params.ServerName, params.Config, store)
+	if err != nil {
+		return fmt.Errorf("failed to create usage middleware: %w", err)
+	}
+	accountant.Start(DefaultFlushInterval)
+
+	runner.AddMiddleware(&Middleware{accountant: accountant})
+	return nil
+}
+
+// Middleware creates an HTTP middleware that accounts the usage of parsed MCP requests
+// and rejects tool calls of principals that exhausted their quota.
+func (a *Accountant) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Only MCP requests are accounted (set by parsing middleware)
+		parsedRequest := mcp.GetParsedMCPRequest(r.Context())
+		if parsedRequest == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal := principalFromContext(r.Context(), a.config.GetPrincipalClaim())
+		isToolCall := parsedRequest.Method == "tools/call"
+
+		if isToolCall {
+			if err := a.Check(principal); err != nil {
+				logger.Debugf("Rejecting tool call: %v", err)
+				handleQuotaExceeded(w, parsedRequest.ID, err)
+				return
+			}
+		}
+
+		rw := &countingWriter{ResponseWriter: w}
+		startTime := time.Now()
+		next.ServeHTTP(rw, r)
+
+		counters := Counters{
+			Requests:   1,
+			BytesIn:    max(r.ContentLength, 0),
+			BytesOut:   rw.bytes,
+			DurationMs: time.Since(startTime).Milliseconds(),
+		}
+		if isToolCall {
+			counters.ToolCalls = 1
+		}
+		a.Record(principal, counters)
+	})
+}
+
+// principalFromContext returns the value of the principal claim of the request,
+// or AnonymousPrincipal if it has none.
+func principalFromContext(ctx context.Context, claim string) string {
+	if claims, ok := auth.GetClaimsFromContext(ctx); ok {
+		if principal, ok := claims[claim].(string); ok && principal != "" {
+			return principal
+		}
+	}
+	return AnonymousPrincipal
+}
+
+// handleQuotaExceeded responds with a JSON-RPC error for a request whose principal exhausted a quota.
+func handleQuotaExceeded(w http.ResponseWriter, msgID interface{}, quotaErr error) {
+	var id jsonrpc2.ID
+	switch v := msgID.(type) {
+	case string:
+		id = jsonrpc2.StringID(v)
+	case float64:
+		// JSON numbers are unmarshaled as float64
+		id = jsonrpc2.Int64ID(int64(v))
+	case int64:
+		id = jsonrpc2.Int64ID(v)
+	}
+
+	errorResponse := &jsonrpc2.Response{
+		ID:    id,
+		Error: jsonrpc2.NewError(http.StatusTooManyRequests, quotaErr.Error()),
+	}
+
+	data, err := jsonrpc2.EncodeMessage(errorResponse)
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	_, _ = w.Write(data)
+}
+
+// countingWriter wraps http.ResponseWriter to count the bytes of the response.
+type countingWriter struct {
+	http.ResponseWriter
+	bytes int64
+}
+
+func (w *countingWriter) Write(data []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(data)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher so streamed responses keep working.
+func (w *countingWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,149 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stacklok/toolhive/pkg/auth"
+	"github.com/stacklok/toolhive/pkg/mcp"
+)
+
+const toolCallBody = `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"fetch","arguments":{}}}`
+
+func newTestHandler(t *testing.T, accountant *Accountant) http.Handler {
+	t.Helper()
+
+	backend := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+	})
+	return mcp.ParsingMiddleware(accountant.Middleware(backend))
+}
+
+func serve(handler http.Handler, body string, claims jwt.MapClaims) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if claims != nil {
+		req = req.WithContext(context.WithValue(req.Context(), auth.ClaimsContextKey{}, claims))
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddlewareAccountsUsage(t *testing.T) {
+	t.Parallel()
+
+	store := NewLocalStore(t.TempDir())
+	accountant, err := NewAccountant(context.Background(), "fetch", &Config{PrincipalClaim: "team"}, store)
+	require.NoError(t, err)
+	handler := newTestHandler(t, accountant)
+
+	alice := jwt.MapClaims{"sub": "alice", "team": "search"}
+	assert.Equal(t, http.StatusOK, serve(handler, toolCallBody, alice).Code)
+	assert.Equal(t, http.StatusOK, serve(handler, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`, alice).Code)
+	assert.Equal(t, http.StatusOK, serve(handler, toolCallBody, nil).Code)
+
+	require.NoError(t, accountant.Close())
+
+	ledger, err := store.Load(context.Background(), "fetch")
+	require.NoError(t, err)
+	now := time.Now()
+
+	team := ledger.Usage("search", now, now)
+	assert.Equal(t, int64(2), team.Requests)
+	assert.Equal(t, int64(1), team.ToolCalls)
+	assert.Equal(t, int64(len(toolCallBody))+int64(len(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)), team.BytesIn)
+	assert.Equal(t, int64(2*len(`{"jsonrpc":"2.0","id":1,"result":{}}`)), team.BytesOut)
+
+	assert.Equal(t, int64(1), ledger.Usage(AnonymousPrincipal, now, now).ToolCalls)
+}
+
+func TestMiddlewareEnforcesQuotas(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{Quotas: []Quota{
+		{Period: PeriodDaily, MaxToolCalls: 2},
+		{Period: PeriodMonthly, Principal: "bob", MaxToolCalls: 1},
+	}}
+	accountant, err := NewAccountant(context.Background(), "fetch", config, NewLocalStore(t.TempDir()))
+	require.NoError(t, err)
+	handler := newTestHandler(t, accountant)
+
+	alice := jwt.MapClaims{"sub": "alice"}
+	bob := jwt.MapClaims{"sub": "bob"}
+
+	assert.Equal(t, http.StatusOK, serve(handler, toolCallBody, alice).Code)
+	assert.Equal(t, http.StatusOK, serve(handler, toolCallBody, alice).Code)
+	assert.Equal(t, http.StatusOK, serve(handler, toolCallBody, bob).Code)
+
+	rec := serve(handler, toolCallBody, alice)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	var response struct {
+		ID    int `json:"id"`
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 1, response.ID)
+	assert.Equal(t, http.StatusTooManyRequests, response.Error.Code)
+	assert.Contains(t, response.Error.Message, "daily tool call quota of 2 exhausted for principal alice")
+
+	rec = serve(handler, toolCallBody, bob)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), "monthly tool call quota of 1 exhausted for principal bob")
+
+	// Other methods are not subject to tool call quotas
+	assert.Equal(t, http.StatusOK, serve(handler, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`, alice).Code)
+
+	// Rejected tool calls are not accounted
+	now := time.Now()
+	assert.Equal(t, int64(2), accountant.ledger.Usage("alice", now, now).ToolCalls)
+}
+
+func TestAccountantQuotaResets(t *testing.T) {
+	t.Parallel()
+
+	config := NewConfigWithQuotas(1, 0)
+	accountant, err := NewAccountant(context.Background(), "fetch", config, NewLocalStore(t.TempDir()))
+	require.NoError(t, err)
+
+	now := time.Date(2025, 10, 1, 23, 59, 0, 0, time.UTC)
+	accountant.now = func() time.Time { return now }
+
+	accountant.Record("alice", Counters{Requests: 1, ToolCalls: 1})
+	var quotaErr *QuotaExceededError
+	require.ErrorAs(t, accountant.Check("alice"), &quotaErr)
+	assert.Equal(t, time.Date(2025, 10, 2, 0, 0, 0, 0, time.UTC), quotaErr.ResetsAt)
+
+	now = now.Add(2 * time.Minute)
+	assert.NoError(t, accountant.Check("alice"))
+}
+
+func TestNewAccountantResumesLedger(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := NewLocalStore(t.TempDir())
+	ledger := NewLedger("fetch")
+	ledger.Add(time.Now(), "alice", Counters{Requests: 1, ToolCalls: 1})
+	require.NoError(t, store.Save(ctx, ledger))
+
+	accountant, err := NewAccountant(ctx, "fetch", NewConfigWithQuotas(1, 0), store)
+	require.NoError(t, err)
+	assert.Error(t, accountant.Check("alice"))
+
+	_, err = NewAccountant(ctx, "fetch", &Config{Quotas: []Quota{{Period: "hourly", MaxToolCalls: 1}}}, store)
+	assert.Error(t, err)
+}
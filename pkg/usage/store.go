@@ -0,0 +1,285 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/adrg/xdg"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	thvk8scli "github.com/stacklok/toolhive/pkg/container/kubernetes"
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/labels"
+)
+
+const (
+	ledgerFileSuffix = ".json"
+
+	// configMapSuffix is appended to the workload name to name its usage ConfigMap
+	configMapSuffix = "-usage"
+	// configMapKey is the ConfigMap data key holding the ledger
+	configMapKey = "usage.json"
+	// LabelUsage marks the ConfigMaps holding usage ledgers
+	LabelUsage = "toolhive-usage"
+)
+
+// Store persists the usage ledgers of workloads.
+type Store interface {
+	// Load returns the ledger of the workload, or an empty ledger if none was saved
+	Load(ctx context.Context, workload string) (*Ledger, error)
+	// Save persists the ledger of a workload
+	Save(ctx context.Context, ledger *Ledger) error
+	// List returns the names of the workloads with a saved ledger
+	List(ctx context.Context) ([]string, error)
+}
+
+// NewStore creates the usage store for the current environment. In Kubernetes, ledgers
+// are kept in ConfigMaps in the current namespace, otherwise in the local state directory.
+func NewStore() (Store, error) {
+	if runtime.IsKubernetesRuntime() {
+		return NewConfigMapStore()
+	}
+	dir, err := DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewLocalStore(dir), nil
+}
+
+// DefaultDir returns the directory where ToolHive keeps usage ledgers.
+func DefaultDir() (string, error) {
+	dir, err := xdg.StateFile("toolhive/usage")
+	if err != nil {
+		return "", fmt.Errorf("failed to get usage directory path: %w", err)
+	}
+	return dir, nil
+}
+
+// LocalStore keeps one ledger file per workload in a directory.
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore creates a store keeping ledgers in dir.
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: dir}
+}
+
+func (s *LocalStore) path(workload string) string {
+	return filepath.Join(s.dir, filepath.Base(filepath.Clean(workload))+ledgerFileSuffix)
+}
+
+// Load returns the ledger of the workload, or an empty ledger if none was saved.
+func (s *LocalStore) Load(_ context.Context, workload string) (*Ledger, error) {
+	// #nosec G304 - the path is confined to the store directory
+	data, err := os.ReadFile(s.path(workload))
+	if os.IsNotExist(err) {
+		return NewLedger(workload), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage of workload %s: %w", workload, err)
+	}
+	return decodeLedger(workload, data)
+}
+
+// Save persists the ledger, replacing the file atomically so that readers never see partial data.
+func (s *LocalStore) Save(_ context.Context, ledger *Ledger) error {
+	if err := os.MkdirAll(s.dir, 0750); err != nil {
+		return fmt.Errorf("failed to create usage directory: %w", err)
+	}
+	data, err := json.Marshal(ledger)
+	if err != nil {
+		return fmt.Errorf("failed to encode usage of workload %s: %w", ledger.Workload, err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".usage-*")
+	if err != nil {
+		return fmt.Errorf("failed to create usage file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write usage file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write usage file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(ledger.Workload)); err != nil {
+		return fmt.Errorf("failed to save usage of workload %s: %w", ledger.Workload, err)
+	}
+	return nil
+}
+
+// List returns the names of the workloads with a saved ledger.
+func (s *LocalStore) List(_ context.Context) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage directory: %w", err)
+	}
+
+	var workloads []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ledgerFileSuffix) {
+			continue
+		}
+		workloads = append(workloads, strings.TrimSuffix(name, ledgerFileSuffix))
+	}
+	sort.Strings(workloads)
+	return workloads, nil
+}
+
+// ConfigMapStore keeps the ledger of each workload in a ConfigMap in a namespace.
+type ConfigMapStore struct {
+	clientset kubernetes.Interface
+	namespace string
+}
+
+// NewConfigMapStoreWithClient creates a store keeping ledgers in ConfigMaps in the namespace.
+func NewConfigMapStoreWithClient(clientset kubernetes.Interface, namespace string) *ConfigMapStore {
+	return &ConfigMapStore{clientset: clientset, namespace: namespace}
+}
+
+// NewConfigMapStore creates a store keeping ledgers in the current namespace, using in-cluster configuration.
+// Note: This function is not unit tested as it requires a real Kubernetes cluster.
+func NewConfigMapStore() (*ConfigMapStore, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-cluster config: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+
+	return NewConfigMapStoreWithClient(clientset, thvk8scli.GetCurrentNamespace()), nil
+}
+
+// Load returns the ledger of the workload, or an empty ledger if none was saved.
+func (s *ConfigMapStore) Load(ctx context.Context, workload string) (*Ledger, error) {
+	configMap, err := s.clientset.CoreV1().ConfigMaps(s.namespace).Get(ctx, workload+configMapSuffix, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return NewLedger(workload), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage ConfigMap of workload %s: %w", workload, err)
+	}
+	return decodeLedger(workload, []byte(configMap.Data[configMapKey]))
+}
+
+// Save persists the ledger, creating the ConfigMap if it does not exist.
+func (s *ConfigMapStore) Save(ctx context.Context, ledger *Ledger) error {
+	data, err := json.Marshal(ledger)
+	if err != nil {
+		return fmt.Errorf("failed to encode usage of workload %s: %w", ledger.Workload, err)
+	}
+
+	configMaps := s.clientset.CoreV1().ConfigMaps(s.namespace)
+	name := ledger.Workload + configMapSuffix
+	existing, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: s.namespace,
+				Labels: map[string]string{
+					labels.LabelToolHive: "true",
+					labels.LabelName:     ledger.Workload,
+					LabelUsage:           "true",
+				},
+			},
+			Data: map[string]string{configMapKey: string(data)},
+		}
+		if _, err := configMaps.Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create usage ConfigMap of workload %s: %w", ledger.Workload, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get usage ConfigMap of workload %s: %w", ledger.Workload, err)
+	}
+
+	if existing.Data == nil {
+		existing.Data = make(map[string]string)
+	}
+	existing.Data[configMapKey] = string(data)
+	if _, err := configMaps.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update usage ConfigMap of workload %s: %w", ledger.Workload, err)
+	}
+	return nil
+}
+
+// List returns the names of the workloads with a saved ledger.
+func (s *ConfigMapStore) List(ctx context.Context) ([]string, error) {
+	configMapList, err := s.clientset.CoreV1().ConfigMaps(s.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: LabelUsage + "=true",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage ConfigMaps: %w", err)
+	}
+
+	workloads := make([]string, 0, len(configMapList.Items))
+	for _, configMap := range configMapList.Items {
+		workloads = append(workloads, configMap.Labels[labels.LabelName])
+	}
+	sort.Strings(workloads)
+	return workloads, nil
+}
+
+func decodeLedger(workload string, data []byte) (*Ledger, error) {
+	ledger := NewLedger(workload)
+	if len(data) == 0 {
+		return ledger, nil
+	}
+	if err := json.Unmarshal(data, ledger); err != nil {
+		return nil, fmt.Errorf("failed to decode usage of workload %s: %w", workload, err)
+	}
+	if ledger.Days == nil {
+		ledger.Days = make(map[string]map[string]*Counters)
+	}
+	return ledger, nil
+}
+
+// Query returns the usage records of all workloads in the store selected by the filter.
+func Query(ctx context.Context, store Store, filter Filter) ([]Record, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	workloads := []string{filter.Workload}
+	if filter.Workload == "" {
+		var err error
+		workloads, err = store.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	records := []Record{}
+	for _, workload := range workloads {
+		ledger, err := store.Load(ctx, workload)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range ledger.Records() {
+			if filter.Matches(record) {
+				records = append(records, record)
+			}
+		}
+	}
+	sortRecords(records)
+	return records, nil
+}
@@ -0,0 +1,106 @@
+package usage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestStores(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		newStore func(t *testing.T) Store
+	}{
+		{
+			name: "local store",
+			newStore: func(t *testing.T) Store {
+				t.Helper()
+				return NewLocalStore(t.TempDir())
+			},
+		},
+		{
+			name: "ConfigMap store",
+			newStore: func(t *testing.T) Store {
+				t.Helper()
+				return NewConfigMapStoreWithClient(fake.NewSimpleClientset(), "toolhive")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			store := tt.newStore(t)
+
+			workloads, err := store.List(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, workloads)
+
+			// A workload without saved usage has an empty ledger
+			ledger, err := store.Load(ctx, "fetch")
+			require.NoError(t, err)
+			assert.Equal(t, "fetch", ledger.Workload)
+			assert.Empty(t, ledger.Days)
+
+			day := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+			ledger.Add(day, "alice", Counters{Requests: 2, ToolCalls: 1})
+			require.NoError(t, store.Save(ctx, ledger))
+
+			// Saving again updates the existing ledger
+			ledger.Add(day, "alice", Counters{Requests: 1, ToolCalls: 1})
+			require.NoError(t, store.Save(ctx, ledger))
+
+			github := NewLedger("github")
+			github.Add(day, "bob", Counters{Requests: 1})
+			require.NoError(t, store.Save(ctx, github))
+
+			loaded, err := store.Load(ctx, "fetch")
+			require.NoError(t, err)
+			assert.Equal(t, ledger, loaded)
+
+			workloads, err = store.List(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"fetch", "github"}, workloads)
+
+			records, err := Query(ctx, store, Filter{})
+			require.NoError(t, err)
+			assert.Equal(t, []Record{
+				{Date: "2025-10-01", Workload: "fetch", Principal: "alice", Counters: Counters{Requests: 3, ToolCalls: 2}},
+				{Date: "2025-10-01", Workload: "github", Principal: "bob", Counters: Counters{Requests: 1}},
+			}, records)
+
+			records, err = Query(ctx, store, Filter{Workload: "github"})
+			require.NoError(t, err)
+			require.Len(t, records, 1)
+			assert.Equal(t, "bob", records[0].Principal)
+
+			_, err = Query(ctx, store, Filter{Until: "10/01/2025"})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestConfigMapStoreLabels(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clientset := fake.NewSimpleClientset()
+	store := NewConfigMapStoreWithClient(clientset, "toolhive")
+
+	require.NoError(t, store.Save(ctx, NewLedger("fetch")))
+
+	configMap, err := clientset.CoreV1().ConfigMaps("toolhive").Get(ctx, "fetch-usage", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "true", configMap.Labels[LabelUsage])
+	assert.Equal(t, "fetch", configMap.Labels["toolhive-name"])
+	assert.JSONEq(t, `{"workload":"fetch","days":{}}`, configMap.Data[configMapKey])
+}
@@ -0,0 +1,217 @@
+// Package usage provides usage accounting and quota enforcement for MCP servers.
+// It counts requests, tool calls, bytes and time per principal per workload per day,
+// so that MCP usage can be charged back to the teams consuming it.
+package usage
+
+import (
+	"sort"
+	"time"
+)
+
+// DateFormat is the format of the UTC dates usage is accounted under
+const DateFormat = "2006-01-02"
+
+// Counters holds the usage accounted to a principal.
+type Counters struct {
+	// Requests is the number of MCP requests
+	Requests int64 `json:"requests"`
+	// ToolCalls is the number of tools/call requests
+	ToolCalls int64 `json:"tool_calls"`
+	// BytesIn is the number of request bytes received from the client
+	BytesIn int64 `json:"bytes_in"`
+	// BytesOut is the number of response bytes sent to the client
+	BytesOut int64 `json:"bytes_out"`
+	// DurationMs is the time spent handling the requests, in milliseconds
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// Add adds other to the counters.
+func (c *Counters) Add(other Counters) {
+	c.Requests += other.Requests
+	c.ToolCalls += other.ToolCalls
+	c.BytesIn += other.BytesIn
+	c.BytesOut += other.BytesOut
+	c.DurationMs += other.DurationMs
+}
+
+// Bytes returns the number of bytes transferred in both directions.
+func (c Counters) Bytes() int64 {
+	return c.BytesIn + c.BytesOut
+}
+
+// Record is the usage of a principal on a workload on a single day.
+type Record struct {
+	// Date is the UTC date of the usage (YYYY-MM-DD)
+	Date string `json:"date"`
+	// Workload is the name of the workload
+	Workload string `json:"workload"`
+	// Principal identifies the client the usage is accounted to
+	Principal string `json:"principal"`
+	Counters
+}
+
+// Ledger holds the daily usage of a workload per principal.
+type Ledger struct {
+	// Workload is the name of the workload
+	Workload string `json:"workload"`
+	// Days maps a UTC date (YYYY-MM-DD) to the usage per principal on that day
+	Days map[string]map[string]*Counters `json:"days"`
+}
+
+// NewLedger creates an empty ledger for the workload.
+func NewLedger(workload string) *Ledger {
+	return &Ledger{
+		Workload: workload,
+		Days:     make(map[string]map[string]*Counters),
+	}
+}
+
+// Add accounts counters to the principal on the day of at.
+func (l *Ledger) Add(at time.Time, principal string, counters Counters) {
+	if l.Days == nil {
+		l.Days = make(map[string]map[string]*Counters)
+	}
+	date := at.UTC().Format(DateFormat)
+	principals, ok := l.Days[date]
+	if !ok {
+		principals = make(map[string]*Counters)
+		l.Days[date] = principals
+	}
+	current, ok := principals[principal]
+	if !ok {
+		current = &Counters{}
+		principals[principal] = current
+	}
+	current.Add(counters)
+}
+
+// Usage returns the usage of the principal between the days of from and to, inclusive.
+func (l *Ledger) Usage(principal string, from, to time.Time) Counters {
+	first := from.UTC().Format(DateFormat)
+	last := to.UTC().Format(DateFormat)
+
+	var total Counters
+	for date, principals := range l.Days {
+		// Dates in DateFormat sort lexically
+		if date < first || date > last {
+			continue
+		}
+		if counters, ok := principals[principal]; ok {
+			total.Add(*counters)
+		}
+	}
+	return total
+}
+
+// Prune removes the usage of the days before the day of before.
+func (l *Ledger) Prune(before time.Time) {
+	cutoff := before.UTC().Format(DateFormat)
+	for date := range l.Days {
+		if date < cutoff {
+			delete(l.Days, date)
+		}
+	}
+}
+
+// Records returns the usage in the ledger, sorted by date and principal.
+func (l *Ledger) Records() []Record {
+	var records []Record
+	for date, principals := range l.Days {
+		for principal, counters := range principals {
+			records = append(records, Record{
+				Date:      date,
+				Workload:  l.Workload,
+				Principal: principal,
+				Counters:  *counters,
+			})
+		}
+	}
+	sortRecords(records)
+	return records
+}
+
+// Filter selects usage records.
+type Filter struct {
+	// Workload only selects the usage of this workload, if set
+	Workload string
+	// Principal only selects the usage of this principal, if set
+	Principal string
+	// Since only selects usage on or after this UTC date (YYYY-MM-DD), if set
+	Since string
+	// Until only selects usage on or before this UTC date (YYYY-MM-DD), if set
+	Until string
+}
+
+// Validate checks that the dates of the filter are valid.
+func (f Filter) Validate() error {
+	for _, date := range []string{f.Since, f.Until} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(DateFormat, date); err != nil {
+			return &InvalidDateError{Date: date}
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the record is selected by the filter.
+func (f Filter) Matches(record Record) bool {
+	if f.Workload != "" && record.Workload != f.Workload {
+		return false
+	}
+	if f.Principal != "" && record.Principal != f.Principal {
+		return false
+	}
+	if f.Since != "" && record.Date < f.Since {
+		return false
+	}
+	if f.Until != "" && record.Date > f.Until {
+		return false
+	}
+	return true
+}
+
+// InvalidDateError is returned when a filter date is not in the YYYY-MM-DD format.
+type InvalidDateError struct {
+	Date string
+}
+
+func (e *InvalidDateError) Error() string {
+	return "invalid date " + e.Date + ": expected YYYY-MM-DD"
+}
+
+// Summarize aggregates the records over their dates, returning a single record
+// per workload and principal with an empty date.
+func Summarize(records []Record) []Record {
+	type key struct{ workload, principal string }
+	totals := make(map[key]*Record)
+	for _, record := range records {
+		k := key{record.Workload, record.Principal}
+		total, ok := totals[k]
+		if !ok {
+			total = &Record{Workload: record.Workload, Principal: record.Principal}
+			totals[k] = total
+		}
+		total.Add(record.Counters)
+	}
+
+	summary := make([]Record, 0, len(totals))
+	for _, total := range totals {
+		summary = append(summary, *total)
+	}
+	sortRecords(summary)
+	return summary
+}
+
+func sortRecords(records []Record) {
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Date != records[j].Date {
+			return records[i].Date < records[j].Date
+		}
+		if records[i].Workload != records[j].Workload {
+			return records[i].Workload < records[j].Workload
+		}
+		return records[i].Principal < records[j].Principal
+	})
+}
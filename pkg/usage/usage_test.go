@@ -0,0 +1,89 @@
+package usage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedger(t *testing.T) {
+	t.Parallel()
+
+	day1 := time.Date(2025, 10, 1, 23, 30, 0, 0, time.UTC)
+	day2 := time.Date(2025, 10, 2, 8, 0, 0, 0, time.UTC)
+
+	ledger := NewLedger("fetch")
+	ledger.Add(day1, "alice", Counters{Requests: 1, ToolCalls: 1, BytesIn: 10, BytesOut: 100, DurationMs: 5})
+	ledger.Add(day1, "alice", Counters{Requests: 1, BytesIn: 20, BytesOut: 200, DurationMs: 7})
+	ledger.Add(day1, "bob", Counters{Requests: 1, ToolCalls: 1})
+	// Dates are UTC, so 1am CEST on October 3rd is accounted on October 2nd
+	ledger.Add(time.Date(2025, 10, 3, 1, 0, 0, 0, time.FixedZone("CEST", 2*60*60)), "alice",
+		Counters{Requests: 1, ToolCalls: 1})
+	ledger.Add(day2, "alice", Counters{Requests: 1, ToolCalls: 1})
+
+	assert.Equal(t,
+		Counters{Requests: 2, ToolCalls: 1, BytesIn: 30, BytesOut: 300, DurationMs: 12},
+		ledger.Usage("alice", day1, day1))
+	assert.Equal(t, int64(3), ledger.Usage("alice", day1, day2).ToolCalls)
+	assert.Equal(t, Counters{}, ledger.Usage("carol", day1, day2))
+
+	records := ledger.Records()
+	require.Len(t, records, 3)
+	assert.Equal(t, Record{Date: "2025-10-01", Workload: "fetch", Principal: "alice",
+		Counters: Counters{Requests: 2, ToolCalls: 1, BytesIn: 30, BytesOut: 300, DurationMs: 12}}, records[0])
+	assert.Equal(t, "bob", records[1].Principal)
+	assert.Equal(t, "2025-10-02", records[2].Date)
+
+	ledger.Prune(day2)
+	assert.Len(t, ledger.Days, 1)
+	assert.Contains(t, ledger.Days, "2025-10-02")
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	record := Record{Date: "2025-10-02", Workload: "fetch", Principal: "alice"}
+
+	tests := []struct {
+		name    string
+		filter  Filter
+		matches bool
+	}{
+		{name: "empty filter", filter: Filter{}, matches: true},
+		{name: "matching workload and principal", filter: Filter{Workload: "fetch", Principal: "alice"}, matches: true},
+		{name: "other workload", filter: Filter{Workload: "github"}, matches: false},
+		{name: "other principal", filter: Filter{Principal: "bob"}, matches: false},
+		{name: "inclusive range", filter: Filter{Since: "2025-10-02", Until: "2025-10-02"}, matches: true},
+		{name: "before since", filter: Filter{Since: "2025-10-03"}, matches: false},
+		{name: "after until", filter: Filter{Until: "2025-10-01"}, matches: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.matches, tt.filter.Matches(record))
+		})
+	}
+
+	var dateErr *InvalidDateError
+	require.ErrorAs(t, Filter{Since: "yesterday"}.Validate(), &dateErr)
+	assert.Equal(t, "yesterday", dateErr.Date)
+	assert.NoError(t, Filter{Since: "2025-10-01", Until: "2025-10-31"}.Validate())
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	summary := Summarize([]Record{
+		{Date: "2025-10-01", Workload: "fetch", Principal: "bob", Counters: Counters{ToolCalls: 1}},
+		{Date: "2025-10-01", Workload: "fetch", Principal: "alice", Counters: Counters{ToolCalls: 1, BytesIn: 5}},
+		{Date: "2025-10-02", Workload: "fetch", Principal: "alice", Counters: Counters{ToolCalls: 2, BytesIn: 5}},
+	})
+
+	assert.Equal(t, []Record{
+		{Workload: "fetch", Principal: "alice", Counters: Counters{ToolCalls: 3, BytesIn: 10}},
+		{Workload: "fetch", Principal: "bob", Counters: Counters{ToolCalls: 1}},
+	}, summary)
+}
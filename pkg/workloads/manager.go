@@ -11,20 +11,23 @@ import (
 	"strings"
 	"time"
 
-	"github.com/adrg/xdg"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/stacklok/toolhive/pkg/client"
 	"github.com/stacklok/toolhive/pkg/config"
 	ct "github.com/stacklok/toolhive/pkg/container"
 	rt "github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/container/sbom"
 	"github.com/stacklok/toolhive/pkg/core"
 	"github.com/stacklok/toolhive/pkg/labels"
 	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/logs"
 	"github.com/stacklok/toolhive/pkg/process"
 	"github.com/stacklok/toolhive/pkg/runner"
 	"github.com/stacklok/toolhive/pkg/secrets"
 	"github.com/stacklok/toolhive/pkg/state"
+	"github.com/stacklok/toolhive/pkg/telemetry"
 	"github.com/stacklok/toolhive/pkg/transport"
 	"github.com/stacklok/toolhive/pkg/workloads/statuses"
 	"github.com/stacklok/toolhive/pkg/workloads/types"
@@ -66,6 +69,12 @@ type Manager interface {
 	ListWorkloadsInGroup(ctx context.Context, groupName string) ([]string, error)
 	// DoesWorkloadExist checks if a workload with the given name exists.
 	DoesWorkloadExist(ctx context.Context, workloadName string) (bool, error)
+	// ListSecretConsumers returns the workloads which reference the named secret.
+	// Workloads started with a version other than currentVersion are marked as stale.
+	ListSecretConsumers(ctx context.Context, secretName string, currentVersion string) ([]SecretConsumer, error)
+	// RollingRestartWorkloads stops and restarts the specified workloads one at a time.
+	// It is implemented as an asynchronous operation which returns an errgroup.Group
+	RollingRestartWorkloads(ctx context.Context, names []string) (*errgroup.Group, error)
 }
 
 type defaultManager struct {
@@ -314,14 +323,34 @@ func (d *defaultManager) stopContainerWorkload(ctx context.Context, name string)
 }
 
 func (d *defaultManager) RunWorkload(ctx context.Context, runConfig *runner.RunConfig) error {
+	// Trace the startup of the workload, continuing the trace of a parent process if any.
+	tracingProvider, err := telemetry.NewTracingProvider(ctx, runConfig.TelemetryConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create tracing provider: %w", err)
+	}
+	defer func() {
+		if err := tracingProvider.Shutdown(context.Background()); err != nil {
+			logger.Warnf("Warning: Failed to shutdown tracing provider: %v", err)
+		}
+	}()
+	ctx, span := telemetry.StartSpan(telemetry.ExtractTraceContextEnv(ctx), tracerName, "workloads.RunWorkload",
+		attribute.String("workload.name", runConfig.BaseName),
+		attribute.String("workload.image", runConfig.Image),
+		attribute.String("workload.transport", runConfig.Transport.String()),
+	)
+	startup := newStartupStatusManager(d.statuses, span)
+
 	// Ensure that the workload has a status entry before starting the process.
 	if err := d.statuses.SetWorkloadStatus(ctx, runConfig.BaseName, rt.WorkloadStatusStarting, ""); err != nil {
 		// Failure to create the initial state is a fatal error.
-		return fmt.Errorf("failed to create workload status: %v", err)
+		err = fmt.Errorf("failed to create workload status: %v", err)
+		startup.end(err)
+		return err
 	}
 
-	mcpRunner := runner.NewRunner(runConfig, d.statuses)
-	err := mcpRunner.Run(ctx)
+	mcpRunner := runner.NewRunner(runConfig, startup)
+	err = mcpRunner.Run(ctx)
+	startup.end(err)
 	if err != nil {
 		// If the run failed, we should set the status to error.
 		if statusErr := d.statuses.SetWorkloadStatus(ctx, runConfig.BaseName, rt.WorkloadStatusError, err.Error()); statusErr != nil {
@@ -354,7 +383,15 @@ func (d *defaultManager) validateSecretParameters(ctx context.Context, runConfig
 	return nil
 }
 
-func (d *defaultManager) RunWorkloadDetached(ctx context.Context, runConfig *runner.RunConfig) error {
+func (d *defaultManager) RunWorkloadDetached(ctx context.Context, runConfig *runner.RunConfig) (retErr error) {
+	ctx, span := telemetry.StartSpan(ctx, tracerName, "workloads.RunWorkloadDetached",
+		attribute.String("workload.name", runConfig.BaseName),
+		attribute.String("workload.image", runConfig.Image),
+	)
+	defer func() {
+		telemetry.EndSpan(span, retErr)
+	}()
+
 	// before running, validate the parameters for the workload
 	err := d.validateSecretParameters(ctx, runConfig)
 	if err != nil {
@@ -368,10 +405,18 @@ func (d *defaultManager) RunWorkloadDetached(ctx context.Context, runConfig *run
 	}
 
 	// Create a log file for the detached process
-	logFilePath, err := xdg.DataFile(fmt.Sprintf("toolhive/logs/%s.log", runConfig.BaseName))
+	logStore, err := logs.NewDefaultStore(d.configProvider.GetConfig().LogRetention)
 	if err != nil {
 		return fmt.Errorf("failed to create log file path: %v", err)
 	}
+	logFilePath := logStore.Path(runConfig.BaseName, logs.SourceProxy)
+	if err := os.MkdirAll(logStore.Dir(), 0750); err != nil {
+		return fmt.Errorf("failed to create log directory: %v", err)
+	}
+	// The detached process writes to the file directly, so rotation happens on (re)start
+	if err := logs.RotateIfNeeded(logFilePath, logStore.Policy()); err != nil {
+		logger.Warnf("Warning: Failed to rotate log file: %v", err)
+	}
 	// #nosec G304 - This is safe as baseName is generated by the application
 	logFile, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
 	if err != nil {
@@ -391,6 +436,8 @@ func (d *defaultManager) RunWorkloadDetached(ctx context.Context, runConfig *run
 
 	// Set environment variables for the detached process
 	detachedCmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", process.ToolHiveDetachedEnv, process.ToolHiveDetachedValue))
+	// The detached process continues the trace of the workload startup
+	detachedCmd.Env = telemetry.InjectTraceContextEnv(ctx, detachedCmd.Env)
 
 	// If we need the decrypt password, set it as an environment variable in the detached process.
 	// NOTE: This breaks the abstraction slightly since this is only relevant for the CLI, but there
@@ -640,6 +687,11 @@ func (d *defaultManager) cleanupWorkloadResources(ctx context.Context, name, bas
 		logger.Infof("Client configurations for %s removed", name)
 	}
 
+	// Remove the SBOM of the image of the workload
+	if err := sbom.Delete(ctx, baseName); err != nil {
+		logger.Warnf("Warning: Failed to delete SBOM: %v", err)
+	}
+
 	// Delete the saved state last (skip for auxiliary workloads that don't have run configs)
 	if !isAuxiliary {
 		if err := state.DeleteSavedRunConfig(ctx, baseName); err != nil {
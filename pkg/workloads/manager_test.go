@@ -1321,6 +1321,8 @@ func TestDefaultManager_updateSingleWorkload(t *testing.T) {
 			mockRuntime := runtimeMocks.NewMockRuntime(ctrl)
 			mockStatusManager := statusMocks.NewMockStatusManager(ctrl)
 			mockConfigProvider := configMocks.NewMockProvider(ctrl)
+			// RunWorkloadDetached reads the log retention policy from the config
+			mockConfigProvider.EXPECT().GetConfig().Return(&config.Config{}).AnyTimes()
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(mockRuntime, mockStatusManager)
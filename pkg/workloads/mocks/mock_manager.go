@@ -15,6 +15,7 @@ import (
 
 	core "github.com/stacklok/toolhive/pkg/core"
 	runner "github.com/stacklok/toolhive/pkg/runner"
+	workloads "github.com/stacklok/toolhive/pkg/workloads"
 	gomock "go.uber.org/mock/gomock"
 	errgroup "golang.org/x/sync/errgroup"
 )
@@ -103,6 +104,21 @@ func (mr *MockManagerMockRecorder) GetWorkload(ctx, workloadName any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkload", reflect.TypeOf((*MockManager)(nil).GetWorkload), ctx, workloadName)
 }
 
+// ListSecretConsumers mocks base method.
+func (m *MockManager) ListSecretConsumers(ctx context.Context, secretName, currentVersion string) ([]workloads.SecretConsumer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSecretConsumers", ctx, secretName, currentVersion)
+	ret0, _ := ret[0].([]workloads.SecretConsumer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSecretConsumers indicates an expected call of ListSecretConsumers.
+func (mr *MockManagerMockRecorder) ListSecretConsumers(ctx, secretName, currentVersion any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecretConsumers", reflect.TypeOf((*MockManager)(nil).ListSecretConsumers), ctx, secretName, currentVersion)
+}
+
 // ListWorkloads mocks base method.
 func (m *MockManager) ListWorkloads(ctx context.Context, listAll bool, labelFilters ...string) ([]core.Workload, error) {
 	m.ctrl.T.Helper()
@@ -167,6 +183,21 @@ func (mr *MockManagerMockRecorder) RestartWorkloads(ctx, names, foreground any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestartWorkloads", reflect.TypeOf((*MockManager)(nil).RestartWorkloads), ctx, names, foreground)
 }
 
+// RollingRestartWorkloads mocks base method.
+func (m *MockManager) RollingRestartWorkloads(ctx context.Context, names []string) (*errgroup.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollingRestartWorkloads", ctx, names)
+	ret0, _ := ret[0].(*errgroup.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollingRestartWorkloads indicates an expected call of RollingRestartWorkloads.
+func (mr *MockManagerMockRecorder) RollingRestartWorkloads(ctx, names any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollingRestartWorkloads", reflect.TypeOf((*MockManager)(nil).RollingRestartWorkloads), ctx, names)
+}
+
 // RunWorkload mocks base method.
 func (m *MockManager) RunWorkload(ctx context.Context, runConfig *runner.RunConfig) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,118 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/logger"
+	"github.com/stacklok/toolhive/pkg/runner"
+	"github.com/stacklok/toolhive/pkg/secrets"
+	"github.com/stacklok/toolhive/pkg/state"
+	"github.com/stacklok/toolhive/pkg/workloads/types"
+)
+
+// SecretConsumer is a workload which consumes a secret.
+type SecretConsumer struct {
+	// Workload is the name of the workload
+	Workload string `json:"workload"`
+	// Target is the environment variable or file the secret is passed as
+	Target string `json:"target"`
+	// Status is the current status of the workload
+	Status runtime.WorkloadStatus `json:"status"`
+	// Version is the version of the secret the workload was last started with, if known
+	Version string `json:"version,omitempty"`
+	// Stale indicates that the workload was started with a previous version of the secret
+	Stale bool `json:"stale"`
+}
+
+// IsRunning returns true if the workload is running, and so uses the value the secret
+// had when the workload was started until it is restarted.
+func (c SecretConsumer) IsRunning() bool {
+	return c.Status == runtime.WorkloadStatusRunning || c.Status == runtime.WorkloadStatusUnhealthy
+}
+
+// ListSecretConsumers returns the workloads whose saved run configuration references the named secret.
+// currentVersion is the current version of the secret, or empty if the provider does not version
+// its secrets. Workloads started with another version are marked as stale.
+func (d *defaultManager) ListSecretConsumers(
+	ctx context.Context,
+	secretName string,
+	currentVersion string,
+) ([]SecretConsumer, error) {
+	store, err := state.NewRunConfigStore(state.DefaultAppName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state store: %w", err)
+	}
+
+	configNames, err := store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list configurations: %w", err)
+	}
+
+	var consumers []SecretConsumer
+	for _, name := range configNames {
+		runConfig, err := runner.LoadState(ctx, name)
+		if err != nil {
+			logger.Warnf("failed to load state for %s: %v", name, err)
+			continue
+		}
+
+		for _, secret := range runConfig.Secrets {
+			param, err := secrets.ParseSecretParameter(secret)
+			if err != nil || param.Name != secretName {
+				continue
+			}
+
+			consumer := SecretConsumer{
+				Workload: name,
+				Target:   param.Target,
+				Status:   runtime.WorkloadStatusUnknown,
+				Version:  runConfig.SecretVersions[secretName],
+			}
+			consumer.Stale = consumer.Version != "" && currentVersion != "" && consumer.Version != currentVersion
+			if workload, err := d.statuses.GetWorkload(ctx, name); err == nil {
+				consumer.Status = workload.Status
+			}
+			consumers = append(consumers, consumer)
+		}
+	}
+
+	sort.Slice(consumers, func(i, j int) bool {
+		if consumers[i].Workload != consumers[j].Workload {
+			return consumers[i].Workload < consumers[j].Workload
+		}
+		return consumers[i].Target < consumers[j].Target
+	})
+	return consumers, nil
+}
+
+// RollingRestartWorkloads restarts the specified running workloads one at a time, so that they
+// pick up the current values of their secrets. Restarting stops at the first workload which
+// fails to restart, leaving the remaining workloads running.
+func (d *defaultManager) RollingRestartWorkloads(_ context.Context, names []string) (*errgroup.Group, error) {
+	for _, name := range names {
+		if err := types.ValidateWorkloadName(name); err != nil {
+			return nil, fmt.Errorf("invalid workload name '%s': %w", name, err)
+		}
+	}
+
+	group := &errgroup.Group{}
+	group.Go(func() error {
+		for _, name := range names {
+			logger.Infof("Restarting workload %s", name)
+			if err := d.stopSingleWorkload(name); err != nil {
+				return fmt.Errorf("failed to stop workload %s: %w", name, err)
+			}
+			if err := d.restartSingleWorkload(name, false); err != nil {
+				return fmt.Errorf("failed to restart workload %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+
+	return group, nil
+}
@@ -0,0 +1,81 @@
+package workloads
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adrg/xdg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	rt "github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/core"
+	"github.com/stacklok/toolhive/pkg/runner"
+	statusMocks "github.com/stacklok/toolhive/pkg/workloads/statuses/mocks"
+)
+
+func TestListSecretConsumers(t *testing.T) {
+	// The state store is located using the XDG environment variables
+	t.Cleanup(xdg.Reload)
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	xdg.Reload()
+
+	ctx := context.Background()
+	for _, config := range []*runner.RunConfig{
+		{
+			BaseName:       "github",
+			Secrets:        []string{"github-token,target=GITHUB_TOKEN", "other,target=OTHER"},
+			SecretVersions: map[string]string{"github-token": "1"},
+		},
+		{
+			BaseName:       "github-readonly",
+			Secrets:        []string{"github-token,target=GITHUB_PERSONAL_ACCESS_TOKEN"},
+			SecretVersions: map[string]string{"github-token": "2"},
+		},
+		{
+			BaseName: "fetch",
+		},
+		{
+			BaseName: "gitlab",
+			Secrets:  []string{"github-token,target=UNUSED"},
+		},
+	} {
+		require.NoError(t, config.SaveState(ctx))
+	}
+
+	ctrl := gomock.NewController(t)
+	mockStatusManager := statusMocks.NewMockStatusManager(ctrl)
+	mockStatusManager.EXPECT().GetWorkload(gomock.Any(), "github").
+		Return(core.Workload{Name: "github", Status: rt.WorkloadStatusRunning}, nil)
+	mockStatusManager.EXPECT().GetWorkload(gomock.Any(), "github-readonly").
+		Return(core.Workload{Name: "github-readonly", Status: rt.WorkloadStatusStopped}, nil)
+	mockStatusManager.EXPECT().GetWorkload(gomock.Any(), "gitlab").
+		Return(core.Workload{}, rt.ErrWorkloadNotFound)
+
+	manager := &defaultManager{statuses: mockStatusManager}
+	consumers, err := manager.ListSecretConsumers(ctx, "github-token", "2")
+	require.NoError(t, err)
+
+	assert.Equal(t, []SecretConsumer{
+		{Workload: "github", Target: "GITHUB_TOKEN", Status: rt.WorkloadStatusRunning, Version: "1", Stale: true},
+		{Workload: "github-readonly", Target: "GITHUB_PERSONAL_ACCESS_TOKEN", Status: rt.WorkloadStatusStopped, Version: "2"},
+		{Workload: "gitlab", Target: "UNUSED", Status: rt.WorkloadStatusUnknown},
+	}, consumers)
+	assert.True(t, consumers[0].IsRunning())
+	assert.False(t, consumers[1].IsRunning())
+
+	// Secrets which are not used by any workload have no consumers
+	consumers, err = manager.ListSecretConsumers(ctx, "unused", "")
+	require.NoError(t, err)
+	assert.Empty(t, consumers)
+}
+
+func TestRollingRestartWorkloadsInvalidName(t *testing.T) {
+	t.Parallel()
+
+	manager := &defaultManager{}
+	_, err := manager.RollingRestartWorkloads(context.Background(), []string{"github", "../etc"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid workload name '../etc'")
+}
@@ -0,0 +1,46 @@
+package workloads
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+
+	rt "github.com/stacklok/toolhive/pkg/container/runtime"
+	"github.com/stacklok/toolhive/pkg/telemetry"
+	"github.com/stacklok/toolhive/pkg/workloads/statuses"
+)
+
+// tracerName is the name of the tracer of workload lifecycle operations.
+const tracerName = "github.com/stacklok/toolhive/pkg/workloads"
+
+// startupStatusManager ends the span of a workload startup once the runner reports
+// the workload as running. The runner keeps running until the workload stops, so
+// the span would otherwise cover the whole lifetime of the workload.
+type startupStatusManager struct {
+	statuses.StatusManager
+	span trace.Span
+	once sync.Once
+}
+
+func newStartupStatusManager(statusManager statuses.StatusManager, span trace.Span) *startupStatusManager {
+	return &startupStatusManager{StatusManager: statusManager, span: span}
+}
+
+// SetWorkloadStatus sets the status of the workload, and ends the startup span when it is running.
+func (s *startupStatusManager) SetWorkloadStatus(
+	ctx context.Context, workloadName string, status rt.WorkloadStatus, contextMsg string,
+) error {
+	err := s.StatusManager.SetWorkloadStatus(ctx, workloadName, status, contextMsg)
+	if status == rt.WorkloadStatusRunning {
+		s.end(err)
+	}
+	return err
+}
+
+// end ends the startup span, if it has not ended yet.
+func (s *startupStatusManager) end(err error) {
+	s.once.Do(func() {
+		telemetry.EndSpan(s.span, err)
+	})
+}
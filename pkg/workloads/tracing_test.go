@@ -0,0 +1,47 @@
+package workloads
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/mock/gomock"
+
+	"github.com/stacklok/toolhive/pkg/container/runtime"
+	statusMocks "github.com/stacklok/toolhive/pkg/workloads/statuses/mocks"
+)
+
+func TestStartupStatusManager(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	mockStatusManager := statusMocks.NewMockStatusManager(ctrl)
+	mockStatusManager.EXPECT().SetWorkloadStatus(ctx, "fetch", runtime.WorkloadStatusStarting, "").Return(nil)
+	mockStatusManager.EXPECT().SetWorkloadStatus(ctx, "fetch", runtime.WorkloadStatusRunning, "").Return(nil)
+	mockStatusManager.EXPECT().SetWorkloadPID(ctx, "fetch", 42).Return(nil)
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+	_, span := tracer.Start(ctx, "workloads.RunWorkload")
+	startup := newStartupStatusManager(mockStatusManager, span)
+
+	// Other status manager calls are delegated without ending the span
+	require.NoError(t, startup.SetWorkloadPID(ctx, "fetch", 42))
+	require.NoError(t, startup.SetWorkloadStatus(ctx, "fetch", runtime.WorkloadStatusStarting, ""))
+	assert.Empty(t, recorder.Ended())
+
+	require.NoError(t, startup.SetWorkloadStatus(ctx, "fetch", runtime.WorkloadStatusRunning, ""))
+	require.Len(t, recorder.Ended(), 1)
+	assert.Equal(t, codes.Ok, recorder.Ended()[0].Status().Code)
+
+	// The span only ends once, errors after the startup are not recorded
+	startup.end(errors.New("transport stopped"))
+	require.Len(t, recorder.Ended(), 1)
+	assert.Equal(t, codes.Ok, recorder.Ended()[0].Status().Code)
+}